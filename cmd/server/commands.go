@@ -0,0 +1,267 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+
+	"github.com/space/backend/internal/config"
+	"github.com/space/backend/internal/database"
+	"gorm.io/gorm"
+)
+
+// usage выводит справку по флагам и подкомандам
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: %s [--config config.yaml] [command]\n\n", os.Args[0])
+	fmt.Fprintln(out, "Without a command the API server is started.")
+	fmt.Fprintln(out, "\nCommands:")
+	fmt.Fprintln(out, "  migrate up           apply all pending migrations")
+	fmt.Fprintln(out, "  migrate down [N]     roll back N migrations (default 1)")
+	fmt.Fprintln(out, "  migrate version      print current schema version")
+	fmt.Fprintln(out, "  migrate force V      set schema version without running migrations")
+	fmt.Fprintln(out, "  seed                 fill an empty database with sample data (not in production)")
+	fmt.Fprintln(out, "  export --out FILE    dump rooms, equipment, instructions, users and bookings to JSON")
+	fmt.Fprintln(out, "  import --in FILE     load a JSON dump into an empty database")
+	fmt.Fprintln(out, "\nFlags:")
+	flag.PrintDefaults()
+}
+
+// runCommand выполняет подкоманду и возвращает код завершения
+func runCommand(cfg *config.Config, args []string) int {
+	switch args[0] {
+	case "migrate":
+		return runMigrate(cfg, args[1:])
+	case "seed":
+		return runSeed(cfg)
+	case "export":
+		return runExport(cfg, args[1:])
+	case "import":
+		return runImport(cfg, args[1:])
+	default:
+		slog.Error("unknown command", "command", args[0])
+		usage()
+		return 2
+	}
+}
+
+// runMigrate управляет версионированными миграциями
+func runMigrate(cfg *config.Config, args []string) int {
+	if len(args) == 0 {
+		usage()
+		return 2
+	}
+
+	// SQL-миграции написаны под PostgreSQL; схема SQLite создаётся при старте из моделей
+	if database.IsSQLite(cfg.DatabaseURL) {
+		slog.Error("migrate is not supported for SQLite, the schema is created automatically on startup")
+		return 1
+	}
+
+	migrator, err := database.NewMigrator(cfg.DatabaseURL)
+	if err != nil {
+		slog.Error("failed to init migrator", "error", err)
+		return 1
+	}
+	defer migrator.Close()
+
+	switch args[0] {
+	case "up":
+		err = migrator.Up()
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil {
+				slog.Error("invalid number of steps", "value", args[1])
+				return 2
+			}
+		}
+		err = migrator.Down(steps)
+	case "force":
+		if len(args) < 2 {
+			usage()
+			return 2
+		}
+		version, convErr := strconv.Atoi(args[1])
+		if convErr != nil {
+			slog.Error("invalid version", "value", args[1])
+			return 2
+		}
+		err = migrator.Force(version)
+	case "version":
+		// Ошибка обрабатывается ниже вместе с остальными подкомандами
+	default:
+		usage()
+		return 2
+	}
+	if err != nil {
+		slog.Error("migrate failed", "command", args[0], "error", err)
+		return 1
+	}
+
+	version, dirty, err := migrator.Version()
+	if err != nil {
+		slog.Error("failed to read schema version", "error", err)
+		return 1
+	}
+	latest, err := database.LatestSchemaVersion()
+	if err != nil {
+		slog.Error("failed to read migrations", "error", err)
+		return 1
+	}
+	slog.Info("schema version", "version", version, "latest", latest, "dirty", dirty)
+	return 0
+}
+
+// runSeed заполняет базу демонстрационными данными
+func runSeed(cfg *config.Config) int {
+	if cfg.Environment == "production" {
+		slog.Error("seed is disabled in production")
+		return 1
+	}
+
+	// Схема должна быть актуальной до вставки данных
+	db, code := connectForCommand(cfg)
+	if db == nil {
+		return code
+	}
+	defer database.Close(db)
+
+	if err := database.Seed(db); err != nil {
+		if errors.Is(err, database.ErrAlreadySeeded) {
+			slog.Warn(err.Error())
+			return 0
+		}
+		slog.Error("failed to seed database", "error", err)
+		return 1
+	}
+	return 0
+}
+
+// runExport выгружает данные в переносимый JSON (перенос между проектами Supabase или в self-hosted PostgreSQL)
+func runExport(cfg *config.Config, args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	outPath := fs.String("out", "", "output file")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *outPath == "" {
+		usage()
+		return 2
+	}
+
+	db, code := connectForCommand(cfg)
+	if db == nil {
+		return code
+	}
+	defer database.Close(db)
+
+	out, err := os.Create(*outPath)
+	if err != nil {
+		slog.Error("failed to create output file", "error", err)
+		return 1
+	}
+
+	if err := database.Export(db, out); err != nil {
+		out.Close()
+		slog.Error("failed to export database", "error", err)
+		return 1
+	}
+	if err := out.Close(); err != nil {
+		slog.Error("failed to write output file", "error", err)
+		return 1
+	}
+	slog.Info("export written", "file", *outPath)
+	return 0
+}
+
+// runImport загружает JSON-выгрузку в пустую базу
+func runImport(cfg *config.Config, args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	inPath := fs.String("in", "", "input file")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *inPath == "" {
+		usage()
+		return 2
+	}
+
+	in, err := os.Open(*inPath)
+	if err != nil {
+		slog.Error("failed to open input file", "error", err)
+		return 1
+	}
+	defer in.Close()
+
+	db, code := connectForCommand(cfg)
+	if db == nil {
+		return code
+	}
+	defer database.Close(db)
+
+	if err := database.Import(db, in); err != nil {
+		slog.Error("failed to import database", "error", err)
+		return 1
+	}
+	return 0
+}
+
+// connectForCommand подключается к БД для служебной подкоманды и проверяет схему
+// При ошибке возвращает nil и код завершения
+func connectForCommand(cfg *config.Config) (*gorm.DB, int) {
+	opts := connectOptions(cfg)
+	opts.Debug = false
+	// Выгрузка и восстановление читают и пишут таблицы целиком - таймаут запросов сервера к ним не относится
+	opts.StatementTimeout = 0
+	db, err := database.Connect(cfg.DatabaseURL, opts)
+	if err != nil {
+		slog.Error("failed to connect to database", "error", err)
+		return nil, 1
+	}
+
+	if err := ensureSchema(cfg, db); err != nil {
+		database.Close(db)
+		slog.Error("database schema check failed", "error", err)
+		return nil, 1
+	}
+	return db, 0
+}
+
+// connectOptions собирает параметры подключения к БД из конфигурации
+func connectOptions(cfg *config.Config) database.ConnectOptions {
+	return database.ConnectOptions{
+		Debug:   cfg.Environment == "development",
+		MaxWait: cfg.DBConnectMaxWait,
+		Backoff: cfg.DBConnectBackoff,
+
+		SlowQueryThreshold: cfg.DBSlowQueryThreshold,
+		ReplicaURL:         cfg.DatabaseReplicaURL,
+		StatementTimeout:   cfg.DBStatementTimeout,
+
+		PrepareStmt:            cfg.DBPrepareStmt,
+		SkipDefaultTransaction: cfg.DBSkipDefaultTransaction,
+	}
+}
+
+// ensureSchema применяет миграции в development или проверяет версию схемы в остальных окружениях
+// Для SQLite схема создаётся из моделей
+func ensureSchema(cfg *config.Config, db *gorm.DB) error {
+	if database.IsSQLite(cfg.DatabaseURL) {
+		return database.MigrateSQLite(db)
+	}
+
+	migrator, err := database.NewMigrator(cfg.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	if cfg.Environment == "development" {
+		return migrator.Up()
+	}
+	return migrator.Check()
+}
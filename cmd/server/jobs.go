@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/space/backend/internal/config"
+	"github.com/space/backend/internal/scheduler"
+	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/telegram"
+)
+
+// retentionJobInterval - период задач очистки по сроку хранения
+const retentionJobInterval = 24 * time.Hour
+
+// reminderJobInterval - период проверки бронирований, о которых пора напомнить
+const reminderJobInterval = time.Minute
+
+// bookingCompletionJobInterval - период завершения закончившихся бронирований (событие completed)
+const bookingCompletionJobInterval = time.Minute
+
+// doorAccessJobInterval - период выдачи и отзыва доступа к дверям
+const doorAccessJobInterval = time.Minute
+
+// roomStateJobInterval - период публикации состояния комнат в MQTT (начало и конец встреч)
+const roomStateJobInterval = time.Minute
+
+// registerJobs регистрирует периодические фоновые задачи
+// Очистка по сроку хранения с нулевым сроком выключена и не регистрируется
+func registerJobs(sched *scheduler.Scheduler, cfg *config.Config, auditService *service.AuditService, purgeService *service.PurgeService, retentionService *service.RetentionService, abuseService *service.AbuseService, bookingService *service.BookingService, doorAccessService *service.DoorAccessService, roomStateService *service.RoomStateService, cleaningService *service.CleaningService) {
+	sched.Register(scheduler.Job{
+		Name:     "membership_cache_cleanup",
+		Interval: cfg.MembershipCacheCleanupInterval,
+		Run: func(ctx context.Context) error {
+			telegram.GlobalCache.Clear()
+			return nil
+		},
+	})
+
+	if cfg.AuditRetentionDays > 0 {
+		sched.Register(scheduler.Job{
+			Name:     "audit_retention",
+			Interval: retentionJobInterval,
+			Jitter:   time.Hour,
+			Run: func(ctx context.Context) error {
+				_, err := auditService.PurgeExpired(ctx)
+				return err
+			},
+		})
+	}
+
+	if cfg.SoftDeleteRetentionDays > 0 {
+		sched.Register(scheduler.Job{
+			Name:     "soft_delete_purge",
+			Interval: retentionJobInterval,
+			Jitter:   time.Hour,
+			Run:      purgeService.RunScheduled,
+		})
+	}
+
+	// Правила хранения задаются администраторами в БД; без правил задача ничего не делает
+	sched.Register(scheduler.Job{
+		Name:     "retention_rules",
+		Interval: retentionJobInterval,
+		Jitter:   time.Hour,
+		Run:      retentionService.RunScheduled,
+	})
+
+	if cfg.AbuseDetectionInterval > 0 {
+		sched.Register(scheduler.Job{
+			Name:     "abuse_detection",
+			Interval: cfg.AbuseDetectionInterval,
+			Run:      abuseService.RunScheduled,
+		})
+	}
+
+	if cfg.BookingReminderLead > 0 {
+		sched.Register(scheduler.Job{
+			Name:     "booking_reminders",
+			Interval: reminderJobInterval,
+			Run: func(ctx context.Context) error {
+				_, err := bookingService.SendReminders(ctx, cfg.BookingReminderLead)
+				return err
+			},
+		})
+	}
+
+	// Закончившиеся бронирования переходят в completed; переход записывается в поток событий бронирований
+	sched.Register(scheduler.Job{
+		Name:     "booking_completion",
+		Interval: bookingCompletionJobInterval,
+		Run:      bookingService.RunScheduled,
+	})
+
+	// Без комнат с reset_required задача ничего не делает
+	if cfg.CleaningJobInterval > 0 {
+		sched.Register(scheduler.Job{
+			Name:     "cleaning_tasks",
+			Interval: cfg.CleaningJobInterval,
+			Run:      cleaningService.RunScheduled,
+		})
+	}
+
+	if cfg.DoorAccessDriver != "" {
+		sched.Register(scheduler.Job{
+			Name:     "door_access",
+			Interval: doorAccessJobInterval,
+			Run:      doorAccessService.RunScheduled,
+		})
+	}
+
+	if roomStateService != nil {
+		sched.Register(scheduler.Job{
+			Name:     "room_state",
+			Interval: roomStateJobInterval,
+			Run:      roomStateService.RunScheduled,
+		})
+	}
+}
@@ -1,43 +1,142 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 	"time"
+	_ "time/tzdata" // База часовых поясов для OFFICE_TIMEZONE и ?tz=: в образе alpine её нет
 
+	"github.com/space/backend/internal/access"
+	"github.com/space/backend/internal/buildinfo"
 	"github.com/space/backend/internal/config"
 	"github.com/space/backend/internal/database"
+	"github.com/space/backend/internal/logger"
 	"github.com/space/backend/internal/repository"
 	"github.com/space/backend/internal/router"
+	"github.com/space/backend/internal/scheduler"
 	"github.com/space/backend/internal/service"
-	"github.com/space/backend/pkg/telegram"
+	"github.com/space/backend/internal/workerpool"
+	"github.com/space/backend/pkg/captcha"
+	"github.com/space/backend/pkg/contentfilter"
+	"github.com/space/backend/pkg/holidays"
+	"github.com/space/backend/pkg/mail"
+	"github.com/space/backend/pkg/mqtt"
+	"github.com/space/backend/pkg/oidc"
 )
 
+const (
+	// httpShutdownTimeout - сколько ждать завершения обрабатываемых HTTP-запросов при остановке
+	httpShutdownTimeout = 30 * time.Second
+	// redirectReadHeaderTimeout ограничивает чтение заголовков на листенере редиректа
+	redirectReadHeaderTimeout = 10 * time.Second
+	// schedulerStopTimeout - сколько ждать завершения фоновых задач при остановке
+	schedulerStopTimeout = 30 * time.Second
+	// outboundDrainTimeout - сколько ждать отправки поставленных в очередь исходящих вызовов
+	outboundDrainTimeout = 15 * time.Second
+	// oidcTimeout ограничивает запросы к провайдеру OIDC (discovery, ключи, обмен кода)
+	oidcTimeout = 10 * time.Second
+	// holidayAPITimeout ограничивает запрос государственных праздников при импорте
+	holidayAPITimeout = 15 * time.Second
+	// captchaTimeout ограничивает проверку токена капчи формы заявок на аренду
+	captchaTimeout = 10 * time.Second
+	// smtpTimeout ограничивает отправку одного письма
+	smtpTimeout = 30 * time.Second
+)
+
+// @title Space Backend API
+// @version dev
+// @description Бронирование комнат коворкинга: Telegram Mini App, бот и интеграции. Ошибки по RFC 7807 - с заголовком Accept: application/problem+json
+// @BasePath /
+// @securityDefinitions.apikey TelegramInitData
+// @in header
+// @name X-Telegram-Init-Data
+// @description initData Telegram Mini App или данные Login Widget
+// @securityDefinitions.apikey BotToken
+// @in header
+// @name X-Bot-Token
+// @securityDefinitions.apikey APIKey
+// @in header
+// @name X-API-Key
 func main() {
+	configPath := flag.String("config", "", "path to YAML config file (env vars take precedence)")
+	flag.Usage = usage
+	flag.Parse()
+
 	// Загружаем конфигурацию
-	cfg, err := config.Load()
+	cfg, err := config.Load(*configPath)
 	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+		// Выводим все проблемы конфигурации сразу, а не по одной за запуск
+		var validationErr *config.ValidationError
+		if errors.As(err, &validationErr) {
+			for _, problem := range validationErr.Problems {
+				slog.Error("invalid config", "problem", problem)
+			}
+		}
+		slog.Error("failed to load config", "error", err)
+		os.Exit(1)
 	}
 
-	log.Printf("Starting Space Backend API in %s mode...", cfg.Environment)
+	// Настраиваем структурированный логгер (используется и как slog.Default)
+	// Версия в каждой записи позволяет сопоставить логи с деплоем
+	appLogger := logger.New(cfg.Environment, cfg.LogFormat, cfg.LogLevel).With("version", buildinfo.Version)
+	slog.SetDefault(appLogger)
+
+	// Подкоманды (migrate ...) выполняются вместо запуска сервера
+	if args := flag.Args(); len(args) > 0 {
+		os.Exit(runCommand(cfg, args))
+	}
+
+	build := buildinfo.Get()
+	appLogger.Info("starting Space Backend API",
+		"environment", cfg.Environment,
+		"commit", build.Commit,
+		"build_date", build.BuildDate,
+		"go_version", build.GoVersion,
+	)
+	appLogger.Info("effective configuration", "config", cfg) // секреты скрыты в Config.LogValue
 
-	// Запускаем фоновую очистку кэша членства в группе
-	telegram.GlobalCache.StartCleanupRoutine(12 * time.Hour)
-	log.Println("Membership cache cleanup routine started")
+	// Перезагружаемая конфигурация: SIGHUP или POST /api/admin/config/reload
+	liveConfig := config.NewLive(cfg, *configPath)
 
 	// Подключаемся к базе данных
-	debugMode := cfg.Environment == "development"
-	db, err := database.Connect(cfg.DatabaseURL, debugMode)
+	db, err := database.Connect(cfg.DatabaseURL, connectOptions(cfg))
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		appLogger.Error("failed to connect to database", "error", err)
+		os.Exit(1)
 	}
 
-	// Запускаем миграции
-	if err := database.Migrate(db); err != nil {
-		log.Fatalf("Failed to run migrations: %v", err)
+	// В development миграции применяются автоматически, в остальных окружениях
+	// сервер только проверяет версию схемы (миграции - через `migrate up` при деплое)
+	if err := ensureSchema(cfg, db); err != nil {
+		appLogger.Error("database schema check failed", "error", err)
+		os.Exit(1)
+	}
+
+	// Пакет начальных данных: новая установка настраивается из файла, повторный старт ничего не дублирует
+	if cfg.DataPackFile != "" {
+		pack, err := database.LoadDataPack(cfg.DataPackFile)
+		if err != nil {
+			appLogger.Error("failed to load data pack", "error", err)
+			os.Exit(1)
+		}
+		result, err := database.ApplyDataPack(db, pack)
+		if err != nil {
+			appLogger.Error("failed to apply data pack", "path", cfg.DataPackFile, "error", err)
+			os.Exit(1)
+		}
+		appLogger.Info("data pack applied",
+			"path", cfg.DataPackFile,
+			"rooms_created", result.Rooms,
+			"equipment_created", result.Equipment,
+			"instructions_created", result.Instructions,
+		)
 	}
 
 	// Инициализируем репозитории
@@ -47,61 +146,350 @@ func main() {
 	equipmentRepo := repository.NewEquipmentRepository(db)
 	instructionRepo := repository.NewInstructionRepository(db)
 	notificationRepo := repository.NewNotificationRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	purgeRepo := repository.NewPurgeRepository(db)
+	retentionRepo := repository.NewRetentionRepository(db)
+	abuseRepo := repository.NewAbuseRepository(db)
+	billingRepo := repository.NewBillingRepository(db)
+	slackTargetRepo := repository.NewSlackTargetRepository(db)
+	restHookRepo := repository.NewRESTHookRepository(db)
+	doorAccessRepo := repository.NewDoorAccessRepository(db)
+	teamRepo := repository.NewTeamRepository(db)
+	holidayRepo := repository.NewHolidayRepository(db)
+	feedbackRepo := repository.NewFeedbackRepository(db)
+	checkInRepo := repository.NewCheckInRepository(db)
+	cleaningRepo := repository.NewCleaningRepository(db)
+	bookingHistoryRepo := repository.NewBookingHistoryRepository(db)
+	floorRepo := repository.NewFloorRepository(db)
+	ownershipRepo := repository.NewOwnershipRepository(db)
+	rentalRepo := repository.NewRentalRepository(db)
+	quarantineRepo := repository.NewQuarantineRepository(db)
+	txManager := repository.NewTxManager(db)
 
-	log.Println("Repositories initialized")
+	appLogger.Debug("repositories initialized")
+
+	// Пул исходящих вызовов: webhook бота и запросы к Telegram API не порождают горутину на каждый запрос
+	outbound := workerpool.New("outbound", cfg.OutboundWorkers, cfg.OutboundQueueSize, appLogger)
+
+	// Планировщик фоновых задач; задачи регистрируются после создания сервисов
+	sched := scheduler.New(appLogger)
 
 	// Инициализируем сервисы
-	userService := service.NewUserService(userRepo)
+	userService := service.NewUserService(userRepo, outbound, cfg.UserpicSyncInterval, appLogger)
 	userService.SetBotToken(cfg.TelegramBotToken) // Устанавливаем bot token для синхронизации userpic
-	roomService := service.NewRoomService(roomRepo, equipmentRepo)
-	notificationService := service.NewNotificationService(notificationRepo, roomRepo, cfg)
-	bookingService := service.NewBookingService(bookingRepo, roomRepo, userRepo, notificationService)
+	textLimits := service.TextLimits{Title: cfg.MaxTitleLength, Description: cfg.MaxDescriptionLength, Name: cfg.MaxNameLength}
+	userService.SetTextLimits(textLimits)
+	roomService := service.NewRoomService(roomRepo, equipmentRepo, feedbackRepo, cfg.RoomCacheTTL)
+	notificationService := service.NewNotificationService(notificationRepo, roomRepo, liveConfig, appLogger)
+	slackService := service.NewSlackService(slackTargetRepo, roomRepo, appLogger)
+	hookService := service.NewHookService(restHookRepo, bookingRepo, appLogger)
+
+	// События бронирований доставляются каналам через пул исходящих вызовов
+	events := service.NewEventBus(outbound, appLogger)
+	events.Subscribe("bot_webhook", notificationService)
+	events.Subscribe("slack", slackService)
+	events.Subscribe("rest_hooks", hookService)
 
-	log.Println("Services initialized")
+	// Объявления администратора подписчикам комнаты идут по тем же каналам, что и события
+	broadcastService := service.NewBroadcastService(roomRepo, outbound, cfg.BroadcastMinInterval, appLogger)
+	broadcastService.Subscribe("bot_webhook", notificationService)
+	broadcastService.Subscribe("slack", slackService)
+
+	// Драйвер замков; nil - интеграция с дверями выключена
+	doorDriver, err := access.NewDriver(access.Config{
+		Driver:  cfg.DoorAccessDriver,
+		URL:     cfg.DoorAccessURL,
+		Token:   cfg.DoorAccessToken,
+		Timeout: cfg.DoorAccessTimeout,
+	})
+	if err != nil {
+		appLogger.Error("failed to create door access driver", "error", err)
+		os.Exit(1)
+	}
+	doorAccessService := service.NewDoorAccessService(doorDriver, doorAccessRepo, bookingRepo, userRepo, cfg.DoorAccessLead, appLogger)
+	if doorDriver != nil {
+		events.Subscribe("door_access", doorAccessService)
+	}
+
+	// Состояние комнат в MQTT; nil - выключено
+	var mqttClient *mqtt.Client
+	var roomStateService *service.RoomStateService
+	if cfg.MQTTURL != "" {
+		mqttClient, err = mqtt.NewClient(mqtt.Config{
+			URL:      cfg.MQTTURL,
+			ClientID: cfg.MQTTClientID,
+			Username: cfg.MQTTUsername,
+			Password: cfg.MQTTPassword,
+			Timeout:  cfg.MQTTTimeout,
+		})
+		if err != nil {
+			appLogger.Error("failed to create MQTT client", "error", err)
+			os.Exit(1)
+		}
+		roomStateService = service.NewRoomStateService(mqttClient, cfg.MQTTTopicPrefix, roomRepo, bookingRepo, appLogger)
+		events.Subscribe("mqtt", roomStateService)
+	}
+
+	// Календарь нерабочих дней; импорт праздников выключен без HOLIDAY_API_URL
+	officeLocation, err := time.LoadLocation(cfg.OfficeTimezone)
+	if err != nil {
+		appLogger.Error("invalid office timezone", "error", err)
+		os.Exit(1)
+	}
+	var holidayProvider service.HolidayProvider
+	if cfg.HolidayAPIURL != "" {
+		holidayProvider = holidays.NewClient(cfg.HolidayAPIURL, holidayAPITimeout)
+	}
+	holidayService := service.NewHolidayService(holidayRepo, holidayProvider, cfg.HolidayCountry, officeLocation, appLogger)
+
+	bookingService := service.NewBookingService(txManager, bookingRepo, roomRepo, userRepo, holidayService, bookingHistoryRepo, events, appLogger)
+	bookingService.SetTextLimits(textLimits)
+	bookingService.SetMultiDaySettings(service.MultiDaySettings{
+		Policy:      service.MultiDayPolicy(cfg.MultiDayBookingPolicy),
+		MaxDuration: cfg.MultiDayBookingMaxDuration,
+		Location:    officeLocation,
+	})
+	// Комнаты, закреплённые за командами-резидентами
+	ownershipService := service.NewOwnershipService(ownershipRepo, roomRepo, teamRepo, userRepo)
+	bookingService.SetDedicatedRooms(ownershipService)
+	bookingHistoryService := service.NewBookingHistoryService(bookingHistoryRepo)
+	parkingService := service.NewParkingService(bookingService, roomRepo, bookingRepo)
+	floorService := service.NewFloorService(floorRepo, roomService, filepath.Join(cfg.StoragePath, "floors"), appLogger)
+	feedbackService := service.NewFeedbackService(bookingRepo, feedbackRepo, appLogger)
+	checkInService := service.NewCheckInService(roomRepo, checkInRepo, appLogger)
+	noShowService := service.NewNoShowService(checkInRepo, roomRepo, userRepo)
+	utilizationService := service.NewUtilizationService(checkInRepo, roomRepo, bookingService, service.UtilizationSettings{
+		Source:         cfg.AttendanceSource,
+		MaxFillPercent: cfg.RightSizeMaxFillPercent,
+		MinBookings:    cfg.RightSizeMinBookings,
+	})
+	cleaningService := service.NewCleaningService(txManager, roomRepo, cleaningRepo, roomService, appLogger)
+
+	// Вход через OIDC; nil - выключен
+	var oidcService *service.OIDCService
+	if cfg.OIDCEnabled() {
+		provider := oidc.NewProvider(cfg.OIDCIssuerURL, cfg.OIDCClientID, cfg.OIDCClientSecret, cfg.OIDCRedirectURL, oidcTimeout)
+		oidcService = service.NewOIDCService(provider, userRepo, cfg.JWTSecret, cfg.OIDCSessionTTL, cfg.OIDCAllowedDomains, appLogger)
+	}
+	// Отключение пользователя каталогом отменяет его будущие бронирования
+	scimService := service.NewSCIMService(userRepo, teamRepo, bookingService, appLogger)
+	importService := service.NewImportService(txManager, roomRepo, bookingRepo, bookingHistoryRepo, userRepo, roomService, appLogger)
+	// Виджет для публичного сайта; nil - выключен
+	var widgetService *service.WidgetService
+	if cfg.WidgetToken != "" {
+		widgetService = service.NewWidgetService(roomRepo, bookingRepo)
+	}
+	// Заявки на аренду сторонними организациями; nil - форма выключена
+	var rentalService *service.RentalService
+	if cfg.RentalsEnabled() {
+		verifyURL := cfg.CaptchaVerifyURL
+		if verifyURL == "" {
+			verifyURL, _ = captcha.VerifyURL(cfg.CaptchaProvider)
+		}
+		verifier := captcha.NewVerifier(verifyURL, cfg.CaptchaSecret, captchaTimeout)
+		rentalService = service.NewRentalService(txManager, rentalRepo, roomRepo, bookingService, verifier, outbound, officeLocation, appLogger)
+		rentalService.SetTextLimits(textLimits)
+		if cfg.SMTPEnabled() {
+			rentalService.SetMailer(mail.NewSender(mail.Config{
+				Host:     cfg.SMTPHost,
+				Port:     cfg.SMTPPort,
+				Username: cfg.SMTPUsername,
+				Password: cfg.SMTPPassword,
+				From:     cfg.SMTPFrom,
+				Timeout:  smtpTimeout,
+			}))
+		}
+	}
+	// Фильтр контента: события бронирований с подозрительным текстом ждут проверки
+	// Сервис создаётся всегда: CONTENT_FILTER_ENABLED включается перезагрузкой конфигурации
+	words := cfg.ContentFilterWords
+	if cfg.ContentFilterWordsFile != "" {
+		fileWords, err := contentfilter.LoadWords(cfg.ContentFilterWordsFile)
+		if err != nil {
+			appLogger.Error("failed to load content filter words", "path", cfg.ContentFilterWordsFile, "error", err)
+			os.Exit(1)
+		}
+		words = append(words, fileWords...)
+	}
+	filter := contentfilter.New(contentfilter.Config{
+		Words:         words,
+		MaxEmoji:      cfg.ContentFilterMaxEmoji,
+		MaxRepeat:     cfg.ContentFilterMaxRepeat,
+		MaxWordLength: cfg.ContentFilterMaxWordLength,
+	})
+	moderationService := service.NewModerationService(quarantineRepo, bookingRepo, filter, events, appLogger)
+	moderationService.SetEnabled(cfg.ContentFilterEnabled)
+	bookingService.SetModerator(moderationService)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, appLogger)
+	healthService := service.NewHealthService(db, liveConfig, sched, appLogger)
+	auditService := service.NewAuditService(auditRepo, time.Duration(cfg.AuditRetentionDays)*24*time.Hour, appLogger)
+
+	purgeService := service.NewPurgeService(txManager, purgeRepo, time.Duration(cfg.SoftDeleteRetentionDays)*24*time.Hour, cfg.PurgeDryRun, appLogger)
+	// Флаги фоновой очистки и фильтра контента применяются при перезагрузке конфигурации
+	liveConfig.OnReload(func(next *config.Config) {
+		purgeService.SetDryRun(next.PurgeDryRun)
+		moderationService.SetEnabled(next.ContentFilterEnabled)
+	})
+	retentionService := service.NewRetentionService(txManager, retentionRepo, appLogger)
+	abuseService := service.NewAbuseService(txManager, abuseRepo, userRepo, service.AbuseThresholds{
+		Window:               cfg.AbuseWindow,
+		MaxCancellations:     cfg.AbuseMaxCancellations,
+		LongBooking:          cfg.AbuseLongBooking,
+		MaxLongBookingStreak: cfg.AbuseMaxLongBookingStreak,
+		AutoBookingLimit:     cfg.AbuseAutoBookingLimit,
+	}, appLogger)
+	billingService := service.NewBillingService(billingRepo, appLogger)
+	billingService.SetCurrency(cfg.PriceCurrency)
+
+	appLogger.Debug("services initialized")
+
+	// Фоновые задачи: очистка кэша членства, журнала аудита и soft-deleted строк, правила хранения, злоупотребления, напоминания, доступ к дверям, состояние комнат
+	registerJobs(sched, cfg, auditService, purgeService, retentionService, abuseService, bookingService, doorAccessService, roomStateService, cleaningService)
+	sched.Start()
 
 	// Настраиваем роутер
 	r := router.SetupRouter(
-		cfg.TelegramBotToken,
-		cfg.BotAPIToken,
-		cfg.AllowedChatID,
-		cfg.AllowedOrigins,
-		cfg.Environment,
-		cfg.AuthDateTTLMiniApp,
-		cfg.AuthDateTTLLoginWidget,
+		liveConfig,
 		userService,
 		roomService,
 		bookingService,
 		notificationService,
+		slackService,
+		hookService,
+		doorAccessService,
+		oidcService,
+		scimService,
+		importService,
+		widgetService,
+		broadcastService,
+		apiKeyService,
+		auditService,
+		purgeService,
+		retentionService,
+		abuseService,
+		billingService,
+		holidayService,
+		feedbackService,
+		checkInService,
+		noShowService,
+		utilizationService,
+		cleaningService,
+		bookingHistoryService,
+		parkingService,
+		floorService,
+		ownershipService,
+		rentalService,
+		moderationService,
+		healthService,
+		sched,
+		appLogger,
 	)
 
-	log.Printf("Router configured")
+	appLogger.Debug("router configured")
 
 	// Создаем канал для graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
 	// Запускаем сервер в горутине
+	srv := &http.Server{
+		Addr:    ":" + cfg.ServerPort,
+		Handler: r,
+	}
+
+	// Без проксирующего балансировщика сервер сам терминирует TLS,
+	// а дополнительный HTTP-листенер перенаправляет на HTTPS
+	var redirectSrv *http.Server
+	if cfg.TLSEnabled() {
+		redirectHandler := configureTLS(cfg, srv)
+		if cfg.HTTPRedirectPort != "" {
+			redirectSrv = &http.Server{
+				Addr:              ":" + cfg.HTTPRedirectPort,
+				Handler:           redirectHandler,
+				ReadHeaderTimeout: redirectReadHeaderTimeout,
+			}
+			go func() {
+				appLogger.Info("HTTP to HTTPS redirect is starting", "addr", redirectSrv.Addr)
+				if err := redirectSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					appLogger.Error("failed to start redirect listener", "error", err)
+					os.Exit(1)
+				}
+			}()
+		}
+	}
+
 	go func() {
-		addr := ":" + cfg.ServerPort
-		log.Printf("Server is starting on http://localhost%s", addr)
-		log.Printf("Health check available at http://localhost%s/health", addr)
-		log.Printf("API endpoints available at http://localhost%s/api", addr)
+		appLogger.Info("server is starting", "addr", srv.Addr, "tls", cfg.TLSEnabled(), "live", "/health/live", "ready", "/health/ready", "api", "/api")
 
-		if err := r.Run(addr); err != nil {
-			log.Fatalf("Failed to start server: %v", err)
+		if err := listenAndServe(cfg, srv); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			appLogger.Error("failed to start server", "error", err)
+			os.Exit(1)
+		}
+	}()
+
+	// SIGHUP перезагружает безопасное подмножество настроек без остановки сервера
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			result, err := liveConfig.Reload()
+			if err != nil {
+				appLogger.Error("failed to reload configuration, keeping current", "error", err)
+				continue
+			}
+			appLogger.Info("configuration reloaded", "applied", result.Applied, "requires_restart", result.RequiresRestart)
 		}
 	}()
 
 	// Ожидаем сигнал завершения
 	<-quit
-	log.Println("Shutting down server...")
+	appLogger.Info("shutting down server")
+
+	// Readiness переходит в draining: балансировщик успевает исключить инстанс,
+	// пока сервер ещё обслуживает запросы
+	healthService.StartDraining()
+	if cfg.ShutdownDrainDelay > 0 {
+		appLogger.Info("draining before shutdown", "delay", cfg.ShutdownDrainDelay.String())
+		time.Sleep(cfg.ShutdownDrainDelay)
+	}
+
+	// Перестаём принимать соединения и дожидаемся обработки текущих запросов
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), httpShutdownTimeout)
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		appLogger.Error("HTTP server did not finish in-flight requests in time", "error", err)
+	}
+	if redirectSrv != nil {
+		if err := redirectSrv.Shutdown(shutdownCtx); err != nil {
+			appLogger.Error("redirect listener did not stop in time", "error", err)
+		}
+	}
+	cancel()
+
+	// Дожидаемся завершения выполняющихся фоновых задач до закрытия БД
+	stopCtx, cancel := context.WithTimeout(context.Background(), schedulerStopTimeout)
+	if err := sched.Stop(stopCtx); err != nil {
+		appLogger.Error("background jobs did not finish in time", "error", err)
+	}
+	cancel()
+
+	// Дожидаемся отправки уже поставленных в очередь webhook и синхронизаций
+	drainCtx, cancel := context.WithTimeout(context.Background(), outboundDrainTimeout)
+	if err := outbound.Shutdown(drainCtx); err != nil {
+		appLogger.Error("outbound calls did not finish in time", "error", err)
+	}
+	cancel()
+
+	if mqttClient != nil {
+		_ = mqttClient.Close()
+	}
 
 	// Закрываем подключение к базе данных
 	if err := database.Close(db); err != nil {
-		log.Printf("Error closing database: %v", err)
+		appLogger.Error("error closing database", "error", err)
 	}
 
-	log.Println("Server gracefully stopped")
+	appLogger.Info("server gracefully stopped")
 
 	// Используем переменные чтобы избежать ошибки "unused"
 	_ = instructionRepo
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"strings"
+
+	"golang.org/x/crypto/acme/autocert"
+
+	"github.com/space/backend/internal/config"
+)
+
+// configureTLS готовит TLS для основного сервера и возвращает handler листенера редиректа
+// При autocert редирект-листенер также отвечает на HTTP-01 challenge Let's Encrypt
+func configureTLS(cfg *config.Config, srv *http.Server) http.Handler {
+	redirect := httpsRedirect(cfg.ServerPort)
+	if len(cfg.TLSAutocertDomains) == 0 {
+		srv.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		return redirect
+	}
+
+	manager := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(cfg.TLSAutocertDomains...),
+		Cache:      autocert.DirCache(cfg.TLSAutocertCacheDir),
+		Email:      cfg.TLSAutocertEmail,
+	}
+	srv.TLSConfig = manager.TLSConfig()
+	srv.TLSConfig.MinVersion = tls.VersionTLS12
+	return manager.HTTPHandler(redirect)
+}
+
+// listenAndServe запускает сервер по HTTP или HTTPS в зависимости от конфигурации
+func listenAndServe(cfg *config.Config, srv *http.Server) error {
+	if !cfg.TLSEnabled() {
+		return srv.ListenAndServe()
+	}
+	// При autocert сертификаты берутся из TLSConfig.GetCertificate, пути не нужны
+	return srv.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+}
+
+// httpsRedirect перенаправляет запросы на тот же хост и путь по HTTPS
+// Порт 443 в адресе не указывается
+func httpsRedirect(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		host = strings.Trim(host, "[]")
+		if httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		} else if strings.Contains(host, ":") {
+			host = "[" + host + "]" // IPv6
+		}
+
+		target := "https://" + host + r.URL.RequestURI()
+		// 308 сохраняет метод и тело запроса, в отличие от 301
+		http.Redirect(w, r, target, http.StatusPermanentRedirect)
+	})
+}
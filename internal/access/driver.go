@@ -0,0 +1,61 @@
+// Package access grants door access to booking participants through pluggable lock drivers
+package access
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Driver grants and revokes door access for a booking window
+// Реализации: HTTP-колбэки (HTTPDriver); драйвер конкретного замка подключается через NewDriver
+type Driver interface {
+	Grant(ctx context.Context, grant Grant) (*Credential, error)
+	Revoke(ctx context.Context, grant Grant) error
+}
+
+// Grant describes who may open which door and when
+type Grant struct {
+	BookingID  uint      `json:"booking_id"`
+	RoomID     uint      `json:"room_id"`
+	DoorID     string    `json:"door_id"`
+	ValidFrom  time.Time `json:"valid_from"`
+	ValidUntil time.Time `json:"valid_until"`
+	Users      []User    `json:"users"`                 // Создатель и участники
+	ExternalID string    `json:"external_id,omitempty"` // Идентификатор доступа у драйвера (при отзыве)
+}
+
+// User is a person who gets access
+type User struct {
+	ID         uint   `json:"id"`
+	TelegramID int64  `json:"telegram_id"`
+	Username   string `json:"username,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Phone      string `json:"phone,omitempty"`
+}
+
+// Credential is what the driver issued for a grant
+type Credential struct {
+	ExternalID string `json:"external_id,omitempty"` // Нужен драйверу для отзыва
+	PIN        string `json:"pin,omitempty"`         // Код для клавиатуры замка, показывается участникам
+}
+
+// Config configures the access driver
+type Config struct {
+	Driver  string // "" - интеграция выключена, "http" - HTTP-колбэки
+	URL     string
+	Token   string
+	Timeout time.Duration
+}
+
+// NewDriver creates the configured driver; nil means door access is disabled
+func NewDriver(cfg Config) (Driver, error) {
+	switch cfg.Driver {
+	case "":
+		return nil, nil
+	case "http":
+		return NewHTTPDriver(cfg.URL, cfg.Token, cfg.Timeout), nil
+	default:
+		return nil, fmt.Errorf("unknown door access driver %q", cfg.Driver)
+	}
+}
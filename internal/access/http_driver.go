@@ -0,0 +1,78 @@
+package access
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HTTPDriver delegates access control to an external service via HTTP callbacks
+// POST {url}/grant с Grant в теле отвечает Credential (оба поля необязательны),
+// POST {url}/revoke с Grant (включая external_id) отзывает доступ
+type HTTPDriver struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewHTTPDriver creates an HTTP callback driver
+func NewHTTPDriver(baseURL, token string, timeout time.Duration) *HTTPDriver {
+	return &HTTPDriver{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Grant asks the external service to grant access
+func (d *HTTPDriver) Grant(ctx context.Context, grant Grant) (*Credential, error) {
+	var credential Credential
+	if err := d.call(ctx, "/grant", grant, &credential); err != nil {
+		return nil, err
+	}
+	return &credential, nil
+}
+
+// Revoke asks the external service to revoke access
+func (d *HTTPDriver) Revoke(ctx context.Context, grant Grant) error {
+	return d.call(ctx, "/revoke", grant, nil)
+}
+
+func (d *HTTPDriver) call(ctx context.Context, path string, grant Grant, result interface{}) error {
+	body, err := json.Marshal(grant)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if d.token != "" {
+		req.Header.Set("Authorization", "Bearer "+d.token)
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("door access %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return fmt.Errorf("door access %s returned status %d", path, resp.StatusCode)
+	}
+
+	// Пустое тело - допустимый ответ: драйвер ничего не выдал
+	data, err := io.ReadAll(resp.Body)
+	if err != nil || result == nil || len(bytes.TrimSpace(data)) == 0 {
+		return err
+	}
+	return json.Unmarshal(data, result)
+}
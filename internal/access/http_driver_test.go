@@ -0,0 +1,53 @@
+package access
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPDriver(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var grant Grant
+		if err := json.NewDecoder(r.Body).Decode(&grant); err != nil || grant.DoorID != "front" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path == "/api/grant" {
+			_, _ = w.Write([]byte(`{"external_id":"abc","pin":"1234"}`))
+		}
+	}))
+	defer server.Close()
+
+	driver := NewHTTPDriver(server.URL+"/api/", "secret", time.Second)
+	grant := Grant{BookingID: 1, DoorID: "front", Users: []User{{ID: 1}}}
+
+	credential, err := driver.Grant(context.Background(), grant)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if credential.ExternalID != "abc" || credential.PIN != "1234" {
+		t.Errorf("Unexpected credential: %+v", credential)
+	}
+
+	grant.ExternalID = credential.ExternalID
+	if err := driver.Revoke(context.Background(), grant); err != nil {
+		t.Fatalf("Expected empty revoke response to be accepted, got: %v", err)
+	}
+	if len(paths) != 2 || paths[1] != "/api/revoke" {
+		t.Errorf("Unexpected calls: %v", paths)
+	}
+
+	if _, err := NewHTTPDriver(server.URL+"/api", "wrong", time.Second).Grant(context.Background(), grant); err == nil {
+		t.Error("Expected error on non-2xx response")
+	}
+}
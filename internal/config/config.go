@@ -5,92 +5,397 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 // Config holds all configuration for the application
 type Config struct {
-	ServerPort           string
-	DatabaseURL          string
-	TelegramBotToken     string
-	AllowedChatID        int64    // Telegram group chat ID for membership check
-	JWTSecret            string
-	StoragePath          string
-	Environment          string
-	SupabaseURL          string
-	SupabaseKey          string
-	AllowedOrigins       []string // CORS allowed origins
-	AuthDateTTLMiniApp   int64    // TTL for Mini App auth_date in seconds (default: 3600 = 1 hour)
-	AuthDateTTLLoginWidget int64  // TTL for Login Widget auth_date in seconds (default: 604800 = 7 days)
-	BotAPIToken          string   // Secret token for bot API authentication
-	BotWebhookURL        string   // URL of the bot webhook for sending notifications
+	ServerPort             string
+	DatabaseURL            string
+	DatabaseReplicaURL     string // Необязательная реплика для календаря и телефонной книги
+	TelegramBotToken       string
+	AllowedChatID          int64 // Telegram group chat ID for membership check
+	JWTSecret              string
+	StoragePath            string
+	Environment            string
+	SupabaseURL            string
+	SupabaseKey            string
+	AllowedOrigins         []string         // CORS allowed origins
+	AuthDateTTLMiniApp     int64            // TTL for Mini App auth_date in seconds (default: 3600 = 1 hour)
+	AuthDateTTLLoginWidget int64            // TTL for Login Widget auth_date in seconds (default: 2592000 = 30 days)
+	BotAPIToken            string           // Secret token for bot API authentication
+	BotWebhookURL          string           // URL of the bot webhook for sending notifications
+	BotWebhookPath         string           // Путь webhook о новом бронировании (default: /webhook/booking/created)
+	LogLevel               string           // debug, info, warn, error (default: info)
+	LogFormat              string           // json или text (default: json в production, text иначе)
+	RateLimitRPM           int              // Лимит запросов в минуту с одного IP для анонимных запросов
+	RateLimitUserRPM       int              // Лимит запросов в минуту на авторизованного пользователя
+	RouteRateLimits        []RouteRateLimit // Отдельные лимиты для дорогих маршрутов
+
+	RateLimitCleanupInterval time.Duration // Период очистки неактивных записей rate limiter
+
+	MembershipCacheTTL             time.Duration // TTL кэша членства в группе
+	MembershipCacheCleanupInterval time.Duration // Период очистки устаревших записей кэша членства
+
+	PublicCacheTTL time.Duration // TTL серверного кэша публичных GET-эндпоинтов (0 - выключен)
+	RoomCacheTTL   time.Duration // TTL кэша списков комнат в RoomService (0 - выключен)
+
+	UserpicSyncInterval  time.Duration // Как часто обновлять userpic пользователя из Telegram (0 - при каждом запросе)
+	BroadcastMinInterval time.Duration // Минимальный интервал между объявлениями в одну комнату (0 - без ограничения)
+	RequestTimeout       time.Duration // Дедлайн обработки запроса (0 - без ограничения)
+
+	AuditRetentionDays int // Срок хранения журнала аудита в днях (0 - хранить бессрочно)
+
+	// Окончательное удаление soft-deleted бронирований, комнат и пользователей
+	SoftDeleteRetentionDays int  // Через сколько дней после удаления строка стирается (0 - никогда)
+	PurgeDryRun             bool // Фоновая задача только считает строки, ничего не удаляя
+
+	// Начальные данные установки: комнаты, оборудование и инструкции из YAML/JSON-файла,
+	// недостающие записи создаются при каждом старте ("" - выключено)
+	DataPackFile string
+
+	// Подключение к БД при старте
+	DBConnectMaxWait time.Duration // Сколько ждать доступности БД (0 - одна попытка)
+	DBConnectBackoff time.Duration // Начальная пауза между попытками (удваивается)
+
+	// Запросы дольше порога логируются как warning и считаются в метриках (0 - выключено)
+	DBSlowQueryThreshold time.Duration
+
+	// statement_timeout сессии PostgreSQL: зависшие запросы прерываются сервером (0 - настройка сервера)
+	DBStatementTimeout time.Duration
+
+	// Настройки сессии GORM
+	DBPrepareStmt            bool // Кэшировать подготовленные выражения на соединениях
+	DBSkipDefaultTransaction bool // Не оборачивать одиночные записи в транзакцию
+
+	// HTTP-поведение webhook бота
+	BotWebhookTimeout time.Duration     // Таймаут одного запроса
+	BotWebhookRetries int               // Количество повторов при сетевой ошибке или 5xx/429
+	BotWebhookBackoff time.Duration     // Начальная пауза между повторами (удваивается)
+	BotWebhookHeaders map[string]string // Дополнительные заголовки запроса
+
+	// TLS на самом сервере (для развёртываний без проксирующего балансировщика):
+	// либо файлы сертификата и ключа, либо autocert через Let's Encrypt
+	TLSCertFile         string
+	TLSKeyFile          string
+	TLSAutocertDomains  []string // Домены, для которых autocert выпускает сертификаты
+	TLSAutocertEmail    string   // Контакт для уведомлений Let's Encrypt
+	TLSAutocertCacheDir string   // Каталог для выпущенных сертификатов
+	HTTPRedirectPort    string   // Порт HTTP-листенера с редиректом на HTTPS (пусто - выключен)
+
+	// Пауза между переводом readiness в draining и остановкой HTTP-сервера
+	ShutdownDrainDelay time.Duration
+
+	// За сколько до начала бронирования рассылается напоминание (Slack); 0 - выключено
+	BookingReminderLead time.Duration
+
+	// Бронирования через полночь или длиннее порога
+	MultiDayBookingPolicy      string        // allow (с предупреждением), reject или admin (только администраторы с allow_multi_day)
+	MultiDayBookingMaxDuration time.Duration // Бронирование длиннее тоже многодневное (0 - только пересечение полуночи)
+
+	// Максимальная длина текстовых полей в символах; 0 - значение по умолчанию
+	MaxTitleLength       int // Название бронирования
+	MaxDescriptionLength int // Описание бронирования
+	MaxNameLength        int // Имя и фамилия в профиле
+
+	// Выявление злоупотреблений бронированиями; нулевой порог выключает правило
+	AbuseDetectionInterval    time.Duration // Период проверки (0 - выключено)
+	AbuseWindow               time.Duration // Окно: отмены считаются назад, длинные бронирования - вперёд
+	AbuseMaxCancellations     int           // Отмен за окно, после которых пользователь отмечается
+	AbuseLongBooking          time.Duration // Бронирование не короче считается длинным (на весь день)
+	AbuseMaxLongBookingStreak int           // Длинных бронирований подряд, после которых пользователь отмечается
+	AbuseAutoBookingLimit     int           // Лимит активных бронирований отмеченного пользователя (0 - не менять)
+
+	// Задачи уборки после бронирований комнат с reset_required; 0 - выключено
+	CleaningJobInterval time.Duration
+
+	// Фактическое присутствие на бронированиях и рекомендации по размеру комнат
+	AttendanceSource        string // auto (отметки, а без них - участники), participants или checkins
+	RightSizeMaxFillPercent int    // Средняя заполненность не выше - предлагается меньшая комната (0 - без рекомендаций)
+	RightSizeMinBookings    int    // Бронирований за период, без которых рекомендация не даётся
+
+	// Валюта почасовых цен комнат и стоимости бронирований (ISO 4217)
+	PriceCurrency string
+
+	// Календарь нерабочих дней
+	OfficeTimezone string // Часовой пояс пространства (IANA), к которому относятся даты нерабочих дней
+	HolidayAPIURL  string // Nager.Date API для импорта государственных праздников ("" - импорт выключен)
+	HolidayCountry string // Страна импорта по умолчанию (ISO 3166-1 alpha-2, например RU)
+
+	// Вход через OIDC (Google, Keycloak) для участников без Telegram; пустой issuer - выключено
+	OIDCIssuerURL      string
+	OIDCClientID       string
+	OIDCClientSecret   string
+	OIDCRedirectURL    string        // Callback на этом сервере: https://api.example.com/api/auth/oidc/callback
+	OIDCPostLoginURL   string        // Страница фронтенда, куда возвращается браузер с токеном сессии
+	OIDCAllowedDomains []string      // Домены email, которым разрешён вход (обязательно в production)
+	OIDCSessionTTL     time.Duration // Срок действия токена сессии
+
+	// Доступ к дверям на время бронирования (драйвер замков)
+	DoorAccessDriver  string // "" - выключено, "http" - HTTP-колбэки на DOOR_ACCESS_URL
+	DoorAccessURL     string
+	DoorAccessToken   string        // Bearer токен для драйвера
+	DoorAccessLead    time.Duration // За сколько до начала бронирования выдаётся доступ
+	DoorAccessTimeout time.Duration
+
+	// Публикация состояния комнат в MQTT (Home Assistant, табло у переговорных)
+	MQTTURL         string // "" - выключено; mqtt://host:1883 или mqtts://host:8883
+	MQTTUsername    string
+	MQTTPassword    string
+	MQTTClientID    string
+	MQTTTopicPrefix string // Топики: {prefix}/rooms/{id} и {prefix}/rooms/{id}/state
+	MQTTTimeout     time.Duration
+
+	// Виджет доступности комнат для публичного сайта (/api/widget)
+	WidgetToken  string // "" - выключен; передаётся в ?token=, поэтому виден на сайте и даёт только чтение занятости
+	WidgetOrigin string // Единственный origin сайта, которому разрешён CORS
+
+	// Публичная форма заявок на аренду комнат сторонними организациями (/api/public/rental-requests)
+	CaptchaProvider  string // turnstile, hcaptcha или recaptcha
+	CaptchaSecret    string // "" - форма заявок выключена
+	CaptchaVerifyURL string // Адрес проверки токенов вместо адреса провайдера
+
+	// SMTP для писем заявителям; без SMTP_HOST письма не отправляются
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string // Адрес отправителя, например "Space <noreply@space.example.com>"
+
+	// Фильтр контента названий и описаний бронирований перед рассылкой подписчикам комнаты;
+	// отмеченные события ждут проверки в карантине (/api/admin/booking-quarantine)
+	ContentFilterEnabled       bool
+	ContentFilterWords         []string // Запрещённые слова; "слово*" - все слова с этим началом
+	ContentFilterWordsFile     string   // Файл со словами, по одному в строке; дополняет CONTENT_FILTER_WORDS
+	ContentFilterMaxEmoji      int      // Эмодзи в тексте сверх лимита (0 - не проверять)
+	ContentFilterMaxRepeat     int      // Один символ подряд сверх лимита (0 - не проверять)
+	ContentFilterMaxWordLength int      // Слово без пробелов длиннее лимита (0 - не проверять)
+
+	// Swagger UI и OpenAPI-спецификация под /api/docs
+	APIDocsEnabled  bool
+	APIDocsUser     string // Basic Auth для /api/docs (пароль пустой - без авторизации)
+	APIDocsPassword string
+
+	// Эндпоинты net/http/pprof под /api/admin/debug/pprof (только для администраторов)
+	PprofEnabled bool
+
+	// Пул исходящих вызовов (webhook бота, Telegram API)
+	OutboundWorkers   int // Число одновременных вызовов
+	OutboundQueueSize int // Размер очереди; при переполнении задачи отбрасываются
+
+	// Заголовки безопасности (значение "off" отключает заголовок)
+	SecurityCSP               string
+	SecurityFrameOptions      string
+	SecurityReferrerPolicy    string
+	SecurityPermissionsPolicy string
+	SecurityHSTS              string
+
+	// Ротация токенов: предыдущие токены принимаются до TokenRotationGraceUntil
+	TelegramBotTokenPrevious string
+	BotAPITokenPrevious      string
+	TokenRotationGraceUntil  time.Time
+}
+
+// RouteRateLimit описывает лимит для маршрута, например "POST /api/bookings=10/1m"
+type RouteRateLimit struct {
+	Method   string
+	Path     string
+	Requests int
+	Window   time.Duration
 }
 
+// defaultRouteRateLimits - лимиты маршрутов по умолчанию (создание бронирований дороже чтения)
+const defaultRouteRateLimits = "POST /api/bookings=10/1m,POST /api/bot/bookings=10/1m"
+
 // Load loads configuration from environment variables
-func Load() (*Config, error) {
+// If configPath is set, values from the YAML file are used where env vars are not set.
+// All invalid values are reported at once in a *ValidationError
+func Load(configPath string) (*Config, error) {
 	// Загружаем .env файл (игнорируем ошибку если файла нет)
 	_ = godotenv.Load()
 
-	// JWT Secret validation - критически важно для безопасности
-	jwtSecret := getEnv("JWT_SECRET", "")
-	if jwtSecret == "" {
-		return nil, fmt.Errorf("JWT_SECRET is required and must not be empty")
-	}
-	if len(jwtSecret) < 32 {
-		return nil, fmt.Errorf("JWT_SECRET must be at least 32 characters long for security")
-	}
-
-	// Parse AllowedChatID
-	allowedChatID := int64(0)
-	if chatIDStr := getEnv("ALLOWED_CHAT_ID", ""); chatIDStr != "" {
-		if parsed, err := strconv.ParseInt(chatIDStr, 10, 64); err == nil {
-			allowedChatID = parsed
+	// Загружаем YAML-файл конфигурации; переменные окружения его переопределяют
+	fileValues = map[string]string{}
+	if configPath != "" {
+		values, err := loadFile(configPath)
+		if err != nil {
+			return nil, err
 		}
+		fileValues = values
 	}
 
-	// Parse Auth Date TTL values
-	authDateTTLMiniApp := parseInt64WithDefault(getEnv("AUTH_DATE_TTL_MINIAPP", ""), 3600) // 1 hour default
-	authDateTTLLoginWidget := parseInt64WithDefault(getEnv("AUTH_DATE_TTL_LOGIN_WIDGET", ""), 2592000) // 30 days default (вместо 7 дней)
+	// Собираем все проблемы конфигурации, а не только первую
+	l := &loader{}
 
 	config := &Config{
-		ServerPort:           getEnv("SERVER_PORT", "8080"),
-		DatabaseURL:          getEnv("DATABASE_URL", ""),
-		TelegramBotToken:     getEnv("TELEGRAM_BOT_TOKEN", ""),
-		AllowedChatID:        allowedChatID,
-		JWTSecret:            jwtSecret,
-		StoragePath:          getEnv("STORAGE_PATH", "./storage"),
-		Environment:          getEnv("ENVIRONMENT", "development"),
-		SupabaseURL:          getEnv("SUPABASE_URL", ""),
-		SupabaseKey:          getEnv("SUPABASE_SECRET_KEY", ""),
-		AllowedOrigins:       parseAllowedOrigins(getEnv("ALLOWED_ORIGINS", "")),
-		AuthDateTTLMiniApp:   authDateTTLMiniApp,
-		AuthDateTTLLoginWidget: authDateTTLLoginWidget,
-		BotAPIToken:          getEnv("BOT_API_TOKEN", ""),
-		BotWebhookURL:        getEnv("BOT_WEBHOOK_URL", "http://localhost:8081"),
+		ServerPort:              getEnv("SERVER_PORT", "8080"),
+		DatabaseURL:             getEnv("DATABASE_URL", ""),
+		DatabaseReplicaURL:      getEnv("DATABASE_REPLICA_URL", ""),
+		TelegramBotToken:        getEnv("TELEGRAM_BOT_TOKEN", ""),
+		AllowedChatID:           l.int64("ALLOWED_CHAT_ID", 0),
+		JWTSecret:               getEnv("JWT_SECRET", ""),
+		StoragePath:             getEnv("STORAGE_PATH", "./storage"),
+		DataPackFile:            getEnv("DATA_PACK_FILE", ""),
+		Environment:             getEnv("ENVIRONMENT", "development"),
+		SupabaseURL:             getEnv("SUPABASE_URL", ""),
+		SupabaseKey:             getEnv("SUPABASE_SECRET_KEY", ""),
+		AllowedOrigins:          parseAllowedOrigins(getEnv("ALLOWED_ORIGINS", "")),
+		AuthDateTTLMiniApp:      l.int64("AUTH_DATE_TTL_MINIAPP", 3600),         // 1 hour default
+		AuthDateTTLLoginWidget:  l.int64("AUTH_DATE_TTL_LOGIN_WIDGET", 2592000), // 30 days default (вместо 7 дней)
+		BotAPIToken:             getEnv("BOT_API_TOKEN", ""),
+		BotWebhookURL:           getEnv("BOT_WEBHOOK_URL", "http://localhost:8081"),
+		BotWebhookPath:          getEnv("BOT_WEBHOOK_PATH", "/webhook/booking/created"),
+		LogLevel:                getEnv("LOG_LEVEL", "info"),
+		LogFormat:               getEnv("LOG_FORMAT", ""),
+		RateLimitRPM:            int(l.int64("RATE_LIMIT_RPM", 100)),
+		RateLimitUserRPM:        int(l.int64("RATE_LIMIT_USER_RPM", 300)),
+		AuditRetentionDays:      int(l.int64("AUDIT_RETENTION_DAYS", 90)),
+		SoftDeleteRetentionDays: int(l.int64("SOFT_DELETE_RETENTION_DAYS", 180)),
+		PurgeDryRun:             l.bool("PURGE_DRY_RUN", false),
+		PprofEnabled:            l.bool("PPROF_ENABLED", false),
+		BotWebhookRetries:       int(l.int64("BOT_WEBHOOK_RETRIES", 2)),
+		OutboundWorkers:         int(l.int64("OUTBOUND_WORKERS", 8)),
+		OutboundQueueSize:       int(l.int64("OUTBOUND_QUEUE_SIZE", 1000)),
+
+		RateLimitCleanupInterval:       l.duration("RATE_LIMIT_CLEANUP_INTERVAL", 5*time.Minute),
+		MembershipCacheTTL:             l.duration("MEMBERSHIP_CACHE_TTL", 5*time.Minute),
+		MembershipCacheCleanupInterval: l.duration("MEMBERSHIP_CACHE_CLEANUP_INTERVAL", 12*time.Hour),
+		PublicCacheTTL:                 l.duration("PUBLIC_CACHE_TTL", 5*time.Second),
+		RoomCacheTTL:                   l.duration("ROOM_CACHE_TTL", time.Minute),
+		UserpicSyncInterval:            l.duration("USERPIC_SYNC_INTERVAL", 24*time.Hour),
+		BroadcastMinInterval:           l.duration("BROADCAST_MIN_INTERVAL", 10*time.Minute),
+		RequestTimeout:                 l.duration("REQUEST_TIMEOUT", 15*time.Second),
+		BotWebhookTimeout:              l.duration("BOT_WEBHOOK_TIMEOUT", 10*time.Second),
+		BotWebhookBackoff:              l.duration("BOT_WEBHOOK_BACKOFF", time.Second),
+		DBConnectMaxWait:               l.duration("DB_CONNECT_MAX_WAIT", time.Minute),
+		DBConnectBackoff:               l.duration("DB_CONNECT_BACKOFF", time.Second),
+		DBSlowQueryThreshold:           l.duration("DB_SLOW_QUERY_THRESHOLD", 200*time.Millisecond),
+		DBStatementTimeout:             l.duration("DB_STATEMENT_TIMEOUT", 30*time.Second),
+		DBPrepareStmt:                  l.bool("DB_PREPARE_STMT", true),
+		DBSkipDefaultTransaction:       l.bool("DB_SKIP_DEFAULT_TRANSACTION", true),
+		ShutdownDrainDelay:             l.duration("SHUTDOWN_DRAIN_DELAY", 5*time.Second),
+		BookingReminderLead:            l.duration("BOOKING_REMINDER_LEAD", 15*time.Minute),
+		MultiDayBookingMaxDuration:     l.duration("MULTI_DAY_BOOKING_MAX_DURATION", 24*time.Hour),
+		MaxTitleLength:                 int(l.int64("MAX_TITLE_LENGTH", 200)),
+		MaxDescriptionLength:           int(l.int64("MAX_DESCRIPTION_LENGTH", 2000)),
+		MaxNameLength:                  int(l.int64("MAX_NAME_LENGTH", 50)),
+		AbuseDetectionInterval:         l.duration("ABUSE_DETECTION_INTERVAL", time.Hour),
+		AbuseWindow:                    l.duration("ABUSE_WINDOW", 7*24*time.Hour),
+		AbuseMaxCancellations:          int(l.int64("ABUSE_MAX_CANCELLATIONS", 10)),
+		AbuseLongBooking:               l.duration("ABUSE_LONG_BOOKING", 8*time.Hour),
+		AbuseMaxLongBookingStreak:      int(l.int64("ABUSE_MAX_LONG_BOOKING_STREAK", 3)),
+		AbuseAutoBookingLimit:          int(l.int64("ABUSE_AUTO_BOOKING_LIMIT", 0)),
+		CleaningJobInterval:            l.duration("CLEANING_JOB_INTERVAL", 5*time.Minute),
+		RightSizeMaxFillPercent:        int(l.int64("RIGHTSIZE_MAX_FILL_PERCENT", 50)),
+		RightSizeMinBookings:           int(l.int64("RIGHTSIZE_MIN_BOOKINGS", 5)),
+		DoorAccessLead:                 l.duration("DOOR_ACCESS_LEAD", 5*time.Minute),
+		OIDCSessionTTL:                 l.duration("OIDC_SESSION_TTL", 24*time.Hour),
+		DoorAccessTimeout:              l.duration("DOOR_ACCESS_TIMEOUT", 10*time.Second),
+		MQTTTimeout:                    l.duration("MQTT_TIMEOUT", 10*time.Second),
+
+		OIDCIssuerURL:      getEnv("OIDC_ISSUER_URL", ""),
+		OIDCClientID:       getEnv("OIDC_CLIENT_ID", ""),
+		OIDCClientSecret:   getEnv("OIDC_CLIENT_SECRET", ""),
+		OIDCRedirectURL:    getEnv("OIDC_REDIRECT_URL", ""),
+		OIDCPostLoginURL:   getEnv("OIDC_POST_LOGIN_URL", ""),
+		OIDCAllowedDomains: parseList(getEnv("OIDC_ALLOWED_DOMAINS", "")),
+
+		DoorAccessDriver: getEnv("DOOR_ACCESS_DRIVER", ""),
+		DoorAccessURL:    getEnv("DOOR_ACCESS_URL", ""),
+		DoorAccessToken:  getEnv("DOOR_ACCESS_TOKEN", ""),
+
+		MQTTURL:         getEnv("MQTT_URL", ""),
+		MQTTUsername:    getEnv("MQTT_USERNAME", ""),
+		MQTTPassword:    getEnv("MQTT_PASSWORD", ""),
+		MQTTClientID:    getEnv("MQTT_CLIENT_ID", "space-backend"),
+		MQTTTopicPrefix: getEnv("MQTT_TOPIC_PREFIX", "space"),
+
+		AttendanceSource: getEnv("ATTENDANCE_SOURCE", "auto"),
+
+		MultiDayBookingPolicy: getEnv("MULTI_DAY_BOOKING_POLICY", "allow"),
+
+		PriceCurrency: getEnv("PRICE_CURRENCY", "RUB"),
+
+		OfficeTimezone: getEnv("OFFICE_TIMEZONE", "UTC"),
+		HolidayAPIURL:  getEnv("HOLIDAY_API_URL", "https://date.nager.at"),
+		HolidayCountry: getEnv("HOLIDAY_COUNTRY", ""),
+
+		WidgetToken:  getEnv("WIDGET_TOKEN", ""),
+		WidgetOrigin: getEnv("WIDGET_ORIGIN", ""),
+
+		CaptchaProvider:  getEnv("CAPTCHA_PROVIDER", "turnstile"),
+		CaptchaSecret:    getEnv("CAPTCHA_SECRET", ""),
+		CaptchaVerifyURL: getEnv("CAPTCHA_VERIFY_URL", ""),
+
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnv("SMTP_PORT", "587"),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:     getEnv("SMTP_FROM", ""),
+
+		ContentFilterEnabled:       l.bool("CONTENT_FILTER_ENABLED", false),
+		ContentFilterWords:         parseList(getEnv("CONTENT_FILTER_WORDS", "")),
+		ContentFilterWordsFile:     getEnv("CONTENT_FILTER_WORDS_FILE", ""),
+		ContentFilterMaxEmoji:      int(l.int64("CONTENT_FILTER_MAX_EMOJI", 10)),
+		ContentFilterMaxRepeat:     int(l.int64("CONTENT_FILTER_MAX_REPEAT", 10)),
+		ContentFilterMaxWordLength: int(l.int64("CONTENT_FILTER_MAX_WORD_LENGTH", 50)),
+
+		TelegramBotTokenPrevious: getEnv("TELEGRAM_BOT_TOKEN_PREVIOUS", ""),
+		BotAPITokenPrevious:      getEnv("BOT_API_TOKEN_PREVIOUS", ""),
+
+		TLSCertFile:         getEnv("TLS_CERT_FILE", ""),
+		TLSKeyFile:          getEnv("TLS_KEY_FILE", ""),
+		TLSAutocertDomains:  parseList(getEnv("TLS_AUTOCERT_DOMAINS", "")),
+		TLSAutocertEmail:    getEnv("TLS_AUTOCERT_EMAIL", ""),
+		TLSAutocertCacheDir: getEnv("TLS_AUTOCERT_CACHE_DIR", "./storage/autocert"),
+		HTTPRedirectPort:    getEnv("HTTP_REDIRECT_PORT", ""),
 	}
 
-	// Если DATABASE_URL не задан, но есть SUPABASE_URL - строим DATABASE_URL из Supabase
-	if config.DatabaseURL == "" && config.SupabaseURL != "" {
-		config.DatabaseURL = buildSupabaseDatabaseURL(config.SupabaseURL)
-		// Не выводим DATABASE_URL даже в development режиме из соображений безопасности
+	webhookHeaders, err := parseHeaders(getEnv("BOT_WEBHOOK_HEADERS", ""))
+	if err != nil {
+		l.problemf("BOT_WEBHOOK_HEADERS: %v", err)
 	}
+	config.BotWebhookHeaders = webhookHeaders
 
-	// Валидация обязательных параметров
-	if config.DatabaseURL == "" {
-		return nil, fmt.Errorf("DATABASE_URL or SUPABASE_URL is required")
+	routeRateLimits, err := parseRouteRateLimits(getEnv("RATE_LIMIT_ROUTES", defaultRouteRateLimits))
+	if err != nil {
+		l.problemf("RATE_LIMIT_ROUTES: %v", err)
 	}
+	config.RouteRateLimits = routeRateLimits
 
-	if config.TelegramBotToken == "" {
-		return nil, fmt.Errorf("TELEGRAM_BOT_TOKEN is required")
+	// Parse token rotation grace window (RFC3339, например 2025-12-01T00:00:00Z)
+	if graceStr := getEnv("TOKEN_ROTATION_GRACE_UNTIL", ""); graceStr != "" {
+		parsed, err := time.Parse(time.RFC3339, graceStr)
+		if err != nil {
+			l.problemf("TOKEN_ROTATION_GRACE_UNTIL must be an RFC3339 timestamp, got %q", graceStr)
+		}
+		config.TokenRotationGraceUntil = parsed
 	}
 
-	if config.BotAPIToken == "" {
-		return nil, fmt.Errorf("BOT_API_TOKEN is required for bot authentication")
+	// Заголовки безопасности с дефолтами для окружения
+	headerDefaults := defaultSecurityHeaders(config.Environment)
+	config.SecurityCSP = getHeaderEnv("SECURITY_CSP", headerDefaults.csp)
+	config.SecurityFrameOptions = getHeaderEnv("SECURITY_FRAME_OPTIONS", headerDefaults.frameOptions)
+	config.SecurityReferrerPolicy = getHeaderEnv("SECURITY_REFERRER_POLICY", "strict-origin-when-cross-origin")
+	config.SecurityPermissionsPolicy = getHeaderEnv("SECURITY_PERMISSIONS_POLICY", "geolocation=(), microphone=(), camera=()")
+	config.SecurityHSTS = getHeaderEnv("SECURITY_HSTS", "max-age=31536000; includeSubDomains; preload")
+
+	// Документация API по умолчанию открыта везде, кроме production
+	config.APIDocsEnabled = l.bool("API_DOCS_ENABLED", config.Environment != "production")
+	config.APIDocsUser = getEnv("API_DOCS_USER", "docs")
+	config.APIDocsPassword = getEnv("API_DOCS_PASSWORD", "")
+
+	// Если DATABASE_URL не задан, но есть SUPABASE_URL - строим DATABASE_URL из Supabase
+	if config.DatabaseURL == "" && config.SupabaseURL != "" {
+		config.DatabaseURL = buildSupabaseDatabaseURL(config.SupabaseURL)
+		// Не выводим DATABASE_URL даже в development режиме из соображений безопасности
 	}
 
-	if len(config.BotAPIToken) < 32 {
-		return nil, fmt.Errorf("BOT_API_TOKEN must be at least 32 characters long for security")
+	l.problems = append(l.problems, config.validate()...)
+	if len(l.problems) > 0 {
+		return nil, &ValidationError{Problems: l.problems}
 	}
 
 	return config, nil
@@ -126,41 +431,78 @@ func buildSupabaseDatabaseURL(supabaseURL string) string {
 	return fmt.Sprintf("postgresql://postgres:%s@db.%s.supabase.co:5432/postgres?sslmode=require&TimeZone=UTC", password, projectRef)
 }
 
-// getEnv gets environment variable or returns default value
+// securityHeaderDefaults - значения заголовков безопасности по умолчанию для окружения
+type securityHeaderDefaults struct {
+	csp          string
+	frameOptions string
+}
+
+// defaultSecurityHeaders возвращает заголовки по умолчанию
+// X-Frame-Options: DENY ломает встраивание в Telegram WebView, поэтому встраивание
+// ограничивается через CSP frame-ancestors
+func defaultSecurityHeaders(environment string) securityHeaderDefaults {
+	if environment == "production" {
+		return securityHeaderDefaults{
+			csp:          "default-src 'self'; frame-ancestors 'self' https://web.telegram.org https://*.telegram.org",
+			frameOptions: "",
+		}
+	}
+	// В development разрешаем встраивание локальным инструментам
+	return securityHeaderDefaults{
+		csp:          "default-src 'self'; frame-ancestors 'self' http://localhost:* https://web.telegram.org https://*.telegram.org",
+		frameOptions: "",
+	}
+}
+
+// getHeaderEnv gets header value from environment; "off" disables the header
+func getHeaderEnv(key, defaultValue string) string {
+	value := getEnv(key, defaultValue)
+	if strings.EqualFold(value, "off") {
+		return ""
+	}
+	return value
+}
+
+// getEnv gets environment variable, then config file value, or returns default value
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
+	if value := fileValues[key]; value != "" {
+		return value
+	}
 	return defaultValue
 }
 
-// parseInt64WithDefault parses string to int64 or returns default value
-func parseInt64WithDefault(value string, defaultValue int64) int64 {
-	if value == "" {
-		return defaultValue
-	}
-	parsed, err := strconv.ParseInt(value, 10, 64)
-	if err != nil {
-		return defaultValue
+// parseList парсит список значений, разделённых запятыми; пустые элементы пропускаются
+func parseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
 	}
-	return parsed
+	return items
 }
 
-// maskPassword скрывает пароль в connection string для безопасного вывода
-func maskPassword(connStr string) string {
-	// Находим пароль между postgres: и @
-	parts := strings.Split(connStr, "postgres:")
-	if len(parts) < 2 {
-		return connStr
-	}
+// OIDCEnabled reports whether login through the OIDC provider is configured
+func (c *Config) OIDCEnabled() bool {
+	return c.OIDCIssuerURL != ""
+}
 
-	afterUser := parts[1]
-	atIndex := strings.Index(afterUser, "@")
-	if atIndex == -1 {
-		return connStr
-	}
+// RentalsEnabled reports whether the public rental request form is configured
+func (c *Config) RentalsEnabled() bool {
+	return c.CaptchaSecret != ""
+}
 
-	return "postgresql://postgres:***@" + afterUser[atIndex+1:]
+// SMTPEnabled reports whether email is configured
+func (c *Config) SMTPEnabled() bool {
+	return c.SMTPHost != ""
+}
+
+// TLSEnabled reports whether the server terminates TLS itself
+func (c *Config) TLSEnabled() bool {
+	return c.TLSCertFile != "" || len(c.TLSAutocertDomains) > 0
 }
 
 // parseAllowedOrigins парсит строку разрешённых origins, разделённых запятыми
@@ -187,3 +529,68 @@ func parseAllowedOrigins(originsStr string) []string {
 
 	return result
 }
+
+// parseRouteRateLimits парсит политики вида "METHOD /path=N/window", разделённые запятыми
+// Пример: "POST /api/bookings=10/1m,GET /api/bookings/calendar=120/1m"
+func parseRouteRateLimits(value string) ([]RouteRateLimit, error) {
+	var limits []RouteRateLimit
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		route, limit, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("%q: expected METHOD /path=N/window", entry)
+		}
+
+		method, path, ok := strings.Cut(strings.TrimSpace(route), " ")
+		if !ok || !strings.HasPrefix(strings.TrimSpace(path), "/") {
+			return nil, fmt.Errorf("%q: expected METHOD /path", route)
+		}
+
+		countStr, windowStr, ok := strings.Cut(limit, "/")
+		if !ok {
+			return nil, fmt.Errorf("%q: expected N/window", limit)
+		}
+
+		count, err := strconv.Atoi(strings.TrimSpace(countStr))
+		if err != nil || count <= 0 {
+			return nil, fmt.Errorf("%q: invalid request count", limit)
+		}
+
+		window, err := time.ParseDuration(strings.TrimSpace(windowStr))
+		if err != nil || window <= 0 {
+			return nil, fmt.Errorf("%q: invalid window", limit)
+		}
+
+		limits = append(limits, RouteRateLimit{
+			Method:   strings.ToUpper(strings.TrimSpace(method)),
+			Path:     strings.TrimSpace(path),
+			Requests: count,
+			Window:   window,
+		})
+	}
+	return limits, nil
+}
+
+// parseHeaders парсит заголовки вида "Name: value", разделённые запятыми
+// Пример: "X-Source: space-backend, X-Env: staging"
+func parseHeaders(value string) (map[string]string, error) {
+	headers := map[string]string{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, headerValue, ok := strings.Cut(entry, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("%q: expected Name: value", entry)
+		}
+		headers[name] = strings.TrimSpace(headerValue)
+	}
+	return headers, nil
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// fileValues - значения из YAML-файла конфигурации, ключи - имена переменных окружения
+// Переменные окружения имеют приоритет над файлом (см. getEnv)
+var fileValues = map[string]string{}
+
+// loadFile читает YAML-файл конфигурации
+// Вложенные ключи соответствуют переменным окружения: rate_limit.user_rpm -> RATE_LIMIT_USER_RPM
+func loadFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	values := map[string]string{}
+	if err := flattenYAML("", raw, values); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// flattenYAML разворачивает дерево YAML в плоские ключи в стиле переменных окружения
+// Списки скаляров склеиваются через запятую (allowed_origins), списки объектов
+// поддерживаются для структурированных настроек вроде rate_limit.routes
+func flattenYAML(prefix string, value interface{}, out map[string]string) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			name := strings.ToUpper(key)
+			if prefix != "" {
+				name = prefix + "_" + name
+			}
+			if err := flattenYAML(name, child, out); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			switch entry := item.(type) {
+			case map[string]interface{}:
+				formatted, err := formatYAMLListItem(prefix, entry)
+				if err != nil {
+					return err
+				}
+				items = append(items, formatted)
+			default:
+				items = append(items, fmt.Sprint(entry))
+			}
+		}
+		out[prefix] = strings.Join(items, ",")
+	case nil:
+		// Пустое значение - используем значение по умолчанию
+	default:
+		out[prefix] = fmt.Sprint(v)
+	}
+	return nil
+}
+
+// formatYAMLListItem приводит объект из списка к строковому формату соответствующей переменной
+func formatYAMLListItem(key string, item map[string]interface{}) (string, error) {
+	switch key {
+	case "RATE_LIMIT_ROUTES":
+		// - {method: POST, path: /api/bookings, requests: 10, window: 1m} -> "POST /api/bookings=10/1m"
+		for _, field := range []string{"method", "path", "requests", "window"} {
+			if _, ok := item[field]; !ok {
+				return "", fmt.Errorf("rate_limit.routes: missing %q", field)
+			}
+		}
+		return fmt.Sprintf("%v %v=%v/%v", item["method"], item["path"], item["requests"], item["window"]), nil
+	default:
+		fields := make([]string, 0, len(item))
+		for field := range item {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		return "", fmt.Errorf("%s: list of objects is not supported (fields: %s)", strings.ToLower(key), strings.Join(fields, ", "))
+	}
+}
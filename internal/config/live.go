@@ -0,0 +1,117 @@
+package config
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// reloadableFields - параметры, которые безопасно менять без перезапуска
+// Остальные изменения (БД, токены, порт) требуют рестарта и при reload игнорируются
+// Флаги функций применяются подписчиками OnReload: маршруты документации и pprof зарегистрированы
+// всегда и отвечают 404, пока флаг выключен
+var reloadableFields = map[string]bool{
+	"AllowedOrigins":       true,
+	"AllowedChatID":        true,
+	"BotWebhookURL":        true,
+	"BotWebhookPath":       true,
+	"BotWebhookRetries":    true,
+	"BotWebhookBackoff":    true,
+	"BotWebhookHeaders":    true,
+	"RateLimitRPM":         true,
+	"RateLimitUserRPM":     true,
+	"RouteRateLimits":      true,
+	"APIDocsEnabled":       true,
+	"PprofEnabled":         true,
+	"ContentFilterEnabled": true,
+	"PurgeDryRun":          true,
+}
+
+// ReloadResult описывает результат перезагрузки конфигурации
+type ReloadResult struct {
+	Applied         []string `json:"applied"`          // Изменённые и применённые параметры
+	RequiresRestart []string `json:"requires_restart"` // Изменённые, но не применённые параметры
+}
+
+// Live хранит текущую конфигурацию и позволяет перезагружать безопасное подмножество
+// параметров (SIGHUP или admin endpoint) без остановки сервера
+// Переменные окружения процесса не меняются после запуска, поэтому для перезагружаемых
+// параметров используйте YAML-файл (--config)
+type Live struct {
+	current    atomic.Pointer[Config]
+	configPath string
+
+	mu        sync.Mutex // сериализует Reload и регистрацию подписчиков
+	listeners []func(*Config)
+}
+
+// NewLive creates a live config holder
+func NewLive(cfg *Config, configPath string) *Live {
+	l := &Live{configPath: configPath}
+	l.current.Store(cfg)
+	return l
+}
+
+// Get returns the current configuration snapshot; it must not be modified
+func (l *Live) Get() *Config {
+	return l.current.Load()
+}
+
+// AllowedOrigins returns the current CORS allowed origins
+func (l *Live) AllowedOrigins() []string {
+	return l.Get().AllowedOrigins
+}
+
+// AllowedChatID returns the current Telegram group chat ID
+func (l *Live) AllowedChatID() int64 {
+	return l.Get().AllowedChatID
+}
+
+// OnReload registers a callback invoked with the new configuration after each reload
+func (l *Live) OnReload(fn func(*Config)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.listeners = append(l.listeners, fn)
+}
+
+// Reload loads and validates the configuration again and applies the reloadable fields
+// При ошибке валидации текущая конфигурация остаётся без изменений
+func (l *Live) Reload() (*ReloadResult, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	loaded, err := Load(l.configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	current := l.Get()
+	next := *current
+	result := &ReloadResult{Applied: []string{}, RequiresRestart: []string{}}
+
+	// Копируем только безопасные поля, остальные изменения лишь сообщаем
+	currentValue := reflect.ValueOf(current).Elem()
+	loadedValue := reflect.ValueOf(loaded).Elem()
+	nextValue := reflect.ValueOf(&next).Elem()
+	for i := 0; i < currentValue.NumField(); i++ {
+		name := currentValue.Type().Field(i).Name
+		if reflect.DeepEqual(currentValue.Field(i).Interface(), loadedValue.Field(i).Interface()) {
+			continue
+		}
+		if reloadableFields[name] {
+			nextValue.Field(i).Set(loadedValue.Field(i))
+			result.Applied = append(result.Applied, name)
+		} else {
+			result.RequiresRestart = append(result.RequiresRestart, name)
+		}
+	}
+
+	if len(result.Applied) > 0 {
+		l.current.Store(&next)
+		for _, fn := range l.listeners {
+			fn(&next)
+		}
+	}
+
+	return result, nil
+}
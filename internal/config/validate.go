@@ -0,0 +1,694 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Допустимые диапазоны TTL auth_date в секундах
+const (
+	minAuthDateTTL            = 60
+	maxAuthDateTTLMiniApp     = 7 * 24 * 3600  // 7 дней
+	maxAuthDateTTLLoginWidget = 90 * 24 * 3600 // 90 дней
+)
+
+// telegramBotTokenRegex - формат токена от BotFather: <bot_id>:<secret>
+var telegramBotTokenRegex = regexp.MustCompile(`^\d+:[A-Za-z0-9_-]{30,}$`)
+
+var currencyRegex = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// ValidationError содержит все найденные проблемы конфигурации
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid configuration (%d problems):\n  - %s", len(e.Problems), strings.Join(e.Problems, "\n  - "))
+}
+
+// loader читает значения с разбором типов и накапливает ошибки вместо выхода на первой
+type loader struct {
+	problems []string
+}
+
+func (l *loader) problemf(format string, args ...interface{}) {
+	l.problems = append(l.problems, fmt.Sprintf(format, args...))
+}
+
+// int64 читает целое число; пустое значение заменяется значением по умолчанию
+func (l *loader) int64(key string, defaultValue int64) int64 {
+	value := getEnv(key, "")
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil {
+		l.problemf("%s must be an integer, got %q", key, value)
+		return defaultValue
+	}
+	return parsed
+}
+
+// duration читает длительность в формате Go (5s, 1m, 12h)
+func (l *loader) duration(key string, defaultValue time.Duration) time.Duration {
+	value := getEnv(key, "")
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		l.problemf("%s must be a duration like 30s or 5m, got %q", key, value)
+		return defaultValue
+	}
+	return parsed
+}
+
+// bool читает логическое значение (true/false, 1/0)
+func (l *loader) bool(key string, defaultValue bool) bool {
+	value := getEnv(key, "")
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.ParseBool(strings.TrimSpace(value))
+	if err != nil {
+		l.problemf("%s must be true or false, got %q", key, value)
+		return defaultValue
+	}
+	return parsed
+}
+
+// validate проверяет значения и связи между параметрами
+func (c *Config) validate() []string {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	switch c.Environment {
+	case "development", "staging", "production":
+	default:
+		add("ENVIRONMENT must be one of development, staging, production, got %q", c.Environment)
+	}
+
+	if port, err := strconv.Atoi(c.ServerPort); err != nil || port < 1 || port > 65535 {
+		add("SERVER_PORT must be a port number, got %q", c.ServerPort)
+	}
+
+	validateTLS(c, add)
+
+	// JWT Secret validation - критически важно для безопасности
+	if c.JWTSecret == "" {
+		add("JWT_SECRET is required and must not be empty")
+	} else if len(c.JWTSecret) < 32 {
+		add("JWT_SECRET must be at least 32 characters long for security")
+	}
+
+	switch {
+	case c.DatabaseURL == "":
+		add("DATABASE_URL or SUPABASE_URL is required")
+	case strings.Contains(c.DatabaseURL, "[YOUR-PASSWORD]"):
+		add("SUPABASE_DB_PASSWORD is required when DATABASE_URL is built from SUPABASE_URL")
+	case strings.HasPrefix(c.DatabaseURL, "sqlite://"):
+		// SQLite - только для локальной разработки и тестов
+		if c.Environment == "production" {
+			add("DATABASE_URL: sqlite is for development and tests only, use PostgreSQL in production")
+		}
+		if c.DatabaseReplicaURL != "" {
+			add("DATABASE_REPLICA_URL is not supported with sqlite")
+		}
+	default:
+		if err := validateDatabaseURL(c.DatabaseURL); err != nil {
+			add("DATABASE_URL: %v", err)
+		}
+	}
+	if c.DatabaseReplicaURL != "" {
+		if err := validateDatabaseURL(c.DatabaseReplicaURL); err != nil {
+			add("DATABASE_REPLICA_URL: %v", err)
+		}
+	}
+
+	if c.TelegramBotToken == "" {
+		add("TELEGRAM_BOT_TOKEN is required")
+	} else if !telegramBotTokenRegex.MatchString(c.TelegramBotToken) {
+		add("TELEGRAM_BOT_TOKEN must look like <bot_id>:<secret> as issued by BotFather")
+	}
+
+	if c.BotAPIToken == "" {
+		add("BOT_API_TOKEN is required for bot authentication")
+	} else if len(c.BotAPIToken) < 32 {
+		add("BOT_API_TOKEN must be at least 32 characters long for security")
+	}
+
+	// В production без группы все защищённые запросы завершались бы ошибкой
+	if c.Environment == "production" && c.AllowedChatID == 0 {
+		add("ALLOWED_CHAT_ID is required in production")
+	}
+
+	if err := validateHTTPURL(c.BotWebhookURL); err != nil {
+		add("BOT_WEBHOOK_URL: %v", err)
+	}
+	if !strings.HasPrefix(c.BotWebhookPath, "/") {
+		add("BOT_WEBHOOK_PATH must start with /, got %q", c.BotWebhookPath)
+	}
+	if c.BotWebhookTimeout <= 0 {
+		add("BOT_WEBHOOK_TIMEOUT must be positive, got %s", c.BotWebhookTimeout)
+	}
+	if c.BotWebhookRetries < 0 || c.BotWebhookRetries > 10 {
+		add("BOT_WEBHOOK_RETRIES must be between 0 and 10, got %d", c.BotWebhookRetries)
+	}
+	if c.BotWebhookBackoff < 0 {
+		add("BOT_WEBHOOK_BACKOFF must not be negative, got %s", c.BotWebhookBackoff)
+	}
+	if c.OutboundWorkers <= 0 {
+		add("OUTBOUND_WORKERS must be positive, got %d", c.OutboundWorkers)
+	}
+	if c.OutboundQueueSize < 0 {
+		add("OUTBOUND_QUEUE_SIZE must not be negative, got %d", c.OutboundQueueSize)
+	}
+
+	for _, origin := range c.AllowedOrigins {
+		if err := validateOrigin(origin); err != nil {
+			add("ALLOWED_ORIGINS: %q %v", origin, err)
+		}
+	}
+
+	if c.AuthDateTTLMiniApp < minAuthDateTTL || c.AuthDateTTLMiniApp > maxAuthDateTTLMiniApp {
+		add("AUTH_DATE_TTL_MINIAPP must be between %d and %d seconds, got %d", minAuthDateTTL, maxAuthDateTTLMiniApp, c.AuthDateTTLMiniApp)
+	}
+	if c.AuthDateTTLLoginWidget < minAuthDateTTL || c.AuthDateTTLLoginWidget > maxAuthDateTTLLoginWidget {
+		add("AUTH_DATE_TTL_LOGIN_WIDGET must be between %d and %d seconds, got %d", minAuthDateTTL, maxAuthDateTTLLoginWidget, c.AuthDateTTLLoginWidget)
+	}
+
+	switch c.LogLevel {
+	case "debug", "info", "warn", "error":
+	default:
+		add("LOG_LEVEL must be one of debug, info, warn, error, got %q", c.LogLevel)
+	}
+	switch c.LogFormat {
+	case "", "json", "text":
+	default:
+		add("LOG_FORMAT must be json or text, got %q", c.LogFormat)
+	}
+
+	if c.RateLimitRPM <= 0 {
+		add("RATE_LIMIT_RPM must be positive, got %d", c.RateLimitRPM)
+	}
+	if c.RateLimitUserRPM <= 0 {
+		add("RATE_LIMIT_USER_RPM must be positive, got %d", c.RateLimitUserRPM)
+	}
+	if c.AuditRetentionDays < 0 {
+		add("AUDIT_RETENTION_DAYS must not be negative, got %d", c.AuditRetentionDays)
+	}
+	if c.SoftDeleteRetentionDays < 0 {
+		add("SOFT_DELETE_RETENTION_DAYS must not be negative, got %d", c.SoftDeleteRetentionDays)
+	}
+	if c.RateLimitCleanupInterval <= 0 {
+		add("RATE_LIMIT_CLEANUP_INTERVAL must be positive, got %s", c.RateLimitCleanupInterval)
+	}
+	if c.MembershipCacheTTL <= 0 {
+		add("MEMBERSHIP_CACHE_TTL must be positive, got %s", c.MembershipCacheTTL)
+	}
+	if c.MembershipCacheCleanupInterval <= 0 {
+		add("MEMBERSHIP_CACHE_CLEANUP_INTERVAL must be positive, got %s", c.MembershipCacheCleanupInterval)
+	}
+	if c.PublicCacheTTL < 0 {
+		add("PUBLIC_CACHE_TTL must not be negative, got %s", c.PublicCacheTTL)
+	}
+	if c.RoomCacheTTL < 0 {
+		add("ROOM_CACHE_TTL must not be negative, got %s", c.RoomCacheTTL)
+	}
+	if c.UserpicSyncInterval < 0 {
+		add("USERPIC_SYNC_INTERVAL must not be negative, got %s", c.UserpicSyncInterval)
+	}
+	if c.BroadcastMinInterval < 0 {
+		add("BROADCAST_MIN_INTERVAL must not be negative, got %s", c.BroadcastMinInterval)
+	}
+	if c.RequestTimeout < 0 {
+		add("REQUEST_TIMEOUT must not be negative, got %s", c.RequestTimeout)
+	}
+	if c.DBConnectMaxWait < 0 {
+		add("DB_CONNECT_MAX_WAIT must not be negative, got %s", c.DBConnectMaxWait)
+	}
+	if c.DBConnectBackoff <= 0 {
+		add("DB_CONNECT_BACKOFF must be positive, got %s", c.DBConnectBackoff)
+	}
+	// В production спецификация раскрывает все маршруты, поэтому без пароля её не отдаём
+	if c.APIDocsEnabled && c.Environment == "production" && c.APIDocsPassword == "" {
+		add("API_DOCS_PASSWORD is required when API_DOCS_ENABLED is set in production")
+	}
+	if c.APIDocsPassword != "" && len(c.APIDocsPassword) < 16 {
+		add("API_DOCS_PASSWORD must be at least 16 characters long")
+	}
+
+	if c.ShutdownDrainDelay < 0 {
+		add("SHUTDOWN_DRAIN_DELAY must not be negative, got %s", c.ShutdownDrainDelay)
+	}
+	if c.BookingReminderLead < 0 {
+		add("BOOKING_REMINDER_LEAD must not be negative, got %s", c.BookingReminderLead)
+	}
+	switch c.MultiDayBookingPolicy {
+	case "", "allow", "reject", "admin":
+	default:
+		add("MULTI_DAY_BOOKING_POLICY must be one of: allow, reject, admin, got %q", c.MultiDayBookingPolicy)
+	}
+	if c.MultiDayBookingMaxDuration < 0 {
+		add("MULTI_DAY_BOOKING_MAX_DURATION must not be negative, got %s", c.MultiDayBookingMaxDuration)
+	}
+	if c.AbuseDetectionInterval < 0 {
+		add("ABUSE_DETECTION_INTERVAL must not be negative, got %s", c.AbuseDetectionInterval)
+	}
+	if c.AbuseDetectionInterval > 0 && c.AbuseWindow <= 0 {
+		add("ABUSE_WINDOW must be positive, got %s", c.AbuseWindow)
+	}
+	if c.AbuseMaxCancellations < 0 {
+		add("ABUSE_MAX_CANCELLATIONS must not be negative, got %d", c.AbuseMaxCancellations)
+	}
+	if c.AbuseMaxLongBookingStreak < 0 {
+		add("ABUSE_MAX_LONG_BOOKING_STREAK must not be negative, got %d", c.AbuseMaxLongBookingStreak)
+	}
+	if c.AbuseMaxLongBookingStreak > 0 && c.AbuseLongBooking <= 0 {
+		add("ABUSE_LONG_BOOKING must be positive, got %s", c.AbuseLongBooking)
+	}
+	if c.MaxTitleLength < 0 {
+		add("MAX_TITLE_LENGTH must not be negative, got %d", c.MaxTitleLength)
+	}
+	if c.MaxDescriptionLength < 0 {
+		add("MAX_DESCRIPTION_LENGTH must not be negative, got %d", c.MaxDescriptionLength)
+	}
+	if c.MaxNameLength < 0 {
+		add("MAX_NAME_LENGTH must not be negative, got %d", c.MaxNameLength)
+	}
+	if c.AbuseAutoBookingLimit < 0 {
+		add("ABUSE_AUTO_BOOKING_LIMIT must not be negative, got %d", c.AbuseAutoBookingLimit)
+	}
+	if c.CleaningJobInterval < 0 {
+		add("CLEANING_JOB_INTERVAL must not be negative, got %s", c.CleaningJobInterval)
+	}
+	switch c.AttendanceSource {
+	case "", "auto", "participants", "checkins":
+	default:
+		add("ATTENDANCE_SOURCE must be one of: auto, participants, checkins, got %q", c.AttendanceSource)
+	}
+	if c.RightSizeMaxFillPercent < 0 || c.RightSizeMaxFillPercent > 100 {
+		add("RIGHTSIZE_MAX_FILL_PERCENT must be between 0 and 100, got %d", c.RightSizeMaxFillPercent)
+	}
+	if c.RightSizeMinBookings < 0 {
+		add("RIGHTSIZE_MIN_BOOKINGS must not be negative, got %d", c.RightSizeMinBookings)
+	}
+	if c.PriceCurrency != "" && !currencyRegex.MatchString(c.PriceCurrency) {
+		add("PRICE_CURRENCY must be an ISO 4217 code such as RUB, got %q", c.PriceCurrency)
+	}
+	if _, err := time.LoadLocation(c.OfficeTimezone); err != nil {
+		add("OFFICE_TIMEZONE must be an IANA timezone such as Europe/Moscow, got %q", c.OfficeTimezone)
+	}
+	if c.HolidayAPIURL != "" {
+		if err := validateHTTPURL(c.HolidayAPIURL); err != nil {
+			add("HOLIDAY_API_URL %v", err)
+		}
+	}
+	if c.HolidayCountry != "" && !isCountryCode(c.HolidayCountry) {
+		add("HOLIDAY_COUNTRY must be a two-letter country code, got %q", c.HolidayCountry)
+	}
+	if c.OIDCEnabled() {
+		if err := validateHTTPURL(c.OIDCIssuerURL); err != nil {
+			add("OIDC_ISSUER_URL %v", err)
+		}
+		if err := validateHTTPURL(c.OIDCRedirectURL); err != nil {
+			add("OIDC_REDIRECT_URL %v", err)
+		}
+		if err := validateHTTPURL(c.OIDCPostLoginURL); err != nil {
+			add("OIDC_POST_LOGIN_URL %v", err)
+		}
+		if c.OIDCClientID == "" || c.OIDCClientSecret == "" {
+			add("OIDC_CLIENT_ID and OIDC_CLIENT_SECRET are required when OIDC_ISSUER_URL is set")
+		}
+		if c.OIDCSessionTTL <= 0 {
+			add("OIDC_SESSION_TTL must be positive, got %s", c.OIDCSessionTTL)
+		}
+		// Без ограничения по домену войти смог бы любой владелец аккаунта Google
+		if c.Environment == "production" && len(c.OIDCAllowedDomains) == 0 {
+			add("OIDC_ALLOWED_DOMAINS is required when OIDC is enabled in production")
+		}
+	}
+
+	switch c.DoorAccessDriver {
+	case "":
+	case "http":
+		if err := validateHTTPURL(c.DoorAccessURL); err != nil {
+			add("DOOR_ACCESS_URL %v", err)
+		}
+	default:
+		add("DOOR_ACCESS_DRIVER must be empty or http, got %q", c.DoorAccessDriver)
+	}
+	if c.DoorAccessLead < 0 {
+		add("DOOR_ACCESS_LEAD must not be negative, got %s", c.DoorAccessLead)
+	}
+	if c.DoorAccessDriver != "" && c.DoorAccessTimeout <= 0 {
+		add("DOOR_ACCESS_TIMEOUT must be positive, got %s", c.DoorAccessTimeout)
+	}
+	if c.MQTTURL != "" {
+		if err := validateMQTTURL(c.MQTTURL); err != nil {
+			add("MQTT_URL %v", err)
+		}
+		if c.MQTTTimeout <= 0 {
+			add("MQTT_TIMEOUT must be positive, got %s", c.MQTTTimeout)
+		}
+		if c.MQTTClientID == "" {
+			add("MQTT_CLIENT_ID is required when MQTT is enabled")
+		}
+		if c.MQTTTopicPrefix == "" || strings.ContainsAny(c.MQTTTopicPrefix, "+#") || strings.HasSuffix(c.MQTTTopicPrefix, "/") {
+			add("MQTT_TOPIC_PREFIX must be a non-empty topic without wildcards or trailing slash, got %q", c.MQTTTopicPrefix)
+		}
+	}
+	if c.WidgetToken != "" {
+		if len(c.WidgetToken) < 16 {
+			add("WIDGET_TOKEN must be at least 16 characters long")
+		}
+		if c.WidgetOrigin == "" {
+			add("WIDGET_ORIGIN is required when WIDGET_TOKEN is set")
+		} else if err := validateOrigin(c.WidgetOrigin); err != nil {
+			add("WIDGET_ORIGIN: %q %v", c.WidgetOrigin, err)
+		}
+	}
+	if c.RentalsEnabled() {
+		switch c.CaptchaProvider {
+		case "turnstile", "hcaptcha", "recaptcha":
+		default:
+			add("CAPTCHA_PROVIDER must be one of: turnstile, hcaptcha, recaptcha, got %q", c.CaptchaProvider)
+		}
+		if c.CaptchaVerifyURL != "" {
+			if err := validateHTTPURL(c.CaptchaVerifyURL); err != nil {
+				add("CAPTCHA_VERIFY_URL %v", err)
+			}
+		}
+	}
+	if c.ContentFilterEnabled {
+		if c.ContentFilterMaxEmoji < 0 {
+			add("CONTENT_FILTER_MAX_EMOJI must not be negative, got %d", c.ContentFilterMaxEmoji)
+		}
+		if c.ContentFilterMaxRepeat < 0 {
+			add("CONTENT_FILTER_MAX_REPEAT must not be negative, got %d", c.ContentFilterMaxRepeat)
+		}
+		if c.ContentFilterMaxWordLength < 0 {
+			add("CONTENT_FILTER_MAX_WORD_LENGTH must not be negative, got %d", c.ContentFilterMaxWordLength)
+		}
+	}
+	if c.SMTPEnabled() {
+		if port, err := strconv.Atoi(c.SMTPPort); err != nil || port < 1 || port > 65535 {
+			add("SMTP_PORT must be a port number, got %q", c.SMTPPort)
+		}
+		if c.SMTPFrom == "" {
+			add("SMTP_FROM is required when SMTP_HOST is set")
+		} else if _, err := mail.ParseAddress(c.SMTPFrom); err != nil {
+			add("SMTP_FROM must be an email address, got %q", c.SMTPFrom)
+		}
+	}
+	if c.DBSlowQueryThreshold < 0 {
+		add("DB_SLOW_QUERY_THRESHOLD must not be negative, got %s", c.DBSlowQueryThreshold)
+	}
+	if c.DBStatementTimeout < 0 {
+		add("DB_STATEMENT_TIMEOUT must not be negative, got %s", c.DBStatementTimeout)
+	} else if c.DBStatementTimeout > 0 && c.DBStatementTimeout < time.Millisecond {
+		// statement_timeout задаётся в миллисекундах: меньшее значение означало бы 0 - без ограничения
+		add("DB_STATEMENT_TIMEOUT must be at least 1ms, got %s", c.DBStatementTimeout)
+	}
+
+	// Ротация токенов: предыдущий токен и окно grace-периода задаются только вместе
+	hasPrevious := c.TelegramBotTokenPrevious != "" || c.BotAPITokenPrevious != ""
+	if hasPrevious && c.TokenRotationGraceUntil.IsZero() {
+		add("TOKEN_ROTATION_GRACE_UNTIL is required when a previous token is set")
+	}
+	if !hasPrevious && !c.TokenRotationGraceUntil.IsZero() {
+		add("TOKEN_ROTATION_GRACE_UNTIL is set but neither TELEGRAM_BOT_TOKEN_PREVIOUS nor BOT_API_TOKEN_PREVIOUS is")
+	}
+	if c.TelegramBotTokenPrevious != "" && c.TelegramBotTokenPrevious == c.TelegramBotToken {
+		add("TELEGRAM_BOT_TOKEN_PREVIOUS must differ from TELEGRAM_BOT_TOKEN")
+	}
+	if c.BotAPITokenPrevious != "" && c.BotAPITokenPrevious == c.BotAPIToken {
+		add("BOT_API_TOKEN_PREVIOUS must differ from BOT_API_TOKEN")
+	}
+
+	return problems
+}
+
+// validateTLS проверяет, что выбран ровно один источник сертификатов и порт редиректа
+func validateTLS(c *Config, add func(format string, args ...interface{})) {
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		add("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	if c.TLSCertFile != "" && len(c.TLSAutocertDomains) > 0 {
+		add("TLS_CERT_FILE and TLS_AUTOCERT_DOMAINS are mutually exclusive")
+	}
+	if len(c.TLSAutocertDomains) > 0 && c.TLSAutocertCacheDir == "" {
+		add("TLS_AUTOCERT_CACHE_DIR is required with TLS_AUTOCERT_DOMAINS")
+	}
+	for _, domain := range c.TLSAutocertDomains {
+		if strings.ContainsAny(domain, "/:*") {
+			add("TLS_AUTOCERT_DOMAINS: %q must be a plain host name", domain)
+		}
+	}
+
+	if c.HTTPRedirectPort == "" {
+		return
+	}
+	if !c.TLSEnabled() {
+		add("HTTP_REDIRECT_PORT requires TLS_CERT_FILE or TLS_AUTOCERT_DOMAINS")
+	}
+	if port, err := strconv.Atoi(c.HTTPRedirectPort); err != nil || port < 1 || port > 65535 {
+		add("HTTP_REDIRECT_PORT must be a port number, got %q", c.HTTPRedirectPort)
+	} else if c.HTTPRedirectPort == c.ServerPort {
+		add("HTTP_REDIRECT_PORT must differ from SERVER_PORT")
+	}
+}
+
+// validateDatabaseURL принимает URL (postgres://...) и DSN вида "host=... user=..."
+func validateDatabaseURL(value string) error {
+	if !strings.Contains(value, "://") {
+		if !strings.Contains(value, "=") {
+			return fmt.Errorf("expected postgres:// URL or key=value DSN")
+		}
+		return nil
+	}
+
+	u, err := url.Parse(value)
+	if err != nil {
+		return fmt.Errorf("invalid URL")
+	}
+	if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+		return fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("host is required")
+	}
+	return nil
+}
+
+// validateHTTPURL проверяет абсолютный http(s) URL
+func validateHTTPURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("must be an absolute http(s) URL, got %q", value)
+	}
+	return nil
+}
+
+// isCountryCode проверяет двухбуквенный код страны ISO 3166-1
+func isCountryCode(value string) bool {
+	if len(value) != 2 {
+		return false
+	}
+	for _, r := range value {
+		if (r < 'A' || r > 'Z') && (r < 'a' || r > 'z') {
+			return false
+		}
+	}
+	return true
+}
+
+// validateOrigin проверяет origin в формате scheme://host[:port] без пути и wildcard
+func validateOrigin(origin string) error {
+	if strings.Contains(origin, "*") {
+		return fmt.Errorf("must not contain wildcards")
+	}
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("must be scheme://host[:port]")
+	}
+	if (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return fmt.Errorf("must not contain path, query or credentials")
+	}
+	return nil
+}
+
+// LogValue implements slog.LogValuer: the effective configuration with secrets redacted
+// Позволяет безопасно логировать конфигурацию целиком: logger.Info("...", "config", cfg)
+func (c *Config) LogValue() slog.Value {
+	routes := make([]string, 0, len(c.RouteRateLimits))
+	for _, r := range c.RouteRateLimits {
+		routes = append(routes, fmt.Sprintf("%s %s=%d/%s", r.Method, r.Path, r.Requests, r.Window))
+	}
+
+	graceUntil := ""
+	if !c.TokenRotationGraceUntil.IsZero() {
+		graceUntil = c.TokenRotationGraceUntil.Format(time.RFC3339)
+	}
+
+	return slog.GroupValue(
+		slog.String("environment", c.Environment),
+		slog.String("server_port", c.ServerPort),
+		slog.String("tls_cert_file", c.TLSCertFile),
+		slog.String("tls_key_file", c.TLSKeyFile),
+		slog.String("tls_autocert_domains", strings.Join(c.TLSAutocertDomains, ",")),
+		slog.String("tls_autocert_email", c.TLSAutocertEmail),
+		slog.String("tls_autocert_cache_dir", c.TLSAutocertCacheDir),
+		slog.String("http_redirect_port", c.HTTPRedirectPort),
+		slog.String("database_url", redactURL(c.DatabaseURL)),
+		slog.String("database_replica_url", redactURL(c.DatabaseReplicaURL)),
+		slog.String("telegram_bot_token", redactSecret(c.TelegramBotToken)),
+		slog.String("telegram_bot_token_previous", redactSecret(c.TelegramBotTokenPrevious)),
+		slog.String("bot_api_token", redactSecret(c.BotAPIToken)),
+		slog.String("bot_api_token_previous", redactSecret(c.BotAPITokenPrevious)),
+		slog.String("token_rotation_grace_until", graceUntil),
+		slog.String("jwt_secret", redactSecret(c.JWTSecret)),
+		slog.String("supabase_url", c.SupabaseURL),
+		slog.String("supabase_key", redactSecret(c.SupabaseKey)),
+		slog.Int64("allowed_chat_id", c.AllowedChatID),
+		slog.String("allowed_origins", strings.Join(c.AllowedOrigins, ",")),
+		slog.Int64("auth_date_ttl_miniapp", c.AuthDateTTLMiniApp),
+		slog.Int64("auth_date_ttl_login_widget", c.AuthDateTTLLoginWidget),
+		slog.String("bot_webhook_url", redactURL(c.BotWebhookURL)),
+		slog.String("bot_webhook_path", c.BotWebhookPath),
+		slog.Duration("bot_webhook_timeout", c.BotWebhookTimeout),
+		slog.Int("bot_webhook_retries", c.BotWebhookRetries),
+		slog.Duration("bot_webhook_backoff", c.BotWebhookBackoff),
+		slog.Int("bot_webhook_headers", len(c.BotWebhookHeaders)), // значения могут содержать секреты
+		slog.Bool("api_docs_enabled", c.APIDocsEnabled),
+		slog.String("api_docs_user", c.APIDocsUser),
+		slog.String("api_docs_password", redactSecret(c.APIDocsPassword)),
+		slog.Bool("pprof_enabled", c.PprofEnabled),
+		slog.Int("outbound_workers", c.OutboundWorkers),
+		slog.Int("outbound_queue_size", c.OutboundQueueSize),
+		slog.String("storage_path", c.StoragePath),
+		slog.String("data_pack_file", c.DataPackFile),
+		slog.String("log_level", c.LogLevel),
+		slog.String("log_format", c.LogFormat),
+		slog.Int("rate_limit_rpm", c.RateLimitRPM),
+		slog.Int("rate_limit_user_rpm", c.RateLimitUserRPM),
+		slog.Duration("rate_limit_cleanup_interval", c.RateLimitCleanupInterval),
+		slog.Duration("membership_cache_ttl", c.MembershipCacheTTL),
+		slog.Duration("membership_cache_cleanup_interval", c.MembershipCacheCleanupInterval),
+		slog.String("rate_limit_routes", strings.Join(routes, ",")),
+		slog.Int("audit_retention_days", c.AuditRetentionDays),
+		slog.Int("soft_delete_retention_days", c.SoftDeleteRetentionDays),
+		slog.Bool("purge_dry_run", c.PurgeDryRun),
+		slog.Duration("public_cache_ttl", c.PublicCacheTTL),
+		slog.Duration("room_cache_ttl", c.RoomCacheTTL),
+		slog.Duration("userpic_sync_interval", c.UserpicSyncInterval),
+		slog.Duration("broadcast_min_interval", c.BroadcastMinInterval),
+		slog.Duration("request_timeout", c.RequestTimeout),
+		slog.Duration("db_connect_max_wait", c.DBConnectMaxWait),
+		slog.Duration("db_connect_backoff", c.DBConnectBackoff),
+		slog.Duration("db_slow_query_threshold", c.DBSlowQueryThreshold),
+		slog.Duration("db_statement_timeout", c.DBStatementTimeout),
+		slog.Bool("db_prepare_stmt", c.DBPrepareStmt),
+		slog.Bool("db_skip_default_transaction", c.DBSkipDefaultTransaction),
+		slog.Duration("shutdown_drain_delay", c.ShutdownDrainDelay),
+		slog.Duration("booking_reminder_lead", c.BookingReminderLead),
+		slog.String("multi_day_booking_policy", c.MultiDayBookingPolicy),
+		slog.Duration("multi_day_booking_max_duration", c.MultiDayBookingMaxDuration),
+		slog.Int("max_title_length", c.MaxTitleLength),
+		slog.Int("max_description_length", c.MaxDescriptionLength),
+		slog.Int("max_name_length", c.MaxNameLength),
+		slog.Duration("abuse_detection_interval", c.AbuseDetectionInterval),
+		slog.Duration("abuse_window", c.AbuseWindow),
+		slog.Int("abuse_max_cancellations", c.AbuseMaxCancellations),
+		slog.Duration("abuse_long_booking", c.AbuseLongBooking),
+		slog.Int("abuse_max_long_booking_streak", c.AbuseMaxLongBookingStreak),
+		slog.Int("abuse_auto_booking_limit", c.AbuseAutoBookingLimit),
+		slog.Duration("cleaning_job_interval", c.CleaningJobInterval),
+		slog.String("attendance_source", c.AttendanceSource),
+		slog.Int("rightsize_max_fill_percent", c.RightSizeMaxFillPercent),
+		slog.Int("rightsize_min_bookings", c.RightSizeMinBookings),
+		slog.String("price_currency", c.PriceCurrency),
+		slog.String("office_timezone", c.OfficeTimezone),
+		slog.String("holiday_api_url", c.HolidayAPIURL),
+		slog.String("holiday_country", c.HolidayCountry),
+		slog.String("oidc_issuer_url", c.OIDCIssuerURL),
+		slog.String("oidc_client_id", c.OIDCClientID),
+		slog.String("oidc_client_secret", redactSecret(c.OIDCClientSecret)),
+		slog.String("oidc_redirect_url", c.OIDCRedirectURL),
+		slog.String("oidc_post_login_url", c.OIDCPostLoginURL),
+		slog.String("oidc_allowed_domains", strings.Join(c.OIDCAllowedDomains, ",")),
+		slog.Duration("oidc_session_ttl", c.OIDCSessionTTL),
+		slog.String("door_access_driver", c.DoorAccessDriver),
+		slog.String("door_access_url", redactURL(c.DoorAccessURL)),
+		slog.String("door_access_token", redactSecret(c.DoorAccessToken)),
+		slog.Duration("door_access_lead", c.DoorAccessLead),
+		slog.Duration("door_access_timeout", c.DoorAccessTimeout),
+		slog.String("mqtt_url", redactURL(c.MQTTURL)),
+		slog.String("mqtt_username", c.MQTTUsername),
+		slog.String("mqtt_password", redactSecret(c.MQTTPassword)),
+		slog.String("mqtt_client_id", c.MQTTClientID),
+		slog.String("mqtt_topic_prefix", c.MQTTTopicPrefix),
+		slog.Duration("mqtt_timeout", c.MQTTTimeout),
+		slog.String("widget_token", redactSecret(c.WidgetToken)),
+		slog.String("widget_origin", c.WidgetOrigin),
+		slog.String("captcha_provider", c.CaptchaProvider),
+		slog.String("captcha_secret", redactSecret(c.CaptchaSecret)),
+		slog.String("captcha_verify_url", c.CaptchaVerifyURL),
+		slog.String("smtp_host", c.SMTPHost),
+		slog.String("smtp_port", c.SMTPPort),
+		slog.String("smtp_username", c.SMTPUsername),
+		slog.String("smtp_password", redactSecret(c.SMTPPassword)),
+		slog.String("smtp_from", c.SMTPFrom),
+		slog.Bool("content_filter_enabled", c.ContentFilterEnabled),
+		slog.Int("content_filter_words", len(c.ContentFilterWords)),
+		slog.String("content_filter_words_file", c.ContentFilterWordsFile),
+		slog.Int("content_filter_max_emoji", c.ContentFilterMaxEmoji),
+		slog.Int("content_filter_max_repeat", c.ContentFilterMaxRepeat),
+		slog.Int("content_filter_max_word_length", c.ContentFilterMaxWordLength),
+		slog.String("security_csp", c.SecurityCSP),
+		slog.String("security_hsts", c.SecurityHSTS),
+	)
+}
+
+// redactSecret скрывает значение секрета, оставляя только признак его наличия
+func redactSecret(value string) string {
+	if value == "" {
+		return ""
+	}
+	return "***"
+}
+
+// redactURL скрывает пароль в URL для безопасного вывода
+// validateMQTTURL checks that the broker URL has a supported scheme and a host
+func validateMQTTURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("is not a valid URL: %w", err)
+	}
+	switch u.Scheme {
+	case "mqtt", "tcp", "mqtts", "ssl", "tls":
+	default:
+		return fmt.Errorf("must use mqtt:// or mqtts://, got %q", raw)
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("must include a host, got %q", raw)
+	}
+	return nil
+}
+
+func redactURL(value string) string {
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme == "" {
+		// DSN вида "host=... password=..." - не выводим целиком
+		return redactSecret(value)
+	}
+	return u.Redacted()
+}
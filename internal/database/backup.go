@@ -0,0 +1,412 @@
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BackupFormatVersion - версия формата JSON-выгрузки; растёт при несовместимых изменениях
+const BackupFormatVersion = 1
+
+// backupBatchSize - размер пачки при вставке строк во время импорта
+const backupBatchSize = 500
+
+// ErrDatabaseNotEmpty возвращается при импорте в базу, где уже есть данные
+var ErrDatabaseNotEmpty = errors.New("database is not empty, import requires an empty database")
+
+// Backup - переносимая выгрузка данных: комнаты, оборудование, инструкции, пользователи и бронирования
+// Идентификаторы сохраняются, поэтому связи между записями переносятся как есть
+// Soft-deleted строки тоже выгружаются: на них могут ссылаться бронирования
+// API-ключи, подписки и журнал аудита не выгружаются - они привязаны к конкретной установке;
+// команды тоже - их заново синхронизирует каталог (SCIM)
+type Backup struct {
+	Version      int                 `json:"version"`
+	ExportedAt   time.Time           `json:"exported_at"`
+	Users        []BackupUser        `json:"users"`
+	Rooms        []BackupRoom        `json:"rooms"`
+	Equipment    []BackupEquipment   `json:"equipment"`
+	Instructions []BackupInstruction `json:"instructions"`
+	Bookings     []BackupBooking     `json:"bookings"`
+}
+
+// BackupUser is a user record in a backup
+type BackupUser struct {
+	ID            uint            `json:"id"`
+	TelegramID    int64           `json:"telegram_id"`
+	Username      string          `json:"username,omitempty"`
+	FirstName     string          `json:"first_name,omitempty"`
+	LastName      string          `json:"last_name,omitempty"`
+	PhoneNumber   string          `json:"phone_number,omitempty"`
+	LanguageCode  string          `json:"language_code,omitempty"`
+	Role          models.UserRole `json:"role"`
+	Userpic       string          `json:"userpic,omitempty"`
+	About         string          `json:"about,omitempty"`
+	Email         *string         `json:"email,omitempty"`
+	OIDCSubject   *string         `json:"oidc_subject,omitempty"`
+	ExternalID    *string         `json:"external_id,omitempty"`
+	DeactivatedAt *time.Time      `json:"deactivated_at,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+	DeletedAt     *time.Time      `json:"deleted_at,omitempty"`
+}
+
+// BackupRoom is a room record in a backup
+type BackupRoom struct {
+	ID          uint            `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Capacity    int             `json:"capacity"`
+	IsActive    bool            `json:"is_active"`
+	Timezone    string          `json:"timezone,omitempty"`
+	Attributes  json.RawMessage `json:"attributes,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	DeletedAt   *time.Time      `json:"deleted_at,omitempty"`
+}
+
+// BackupEquipment is an equipment record in a backup
+type BackupEquipment struct {
+	ID          uint       `json:"id"`
+	RoomID      uint       `json:"room_id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	IsAvailable bool       `json:"is_available"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
+
+// BackupInstruction is an instruction record in a backup
+type BackupInstruction struct {
+	ID          uint                   `json:"id"`
+	EquipmentID uint                   `json:"equipment_id"`
+	Title       string                 `json:"title"`
+	Description string                 `json:"description,omitempty"`
+	Type        models.InstructionType `json:"type"`
+	FilePath    string                 `json:"file_path,omitempty"`
+	URL         string                 `json:"url,omitempty"`
+	Content     string                 `json:"content,omitempty"`
+	FileSize    int64                  `json:"file_size,omitempty"`
+	MimeType    string                 `json:"mime_type,omitempty"`
+	Order       int                    `json:"order"`
+	CreatedAt   time.Time              `json:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+	DeletedAt   *time.Time             `json:"deleted_at,omitempty"`
+}
+
+// BackupBooking is a booking record in a backup, participants are referenced by user ID
+type BackupBooking struct {
+	ID                    uint                 `json:"id"`
+	RoomID                uint                 `json:"room_id"`
+	CreatorID             uint                 `json:"creator_id"`
+	StartTime             time.Time            `json:"start_time"`
+	EndTime               time.Time            `json:"end_time"`
+	Title                 string               `json:"title"`
+	Description           string               `json:"description,omitempty"`
+	EstimatedParticipants int                  `json:"estimated_participants"`
+	IsJoinable            bool                 `json:"is_joinable"`
+	Status                models.BookingStatus `json:"status"`
+	ParticipantIDs        []uint               `json:"participant_ids,omitempty"`
+	CreatedAt             time.Time            `json:"created_at"`
+	UpdatedAt             time.Time            `json:"updated_at"`
+	DeletedAt             *time.Time           `json:"deleted_at,omitempty"`
+}
+
+// bookingParticipant - строка таблицы booking_participants
+type bookingParticipant struct {
+	BookingID uint
+	UserID    uint
+}
+
+// Export выгружает данные в JSON
+func Export(db *gorm.DB, w io.Writer) error {
+	backup := Backup{Version: BackupFormatVersion, ExportedAt: time.Now().UTC()}
+
+	// Одна транзакция - согласованный снимок всех таблиц
+	err := db.Transaction(func(tx *gorm.DB) error {
+		q := tx.Unscoped().Order("id").Session(&gorm.Session{})
+
+		var users []models.User
+		if err := q.Find(&users).Error; err != nil {
+			return fmt.Errorf("failed to export users: %w", err)
+		}
+		for _, u := range users {
+			backup.Users = append(backup.Users, BackupUser{
+				ID: u.ID, TelegramID: u.TelegramID, Username: u.Username, FirstName: u.FirstName,
+				LastName: u.LastName, PhoneNumber: u.PhoneNumber, LanguageCode: u.LanguageCode,
+				Role: u.Role, Userpic: u.Userpic, About: u.About, Email: u.Email, OIDCSubject: u.OIDCSubject,
+				ExternalID: u.ExternalID, DeactivatedAt: u.DeactivatedAt,
+				CreatedAt: u.CreatedAt, UpdatedAt: u.UpdatedAt, DeletedAt: deletedAtPtr(u.DeletedAt),
+			})
+		}
+
+		var rooms []models.Room
+		if err := q.Find(&rooms).Error; err != nil {
+			return fmt.Errorf("failed to export rooms: %w", err)
+		}
+		for _, r := range rooms {
+			backup.Rooms = append(backup.Rooms, BackupRoom{
+				ID: r.ID, Name: r.Name, Description: r.Description, Capacity: r.Capacity, IsActive: r.IsActive,
+				Timezone: r.Timezone, Attributes: json.RawMessage(r.Attributes),
+				CreatedAt: r.CreatedAt, UpdatedAt: r.UpdatedAt, DeletedAt: deletedAtPtr(r.DeletedAt),
+			})
+		}
+
+		var equipment []models.Equipment
+		if err := q.Find(&equipment).Error; err != nil {
+			return fmt.Errorf("failed to export equipment: %w", err)
+		}
+		for _, e := range equipment {
+			backup.Equipment = append(backup.Equipment, BackupEquipment{
+				ID: e.ID, RoomID: e.RoomID, Name: e.Name, Description: e.Description, IsAvailable: e.IsAvailable,
+				CreatedAt: e.CreatedAt, UpdatedAt: e.UpdatedAt, DeletedAt: deletedAtPtr(e.DeletedAt),
+			})
+		}
+
+		var instructions []models.Instruction
+		if err := q.Find(&instructions).Error; err != nil {
+			return fmt.Errorf("failed to export instructions: %w", err)
+		}
+		for _, i := range instructions {
+			backup.Instructions = append(backup.Instructions, BackupInstruction{
+				ID: i.ID, EquipmentID: i.EquipmentID, Title: i.Title, Description: i.Description, Type: i.Type,
+				FilePath: i.FilePath, URL: i.URL, Content: i.Content, FileSize: i.FileSize, MimeType: i.MimeType,
+				Order: i.Order, CreatedAt: i.CreatedAt, UpdatedAt: i.UpdatedAt, DeletedAt: deletedAtPtr(i.DeletedAt),
+			})
+		}
+
+		var participants []bookingParticipant
+		if err := tx.Table("booking_participants").Order("booking_id, user_id").Find(&participants).Error; err != nil {
+			return fmt.Errorf("failed to export booking participants: %w", err)
+		}
+		participantIDs := make(map[uint][]uint)
+		for _, p := range participants {
+			participantIDs[p.BookingID] = append(participantIDs[p.BookingID], p.UserID)
+		}
+
+		var bookings []models.Booking
+		if err := q.Find(&bookings).Error; err != nil {
+			return fmt.Errorf("failed to export bookings: %w", err)
+		}
+		for _, b := range bookings {
+			backup.Bookings = append(backup.Bookings, BackupBooking{
+				ID: b.ID, RoomID: b.RoomID, CreatorID: b.CreatorID, StartTime: b.StartTime, EndTime: b.EndTime,
+				Title: b.Title, Description: b.Description, EstimatedParticipants: b.EstimatedParticipants,
+				IsJoinable: b.IsJoinable, Status: b.Status, ParticipantIDs: participantIDs[b.ID],
+				CreatedAt: b.CreatedAt, UpdatedAt: b.UpdatedAt, DeletedAt: deletedAtPtr(b.DeletedAt),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(backup); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+
+	slog.Info("database exported",
+		"users", len(backup.Users),
+		"rooms", len(backup.Rooms),
+		"equipment", len(backup.Equipment),
+		"instructions", len(backup.Instructions),
+		"bookings", len(backup.Bookings),
+	)
+	return nil
+}
+
+// Import загружает JSON-выгрузку в пустую базу с сохранением идентификаторов
+// Всё выполняется в одной транзакции: при ошибке база остаётся пустой
+func Import(db *gorm.DB, r io.Reader) error {
+	var backup Backup
+	if err := json.NewDecoder(r).Decode(&backup); err != nil {
+		return fmt.Errorf("failed to read backup: %w", err)
+	}
+	if backup.Version != BackupFormatVersion {
+		return fmt.Errorf("unsupported backup format version %d, expected %d", backup.Version, BackupFormatVersion)
+	}
+
+	for _, model := range []interface{}{&models.User{}, &models.Room{}} {
+		var count int64
+		if err := db.Unscoped().Model(model).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check database: %w", err)
+		}
+		if count > 0 {
+			return ErrDatabaseNotEmpty
+		}
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		users := make([]models.User, 0, len(backup.Users))
+		for _, u := range backup.Users {
+			users = append(users, models.User{
+				ID: u.ID, TelegramID: u.TelegramID, Username: u.Username, FirstName: u.FirstName,
+				LastName: u.LastName, PhoneNumber: u.PhoneNumber, LanguageCode: u.LanguageCode,
+				Role: u.Role, Userpic: u.Userpic, About: u.About, Email: u.Email, OIDCSubject: u.OIDCSubject,
+				ExternalID: u.ExternalID, DeactivatedAt: u.DeactivatedAt,
+				CreatedAt: u.CreatedAt, UpdatedAt: u.UpdatedAt, DeletedAt: deletedAtValue(u.DeletedAt),
+			})
+		}
+		if err := insertBackupRows(tx, "users", &users, len(users)); err != nil {
+			return err
+		}
+
+		rooms := make([]models.Room, 0, len(backup.Rooms))
+		for _, r := range backup.Rooms {
+			rooms = append(rooms, models.Room{
+				ID: r.ID, Name: r.Name, Description: r.Description, Capacity: r.Capacity, IsActive: r.IsActive,
+				Timezone: r.Timezone, Attributes: datatypes.JSON(r.Attributes),
+				CreatedAt: r.CreatedAt, UpdatedAt: r.UpdatedAt, DeletedAt: deletedAtValue(r.DeletedAt),
+			})
+		}
+		if err := insertBackupRows(tx, "rooms", &rooms, len(rooms)); err != nil {
+			return err
+		}
+		var inactiveRooms []uint
+		for _, r := range backup.Rooms {
+			if !r.IsActive {
+				inactiveRooms = append(inactiveRooms, r.ID)
+			}
+		}
+		if err := restoreZeroColumn(tx, &models.Room{}, "is_active", false, inactiveRooms); err != nil {
+			return err
+		}
+
+		equipment := make([]models.Equipment, 0, len(backup.Equipment))
+		for _, e := range backup.Equipment {
+			equipment = append(equipment, models.Equipment{
+				ID: e.ID, RoomID: e.RoomID, Name: e.Name, Description: e.Description, IsAvailable: e.IsAvailable,
+				CreatedAt: e.CreatedAt, UpdatedAt: e.UpdatedAt, DeletedAt: deletedAtValue(e.DeletedAt),
+			})
+		}
+		if err := insertBackupRows(tx, "equipment", &equipment, len(equipment)); err != nil {
+			return err
+		}
+		var unavailableEquipment []uint
+		for _, e := range backup.Equipment {
+			if !e.IsAvailable {
+				unavailableEquipment = append(unavailableEquipment, e.ID)
+			}
+		}
+		if err := restoreZeroColumn(tx, &models.Equipment{}, "is_available", false, unavailableEquipment); err != nil {
+			return err
+		}
+
+		instructions := make([]models.Instruction, 0, len(backup.Instructions))
+		for _, i := range backup.Instructions {
+			instructions = append(instructions, models.Instruction{
+				ID: i.ID, EquipmentID: i.EquipmentID, Title: i.Title, Description: i.Description, Type: i.Type,
+				FilePath: i.FilePath, URL: i.URL, Content: i.Content, FileSize: i.FileSize, MimeType: i.MimeType,
+				Order: i.Order, CreatedAt: i.CreatedAt, UpdatedAt: i.UpdatedAt, DeletedAt: deletedAtValue(i.DeletedAt),
+			})
+		}
+		if err := insertBackupRows(tx, "instructions", &instructions, len(instructions)); err != nil {
+			return err
+		}
+
+		bookings := make([]models.Booking, 0, len(backup.Bookings))
+		var participants []map[string]interface{}
+		for _, b := range backup.Bookings {
+			bookings = append(bookings, models.Booking{
+				ID: b.ID, RoomID: b.RoomID, CreatorID: b.CreatorID, StartTime: b.StartTime, EndTime: b.EndTime,
+				Title: b.Title, Description: b.Description, EstimatedParticipants: b.EstimatedParticipants,
+				IsJoinable: b.IsJoinable, Status: b.Status,
+				CreatedAt: b.CreatedAt, UpdatedAt: b.UpdatedAt, DeletedAt: deletedAtValue(b.DeletedAt),
+			})
+			for _, userID := range b.ParticipantIDs {
+				participants = append(participants, map[string]interface{}{"booking_id": b.ID, "user_id": userID})
+			}
+		}
+		if err := insertBackupRows(tx, "bookings", &bookings, len(bookings)); err != nil {
+			return err
+		}
+		if len(participants) > 0 {
+			if err := tx.Table("booking_participants").CreateInBatches(participants, backupBatchSize).Error; err != nil {
+				return fmt.Errorf("failed to import booking participants: %w", err)
+			}
+		}
+
+		// Идентификаторы вставлены явно - сдвигаем последовательности, иначе новые записи получат занятые id
+		if err := resetSequences(tx, "users", "rooms", "equipment", "instructions", "bookings"); err != nil {
+			return err
+		}
+
+		slog.Info("database imported",
+			"exported_at", backup.ExportedAt,
+			"users", len(users),
+			"rooms", len(rooms),
+			"equipment", len(equipment),
+			"instructions", len(instructions),
+			"bookings", len(bookings),
+		)
+		return nil
+	})
+}
+
+// insertBackupRows вставляет строки пачками, без связей
+func insertBackupRows(tx *gorm.DB, table string, rows interface{}, count int) error {
+	if count == 0 {
+		return nil
+	}
+	if err := tx.Omit(clause.Associations).CreateInBatches(rows, backupBatchSize).Error; err != nil {
+		return fmt.Errorf("failed to import %s: %w", table, err)
+	}
+	return nil
+}
+
+// restoreZeroColumn возвращает нулевое значение колонке с default-тегом
+// GORM при вставке подставляет default вместо нулевого значения (is_active = false превращается в true)
+func restoreZeroColumn(tx *gorm.DB, model interface{}, column string, value interface{}, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := tx.Unscoped().Model(model).Where("id IN ?", ids).UpdateColumn(column, value).Error; err != nil {
+		return fmt.Errorf("failed to restore %s: %w", column, err)
+	}
+	return nil
+}
+
+// resetSequences выставляет bigserial-последовательности PostgreSQL на максимальный id таблицы
+// В SQLite автоинкремент сам продолжает после максимального rowid
+func resetSequences(tx *gorm.DB, tables ...string) error {
+	if tx.Dialector.Name() != "postgres" {
+		return nil
+	}
+	for _, table := range tables {
+		query := fmt.Sprintf(
+			"SELECT setval(pg_get_serial_sequence('%[1]s', 'id'), COALESCE((SELECT MAX(id) FROM %[1]s), 0) + 1, false)",
+			table,
+		)
+		if err := tx.Exec(query).Error; err != nil {
+			return fmt.Errorf("failed to reset %s id sequence: %w", table, err)
+		}
+	}
+	return nil
+}
+
+func deletedAtPtr(d gorm.DeletedAt) *time.Time {
+	if !d.Valid {
+		return nil
+	}
+	t := d.Time
+	return &t
+}
+
+func deletedAtValue(t *time.Time) gorm.DeletedAt {
+	if t == nil {
+		return gorm.DeletedAt{}
+	}
+	return gorm.DeletedAt{Time: *t, Valid: true}
+}
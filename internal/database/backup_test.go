@@ -0,0 +1,90 @@
+//go:build sqlite
+
+package database
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+func newSQLiteTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := Connect("sqlite://:memory:", ConnectOptions{Backoff: time.Second})
+	if err != nil {
+		t.Fatalf("Failed to open SQLite: %v", err)
+	}
+	t.Cleanup(func() { Close(db) })
+
+	if err := MigrateSQLite(db); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	return db
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	src := newSQLiteTestDB(t)
+	if err := Seed(src); err != nil {
+		t.Fatalf("Failed to seed: %v", err)
+	}
+
+	// Нулевые значения и soft-deleted строки должны пережить перенос
+	var room models.Room
+	src.First(&room)
+	src.Model(&room).UpdateColumn("is_active", false)
+	var deleted models.Booking
+	src.First(&deleted)
+	src.Delete(&deleted)
+	var participants int64
+	src.Table("booking_participants").Where("booking_id = ?", deleted.ID).Count(&participants)
+
+	var buf bytes.Buffer
+	if err := Export(src, &buf); err != nil {
+		t.Fatalf("Failed to export: %v", err)
+	}
+	exported := buf.Bytes()
+
+	dst := newSQLiteTestDB(t)
+	if err := Import(dst, bytes.NewReader(exported)); err != nil {
+		t.Fatalf("Failed to import: %v", err)
+	}
+
+	for _, model := range []interface{}{&models.User{}, &models.Room{}, &models.Equipment{}, &models.Instruction{}, &models.Booking{}} {
+		var want, got int64
+		src.Unscoped().Model(model).Count(&want)
+		dst.Unscoped().Model(model).Count(&got)
+		if got != want {
+			t.Errorf("Expected %d rows of %T, got: %d", want, model, got)
+		}
+	}
+
+	var imported models.Room
+	dst.First(&imported, room.ID)
+	if imported.IsActive || imported.Name != room.Name {
+		t.Errorf("Expected inactive room %q, got: %+v", room.Name, imported)
+	}
+
+	var importedBooking models.Booking
+	if err := dst.Unscoped().Preload("Participants").First(&importedBooking, deleted.ID).Error; err != nil {
+		t.Fatalf("Expected soft-deleted booking to be imported, got: %v", err)
+	}
+	if !importedBooking.DeletedAt.Valid || int64(len(importedBooking.Participants)) != participants {
+		t.Errorf("Expected deleted booking with %d participants, got: %+v", participants, importedBooking)
+	}
+
+	// Новые записи продолжают нумерацию после импортированных
+	newRoom := models.Room{Name: "New room", Capacity: 2, IsActive: true}
+	if err := dst.Create(&newRoom).Error; err != nil {
+		t.Fatalf("Failed to create room after import: %v", err)
+	}
+
+	// Повторный импорт в непустую базу запрещён
+	if err := Import(dst, bytes.NewReader(exported)); !errors.Is(err, ErrDatabaseNotEmpty) {
+		t.Errorf("Expected ErrDatabaseNotEmpty, got: %v", err)
+	}
+}
@@ -0,0 +1,325 @@
+package database
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/space/backend/internal/models"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// DataPack - декларативное описание начальных данных установки: комнаты с оборудованием и инструкциями
+// Новое пространство разворачивается из файла (DATA_PACK_FILE) вместо ручной настройки в админке
+type DataPack struct {
+	Rooms []PackRoom `json:"rooms" yaml:"rooms"`
+}
+
+// PackRoom is a room in a data pack; the name identifies it
+type PackRoom struct {
+	Name          string                 `json:"name" yaml:"name"`
+	Description   string                 `json:"description" yaml:"description"`
+	Capacity      int                    `json:"capacity" yaml:"capacity"`
+	Kind          models.RoomKind        `json:"kind" yaml:"kind"`                     // room (по умолчанию) или parking
+	Timezone      string                 `json:"timezone" yaml:"timezone"`             // IANA; пусто - OFFICE_TIMEZONE
+	IsActive      *bool                  `json:"is_active" yaml:"is_active"`           // По умолчанию true
+	HourlyPrice   int64                  `json:"hourly_price" yaml:"hourly_price"`     // В минимальных единицах PRICE_CURRENCY
+	AnnounceStart bool                   `json:"announce_start" yaml:"announce_start"` // Объявлять начало бронирований в группе
+	Attributes    map[string]interface{} `json:"attributes" yaml:"attributes"`
+	Equipment     []PackEquipment        `json:"equipment" yaml:"equipment"`
+}
+
+// PackEquipment is room equipment in a data pack; the name identifies it within the room
+type PackEquipment struct {
+	Name         string            `json:"name" yaml:"name"`
+	Description  string            `json:"description" yaml:"description"`
+	IsAvailable  *bool             `json:"is_available" yaml:"is_available"` // По умолчанию true
+	Instructions []PackInstruction `json:"instructions" yaml:"instructions"`
+}
+
+// PackInstruction is an equipment instruction in a data pack; the title identifies it within the equipment
+// Файлы (document и video) в пакет не входят - такие инструкции задаются ссылкой url
+type PackInstruction struct {
+	Title       string                 `json:"title" yaml:"title"`
+	Description string                 `json:"description" yaml:"description"`
+	Type        models.InstructionType `json:"type" yaml:"type"`
+	Content     string                 `json:"content" yaml:"content"` // Для text
+	URL         string                 `json:"url" yaml:"url"`         // Для link, document и video
+	Order       int                    `json:"order" yaml:"order"`     // По умолчанию - порядок в списке, с 1
+}
+
+// DataPackResult - сколько записей создано; уже существующие не изменяются
+type DataPackResult struct {
+	Rooms        int
+	Equipment    int
+	Instructions int
+}
+
+// LoadDataPack reads a data pack from a YAML or JSON file (by extension) and validates it
+// Неизвестные поля - ошибка: опечатка в имени поля не должна молча терять данные
+func LoadDataPack(path string) (*DataPack, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data pack: %w", err)
+	}
+
+	var pack DataPack
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&pack)
+	} else {
+		err = yaml.UnmarshalWithOptions(data, &pack, yaml.Strict())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse data pack %s: %w", path, err)
+	}
+
+	if err := pack.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid data pack %s: %w", path, err)
+	}
+	return &pack, nil
+}
+
+// Validate checks the pack and returns all problems at once
+func (p *DataPack) Validate() error {
+	var problems []error
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Errorf(format, args...))
+	}
+
+	rooms := make(map[string]bool, len(p.Rooms))
+	for i, room := range p.Rooms {
+		at := fmt.Sprintf("rooms[%d]", i)
+		switch {
+		case strings.TrimSpace(room.Name) == "":
+			add("%s: name is required", at)
+		case rooms[room.Name]:
+			add("%s: duplicate room %q", at, room.Name)
+		}
+		rooms[room.Name] = true
+		if room.Capacity < 0 {
+			add("%s: capacity must not be negative", at)
+		}
+		if room.HourlyPrice < 0 {
+			add("%s: hourly_price must not be negative", at)
+		}
+		switch room.Kind {
+		case "", models.RoomKindRoom, models.RoomKindParking:
+		default:
+			add("%s: kind must be one of: room, parking, got %q", at, room.Kind)
+		}
+		if room.Timezone != "" {
+			if _, err := time.LoadLocation(room.Timezone); err != nil {
+				add("%s: unknown timezone %q", at, room.Timezone)
+			}
+		}
+
+		equipment := make(map[string]bool, len(room.Equipment))
+		for j, e := range room.Equipment {
+			at := fmt.Sprintf("%s.equipment[%d]", at, j)
+			switch {
+			case strings.TrimSpace(e.Name) == "":
+				add("%s: name is required", at)
+			case equipment[e.Name]:
+				add("%s: duplicate equipment %q", at, e.Name)
+			}
+			equipment[e.Name] = true
+
+			titles := make(map[string]bool, len(e.Instructions))
+			for k, instruction := range e.Instructions {
+				at := fmt.Sprintf("%s.instructions[%d]", at, k)
+				switch {
+				case strings.TrimSpace(instruction.Title) == "":
+					add("%s: title is required", at)
+				case titles[instruction.Title]:
+					add("%s: duplicate instruction %q", at, instruction.Title)
+				}
+				titles[instruction.Title] = true
+				switch instruction.Type {
+				case models.InstructionTypeText:
+					if instruction.Content == "" {
+						add("%s: content is required for a text instruction", at)
+					}
+				case models.InstructionTypeLink, models.InstructionTypeDocument, models.InstructionTypeVideo:
+					if instruction.URL == "" {
+						add("%s: url is required for a %s instruction", at, instruction.Type)
+					}
+				default:
+					add("%s: type must be one of: text, link, document, video, got %q", at, instruction.Type)
+				}
+			}
+		}
+	}
+	return errors.Join(problems...)
+}
+
+// ApplyDataPack creates the rooms, equipment and instructions of the pack that are missing, in one transaction
+// Применение идемпотентно: записи ищутся по названию (комната), по названию в комнате (оборудование)
+// и по заголовку у оборудования (инструкция). Существующие записи не меняются, поэтому правки
+// администратора переживают перезапуск; удалённые администратором записи тоже не создаются заново
+func ApplyDataPack(db *gorm.DB, pack *DataPack) (*DataPackResult, error) {
+	result := &DataPackResult{}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, spec := range pack.Rooms {
+			room, created, err := applyPackRoom(tx, spec)
+			if err != nil {
+				return err
+			}
+			if created {
+				result.Rooms++
+			}
+			if room.DeletedAt.Valid {
+				continue
+			}
+
+			for _, equipmentSpec := range spec.Equipment {
+				equipment, created, err := applyPackEquipment(tx, room.ID, equipmentSpec)
+				if err != nil {
+					return err
+				}
+				if created {
+					result.Equipment++
+				}
+				if equipment.DeletedAt.Valid {
+					continue
+				}
+
+				for i, instructionSpec := range equipmentSpec.Instructions {
+					created, err := applyPackInstruction(tx, equipment.ID, i, instructionSpec)
+					if err != nil {
+						return err
+					}
+					if created {
+						result.Instructions++
+					}
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("data pack applied",
+		"rooms_created", result.Rooms,
+		"equipment_created", result.Equipment,
+		"instructions_created", result.Instructions,
+	)
+	return result, nil
+}
+
+// applyPackRoom находит комнату по названию, в том числе удалённую, или создаёт её
+func applyPackRoom(tx *gorm.DB, spec PackRoom) (*models.Room, bool, error) {
+	var room models.Room
+	err := tx.Unscoped().Where("name = ?", spec.Name).First(&room).Error
+	if err == nil {
+		return &room, false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, fmt.Errorf("failed to find room %q: %w", spec.Name, err)
+	}
+
+	room = models.Room{
+		Name:          spec.Name,
+		Description:   spec.Description,
+		Capacity:      spec.Capacity,
+		Kind:          spec.Kind,
+		Timezone:      spec.Timezone,
+		IsActive:      spec.IsActive == nil || *spec.IsActive,
+		HourlyPrice:   spec.HourlyPrice,
+		AnnounceStart: spec.AnnounceStart,
+	}
+	if room.Kind == "" {
+		room.Kind = models.RoomKindRoom
+	}
+	if len(spec.Attributes) > 0 {
+		attributes, err := json.Marshal(spec.Attributes)
+		if err != nil {
+			return nil, false, fmt.Errorf("room %q: invalid attributes: %w", spec.Name, err)
+		}
+		room.Attributes = datatypes.JSON(attributes)
+	}
+	// GORM подставляет default вместо false и в структуру, поэтому значение запоминается до вставки
+	active := room.IsActive
+	if err := tx.Create(&room).Error; err != nil {
+		return nil, false, fmt.Errorf("failed to create room %q: %w", spec.Name, err)
+	}
+	if !active {
+		if err := restoreZeroColumn(tx, &models.Room{}, "is_active", false, []uint{room.ID}); err != nil {
+			return nil, false, err
+		}
+		room.IsActive = false
+	}
+	return &room, true, nil
+}
+
+// applyPackEquipment находит оборудование комнаты по названию, в том числе удалённое, или создаёт его
+func applyPackEquipment(tx *gorm.DB, roomID uint, spec PackEquipment) (*models.Equipment, bool, error) {
+	var equipment models.Equipment
+	err := tx.Unscoped().Where("room_id = ? AND name = ?", roomID, spec.Name).First(&equipment).Error
+	if err == nil {
+		return &equipment, false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, fmt.Errorf("failed to find equipment %q: %w", spec.Name, err)
+	}
+
+	equipment = models.Equipment{
+		RoomID:      roomID,
+		Name:        spec.Name,
+		Description: spec.Description,
+		IsAvailable: spec.IsAvailable == nil || *spec.IsAvailable,
+	}
+	available := equipment.IsAvailable
+	if err := tx.Create(&equipment).Error; err != nil {
+		return nil, false, fmt.Errorf("failed to create equipment %q: %w", spec.Name, err)
+	}
+	if !available {
+		if err := restoreZeroColumn(tx, &models.Equipment{}, "is_available", false, []uint{equipment.ID}); err != nil {
+			return nil, false, err
+		}
+		equipment.IsAvailable = false
+	}
+	return &equipment, true, nil
+}
+
+// applyPackInstruction создаёт инструкцию, если у оборудования нет инструкции с тем же заголовком
+func applyPackInstruction(tx *gorm.DB, equipmentID uint, index int, spec PackInstruction) (bool, error) {
+	var count int64
+	err := tx.Unscoped().Model(&models.Instruction{}).
+		Where("equipment_id = ? AND title = ?", equipmentID, spec.Title).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to find instruction %q: %w", spec.Title, err)
+	}
+	if count > 0 {
+		return false, nil
+	}
+
+	instruction := models.Instruction{
+		EquipmentID: equipmentID,
+		Title:       spec.Title,
+		Description: spec.Description,
+		Type:        spec.Type,
+		Content:     spec.Content,
+		URL:         spec.URL,
+		Order:       spec.Order,
+	}
+	if instruction.Order == 0 {
+		instruction.Order = index + 1
+	}
+	if err := tx.Create(&instruction).Error; err != nil {
+		return false, fmt.Errorf("failed to create instruction %q: %w", spec.Title, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,74 @@
+//go:build sqlite
+
+package database
+
+import (
+	"testing"
+
+	"github.com/space/backend/internal/models"
+)
+
+func TestApplyDataPack(t *testing.T) {
+	db := newSQLiteTestDB(t)
+	inactive := false
+	pack := &DataPack{Rooms: []PackRoom{
+		{
+			Name: "Hall", Capacity: 40, HourlyPrice: 100000, Attributes: map[string]interface{}{"color": "#059669"},
+			Equipment: []PackEquipment{{
+				Name: "Projector",
+				Instructions: []PackInstruction{
+					{Title: "Power", Type: models.InstructionTypeText, Content: "Press the red button"},
+					{Title: "Manual", Type: models.InstructionTypeLink, URL: "https://example.com/manual"},
+				},
+			}},
+		},
+		{Name: "P1", Kind: models.RoomKindParking, IsActive: &inactive},
+	}}
+
+	result, err := ApplyDataPack(db, pack)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if *result != (DataPackResult{Rooms: 2, Equipment: 1, Instructions: 2}) {
+		t.Errorf("Expected 2 rooms, 1 equipment and 2 instructions, got: %+v", result)
+	}
+
+	var hall, parking models.Room
+	db.Where("name = ?", "Hall").First(&hall)
+	db.Where("name = ?", "P1").First(&parking)
+	if !hall.IsActive || hall.Kind != models.RoomKindRoom || hall.HourlyPrice != 100000 || string(hall.Attributes) != `{"color":"#059669"}` {
+		t.Errorf("Expected an active room with price and attributes, got: %+v", hall)
+	}
+	if parking.IsActive || parking.Kind != models.RoomKindParking {
+		t.Errorf("Expected an inactive parking spot, got: %+v", parking)
+	}
+	var instructions []models.Instruction
+	db.Order("\"order\"").Find(&instructions)
+	if len(instructions) != 2 || instructions[0].Order != 1 || instructions[1].Order != 2 {
+		t.Errorf("Expected instructions ordered as listed, got: %+v", instructions)
+	}
+
+	// Правки и удаления администратора переживают повторное применение
+	db.Model(&hall).UpdateColumn("capacity", 30)
+	db.Where("title = ?", "Manual").Delete(&models.Instruction{})
+	db.Delete(&parking)
+
+	pack.Rooms[0].Equipment = append(pack.Rooms[0].Equipment, PackEquipment{Name: "Speaker"})
+	result, err = ApplyDataPack(db, pack)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if *result != (DataPackResult{Equipment: 1}) {
+		t.Errorf("Expected only the new equipment to be created, got: %+v", result)
+	}
+	db.First(&hall, hall.ID)
+	if hall.Capacity != 30 {
+		t.Errorf("Expected the edited capacity to stay, got: %d", hall.Capacity)
+	}
+	var rooms, manuals int64
+	db.Model(&models.Room{}).Count(&rooms)
+	db.Model(&models.Instruction{}).Where("title = ?", "Manual").Count(&manuals)
+	if rooms != 1 || manuals != 0 {
+		t.Errorf("Expected deleted records not to be recreated, got %d rooms and %d manuals", rooms, manuals)
+	}
+}
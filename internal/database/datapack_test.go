@@ -0,0 +1,78 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadDataPack(t *testing.T) {
+	// Пример из репозитория должен оставаться корректным
+	pack, err := LoadDataPack(filepath.Join("..", "..", "datapack.example.yaml"))
+	if err != nil {
+		t.Fatalf("Expected the example pack to load, got: %v", err)
+	}
+	if len(pack.Rooms) != 3 || len(pack.Rooms[0].Equipment) != 1 || len(pack.Rooms[0].Equipment[0].Instructions) != 2 {
+		t.Errorf("Expected 3 rooms with equipment and instructions, got: %+v", pack.Rooms)
+	}
+	if active := pack.Rooms[2].IsActive; active == nil || *active {
+		t.Errorf("Expected the parking spot to be inactive, got: %v", active)
+	}
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write pack: %v", err)
+		}
+		return path
+	}
+
+	jsonPack, err := LoadDataPack(write("pack.json", `{"rooms":[{"name":"Hall","capacity":40,"equipment":[{"name":"Projector"}]}]}`))
+	if err != nil {
+		t.Fatalf("Expected a JSON pack to load, got: %v", err)
+	}
+	if jsonPack.Rooms[0].Name != "Hall" || jsonPack.Rooms[0].Equipment[0].Name != "Projector" {
+		t.Errorf("Expected the JSON room, got: %+v", jsonPack.Rooms)
+	}
+
+	// Опечатка в имени поля не теряется молча
+	if _, err := LoadDataPack(write("typo.yaml", "rooms:\n  - name: Hall\n    capacty: 40\n")); err == nil {
+		t.Error("Expected an unknown YAML field to fail")
+	}
+	if _, err := LoadDataPack(write("typo.json", `{"rooms":[{"name":"Hall","capacty":40}]}`)); err == nil {
+		t.Error("Expected an unknown JSON field to fail")
+	}
+
+	invalid := write("invalid.yaml", `
+rooms:
+  - name: Hall
+    kind: garage
+    timezone: Mars/Olympus
+    equipment:
+      - name: Projector
+        instructions:
+          - title: Power
+            type: text
+          - title: Power
+            type: link
+  - name: Hall
+`)
+	_, err = LoadDataPack(invalid)
+	if err == nil {
+		t.Fatal("Expected an invalid pack to fail")
+	}
+	for _, problem := range []string{
+		`rooms[0]: kind must be one of: room, parking, got "garage"`,
+		`rooms[0]: unknown timezone "Mars/Olympus"`,
+		"rooms[0].equipment[0].instructions[0]: content is required for a text instruction",
+		`rooms[0].equipment[0].instructions[1]: duplicate instruction "Power"`,
+		"rooms[0].equipment[0].instructions[1]: url is required for a link instruction",
+		`rooms[1]: duplicate room "Hall"`,
+	} {
+		if !strings.Contains(err.Error(), problem) {
+			t.Errorf("Expected %q among the problems, got: %v", problem, err)
+		}
+	}
+}
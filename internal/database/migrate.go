@@ -0,0 +1,169 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+
+	"github.com/golang-migrate/migrate/v4"
+	pgxmigrate "github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	_ "github.com/jackc/pgx/v5/stdlib" // драйвер "pgx" для database/sql
+	"gorm.io/gorm"
+)
+
+// migrationsFS содержит версионированные SQL-миграции
+// Новая миграция - пара файлов NNNNNN_name.up.sql / NNNNNN_name.down.sql в migrations/
+//
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// ErrSchemaOutdated возвращается, если в базе применены не все миграции
+var ErrSchemaOutdated = errors.New("database schema is outdated")
+
+// Migrator применяет версионированные миграции (таблица schema_migrations)
+// Использует отдельное подключение, чтобы не занимать соединения основного пула
+type Migrator struct {
+	m *migrate.Migrate
+}
+
+// NewMigrator creates a migrator for the database
+func NewMigrator(databaseURL string) (*Migrator, error) {
+	sqlDB, err := sql.Open("pgx", databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database for migrations: %w", err)
+	}
+
+	driver, err := pgxmigrate.WithInstance(sqlDB, &pgxmigrate.Config{})
+	if err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("failed to init migration driver: %w", err)
+	}
+
+	source, err := iofs.New(migrationsFS, "migrations")
+	if err != nil {
+		driver.Close()
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", source, "pgx5", driver)
+	if err != nil {
+		driver.Close()
+		return nil, fmt.Errorf("failed to init migrator: %w", err)
+	}
+
+	return &Migrator{m: m}, nil
+}
+
+// Up applies all pending migrations
+func (mg *Migrator) Up() error {
+	slog.Info("running database migrations")
+
+	if err := mg.m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	version, _, err := mg.Version()
+	if err != nil {
+		return err
+	}
+	slog.Info("migrations completed", "version", version)
+	return nil
+}
+
+// Down rolls back the given number of migrations
+func (mg *Migrator) Down(steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("steps must be positive")
+	}
+	if err := mg.m.Steps(-steps); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to roll back migrations: %w", err)
+	}
+	return nil
+}
+
+// Force sets the schema version without running migrations (recovery after a failed migration)
+func (mg *Migrator) Force(version int) error {
+	return mg.m.Force(version)
+}
+
+// Version returns the current schema version; 0 means no migrations applied
+func (mg *Migrator) Version() (uint, bool, error) {
+	version, dirty, err := mg.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+// Check verifies the schema is at the latest embedded version and not dirty
+func (mg *Migrator) Check() error {
+	version, dirty, err := mg.Version()
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return compareSchemaVersion(version, dirty)
+}
+
+// CheckSchemaVersion verifies the schema version through an existing connection
+// В отличие от Migrator.Check не открывает отдельное подключение - подходит для readiness probe
+func CheckSchemaVersion(ctx context.Context, db *gorm.DB) error {
+	var row struct {
+		Version uint
+		Dirty   bool
+	}
+	if err := db.WithContext(ctx).Raw("SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&row).Error; err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return compareSchemaVersion(row.Version, row.Dirty)
+}
+
+// compareSchemaVersion сравнивает версию схемы с последней встроенной миграцией
+func compareSchemaVersion(version uint, dirty bool) error {
+	latest, err := LatestSchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	if dirty {
+		return fmt.Errorf("%w: migration %d failed and left the schema dirty, fix it and run `migrate force`", ErrSchemaOutdated, version)
+	}
+	if version < latest {
+		return fmt.Errorf("%w: version %d, expected %d, run `migrate up`", ErrSchemaOutdated, version, latest)
+	}
+	return nil
+}
+
+// Close closes the migration connection
+func (mg *Migrator) Close() error {
+	sourceErr, dbErr := mg.m.Close()
+	return errors.Join(sourceErr, dbErr)
+}
+
+// LatestSchemaVersion returns the version of the newest embedded migration
+func LatestSchemaVersion() (uint, error) {
+	source, err := iofs.New(migrationsFS, "migrations")
+	if err != nil {
+		return 0, fmt.Errorf("failed to load migrations: %w", err)
+	}
+	defer source.Close()
+
+	version, err := source.First()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	for {
+		next, err := source.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read migrations: %w", err)
+		}
+		version = next
+	}
+}
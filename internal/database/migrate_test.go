@@ -0,0 +1,38 @@
+package database
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+func TestMigrationsArePaired(t *testing.T) {
+	entries, err := fs.ReadDir(migrationsFS, "migrations")
+	if err != nil {
+		t.Fatalf("Failed to read migrations: %v", err)
+	}
+
+	files := map[string]bool{}
+	for _, e := range entries {
+		files[e.Name()] = true
+	}
+
+	for name := range files {
+		if base, ok := strings.CutSuffix(name, ".up.sql"); ok && !files[base+".down.sql"] {
+			t.Errorf("Migration %s has no down migration", name)
+		}
+		if base, ok := strings.CutSuffix(name, ".down.sql"); ok && !files[base+".up.sql"] {
+			t.Errorf("Migration %s has no up migration", name)
+		}
+	}
+}
+
+func TestLatestSchemaVersion(t *testing.T) {
+	version, err := LatestSchemaVersion()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if version < 1 {
+		t.Errorf("Expected at least one migration, got version: %d", version)
+	}
+}
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS audit_logs;
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS notification_subscriptions;
+DROP TABLE IF EXISTS booking_participants;
+DROP TABLE IF EXISTS bookings;
+DROP TABLE IF EXISTS instructions;
+DROP TABLE IF EXISTS equipment;
+DROP TABLE IF EXISTS rooms;
+DROP TABLE IF EXISTS users;
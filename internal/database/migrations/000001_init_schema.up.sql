@@ -0,0 +1,143 @@
+-- Базовая схема, совпадающая с созданной ранее через GORM AutoMigrate.
+-- IF NOT EXISTS позволяет применить миграцию к существующей базе без изменений.
+
+CREATE TABLE IF NOT EXISTS users (
+    id               bigserial PRIMARY KEY,
+    telegram_id      bigint       NOT NULL,
+    username         text,
+    first_name       text,
+    last_name        text,
+    phone_number     text,
+    language_code    text,
+    role             varchar(20)  NOT NULL DEFAULT 'user',
+    userpic          varchar(500),
+    about            varchar(500),
+    is_in_phone_book boolean      DEFAULT false,
+    created_at       timestamptz,
+    updated_at       timestamptz,
+    deleted_at       timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_telegram_id ON users (telegram_id);
+CREATE INDEX IF NOT EXISTS idx_users_username ON users (username);
+CREATE INDEX IF NOT EXISTS idx_users_phone_number ON users (phone_number);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS rooms (
+    id          bigserial PRIMARY KEY,
+    name        text    NOT NULL,
+    description text,
+    capacity    bigint  DEFAULT 1,
+    is_active   boolean DEFAULT true,
+    attributes  jsonb,
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    deleted_at  timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_rooms_name ON rooms (name);
+CREATE INDEX IF NOT EXISTS idx_rooms_deleted_at ON rooms (deleted_at);
+
+CREATE TABLE IF NOT EXISTS equipment (
+    id           bigserial PRIMARY KEY,
+    room_id      bigint  NOT NULL CONSTRAINT fk_rooms_equipment REFERENCES rooms (id),
+    name         text    NOT NULL,
+    description  text,
+    is_available boolean DEFAULT true,
+    created_at   timestamptz,
+    updated_at   timestamptz,
+    deleted_at   timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_equipment_room_id ON equipment (room_id);
+CREATE INDEX IF NOT EXISTS idx_equipment_deleted_at ON equipment (deleted_at);
+
+CREATE TABLE IF NOT EXISTS instructions (
+    id           bigserial PRIMARY KEY,
+    equipment_id bigint      NOT NULL CONSTRAINT fk_equipment_instructions REFERENCES equipment (id),
+    title        text        NOT NULL,
+    description  text,
+    type         varchar(50) NOT NULL,
+    file_path    text,
+    url          text,
+    content      text,
+    file_size    bigint,
+    mime_type    text,
+    "order"      bigint      DEFAULT 0,
+    created_at   timestamptz,
+    updated_at   timestamptz,
+    deleted_at   timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_instructions_equipment_id ON instructions (equipment_id);
+CREATE INDEX IF NOT EXISTS idx_instructions_deleted_at ON instructions (deleted_at);
+
+CREATE TABLE IF NOT EXISTS bookings (
+    id                     bigserial PRIMARY KEY,
+    room_id                bigint      NOT NULL CONSTRAINT fk_rooms_bookings REFERENCES rooms (id),
+    creator_id             bigint      NOT NULL CONSTRAINT fk_users_bookings REFERENCES users (id),
+    start_time             timestamptz NOT NULL,
+    end_time               timestamptz NOT NULL,
+    title                  text        NOT NULL,
+    description            text,
+    estimated_participants bigint      DEFAULT 1,
+    is_joinable            boolean     DEFAULT false,
+    status                 varchar(20) DEFAULT 'confirmed',
+    created_at             timestamptz,
+    updated_at             timestamptz,
+    deleted_at             timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_bookings_room_id ON bookings (room_id);
+CREATE INDEX IF NOT EXISTS idx_bookings_creator_id ON bookings (creator_id);
+CREATE INDEX IF NOT EXISTS idx_bookings_start_time ON bookings (start_time);
+CREATE INDEX IF NOT EXISTS idx_bookings_end_time ON bookings (end_time);
+CREATE INDEX IF NOT EXISTS idx_bookings_deleted_at ON bookings (deleted_at);
+
+CREATE TABLE IF NOT EXISTS booking_participants (
+    booking_id bigint NOT NULL CONSTRAINT fk_booking_participants_booking REFERENCES bookings (id),
+    user_id    bigint NOT NULL CONSTRAINT fk_booking_participants_user REFERENCES users (id),
+    PRIMARY KEY (booking_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS notification_subscriptions (
+    id         bigserial PRIMARY KEY,
+    user_id    bigint NOT NULL CONSTRAINT fk_notification_subscriptions_user REFERENCES users (id),
+    room_id    bigint NOT NULL CONSTRAINT fk_notification_subscriptions_room REFERENCES rooms (id),
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_user_room ON notification_subscriptions (user_id, room_id);
+CREATE INDEX IF NOT EXISTS idx_notification_subscriptions_deleted_at ON notification_subscriptions (deleted_at);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id            bigserial PRIMARY KEY,
+    name          text         NOT NULL,
+    prefix        varchar(16)  NOT NULL,
+    secret_hash   varchar(64)  NOT NULL,
+    scopes        varchar(500) NOT NULL,
+    created_by_id bigint       NOT NULL CONSTRAINT fk_api_keys_created_by REFERENCES users (id),
+    expires_at    timestamptz,
+    last_used_at  timestamptz,
+    created_at    timestamptz,
+    updated_at    timestamptz,
+    deleted_at    timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_secret_hash ON api_keys (secret_hash);
+CREATE INDEX IF NOT EXISTS idx_api_keys_created_by_id ON api_keys (created_by_id);
+CREATE INDEX IF NOT EXISTS idx_api_keys_deleted_at ON api_keys (deleted_at);
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id         bigserial PRIMARY KEY,
+    actor_id   bigint,
+    actor_type varchar(20)  NOT NULL,
+    api_key_id bigint,
+    method     varchar(10)  NOT NULL,
+    route      varchar(255) NOT NULL,
+    path       varchar(500) NOT NULL,
+    entity_id  varchar(64),
+    status     bigint       NOT NULL,
+    client_ip  varchar(64),
+    request_id varchar(128),
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_id ON audit_logs (actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_route ON audit_logs (route);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_entity_id ON audit_logs (entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs (created_at);
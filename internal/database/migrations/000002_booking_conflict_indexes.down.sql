@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_bookings_active_time;
+DROP INDEX IF EXISTS idx_bookings_active_room_time;
@@ -0,0 +1,13 @@
+-- Составные частичные индексы для проверки конфликтов и календаря.
+-- Предикат совпадает с условием в BookingRepository (activeBookingCondition)
+-- и с условием soft delete, которое GORM добавляет к каждому запросу.
+
+-- Проверка конфликтов и календарь комнаты: room_id = ? AND start_time < ? AND end_time > ?
+CREATE INDEX IF NOT EXISTS idx_bookings_active_room_time
+    ON bookings (room_id, start_time, end_time)
+    WHERE status <> 'cancelled' AND deleted_at IS NULL;
+
+-- Общий календарь: start_time < ? AND end_time > ? по всем комнатам
+CREATE INDEX IF NOT EXISTS idx_bookings_active_time
+    ON bookings (start_time, end_time)
+    WHERE status <> 'cancelled' AND deleted_at IS NULL;
@@ -0,0 +1,21 @@
+-- Каналы Slack для уведомлений о бронированиях (room_id NULL - все комнаты)
+CREATE TABLE IF NOT EXISTS slack_targets (
+    id            bigserial PRIMARY KEY,
+    name          text         NOT NULL,
+    kind          varchar(10)  NOT NULL,
+    room_id       bigint       CONSTRAINT fk_slack_targets_room REFERENCES rooms (id),
+    webhook_url   varchar(500),
+    bot_token     varchar(255),
+    channel       varchar(100),
+    events        varchar(255) NOT NULL,
+    created_by_id bigint       NOT NULL CONSTRAINT fk_slack_targets_created_by REFERENCES users (id),
+    created_at    timestamptz,
+    updated_at    timestamptz,
+    deleted_at    timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_slack_targets_room_id ON slack_targets (room_id);
+CREATE INDEX IF NOT EXISTS idx_slack_targets_created_by_id ON slack_targets (created_by_id);
+CREATE INDEX IF NOT EXISTS idx_slack_targets_deleted_at ON slack_targets (deleted_at);
+
+-- Напоминание о начале бронирования отправляется один раз
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS reminder_sent_at timestamptz;
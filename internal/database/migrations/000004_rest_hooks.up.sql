@@ -0,0 +1,13 @@
+-- Подписки REST hooks для no-code интеграций (Zapier, Make)
+CREATE TABLE IF NOT EXISTS rest_hooks (
+    id         bigserial PRIMARY KEY,
+    api_key_id bigint        NOT NULL CONSTRAINT fk_rest_hooks_api_key REFERENCES api_keys (id),
+    event      varchar(50)   NOT NULL,
+    target_url varchar(1000) NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_rest_hooks_api_key_id ON rest_hooks (api_key_id);
+CREATE INDEX IF NOT EXISTS idx_rest_hooks_event ON rest_hooks (event);
+CREATE INDEX IF NOT EXISTS idx_rest_hooks_deleted_at ON rest_hooks (deleted_at);
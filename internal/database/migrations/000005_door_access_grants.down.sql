@@ -0,0 +1 @@
+DROP TABLE IF EXISTS door_access_grants;
@@ -0,0 +1,16 @@
+-- Доступ к дверям на время бронирования (DOOR_ACCESS_DRIVER)
+CREATE TABLE IF NOT EXISTS door_access_grants (
+    id          bigserial PRIMARY KEY,
+    booking_id  bigint       NOT NULL CONSTRAINT fk_door_access_grants_booking REFERENCES bookings (id),
+    door_id     varchar(100) NOT NULL,
+    external_id varchar(255),
+    pin         varchar(32),
+    valid_from  timestamptz  NOT NULL,
+    valid_until timestamptz  NOT NULL,
+    granted_at  timestamptz,
+    revoked_at  timestamptz,
+    created_at  timestamptz,
+    updated_at  timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_door_access_grants_booking_id ON door_access_grants (booking_id);
+CREATE INDEX IF NOT EXISTS idx_door_access_grants_valid_until ON door_access_grants (valid_until);
@@ -0,0 +1,8 @@
+-- Не выполнится, пока есть пользователи только с OIDC (telegram_id = 0)
+DROP INDEX IF EXISTS idx_users_telegram_id;
+CREATE UNIQUE INDEX idx_users_telegram_id ON users (telegram_id);
+
+DROP INDEX IF EXISTS idx_users_oidc_subject;
+DROP INDEX IF EXISTS idx_users_email;
+ALTER TABLE users DROP COLUMN IF EXISTS oidc_subject;
+ALTER TABLE users DROP COLUMN IF EXISTS email;
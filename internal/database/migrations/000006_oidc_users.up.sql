@@ -0,0 +1,8 @@
+-- Вход через OIDC: пользователи без Telegram получают telegram_id = 0
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email varchar(320);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS oidc_subject varchar(255);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_oidc_subject ON users (oidc_subject);
+
+DROP INDEX IF EXISTS idx_users_telegram_id;
+CREATE UNIQUE INDEX idx_users_telegram_id ON users (telegram_id) WHERE telegram_id <> 0;
@@ -0,0 +1,20 @@
+-- Синхронизация с каталогом (SCIM): отключение пользователей и команды
+ALTER TABLE users ADD COLUMN IF NOT EXISTS external_id varchar(255);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deactivated_at timestamptz;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_external_id ON users (external_id);
+
+CREATE TABLE IF NOT EXISTS teams (
+    id          bigserial PRIMARY KEY,
+    name        varchar(255) NOT NULL,
+    external_id varchar(255),
+    created_at  timestamptz,
+    updated_at  timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_teams_name ON teams (name);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_teams_external_id ON teams (external_id);
+
+CREATE TABLE IF NOT EXISTS team_members (
+    team_id bigint NOT NULL CONSTRAINT fk_team_members_team REFERENCES teams (id),
+    user_id bigint NOT NULL CONSTRAINT fk_team_members_user REFERENCES users (id),
+    PRIMARY KEY (team_id, user_id)
+);
@@ -0,0 +1,2 @@
+-- Когда userpic последний раз запрашивался из Telegram: синхронизация не чаще USERPIC_SYNC_INTERVAL
+ALTER TABLE users ADD COLUMN IF NOT EXISTS userpic_synced_at timestamptz;
@@ -0,0 +1,2 @@
+-- Когда администратор последний раз отправил объявление подписчикам комнаты: не чаще BROADCAST_MIN_INTERVAL
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS broadcast_at timestamptz;
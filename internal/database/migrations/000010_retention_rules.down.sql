@@ -0,0 +1,2 @@
+ALTER TABLE bookings DROP COLUMN IF EXISTS retention_exempt;
+DROP TABLE IF EXISTS retention_rules;
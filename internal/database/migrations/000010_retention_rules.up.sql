@@ -0,0 +1,13 @@
+-- Правила хранения данных: фоновая задача окончательно удаляет строки сущности старше max_age_days
+CREATE TABLE IF NOT EXISTS retention_rules (
+    id           bigserial PRIMARY KEY,
+    entity       varchar(50) NOT NULL,
+    max_age_days integer     NOT NULL,
+    enabled      boolean     NOT NULL DEFAULT true,
+    created_at   timestamptz,
+    updated_at   timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_retention_rules_entity ON retention_rules (entity);
+
+-- Бронирования с флагом исключения (например, нужные для разбирательства) правилами не удаляются
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS retention_exempt boolean NOT NULL DEFAULT false;
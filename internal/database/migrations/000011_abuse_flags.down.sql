@@ -0,0 +1,2 @@
+ALTER TABLE users DROP COLUMN IF EXISTS booking_limit;
+DROP TABLE IF EXISTS abuse_flags;
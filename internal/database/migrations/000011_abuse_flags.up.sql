@@ -0,0 +1,20 @@
+-- Очередь проверки: пользователи, отмеченные правилами выявления злоупотреблений бронированиями
+CREATE TABLE IF NOT EXISTS abuse_flags (
+    id             bigserial PRIMARY KEY,
+    user_id        bigint      NOT NULL CONSTRAINT fk_abuse_flags_user REFERENCES users (id),
+    reason         varchar(30) NOT NULL,
+    count          integer     NOT NULL,
+    status         varchar(20) NOT NULL DEFAULT 'open',
+    booking_limit  integer,
+    reviewed_by_id bigint      CONSTRAINT fk_abuse_flags_reviewed_by REFERENCES users (id),
+    reviewed_at    timestamptz,
+    created_at     timestamptz,
+    updated_at     timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_abuse_flags_user_id ON abuse_flags (user_id);
+CREATE INDEX IF NOT EXISTS idx_abuse_flags_status ON abuse_flags (status);
+-- Пока отметка не рассмотрена, повторная проверка не создаёт новую по той же причине
+CREATE UNIQUE INDEX IF NOT EXISTS idx_abuse_flags_open ON abuse_flags (user_id, reason) WHERE status = 'open';
+
+-- Лимит активных бронирований пользователя (NULL - без ограничения)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS booking_limit integer;
@@ -0,0 +1,3 @@
+-- Учёт использования для платных коворкингов: часы бронирований, включённые в месячный тариф команды
+-- (NULL - команда не тарифицируется, перерасход не считается)
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS included_hours integer;
@@ -0,0 +1,10 @@
+-- Календарь нерабочих дней пространства: в эти даты (в часовом поясе OFFICE_TIMEZONE) бронировать нельзя
+CREATE TABLE IF NOT EXISTS holidays (
+    id         bigserial PRIMARY KEY,
+    date       varchar(10)  NOT NULL, -- YYYY-MM-DD
+    name       varchar(255) NOT NULL,
+    source     varchar(20)  NOT NULL DEFAULT 'manual',
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_holidays_date ON holidays (date);
@@ -0,0 +1,15 @@
+-- Отзывы участников о комнате и оборудовании после завершения бронирования
+CREATE TABLE IF NOT EXISTS booking_feedback (
+    id         bigserial PRIMARY KEY,
+    booking_id bigint   NOT NULL CONSTRAINT fk_booking_feedback_booking REFERENCES bookings (id),
+    user_id    bigint   NOT NULL CONSTRAINT fk_booking_feedback_user REFERENCES users (id),
+    room_id    bigint   NOT NULL CONSTRAINT fk_booking_feedback_room REFERENCES rooms (id),
+    rating     smallint NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    comment    text,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+-- Один отзыв на участника бронирования
+CREATE UNIQUE INDEX IF NOT EXISTS idx_booking_feedback_booking_user ON booking_feedback (booking_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_booking_feedback_user_id ON booking_feedback (user_id);
+CREATE INDEX IF NOT EXISTS idx_booking_feedback_room_id ON booking_feedback (room_id);
@@ -0,0 +1,10 @@
+-- Отметки о приходе участников на бронирование (сканирование QR-кода комнаты)
+CREATE TABLE IF NOT EXISTS booking_check_ins (
+    id            bigserial PRIMARY KEY,
+    booking_id    bigint      NOT NULL CONSTRAINT fk_booking_check_ins_booking REFERENCES bookings (id),
+    user_id       bigint      NOT NULL CONSTRAINT fk_booking_check_ins_user REFERENCES users (id),
+    checked_in_at timestamptz NOT NULL
+);
+-- Одна отметка на участника бронирования
+CREATE UNIQUE INDEX IF NOT EXISTS idx_booking_check_ins_booking_user ON booking_check_ins (booking_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_booking_check_ins_user_id ON booking_check_ins (user_id);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS cleaning_tasks;
+
+ALTER TABLE rooms DROP COLUMN IF EXISTS usage_minutes;
+ALTER TABLE rooms DROP COLUMN IF EXISTS cleaning_cursor;
+ALTER TABLE rooms DROP COLUMN IF EXISTS needs_cleaning;
+ALTER TABLE rooms DROP COLUMN IF EXISTS reset_after_hours;
+ALTER TABLE rooms DROP COLUMN IF EXISTS reset_required;
@@ -0,0 +1,21 @@
+-- Уборка комнат после использования: настройки комнаты и курсор учёта бронирований
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS reset_required boolean NOT NULL DEFAULT false;
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS reset_after_hours integer NOT NULL DEFAULT 0;
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS needs_cleaning boolean NOT NULL DEFAULT false;
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS cleaning_cursor timestamptz;
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS usage_minutes integer NOT NULL DEFAULT 0;
+
+-- Задачи уборки для персонала пространства
+CREATE TABLE IF NOT EXISTS cleaning_tasks (
+    id              bigserial PRIMARY KEY,
+    room_id         bigint      NOT NULL CONSTRAINT fk_cleaning_tasks_room REFERENCES rooms (id),
+    booking_id      bigint      CONSTRAINT fk_cleaning_tasks_booking REFERENCES bookings (id),
+    usage_minutes   integer     NOT NULL,
+    status          varchar(20) NOT NULL DEFAULT 'open',
+    completed_by_id bigint      CONSTRAINT fk_cleaning_tasks_completed_by REFERENCES users (id),
+    completed_at    timestamptz,
+    created_at      timestamptz,
+    updated_at      timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_cleaning_tasks_room_id ON cleaning_tasks (room_id);
+CREATE INDEX IF NOT EXISTS idx_cleaning_tasks_status ON cleaning_tasks (status);
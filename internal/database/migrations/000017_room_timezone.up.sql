@@ -0,0 +1,2 @@
+-- Часовой пояс комнаты (IANA); пусто - часовой пояс пространства (OFFICE_TIMEZONE)
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS timezone varchar(64) NOT NULL DEFAULT '';
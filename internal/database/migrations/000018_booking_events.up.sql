@@ -0,0 +1,40 @@
+-- Неизменяемый поток событий жизненного цикла бронирований; ID задаёт порядок событий
+CREATE TABLE IF NOT EXISTS booking_events (
+    id         bigserial PRIMARY KEY,
+    booking_id bigint      NOT NULL CONSTRAINT fk_booking_events_booking REFERENCES bookings (id),
+    type       varchar(20) NOT NULL,
+    actor_id   bigint,
+    data       jsonb,
+    created_at timestamptz NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_booking_events_booking_id ON booking_events (booking_id);
+CREATE INDEX IF NOT EXISTS idx_booking_events_created_at ON booking_events (created_at);
+
+-- Существующие бронирования получают событие created с текущим состоянием
+-- и завершающее событие, если они уже отменены или завершены
+INSERT INTO booking_events (booking_id, type, actor_id, data, created_at)
+SELECT b.id, 'created', b.creator_id, jsonb_strip_nulls(jsonb_build_object(
+           'room_id', b.room_id,
+           'creator_id', b.creator_id,
+           'start_time', b.start_time,
+           'end_time', b.end_time,
+           'title', b.title,
+           'description', NULLIF(b.description, ''),
+           'estimated_participants', b.estimated_participants,
+           'is_joinable', b.is_joinable,
+           'participant_ids', (SELECT jsonb_agg(bp.user_id ORDER BY bp.user_id) FROM booking_participants bp WHERE bp.booking_id = b.id)
+       )), COALESCE(b.created_at, now())
+FROM bookings b
+ORDER BY b.id;
+
+INSERT INTO booking_events (booking_id, type, data, created_at)
+SELECT b.id, 'completed', jsonb_build_object('end_time', b.end_time), COALESCE(b.updated_at, b.end_time)
+FROM bookings b
+WHERE b.status = 'completed' AND b.deleted_at IS NULL
+ORDER BY b.id;
+
+INSERT INTO booking_events (booking_id, type, created_at)
+SELECT b.id, 'cancelled', COALESCE(b.deleted_at, b.updated_at, now())
+FROM bookings b
+WHERE b.status = 'cancelled' OR b.deleted_at IS NOT NULL
+ORDER BY b.id;
@@ -0,0 +1,4 @@
+ALTER TABLE bookings DROP COLUMN IF EXISTS license_plate;
+
+DROP INDEX IF EXISTS idx_rooms_kind;
+ALTER TABLE rooms DROP COLUMN IF EXISTS kind;
@@ -0,0 +1,6 @@
+-- Парковочные места - бронируемые ресурсы вида parking в таблице rooms
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS kind varchar(20) NOT NULL DEFAULT 'room';
+CREATE INDEX IF NOT EXISTS idx_rooms_kind ON rooms (kind);
+
+-- Номер автомобиля бронирования парковочного места
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS license_plate varchar(20);
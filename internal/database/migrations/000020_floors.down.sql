@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_rooms_floor_id;
+ALTER TABLE rooms DROP COLUMN IF EXISTS map_y;
+ALTER TABLE rooms DROP COLUMN IF EXISTS map_x;
+ALTER TABLE rooms DROP COLUMN IF EXISTS floor_id;
+DROP TABLE IF EXISTS floors;
@@ -0,0 +1,18 @@
+-- Этажи пространства с планами
+CREATE TABLE IF NOT EXISTS floors (
+    id               bigserial PRIMARY KEY,
+    name             varchar(255) NOT NULL,
+    level            integer      NOT NULL DEFAULT 0,
+    image_path       varchar(255) NOT NULL DEFAULT '',
+    image_type       varchar(50)  NOT NULL DEFAULT '',
+    image_updated_at timestamptz,
+    created_at       timestamptz,
+    updated_at       timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_floors_name ON floors (name);
+
+-- Положение комнаты на плане этажа (доли ширины и высоты изображения)
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS floor_id bigint CONSTRAINT fk_rooms_floor REFERENCES floors (id) ON DELETE SET NULL;
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS map_x double precision;
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS map_y double precision;
+CREATE INDEX IF NOT EXISTS idx_rooms_floor_id ON rooms (floor_id);
@@ -0,0 +1,2 @@
+-- Объявление скорого начала бронирований комнаты в групповом чате
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS announce_start boolean NOT NULL DEFAULT false;
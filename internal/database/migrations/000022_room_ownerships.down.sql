@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS room_delegations;
+DROP TABLE IF EXISTS room_ownerships;
@@ -0,0 +1,26 @@
+-- Комнаты, закреплённые за командами-резидентами
+CREATE TABLE IF NOT EXISTS room_ownerships (
+    id         bigserial PRIMARY KEY,
+    room_id    bigint NOT NULL CONSTRAINT fk_room_ownerships_room REFERENCES rooms (id) ON DELETE CASCADE,
+    team_id    bigint NOT NULL CONSTRAINT fk_room_ownerships_team REFERENCES teams (id) ON DELETE CASCADE,
+    note       text   NOT NULL DEFAULT '',
+    until      timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+-- Одна команда-владелец на комнату
+CREATE UNIQUE INDEX IF NOT EXISTS idx_room_ownerships_room_id ON room_ownerships (room_id);
+CREATE INDEX IF NOT EXISTS idx_room_ownerships_team_id ON room_ownerships (team_id);
+
+-- Время закреплённой комнаты, переданное владельцами другим пользователям
+CREATE TABLE IF NOT EXISTS room_delegations (
+    id            bigserial PRIMARY KEY,
+    ownership_id  bigint      NOT NULL CONSTRAINT fk_room_delegations_ownership REFERENCES room_ownerships (id) ON DELETE CASCADE,
+    user_id       bigint      NOT NULL CONSTRAINT fk_room_delegations_user REFERENCES users (id),
+    start_time    timestamptz NOT NULL,
+    end_time      timestamptz NOT NULL,
+    created_by_id bigint      NOT NULL,
+    created_at    timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_room_delegations_ownership_id ON room_delegations (ownership_id);
+CREATE INDEX IF NOT EXISTS idx_room_delegations_user_id ON room_delegations (user_id);
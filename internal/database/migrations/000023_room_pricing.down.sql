@@ -0,0 +1,2 @@
+ALTER TABLE bookings DROP COLUMN IF EXISTS estimated_cost;
+ALTER TABLE rooms DROP COLUMN IF EXISTS hourly_price;
@@ -0,0 +1,3 @@
+-- Цена часа комнаты и оценка стоимости бронирования (в минимальных единицах валюты PRICE_CURRENCY)
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS hourly_price bigint NOT NULL DEFAULT 0;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS estimated_cost bigint NOT NULL DEFAULT 0;
@@ -0,0 +1,24 @@
+-- Заявки сторонних организаций на аренду комнат (публичная форма с капчей)
+CREATE TABLE IF NOT EXISTS rental_requests (
+    id             bigserial PRIMARY KEY,
+    room_id        bigint       NOT NULL CONSTRAINT fk_rental_requests_room REFERENCES rooms (id) ON DELETE CASCADE,
+    name           text         NOT NULL,
+    email          text         NOT NULL,
+    phone          text         NOT NULL DEFAULT '',
+    organization   text         NOT NULL DEFAULT '',
+    language       varchar(8)   NOT NULL DEFAULT '',
+    title          text         NOT NULL,
+    description    text         NOT NULL DEFAULT '',
+    participants   bigint       NOT NULL DEFAULT 1,
+    start_time     timestamptz  NOT NULL,
+    end_time       timestamptz  NOT NULL,
+    status         varchar(20)  NOT NULL DEFAULT 'pending',
+    reject_reason  text         NOT NULL DEFAULT '',
+    booking_id     bigint CONSTRAINT fk_rental_requests_booking REFERENCES bookings (id) ON DELETE SET NULL,
+    reviewed_by_id bigint,
+    reviewed_at    timestamptz,
+    created_at     timestamptz,
+    updated_at     timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_rental_requests_room_id ON rental_requests (room_id);
+CREATE INDEX IF NOT EXISTS idx_rental_requests_status ON rental_requests (status);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS booking_waitlist;
+ALTER TABLE bookings DROP COLUMN IF EXISTS max_participants;
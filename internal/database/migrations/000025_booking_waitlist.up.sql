@@ -0,0 +1,13 @@
+-- Лимит мест в мероприятии (вместе с создателем); 0 - без ограничения
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS max_participants bigint NOT NULL DEFAULT 0;
+
+-- Очередь на место в заполненном мероприятии
+CREATE TABLE IF NOT EXISTS booking_waitlist (
+    id         bigserial PRIMARY KEY,
+    booking_id bigint NOT NULL CONSTRAINT fk_booking_waitlist_booking REFERENCES bookings (id) ON DELETE CASCADE,
+    user_id    bigint NOT NULL CONSTRAINT fk_booking_waitlist_user REFERENCES users (id) ON DELETE CASCADE,
+    created_at timestamptz
+);
+-- Один пользователь - одно место в очереди бронирования
+CREATE UNIQUE INDEX IF NOT EXISTS idx_booking_waitlist_booking_user ON booking_waitlist (booking_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_booking_waitlist_user_id ON booking_waitlist (user_id);
@@ -0,0 +1,17 @@
+-- Рассылки о бронированиях, задержанные фильтром содержимого до проверки администратором
+CREATE TABLE IF NOT EXISTS booking_quarantines (
+    id             bigserial PRIMARY KEY,
+    booking_id     bigint      NOT NULL CONSTRAINT fk_booking_quarantines_booking REFERENCES bookings (id) ON DELETE CASCADE,
+    event          varchar(40) NOT NULL,
+    changes        jsonb,
+    violations     jsonb,
+    status         varchar(20) NOT NULL DEFAULT 'pending',
+    reviewed_by_id bigint,
+    reviewed_at    timestamptz,
+    created_at     timestamptz,
+    updated_at     timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_booking_quarantines_booking_id ON booking_quarantines (booking_id);
+CREATE INDEX IF NOT EXISTS idx_booking_quarantines_status ON booking_quarantines (status);
+-- Не больше одной ожидающей проверки записи на бронирование
+CREATE UNIQUE INDEX IF NOT EXISTS idx_booking_quarantines_pending ON booking_quarantines (booking_id) WHERE status = 'pending';
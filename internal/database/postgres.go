@@ -2,41 +2,71 @@ package database
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/space/backend/internal/models"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
 )
 
-// Connect creates a connection to PostgreSQL database
-func Connect(databaseURL string, debug bool) (*gorm.DB, error) {
-	// Настройка логгера
-	logLevel := logger.Silent
-	if debug {
-		logLevel = logger.Info
-	}
+// ReplicaResolver - имя резолвера dbresolver для чтения с реплики
+const ReplicaResolver = "replica"
 
-	// Добавляем параметр TimeZone=UTC если его нет в URL
-	// Это критически важно для корректной работы с часовыми поясами
-	if !strings.Contains(databaseURL, "TimeZone=") {
-		separator := "?"
-		if strings.Contains(databaseURL, "?") {
-			separator = "&"
-		}
-		databaseURL = databaseURL + separator + "TimeZone=UTC"
+// maxConnectBackoff ограничивает паузу между попытками подключения
+const maxConnectBackoff = 10 * time.Second
+
+// ConnectOptions configures the database connection
+type ConnectOptions struct {
+	Debug   bool          // Логировать SQL-запросы
+	MaxWait time.Duration // Сколько ждать доступности БД при старте (0 - одна попытка)
+	Backoff time.Duration // Начальная пауза между попытками (удваивается до maxConnectBackoff)
+
+	// SlowQueryThreshold - запросы дольше порога пишутся как warning и считаются в metrics (0 - выключено)
+	SlowQueryThreshold time.Duration
+
+	// StatementTimeout - statement_timeout сессии PostgreSQL: сервер сам прерывает запросы дольше него,
+	// даже если клиент их уже не ждёт (0 - настройка сервера). На SQLite не действует
+	StatementTimeout time.Duration
+
+	// ReplicaURL - необязательная реплика для тяжёлых чтений (календарь, телефонная книга)
+	// Запросы попадают на неё только через dbresolver.Use(ReplicaResolver), остальные идут в основную БД
+	ReplicaURL string
+
+	// PrepareStmt кэширует подготовленные выражения на каждом соединении пула
+	// (несовместимо с PgBouncer в режиме transaction)
+	PrepareStmt bool
+	// SkipDefaultTransaction отключает неявную транзакцию вокруг одиночных Create/Update/Delete;
+	// многошаговые операции используют явные транзакции (TxManager)
+	SkipDefaultTransaction bool
+}
+
+// Connect creates a connection to PostgreSQL database
+// (или SQLite для DATABASE_URL=sqlite://..., если бинарник собран с -tags sqlite)
+// Если БД недоступна (например, ещё стартует при деплое), подключение повторяется
+// с экспоненциальной паузой, пока не истечёт opts.MaxWait
+func Connect(databaseURL string, opts ConnectOptions) (*gorm.DB, error) {
+	dialector, err := openDialector(databaseURL, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	// Открываем подключение к PostgreSQL
-	db, err := gorm.Open(postgres.Open(databaseURL), &gorm.Config{
-		Logger: logger.Default.LogMode(logLevel),
-		NowFunc: func() time.Time {
-			// Всегда используем UTC для консистентности
-			return time.Now().UTC()
-		},
+	// Открываем подключение (gorm.Open проверяет соединение ping-ом)
+	var db *gorm.DB
+	err = retryWithBackoff(opts.MaxWait, opts.Backoff, func() error {
+		var err error
+		db, err = gorm.Open(dialector, &gorm.Config{
+			Logger:                 newQueryLogger(opts.SlowQueryThreshold, opts.Debug),
+			PrepareStmt:            opts.PrepareStmt,
+			SkipDefaultTransaction: opts.SkipDefaultTransaction,
+			NowFunc: func() time.Time {
+				// Всегда используем UTC для консистентности
+				return time.Now().UTC()
+			},
+		})
+		return err
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
@@ -49,32 +79,96 @@ func Connect(databaseURL string, debug bool) (*gorm.DB, error) {
 	}
 
 	// Настройка connection pool
-	sqlDB.SetMaxOpenConns(25)
-	sqlDB.SetMaxIdleConns(5)
+	// SQLite допускает одного писателя, а :memory: у каждого соединения своя - одно соединение
+	if IsSQLite(databaseURL) {
+		sqlDB.SetMaxOpenConns(1)
+	} else {
+		sqlDB.SetMaxOpenConns(25)
+		sqlDB.SetMaxIdleConns(5)
+	}
+
+	// Реплика подключается как именованный резолвер: без явного Use запросы её не затрагивают
+	if opts.ReplicaURL != "" {
+		resolver := dbresolver.Register(dbresolver.Config{
+			Replicas: []gorm.Dialector{postgres.Open(postgresDSN(opts.ReplicaURL, opts))},
+		}, ReplicaResolver).
+			SetMaxOpenConns(25).
+			SetMaxIdleConns(5)
+		if err := db.Use(resolver); err != nil {
+			return nil, fmt.Errorf("failed to connect to read replica: %w", err)
+		}
+		slog.Info("read replica configured")
+	}
 
-	log.Println("✅ Successfully connected to database")
+	slog.Info("connected to database")
 	return db, nil
 }
 
-// Migrate runs database migrations
-func Migrate(db *gorm.DB) error {
-	log.Println("Running database migrations...")
+// IsSQLite сообщает, указывает ли DATABASE_URL на SQLite (sqlite://path)
+func IsSQLite(databaseURL string) bool {
+	return strings.HasPrefix(databaseURL, sqliteScheme)
+}
 
-	err := db.AutoMigrate(
-		&models.User{},
-		&models.Room{},
-		&models.Equipment{},
-		&models.Instruction{},
-		&models.Booking{},
-		&models.NotificationSubscription{},
-	)
+// openDialector выбирает драйвер GORM по DATABASE_URL
+func openDialector(databaseURL string, opts ConnectOptions) (gorm.Dialector, error) {
+	if IsSQLite(databaseURL) {
+		return sqliteDialector(strings.TrimPrefix(databaseURL, sqliteScheme))
+	}
+	return postgres.Open(postgresDSN(databaseURL, opts)), nil
+}
 
-	if err != nil {
-		return fmt.Errorf("failed to run migrations: %w", err)
+// postgresDSN добавляет к URL параметры сессии: часовой пояс и statement_timeout
+// pgx передаёт неизвестные драйверу параметры URL серверу как параметры сессии
+func postgresDSN(databaseURL string, opts ConnectOptions) string {
+	return withStatementTimeout(withUTC(databaseURL), opts.StatementTimeout)
+}
+
+// withStatementTimeout добавляет statement_timeout (в миллисекундах), если он задан и его нет в URL
+func withStatementTimeout(databaseURL string, timeout time.Duration) string {
+	if timeout <= 0 || strings.Contains(databaseURL, "statement_timeout=") {
+		return databaseURL
+	}
+	separator := "?"
+	if strings.Contains(databaseURL, "?") {
+		separator = "&"
 	}
+	return databaseURL + separator + "statement_timeout=" + strconv.FormatInt(timeout.Milliseconds(), 10)
+}
 
-	log.Println("Migrations completed successfully")
-	return nil
+// withUTC добавляет параметр TimeZone=UTC если его нет в URL
+// Это критически важно для корректной работы с часовыми поясами
+func withUTC(databaseURL string) string {
+	if strings.Contains(databaseURL, "TimeZone=") {
+		return databaseURL
+	}
+	separator := "?"
+	if strings.Contains(databaseURL, "?") {
+		separator = "&"
+	}
+	return databaseURL + separator + "TimeZone=UTC"
+}
+
+// retryWithBackoff вызывает fn, пока она не завершится успешно или не истечёт maxWait
+// Пауза между попытками начинается с backoff и удваивается до maxConnectBackoff
+func retryWithBackoff(maxWait, backoff time.Duration, fn func() error) error {
+	deadline := time.Now().Add(maxWait)
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		if time.Now().Add(backoff).After(deadline) {
+			if attempt > 1 {
+				return fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+			}
+			return err
+		}
+
+		slog.Warn("database is not available, retrying", "attempt", attempt, "backoff", backoff.String(), "error", err)
+		time.Sleep(backoff)
+		backoff = min(backoff*2, maxConnectBackoff)
+	}
 }
 
 // Close closes the database connection
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryWithBackoff_SucceedsAfterFailures(t *testing.T) {
+	attempts := 0
+	err := retryWithBackoff(time.Second, time.Millisecond, func() error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("the database system is starting up")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got: %d", attempts)
+	}
+}
+
+func TestRetryWithBackoff_GivesUpAfterMaxWait(t *testing.T) {
+	errDown := errors.New("connection refused")
+	attempts := 0
+	err := retryWithBackoff(20*time.Millisecond, 5*time.Millisecond, func() error {
+		attempts++
+		return errDown
+	})
+	if !errors.Is(err, errDown) {
+		t.Errorf("Expected wrapped connection error, got: %v", err)
+	}
+	if attempts < 2 {
+		t.Errorf("Expected several attempts, got: %d", attempts)
+	}
+}
+
+func TestRetryWithBackoff_NoWaitSingleAttempt(t *testing.T) {
+	attempts := 0
+	_ = retryWithBackoff(0, time.Millisecond, func() error {
+		attempts++
+		return errors.New("connection refused")
+	})
+	if attempts != 1 {
+		t.Errorf("Expected 1 attempt, got: %d", attempts)
+	}
+}
+
+func TestPostgresDSN(t *testing.T) {
+	tests := []struct {
+		url     string
+		timeout time.Duration
+		want    string
+	}{
+		{"postgres://db/space", 30 * time.Second, "postgres://db/space?TimeZone=UTC&statement_timeout=30000"},
+		{"postgres://db/space?sslmode=disable", 0, "postgres://db/space?sslmode=disable&TimeZone=UTC"},
+		// Явно заданный в URL таймаут не переопределяется
+		{"postgres://db/space?statement_timeout=5000", time.Minute, "postgres://db/space?statement_timeout=5000&TimeZone=UTC"},
+	}
+	for _, tt := range tests {
+		if got := postgresDSN(tt.url, ConnectOptions{StatementTimeout: tt.timeout}); got != tt.want {
+			t.Errorf("postgresDSN(%q, %s) = %q, want %q", tt.url, tt.timeout, got, tt.want)
+		}
+	}
+}
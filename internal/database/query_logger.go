@@ -0,0 +1,97 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/space/backend/internal/logger"
+	"github.com/space/backend/internal/metrics"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// queryLogger - логгер GORM поверх slog
+// Медленные запросы (дольше slowThreshold) пишутся как warning со структурированными полями
+// и учитываются в metrics.DBSlowQueries; в debug-режиме пишутся все запросы
+type queryLogger struct {
+	slowThreshold time.Duration // 0 - не отслеживать медленные запросы
+	debug         bool
+}
+
+func newQueryLogger(slowThreshold time.Duration, debug bool) *queryLogger {
+	return &queryLogger{slowThreshold: slowThreshold, debug: debug}
+}
+
+// LogMode implements gormlogger.Interface
+// Уровень определяется конфигурацией, а не GORM: db.Debug() включает вывод всех запросов
+func (l *queryLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	copied := *l
+	copied.debug = level >= gormlogger.Info
+	return &copied
+}
+
+// Info implements gormlogger.Interface
+func (l *queryLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.debug {
+		logger.FromContext(ctx).InfoContext(ctx, "gorm", "message", msg, "args", args)
+	}
+}
+
+// Warn implements gormlogger.Interface
+func (l *queryLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	logger.FromContext(ctx).WarnContext(ctx, "gorm", "message", msg, "args", args)
+}
+
+// Error implements gormlogger.Interface
+func (l *queryLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	logger.FromContext(ctx).ErrorContext(ctx, "gorm", "message", msg, "args", args)
+}
+
+// Trace implements gormlogger.Interface; вызывается GORM после каждого запроса
+func (l *queryLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if queryCancelled(err) {
+		metrics.DBCancelledQueries.Add(1)
+	}
+
+	elapsed := time.Since(begin)
+	slow := l.slowThreshold > 0 && elapsed > l.slowThreshold
+	if !slow && !l.debug {
+		return
+	}
+
+	sql, rows := fc()
+	log := logger.FromContext(ctx)
+
+	if slow {
+		metrics.DBSlowQueries.Add(1)
+		log.WarnContext(ctx, "slow query",
+			"query", sql,
+			"duration", elapsed,
+			"rows", rows,
+			"threshold", l.slowThreshold,
+		)
+		return
+	}
+
+	// Отсутствие записи - штатная ситуация, ошибкой не считаем
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.InfoContext(ctx, "query failed", "query", sql, "duration", elapsed, "rows", rows, "error", err)
+		return
+	}
+	log.InfoContext(ctx, "query", "query", sql, "duration", elapsed, "rows", rows)
+}
+
+// queryCancelled сообщает, что запрос прерван: клиент ушёл, истёк дедлайн запроса
+// или сервер сработал по statement_timeout (SQLSTATE 57014 query_canceled)
+func queryCancelled(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "57014"
+}
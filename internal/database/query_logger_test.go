@@ -0,0 +1,53 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/space/backend/internal/metrics"
+)
+
+func TestQueryLoggerCountsSlowQueries(t *testing.T) {
+	l := newQueryLogger(100*time.Millisecond, false)
+	ctx := context.Background()
+	sql := func() (string, int64) { return "SELECT 1", 1 }
+
+	before := metrics.DBSlowQueries.Value()
+
+	l.Trace(ctx, time.Now().Add(-10*time.Millisecond), sql, nil)
+	if got := metrics.DBSlowQueries.Value() - before; got != 0 {
+		t.Errorf("Expected fast query not to be counted, got: %d", got)
+	}
+
+	l.Trace(ctx, time.Now().Add(-time.Second), sql, nil)
+	if got := metrics.DBSlowQueries.Value() - before; got != 1 {
+		t.Errorf("Expected 1 slow query, got: %d", got)
+	}
+
+	// Нулевой порог выключает отслеживание
+	newQueryLogger(0, false).Trace(ctx, time.Now().Add(-time.Hour), sql, nil)
+	if got := metrics.DBSlowQueries.Value() - before; got != 1 {
+		t.Errorf("Expected disabled threshold not to count, got: %d", got)
+	}
+}
+
+func TestQueryLoggerCountsCancelledQueries(t *testing.T) {
+	l := newQueryLogger(0, false)
+	ctx := context.Background()
+	sql := func() (string, int64) { return "SELECT pg_sleep(60)", 0 }
+
+	before := metrics.DBCancelledQueries.Value()
+	l.Trace(ctx, time.Now(), sql, context.Canceled)
+	l.Trace(ctx, time.Now(), sql, fmt.Errorf("read: %w", context.DeadlineExceeded))
+	l.Trace(ctx, time.Now(), sql, &pgconn.PgError{Code: "57014", Message: "canceling statement due to statement timeout"})
+	l.Trace(ctx, time.Now(), sql, errors.New("connection refused"))
+	l.Trace(ctx, time.Now(), sql, nil)
+
+	if got := metrics.DBCancelledQueries.Value() - before; got != 3 {
+		t.Errorf("Expected 3 cancelled queries, got: %d", got)
+	}
+}
@@ -0,0 +1,121 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// ErrAlreadySeeded возвращается, если в базе уже есть комнаты
+var ErrAlreadySeeded = errors.New("database already contains rooms, skipping seed")
+
+// Seed заполняет пустую базу демонстрационными данными: пользователи, комнаты,
+// оборудование, инструкции и бронирования на ближайшие дни
+// Предназначен для development и staging - не запускайте в production
+func Seed(db *gorm.DB) error {
+	var roomCount int64
+	if err := db.Model(&models.Room{}).Count(&roomCount).Error; err != nil {
+		return fmt.Errorf("failed to count rooms: %w", err)
+	}
+	if roomCount > 0 {
+		return ErrAlreadySeeded
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		users := seedUsers()
+		if err := tx.Create(&users).Error; err != nil {
+			return fmt.Errorf("failed to seed users: %w", err)
+		}
+
+		rooms := seedRooms()
+		if err := tx.Create(&rooms).Error; err != nil {
+			return fmt.Errorf("failed to seed rooms: %w", err)
+		}
+
+		equipment := seedEquipment(rooms)
+		if err := tx.Create(&equipment).Error; err != nil {
+			return fmt.Errorf("failed to seed equipment: %w", err)
+		}
+
+		instructions := seedInstructions(equipment)
+		if err := tx.Create(&instructions).Error; err != nil {
+			return fmt.Errorf("failed to seed instructions: %w", err)
+		}
+
+		bookings := seedBookings(rooms, users, time.Now().UTC())
+		if err := tx.Create(&bookings).Error; err != nil {
+			return fmt.Errorf("failed to seed bookings: %w", err)
+		}
+
+		slog.Info("database seeded",
+			"users", len(users),
+			"rooms", len(rooms),
+			"equipment", len(equipment),
+			"instructions", len(instructions),
+			"bookings", len(bookings),
+		)
+		return nil
+	})
+}
+
+// seedUsers - тестовые пользователи (Telegram ID из диапазона, не пересекающегося с реальными)
+func seedUsers() []models.User {
+	return []models.User{
+		{TelegramID: 100000001, Username: "space_admin", FirstName: "Анна", LastName: "Смирнова", PhoneNumber: "+79990000001", LanguageCode: "ru", Role: models.RoleAdmin, IsInPhoneBook: true},
+		{TelegramID: 100000002, Username: "ivan_dev", FirstName: "Иван", LastName: "Петров", PhoneNumber: "+79990000002", LanguageCode: "ru", Role: models.RoleUser, IsInPhoneBook: true},
+		{TelegramID: 100000003, Username: "maria_design", FirstName: "Мария", LastName: "Козлова", LanguageCode: "ru", Role: models.RoleUser},
+		{TelegramID: 100000004, Username: "john_guest", FirstName: "John", LastName: "Doe", LanguageCode: "en", Role: models.RoleUser},
+	}
+}
+
+// seedRooms - комнаты коворкинга с атрибутами для отображения в календаре
+func seedRooms() []models.Room {
+	return []models.Room{
+		{Name: "Переговорная «Орбита»", Description: "Переговорная с экраном для созвонов", Capacity: 8, IsActive: true, Attributes: datatypes.JSON(`{"color": "#4F46E5", "location": "2 этаж", "area_sqm": 20}`)},
+		{Name: "Зал «Галактика»", Description: "Большой зал для митапов и лекций", Capacity: 40, IsActive: true, Attributes: datatypes.JSON(`{"color": "#059669", "location": "1 этаж", "area_sqm": 80}`)},
+		{Name: "Фокус-комната", Description: "Тихая комната для одного-двух человек", Capacity: 2, IsActive: true, Attributes: datatypes.JSON(`{"color": "#D97706", "location": "2 этаж", "area_sqm": 6}`)},
+		{Name: "Студия подкастов", Description: "Звукоизолированная студия", Capacity: 4, IsActive: false, Attributes: datatypes.JSON(`{"color": "#DC2626", "location": "цоколь", "area_sqm": 12}`)},
+	}
+}
+
+// seedEquipment - оборудование в комнатах (ожидает комнаты в порядке seedRooms)
+func seedEquipment(rooms []models.Room) []models.Equipment {
+	return []models.Equipment{
+		{RoomID: rooms[0].ID, Name: "Экран 65\"", Description: "Подключение по HDMI и AirPlay", IsAvailable: true},
+		{RoomID: rooms[0].ID, Name: "Спикерфон", Description: "Jabra для видеозвонков", IsAvailable: true},
+		{RoomID: rooms[1].ID, Name: "Проектор", Description: "Full HD, пульт у администратора", IsAvailable: true},
+		{RoomID: rooms[1].ID, Name: "Радиомикрофоны", Description: "Два ручных микрофона", IsAvailable: true},
+		{RoomID: rooms[3].ID, Name: "Микшерный пульт", Description: "На обслуживании", IsAvailable: false},
+	}
+}
+
+// seedInstructions - инструкции к оборудованию (ожидает оборудование в порядке seedEquipment)
+func seedInstructions(equipment []models.Equipment) []models.Instruction {
+	return []models.Instruction{
+		{EquipmentID: equipment[0].ID, Title: "Как подключить ноутбук", Type: models.InstructionTypeText, Content: "Возьмите HDMI-кабель со стола и выберите вход HDMI 1 на пульте.", Order: 1},
+		{EquipmentID: equipment[0].ID, Title: "Трансляция по AirPlay", Type: models.InstructionTypeLink, URL: "https://support.apple.com/HT204289", Order: 2},
+		{EquipmentID: equipment[2].ID, Title: "Включение проектора", Type: models.InstructionTypeText, Content: "Кнопка питания на пульте, прогрев около минуты.", Order: 1},
+	}
+}
+
+// seedBookings - бронирования на сегодня и ближайшие дни относительно now
+func seedBookings(rooms []models.Room, users []models.User, now time.Time) []models.Booking {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	at := func(dayOffset, hour int) time.Time {
+		return day.AddDate(0, 0, dayOffset).Add(time.Duration(hour) * time.Hour)
+	}
+
+	return []models.Booking{
+		{RoomID: rooms[0].ID, CreatorID: users[1].ID, StartTime: at(0, 10), EndTime: at(0, 11), Title: "Daily standup", EstimatedParticipants: 6, IsJoinable: true, Status: models.BookingStatusConfirmed, Participants: []models.User{users[2]}},
+		{RoomID: rooms[0].ID, CreatorID: users[2].ID, StartTime: at(0, 14), EndTime: at(0, 15), Title: "Дизайн-ревью", Description: "Обсуждение макетов нового лендинга", EstimatedParticipants: 3, Status: models.BookingStatusConfirmed},
+		{RoomID: rooms[1].ID, CreatorID: users[0].ID, StartTime: at(1, 18), EndTime: at(1, 21), Title: "Go meetup", Description: "Открытый митап сообщества", EstimatedParticipants: 35, IsJoinable: true, Status: models.BookingStatusConfirmed, Participants: []models.User{users[1], users[3]}},
+		{RoomID: rooms[2].ID, CreatorID: users[3].ID, StartTime: at(1, 9), EndTime: at(1, 12), Title: "Focus time", EstimatedParticipants: 1, Status: models.BookingStatusConfirmed},
+		{RoomID: rooms[0].ID, CreatorID: users[1].ID, StartTime: at(-1, 16), EndTime: at(-1, 17), Title: "Ретроспектива", EstimatedParticipants: 5, Status: models.BookingStatusCompleted},
+		{RoomID: rooms[1].ID, CreatorID: users[2].ID, StartTime: at(2, 12), EndTime: at(2, 13), Title: "Отменённая встреча", EstimatedParticipants: 10, Status: models.BookingStatusCancelled},
+	}
+}
@@ -0,0 +1,19 @@
+//go:build sqlite
+
+package database
+
+import (
+	"strings"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// sqliteDialector открывает SQLite (чистый Go, без cgo) с включёнными внешними ключами
+func sqliteDialector(path string) (gorm.Dialector, error) {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	return sqlite.Open(path + separator + "_pragma=foreign_keys(1)"), nil
+}
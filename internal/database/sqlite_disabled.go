@@ -0,0 +1,16 @@
+//go:build !sqlite
+
+package database
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrSQLiteNotSupported возвращается, если бинарник собран без тега sqlite
+var ErrSQLiteNotSupported = errors.New("SQLite support is not compiled in, rebuild with -tags sqlite")
+
+func sqliteDialector(string) (gorm.Dialector, error) {
+	return nil, ErrSQLiteNotSupported
+}
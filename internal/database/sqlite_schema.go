@@ -0,0 +1,42 @@
+package database
+
+import (
+	"github.com/space/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// sqliteScheme - префикс DATABASE_URL для SQLite: sqlite://./dev.db, sqlite://:memory:
+const sqliteScheme = "sqlite://"
+
+// MigrateSQLite создаёт схему SQLite по моделям
+// SQL-миграции написаны под PostgreSQL, поэтому для SQLite (локальная разработка и тесты)
+// схема строится AutoMigrate; в PostgreSQL схемой управляют только миграции
+func MigrateSQLite(db *gorm.DB) error {
+	return db.AutoMigrate(
+		&models.User{},
+		&models.Room{},
+		&models.Equipment{},
+		&models.Instruction{},
+		&models.Booking{},
+		&models.NotificationSubscription{},
+		&models.APIKey{},
+		&models.AuditLog{},
+		&models.SlackTarget{},
+		&models.RESTHook{},
+		&models.DoorAccessGrant{},
+		&models.Team{},
+		&models.RetentionRule{},
+		&models.AbuseFlag{},
+		&models.Holiday{},
+		&models.BookingFeedback{},
+		&models.BookingCheckIn{},
+		&models.CleaningTask{},
+		&models.BookingHistoryEvent{},
+		&models.Floor{},
+		&models.RoomOwnership{},
+		&models.RoomDelegation{},
+		&models.RentalRequest{},
+		&models.BookingWaitlistEntry{},
+		&models.BookingQuarantine{},
+	)
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/response"
+)
+
+// AbuseHandler handles the admin review queue of booking abuse and user booking limits
+type AbuseHandler struct {
+	abuseService *service.AbuseService
+}
+
+// NewAbuseHandler creates a new abuse handler
+func NewAbuseHandler(abuseService *service.AbuseService) *AbuseHandler {
+	return &AbuseHandler{abuseService: abuseService}
+}
+
+// ReviewAbuseFlagRequest represents an admin decision on a flag
+type ReviewAbuseFlagRequest struct {
+	Status models.AbuseFlagStatus `json:"status" binding:"required"`
+}
+
+// SetBookingLimitRequest sets the maximum number of active upcoming bookings of a user
+type SetBookingLimitRequest struct {
+	BookingLimit *int `json:"booking_limit"` // null - без ограничения
+}
+
+// ListFlags godoc
+// @Summary List booking abuse flags (admin only)
+// @Description Users flagged by the abuse detector: too many cancellations or back-to-back long bookings (ABUSE_* thresholds)
+// @Tags admin
+// @Produce json
+// @Param status query string false "Flag status" Enums(open, dismissed, confirmed)
+// @Param page query int false "Page number, starting from 1"
+// @Param per_page query int false "Page size (default 100, max 500)"
+// @Param cursor query string false "Cursor from meta.next_cursor"
+// @Success 200 {object} response.PaginatedResponse{data=[]models.AbuseFlag}
+// @Security TelegramInitData
+// @Router /api/admin/abuse-flags [get]
+func (h *AbuseHandler) ListFlags(c *gin.Context) {
+	page, err := response.ParsePage(c, service.DefaultListPageSize, service.MaxListPageSize)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	flags, total, err := h.abuseService.ListFlags(c.Request.Context(), models.AbuseFlagStatus(c.Query("status")), page.Limit, page.Offset)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Paginated(c, flags, page.Meta(total))
+}
+
+// ReviewFlag godoc
+// @Summary Review a booking abuse flag (admin only)
+// @Description Closes an open flag. The user's booking limit is not changed - use PUT /api/admin/users/{id}/booking-limit
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Flag ID"
+// @Param review body ReviewAbuseFlagRequest true "Decision: dismissed or confirmed"
+// @Success 200 {object} models.AbuseFlag
+// @Security TelegramInitData
+// @Router /api/admin/abuse-flags/{id} [patch]
+func (h *AbuseHandler) ReviewFlag(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	var req ReviewAbuseFlagRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	flag, err := h.abuseService.ReviewFlag(c.Request.Context(), uint(id), c.GetUint("userID"), req.Status)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Success(c, flag)
+}
+
+// SetBookingLimit godoc
+// @Summary Set a user's booking limit (admin only)
+// @Description Maximum number of active bookings that have not ended yet; null removes the limit.
+// @Description The abuse detector lowers it automatically when ABUSE_AUTO_BOOKING_LIMIT is set
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param limit body SetBookingLimitRequest true "Booking limit"
+// @Success 200 {object} models.User
+// @Security TelegramInitData
+// @Router /api/admin/users/{id}/booking-limit [put]
+func (h *AbuseHandler) SetBookingLimit(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	var req SetBookingLimitRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	user, err := h.abuseService.SetBookingLimit(c.Request.Context(), uint(id), req.BookingLimit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Success(c, user)
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/response"
+)
+
+// AdminHandler handles admin-only HTTP requests
+type AdminHandler struct {
+	userService *service.UserService
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(userService *service.UserService) *AdminHandler {
+	return &AdminHandler{userService: userService}
+}
+
+// ListUsers godoc
+// @Summary List all users (admin only)
+// @Tags admin
+// @Produce json
+// @Param page query int false "Page number, starting from 1"
+// @Param per_page query int false "Page size (default 100, max 500)"
+// @Param cursor query string false "Cursor from meta.next_cursor"
+// @Param sort query string false "Sort fields, '-' for descending, e.g. -created_at"
+// @Success 200 {object} response.PaginatedResponse{data=[]models.User}
+// @Security TelegramInitData
+// @Router /api/admin/users [get]
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	page, err := response.ParsePage(c, service.DefaultUserPageSize, service.MaxUserPageSize)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+	order, err := response.ParseSort(c, service.UserSortColumns)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	users, total, err := h.userService.ListUsers(c.Request.Context(), page.Limit, page.Offset, order)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Paginated(c, users, page.Meta(total))
+}
+
+// SetUserRoleRequest represents a request to change a user's role
+type SetUserRoleRequest struct {
+	Role models.UserRole `json:"role" binding:"required"`
+}
+
+// SetUserRole godoc
+// @Summary Change user role (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param role body SetUserRoleRequest true "Role"
+// @Success 200 {object} models.User
+// @Security TelegramInitData
+// @Router /api/admin/users/{id}/role [patch]
+func (h *AdminHandler) SetUserRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	var req SetUserRoleRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	// Администратор не может снять права с самого себя
+	if currentUserID, exists := c.Get("userID"); exists && currentUserID.(uint) == uint(id) && req.Role != models.RoleAdmin {
+		response.BadRequest(c, errors.New("cannot revoke your own admin role"))
+		return
+	}
+
+	user, err := h.userService.SetUserRole(c.Request.Context(), uint(id), req.Role)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Success(c, user)
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/response"
+)
+
+// APIKeyHandler handles admin management of third-party API keys
+type APIKeyHandler struct {
+	apiKeyService *service.APIKeyService
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(apiKeyService *service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyService: apiKeyService}
+}
+
+// ListKeys godoc
+// @Summary List API keys (admin only)
+// @Tags admin
+// @Produce json
+// @Param page query int false "Page number, starting from 1"
+// @Param per_page query int false "Page size (default 100, max 500)"
+// @Param cursor query string false "Cursor from meta.next_cursor"
+// @Param sort query string false "Sort fields, '-' for descending, e.g. -last_used_at"
+// @Success 200 {object} response.PaginatedResponse{data=[]models.APIKey}
+// @Security TelegramInitData
+// @Router /api/admin/api-keys [get]
+func (h *APIKeyHandler) ListKeys(c *gin.Context) {
+	page, err := response.ParsePage(c, service.DefaultListPageSize, service.MaxListPageSize)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+	order, err := response.ParseSort(c, service.APIKeySortColumns)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	keys, err := h.apiKeyService.ListKeys(c.Request.Context(), order)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Paginated(c, response.Slice(keys, page), page.Meta(int64(len(keys))))
+}
+
+// CreateKey godoc
+// @Summary Create an API key (admin only)
+// @Description The plaintext key is returned only once
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param key body service.CreateAPIKeyRequest true "API key data"
+// @Success 201 {object} service.CreatedAPIKey
+// @Security TelegramInitData
+// @Router /api/admin/api-keys [post]
+func (h *APIKeyHandler) CreateKey(c *gin.Context) {
+	var req service.CreateAPIKeyRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, service.ErrNotAuthorized)
+		return
+	}
+
+	created, err := h.apiKeyService.CreateKey(c.Request.Context(), userID.(uint), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Set("auditEntityID", created.APIKey.ID) // ID созданной сущности для журнала аудита
+	response.Created(c, created)
+}
+
+// RevokeKey godoc
+// @Summary Revoke an API key (admin only)
+// @Tags admin
+// @Param id path int true "API key ID"
+// @Success 204
+// @Security TelegramInitData
+// @Router /api/admin/api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeKey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if err := h.apiKeyService.RevokeKey(c.Request.Context(), uint(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.NoContent(c)
+}
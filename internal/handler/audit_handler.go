@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/repository"
+	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/response"
+)
+
+// AuditHandler handles admin access to the audit log
+type AuditHandler struct {
+	auditService *service.AuditService
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(auditService *service.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// ListAuditLog godoc
+// @Summary List audit log entries (admin only)
+// @Description Answers questions like "who cancelled this booking?": filter by route=/api/bookings/:id&method=DELETE&entity_id=42
+// @Tags admin
+// @Produce json
+// @Param actor_id query int false "User who performed the action"
+// @Param method query string false "HTTP method (POST, PATCH, PUT, DELETE)"
+// @Param route query string false "Route template, e.g. /api/bookings/:id"
+// @Param entity_id query string false "Entity ID"
+// @Param status query int false "Response status"
+// @Param from query string false "Start of period (RFC3339)"
+// @Param to query string false "End of period (RFC3339)"
+// @Param page query int false "Page number, starting from 1"
+// @Param per_page query int false "Page size (default 50, max 500)"
+// @Param cursor query string false "Cursor from meta.next_cursor"
+// @Param sort query string false "Sort fields, '-' for descending, e.g. status,-created_at"
+// @Success 200 {object} response.PaginatedResponse{data=[]models.AuditLog}
+// @Security TelegramInitData
+// @Router /api/admin/audit [get]
+func (h *AuditHandler) ListAuditLog(c *gin.Context) {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	result, err := h.auditService.List(c.Request.Context(), filter)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	page := response.Page{Limit: result.Limit, Offset: result.Offset}
+	response.Paginated(c, result.Items, page.Meta(result.Total))
+}
+
+// parseAuditFilter собирает фильтр журнала аудита из query-параметров
+func parseAuditFilter(c *gin.Context) (repository.AuditFilter, error) {
+	filter := repository.AuditFilter{
+		Method:   strings.ToUpper(c.Query("method")),
+		Route:    c.Query("route"),
+		EntityID: c.Query("entity_id"),
+	}
+
+	if v := c.Query("actor_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return filter, errors.New("invalid actor_id")
+		}
+		actorID := uint(id)
+		filter.ActorID = &actorID
+	}
+
+	if v := c.Query("status"); v != "" {
+		status, err := strconv.Atoi(v)
+		if err != nil {
+			return filter, errors.New("invalid status")
+		}
+		filter.Status = status
+	}
+
+	var err error
+	if filter.From, err = parseTimeQuery(c, "from"); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseTimeQuery(c, "to"); err != nil {
+		return filter, err
+	}
+
+	page, err := response.ParsePage(c, service.DefaultAuditPageSize, service.MaxAuditPageSize)
+	if err != nil {
+		return filter, err
+	}
+	filter.Limit, filter.Offset = page.Limit, page.Offset
+
+	if filter.Order, err = response.ParseSort(c, service.AuditSortColumns); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+// parseTimeQuery парсит необязательный query-параметр в формате RFC3339
+func parseTimeQuery(c *gin.Context, name string) (*time.Time, error) {
+	v := c.Query(name)
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, errors.New("invalid " + name + ": must be RFC3339")
+	}
+	return &t, nil
+}
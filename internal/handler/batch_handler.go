@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/pkg/response"
+)
+
+// maxBatchRequests ограничивает число подзапросов в одном batch
+const maxBatchRequests = 20
+
+// batchPath - путь самого batch-эндпоинта; вложенные batch не допускаются
+const batchPath = "/api/batch"
+
+var (
+	ErrEmptyBatch    = errors.New("batch must contain at least one request")
+	ErrBatchTooLarge = fmt.Errorf("batch must contain at most %d requests", maxBatchRequests)
+	ErrNestedBatch   = errors.New("nested batch requests are not allowed")
+)
+
+// batchSubRequestKey помечает контекст подзапросов batch
+type batchSubRequestKey struct{}
+
+// batchForwardedHeaders - заголовки исходного запроса, которые получает каждый подзапрос
+// (авторизация, язык, формат ошибок)
+var batchForwardedHeaders = []string{
+	"X-Telegram-Init-Data",
+	"X-Bot-Token",
+	"X-Telegram-User-ID",
+	"X-Telegram-Username",
+	"X-Telegram-First-Name",
+	"X-Telegram-Last-Name",
+	"X-Telegram-Language-Code",
+	"X-API-Key",
+	"Authorization",
+	"Accept",
+	"Accept-Language",
+	"Origin",
+	"Referer",
+	"User-Agent",
+}
+
+// BatchHandler executes several API requests in a single round trip
+type BatchHandler struct {
+	engine http.Handler
+}
+
+// NewBatchHandler creates a batch handler that dispatches sub-requests to engine
+func NewBatchHandler(engine http.Handler) *BatchHandler {
+	return &BatchHandler{engine: engine}
+}
+
+// BatchRequest represents a batch of API requests
+type BatchRequest struct {
+	Requests []BatchItem `json:"requests"`
+}
+
+// BatchItem represents a single sub-request
+type BatchItem struct {
+	Method string          `json:"method" binding:"required"`
+	Path   string          `json:"path" binding:"required"` // Путь с query, например /api/bookings/my?per_page=10
+	Body   json.RawMessage `json:"body,omitempty" swaggertype:"object"`
+}
+
+// BatchResult represents the response to a single sub-request
+type BatchResult struct {
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body,omitempty" swaggertype:"object"`
+}
+
+// Execute godoc
+// @Summary Execute several API requests at once
+// @Description Sub-requests run in order with the caller's credentials; each gets its own status and body.
+// @Description A failed sub-request does not stop the following ones.
+// @Tags batch
+// @Accept json
+// @Produce json
+// @Param request body BatchRequest true "Sub-requests (at most 20)"
+// @Success 200 {array} BatchResult
+// @Security TelegramInitData
+// @Router /api/batch [post]
+func (h *BatchHandler) Execute(c *gin.Context) {
+	// Подзапрос мог дойти до batch по пути, который не распознала validateBatchItem
+	if c.Request.Context().Value(batchSubRequestKey{}) != nil {
+		response.BadRequest(c, ErrNestedBatch)
+		return
+	}
+
+	var req BatchRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if len(req.Requests) == 0 {
+		response.BadRequest(c, ErrEmptyBatch)
+		return
+	}
+	if len(req.Requests) > maxBatchRequests {
+		response.BadRequest(c, ErrBatchTooLarge)
+		return
+	}
+	for i, item := range req.Requests {
+		if err := validateBatchItem(item); err != nil {
+			response.BadRequest(c, fmt.Errorf("requests[%d]: %w", i, err))
+			return
+		}
+	}
+
+	results := make([]BatchResult, len(req.Requests))
+	for i, item := range req.Requests {
+		results[i] = h.dispatch(c, i, item)
+	}
+
+	response.Success(c, results)
+}
+
+// validateBatchItem допускает только методы API и пути под /api, кроме самого batch
+func validateBatchItem(item BatchItem) error {
+	switch strings.ToUpper(item.Method) {
+	case http.MethodGet, http.MethodPost, http.MethodPatch, http.MethodPut, http.MethodDelete:
+	default:
+		return fmt.Errorf("unsupported method %q", item.Method)
+	}
+
+	// Сравнивается путь в том виде, в каком его маршрутизирует gin: декодированный и нормализованный
+	target, err := url.Parse(item.Path)
+	if err != nil {
+		return fmt.Errorf("invalid path %q", item.Path)
+	}
+	if target.Scheme != "" || target.Host != "" {
+		return fmt.Errorf("path must not contain a scheme or host, got %q", item.Path)
+	}
+	clean := path.Clean(target.Path)
+	if !strings.HasPrefix(clean, "/api/") {
+		return fmt.Errorf("path must start with /api/, got %q", item.Path)
+	}
+	if clean == batchPath || strings.HasPrefix(clean, batchPath+"/") {
+		return ErrNestedBatch
+	}
+	return nil
+}
+
+// dispatch выполняет подзапрос через роутер со всеми middleware (авторизация, лимиты, аудит)
+// Контекст общий с batch-запросом: весь batch укладывается в REQUEST_TIMEOUT
+func (h *BatchHandler) dispatch(c *gin.Context, index int, item BatchItem) BatchResult {
+	ctx := context.WithValue(c.Request.Context(), batchSubRequestKey{}, true)
+	sub, err := http.NewRequestWithContext(ctx, strings.ToUpper(item.Method), item.Path, bytes.NewReader(item.Body))
+	if err != nil {
+		return batchError(http.StatusBadRequest, err)
+	}
+
+	for _, name := range batchForwardedHeaders {
+		if value := c.GetHeader(name); value != "" {
+			sub.Header.Set(name, value)
+		}
+	}
+	if len(item.Body) > 0 {
+		sub.Header.Set("Content-Type", "application/json")
+	}
+	if requestID := c.GetString("requestID"); requestID != "" {
+		sub.Header.Set("X-Request-ID", requestID+"."+strconv.Itoa(index))
+	}
+	sub.RemoteAddr = c.Request.RemoteAddr
+	sub.Host = c.Request.Host
+
+	recorder := newBatchRecorder()
+	h.engine.ServeHTTP(recorder, sub)
+
+	result := BatchResult{Status: recorder.status}
+	if body := recorder.body.Bytes(); len(body) > 0 {
+		if json.Valid(body) {
+			result.Body = body
+		} else {
+			// Не-JSON ответ (например, CSV) передаётся строкой
+			result.Body, _ = json.Marshal(string(body))
+		}
+	}
+	return result
+}
+
+// batchError формирует результат подзапроса, который не удалось отправить
+func batchError(status int, err error) BatchResult {
+	body, _ := json.Marshal(response.ErrorResponse{Error: err.Error()})
+	return BatchResult{Status: status, Body: body}
+}
+
+// batchRecorder накапливает ответ подзапроса в памяти
+type batchRecorder struct {
+	header http.Header
+	body   bytes.Buffer
+	status int
+}
+
+func newBatchRecorder() *batchRecorder {
+	return &batchRecorder{header: http.Header{}, status: http.StatusOK}
+}
+
+func (r *batchRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *batchRecorder) Write(data []byte) (int, error) {
+	return r.body.Write(data)
+}
+
+func (r *batchRecorder) WriteHeader(status int) {
+	r.status = status
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newBatchEngine() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/rooms", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"auth": c.GetHeader("X-Telegram-Init-Data")})
+	})
+	r.POST("/api/bookings", func(c *gin.Context) {
+		var body map[string]string
+		_ = c.ShouldBindJSON(&body)
+		c.JSON(http.StatusCreated, body)
+	})
+	r.POST("/api/batch", NewBatchHandler(r).Execute)
+	return r
+}
+
+func postBatch(r *gin.Engine, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/batch", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Telegram-Init-Data", "init")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestBatch_ExecutesInOrder(t *testing.T) {
+	w := postBatch(newBatchEngine(), `{"requests": [
+		{"method": "GET", "path": "/api/rooms"},
+		{"method": "POST", "path": "/api/bookings", "body": {"title": "Standup"}},
+		{"method": "GET", "path": "/api/missing"}
+	]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got: %d %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Data []BatchResult `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || len(resp.Data) != 3 {
+		t.Fatalf("Expected 3 results, got: %s", w.Body.String())
+	}
+	if resp.Data[0].Status != http.StatusOK || !strings.Contains(string(resp.Data[0].Body), `"auth":"init"`) {
+		t.Errorf("Expected credentials to be forwarded, got: %d %s", resp.Data[0].Status, resp.Data[0].Body)
+	}
+	if resp.Data[1].Status != http.StatusCreated || !strings.Contains(string(resp.Data[1].Body), "Standup") {
+		t.Errorf("Expected body to be forwarded, got: %d %s", resp.Data[1].Status, resp.Data[1].Body)
+	}
+	if resp.Data[2].Status != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown path, got: %d", resp.Data[2].Status)
+	}
+}
+
+func TestBatch_RejectsInvalidItems(t *testing.T) {
+	r := newBatchEngine()
+	for _, body := range []string{
+		`{"requests": []}`,
+		`{"requests": [{"method": "GET", "path": "/health"}]}`,
+		`{"requests": [{"method": "POST", "path": "/api/batch"}]}`,
+		`{"requests": [{"method": "POST", "path": "/api/%62atch"}]}`,
+		`{"requests": [{"method": "POST", "path": "/api/rooms/../batch"}]}`,
+		`{"requests": [{"method": "GET", "path": "http://example.com/api/rooms"}]}`,
+		`{"requests": [{"method": "TRACE", "path": "/api/rooms"}]}`,
+	} {
+		if w := postBatch(r, body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got: %d", body, w.Code)
+		}
+	}
+}
+
+func TestBatch_RejectsNestedBatchByContext(t *testing.T) {
+	r := newBatchEngine()
+	// Тот же обработчик под путём, который validateBatchItem не считает batch
+	r.POST("/api/v2/batch", NewBatchHandler(r).Execute)
+
+	w := postBatch(r, `{"requests": [{"method": "POST", "path": "/api/v2/batch", "body": {"requests": [{"method": "GET", "path": "/api/rooms"}]}}]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got: %d %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data []BatchResult `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || len(resp.Data) != 1 {
+		t.Fatalf("Expected 1 result, got: %s", w.Body.String())
+	}
+	if resp.Data[0].Status != http.StatusBadRequest {
+		t.Errorf("Expected the nested batch to be rejected, got: %d %s", resp.Data[0].Status, resp.Data[0].Body)
+	}
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/response"
+)
+
+// BillingHandler handles team usage reports and included hours for paid coworking spaces
+type BillingHandler struct {
+	billingService *service.BillingService
+}
+
+// NewBillingHandler creates a new billing handler
+func NewBillingHandler(billingService *service.BillingService) *BillingHandler {
+	return &BillingHandler{billingService: billingService}
+}
+
+// SetIncludedHoursRequest sets the monthly included hours of a team
+type SetIncludedHoursRequest struct {
+	IncludedHours *int `json:"included_hours"` // null - команда не тарифицируется
+}
+
+// GetUsage godoc
+// @Summary Team usage for a month (admin only)
+// @Description Booked hours per team for invoicing. Only bookings that have already ended are counted, clipped to the month (UTC).
+// @Description A booking is billed to its creator's team; creators in several teams are billed to the team with the lowest ID,
+// @Description bookings of users without a team are not billed. overage_hours is set only for teams with included_hours
+// @Tags admin
+// @Produce json,text/csv
+// @Param month query string false "Month in YYYY-MM format (default: current month)"
+// @Param team_id query int false "Only this team"
+// @Param format query string false "json (default) or csv"
+// @Success 200 {array} service.TeamUsage
+// @Security TelegramInitData
+// @Router /api/admin/billing/usage [get]
+func (h *BillingHandler) GetUsage(c *gin.Context) {
+	month, err := service.ParseBillingMonth(c.Query("month"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+	var teamID uint64
+	if value := c.Query("team_id"); value != "" {
+		if teamID, err = strconv.ParseUint(value, 10, 32); err != nil {
+			response.BadRequest(c, err)
+			return
+		}
+	}
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		response.BadRequest(c, errExportFormat)
+		return
+	}
+
+	usage, err := h.billingService.Usage(c.Request.Context(), month, uint(teamID))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if format == "json" {
+		response.Success(c, usage)
+		return
+	}
+	rows := make([][]string, 0, len(usage))
+	for _, u := range usage {
+		included, overage := "", ""
+		if u.IncludedHours != nil {
+			included = strconv.Itoa(*u.IncludedHours)
+		}
+		if u.OverageHours != nil {
+			overage = strconv.FormatFloat(*u.OverageHours, 'f', 2, 64)
+		}
+		rows = append(rows, []string{
+			strconv.FormatUint(uint64(u.TeamID), 10),
+			u.TeamName,
+			u.Month,
+			strconv.Itoa(u.Bookings),
+			strconv.FormatFloat(u.UsedHours, 'f', 2, 64),
+			included,
+			overage,
+		})
+	}
+	response.CSV(c, "billing_usage_"+month.Format(service.BillingMonthLayout)+".csv",
+		[]string{"team_id", "team", "month", "bookings", "used_hours", "included_hours", "overage_hours"}, rows)
+}
+
+// GetCosts godoc
+// @Summary Estimated booking costs for a month (admin only)
+// @Description Sums estimated costs of paid bookings (rooms with hourly_price) per user or team, most expensive first.
+// @Description A booking is counted in the month it starts (UTC) once it has ended, at the cost estimated when it was booked.
+// @Description With group=team bookings are attributed like in usage reports. cost is in minor units of currency
+// @Tags admin
+// @Produce json
+// @Param month query string false "Month in YYYY-MM format (default: current month)"
+// @Param group query string false "user (default) or team"
+// @Success 200 {array} service.CostSummary
+// @Security TelegramInitData
+// @Router /api/admin/billing/costs [get]
+func (h *BillingHandler) GetCosts(c *gin.Context) {
+	month, err := service.ParseBillingMonth(c.Query("month"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	costs, err := h.billingService.Costs(c.Request.Context(), month, c.DefaultQuery("group", service.CostByUser))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.Success(c, costs)
+}
+
+// SetIncludedHours godoc
+// @Summary Set monthly included hours of a team (admin only)
+// @Description Hours of bookings included in the team's plan; usage above them is reported as overage. null stops billing the team
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Team ID"
+// @Param hours body SetIncludedHoursRequest true "Included hours"
+// @Success 200 {object} models.Team
+// @Security TelegramInitData
+// @Router /api/admin/billing/teams/{id} [put]
+func (h *BillingHandler) SetIncludedHours(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	var req SetIncludedHoursRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	team, err := h.billingService.SetIncludedHours(c.Request.Context(), uint(id), req.IncludedHours)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Success(c, team)
+}
@@ -0,0 +1,17 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/pkg/response"
+	textvalidator "github.com/space/backend/pkg/validator"
+)
+
+// bindJSON разбирает тело запроса; ошибки тегов binding и типов значений отдаются списком полей
+// {field, rule, message} на языке запроса, а не текстом ошибки разбора Go
+func bindJSON(c *gin.Context, obj interface{}) bool {
+	if err := c.ShouldBindJSON(obj); err != nil {
+		response.BadRequest(c, textvalidator.FromBinding(err))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/pkg/i18n"
+	"github.com/space/backend/pkg/response"
+)
+
+func postBind(body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/bookings", func(c *gin.Context) {
+		c.Set(i18n.ContextKey, "ru")
+		var req struct {
+			RoomID uint   `json:"room_id"`
+			Title  string `json:"title"`
+		}
+		if !bindJSON(c, &req) {
+			return
+		}
+		response.Success(c, req)
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/bookings", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestBindJSON_FieldErrors(t *testing.T) {
+	w := postBind(`{"room_id": "seven", "title": "Standup"}`)
+
+	var body struct {
+		Fields []response.FieldMessage `json:"fields"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected JSON body, got: %v", err)
+	}
+	if w.Code != http.StatusBadRequest || len(body.Fields) != 1 {
+		t.Fatalf("Expected 400 with one field error, got: %d %s", w.Code, w.Body.String())
+	}
+	if fe := body.Fields[0]; fe.Field != "room_id" || fe.Rule != "type" || fe.Message != "поле 'room_id' должно иметь тип number" {
+		t.Errorf("Expected a localized type error for room_id, got: %+v", fe)
+	}
+}
+
+func TestBindJSON_InvalidBody(t *testing.T) {
+	w := postBind(`{"room_id": 1`)
+
+	var body response.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected JSON body, got: %v", err)
+	}
+	if w.Code != http.StatusBadRequest || body.Error != "тело запроса не является корректным JSON" {
+		t.Errorf("Expected a localized invalid body error, got: %d %s", w.Code, w.Body.String())
+	}
+}
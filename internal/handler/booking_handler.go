@@ -2,8 +2,10 @@ package handler
 
 import (
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/models"
 	"github.com/space/backend/internal/service"
 	"github.com/space/backend/pkg/response"
 	"github.com/space/backend/pkg/utils"
@@ -21,16 +23,18 @@ func NewBookingHandler(bookingService *service.BookingService) *BookingHandler {
 
 // CreateBooking godoc
 // @Summary Create a new booking
+// @Description A booking crossing midnight in the room timezone or longer than MULTI_DAY_BOOKING_MAX_DURATION follows MULTI_DAY_BOOKING_POLICY:
+// @Description allow - created with a warning in message, reject - 400 multi_day_booking, admin - only administrators with allow_multi_day
 // @Tags bookings
 // @Accept json
 // @Produce json
 // @Param booking body service.CreateBookingRequest true "Booking data"
 // @Success 201 {object} models.Booking
+// @Security TelegramInitData
 // @Router /api/bookings [post]
 func (h *BookingHandler) CreateBooking(c *gin.Context) {
 	var req service.CreateBookingRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, err)
+	if !bindJSON(c, &req) {
 		return
 	}
 
@@ -41,27 +45,18 @@ func (h *BookingHandler) CreateBooking(c *gin.Context) {
 		return
 	}
 
-	booking, err := h.bookingService.CreateBooking(userID.(uint), req)
+	booking, err := h.bookingService.CreateBooking(c.Request.Context(), userID.(uint), req)
 	if err != nil {
-		// Проверяем, является ли это ошибкой конфликта с деталями
-		if conflictErr, ok := err.(*service.BookingConflictError); ok {
-			response.ConflictWithData(c, conflictErr.Message, conflictErr.ConflictingBookings)
-			return
-		}
-
-		switch err {
-		case service.ErrBookingConflict:
-			response.Conflict(c, err)
-		case service.ErrInvalidTime, service.ErrPastBooking:
-			response.BadRequest(c, err)
-		case service.ErrRoomNotFound:
-			response.NotFound(c, err)
-		default:
-			response.InternalServerError(c, err)
-		}
+		_ = c.Error(err)
 		return
 	}
 
+	c.Set("auditEntityID", booking.ID) // ID созданной сущности для журнала аудита
+	// Многодневное бронирование, разрешённое политикой, создаётся с предупреждением
+	if booking.MultiDay {
+		response.CreatedWithMessage(c, booking, service.MultiDayWarning)
+		return
+	}
 	response.Created(c, booking)
 }
 
@@ -70,7 +65,10 @@ func (h *BookingHandler) CreateBooking(c *gin.Context) {
 // @Tags bookings
 // @Produce json
 // @Param id path int true "Booking ID"
+// @Param fields query string false "Comma-separated booking fields, e.g. id,title,start_time,end_time"
+// @Param include query string false "Relations to expand: room, creator, participants"
 // @Success 200 {object} models.Booking
+// @Security TelegramInitData
 // @Router /api/bookings/{id} [get]
 func (h *BookingHandler) GetBooking(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -79,20 +77,38 @@ func (h *BookingHandler) GetBooking(c *gin.Context) {
 		return
 	}
 
-	booking, err := h.bookingService.GetBooking(uint(id))
+	selector, err := parseFieldSelector(c, models.Booking{}, bookingRelations)
 	if err != nil {
-		response.NotFound(c, err)
+		response.BadRequest(c, err)
 		return
 	}
 
-	response.Success(c, booking)
+	booking, err := h.bookingService.GetBooking(c.Request.Context(), uint(id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	data, err := selector.project(booking)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.Success(c, data)
 }
 
 // GetUserBookings godoc
 // @Summary Get current user's bookings
 // @Tags bookings
 // @Produce json
-// @Success 200 {array} models.Booking
+// @Param page query int false "Page number, starting from 1"
+// @Param per_page query int false "Page size (default 100, max 500)"
+// @Param cursor query string false "Cursor from meta.next_cursor"
+// @Param sort query string false "Sort fields, '-' for descending, e.g. -start_time,title"
+// @Param fields query string false "Comma-separated booking fields, e.g. id,title,start_time,end_time"
+// @Param include query string false "Relations to expand: room, creator, participants"
+// @Success 200 {object} response.PaginatedResponse{data=[]models.Booking}
+// @Security TelegramInitData
 // @Router /api/bookings/my [get]
 func (h *BookingHandler) GetUserBookings(c *gin.Context) {
 	userID, exists := c.Get("userID")
@@ -101,22 +117,49 @@ func (h *BookingHandler) GetUserBookings(c *gin.Context) {
 		return
 	}
 
-	bookings, err := h.bookingService.GetUserBookings(userID.(uint))
+	page, err := response.ParsePage(c, service.DefaultBookingPageSize, service.MaxBookingPageSize)
 	if err != nil {
-		response.InternalServerError(c, err)
+		response.BadRequest(c, err)
+		return
+	}
+	order, err := response.ParseSort(c, service.BookingSortColumns)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+	selector, err := parseFieldSelector(c, models.Booking{}, bookingRelations)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	bookings, total, err := h.bookingService.GetUserBookings(c.Request.Context(), userID.(uint), page.Limit, page.Offset, order)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
 
-	response.Success(c, bookings)
+	data, err := selector.project(bookings)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.Paginated(c, data, page.Meta(total))
 }
 
 // GetCalendarEvents godoc
 // @Summary Get calendar events
+// @Description Closure days of the space in the range are added as all-day background events (extendedProps.type = "closure"),
+// @Description rooms dedicated to teams - as background events of the room titled with the team name (extendedProps.type = "dedicated").
+// @Description Bookings crossing midnight are split into one event per day sharing groupId; extendedProps booking_start and booking_end hold the whole booking
 // @Tags bookings
 // @Produce json
 // @Param start query string true "Start date (RFC3339)"
 // @Param end query string true "End date (RFC3339)"
+// @Param summary query bool false "Compact events: creator name and participant_count instead of participant objects"
+// @Param timeZone query string false "IANA timezone of the calendar: multi-day bookings are split into one event per day (default - the space timezone)"
 // @Success 200 {array} map[string]interface{}
+// @Security TelegramInitData
 // @Router /api/bookings/calendar [get]
 func (h *BookingHandler) GetCalendarEvents(c *gin.Context) {
 	startStr := c.Query("start")
@@ -139,18 +182,65 @@ func (h *BookingHandler) GetCalendarEvents(c *gin.Context) {
 		return
 	}
 
-	bookings, err := h.bookingService.GetCalendarEvents(start, end)
+	// Многодневные бронирования делятся по дням часового пояса календаря (FullCalendar передаёт его в ?timeZone=)
+	loc := h.bookingService.CalendarLocation()
+	if tz := c.Query("timeZone"); tz != "" {
+		if loc, err = time.LoadLocation(tz); err != nil {
+			_ = c.Error(service.ErrInvalidTimezone)
+			return
+		}
+	}
+
+	// Компактный вариант для загруженных месяцев: без объектов участников и создателя
+	if c.Query("summary") == "true" {
+		summaries, err := h.bookingService.GetCalendarSummaries(c.Request.Context(), start, end)
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		events := make([]map[string]interface{}, 0, len(summaries))
+		for i := range summaries {
+			event := service.FormatSummaryForCalendar(&summaries[i])
+			events = append(events, service.SplitCalendarEvent(event, summaries[i].StartTime, summaries[i].EndTime, loc)...)
+		}
+		h.respondCalendar(c, start, end, events)
+		return
+	}
+
+	bookings, err := h.bookingService.GetCalendarEvents(c.Request.Context(), start, end)
 	if err != nil {
-		response.InternalServerError(c, err)
+		_ = c.Error(err)
 		return
 	}
 
 	// Форматируем для FullCalendar
-	events := make([]map[string]interface{}, len(bookings))
-	for i, booking := range bookings {
-		events[i] = service.FormatBookingForCalendar(&booking)
+	events := make([]map[string]interface{}, 0, len(bookings))
+	for i := range bookings {
+		event := service.FormatBookingForCalendar(&bookings[i])
+		events = append(events, service.SplitCalendarEvent(event, bookings[i].StartTime, bookings[i].EndTime, loc)...)
 	}
 
+	h.respondCalendar(c, start, end, events)
+}
+
+// respondCalendar дополняет события календаря нерабочими днями и закреплёнными комнатами и отправляет ответ
+func (h *BookingHandler) respondCalendar(c *gin.Context, start, end time.Time, events []map[string]interface{}) {
+	closed, err := h.bookingService.GetClosedDays(c.Request.Context(), start, end)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	for i := range closed {
+		events = append(events, service.FormatHolidayForCalendar(&closed[i]))
+	}
+	dedicated, err := h.bookingService.GetDedicatedRooms(c.Request.Context(), start)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	for i := range dedicated {
+		events = append(events, service.FormatOwnershipForCalendar(&dedicated[i], start, end))
+	}
 	response.Success(c, events)
 }
 
@@ -159,6 +249,7 @@ func (h *BookingHandler) GetCalendarEvents(c *gin.Context) {
 // @Tags bookings
 // @Param id path int true "Booking ID"
 // @Success 204
+// @Security TelegramInitData
 // @Router /api/bookings/{id} [delete]
 func (h *BookingHandler) CancelBooking(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -173,25 +264,48 @@ func (h *BookingHandler) CancelBooking(c *gin.Context) {
 		return
 	}
 
-	err = h.bookingService.CancelBooking(uint(id), userID.(uint))
+	err = h.bookingService.CancelBooking(c.Request.Context(), uint(id), userID.(uint))
 	if err != nil {
-		switch err {
-		case service.ErrNotAuthorized:
-			response.Forbidden(c, err)
-		default:
-			response.InternalServerError(c, err)
-		}
+		_ = c.Error(err)
 		return
 	}
 
 	response.NoContent(c)
 }
 
+// ReleaseRoom godoc
+// @Summary Release a room immediately (admin only)
+// @Description Ends the booking in progress now: status completed, end_time set to the current time.
+// @Description The creator is notified through the bot webhook (event booking.released) and room displays show the room as free
+// @Tags admin
+// @Produce json
+// @Param id path int true "Room ID"
+// @Success 200 {object} models.Booking
+// @Security TelegramInitData
+// @Router /api/admin/rooms/{id}/release [post]
+func (h *BookingHandler) ReleaseRoom(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	booking, err := h.bookingService.ReleaseRoom(c.Request.Context(), uint(id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Success(c, booking)
+}
+
 // JoinBooking godoc
 // @Summary Join a booking
+// @Description Fails with 409 when all max_participants seats are taken: join the waitlist instead
 // @Tags bookings
 // @Param id path int true "Booking ID"
 // @Success 200
+// @Security TelegramInitData
 // @Router /api/bookings/{id}/join [post]
 func (h *BookingHandler) JoinBooking(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -206,9 +320,9 @@ func (h *BookingHandler) JoinBooking(c *gin.Context) {
 		return
 	}
 
-	err = h.bookingService.JoinBooking(uint(id), userID.(uint))
+	err = h.bookingService.JoinBooking(c.Request.Context(), uint(id), userID.(uint))
 	if err != nil {
-		response.BadRequest(c, err)
+		_ = c.Error(err)
 		return
 	}
 
@@ -217,9 +331,11 @@ func (h *BookingHandler) JoinBooking(c *gin.Context) {
 
 // LeaveBooking godoc
 // @Summary Leave a booking
+// @Description The freed seat goes to the first user on the waitlist, who is notified through the bot
 // @Tags bookings
 // @Param id path int true "Booking ID"
 // @Success 200
+// @Security TelegramInitData
 // @Router /api/bookings/{id}/leave [post]
 func (h *BookingHandler) LeaveBooking(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -234,15 +350,85 @@ func (h *BookingHandler) LeaveBooking(c *gin.Context) {
 		return
 	}
 
-	err = h.bookingService.LeaveBooking(uint(id), userID.(uint))
+	err = h.bookingService.LeaveBooking(c.Request.Context(), uint(id), userID.(uint))
 	if err != nil {
-		response.BadRequest(c, err)
+		_ = c.Error(err)
 		return
 	}
 
 	response.SuccessWithMessage(c, nil, "Successfully left booking")
 }
 
+// JoinWaitlist godoc
+// @Summary Wait for a seat at a full booking
+// @Description Queues the user for a seat at a joinable booking whose max_participants seats are taken.
+// @Description When a participant leaves or the limit is raised, the first in the queue becomes a participant
+// @Description and is notified through the bot webhook (event booking.waitlist_promoted)
+// @Tags bookings
+// @Produce json
+// @Param id path int true "Booking ID"
+// @Success 201 {object} service.WaitlistPosition
+// @Security TelegramInitData
+// @Router /api/bookings/{id}/waitlist [post]
+func (h *BookingHandler) JoinWaitlist(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	position, err := h.bookingService.JoinWaitlist(c.Request.Context(), uint(id), c.GetUint("userID"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.Created(c, position)
+}
+
+// GetWaitlistPosition godoc
+// @Summary Get own place in the booking waitlist
+// @Tags bookings
+// @Produce json
+// @Param id path int true "Booking ID"
+// @Success 200 {object} service.WaitlistPosition
+// @Security TelegramInitData
+// @Router /api/bookings/{id}/waitlist [get]
+func (h *BookingHandler) GetWaitlistPosition(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	position, err := h.bookingService.GetWaitlistPosition(c.Request.Context(), uint(id), c.GetUint("userID"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.Success(c, position)
+}
+
+// LeaveWaitlist godoc
+// @Summary Leave the booking waitlist
+// @Tags bookings
+// @Param id path int true "Booking ID"
+// @Success 204
+// @Security TelegramInitData
+// @Router /api/bookings/{id}/waitlist [delete]
+func (h *BookingHandler) LeaveWaitlist(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if err := h.bookingService.LeaveWaitlist(c.Request.Context(), uint(id), c.GetUint("userID")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.NoContent(c)
+}
+
 // UpdateBooking godoc
 // @Summary Update a booking
 // @Tags bookings
@@ -251,6 +437,7 @@ func (h *BookingHandler) LeaveBooking(c *gin.Context) {
 // @Param id path int true "Booking ID"
 // @Param booking body service.UpdateBookingRequest true "Booking data"
 // @Success 200 {object} models.Booking
+// @Security TelegramInitData
 // @Router /api/bookings/{id} [patch]
 func (h *BookingHandler) UpdateBooking(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -260,8 +447,7 @@ func (h *BookingHandler) UpdateBooking(c *gin.Context) {
 	}
 
 	var req service.UpdateBookingRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, err)
+	if !bindJSON(c, &req) {
 		return
 	}
 
@@ -271,26 +457,56 @@ func (h *BookingHandler) UpdateBooking(c *gin.Context) {
 		return
 	}
 
-	booking, err := h.bookingService.UpdateBooking(uint(id), userID.(uint), req)
+	booking, err := h.bookingService.UpdateBooking(c.Request.Context(), uint(id), userID.(uint), req)
 	if err != nil {
-		// Проверяем, является ли это ошибкой конфликта с деталями
-		if conflictErr, ok := err.(*service.BookingConflictError); ok {
-			response.ConflictWithData(c, conflictErr.Message, conflictErr.ConflictingBookings)
-			return
-		}
-
-		switch err {
-		case service.ErrNotAuthorized:
-			response.Forbidden(c, err)
-		case service.ErrBookingConflict:
-			response.Conflict(c, err)
-		case service.ErrInvalidTime:
-			response.BadRequest(c, err)
-		default:
-			response.InternalServerError(c, err)
-		}
+		_ = c.Error(err)
 		return
 	}
 
 	response.Success(c, booking)
 }
+
+// CheckAvailability godoc
+// @Summary Check if a room is free for a period
+// @Description Runs the same overlap and closed-day checks as creating a booking, so the form can be validated before submitting.
+// @Description available is false if the period overlaps active bookings or a day the space is closed
+// @Tags rooms
+// @Produce json
+// @Param id path int true "Room ID"
+// @Param start query string true "Start time (RFC3339)"
+// @Param end query string true "End time (RFC3339)"
+// @Success 200 {object} service.AvailabilityCheck
+// @Security TelegramInitData
+// @Router /api/rooms/{id}/availability/check [get]
+func (h *BookingHandler) CheckAvailability(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	startStr := c.Query("start")
+	endStr := c.Query("end")
+	if startStr == "" || endStr == "" {
+		response.BadRequest(c, service.ErrInvalidTime)
+		return
+	}
+	start, err := utils.ParseFlexibleTime(startStr)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+	end, err := utils.ParseFlexibleTime(endStr)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	result, err := h.bookingService.CheckAvailability(c.Request.Context(), uint(id), start, end)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Success(c, result)
+}
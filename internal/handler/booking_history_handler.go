@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/response"
+)
+
+// BookingHistoryHandler exposes the booking event stream
+type BookingHistoryHandler struct {
+	historyService *service.BookingHistoryService
+}
+
+// NewBookingHistoryHandler creates a new booking history handler
+func NewBookingHistoryHandler(historyService *service.BookingHistoryService) *BookingHistoryHandler {
+	return &BookingHistoryHandler{historyService: historyService}
+}
+
+// GetHistory godoc
+// @Summary Booking history (admin only)
+// @Description Lifecycle events of a booking (created, updated, cancelled, joined, left, completed) in order,
+// @Description and the booking state rebuilt from them. Available for cancelled bookings as well
+// @Tags admin
+// @Produce json
+// @Param id path int true "Booking ID"
+// @Success 200 {object} service.BookingHistory
+// @Security TelegramInitData
+// @Router /api/admin/bookings/{id}/history [get]
+func (h *BookingHistoryHandler) GetHistory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	history, err := h.historyService.History(c.Request.Context(), uint(id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.Success(c, history)
+}
+
+// GetFeed godoc
+// @Summary Booking event feed
+// @Description Lifecycle events of all bookings in stream order, for integrations that must not miss changes.
+// @Description Pass next from the previous response as after; events appear in the feed 15 seconds after they happen
+// @Tags integration
+// @Produce json
+// @Param after query int false "Cursor: ID of the last processed event (default 0 - from the beginning)"
+// @Param limit query int false "Maximum number of events (default 100, max 500)"
+// @Success 200 {object} service.BookingEventFeed
+// @Security APIKey
+// @Router /api/integration/booking-events [get]
+func (h *BookingHistoryHandler) GetFeed(c *gin.Context) {
+	var after uint64
+	if value := c.Query("after"); value != "" {
+		var err error
+		if after, err = strconv.ParseUint(value, 10, 32); err != nil {
+			response.BadRequest(c, err)
+			return
+		}
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	feed, err := h.historyService.Feed(c.Request.Context(), uint(after), limit)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.Success(c, feed)
+}
@@ -1,11 +1,14 @@
 package handler
 
 import (
-	"log"
+	"context"
+	"errors"
+	"log/slog"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/logger"
 	"github.com/space/backend/internal/models"
 	"github.com/space/backend/internal/service"
 	"github.com/space/backend/pkg/response"
@@ -15,12 +18,14 @@ import (
 type BotHandler struct {
 	bookingService      *service.BookingService
 	notificationService *service.NotificationService
+	userService         *service.UserService
 }
 
-func NewBotHandler(bookingService *service.BookingService, notificationService *service.NotificationService) *BotHandler {
+func NewBotHandler(bookingService *service.BookingService, notificationService *service.NotificationService, userService *service.UserService) *BotHandler {
 	return &BotHandler{
 		bookingService:      bookingService,
 		notificationService: notificationService,
+		userService:         userService,
 	}
 }
 
@@ -44,12 +49,12 @@ func (h *BotHandler) CreateBooking(c *gin.Context) {
 		IsJoinable            bool      `json:"is_joinable"`
 	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, err)
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	booking, err := h.bookingService.CreateSimpleBooking(
+		c.Request.Context(),
 		req.RoomID,
 		user.ID,
 		req.StartTime,
@@ -61,27 +66,120 @@ func (h *BotHandler) CreateBooking(c *gin.Context) {
 	)
 
 	if err != nil {
-		log.Printf("ERROR: Bot failed to create booking: %v", err)
-		response.InternalServerError(c, err)
+		_ = c.Error(err)
 		return
 	}
 
-	log.Printf("INFO: Bot created booking ID %d for user %d (TelegramID: %d)", booking.ID, user.ID, user.TelegramID)
+	requestLogger(c).Info("bot created booking", "booking_id", booking.ID, "telegram_id", user.TelegramID)
 
 	// Получаем подписчиков для уведомлений
-	subscribers, err := h.notificationService.GetRoomSubscribers(req.RoomID)
+	subscribers, err := h.notificationService.GetRoomSubscribers(c.Request.Context(), req.RoomID)
 	if err != nil {
-		log.Printf("WARNING: Failed to get subscribers for room %d: %v", req.RoomID, err)
+		requestLogger(c).Warn("failed to get room subscribers", "room_id", req.RoomID, "error", err)
 	} else {
-		log.Printf("INFO: Found %d subscribers for room %d", len(subscribers), req.RoomID)
+		requestLogger(c).Debug("found room subscribers", "room_id", req.RoomID, "count", len(subscribers))
 	}
 
+	c.Set("auditEntityID", booking.ID) // ID созданной сущности для журнала аудита
 	response.Created(c, gin.H{
 		"booking":     booking,
 		"subscribers": subscribers, // Бот использует это для отправки уведомлений
 	})
 }
 
+// UpdateBooking changes a booking on behalf of a user
+// PATCH /api/bot/bookings/:id - изменить может создатель или администратор, как в Mini App
+func (h *BotHandler) UpdateBooking(c *gin.Context) {
+	user, ok := botUser(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	var req service.UpdateBookingRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	booking, err := h.bookingService.UpdateBooking(c.Request.Context(), uint(id), user.ID, req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	requestLogger(c).Info("bot updated booking", "booking_id", booking.ID, "telegram_id", user.TelegramID)
+	response.Success(c, booking)
+}
+
+// CancelBooking cancels a booking on behalf of a user
+// DELETE /api/bot/bookings/:id - отменить может создатель или администратор, как в Mini App
+func (h *BotHandler) CancelBooking(c *gin.Context) {
+	user, ok := botUser(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if err := h.bookingService.CancelBooking(c.Request.Context(), uint(id), user.ID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	requestLogger(c).Info("bot cancelled booking", "booking_id", id, "telegram_id", user.TelegramID)
+	response.NoContent(c)
+}
+
+// JoinBooking adds the user to the participants of a joinable booking
+// POST /api/bot/bookings/:id/join - для кнопки "Присоединиться" под анонсом в группе
+func (h *BotHandler) JoinBooking(c *gin.Context) {
+	h.changeParticipation(c, h.bookingService.JoinBooking, "joined")
+}
+
+// LeaveBooking removes the user from the participants of a booking
+// POST /api/bot/bookings/:id/leave
+func (h *BotHandler) LeaveBooking(c *gin.Context) {
+	h.changeParticipation(c, h.bookingService.LeaveBooking, "left")
+}
+
+// changeParticipation вызывает join или leave от имени пользователя и возвращает бронирование
+// с обновлённым списком участников - бот перерисовывает по нему сообщение
+func (h *BotHandler) changeParticipation(c *gin.Context, change func(ctx context.Context, bookingID, userID uint) error, action string) {
+	user, ok := botUser(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if err := change(c.Request.Context(), uint(id), user.ID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	booking, err := h.bookingService.GetBooking(c.Request.Context(), uint(id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	requestLogger(c).Info("bot "+action+" booking", "booking_id", booking.ID, "telegram_id", user.TelegramID)
+	response.Success(c, booking)
+}
+
 // Subscribe subscribes a user to room notifications
 // POST /api/bot/notifications/subscribe
 func (h *BotHandler) Subscribe(c *gin.Context) {
@@ -96,19 +194,17 @@ func (h *BotHandler) Subscribe(c *gin.Context) {
 		RoomID uint `json:"room_id" binding:"required"`
 	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, err)
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	err := h.notificationService.Subscribe(user.ID, req.RoomID)
+	err := h.notificationService.Subscribe(c.Request.Context(), user.ID, req.RoomID)
 	if err != nil {
-		log.Printf("ERROR: Bot failed to subscribe user %d to room %d: %v", user.ID, req.RoomID, err)
-		response.InternalServerError(c, err)
+		_ = c.Error(err)
 		return
 	}
 
-	log.Printf("INFO: User %d (TelegramID: %d) subscribed to room %d", user.ID, user.TelegramID, req.RoomID)
+	requestLogger(c).Info("user subscribed to room", "room_id", req.RoomID)
 	response.Success(c, gin.H{"message": "subscribed successfully"})
 }
 
@@ -126,19 +222,17 @@ func (h *BotHandler) Unsubscribe(c *gin.Context) {
 		RoomID uint `json:"room_id" binding:"required"`
 	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, err)
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	err := h.notificationService.Unsubscribe(user.ID, req.RoomID)
+	err := h.notificationService.Unsubscribe(c.Request.Context(), user.ID, req.RoomID)
 	if err != nil {
-		log.Printf("ERROR: Bot failed to unsubscribe user %d from room %d: %v", user.ID, req.RoomID, err)
-		response.InternalServerError(c, err)
+		_ = c.Error(err)
 		return
 	}
 
-	log.Printf("INFO: User %d (TelegramID: %d) unsubscribed from room %d", user.ID, user.TelegramID, req.RoomID)
+	requestLogger(c).Info("user unsubscribed from room", "room_id", req.RoomID)
 	response.Success(c, gin.H{"message": "unsubscribed successfully"})
 }
 
@@ -152,18 +246,18 @@ func (h *BotHandler) GetSubscriptions(c *gin.Context) {
 	}
 	user := userInterface.(*models.User)
 
-	subscriptions, err := h.notificationService.GetUserSubscriptions(user.ID)
+	subscriptions, err := h.notificationService.GetUserSubscriptions(c.Request.Context(), user.ID)
 	if err != nil {
-		log.Printf("ERROR: Bot failed to get subscriptions for user %d: %v", user.ID, err)
-		response.InternalServerError(c, err)
+		_ = c.Error(err)
 		return
 	}
 
 	response.Success(c, subscriptions)
 }
 
-// GetUserBookings returns bookings for a specific user
-// GET /api/bot/bookings/user/:telegram_id
+// GetUserBookings returns a page of bookings for a specific user
+// GET /api/bot/bookings/user/:telegram_id?page=&per_page= (или cursor=)&sort=&fields=&include=
+// Доступно самому пользователю и администраторам (по X-Telegram-User-ID)
 func (h *BotHandler) GetUserBookings(c *gin.Context) {
 	telegramIDStr := c.Param("telegram_id")
 	telegramID, err := strconv.ParseInt(telegramIDStr, 10, 64)
@@ -172,21 +266,98 @@ func (h *BotHandler) GetUserBookings(c *gin.Context) {
 		return
 	}
 
-	// Здесь можно было бы добавить проверку прав доступа
-	// (например, только свои бронирования или админ)
+	// Свои бронирования или, для администратора, любого пользователя;
+	// действующий пользователь - из X-Telegram-User-ID, проверенного BotAuthMiddleware
+	user, ok := botUser(c)
+	if !ok {
+		return
+	}
+
+	page, err := response.ParsePage(c, service.DefaultBookingPageSize, service.MaxBookingPageSize)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+	order, err := response.ParseSort(c, service.BookingSortColumns)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+	selector, err := parseFieldSelector(c, models.Booking{}, bookingRelations)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	bookings, total, err := h.bookingService.GetUserBookingsByTelegramID(c.Request.Context(), user, telegramID, page.Limit, page.Offset, order)
+	if err != nil {
+		if errors.Is(err, service.ErrNotAuthorized) {
+			requestLogger(c).Info("bot denied user bookings", "telegram_id", telegramID, "acting_telegram_id", user.TelegramID)
+		}
+		_ = c.Error(err)
+		return
+	}
+
+	data, err := selector.project(bookings)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.Paginated(c, data, page.Meta(total))
+}
+
+// ExportUserWeek renders the week of a user as a ready Telegram message and, optionally, an iCalendar file
+// POST /api/bot/bookings/user/:telegram_id/export?date=&ics=true
+// date - любой день недели (YYYY-MM-DD или RFC3339; смещение задаёт часовой пояс, по умолчанию текущая неделя).
+// Дата без смещения и текущая неделя считаются в часовом поясе пространства (OFFICE_TIMEZONE), как в календаре.
+// Доступно самому пользователю и администраторам (по X-Telegram-User-ID)
+func (h *BotHandler) ExportUserWeek(c *gin.Context) {
+	telegramID, err := strconv.ParseInt(c.Param("telegram_id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	user, ok := botUser(c)
+	if !ok {
+		return
+	}
 
-	bookings, err := h.bookingService.GetUserBookingsByTelegramID(telegramID)
+	loc := h.bookingService.CalendarLocation()
+	date := time.Now().In(loc)
+	if value := c.Query("date"); value != "" {
+		t, err := time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			if t, err = utils.ParseFlexibleTime(value); err != nil {
+				response.BadRequest(c, err)
+				return
+			}
+		}
+		date = t
+	}
+	withICS := false
+	if value := c.Query("ics"); value != "" {
+		if withICS, err = strconv.ParseBool(value); err != nil {
+			response.BadRequest(c, err)
+			return
+		}
+	}
+
+	export, err := h.bookingService.ExportUserWeek(c.Request.Context(), user, telegramID, date, withICS)
 	if err != nil {
-		log.Printf("ERROR: Bot failed to get bookings for Telegram user %d: %v", telegramID, err)
-		response.InternalServerError(c, err)
+		if errors.Is(err, service.ErrNotAuthorized) {
+			requestLogger(c).Info("bot denied week export", "telegram_id", telegramID, "acting_telegram_id", user.TelegramID)
+		}
+		_ = c.Error(err)
 		return
 	}
 
-	response.Success(c, bookings)
+	response.Success(c, export)
 }
 
 // GetRoomBookings returns all bookings for a specific room
-// GET /api/bot/rooms/:id/bookings
+// GET /api/bot/rooms/:id/bookings?date= (или start=&end=)&fields=&include=
+// date - день в часовом поясе комнаты
 func (h *BotHandler) GetRoomBookings(c *gin.Context) {
 	roomIDStr := c.Param("id")
 	roomID, err := strconv.ParseUint(roomIDStr, 10, 64)
@@ -201,19 +372,17 @@ func (h *BotHandler) GetRoomBookings(c *gin.Context) {
 	startTimeStr := c.Query("start")
 	endTimeStr := c.Query("end")
 
-	var startTime, endTime time.Time
+	var startTime, endTime, date time.Time
 
 	// If "date" parameter is provided, use it for a single day
+	// Границы дня считаются в часовом поясе комнаты
 	if dateStr != "" {
 		t, err := utils.ParseFlexibleTime(dateStr)
 		if err != nil {
 			response.BadRequest(c, err)
 			return
 		}
-		// Set start to beginning of the day (00:00:00)
-		startTime = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
-		// Set end to end of the day (23:59:59)
-		endTime = time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 59, 999999999, t.Location())
+		date = t
 	} else {
 		// Use "start" and "end" parameters
 		// Default to current time and 30 days ahead if not specified
@@ -240,13 +409,149 @@ func (h *BotHandler) GetRoomBookings(c *gin.Context) {
 		}
 	}
 
-	bookings, err := h.bookingService.GetRoomBookings(uint(roomID), startTime, endTime)
+	selector, err := parseFieldSelector(c, models.Booking{}, bookingRelations)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	var bookings []models.Booking
+	if dateStr != "" {
+		bookings, err = h.bookingService.GetRoomDayBookings(c.Request.Context(), uint(roomID), date)
+	} else {
+		bookings, err = h.bookingService.GetRoomBookings(c.Request.Context(), uint(roomID), startTime, endTime)
+	}
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	data, err := selector.project(bookings)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.Success(c, data)
+}
+
+// requestLogger возвращает логгер текущего запроса (с request_id и user_id)
+func requestLogger(c *gin.Context) *slog.Logger {
+	return logger.FromContext(c.Request.Context())
+}
+
+// GetFreeSlots returns free windows of a room that fit a booking, with a suggested slot in each
+// GET /api/bot/rooms/:id/free-slots?date=&duration=60
+// date - день (YYYY-MM-DD, по умолчанию сегодня) или время (RFC3339): тогда слоты ранжируются по близости к нему,
+// например "после обеда" - date=...T14:00:00+03:00. duration - длительность в минутах (по умолчанию 60)
+func (h *BotHandler) GetFreeSlots(c *gin.Context) {
+	roomID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	date := time.Now()
+	var preferred time.Time
+	if value := c.Query("date"); value != "" {
+		t, err := utils.ParseFlexibleTime(value)
+		if err != nil {
+			response.BadRequest(c, err)
+			return
+		}
+		date = t
+		if len(value) > len("2006-01-02") {
+			preferred = t
+		}
+	}
+
+	duration := 60
+	if value := c.Query("duration"); value != "" {
+		if duration, err = strconv.Atoi(value); err != nil {
+			response.BadRequest(c, service.ErrInvalidDuration)
+			return
+		}
+	}
+
+	slots, err := h.bookingService.GetFreeSlots(c.Request.Context(), uint(roomID), date, time.Duration(duration)*time.Minute, preferred)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Success(c, slots)
+}
+
+// GetDigest returns the bookings of all rooms for a day grouped by room, for the daily schedule in the group chat
+// GET /api/bot/digest?date= (YYYY-MM-DD или RFC3339; смещение задаёт часовой пояс дня, по умолчанию сегодня)
+func (h *BotHandler) GetDigest(c *gin.Context) {
+	date := time.Now()
+	if value := c.Query("date"); value != "" {
+		t, err := utils.ParseFlexibleTime(value)
+		if err != nil {
+			response.BadRequest(c, err)
+			return
+		}
+		date = t
+	}
+
+	digest, err := h.bookingService.GetDailyDigest(c.Request.Context(), date)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Success(c, digest)
+}
+
+// GetUser tells whether a Telegram user is registered and how to address them
+// GET /api/bot/users/:telegram_id - 404, если пользователь ещё не заходил ни в Mini App, ни через бота
+func (h *BotHandler) GetUser(c *gin.Context) {
+	telegramID, err := strconv.ParseInt(c.Param("telegram_id"), 10, 64)
+	if err != nil || telegramID <= 0 {
+		response.BadRequest(c, service.ErrInvalidTelegramID)
+		return
+	}
+
+	user, err := h.userService.GetUserByTelegramID(c.Request.Context(), telegramID)
 	if err != nil {
-		log.Printf("ERROR: Bot failed to get bookings for room %d: %v", roomID, err)
-		response.InternalServerError(c, err)
+		_ = c.Error(err)
 		return
 	}
 
-	response.Success(c, bookings)
+	response.Success(c, service.NewUserCard(user))
 }
 
+// SyncUser updates the profile of the acting user from the X-Telegram-* headers
+// POST /api/bot/users/sync - пользователь создаётся при первом обращении бота,
+// а существующий профиль обновляется только этим вызовом (как /api/users/me/sync-telegram)
+func (h *BotHandler) SyncUser(c *gin.Context) {
+	user, ok := botUser(c)
+	if !ok {
+		return
+	}
+
+	updated, err := h.userService.SyncUserFromTelegram(
+		c.Request.Context(),
+		user.TelegramID,
+		c.GetHeader("X-Telegram-Username"),
+		c.GetHeader("X-Telegram-First-Name"),
+		c.GetHeader("X-Telegram-Last-Name"),
+		c.GetHeader("X-Telegram-Language-Code"),
+	)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Success(c, service.NewUserCard(updated))
+}
+
+// botUser возвращает пользователя, от имени которого действует бот (устанавливается BotAuthMiddleware)
+func botUser(c *gin.Context) (*models.User, bool) {
+	user, ok := c.Get("user")
+	if !ok {
+		response.Unauthorized(c, service.ErrNotAuthorized)
+		return nil, false
+	}
+	return user.(*models.User), true
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/response"
+)
+
+// BroadcastHandler handles admin announcements to room subscribers
+type BroadcastHandler struct {
+	broadcastService *service.BroadcastService
+}
+
+// NewBroadcastHandler creates a new broadcast handler
+func NewBroadcastHandler(broadcastService *service.BroadcastService) *BroadcastHandler {
+	return &BroadcastHandler{broadcastService: broadcastService}
+}
+
+// BroadcastRequest represents an announcement to room subscribers
+type BroadcastRequest struct {
+	Message string `json:"message" binding:"required"`
+}
+
+// BroadcastResponse describes a queued announcement
+type BroadcastResponse struct {
+	RoomID  uint      `json:"room_id"`
+	Message string    `json:"message"`
+	SentAt  time.Time `json:"sent_at"`
+}
+
+// Broadcast godoc
+// @Summary Send an announcement to room subscribers (admin only)
+// @Description Delivered to the room's notification subscribers through the bot webhook and to the room's Slack targets.
+// @Description One announcement per room per BROADCAST_MIN_INTERVAL; a too frequent one gets 429.
+// @Description The request is recorded in the audit log like every admin change.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Room ID"
+// @Param broadcast body BroadcastRequest true "Announcement, at most 1000 characters"
+// @Success 202 {object} BroadcastResponse
+// @Security TelegramInitData
+// @Router /api/admin/rooms/{id}/broadcast [post]
+func (h *BroadcastHandler) Broadcast(c *gin.Context) {
+	roomID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	var req BroadcastRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	broadcast, err := h.broadcastService.Broadcast(c.Request.Context(), uint(roomID), c.GetUint("userID"), req.Message)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response.SuccessResponse{Data: BroadcastResponse{
+		RoomID:  broadcast.Room.ID,
+		Message: broadcast.Message,
+		SentAt:  broadcast.SentAt,
+	}})
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/response"
+)
+
+// CheckInHandler handles check-ins by scanning a room's QR code
+type CheckInHandler struct {
+	checkInService *service.CheckInService
+}
+
+// NewCheckInHandler creates a new check-in handler
+func NewCheckInHandler(checkInService *service.CheckInService) *CheckInHandler {
+	return &CheckInHandler{checkInService: checkInService}
+}
+
+// CheckIn godoc
+// @Summary Check in to a room
+// @Description Called by the Mini App after scanning the room's QR code, which carries only the room ID.
+// @Description Checks the user in to their booking of the room (as creator or participant) that is in progress
+// @Description or starts within 15 minutes. Scanning again returns the existing check-in with already_checked_in = true
+// @Tags rooms
+// @Produce json
+// @Param id path int true "Room ID"
+// @Success 200 {object} service.CheckInResult
+// @Security TelegramInitData
+// @Router /api/rooms/{id}/checkin [post]
+func (h *CheckInHandler) CheckIn(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	result, err := h.checkInService.CheckIn(c.Request.Context(), uint(id), c.GetUint("userID"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Success(c, result)
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/response"
+)
+
+// CleaningHandler handles cleaning tasks for facility staff
+type CleaningHandler struct {
+	cleaningService *service.CleaningService
+}
+
+// NewCleaningHandler creates a new cleaning handler
+func NewCleaningHandler(cleaningService *service.CleaningService) *CleaningHandler {
+	return &CleaningHandler{cleaningService: cleaningService}
+}
+
+// ListTasks godoc
+// @Summary List cleaning tasks
+// @Description Tasks generated after bookings of rooms with reset_required (admin or API key with the facility:cleaning scope).
+// @Description Open tasks are listed oldest first, done tasks newest first
+// @Tags cleaning
+// @Produce json
+// @Param status query string false "Task status" Enums(open, done)
+// @Param room_id query int false "Only this room"
+// @Param page query int false "Page number, starting from 1"
+// @Param per_page query int false "Page size (default 100, max 500)"
+// @Param cursor query string false "Cursor from meta.next_cursor"
+// @Success 200 {object} response.PaginatedResponse{data=[]models.CleaningTask}
+// @Security TelegramInitData
+// @Security APIKey
+// @Router /api/admin/cleaning-tasks [get]
+// @Router /api/integration/cleaning-tasks [get]
+func (h *CleaningHandler) ListTasks(c *gin.Context) {
+	page, err := response.ParsePage(c, service.DefaultListPageSize, service.MaxListPageSize)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+	var roomID uint64
+	if value := c.Query("room_id"); value != "" {
+		if roomID, err = strconv.ParseUint(value, 10, 32); err != nil {
+			response.BadRequest(c, err)
+			return
+		}
+	}
+
+	tasks, total, err := h.cleaningService.ListTasks(c.Request.Context(), models.CleaningTaskStatus(c.Query("status")), uint(roomID), page.Limit, page.Offset)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Paginated(c, tasks, page.Meta(total))
+}
+
+// CompleteTask godoc
+// @Summary Mark a cleaning task as done
+// @Description The room stops being reported as needs_cleaning once it has no open tasks
+// @Tags cleaning
+// @Produce json
+// @Param id path int true "Task ID"
+// @Success 200 {object} models.CleaningTask
+// @Security TelegramInitData
+// @Security APIKey
+// @Router /api/admin/cleaning-tasks/{id}/complete [post]
+// @Router /api/integration/cleaning-tasks/{id}/complete [post]
+func (h *CleaningHandler) CompleteTask(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	task, err := h.cleaningService.CompleteTask(c.Request.Context(), uint(id), c.GetUint("userID"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Success(c, task)
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/config"
+	"github.com/space/backend/pkg/response"
+)
+
+// ConfigHandler handles admin operations on the runtime configuration
+type ConfigHandler struct {
+	liveConfig *config.Live
+}
+
+// NewConfigHandler creates a new config handler
+func NewConfigHandler(liveConfig *config.Live) *ConfigHandler {
+	return &ConfigHandler{liveConfig: liveConfig}
+}
+
+// Reload godoc
+// @Summary Reload configuration (admin only)
+// @Description Re-reads the config file and applies the safe subset (allowed origins, chat ID, webhook URL, rate limits) without a restart
+// @Tags admin
+// @Produce json
+// @Success 200 {object} config.ReloadResult
+// @Security TelegramInitData
+// @Router /api/admin/config/reload [post]
+func (h *ConfigHandler) Reload(c *gin.Context) {
+	result, err := h.liveConfig.Reload()
+	if err != nil {
+		var validationErr *config.ValidationError
+		if errors.As(err, &validationErr) {
+			response.ErrorWithMessage(c, http.StatusUnprocessableEntity, err, "configuration was not reloaded")
+			return
+		}
+		response.InternalServerError(c, err)
+		return
+	}
+
+	requestLogger(c).Info("configuration reloaded via admin endpoint",
+		"applied", result.Applied, "requires_restart", result.RequiresRestart)
+	response.Success(c, result)
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/response"
+)
+
+// DoorAccessHandler handles door access of bookings
+type DoorAccessHandler struct {
+	doorAccessService *service.DoorAccessService
+}
+
+// NewDoorAccessHandler creates a new door access handler
+func NewDoorAccessHandler(doorAccessService *service.DoorAccessService) *DoorAccessHandler {
+	return &DoorAccessHandler{doorAccessService: doorAccessService}
+}
+
+// GetAccess godoc
+// @Summary Get door access of a booking
+// @Description Available to the creator, participants and admins while access is active (from shortly before the start until the end). The PIN is present only if the lock driver issued one.
+// @Tags bookings
+// @Produce json
+// @Param id path int true "Booking ID"
+// @Success 200 {object} models.DoorAccessGrant
+// @Security TelegramInitData
+// @Router /api/bookings/{id}/access [get]
+func (h *DoorAccessHandler) GetAccess(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, service.ErrNotAuthorized)
+		return
+	}
+
+	grant, err := h.doorAccessService.GetAccess(c.Request.Context(), uint(id), userID.(uint))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Success(c, grant)
+}
@@ -0,0 +1,282 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/response"
+	"github.com/space/backend/pkg/utils"
+)
+
+// exportTimeLayout - формат дат в выгрузках, который Excel распознаёт как дату и время
+const exportTimeLayout = "2006-01-02 15:04"
+
+var errExportFormat = errors.New("format must be csv or json")
+
+// ExportHandler handles admin CSV and JSON exports
+type ExportHandler struct {
+	bookingService *service.BookingService
+	userService    *service.UserService
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(bookingService *service.BookingService, userService *service.UserService) *ExportHandler {
+	return &ExportHandler{
+		bookingService: bookingService,
+		userService:    userService,
+	}
+}
+
+// ExportBookings godoc
+// @Summary Export bookings as CSV or JSON (admin only)
+// @Description Active bookings in the range as a UTF-8 CSV with BOM, ready to open in Excel, or as a JSON array.
+// @Description The file is streamed in chunks, so large ranges are not held in memory.
+// @Tags admin
+// @Produce text/csv
+// @Produce json
+// @Param start query string true "Start date (RFC3339)"
+// @Param end query string true "End date (RFC3339)"
+// @Param room_id query int false "Only bookings of this room"
+// @Param tz query string false "IANA time zone for dates, e.g. Europe/Moscow (default UTC)"
+// @Param format query string false "csv (default) or json"
+// @Success 200 {string} string "CSV or JSON file"
+// @Security TelegramInitData
+// @Router /api/admin/export/bookings [get]
+func (h *ExportHandler) ExportBookings(c *gin.Context) {
+	startStr := c.Query("start")
+	endStr := c.Query("end")
+	if startStr == "" || endStr == "" {
+		response.BadRequest(c, service.ErrInvalidTime)
+		return
+	}
+
+	start, err := utils.ParseFlexibleTime(startStr)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+	end, err := utils.ParseFlexibleTime(endStr)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	var roomID uint64
+	if v := c.Query("room_id"); v != "" {
+		if roomID, err = strconv.ParseUint(v, 10, 32); err != nil {
+			response.BadRequest(c, err)
+			return
+		}
+	}
+
+	loc, err := exportLocation(c)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	name := fmt.Sprintf("bookings_%s_%s", start.In(loc).Format("2006-01-02"), end.In(loc).Format("2006-01-02"))
+	stream, err := newExportStream(c, name, bookingExportHeader)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	err = h.bookingService.ExportBookings(c.Request.Context(), start, end, uint(roomID), func(batch []models.Booking) error {
+		for i := range batch {
+			if err := stream.write(newBookingExport(&batch[i], loc)); err != nil {
+				return err
+			}
+		}
+		return stream.flush()
+	})
+	stream.finish(c, err)
+}
+
+// ExportPhonebook godoc
+// @Summary Export phonebook as CSV or JSON (admin only)
+// @Description The whole phonebook as a UTF-8 CSV with BOM, ready to open in Excel, or as a JSON array.
+// @Description The file is streamed in chunks, so the phonebook is not held in memory.
+// @Tags admin
+// @Produce text/csv
+// @Produce json
+// @Param format query string false "csv (default) or json"
+// @Success 200 {string} string "CSV or JSON file"
+// @Security TelegramInitData
+// @Router /api/admin/export/phonebook [get]
+func (h *ExportHandler) ExportPhonebook(c *gin.Context) {
+	stream, err := newExportStream(c, "phonebook", phonebookExportHeader)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	err = h.userService.ExportPhonebook(c.Request.Context(), func(batch []models.User) error {
+		for i := range batch {
+			if err := stream.write(newPhonebookExport(&batch[i])); err != nil {
+				return err
+			}
+		}
+		return stream.flush()
+	})
+	stream.finish(c, err)
+}
+
+var bookingExportHeader = []string{"ID", "Room", "Title", "Start", "End", "Status", "Creator", "Username", "Participants", "Estimated participants", "Created at"}
+
+// bookingExport - строка выгрузки бронирований; JSON содержит те же поля, что и CSV
+type bookingExport struct {
+	ID                    uint      `json:"id"`
+	Room                  string    `json:"room"`
+	Title                 string    `json:"title"`
+	StartTime             time.Time `json:"start_time"`
+	EndTime               time.Time `json:"end_time"`
+	Status                string    `json:"status"`
+	Creator               string    `json:"creator"`
+	Username              string    `json:"username"`
+	Participants          []string  `json:"participants"`
+	EstimatedParticipants int       `json:"estimated_participants"`
+	CreatedAt             time.Time `json:"created_at"`
+}
+
+func newBookingExport(b *models.Booking, loc *time.Location) bookingExport {
+	participants := make([]string, len(b.Participants))
+	for i := range b.Participants {
+		participants[i] = displayName(&b.Participants[i])
+	}
+	return bookingExport{
+		ID:                    b.ID,
+		Room:                  b.Room.Name,
+		Title:                 b.Title,
+		StartTime:             b.StartTime.In(loc),
+		EndTime:               b.EndTime.In(loc),
+		Status:                string(b.Status),
+		Creator:               displayName(&b.Creator),
+		Username:              b.Creator.Username,
+		Participants:          participants,
+		EstimatedParticipants: b.EstimatedParticipants,
+		CreatedAt:             b.CreatedAt.In(loc),
+	}
+}
+
+func (e bookingExport) csvRow() []string {
+	return []string{
+		strconv.FormatUint(uint64(e.ID), 10),
+		e.Room,
+		e.Title,
+		e.StartTime.Format(exportTimeLayout),
+		e.EndTime.Format(exportTimeLayout),
+		e.Status,
+		e.Creator,
+		e.Username,
+		strings.Join(e.Participants, ", "),
+		strconv.Itoa(e.EstimatedParticipants),
+		e.CreatedAt.Format(exportTimeLayout),
+	}
+}
+
+var phonebookExportHeader = []string{"Last name", "First name", "Username", "Phone", "About"}
+
+// phonebookExport - строка выгрузки телефонной книги
+type phonebookExport struct {
+	LastName    string `json:"last_name"`
+	FirstName   string `json:"first_name"`
+	Username    string `json:"username"`
+	PhoneNumber string `json:"phone_number"`
+	About       string `json:"about"`
+}
+
+func newPhonebookExport(u *models.User) phonebookExport {
+	return phonebookExport{LastName: u.LastName, FirstName: u.FirstName, Username: u.Username, PhoneNumber: u.PhoneNumber, About: u.About}
+}
+
+func (e phonebookExport) csvRow() []string {
+	return []string{e.LastName, e.FirstName, e.Username, e.PhoneNumber, e.About}
+}
+
+// exportRow - строка выгрузки, которую можно записать и в CSV, и в JSON
+type exportRow interface {
+	csvRow() []string
+}
+
+// exportStream пишет выгрузку в формате из ?format= (csv по умолчанию или json)
+type exportStream struct {
+	csv  *response.CSVStream
+	json *response.JSONStream
+}
+
+func newExportStream(c *gin.Context, name string, header []string) (*exportStream, error) {
+	switch c.DefaultQuery("format", "csv") {
+	case "csv":
+		return &exportStream{csv: response.NewCSVStream(c, name+".csv", header)}, nil
+	case "json":
+		return &exportStream{json: response.NewJSONStream(c, name+".json")}, nil
+	default:
+		return nil, errExportFormat
+	}
+}
+
+func (s *exportStream) write(row exportRow) error {
+	if s.csv != nil {
+		return s.csv.WriteRow(row.csvRow())
+	}
+	return s.json.Write(row)
+}
+
+func (s *exportStream) flush() error {
+	if s.csv != nil {
+		return s.csv.Flush()
+	}
+	return s.json.Flush()
+}
+
+func (s *exportStream) started() bool {
+	if s.csv != nil {
+		return s.csv.Started()
+	}
+	return s.json.Started()
+}
+
+// finish завершает выгрузку. Ошибку до первой пачки ещё можно вернуть обычным ответом;
+// после неё статус уже отправлен - выгрузка обрывается и ошибка только логируется
+func (s *exportStream) finish(c *gin.Context, err error) {
+	switch {
+	case err == nil:
+		var closeErr error
+		if s.csv != nil {
+			closeErr = s.csv.Close()
+		} else {
+			closeErr = s.json.Close()
+		}
+		if closeErr != nil {
+			requestLogger(c).Warn("export was not delivered", "error", closeErr)
+		}
+	case !s.started():
+		_ = c.Error(err)
+	default:
+		requestLogger(c).Error("export interrupted", "error", err)
+	}
+}
+
+// exportLocation возвращает часовой пояс из ?tz= (по умолчанию UTC)
+func exportLocation(c *gin.Context) (*time.Location, error) {
+	tz := c.Query("tz")
+	if tz == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(tz)
+}
+
+// displayName возвращает имя и фамилию пользователя, а если их нет - username
+func displayName(u *models.User) string {
+	if name := strings.TrimSpace(u.FirstName + " " + u.LastName); name != "" {
+		return name
+	}
+	return u.Username
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/response"
+	"github.com/space/backend/pkg/utils"
+)
+
+// defaultRatingReportPeriod - период отчёта по оценкам комнат, если since не передан
+const defaultRatingReportPeriod = 90 * 24 * time.Hour
+
+// FeedbackHandler handles booking feedback and the room rating report
+type FeedbackHandler struct {
+	feedbackService *service.FeedbackService
+}
+
+// NewFeedbackHandler creates a new feedback handler
+func NewFeedbackHandler(feedbackService *service.FeedbackService) *FeedbackHandler {
+	return &FeedbackHandler{feedbackService: feedbackService}
+}
+
+// SubmitFeedback godoc
+// @Summary Rate the room after a booking
+// @Description A participant (creator or joined user) rates the room and its equipment from 1 to 5 once the booking has ended.
+// @Description Sending feedback again replaces the previous rating and comment
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param id path int true "Booking ID"
+// @Param feedback body service.FeedbackRequest true "Rating and comment"
+// @Success 200 {object} models.BookingFeedback
+// @Security TelegramInitData
+// @Router /api/bookings/{id}/feedback [post]
+func (h *FeedbackHandler) SubmitFeedback(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	var req service.FeedbackRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	feedback, err := h.feedbackService.Submit(c.Request.Context(), uint(id), c.GetUint("userID"), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Success(c, feedback)
+}
+
+// GetRoomRatings godoc
+// @Summary Room rating report (admin only)
+// @Description Feedback aggregates of rooms rated since the given time, worst rated first; low_count is the number of ratings 1-2
+// @Tags admin
+// @Produce json
+// @Param since query string false "Feedback left since (RFC3339, default: 90 days ago)"
+// @Success 200 {array} repository.RoomRatingStats
+// @Security TelegramInitData
+// @Router /api/admin/room-ratings [get]
+func (h *FeedbackHandler) GetRoomRatings(c *gin.Context) {
+	since := time.Now().Add(-defaultRatingReportPeriod)
+	if value := c.Query("since"); value != "" {
+		parsed, err := utils.ParseFlexibleTime(value)
+		if err != nil {
+			response.BadRequest(c, err)
+			return
+		}
+		since = parsed
+	}
+
+	report, err := h.feedbackService.RoomReport(c.Request.Context(), since)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.Success(c, report)
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bookingRelations - связи бронирования, которые разворачиваются через ?include=
+var bookingRelations = []string{"room", "creator", "participants"}
+
+// fieldSelector выбирает поля ответа по ?fields= и связи по ?include=
+// Без параметров ответ отдаётся целиком, как раньше; с ?fields= связи
+// включаются только явно перечисленные в ?include=
+type fieldSelector struct {
+	relations []string
+	fields    map[string]bool // nil - все поля
+	include   map[string]bool // nil - все связи
+}
+
+// parseFieldSelector разбирает ?fields= и ?include= и проверяет имена по JSON-тегам модели
+// Например: ?fields=id,title,start_time,end_time&include=room
+func parseFieldSelector(c *gin.Context, model interface{}, relations []string) (*fieldSelector, error) {
+	s := &fieldSelector{relations: relations}
+
+	if v := c.Query("fields"); v != "" {
+		allowed := jsonFieldNames(reflect.TypeOf(model), relations)
+		s.fields = map[string]bool{"id": true} // id нужен клиенту всегда
+		for _, name := range splitList(v) {
+			if !allowed[name] {
+				return nil, fmt.Errorf("unknown field %q", name)
+			}
+			s.fields[name] = true
+		}
+		// С fields связи не отдаются, пока их не запросили через include
+		s.include = map[string]bool{}
+	}
+
+	if v := c.Query("include"); v != "" {
+		s.include = map[string]bool{}
+		for _, name := range splitList(v) {
+			if !slices.Contains(relations, name) {
+				return nil, fmt.Errorf("unknown include %q", name)
+			}
+			s.include[name] = true
+		}
+	}
+
+	return s, nil
+}
+
+// project оставляет в объекте или списке объектов только выбранные поля и связи
+func (s *fieldSelector) project(v interface{}) (interface{}, error) {
+	if s.fields == nil && s.include == nil {
+		return v, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if reflect.ValueOf(v).Kind() == reflect.Slice {
+		var items []map[string]json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			s.filter(item)
+		}
+		return items, nil
+	}
+
+	var item map[string]json.RawMessage
+	if err := json.Unmarshal(data, &item); err != nil {
+		return nil, err
+	}
+	s.filter(item)
+	return item, nil
+}
+
+func (s *fieldSelector) filter(item map[string]json.RawMessage) {
+	for name := range item {
+		if s.include != nil && slices.Contains(s.relations, name) {
+			if !s.include[name] {
+				delete(item, name)
+			}
+			continue
+		}
+		if s.fields != nil && !s.fields[name] {
+			delete(item, name)
+		}
+	}
+}
+
+// jsonFieldNames возвращает JSON-имена полей модели, кроме связей и скрытых полей
+func jsonFieldNames(t reflect.Type, relations []string) map[string]bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	names := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name == "" || name == "-" || slices.Contains(relations, name) {
+			continue
+		}
+		names[name] = true
+	}
+	return names
+}
+
+// splitList разбирает список через запятую, пропуская пустые элементы
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/models"
+)
+
+func selectorFor(t *testing.T, query string) (*fieldSelector, error) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/bookings?"+query, nil)
+	return parseFieldSelector(c, models.Booking{}, bookingRelations)
+}
+
+func projectedKeys(t *testing.T, query string) map[string]json.RawMessage {
+	t.Helper()
+	s, err := selectorFor(t, query)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	bookings := []models.Booking{{ID: 1, Title: "Standup", StartTime: time.Now(), EndTime: time.Now().Add(time.Hour)}}
+	projected, err := s.project(bookings)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	data, _ := json.Marshal(projected)
+
+	var items []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil || len(items) != 1 {
+		t.Fatalf("Expected one item, got: %s", data)
+	}
+	return items[0]
+}
+
+func TestFieldSelector_Fields(t *testing.T) {
+	item := projectedKeys(t, "fields=title,start_time,end_time")
+	if len(item) != 4 {
+		t.Errorf("Expected id, title, start_time, end_time, got: %v", item)
+	}
+	if _, ok := item["room"]; ok {
+		t.Error("Expected relations to be omitted without include")
+	}
+}
+
+func TestFieldSelector_Include(t *testing.T) {
+	item := projectedKeys(t, "include=room")
+	if _, ok := item["room"]; !ok {
+		t.Error("Expected room to be included")
+	}
+	if _, ok := item["creator"]; ok {
+		t.Error("Expected creator to be omitted")
+	}
+	if _, ok := item["title"]; !ok {
+		t.Error("Expected all fields without ?fields=")
+	}
+}
+
+func TestFieldSelector_Unknown(t *testing.T) {
+	for _, query := range []string{"fields=secret", "fields=room", "include=owner"} {
+		if _, err := selectorFor(t, query); err == nil {
+			t.Errorf("%q: expected an error", query)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/response"
+)
+
+// maxFloorImageSize ограничивает размер загружаемого плана этажа
+const maxFloorImageSize = 10 << 20
+
+// FloorHandler handles floors and floor plans
+type FloorHandler struct {
+	floorService *service.FloorService
+}
+
+// NewFloorHandler creates a new floor handler
+func NewFloorHandler(floorService *service.FloorService) *FloorHandler {
+	return &FloorHandler{floorService: floorService}
+}
+
+// ListFloors godoc
+// @Summary List floors
+// @Tags floors
+// @Produce json
+// @Success 200 {array} models.Floor
+// @Security TelegramInitData
+// @Router /api/floors [get]
+func (h *FloorHandler) ListFloors(c *gin.Context) {
+	floors, err := h.floorService.ListFloors(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.Success(c, floors)
+}
+
+// GetMap godoc
+// @Summary Floor map
+// @Description The floor, the URL of its plan image and markers of active rooms and parking spots placed on it.
+// @Description Marker coordinates are fractions (0..1) of the image width and height from the top left corner
+// @Tags floors
+// @Produce json
+// @Param id path int true "Floor ID"
+// @Success 200 {object} service.FloorMap
+// @Security TelegramInitData
+// @Router /api/floors/{id}/map [get]
+func (h *FloorHandler) GetMap(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	floorMap, err := h.floorService.GetMap(c.Request.Context(), uint(id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.Success(c, floorMap)
+}
+
+// GetImage godoc
+// @Summary Floor plan image
+// @Description PNG, JPEG or WebP. Requires the same authorization as the rest of the API; image_url of the map carries a version and may be cached
+// @Tags floors
+// @Produce png,jpeg
+// @Param id path int true "Floor ID"
+// @Success 200 {file} file
+// @Security TelegramInitData
+// @Router /api/floors/{id}/image [get]
+func (h *FloorHandler) GetImage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	path, contentType, err := h.floorService.OpenImage(c.Request.Context(), uint(id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Cache-Control", "private, max-age=86400")
+	c.File(path)
+}
+
+// CreateFloor godoc
+// @Summary Create a floor (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param floor body service.FloorRequest true "Floor name and level"
+// @Success 201 {object} models.Floor
+// @Security TelegramInitData
+// @Router /api/admin/floors [post]
+func (h *FloorHandler) CreateFloor(c *gin.Context) {
+	var req service.FloorRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	floor, err := h.floorService.CreateFloor(c.Request.Context(), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Set("auditEntityID", floor.ID) // ID созданной сущности для журнала аудита
+	response.Created(c, floor)
+}
+
+// UpdateFloor godoc
+// @Summary Change a floor (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Floor ID"
+// @Param floor body service.FloorRequest true "Floor name and level"
+// @Success 200 {object} models.Floor
+// @Security TelegramInitData
+// @Router /api/admin/floors/{id} [put]
+func (h *FloorHandler) UpdateFloor(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	var req service.FloorRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	floor, err := h.floorService.UpdateFloor(c.Request.Context(), uint(id), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.Success(c, floor)
+}
+
+// DeleteFloor godoc
+// @Summary Delete a floor (admin only)
+// @Description Deletes the floor and its plan; rooms placed on it stay bookable but leave the map
+// @Tags admin
+// @Param id path int true "Floor ID"
+// @Success 204
+// @Security TelegramInitData
+// @Router /api/admin/floors/{id} [delete]
+func (h *FloorHandler) DeleteFloor(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if err := h.floorService.DeleteFloor(c.Request.Context(), uint(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.NoContent(c)
+}
+
+// UploadImage godoc
+// @Summary Upload a floor plan (admin only)
+// @Description PNG, JPEG or WebP up to 10 MB, as the form field file or the request body. Replaces the previous plan;
+// @Description room coordinates are fractions of the image size, so a plan of the same proportions keeps markers in place
+// @Tags admin
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Floor ID"
+// @Param file formData file true "Plan image (or image request body)"
+// @Success 200 {object} models.Floor
+// @Security TelegramInitData
+// @Router /api/admin/floors/{id}/image [put]
+func (h *FloorHandler) UploadImage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	file, ok := uploadedFile(c, maxFloorImageSize)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	floor, err := h.floorService.UploadImage(c.Request.Context(), uint(id), file)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.Success(c, floor)
+}
+
+// PlaceRoom godoc
+// @Summary Place a room on a floor plan (admin only)
+// @Description Sets the floor and the marker position (fractions 0..1 of the plan width and height) of a room or parking spot;
+// @Description floor_id null removes it from the map
+// @Tags admin
+// @Accept json
+// @Param id path int true "Room ID"
+// @Param placement body service.RoomPlacementRequest true "Floor and position"
+// @Success 204
+// @Security TelegramInitData
+// @Router /api/admin/rooms/{id}/placement [put]
+func (h *FloorHandler) PlaceRoom(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	var req service.RoomPlacementRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.floorService.PlaceRoom(c.Request.Context(), uint(id), req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.NoContent(c)
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/service"
+)
+
+// HealthHandler handles liveness and readiness probes
+type HealthHandler struct {
+	healthService *service.HealthService
+}
+
+// NewHealthHandler creates a new health handler
+func NewHealthHandler(healthService *service.HealthService) *HealthHandler {
+	return &HealthHandler{healthService: healthService}
+}
+
+// Live godoc
+// @Summary Liveness probe
+// @Description Reports that the process is up and serving HTTP. Does not check dependencies, so a database outage does not restart the pod
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /health/live [get]
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{
+		"status":  service.HealthStatusOK,
+		"service": "Space Backend API",
+	})
+}
+
+// Ready godoc
+// @Summary Readiness probe
+// @Description Checks the database, applied migrations, background scheduler, Telegram Bot API and bot webhook target. Returns 503 when a critical dependency is unavailable or the server is draining before shutdown; other failures are reported as degraded with 200
+// @Tags health
+// @Produce json
+// @Success 200 {object} service.HealthReport
+// @Failure 503 {object} service.HealthReport
+// @Router /health/ready [get]
+func (h *HealthHandler) Ready(c *gin.Context) {
+	report := h.healthService.Check(c.Request.Context())
+
+	status := http.StatusOK
+	if report.Status == service.HealthStatusUnavailable || report.Status == service.HealthStatusDraining {
+		status = http.StatusServiceUnavailable
+	}
+
+	// Probe не должен кэшироваться прокси
+	c.Header("Cache-Control", "no-store")
+	c.JSON(status, report)
+}
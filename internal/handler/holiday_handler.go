@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/response"
+)
+
+var errInvalidYear = errors.New("year must be between 2000 and 2100")
+
+// HolidayHandler handles the closure calendar of the space
+type HolidayHandler struct {
+	holidayService *service.HolidayService
+}
+
+// NewHolidayHandler creates a new holiday handler
+func NewHolidayHandler(holidayService *service.HolidayService) *HolidayHandler {
+	return &HolidayHandler{holidayService: holidayService}
+}
+
+// ImportHolidaysRequest selects public holidays to import
+type ImportHolidaysRequest struct {
+	Year        int    `json:"year" binding:"required"`
+	CountryCode string `json:"country_code"` // ISO 3166-1 alpha-2; пусто - HOLIDAY_COUNTRY
+}
+
+// ListHolidays godoc
+// @Summary List closure days
+// @Description Days the space is closed (holidays); bookings overlapping them are rejected. Dates are in the space's timezone
+// @Tags holidays
+// @Produce json
+// @Param year query int false "Year (default: current year)"
+// @Success 200 {array} models.Holiday
+// @Security TelegramInitData
+// @Router /api/holidays [get]
+func (h *HolidayHandler) ListHolidays(c *gin.Context) {
+	year := time.Now().Year()
+	if value := c.Query("year"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || !validYear(parsed) {
+			response.BadRequest(c, errInvalidYear)
+			return
+		}
+		year = parsed
+	}
+
+	list, err := h.holidayService.List(c.Request.Context(), year)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.Success(c, list)
+}
+
+// CreateHoliday godoc
+// @Summary Add a closure day (admin only)
+// @Description Existing bookings on the day are kept; new bookings and moves onto it are rejected
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param holiday body service.HolidayRequest true "Date (YYYY-MM-DD) and name"
+// @Success 201 {object} models.Holiday
+// @Security TelegramInitData
+// @Router /api/admin/holidays [post]
+func (h *HolidayHandler) CreateHoliday(c *gin.Context) {
+	var req service.HolidayRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	holiday, err := h.holidayService.Create(c.Request.Context(), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Set("auditEntityID", holiday.ID) // ID созданной сущности для журнала аудита
+	response.Created(c, holiday)
+}
+
+// UpdateHoliday godoc
+// @Summary Change a closure day (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Holiday ID"
+// @Param holiday body service.HolidayRequest true "Date (YYYY-MM-DD) and name"
+// @Success 200 {object} models.Holiday
+// @Security TelegramInitData
+// @Router /api/admin/holidays/{id} [put]
+func (h *HolidayHandler) UpdateHoliday(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	var req service.HolidayRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	holiday, err := h.holidayService.Update(c.Request.Context(), uint(id), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.Success(c, holiday)
+}
+
+// DeleteHoliday godoc
+// @Summary Remove a closure day (admin only)
+// @Tags admin
+// @Param id path int true "Holiday ID"
+// @Success 204
+// @Security TelegramInitData
+// @Router /api/admin/holidays/{id} [delete]
+func (h *HolidayHandler) DeleteHoliday(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if err := h.holidayService.Delete(c.Request.Context(), uint(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.NoContent(c)
+}
+
+// ImportHolidays godoc
+// @Summary Import public holidays (admin only)
+// @Description Adds nationwide public holidays of a country for a year from the Nager.Date API (HOLIDAY_API_URL).
+// @Description Dates already in the calendar are kept as is, regional holidays are skipped
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param import body ImportHolidaysRequest true "Year and country"
+// @Success 200 {object} service.HolidayImportResult
+// @Security TelegramInitData
+// @Router /api/admin/holidays/import [post]
+func (h *HolidayHandler) ImportHolidays(c *gin.Context) {
+	var req ImportHolidaysRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if !validYear(req.Year) {
+		response.BadRequest(c, errInvalidYear)
+		return
+	}
+
+	result, err := h.holidayService.Import(c.Request.Context(), req.Year, req.CountryCode)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.Success(c, result)
+}
+
+func validYear(year int) bool {
+	return year >= 2000 && year <= 2100
+}
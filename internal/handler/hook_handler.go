@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/response"
+)
+
+// HookHandler handles REST hook subscriptions of API key integrations
+type HookHandler struct {
+	hookService *service.HookService
+}
+
+// NewHookHandler creates a new REST hook handler
+func NewHookHandler(hookService *service.HookService) *HookHandler {
+	return &HookHandler{hookService: hookService}
+}
+
+// Subscribe godoc
+// @Summary Subscribe to booking events (REST hook)
+// @Description Events are POSTed to target_url as service.HookPayload. Responding 410 Gone unsubscribes.
+// @Tags integration
+// @Accept json
+// @Produce json
+// @Param hook body service.SubscribeHookRequest true "Event and target URL"
+// @Success 201 {object} models.RESTHook
+// @Security APIKey
+// @Router /api/integration/hooks [post]
+func (h *HookHandler) Subscribe(c *gin.Context) {
+	apiKey, ok := currentAPIKey(c)
+	if !ok {
+		return
+	}
+
+	var req service.SubscribeHookRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	hook, err := h.hookService.Subscribe(c.Request.Context(), apiKey.ID, req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Set("auditEntityID", hook.ID) // ID созданной сущности для журнала аудита
+	response.Created(c, hook)
+}
+
+// Unsubscribe godoc
+// @Summary Unsubscribe a REST hook
+// @Tags integration
+// @Param id path int true "Hook ID"
+// @Success 204
+// @Security APIKey
+// @Router /api/integration/hooks/{id} [delete]
+func (h *HookHandler) Unsubscribe(c *gin.Context) {
+	apiKey, ok := currentAPIKey(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if err := h.hookService.Unsubscribe(c.Request.Context(), apiKey.ID, uint(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// ListHooks godoc
+// @Summary List REST hooks of the API key
+// @Tags integration
+// @Produce json
+// @Success 200 {array} models.RESTHook
+// @Security APIKey
+// @Router /api/integration/hooks [get]
+func (h *HookHandler) ListHooks(c *gin.Context) {
+	apiKey, ok := currentAPIKey(c)
+	if !ok {
+		return
+	}
+
+	hooks, err := h.hookService.ListHooks(c.Request.Context(), apiKey.ID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Success(c, hooks)
+}
+
+// Samples godoc
+// @Summary Sample payloads of an event
+// @Description Payloads built from upcoming bookings, for testing a Zap before a real event happens
+// @Tags integration
+// @Produce json
+// @Param event query string true "Event, e.g. booking.created"
+// @Success 200 {array} service.HookPayload
+// @Security APIKey
+// @Router /api/integration/hooks/samples [get]
+func (h *HookHandler) Samples(c *gin.Context) {
+	samples, err := h.hookService.Samples(c.Request.Context(), c.Query("event"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Success(c, samples)
+}
+
+// currentAPIKey возвращает API ключ, установленный APIKeyAuthMiddleware
+func currentAPIKey(c *gin.Context) (*models.APIKey, bool) {
+	value, _ := c.Get("apiKey")
+	apiKey, ok := value.(*models.APIKey)
+	if !ok {
+		response.Unauthorized(c, service.ErrNotAuthorized)
+		return nil, false
+	}
+	return apiKey, true
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/response"
+)
+
+// maxImportFileSize ограничивает размер загружаемого CSV
+const maxImportFileSize = 5 << 20
+
+// ImportHandler handles admin CSV imports
+type ImportHandler struct {
+	importService *service.ImportService
+}
+
+// NewImportHandler creates a new import handler
+func NewImportHandler(importService *service.ImportService) *ImportHandler {
+	return &ImportHandler{importService: importService}
+}
+
+// ImportRooms godoc
+// @Summary Import rooms from CSV (admin only)
+// @Description Columns: name (required), description, capacity, is_active, equipment (names separated by ";").
+// @Description The file is imported entirely or not at all; 422 returns errors of all rows. dry_run=true only validates.
+// @Tags admin
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV file (or text/csv request body)"
+// @Param dry_run query bool false "Validate without importing"
+// @Success 200 {object} service.ImportResult
+// @Failure 422 {object} map[string]interface{}
+// @Security TelegramInitData
+// @Router /api/admin/import/rooms [post]
+func (h *ImportHandler) ImportRooms(c *gin.Context) {
+	file, ok := uploadedFile(c, maxImportFileSize)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	result, err := h.importService.ImportRooms(c.Request.Context(), file, c.Query("dry_run") == "true")
+	h.respond(c, result, err)
+}
+
+// ImportBookings godoc
+// @Summary Import bookings from CSV (admin only)
+// @Description Columns: room (name), title, start, end (required); description, status (confirmed, cancelled, completed),
+// @Description estimated_participants, is_joinable, creator_email, creator_telegram_id (default: the importing admin).
+// @Description Times without an offset are read in tz; the format of /api/admin/export/bookings is accepted.
+// @Description Past bookings are allowed; overlapping bookings are rejected. No notifications are sent for imported bookings.
+// @Tags admin
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV file (or text/csv request body)"
+// @Param dry_run query bool false "Validate without importing"
+// @Param tz query string false "IANA time zone of times without offset, e.g. Europe/Moscow (default UTC)"
+// @Success 200 {object} service.ImportResult
+// @Failure 422 {object} map[string]interface{}
+// @Security TelegramInitData
+// @Router /api/admin/import/bookings [post]
+func (h *ImportHandler) ImportBookings(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, service.ErrNotAuthorized)
+		return
+	}
+
+	loc, err := exportLocation(c)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	file, ok := uploadedFile(c, maxImportFileSize)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	result, err := h.importService.ImportBookings(c.Request.Context(), file, loc, userID.(uint), c.Query("dry_run") == "true")
+	h.respond(c, result, err)
+}
+
+// respond отправляет итог импорта; файл с ошибками в строках - 422 с тем же итогом в data
+func (h *ImportHandler) respond(c *gin.Context, result *service.ImportResult, err error) {
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.Success(c, result)
+}
+
+// uploadedFile возвращает файл из поля формы file или из тела запроса (например, Content-Type: text/csv)
+// Тело ограничено maxSize; при ошибке ответ уже отправлен
+func uploadedFile(c *gin.Context, maxSize int64) (io.ReadCloser, bool) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize)
+
+	if !strings.HasPrefix(c.ContentType(), "multipart/") {
+		return c.Request.Body, true
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, err)
+		return nil, false
+	}
+	file, err := header.Open()
+	if err != nil {
+		_ = c.Error(err)
+		return nil, false
+	}
+	return file, true
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/scheduler"
+	"github.com/space/backend/pkg/response"
+)
+
+// JobsHandler exposes the state of scheduled background jobs
+type JobsHandler struct {
+	scheduler *scheduler.Scheduler
+}
+
+// NewJobsHandler creates a new jobs handler
+func NewJobsHandler(scheduler *scheduler.Scheduler) *JobsHandler {
+	return &JobsHandler{scheduler: scheduler}
+}
+
+// ListJobs godoc
+// @Summary List background jobs (admin only)
+// @Description Returns registered scheduled jobs with run counters and the last error
+// @Tags admin
+// @Produce json
+// @Success 200 {array} scheduler.JobStatus
+// @Security TelegramInitData
+// @Router /api/admin/jobs [get]
+func (h *JobsHandler) ListJobs(c *gin.Context) {
+	response.Success(c, h.scheduler.Jobs())
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/response"
+)
+
+// ModerationHandler handles the admin quarantine of booking events held by the content filter
+type ModerationHandler struct {
+	moderationService *service.ModerationService
+}
+
+// NewModerationHandler creates a new moderation handler
+func NewModerationHandler(moderationService *service.ModerationService) *ModerationHandler {
+	return &ModerationHandler{moderationService: moderationService}
+}
+
+// ListQuarantine godoc
+// @Summary List quarantined booking events (admin only)
+// @Description Booking announcements held back because the title or description broke the content filter
+// @Description (CONTENT_FILTER_* wordlist, emoji, repeated characters, long words). The bookings themselves exist
+// @Description and are visible in calendars; room subscribers, Slack and REST hooks are notified only after release
+// @Tags admin
+// @Produce json
+// @Param status query string false "Entry status" Enums(pending, released, rejected)
+// @Param page query int false "Page number, starting from 1"
+// @Param per_page query int false "Page size (default 100, max 500)"
+// @Param cursor query string false "Cursor from meta.next_cursor"
+// @Success 200 {object} response.PaginatedResponse{data=[]models.BookingQuarantine}
+// @Security TelegramInitData
+// @Router /api/admin/booking-quarantine [get]
+func (h *ModerationHandler) ListQuarantine(c *gin.Context) {
+	page, err := response.ParsePage(c, service.DefaultListPageSize, service.MaxListPageSize)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	entries, total, err := h.moderationService.List(c.Request.Context(), models.QuarantineStatus(c.Query("status")), page.Limit, page.Offset)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Paginated(c, entries, page.Meta(total))
+}
+
+// ReleaseQuarantine godoc
+// @Summary Release a quarantined booking event (admin only)
+// @Description Marks the entry as a false positive and broadcasts the held event with the current state of the booking.
+// @Description Nothing is sent for a booking cancelled meanwhile
+// @Tags admin
+// @Produce json
+// @Param id path int true "Quarantine entry ID"
+// @Success 200 {object} models.BookingQuarantine
+// @Security TelegramInitData
+// @Router /api/admin/booking-quarantine/{id}/release [post]
+func (h *ModerationHandler) ReleaseQuarantine(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	entry, err := h.moderationService.Release(c.Request.Context(), uint(id), c.GetUint("userID"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.Success(c, entry)
+}
+
+// RejectQuarantine godoc
+// @Summary Reject a quarantined booking event (admin only)
+// @Description Closes the entry without broadcasting. The booking stays - cancel it with DELETE /api/bookings/{id} if needed
+// @Tags admin
+// @Produce json
+// @Param id path int true "Quarantine entry ID"
+// @Success 200 {object} models.BookingQuarantine
+// @Security TelegramInitData
+// @Router /api/admin/booking-quarantine/{id}/reject [post]
+func (h *ModerationHandler) RejectQuarantine(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	entry, err := h.moderationService.Reject(c.Request.Context(), uint(id), c.GetUint("userID"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.Success(c, entry)
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/response"
+)
+
+// NoShowHandler handles the no-show report
+type NoShowHandler struct {
+	noShowService *service.NoShowService
+}
+
+// NewNoShowHandler creates a new no-show report handler
+func NewNoShowHandler(noShowService *service.NoShowService) *NoShowHandler {
+	return &NoShowHandler{noShowService: noShowService}
+}
+
+// GetNoShows godoc
+// @Summary No-show report (admin only)
+// @Description Users and rooms with the highest share of finished bookings nobody checked in to by the room's QR code.
+// @Description A no-show counts against the booking's creator. Rooms without a single check-in in the period are skipped:
+// @Description check-in is not used there. Rows with fewer than min_bookings bookings or without no-shows are omitted
+// @Tags admin
+// @Produce json,text/csv
+// @Param since query string false "Bookings started since (RFC3339, default: 90 days ago)"
+// @Param until query string false "Bookings ended by (RFC3339, default: now)"
+// @Param min_bookings query int false "Minimum finished bookings of a user or room (default: 3)"
+// @Param limit query int false "Rows per list (default: 20, max: 200)"
+// @Param format query string false "json (default) or csv"
+// @Success 200 {object} service.NoShowReport
+// @Security TelegramInitData
+// @Router /api/admin/reports/no-shows [get]
+func (h *NoShowHandler) GetNoShows(c *gin.Context) {
+	query := service.NoShowQuery{Until: time.Now()}
+	query.Since = query.Until.Add(-defaultRatingReportPeriod)
+	since, err := parseTimeQuery(c, "since")
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+	if since != nil {
+		query.Since = *since
+	}
+	until, err := parseTimeQuery(c, "until")
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+	if until != nil {
+		query.Until = *until
+	}
+	if value := c.Query("min_bookings"); value != "" {
+		if query.MinBookings, err = strconv.Atoi(value); err != nil {
+			response.BadRequest(c, err)
+			return
+		}
+	}
+	if value := c.Query("limit"); value != "" {
+		if query.Limit, err = strconv.Atoi(value); err != nil {
+			response.BadRequest(c, err)
+			return
+		}
+	}
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		response.BadRequest(c, errExportFormat)
+		return
+	}
+
+	report, err := h.noShowService.Report(c.Request.Context(), query)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if format == "json" {
+		response.Success(c, report)
+		return
+	}
+	rows := make([][]string, 0, len(report.Users)+len(report.Rooms))
+	for _, list := range []struct {
+		kind  string
+		stats []service.NoShowStats
+	}{{"user", report.Users}, {"room", report.Rooms}} {
+		for _, s := range list.stats {
+			rows = append(rows, []string{
+				list.kind,
+				strconv.FormatUint(uint64(s.ID), 10),
+				s.Name,
+				strconv.Itoa(s.Bookings),
+				strconv.Itoa(s.NoShows),
+				strconv.Itoa(s.NoShowPercent),
+			})
+		}
+	}
+	response.CSV(c, "no_shows_"+query.Since.Format("2006-01-02")+"_"+query.Until.Format("2006-01-02")+".csv",
+		[]string{"type", "id", "name", "bookings", "no_shows", "no_show_percent"}, rows)
+}
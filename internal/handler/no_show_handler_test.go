@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/internal/repository"
+	"github.com/space/backend/internal/service"
+)
+
+type noShowAttendance []repository.BookingAttendance
+
+func (a noShowAttendance) GetAttendance(ctx context.Context, from, to time.Time) ([]repository.BookingAttendance, error) {
+	return a, nil
+}
+
+type noShowRooms struct{ service.RoomStore }
+
+func (noShowRooms) GetAll(ctx context.Context, order string) ([]models.Room, error) {
+	return []models.Room{{ID: 1, Name: "Hall, 2nd floor"}}, nil
+}
+
+type noShowUsers struct{ service.UserStore }
+
+func (noShowUsers) GetByIDs(ctx context.Context, ids []uint) ([]models.User, error) {
+	return []models.User{{ID: 10, Username: "anna"}}, nil
+}
+
+func TestNoShowHandler_CSV(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	attendance := noShowAttendance{
+		{BookingID: 1, RoomID: 1, CreatorID: 10},
+		{BookingID: 2, RoomID: 1, CreatorID: 10},
+		{BookingID: 3, RoomID: 1, CreatorID: 10, CheckInCount: 1},
+	}
+	r := gin.New()
+	r.GET("/api/admin/reports/no-shows", NewNoShowHandler(service.NewNoShowService(attendance, noShowRooms{}, noShowUsers{})).GetNoShows)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet,
+		"/api/admin/reports/no-shows?format=csv&since=2026-01-01T00:00:00Z&until=2026-02-01T00:00:00Z", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got: %d %s", w.Code, w.Body.String())
+	}
+	if cd := w.Header().Get("Content-Disposition"); cd != `attachment; filename="no_shows_2026-01-01_2026-02-01.csv"` {
+		t.Errorf("Expected the period in the file name, got: %s", cd)
+	}
+	want := "type,id,name,bookings,no_shows,no_show_percent\n" +
+		"user,10,anna,3,2,67\n" +
+		"room,1,\"Hall, 2nd floor\",3,2,67\n"
+	if body := strings.TrimPrefix(w.Body.String(), "\xef\xbb\xbf"); body != want {
+		t.Errorf("Unexpected CSV:\n%s", body)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin/reports/no-shows?format=xml", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown format, got: %d", w.Code)
+	}
+}
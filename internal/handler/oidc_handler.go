@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/response"
+)
+
+// oidcFlowCookie хранит подписанные state, nonce и PKCE verifier между редиректами
+const oidcFlowCookie = "oidc_flow"
+
+// oidcCookiePath - cookie нужна только эндпоинтам входа
+const oidcCookiePath = "/api/auth/oidc"
+
+// OIDCHandler handles login through the OIDC provider
+// Вход идёт редиректами браузера: login -> провайдер -> callback -> OIDC_POST_LOGIN_URL#token=...
+type OIDCHandler struct {
+	oidcService  *service.OIDCService
+	loginURL     string
+	postLoginURL string
+	secureCookie bool
+}
+
+// NewOIDCHandler creates a new OIDC handler
+// redirectURL - адрес callback у провайдера; адрес login вычисляется относительно него
+func NewOIDCHandler(oidcService *service.OIDCService, redirectURL, postLoginURL string, secureCookie bool) *OIDCHandler {
+	loginURL := redirectURL
+	if u, err := url.Parse(redirectURL); err == nil {
+		loginURL = u.ResolveReference(&url.URL{Path: "login"}).String()
+	}
+	return &OIDCHandler{
+		oidcService:  oidcService,
+		loginURL:     loginURL,
+		postLoginURL: postLoginURL,
+		secureCookie: secureCookie,
+	}
+}
+
+// Login godoc
+// @Summary Start OIDC login
+// @Description Redirects the browser to the identity provider. With link (from POST /api/auth/oidc/link) the login is linked to the current Telegram account.
+// @Tags auth
+// @Param link query string false "Link ticket"
+// @Success 302
+// @Router /api/auth/oidc/login [get]
+func (h *OIDCHandler) Login(c *gin.Context) {
+	authURL, flowToken, err := h.oidcService.StartLogin(c.Request.Context(), c.Query("link"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode) // Cookie должна прийти с редиректом от провайдера
+	c.SetCookie(oidcFlowCookie, flowToken, int(service.OIDCFlowTTL.Seconds()), oidcCookiePath, "", h.secureCookie, true)
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback godoc
+// @Summary Finish OIDC login
+// @Description Redirects to OIDC_POST_LOGIN_URL with #token=...&expires_in=... (use as Authorization: Bearer) or #error=...
+// @Tags auth
+// @Param code query string true "Authorization code"
+// @Param state query string true "State"
+// @Success 302
+// @Router /api/auth/oidc/callback [get]
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	flowToken, _ := c.Cookie(oidcFlowCookie)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcFlowCookie, "", -1, oidcCookiePath, "", h.secureCookie, true)
+
+	if providerErr := c.Query("error"); providerErr != "" {
+		h.finish(c, url.Values{"error": {"access_denied"}})
+		return
+	}
+
+	user, err := h.oidcService.CompleteLogin(c.Request.Context(), flowToken, c.Query("state"), c.Query("code"))
+	if err != nil {
+		requestLogger(c).Warn("OIDC login failed", "error", err)
+		h.finish(c, url.Values{"error": {oidcErrorCode(err)}})
+		return
+	}
+
+	token, ttl, err := h.oidcService.IssueSession(user)
+	if err != nil {
+		requestLogger(c).Error("failed to issue session", "user_id", user.ID, "error", err)
+		h.finish(c, url.Values{"error": {"login_failed"}})
+		return
+	}
+
+	h.finish(c, url.Values{
+		"token":      {token},
+		"expires_in": {strconv.Itoa(int(ttl.Seconds()))},
+	})
+}
+
+// CreateLink godoc
+// @Summary Link an OIDC identity to the current account
+// @Description Returns a login URL; after login with the provider the identity (and its verified email) is attached to the current user. The URL is valid for 5 minutes.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Security TelegramInitData
+// @Router /api/auth/oidc/link [post]
+func (h *OIDCHandler) CreateLink(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, service.ErrNotAuthorized)
+		return
+	}
+
+	ticket, err := h.oidcService.CreateLinkTicket(userID.(uint))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Success(c, gin.H{"url": h.loginURL + "?" + url.Values{"link": {ticket}}.Encode()})
+}
+
+// finish возвращает браузер во фронтенд; токен передаётся во фрагменте, чтобы не попасть в логи серверов
+func (h *OIDCHandler) finish(c *gin.Context, params url.Values) {
+	c.Redirect(http.StatusFound, h.postLoginURL+"#"+params.Encode())
+}
+
+// oidcErrorCode - код ошибки для фронтенда
+func oidcErrorCode(err error) string {
+	switch {
+	case errors.Is(err, service.ErrEmailNotVerified):
+		return "email_not_verified"
+	case errors.Is(err, service.ErrEmailDomainNotAllowed):
+		return "domain_not_allowed"
+	case errors.Is(err, service.ErrIdentityLinked):
+		return "identity_linked"
+	default:
+		return "login_failed"
+	}
+}
@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/response"
+)
+
+// OwnershipHandler handles rooms dedicated to teams and delegated slots
+type OwnershipHandler struct {
+	ownershipService *service.OwnershipService
+}
+
+// NewOwnershipHandler creates a new ownership handler
+func NewOwnershipHandler(ownershipService *service.OwnershipService) *OwnershipHandler {
+	return &OwnershipHandler{ownershipService: ownershipService}
+}
+
+// ListOwnerships godoc
+// @Summary List rooms dedicated to teams (admin only)
+// @Tags admin
+// @Produce json
+// @Success 200 {array} models.RoomOwnership
+// @Security TelegramInitData
+// @Router /api/admin/room-ownerships [get]
+func (h *OwnershipHandler) ListOwnerships(c *gin.Context) {
+	ownerships, err := h.ownershipService.ListOwnerships(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.Success(c, ownerships)
+}
+
+// CreateOwnership godoc
+// @Summary Dedicate a room to a team (admin only)
+// @Description While the ownership is in force, only team members, admins and users with a delegated slot can book the room.
+// @Description Existing bookings of other users are kept
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param ownership body service.RoomOwnershipRequest true "Room, team and end of the ownership"
+// @Success 201 {object} models.RoomOwnership
+// @Security TelegramInitData
+// @Router /api/admin/room-ownerships [post]
+func (h *OwnershipHandler) CreateOwnership(c *gin.Context) {
+	var req service.RoomOwnershipRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	ownership, err := h.ownershipService.CreateOwnership(c.Request.Context(), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Set("auditEntityID", ownership.ID) // ID созданной сущности для журнала аудита
+	response.Created(c, ownership)
+}
+
+// UpdateOwnership godoc
+// @Summary Change a room ownership (admin only)
+// @Description Changes the team, note and end of the ownership; room_id is ignored
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Ownership ID"
+// @Param ownership body service.RoomOwnershipRequest true "Team and end of the ownership"
+// @Success 200 {object} models.RoomOwnership
+// @Security TelegramInitData
+// @Router /api/admin/room-ownerships/{id} [put]
+func (h *OwnershipHandler) UpdateOwnership(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	var req service.RoomOwnershipRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	ownership, err := h.ownershipService.UpdateOwnership(c.Request.Context(), uint(id), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.Success(c, ownership)
+}
+
+// DeleteOwnership godoc
+// @Summary Return a dedicated room to general booking (admin only)
+// @Tags admin
+// @Param id path int true "Ownership ID"
+// @Success 204
+// @Security TelegramInitData
+// @Router /api/admin/room-ownerships/{id} [delete]
+func (h *OwnershipHandler) DeleteOwnership(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if err := h.ownershipService.DeleteOwnership(c.Request.Context(), uint(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.NoContent(c)
+}
+
+// ListDelegations godoc
+// @Summary List delegated slots of a dedicated room
+// @Description Current and future slots handed to users outside the owning team; for team members and admins
+// @Tags rooms
+// @Produce json
+// @Param id path int true "Room ID"
+// @Success 200 {array} models.RoomDelegation
+// @Security TelegramInitData
+// @Router /api/rooms/{id}/delegations [get]
+func (h *OwnershipHandler) ListDelegations(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	delegations, err := h.ownershipService.ListDelegations(c.Request.Context(), uint(id), c.GetUint("userID"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.Success(c, delegations)
+}
+
+// Delegate godoc
+// @Summary Delegate a slot of a dedicated room
+// @Description Lets a user outside the owning team book the room within the period; for team members and admins
+// @Tags rooms
+// @Accept json
+// @Produce json
+// @Param id path int true "Room ID"
+// @Param delegation body service.RoomDelegationRequest true "User and period"
+// @Success 201 {object} models.RoomDelegation
+// @Security TelegramInitData
+// @Router /api/rooms/{id}/delegations [post]
+func (h *OwnershipHandler) Delegate(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	var req service.RoomDelegationRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	delegation, err := h.ownershipService.Delegate(c.Request.Context(), uint(id), c.GetUint("userID"), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Set("auditEntityID", delegation.ID) // ID созданной сущности для журнала аудита
+	response.Created(c, delegation)
+}
+
+// RevokeDelegation godoc
+// @Summary Revoke a delegated slot of a dedicated room
+// @Description Bookings already made in the slot are kept; for team members and admins
+// @Tags rooms
+// @Param id path int true "Room ID"
+// @Param delegation_id path int true "Delegation ID"
+// @Success 204
+// @Security TelegramInitData
+// @Router /api/rooms/{id}/delegations/{delegation_id} [delete]
+func (h *OwnershipHandler) RevokeDelegation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+	delegationID, err := strconv.ParseUint(c.Param("delegation_id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if err := h.ownershipService.RevokeDelegation(c.Request.Context(), uint(id), c.GetUint("userID"), uint(delegationID)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.NoContent(c)
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/response"
+	"github.com/space/backend/pkg/utils"
+)
+
+// ParkingHandler handles the parking tab of the Mini App
+type ParkingHandler struct {
+	parkingService *service.ParkingService
+}
+
+// NewParkingHandler creates a new parking handler
+func NewParkingHandler(parkingService *service.ParkingService) *ParkingHandler {
+	return &ParkingHandler{parkingService: parkingService}
+}
+
+// GetSpots godoc
+// @Summary Parking spots with availability
+// @Description Active parking spots and their busy intervals on a day; the user's own bookings are marked with mine = true
+// @Tags parking
+// @Produce json
+// @Param date query string false "Day (RFC3339 or YYYY-MM-DD, the offset sets the timezone of the day; default today)"
+// @Success 200 {array} service.ParkingSpotAvailability
+// @Security TelegramInitData
+// @Router /api/parking/spots [get]
+func (h *ParkingHandler) GetSpots(c *gin.Context) {
+	date := time.Now()
+	if value := c.Query("date"); value != "" {
+		t, err := utils.ParseFlexibleTime(value)
+		if err != nil {
+			response.BadRequest(c, err)
+			return
+		}
+		date = t
+	}
+
+	spots, err := h.parkingService.GetSpots(c.Request.Context(), c.GetUint("userID"), date)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.Success(c, spots)
+}
+
+// CreateBooking godoc
+// @Summary Book a parking spot
+// @Description A parking booking must start and end on the same day, and a user may hold one parking booking per day.
+// @Description The license plate is stored in upper case without spaces and hyphens
+// @Tags parking
+// @Accept json
+// @Produce json
+// @Param booking body service.CreateParkingBookingRequest true "Parking booking data"
+// @Success 201 {object} models.Booking
+// @Security TelegramInitData
+// @Router /api/parking/bookings [post]
+func (h *ParkingHandler) CreateBooking(c *gin.Context) {
+	var req service.CreateParkingBookingRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	booking, err := h.parkingService.BookSpot(c.Request.Context(), c.GetUint("userID"), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Set("auditEntityID", booking.ID) // ID созданной сущности для журнала аудита
+	response.Created(c, booking)
+}
+
+// GetMyBookings godoc
+// @Summary My parking bookings
+// @Description Parking bookings of the current user that have not ended yet, by start time
+// @Tags parking
+// @Produce json
+// @Success 200 {array} models.Booking
+// @Security TelegramInitData
+// @Router /api/parking/bookings [get]
+func (h *ParkingHandler) GetMyBookings(c *gin.Context) {
+	bookings, err := h.parkingService.GetUserBookings(c.Request.Context(), c.GetUint("userID"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.Success(c, bookings)
+}
+
+// CancelBooking godoc
+// @Summary Cancel a parking booking
+// @Tags parking
+// @Param id path int true "Booking ID"
+// @Success 204
+// @Security TelegramInitData
+// @Router /api/parking/bookings/{id} [delete]
+func (h *ParkingHandler) CancelBooking(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if err := h.parkingService.CancelBooking(c.Request.Context(), uint(id), c.GetUint("userID")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.NoContent(c)
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/response"
+)
+
+// PurgeHandler handles admin-triggered purge of soft-deleted rows
+type PurgeHandler struct {
+	purgeService *service.PurgeService
+}
+
+// NewPurgeHandler creates a new purge handler
+func NewPurgeHandler(purgeService *service.PurgeService) *PurgeHandler {
+	return &PurgeHandler{purgeService: purgeService}
+}
+
+// Purge godoc
+// @Summary Purge soft-deleted rows (admin only)
+// @Description Permanently deletes bookings, rooms and users soft-deleted longer than SOFT_DELETE_RETENTION_DAYS ago. Rows still referenced by other records are kept
+// @Tags admin
+// @Produce json
+// @Param dry_run query bool false "Only report what would be deleted"
+// @Success 200 {object} service.PurgeResult
+// @Security TelegramInitData
+// @Router /api/admin/purge [post]
+func (h *PurgeHandler) Purge(c *gin.Context) {
+	dryRun := false
+	if value := c.Query("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			response.BadRequest(c, errors.New("invalid dry_run"))
+			return
+		}
+		dryRun = parsed
+	}
+
+	result, err := h.purgeService.Purge(c.Request.Context(), dryRun)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Success(c, result)
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/i18n"
+	"github.com/space/backend/pkg/response"
+)
+
+// RentalHandler handles rental requests of outside parties and their moderation
+type RentalHandler struct {
+	rentalService *service.RentalService
+}
+
+// NewRentalHandler creates a new rental handler
+func NewRentalHandler(rentalService *service.RentalService) *RentalHandler {
+	return &RentalHandler{rentalService: rentalService}
+}
+
+// RejectRentalRequest represents an admin decision to decline a request
+type RejectRentalRequest struct {
+	Reason string `json:"reason"` // Отправляется заявителю
+}
+
+// SubmitRequest godoc
+// @Summary Request a room rental (public)
+// @Description Form for outside parties, protected by a captcha (CAPTCHA_PROVIDER). The request lands in the admin moderation queue
+// @Description and becomes a booking only when approved; the requester is notified by email in the request's language (Accept-Language)
+// @Tags rentals
+// @Accept json
+// @Produce json
+// @Param request body service.RentalRequestInput true "Contacts, event, time and captcha token"
+// @Success 201 {object} models.RentalRequest
+// @Router /api/public/rental-requests [post]
+func (h *RentalHandler) SubmitRequest(c *gin.Context) {
+	var req service.RentalRequestInput
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	request, err := h.rentalService.Submit(c.Request.Context(), req, c.GetString(i18n.ContextKey), c.ClientIP())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Set("auditEntityID", request.ID) // ID созданной сущности для журнала аудита
+	response.Created(c, request)
+}
+
+// ListRequests godoc
+// @Summary List rental requests (admin only)
+// @Description Moderation queue of rental requests from the public form, newest first
+// @Tags admin
+// @Produce json
+// @Param status query string false "Request status" Enums(pending, approved, rejected)
+// @Param page query int false "Page number, starting from 1"
+// @Param per_page query int false "Page size (default 100, max 500)"
+// @Param cursor query string false "Cursor from meta.next_cursor"
+// @Success 200 {object} response.PaginatedResponse{data=[]models.RentalRequest}
+// @Security TelegramInitData
+// @Router /api/admin/rental-requests [get]
+func (h *RentalHandler) ListRequests(c *gin.Context) {
+	page, err := response.ParsePage(c, service.DefaultListPageSize, service.MaxListPageSize)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	requests, total, err := h.rentalService.List(c.Request.Context(), models.RentalRequestStatus(c.Query("status")), page.Limit, page.Offset)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Paginated(c, requests, page.Meta(total))
+}
+
+// ApproveRequest godoc
+// @Summary Approve a rental request (admin only)
+// @Description Books the room on behalf of the admin and emails the requester. If the time is taken meanwhile,
+// @Description the request stays pending and the booking error is returned
+// @Tags admin
+// @Produce json
+// @Param id path int true "Rental request ID"
+// @Success 200 {object} models.RentalRequest
+// @Security TelegramInitData
+// @Router /api/admin/rental-requests/{id}/approve [post]
+func (h *RentalHandler) ApproveRequest(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	request, err := h.rentalService.Approve(c.Request.Context(), uint(id), c.GetUint("userID"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.Success(c, request)
+}
+
+// RejectRequest godoc
+// @Summary Reject a rental request (admin only)
+// @Description Closes a pending request and emails the requester with the reason
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Rental request ID"
+// @Param decision body RejectRentalRequest true "Reason sent to the requester (may be empty)"
+// @Success 200 {object} models.RentalRequest
+// @Security TelegramInitData
+// @Router /api/admin/rental-requests/{id}/reject [post]
+func (h *RentalHandler) RejectRequest(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	var req RejectRentalRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	request, err := h.rentalService.Reject(c.Request.Context(), uint(id), c.GetUint("userID"), req.Reason)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.Success(c, request)
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/response"
+)
+
+// RetentionHandler handles admin management of data retention rules
+type RetentionHandler struct {
+	retentionService *service.RetentionService
+}
+
+// NewRetentionHandler creates a new retention handler
+func NewRetentionHandler(retentionService *service.RetentionService) *RetentionHandler {
+	return &RetentionHandler{retentionService: retentionService}
+}
+
+// BookingExemptRequest sets or lifts the retention exemption of a booking
+type BookingExemptRequest struct {
+	Exempt *bool `json:"exempt" binding:"required"`
+}
+
+// ListRules godoc
+// @Summary List data retention rules (admin only)
+// @Tags admin
+// @Produce json
+// @Success 200 {array} models.RetentionRule
+// @Security TelegramInitData
+// @Router /api/admin/retention/rules [get]
+func (h *RetentionHandler) ListRules(c *gin.Context) {
+	rules, err := h.retentionService.ListRules(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Success(c, rules)
+}
+
+// SetRule godoc
+// @Summary Set the retention rule of an entity (admin only)
+// @Description The daily retention job permanently deletes rows older than max_age_days.
+// @Description bookings - by end time, including cancelled and soft-deleted ones, except exempt bookings; audit_logs - by record time.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param entity path string true "Entity" Enums(bookings, audit_logs)
+// @Param rule body service.SetRetentionRuleRequest true "Retention rule"
+// @Success 200 {object} models.RetentionRule
+// @Security TelegramInitData
+// @Router /api/admin/retention/rules/{entity} [put]
+func (h *RetentionHandler) SetRule(c *gin.Context) {
+	entity := c.Param("entity")
+	c.Set("auditEntityID", entity) // В пути нет :id - сущность правила передаётся журналу аудита явно
+
+	var req service.SetRetentionRuleRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	rule, err := h.retentionService.SetRule(c.Request.Context(), entity, req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Success(c, rule)
+}
+
+// DeleteRule godoc
+// @Summary Remove the retention rule of an entity (admin only)
+// @Tags admin
+// @Param entity path string true "Entity" Enums(bookings, audit_logs)
+// @Success 204
+// @Security TelegramInitData
+// @Router /api/admin/retention/rules/{entity} [delete]
+func (h *RetentionHandler) DeleteRule(c *gin.Context) {
+	entity := c.Param("entity")
+	c.Set("auditEntityID", entity) // В пути нет :id - сущность правила передаётся журналу аудита явно
+
+	if err := h.retentionService.DeleteRule(c.Request.Context(), entity); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// Preview godoc
+// @Summary Preview data retention (admin only)
+// @Description Counts rows the enabled rules would delete now; nothing is deleted
+// @Tags admin
+// @Produce json
+// @Success 200 {array} service.RetentionReport
+// @Security TelegramInitData
+// @Router /api/admin/retention/preview [get]
+func (h *RetentionHandler) Preview(c *gin.Context) {
+	reports, err := h.retentionService.Preview(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Success(c, reports)
+}
+
+// SetBookingExempt godoc
+// @Summary Exempt a booking from retention rules (admin only)
+// @Description Exempt bookings are never deleted by retention rules, e.g. while they are needed for an investigation
+// @Tags admin
+// @Accept json
+// @Param id path int true "Booking ID"
+// @Param exempt body BookingExemptRequest true "Exemption flag"
+// @Success 204
+// @Security TelegramInitData
+// @Router /api/admin/bookings/{id}/retention-exempt [put]
+func (h *RetentionHandler) SetBookingExempt(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	var req BookingExemptRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.retentionService.SetBookingExempt(c.Request.Context(), uint(id), *req.Exempt); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.NoContent(c)
+}
@@ -4,6 +4,7 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/models"
 	"github.com/space/backend/internal/service"
 	"github.com/space/backend/pkg/response"
 )
@@ -23,26 +24,40 @@ func NewRoomHandler(roomService *service.RoomService) *RoomHandler {
 // @Tags rooms
 // @Produce json
 // @Param with_equipment query bool false "Include equipment"
-// @Success 200 {array} models.Room
+// @Param page query int false "Page number, starting from 1"
+// @Param per_page query int false "Page size (default 100, max 500)"
+// @Param cursor query string false "Cursor from meta.next_cursor"
+// @Param sort query string false "Sort fields, '-' for descending, e.g. -capacity,name"
+// @Success 200 {object} response.PaginatedResponse{data=[]models.Room}
+// @Security TelegramInitData
 // @Router /api/rooms [get]
 func (h *RoomHandler) GetAllRooms(c *gin.Context) {
 	withEquipment := c.Query("with_equipment") == "true"
 
-	var rooms interface{}
-	var err error
+	page, err := response.ParsePage(c, service.DefaultListPageSize, service.MaxListPageSize)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+	order, err := response.ParseSort(c, service.RoomSortColumns)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
 
+	var rooms []models.Room
 	if withEquipment {
-		rooms, err = h.roomService.GetAllRoomsWithEquipment()
+		rooms, err = h.roomService.GetAllRoomsWithEquipment(c.Request.Context(), order)
 	} else {
-		rooms, err = h.roomService.GetAllRooms()
+		rooms, err = h.roomService.GetAllRooms(c.Request.Context(), order)
 	}
 
 	if err != nil {
-		response.InternalServerError(c, err)
+		_ = c.Error(err)
 		return
 	}
 
-	response.Success(c, rooms)
+	response.Paginated(c, response.Slice(rooms, page), page.Meta(int64(len(rooms))))
 }
 
 // GetRoom godoc
@@ -51,6 +66,7 @@ func (h *RoomHandler) GetAllRooms(c *gin.Context) {
 // @Produce json
 // @Param id path int true "Room ID"
 // @Success 200 {object} models.Room
+// @Security TelegramInitData
 // @Router /api/rooms/{id} [get]
 func (h *RoomHandler) GetRoom(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -59,7 +75,7 @@ func (h *RoomHandler) GetRoom(c *gin.Context) {
 		return
 	}
 
-	room, err := h.roomService.GetRoom(uint(id))
+	room, err := h.roomService.GetRoom(c.Request.Context(), uint(id))
 	if err != nil {
 		response.NotFound(c, err)
 		return
@@ -74,6 +90,7 @@ func (h *RoomHandler) GetRoom(c *gin.Context) {
 // @Produce json
 // @Param id path int true "Room ID"
 // @Success 200 {array} models.Equipment
+// @Security TelegramInitData
 // @Router /api/rooms/{id}/equipment [get]
 func (h *RoomHandler) GetRoomEquipment(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -82,15 +99,52 @@ func (h *RoomHandler) GetRoomEquipment(c *gin.Context) {
 		return
 	}
 
-	equipment, err := h.roomService.GetRoomEquipment(uint(id))
+	equipment, err := h.roomService.GetRoomEquipment(c.Request.Context(), uint(id))
 	if err != nil {
-		response.InternalServerError(c, err)
+		_ = c.Error(err)
 		return
 	}
 
 	response.Success(c, equipment)
 }
 
+// GetEquipmentSummaries returns the equipment of a room with the number of instructions of each
+// GET /api/bot/rooms/:id/equipment
+func (h *RoomHandler) GetEquipmentSummaries(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	equipment, err := h.roomService.GetEquipmentSummaries(c.Request.Context(), uint(id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Success(c, equipment)
+}
+
+// GetEquipmentGuide returns the instructions of equipment as Telegram HTML messages
+// GET /api/bot/equipment/:id/instructions
+// text - готовое сообщение целиком; instructions[].text - по инструкции на сообщение, если целиком не помещается
+func (h *RoomHandler) GetEquipmentGuide(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	guide, err := h.roomService.GetEquipmentGuide(c.Request.Context(), uint(id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Success(c, guide)
+}
+
 // CreateRoom godoc
 // @Summary Create a new room (admin only)
 // @Tags rooms
@@ -98,20 +152,21 @@ func (h *RoomHandler) GetRoomEquipment(c *gin.Context) {
 // @Produce json
 // @Param room body service.CreateRoomRequest true "Room data"
 // @Success 201 {object} models.Room
-// @Router /api/rooms [post]
+// @Security TelegramInitData
+// @Router /api/admin/rooms [post]
 func (h *RoomHandler) CreateRoom(c *gin.Context) {
 	var req service.CreateRoomRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, err)
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	room, err := h.roomService.CreateRoom(req)
+	room, err := h.roomService.CreateRoom(c.Request.Context(), req)
 	if err != nil {
-		response.InternalServerError(c, err)
+		_ = c.Error(err)
 		return
 	}
 
+	c.Set("auditEntityID", room.ID) // ID созданной сущности для журнала аудита
 	response.Created(c, room)
 }
 
@@ -123,7 +178,8 @@ func (h *RoomHandler) CreateRoom(c *gin.Context) {
 // @Param id path int true "Room ID"
 // @Param room body service.UpdateRoomRequest true "Room data"
 // @Success 200 {object} models.Room
-// @Router /api/rooms/{id} [patch]
+// @Security TelegramInitData
+// @Router /api/admin/rooms/{id} [patch]
 func (h *RoomHandler) UpdateRoom(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
@@ -132,14 +188,13 @@ func (h *RoomHandler) UpdateRoom(c *gin.Context) {
 	}
 
 	var req service.UpdateRoomRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, err)
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	room, err := h.roomService.UpdateRoom(uint(id), req)
+	room, err := h.roomService.UpdateRoom(c.Request.Context(), uint(id), req)
 	if err != nil {
-		response.InternalServerError(c, err)
+		_ = c.Error(err)
 		return
 	}
 
@@ -151,7 +206,8 @@ func (h *RoomHandler) UpdateRoom(c *gin.Context) {
 // @Tags rooms
 // @Param id path int true "Room ID"
 // @Success 204
-// @Router /api/rooms/{id} [delete]
+// @Security TelegramInitData
+// @Router /api/admin/rooms/{id} [delete]
 func (h *RoomHandler) DeleteRoom(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
@@ -159,9 +215,9 @@ func (h *RoomHandler) DeleteRoom(c *gin.Context) {
 		return
 	}
 
-	err = h.roomService.DeleteRoom(uint(id))
+	err = h.roomService.DeleteRoom(c.Request.Context(), uint(id))
 	if err != nil {
-		response.InternalServerError(c, err)
+		_ = c.Error(err)
 		return
 	}
 
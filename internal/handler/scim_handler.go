@@ -0,0 +1,367 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/service"
+	"gorm.io/gorm"
+)
+
+// scimContentType - тип ответов SCIM (RFC 7644, раздел 3.1)
+const scimContentType = "application/scim+json"
+
+// SCIMHandler handles SCIM 2.0 provisioning from an HR directory
+// Ответы и ошибки - в формате SCIM, а не {"data": ...}: их разбирают клиенты каталога (Okta, Entra ID)
+type SCIMHandler struct {
+	scimService *service.SCIMService
+}
+
+// NewSCIMHandler creates a new SCIM handler
+func NewSCIMHandler(scimService *service.SCIMService) *SCIMHandler {
+	return &SCIMHandler{scimService: scimService}
+}
+
+// SCIMError is a SCIM error response
+type SCIMError struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+}
+
+// ListUsers godoc
+// @Summary List directory users (SCIM)
+// @Description Only users with an email are visible. filter supports userName, emails.value and externalId with eq.
+// @Tags scim
+// @Produce json
+// @Param filter query string false "Filter, e.g. userName eq \"anna@example.com\""
+// @Param startIndex query int false "1-based index of the first result"
+// @Param count query int false "Page size (max 200)"
+// @Success 200 {object} service.SCIMListResponse
+// @Security APIKey
+// @Router /api/scim/v2/Users [get]
+func (h *SCIMHandler) ListUsers(c *gin.Context) {
+	startIndex, count := scimPageParams(c)
+	list, err := h.scimService.ListUsers(c.Request.Context(), c.Query("filter"), startIndex, count)
+	if err != nil {
+		scimFail(c, err)
+		return
+	}
+	scimJSON(c, http.StatusOK, list)
+}
+
+// GetUser godoc
+// @Summary Get a directory user (SCIM)
+// @Tags scim
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} service.SCIMUser
+// @Security APIKey
+// @Router /api/scim/v2/Users/{id} [get]
+func (h *SCIMHandler) GetUser(c *gin.Context) {
+	user, err := h.scimService.GetUser(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		scimFail(c, err)
+		return
+	}
+	scimJSON(c, http.StatusOK, user)
+}
+
+// CreateUser godoc
+// @Summary Provision a user (SCIM)
+// @Description userName must be the user's email; it links the account to OIDC login.
+// @Tags scim
+// @Accept json
+// @Produce json
+// @Param user body service.SCIMUser true "User"
+// @Success 201 {object} service.SCIMUser
+// @Failure 409 {object} SCIMError
+// @Security APIKey
+// @Router /api/scim/v2/Users [post]
+func (h *SCIMHandler) CreateUser(c *gin.Context) {
+	var in service.SCIMUser
+	if !bindSCIM(c, &in) {
+		return
+	}
+
+	user, err := h.scimService.CreateUser(c.Request.Context(), &in)
+	if err != nil {
+		scimFail(c, err)
+		return
+	}
+
+	c.Set("auditEntityID", user.ID) // ID созданной сущности для журнала аудита
+	scimJSON(c, http.StatusCreated, user)
+}
+
+// ReplaceUser godoc
+// @Summary Replace a user (SCIM)
+// @Description active=false deactivates the user and cancels their future bookings.
+// @Tags scim
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param user body service.SCIMUser true "User"
+// @Success 200 {object} service.SCIMUser
+// @Security APIKey
+// @Router /api/scim/v2/Users/{id} [put]
+func (h *SCIMHandler) ReplaceUser(c *gin.Context) {
+	var in service.SCIMUser
+	if !bindSCIM(c, &in) {
+		return
+	}
+
+	user, err := h.scimService.ReplaceUser(c.Request.Context(), c.Param("id"), &in)
+	if err != nil {
+		scimFail(c, err)
+		return
+	}
+	scimJSON(c, http.StatusOK, user)
+}
+
+// PatchUser godoc
+// @Summary Update a user (SCIM PATCH)
+// @Description active=false deactivates the user and cancels their future bookings.
+// @Tags scim
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param patch body service.SCIMPatchRequest true "Operations"
+// @Success 200 {object} service.SCIMUser
+// @Security APIKey
+// @Router /api/scim/v2/Users/{id} [patch]
+func (h *SCIMHandler) PatchUser(c *gin.Context) {
+	var req service.SCIMPatchRequest
+	if !bindSCIM(c, &req) {
+		return
+	}
+
+	user, err := h.scimService.PatchUser(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		scimFail(c, err)
+		return
+	}
+	scimJSON(c, http.StatusOK, user)
+}
+
+// DeleteUser godoc
+// @Summary Deprovision a user (SCIM)
+// @Description The user is deactivated, not deleted: booking history is kept, future bookings are cancelled.
+// @Tags scim
+// @Param id path string true "User ID"
+// @Success 204
+// @Security APIKey
+// @Router /api/scim/v2/Users/{id} [delete]
+func (h *SCIMHandler) DeleteUser(c *gin.Context) {
+	if err := h.scimService.DeleteUser(c.Request.Context(), c.Param("id")); err != nil {
+		scimFail(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListGroups godoc
+// @Summary List teams (SCIM)
+// @Description filter supports displayName and externalId with eq.
+// @Tags scim
+// @Produce json
+// @Param filter query string false "Filter, e.g. displayName eq \"Design\""
+// @Param startIndex query int false "1-based index of the first result"
+// @Param count query int false "Page size (max 200)"
+// @Success 200 {object} service.SCIMListResponse
+// @Security APIKey
+// @Router /api/scim/v2/Groups [get]
+func (h *SCIMHandler) ListGroups(c *gin.Context) {
+	startIndex, count := scimPageParams(c)
+	list, err := h.scimService.ListGroups(c.Request.Context(), c.Query("filter"), startIndex, count)
+	if err != nil {
+		scimFail(c, err)
+		return
+	}
+	scimJSON(c, http.StatusOK, list)
+}
+
+// GetGroup godoc
+// @Summary Get a team (SCIM)
+// @Tags scim
+// @Produce json
+// @Param id path string true "Team ID"
+// @Success 200 {object} service.SCIMGroup
+// @Security APIKey
+// @Router /api/scim/v2/Groups/{id} [get]
+func (h *SCIMHandler) GetGroup(c *gin.Context) {
+	group, err := h.scimService.GetGroup(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		scimFail(c, err)
+		return
+	}
+	scimJSON(c, http.StatusOK, group)
+}
+
+// CreateGroup godoc
+// @Summary Provision a team (SCIM)
+// @Tags scim
+// @Accept json
+// @Produce json
+// @Param group body service.SCIMGroup true "Team"
+// @Success 201 {object} service.SCIMGroup
+// @Security APIKey
+// @Router /api/scim/v2/Groups [post]
+func (h *SCIMHandler) CreateGroup(c *gin.Context) {
+	var in service.SCIMGroup
+	if !bindSCIM(c, &in) {
+		return
+	}
+
+	group, err := h.scimService.CreateGroup(c.Request.Context(), &in)
+	if err != nil {
+		scimFail(c, err)
+		return
+	}
+
+	c.Set("auditEntityID", group.ID) // ID созданной сущности для журнала аудита
+	scimJSON(c, http.StatusCreated, group)
+}
+
+// ReplaceGroup godoc
+// @Summary Replace a team (SCIM)
+// @Tags scim
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param group body service.SCIMGroup true "Team"
+// @Success 200 {object} service.SCIMGroup
+// @Security APIKey
+// @Router /api/scim/v2/Groups/{id} [put]
+func (h *SCIMHandler) ReplaceGroup(c *gin.Context) {
+	var in service.SCIMGroup
+	if !bindSCIM(c, &in) {
+		return
+	}
+
+	group, err := h.scimService.ReplaceGroup(c.Request.Context(), c.Param("id"), &in)
+	if err != nil {
+		scimFail(c, err)
+		return
+	}
+	scimJSON(c, http.StatusOK, group)
+}
+
+// PatchGroup godoc
+// @Summary Update a team (SCIM PATCH)
+// @Tags scim
+// @Accept json
+// @Produce json
+// @Param id path string true "Team ID"
+// @Param patch body service.SCIMPatchRequest true "Operations"
+// @Success 200 {object} service.SCIMGroup
+// @Security APIKey
+// @Router /api/scim/v2/Groups/{id} [patch]
+func (h *SCIMHandler) PatchGroup(c *gin.Context) {
+	var req service.SCIMPatchRequest
+	if !bindSCIM(c, &req) {
+		return
+	}
+
+	group, err := h.scimService.PatchGroup(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		scimFail(c, err)
+		return
+	}
+	scimJSON(c, http.StatusOK, group)
+}
+
+// DeleteGroup godoc
+// @Summary Delete a team (SCIM)
+// @Description Members are not affected.
+// @Tags scim
+// @Param id path string true "Team ID"
+// @Success 204
+// @Security APIKey
+// @Router /api/scim/v2/Groups/{id} [delete]
+func (h *SCIMHandler) DeleteGroup(c *gin.Context) {
+	if err := h.scimService.DeleteGroup(c.Request.Context(), c.Param("id")); err != nil {
+		scimFail(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ServiceProviderConfig godoc
+// @Summary SCIM capabilities
+// @Tags scim
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Security APIKey
+// @Router /api/scim/v2/ServiceProviderConfig [get]
+func (h *SCIMHandler) ServiceProviderConfig(c *gin.Context) {
+	unsupported := gin.H{"supported": false}
+	scimJSON(c, http.StatusOK, gin.H{
+		"schemas":        []string{"urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"},
+		"patch":          gin.H{"supported": true},
+		"bulk":           gin.H{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         gin.H{"supported": true, "maxResults": service.MaxSCIMPageSize},
+		"changePassword": unsupported,
+		"sort":           unsupported,
+		"etag":           unsupported,
+		"authenticationSchemes": []gin.H{{
+			"type":        "oauthbearertoken",
+			"name":        "API key",
+			"description": "API key with the provision:users scope in the Authorization: Bearer header",
+		}},
+	})
+}
+
+// scimJSON отправляет ответ с типом application/scim+json
+// SecurityHeaders заранее выставляет application/json, поэтому заголовок перезаписывается явно
+func scimJSON(c *gin.Context, status int, body interface{}) {
+	c.Header("Content-Type", scimContentType)
+	c.JSON(status, body)
+}
+
+// scimError отправляет ошибку в формате SCIM
+func scimError(c *gin.Context, status int, scimType, detail string) {
+	scimJSON(c, status, SCIMError{
+		Schemas:  []string{service.SCIMSchemaError},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	})
+	c.Abort()
+}
+
+// scimFail преобразует ошибку сервиса в ответ SCIM
+func scimFail(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		scimError(c, http.StatusNotFound, "", "resource not found")
+	case errors.Is(err, service.ErrSCIMInvalidFilter):
+		scimError(c, http.StatusBadRequest, "invalidFilter", err.Error())
+	case errors.Is(err, service.ErrSCIMInvalidValue):
+		scimError(c, http.StatusBadRequest, "invalidValue", err.Error())
+	case errors.Is(err, service.ErrSCIMUniqueness):
+		scimError(c, http.StatusConflict, "uniqueness", err.Error())
+	default:
+		requestLogger(c).Error("SCIM request failed", "error", err)
+		scimError(c, http.StatusInternalServerError, "", "internal server error")
+	}
+}
+
+// bindSCIM разбирает тело запроса; клиенты присылают application/scim+json, поэтому JSON разбирается явно
+func bindSCIM(c *gin.Context, obj interface{}) bool {
+	if err := c.ShouldBindJSON(obj); err != nil {
+		scimError(c, http.StatusBadRequest, "invalidSyntax", err.Error())
+		return false
+	}
+	return true
+}
+
+// scimPageParams читает startIndex и count; некорректные значения заменяются значениями по умолчанию
+func scimPageParams(c *gin.Context) (int, int) {
+	startIndex, _ := strconv.Atoi(c.Query("startIndex"))
+	count, _ := strconv.Atoi(c.Query("count"))
+	return startIndex, count
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/response"
+)
+
+// SlackHandler handles admin management of Slack notification targets
+type SlackHandler struct {
+	slackService *service.SlackService
+}
+
+// NewSlackHandler creates a new Slack handler
+func NewSlackHandler(slackService *service.SlackService) *SlackHandler {
+	return &SlackHandler{slackService: slackService}
+}
+
+// ListTargets godoc
+// @Summary List Slack notification targets (admin only)
+// @Description Webhook URLs and bot tokens are never returned
+// @Tags admin
+// @Produce json
+// @Success 200 {array} models.SlackTarget
+// @Security TelegramInitData
+// @Router /api/admin/slack-targets [get]
+func (h *SlackHandler) ListTargets(c *gin.Context) {
+	targets, err := h.slackService.ListTargets(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Success(c, targets)
+}
+
+// CreateTarget godoc
+// @Summary Add a Slack notification target (admin only)
+// @Description Either an incoming webhook URL or a bot token with a channel.
+// @Description Without room_id the target receives events of all rooms; without events - all of booking.created, booking.cancelled, booking.reminder, booking.released, booking.updated.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param target body service.CreateSlackTargetRequest true "Slack target"
+// @Success 201 {object} models.SlackTarget
+// @Security TelegramInitData
+// @Router /api/admin/slack-targets [post]
+func (h *SlackHandler) CreateTarget(c *gin.Context) {
+	var req service.CreateSlackTargetRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		response.Unauthorized(c, service.ErrNotAuthorized)
+		return
+	}
+
+	target, err := h.slackService.CreateTarget(c.Request.Context(), userID.(uint), req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Set("auditEntityID", target.ID) // ID созданной сущности для журнала аудита
+	response.Created(c, target)
+}
+
+// DeleteTarget godoc
+// @Summary Remove a Slack notification target (admin only)
+// @Tags admin
+// @Param id path int true "Slack target ID"
+// @Success 204
+// @Security TelegramInitData
+// @Router /api/admin/slack-targets/{id} [delete]
+func (h *SlackHandler) DeleteTarget(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if err := h.slackService.DeleteTarget(c.Request.Context(), uint(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.NoContent(c)
+}
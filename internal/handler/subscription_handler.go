@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/response"
+)
+
+// SubscriptionHandler handles room notification subscriptions of the current user from the Mini App
+type SubscriptionHandler struct {
+	notificationService *service.NotificationService
+}
+
+// NewSubscriptionHandler creates a new subscription handler
+func NewSubscriptionHandler(notificationService *service.NotificationService) *SubscriptionHandler {
+	return &SubscriptionHandler{notificationService: notificationService}
+}
+
+// Subscribe godoc
+// @Summary Subscribe to room notifications
+// @Description The bot notifies the user about new bookings of the room. Subscribing again does nothing
+// @Tags rooms
+// @Param id path int true "Room ID"
+// @Success 204
+// @Security TelegramInitData
+// @Router /api/rooms/{id}/subscribe [post]
+func (h *SubscriptionHandler) Subscribe(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	err = h.notificationService.Subscribe(c.Request.Context(), c.GetUint("userID"), uint(id))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	requestLogger(c).Info("user subscribed to room", "room_id", id)
+	response.NoContent(c)
+}
+
+// Unsubscribe godoc
+// @Summary Unsubscribe from room notifications
+// @Tags rooms
+// @Param id path int true "Room ID"
+// @Success 204
+// @Security TelegramInitData
+// @Router /api/rooms/{id}/subscribe [delete]
+func (h *SubscriptionHandler) Unsubscribe(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if err := h.notificationService.Unsubscribe(c.Request.Context(), c.GetUint("userID"), uint(id)); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	requestLogger(c).Info("user unsubscribed from room", "room_id", id)
+	response.NoContent(c)
+}
+
+// GetMySubscriptions godoc
+// @Summary Get my room subscriptions
+// @Tags users
+// @Produce json
+// @Success 200 {array} models.NotificationSubscription
+// @Security TelegramInitData
+// @Router /api/users/me/subscriptions [get]
+func (h *SubscriptionHandler) GetMySubscriptions(c *gin.Context) {
+	subscriptions, err := h.notificationService.GetUserSubscriptions(c.Request.Context(), c.GetUint("userID"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Success(c, subscriptions)
+}
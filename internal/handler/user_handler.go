@@ -26,6 +26,7 @@ func NewUserHandler(userService *service.UserService) *UserHandler {
 // @Tags users
 // @Produce json
 // @Success 200 {object} models.User
+// @Security TelegramInitData
 // @Router /api/users/me [get]
 func (h *UserHandler) GetProfile(c *gin.Context) {
 	userID, exists := c.Get("userID")
@@ -34,7 +35,7 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userService.GetUser(userID.(uint))
+	user, err := h.userService.GetUser(c.Request.Context(), userID.(uint))
 	if err != nil {
 		response.NotFound(c, err)
 		return
@@ -50,6 +51,7 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 // @Produce json
 // @Param profile body service.UpdateProfileRequest true "Profile data"
 // @Success 200 {object} models.User
+// @Security TelegramInitData
 // @Router /api/users/me [patch]
 func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	userID, exists := c.Get("userID")
@@ -59,14 +61,13 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	}
 
 	var req service.UpdateProfileRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, err)
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	user, err := h.userService.UpdateProfile(userID.(uint), req)
+	user, err := h.userService.UpdateProfile(c.Request.Context(), userID.(uint), req)
 	if err != nil {
-		response.InternalServerError(c, err)
+		_ = c.Error(err)
 		return
 	}
 
@@ -78,18 +79,34 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 // @Tags users
 // @Produce json
 // @Param q query string false "Search query"
-// @Success 200 {array} models.User
+// @Param page query int false "Page number, starting from 1"
+// @Param per_page query int false "Page size (default 500, max 500)"
+// @Param cursor query string false "Cursor from meta.next_cursor"
+// @Param sort query string false "Sort fields, '-' for descending: first_name, last_name, username"
+// @Success 200 {object} response.PaginatedResponse{data=[]models.User}
+// @Security TelegramInitData
 // @Router /api/users/phonebook [get]
 func (h *UserHandler) GetPhonebook(c *gin.Context) {
 	query := c.Query("q")
 
-	users, err := h.userService.SearchPhonebook(query)
+	page, err := response.ParsePage(c, service.DefaultPhonebookPageSize, service.MaxPhonebookPageSize)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+	order, err := response.ParseSort(c, service.PhonebookSortColumns)
 	if err != nil {
-		response.InternalServerError(c, err)
+		response.BadRequest(c, err)
 		return
 	}
 
-	response.Success(c, users)
+	users, total, err := h.userService.SearchPhonebook(c.Request.Context(), query, page.Limit, page.Offset, order)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Paginated(c, users, page.Meta(total))
 }
 
 // SyncFromTelegram godoc
@@ -99,6 +116,7 @@ func (h *UserHandler) GetPhonebook(c *gin.Context) {
 // @Tags users
 // @Produce json
 // @Success 200 {object} models.User
+// @Security TelegramInitData
 // @Router /api/users/me/sync-telegram [post]
 func (h *UserHandler) SyncFromTelegram(c *gin.Context) {
 	// Получаем текущего пользователя из контекста
@@ -122,6 +140,7 @@ func (h *UserHandler) SyncFromTelegram(c *gin.Context) {
 
 	// Синхронизируем данные из Telegram
 	updatedUser, err := h.userService.SyncUserFromTelegram(
+		c.Request.Context(),
 		telegramUser.ID,
 		telegramUser.Username,
 		telegramUser.FirstName,
@@ -129,7 +148,7 @@ func (h *UserHandler) SyncFromTelegram(c *gin.Context) {
 		telegramUser.LanguageCode,
 	)
 	if err != nil {
-		response.InternalServerError(c, err)
+		_ = c.Error(err)
 		return
 	}
 
@@ -148,6 +167,7 @@ func (h *UserHandler) SyncFromTelegram(c *gin.Context) {
 // @Produce json
 // @Param id path int true "User ID"
 // @Success 200 {object} models.User
+// @Security TelegramInitData
 // @Router /api/users/{id} [get]
 func (h *UserHandler) GetUserByID(c *gin.Context) {
 	userIDParam := c.Param("id")
@@ -159,7 +179,7 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userService.GetUser(userID)
+	user, err := h.userService.GetUser(c.Request.Context(), userID)
 	if err != nil {
 		response.NotFound(c, err)
 		return
@@ -176,6 +196,7 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 // @Param id path int true "User ID"
 // @Param profile body service.UpdateProfileRequest true "Profile data"
 // @Success 200 {object} models.User
+// @Security TelegramInitData
 // @Router /api/users/{id} [patch]
 func (h *UserHandler) UpdateUserByID(c *gin.Context) {
 	userIDParam := c.Param("id")
@@ -202,14 +223,13 @@ func (h *UserHandler) UpdateUserByID(c *gin.Context) {
 	}
 
 	var req service.UpdateProfileRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, err)
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	user, err := h.userService.UpdateProfile(targetUserID, req)
+	user, err := h.userService.UpdateProfile(c.Request.Context(), targetUserID, req)
 	if err != nil {
-		response.InternalServerError(c, err)
+		_ = c.Error(err)
 		return
 	}
 
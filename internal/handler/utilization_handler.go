@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/response"
+	"github.com/space/backend/pkg/utils"
+)
+
+// UtilizationHandler handles the room utilization report
+type UtilizationHandler struct {
+	utilizationService *service.UtilizationService
+}
+
+// NewUtilizationHandler creates a new utilization handler
+func NewUtilizationHandler(utilizationService *service.UtilizationService) *UtilizationHandler {
+	return &UtilizationHandler{utilizationService: utilizationService}
+}
+
+// GetRoomUtilization godoc
+// @Summary Room utilization report (admin only)
+// @Description Estimated vs actual participants of finished bookings per room, least filled first.
+// @Description Actual attendance comes from check-ins or joined participants (ATTENDANCE_SOURCE);
+// @Description smaller_room is a smaller room that fits every meeting of the period, for rooms filled no more than RIGHTSIZE_MAX_FILL_PERCENT
+// @Tags admin
+// @Produce json
+// @Param since query string false "Bookings started since (RFC3339, default: 90 days ago)"
+// @Success 200 {array} service.RoomUtilization
+// @Security TelegramInitData
+// @Router /api/admin/room-utilization [get]
+func (h *UtilizationHandler) GetRoomUtilization(c *gin.Context) {
+	since := time.Now().Add(-defaultRatingReportPeriod)
+	if value := c.Query("since"); value != "" {
+		parsed, err := utils.ParseFlexibleTime(value)
+		if err != nil {
+			response.BadRequest(c, err)
+			return
+		}
+		since = parsed
+	}
+
+	report, err := h.utilizationService.RoomReport(c.Request.Context(), since)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.Success(c, report)
+}
+
+// SuggestRoom godoc
+// @Summary Suggest the smallest free room for a meeting
+// @Description Picks the smallest room that is free for the period and fits the expected number of participants.
+// @Description expected_participants is participants reduced by the share of people who actually came to past meetings;
+// @Description room is null if no free room is large enough
+// @Tags rooms
+// @Produce json
+// @Param start query string true "Start time (RFC3339)"
+// @Param end query string true "End time (RFC3339)"
+// @Param participants query int true "Estimated participants"
+// @Success 200 {object} service.RoomSuggestion
+// @Security TelegramInitData
+// @Router /api/rooms/suggest [get]
+func (h *UtilizationHandler) SuggestRoom(c *gin.Context) {
+	startStr := c.Query("start")
+	endStr := c.Query("end")
+	if startStr == "" || endStr == "" {
+		response.BadRequest(c, service.ErrInvalidTime)
+		return
+	}
+	start, err := utils.ParseFlexibleTime(startStr)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+	end, err := utils.ParseFlexibleTime(endStr)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+	participants, err := strconv.Atoi(c.Query("participants"))
+	if err != nil {
+		response.BadRequest(c, service.ErrInvalidParticipants)
+		return
+	}
+
+	suggestion, err := h.utilizationService.SuggestRoom(c.Request.Context(), start, end, participants)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	response.Success(c, suggestion)
+}
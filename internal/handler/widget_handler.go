@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/response"
+	"github.com/space/backend/pkg/utils"
+)
+
+// WidgetHandler handles the embeddable availability widget
+type WidgetHandler struct {
+	widgetService *service.WidgetService
+}
+
+// NewWidgetHandler creates a new widget handler
+func NewWidgetHandler(widgetService *service.WidgetService) *WidgetHandler {
+	return &WidgetHandler{widgetService: widgetService}
+}
+
+// GetCalendar godoc
+// @Summary Room availability for the public website widget
+// @Description Active rooms and merged busy intervals; booking titles and people are never included.
+// @Description Protected by WIDGET_TOKEN; CORS is allowed only for WIDGET_ORIGIN. Without start and end returns the next 7 days.
+// @Tags widget
+// @Produce json
+// @Param token query string true "Widget token"
+// @Param start query string false "Start date (RFC3339)"
+// @Param end query string false "End date (RFC3339), at most 31 days after start"
+// @Success 200 {object} service.WidgetCalendar
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/widget/calendar [get]
+func (h *WidgetHandler) GetCalendar(c *gin.Context) {
+	startStr := c.Query("start")
+	endStr := c.Query("end")
+
+	start := time.Now()
+	end := start.Add(service.DefaultWidgetRange)
+	if startStr != "" || endStr != "" {
+		if startStr == "" || endStr == "" {
+			response.BadRequest(c, service.ErrInvalidTime)
+			return
+		}
+		var err error
+		if start, err = utils.ParseFlexibleTime(startStr); err != nil {
+			response.BadRequest(c, err)
+			return
+		}
+		if end, err = utils.ParseFlexibleTime(endStr); err != nil {
+			response.BadRequest(c, err)
+			return
+		}
+	}
+
+	calendar, err := h.widgetService.GetCalendar(c.Request.Context(), start, end)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response.Success(c, calendar)
+}
@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+type contextKey struct{}
+
+// New создаёт структурированный логгер
+// format: "json" или "text" (пусто - json в production, text в остальных окружениях)
+// level: debug, info, warn, error (пусто - info)
+func New(environment, format, level string) *slog.Logger {
+	return NewWithWriter(os.Stdout, environment, format, level)
+}
+
+// NewWithWriter создаёт логгер, пишущий в указанный writer
+func NewWithWriter(w io.Writer, environment, format, level string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: ParseLevel(level)}
+
+	if format == "" {
+		format = "text"
+		if environment == "production" {
+			format = "json"
+		}
+	}
+
+	var handler slog.Handler
+	if strings.EqualFold(format, "json") {
+		handler = slog.NewJSONHandler(w, opts)
+	} else {
+		handler = slog.NewTextHandler(w, opts)
+	}
+
+	return slog.New(handler)
+}
+
+// ParseLevel парсит уровень логирования, по умолчанию info
+func ParseLevel(level string) slog.Level {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// WithContext сохраняет логгер в контекст (используется для per-request полей)
+func WithContext(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext возвращает логгер из контекста или глобальный логгер
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(contextKey{}).(*slog.Logger); ok && l != nil {
+			return l
+		}
+	}
+	return slog.Default()
+}
@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"expvar"
+	"net/http"
+)
+
+// Счётчики процесса публикуются через expvar (JSON на /api/admin/metrics)
+// expvar сам добавляет memstats и cmdline, так что отдельная библиотека метрик не нужна
+
+var (
+	// DBSlowQueries - количество SQL-запросов дольше порога DB_SLOW_QUERY_THRESHOLD
+	DBSlowQueries = expvar.NewInt("db_slow_queries_total")
+
+	// DBCancelledQueries - SQL-запросы, прерванные отменой контекста запроса или statement_timeout
+	DBCancelledQueries = expvar.NewInt("db_cancelled_queries_total")
+
+	// SchedulerJobRuns и SchedulerJobFailures - запуски и ошибки фоновых задач по имени задачи
+	SchedulerJobRuns     = expvar.NewMap("scheduler_job_runs_total")
+	SchedulerJobFailures = expvar.NewMap("scheduler_job_failures_total")
+
+	// WorkerPoolDropped - задачи, отброшенные из-за переполненной очереди, по имени пула
+	WorkerPoolDropped = expvar.NewMap("worker_pool_dropped_total")
+
+	// RoomCacheHits и RoomCacheMisses - обращения к кэшу списков комнат
+	RoomCacheHits   = expvar.NewInt("room_cache_hits_total")
+	RoomCacheMisses = expvar.NewInt("room_cache_misses_total")
+)
+
+// Handler returns an HTTP handler that serves all published metrics as JSON
+func Handler() http.Handler {
+	return expvar.Handler()
+}
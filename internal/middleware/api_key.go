@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/response"
+)
+
+var (
+	ErrMissingAPIKey     = errors.New("missing X-API-Key header")
+	ErrInsufficientScope = errors.New("API key does not have the required scope")
+)
+
+// APIKeyAuthMiddleware validates third-party API keys
+// Ключ передаётся в заголовке X-API-Key или Authorization: Bearer <key>
+func APIKeyAuthMiddleware(apiKeyService *service.APIKeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("X-API-Key")
+		if key == "" {
+			if auth := c.GetHeader("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+				key = strings.TrimPrefix(auth, "Bearer ")
+			}
+		}
+		if key == "" {
+			response.Unauthorized(c, ErrMissingAPIKey)
+			c.Abort()
+			return
+		}
+
+		apiKey, err := apiKeyService.Authenticate(c.Request.Context(), key)
+		if err != nil {
+			requestLogger(c).Warn("API key authentication failed", "client_ip", c.ClientIP(), "error", err)
+			if errors.Is(err, service.ErrInvalidAPIKey) || errors.Is(err, service.ErrAPIKeyExpired) {
+				response.Unauthorized(c, err)
+			} else {
+				response.InternalServerError(c, err)
+			}
+			c.Abort()
+			return
+		}
+
+		// Действия по ключу выполняются от имени создавшего его администратора
+		c.Set("apiKey", apiKey)
+		c.Set("userID", apiKey.CreatedByID)
+
+		requestLogger(c).Debug("API key authenticated", "api_key_id", apiKey.ID)
+		c.Next()
+	}
+}
+
+// RequireScope проверяет, что API ключ имеет нужный scope
+// Этот middleware должен использоваться после APIKeyAuthMiddleware
+func RequireScope(scope models.APIKeyScope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		keyInterface, exists := c.Get("apiKey")
+		if !exists {
+			response.Unauthorized(c, ErrMissingAPIKey)
+			c.Abort()
+			return
+		}
+
+		apiKey, ok := keyInterface.(*models.APIKey)
+		if !ok {
+			response.InternalServerError(c, errors.New("invalid API key data type"))
+			c.Abort()
+			return
+		}
+
+		if !apiKey.HasScope(scope) {
+			requestLogger(c).Info("API key scope denied", "api_key_id", apiKey.ID, "scope", scope)
+			response.Forbidden(c, ErrInsufficientScope)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/internal/service"
+)
+
+// auditEntityKey - ключ контекста, через который обработчик может передать ID
+// созданной сущности (у POST-запросов его нет в пути): c.Set("auditEntityID", id)
+const auditEntityKey = "auditEntityID"
+
+// Audit записывает в журнал аудита все изменяющие запросы (POST/PUT/PATCH/DELETE)
+// Подключается глобально: актор определяется после c.Next(), когда auth middleware
+// уже положили пользователя или API ключ в контекст
+func Audit(auditService *service.AuditService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if !isStateChanging(c.Request.Method) {
+			return
+		}
+
+		// Несуществующие маршруты не аудируем
+		route := c.FullPath()
+		if route == "" {
+			return
+		}
+
+		entry := &models.AuditLog{
+			ActorType: models.AuditActorAnonymous,
+			Method:    c.Request.Method,
+			Route:     route,
+			Path:      c.Request.URL.Path,
+			EntityID:  auditEntityID(c),
+			Status:    c.Writer.Status(),
+			ClientIP:  c.ClientIP(),
+			RequestID: c.GetString("requestID"),
+		}
+
+		if userID, ok := c.Get("userID"); ok {
+			if id, ok := userID.(uint); ok {
+				entry.ActorID = &id
+				entry.ActorType = models.AuditActorUser
+			}
+		}
+		if c.GetBool("isBot") {
+			entry.ActorType = models.AuditActorBot
+		}
+		if apiKey, ok := c.Get("apiKey"); ok {
+			if key, ok := apiKey.(*models.APIKey); ok {
+				entry.APIKeyID = &key.ID
+				entry.ActorType = models.AuditActorAPIKey
+			}
+		}
+
+		// Запись аудита не должна пропадать из-за таймаута или отмены самого запроса
+		auditService.Record(context.WithoutCancel(c.Request.Context()), entry)
+	}
+}
+
+// auditEntityID возвращает ID сущности из контекста или параметров маршрута
+func auditEntityID(c *gin.Context) string {
+	if id, ok := c.Get(auditEntityKey); ok {
+		return fmt.Sprint(id)
+	}
+	if id := c.Param("id"); id != "" {
+		return id
+	}
+	return c.Param("telegram_id")
+}
+
+// isStateChanging проверяет, изменяет ли метод состояние
+func isStateChanging(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
@@ -1,15 +1,17 @@
 package middleware
 
 import (
+	"context"
 	"errors"
-	"log"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/space/backend/internal/models"
 	"github.com/space/backend/internal/service"
 	"github.com/space/backend/pkg/response"
+	"github.com/space/backend/pkg/session"
 	"github.com/space/backend/pkg/telegram"
 )
 
@@ -20,13 +22,43 @@ var (
 	ErrInvalidBotToken   = errors.New("invalid bot API token")
 	ErrMissingBotToken   = errors.New("missing X-Bot-Token header")
 	ErrMissingTelegramID = errors.New("missing X-Telegram-User-ID header")
+	ErrUserDeactivated   = errors.New("user account is deactivated")
 )
 
+// SessionAuthenticator resolves a session token issued after OIDC login
+type SessionAuthenticator interface {
+	AuthenticateSession(ctx context.Context, token string) (*models.User, error)
+}
+
 // TelegramAuthMiddleware validates Telegram Mini App authentication
-func TelegramAuthMiddleware(botToken string, userService *service.UserService, ttlMiniApp int64, ttlLoginWidget int64) gin.HandlerFunc {
+// Во время ротации bot token initData, подписанные предыдущим токеном, тоже принимаются
+// Без initData принимается сессия OIDC (Authorization: Bearer), если sessions задан
+func TelegramAuthMiddleware(botTokens TokenSet, userService *service.UserService, sessions SessionAuthenticator, ttlMiniApp int64, ttlLoginWidget int64) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Получаем initData из заголовка
 		initData := c.GetHeader("X-Telegram-Init-Data")
+		if token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); initData == "" && ok && sessions != nil {
+			user, err := sessions.AuthenticateSession(c.Request.Context(), token)
+			if err != nil {
+				requestLogger(c).Warn("session validation failed", "error", err)
+				if errors.Is(err, session.ErrTokenExpired) {
+					response.UnauthorizedWithCode(c, err, "AUTH_EXPIRED")
+				} else if errors.Is(err, session.ErrInvalidToken) {
+					response.Unauthorized(c, err)
+				} else {
+					response.InternalServerError(c, err)
+				}
+				c.Abort()
+				return
+			}
+
+			if !allowActiveUser(c, user) {
+				return
+			}
+			setAuthenticatedUser(c, user)
+			c.Next()
+			return
+		}
 		if initData == "" {
 			response.Unauthorized(c, ErrMissingAuthHeader)
 			c.Abort()
@@ -36,7 +68,7 @@ func TelegramAuthMiddleware(botToken string, userService *service.UserService, t
 		// Development mode - пропускаем валидацию
 		if initData == "dev_mode" {
 			// Создаем тестового пользователя для разработки
-			user, err := userService.SyncTelegramUser(12345, "devuser", "Dev", "User", "en")
+			user, err := userService.SyncTelegramUser(c.Request.Context(), 12345, "devuser", "Dev", "User", "en")
 			if err != nil {
 				response.InternalServerError(c, err)
 				c.Abort()
@@ -44,33 +76,50 @@ func TelegramAuthMiddleware(botToken string, userService *service.UserService, t
 			}
 
 			// Сохраняем пользователя в контекст
-			c.Set("userID", user.ID)
-			c.Set("user", user)
+			setAuthenticatedUser(c, user)
 			c.Next()
 			return
 		}
 
 		// Production mode - определяем тип авторизации и валидируем
 		authType := telegram.DetectAuthType(initData)
-		var telegramUser *telegram.TelegramUser
-		var err error
+		var validate func(initData string, botToken string) (*telegram.TelegramUser, error)
 
 		switch authType {
 		case "miniapp":
 			// Telegram Mini App
-			telegramUser, err = telegram.ValidateAndParseInitData(initData, botToken, ttlMiniApp)
+			validate = func(initData string, botToken string) (*telegram.TelegramUser, error) {
+				return telegram.ValidateAndParseInitData(initData, botToken, ttlMiniApp)
+			}
 		case "loginwidget":
 			// Telegram Login Widget (веб-авторизация)
-			telegramUser, err = telegram.ValidateAndParseLoginWidget(initData, botToken, ttlLoginWidget)
+			validate = func(initData string, botToken string) (*telegram.TelegramUser, error) {
+				return telegram.ValidateAndParseLoginWidget(initData, botToken, ttlLoginWidget)
+			}
 		default:
-			log.Printf("Unknown auth type for initData: %s", initData)
+			requestLogger(c).Warn("unknown auth type in initData")
 			response.Unauthorized(c, errors.New("unknown auth type"))
 			c.Abort()
 			return
 		}
 
+		// Пробуем текущий токен, затем предыдущий (если идёт grace-период ротации)
+		var telegramUser *telegram.TelegramUser
+		var err error
+		for i, botToken := range botTokens.Active() {
+			telegramUser, err = validate(initData, botToken)
+			if err == nil && i > 0 {
+				requestLogger(c).Warn("auth validated with previous bot token",
+					"auth_type", authType, "telegram_id", telegramUser.ID, "grace_until", botTokens.GraceUntil)
+			}
+			// Другой токен может помочь только при несовпадении подписи
+			if !errors.Is(err, telegram.ErrInvalidHash) {
+				break
+			}
+		}
+
 		if err != nil {
-			log.Printf("Auth validation error (%s): %v", authType, err)
+			requestLogger(c).Warn("auth validation failed", "auth_type", authType, "error", err)
 
 			// Для ошибок истекшей авторизации используем специальный код
 			if errors.Is(err, telegram.ErrAuthDateExpired) {
@@ -83,10 +132,11 @@ func TelegramAuthMiddleware(botToken string, userService *service.UserService, t
 			return
 		}
 
-		log.Printf("Successful %s authentication for user %d", authType, telegramUser.ID)
+		requestLogger(c).Debug("authentication succeeded", "auth_type", authType, "telegram_id", telegramUser.ID)
 
 		// Получаем или создаем пользователя с полными данными из Telegram
 		user, err := userService.SyncTelegramUser(
+			c.Request.Context(),
 			telegramUser.ID,
 			telegramUser.Username,
 			telegramUser.FirstName,
@@ -99,9 +149,12 @@ func TelegramAuthMiddleware(botToken string, userService *service.UserService, t
 			return
 		}
 
+		if !allowActiveUser(c, user) {
+			return
+		}
+
 		// Сохраняем пользователя и данные из Telegram в контекст
-		c.Set("userID", user.ID)
-		c.Set("user", user)
+		setAuthenticatedUser(c, user)
 		c.Set("telegramUser", telegramUser) // Для возможности синхронизации
 
 		c.Next()
@@ -109,12 +162,16 @@ func TelegramAuthMiddleware(botToken string, userService *service.UserService, t
 }
 
 // RequireChatMembership проверяет, что пользователь является участником разрешенной группы
-func RequireChatMembership(botToken string, allowedChatID int64, environment string) gin.HandlerFunc {
+// membershipTTL - время жизни результата проверки в кэше (MEMBERSHIP_CACHE_TTL)
+func RequireChatMembership(botToken string, allowedChatID func() int64, environment string, membershipTTL time.Duration) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// Значение читается на каждый запрос - ALLOWED_CHAT_ID перезагружается без рестарта
+		chatID := allowedChatID()
+
 		// В development режиме без настроенного ALLOWED_CHAT_ID пропускаем проверку
-		if allowedChatID == 0 {
+		if chatID == 0 {
 			if environment != "production" {
-				log.Println("WARNING: ALLOWED_CHAT_ID not set, skipping membership check in development mode")
+				requestLogger(c).Warn("ALLOWED_CHAT_ID not set, skipping membership check in development mode")
 				c.Next()
 				return
 			}
@@ -142,10 +199,16 @@ func RequireChatMembership(botToken string, allowedChatID int64, environment str
 
 		telegramUserID := userModel.TelegramID
 
+		// Пользователь без Telegram вошёл через OIDC: доступ уже ограничен OIDC_ALLOWED_DOMAINS
+		if telegramUserID == 0 {
+			c.Next()
+			return
+		}
+
 		// Проверяем кэш сначала (TTL 5 минут)
 		if isMember, cached := telegram.GlobalCache.Get(telegramUserID); cached {
 			if !isMember {
-				log.Printf("INFO: User %d denied access - not a group member (cached)", telegramUserID)
+				requestLogger(c).Info("access denied: not a group member", "telegram_id", telegramUserID, "cached", true)
 				response.Forbidden(c, errors.New("access denied. You must be a member of the authorized group"))
 				c.Abort()
 				return
@@ -156,9 +219,9 @@ func RequireChatMembership(botToken string, allowedChatID int64, environment str
 		}
 
 		// Проверяем членство через API
-		isMember, err := telegram.CheckUserInChat(telegramUserID, allowedChatID, botToken)
+		isMember, err := telegram.CheckUserInChat(c.Request.Context(), telegramUserID, chatID, botToken)
 		if err != nil {
-			log.Printf("ERROR: Failed to check membership for user %d: %v", telegramUserID, err)
+			requestLogger(c).Error("membership check failed", "telegram_id", telegramUserID, "error", err)
 
 			// В production блокируем при ошибке проверки
 			if environment == "production" {
@@ -167,22 +230,22 @@ func RequireChatMembership(botToken string, allowedChatID int64, environment str
 				return
 			}
 			// В dev разрешаем
-			log.Println("WARNING: Membership check failed in development mode, allowing access")
+			requestLogger(c).Warn("membership check failed in development mode, allowing access")
 			c.Next()
 			return
 		}
 
 		// Сохраняем результат в кэш (5 минут)
-		telegram.GlobalCache.Set(telegramUserID, isMember, 5*time.Minute)
+		telegram.GlobalCache.Set(telegramUserID, isMember, membershipTTL)
 
 		if !isMember {
-			log.Printf("INFO: User %d denied access - not a group member", telegramUserID)
+			requestLogger(c).Info("access denied: not a group member", "telegram_id", telegramUserID, "cached", false)
 			response.Forbidden(c, errors.New("access denied. You must be a member of the authorized group"))
 			c.Abort()
 			return
 		}
 
-		log.Printf("INFO: User %d authorized - group member", telegramUserID)
+		requestLogger(c).Debug("group membership confirmed", "telegram_id", telegramUserID)
 		c.Next()
 	}
 }
@@ -209,13 +272,13 @@ func RequireAdmin() gin.HandlerFunc {
 
 		// Проверяем, является ли пользователь администратором
 		if !user.IsAdmin() {
-			log.Printf("INFO: User %d (TelegramID: %d) denied admin access - not an admin (role: %s)", user.ID, user.TelegramID, user.Role)
+			requestLogger(c).Info("admin access denied", "telegram_id", user.TelegramID, "role", user.Role)
 			response.Forbidden(c, ErrNotAdmin)
 			c.Abort()
 			return
 		}
 
-		log.Printf("INFO: User %d (TelegramID: %d) granted admin access", user.ID, user.TelegramID)
+		requestLogger(c).Debug("admin access granted", "telegram_id", user.TelegramID)
 		c.Next()
 	}
 }
@@ -225,70 +288,76 @@ func RequireAdmin() gin.HandlerFunc {
 // - X-Bot-Token: секретный токен для авторизации бота
 // - X-Telegram-User-ID: ID пользователя Telegram от имени которого выполняется действие
 // - X-Telegram-Username, X-Telegram-First-Name, X-Telegram-Last-Name (опционально)
-func BotAuthMiddleware(botAPIToken string, botToken string, allowedChatID int64, environment string, userService *service.UserService) gin.HandlerFunc {
+// Во время ротации BOT_API_TOKEN предыдущий токен принимается до окончания grace-периода
+func BotAuthMiddleware(botAPITokens TokenSet, botToken string, allowedChatID func() int64, environment string, membershipTTL time.Duration, userService *service.UserService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Проверяем наличие токена бота
 		providedToken := c.GetHeader("X-Bot-Token")
 		if providedToken == "" {
-			log.Println("ERROR: Bot request missing X-Bot-Token header")
+			requestLogger(c).Warn("bot request missing X-Bot-Token header")
 			response.Unauthorized(c, ErrMissingBotToken)
 			c.Abort()
 			return
 		}
 
-		// Проверяем валидность токена
-		if providedToken != botAPIToken {
-			log.Printf("ERROR: Invalid bot token provided: %s... (expected: %s...)", providedToken[:10], botAPIToken[:10])
+		// Проверяем валидность токена (текущий или предыдущий в grace-период)
+		valid, isPrevious := botAPITokens.Match(providedToken)
+		if !valid {
+			requestLogger(c).Warn("invalid bot token provided", "client_ip", c.ClientIP())
 			response.Unauthorized(c, ErrInvalidBotToken)
 			c.Abort()
 			return
 		}
+		if isPrevious {
+			requestLogger(c).Warn("bot authenticated with previous BOT_API_TOKEN", "grace_until", botAPITokens.GraceUntil)
+		}
 
 		// Получаем Telegram User ID
 		telegramUserIDStr := c.GetHeader("X-Telegram-User-ID")
 		if telegramUserIDStr == "" {
-			log.Println("ERROR: Bot request missing X-Telegram-User-ID header")
+			requestLogger(c).Warn("bot request missing X-Telegram-User-ID header")
 			response.BadRequest(c, ErrMissingTelegramID)
 			c.Abort()
 			return
 		}
 
 		telegramUserID, err := strconv.ParseInt(telegramUserIDStr, 10, 64)
-		if err != nil {
-			log.Printf("ERROR: Invalid X-Telegram-User-ID format: %s", telegramUserIDStr)
+		if err != nil || telegramUserID <= 0 {
+			requestLogger(c).Warn("invalid X-Telegram-User-ID format", "value", telegramUserIDStr)
 			response.BadRequest(c, errors.New("invalid X-Telegram-User-ID format"))
 			c.Abort()
 			return
 		}
 
-		log.Printf("INFO: Bot authenticated, acting on behalf of Telegram user %d", telegramUserID)
+		requestLogger(c).Debug("bot authenticated", "telegram_id", telegramUserID)
 
 		// Проверяем членство в группе (если настроено)
-		if allowedChatID != 0 {
+		chatID := allowedChatID()
+		if chatID != 0 {
 			// Проверяем кэш сначала
 			if isMember, cached := telegram.GlobalCache.Get(telegramUserID); cached {
 				if !isMember {
-					log.Printf("INFO: Bot request denied - user %d not a group member (cached)", telegramUserID)
+					requestLogger(c).Info("bot request denied: not a group member", "telegram_id", telegramUserID, "cached", true)
 					response.Forbidden(c, errors.New("user is not a member of the authorized group"))
 					c.Abort()
 					return
 				}
 			} else {
 				// Проверяем членство через API
-				isMember, err := telegram.CheckUserInChat(telegramUserID, allowedChatID, botToken)
+				isMember, err := telegram.CheckUserInChat(c.Request.Context(), telegramUserID, chatID, botToken)
 				if err != nil {
-					log.Printf("ERROR: Failed to check membership for user %d: %v", telegramUserID, err)
+					requestLogger(c).Error("membership check failed", "telegram_id", telegramUserID, "error", err)
 					if environment == "production" {
 						response.InternalServerError(c, errors.New("failed to verify membership"))
 						c.Abort()
 						return
 					}
-					log.Println("WARNING: Membership check failed in development mode, allowing access")
+					requestLogger(c).Warn("membership check failed in development mode, allowing access")
 				} else {
 					// Сохраняем результат в кэш
-					telegram.GlobalCache.Set(telegramUserID, isMember, 5*time.Minute)
+					telegram.GlobalCache.Set(telegramUserID, isMember, membershipTTL)
 					if !isMember {
-						log.Printf("INFO: Bot request denied - user %d not a group member", telegramUserID)
+						requestLogger(c).Info("bot request denied: not a group member", "telegram_id", telegramUserID, "cached", false)
 						response.Forbidden(c, errors.New("user is not a member of the authorized group"))
 						c.Abort()
 						return
@@ -304,6 +373,7 @@ func BotAuthMiddleware(botAPIToken string, botToken string, allowedChatID int64,
 		languageCode := c.GetHeader("X-Telegram-Language-Code")
 
 		user, err := userService.SyncTelegramUser(
+			c.Request.Context(),
 			telegramUserID,
 			username,
 			firstName,
@@ -311,31 +381,45 @@ func BotAuthMiddleware(botAPIToken string, botToken string, allowedChatID int64,
 			languageCode,
 		)
 		if err != nil {
-			log.Printf("ERROR: Failed to sync user %d: %v", telegramUserID, err)
+			requestLogger(c).Error("failed to sync user", "telegram_id", telegramUserID, "error", err)
 			response.InternalServerError(c, err)
 			c.Abort()
 			return
 		}
 
+		if !allowActiveUser(c, user) {
+			return
+		}
+
 		// Сохраняем пользователя в контекст
-		c.Set("userID", user.ID)
-		c.Set("user", user)
+		setAuthenticatedUser(c, user)
 		c.Set("isBot", true) // Флаг что запрос от бота
 
-		log.Printf("INFO: Bot request authorized for user %d (DB ID: %d)", telegramUserID, user.ID)
+		requestLogger(c).Debug("bot request authorized", "telegram_id", telegramUserID)
 		c.Next()
 	}
 }
 
+// allowActiveUser отклоняет пользователей, отключённых каталогом (SCIM)
+func allowActiveUser(c *gin.Context, user *models.User) bool {
+	if !user.IsDeactivated() {
+		return true
+	}
+	requestLogger(c).Warn("deactivated user rejected", "user_id", user.ID)
+	response.Forbidden(c, ErrUserDeactivated)
+	c.Abort()
+	return false
+}
+
 // CORS middleware with security restrictions
-// allowedOrigins: список разрешённых доменов (из конфигурации)
-func CORS(allowedOrigins []string) gin.HandlerFunc {
+// allowedOrigins: список разрешённых доменов (из конфигурации, может меняться при reload)
+func CORS(allowedOrigins func() []string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		origin := c.Request.Header.Get("Origin")
 
 		// Проверяем, разрешён ли origin
 		isAllowed := false
-		for _, allowed := range allowedOrigins {
+		for _, allowed := range allowedOrigins() {
 			if origin == allowed {
 				isAllowed = true
 				break
@@ -348,8 +432,10 @@ func CORS(allowedOrigins []string) gin.HandlerFunc {
 			c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 		}
 
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Telegram-Init-Data, X-Telegram-User-ID, X-Telegram-Username")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Telegram-Init-Data, X-Telegram-User-ID, X-Telegram-Username, X-Request-ID, X-API-Key")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, PATCH, DELETE")
+		// Состояние лимита запросов доступно клиенту для паузы перед повтором
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset, Retry-After")
 		c.Writer.Header().Set("Access-Control-Max-Age", "43200") // 12 hours
 
 		if c.Request.Method == "OPTIONS" {
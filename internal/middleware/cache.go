@@ -0,0 +1,220 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// responseCacheMaxEntries ограничивает число записей кэша: ключ зависит от пути запроса,
+// и перебор путей и параметров не должен расходовать память без предела
+const responseCacheMaxEntries = 1024
+
+// ResponseCache - короткий серверный кэш GET-ответов с поддержкой ETag/Last-Modified
+// Предназначен для публичных эндпоинтов, которые часто опрашиваются (киоски, табло)
+type ResponseCache struct {
+	entries    map[string]*cachedResponse
+	mu         sync.RWMutex
+	ttl        time.Duration
+	maxEntries int
+}
+
+// cachedResponse хранит закэшированный ответ
+type cachedResponse struct {
+	body         []byte
+	contentType  string
+	etag         string
+	lastModified time.Time
+	expiresAt    time.Time
+}
+
+// NewResponseCache создаёт кэш ответов с заданным TTL
+func NewResponseCache(ttl time.Duration) *ResponseCache {
+	return &ResponseCache{
+		entries:    make(map[string]*cachedResponse),
+		ttl:        ttl,
+		maxEntries: responseCacheMaxEntries,
+	}
+}
+
+// Cache middleware отдаёт ответ из кэша и обрабатывает условные запросы
+// params - query-параметры, от которых зависит ответ; остальные в ключ не входят,
+// чтобы случайные параметры не создавали новых записей в обход кэша
+func (rc *ResponseCache) Cache(params ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		key := cacheKey(c.Request.URL, params)
+		if entry, ok := rc.get(key); ok {
+			writeCachedResponse(c, entry, "HIT")
+			c.Abort()
+			return
+		}
+
+		// Буферизуем тело ответа, чтобы посчитать ETag до отправки заголовков
+		writer := &bodyCaptureWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		// Ошибка, переданная через c.Error, ещё не отправлена - её отдаст Errors middleware
+		if writer.Status() != http.StatusOK || len(c.Errors) > 0 {
+			// Не кэшируем ошибки - отдаём ответ как есть
+			if writer.body.Len() > 0 {
+				writer.ResponseWriter.Write(writer.body.Bytes())
+			}
+			return
+		}
+
+		sum := sha256.Sum256(writer.body.Bytes())
+		entry := &cachedResponse{
+			body:         writer.body.Bytes(),
+			contentType:  writer.Header().Get("Content-Type"),
+			etag:         `"` + hex.EncodeToString(sum[:16]) + `"`,
+			lastModified: time.Now().UTC().Truncate(time.Second),
+			expiresAt:    time.Now().Add(rc.ttl),
+		}
+
+		rc.put(key, entry)
+
+		writeCachedResponse(c, entry, "MISS")
+	}
+}
+
+// InvalidateOnSuccess сбрасывает кэш после успешного изменяющего запроса
+// Используется на admin-маршрутах, изменяющих закэшированные данные
+func (rc *ResponseCache) InvalidateOnSuccess() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if status := c.Writer.Status(); status >= 200 && status < 300 && len(c.Errors) == 0 {
+			rc.Purge()
+		}
+	}
+}
+
+// Purge удаляет все записи кэша
+func (rc *ResponseCache) Purge() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.entries = make(map[string]*cachedResponse)
+}
+
+// put сохраняет запись; если кэш заполнен, сначала удаляются истёкшие записи, а затем - самая старая
+// Если содержимое не изменилось, сохраняется прежний Last-Modified
+func (rc *ResponseCache) put(key string, entry *cachedResponse) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	prev, ok := rc.entries[key]
+	if ok && prev.etag == entry.etag {
+		entry.lastModified = prev.lastModified
+	}
+	if !ok && len(rc.entries) >= rc.maxEntries {
+		now := time.Now()
+		oldestKey := ""
+		var oldest time.Time
+		for k, e := range rc.entries {
+			if now.After(e.expiresAt) {
+				delete(rc.entries, k)
+				continue
+			}
+			// TTL у всех записей одинаковый: раньше истекает та, что сохранена раньше
+			if oldestKey == "" || e.expiresAt.Before(oldest) {
+				oldestKey, oldest = k, e.expiresAt
+			}
+		}
+		if len(rc.entries) >= rc.maxEntries {
+			delete(rc.entries, oldestKey)
+		}
+	}
+	rc.entries[key] = entry
+}
+
+// cacheKey строит ключ кэша из пути и значений перечисленных query-параметров (в порядке сортировки)
+func cacheKey(u *url.URL, params []string) string {
+	query := u.Query()
+	values := url.Values{}
+	for _, name := range params {
+		if v, ok := query[name]; ok {
+			values[name] = v
+		}
+	}
+	if len(values) == 0 {
+		return u.Path
+	}
+	return u.Path + "?" + values.Encode()
+}
+
+// get возвращает актуальную запись кэша
+func (rc *ResponseCache) get(key string) (*cachedResponse, bool) {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+
+	entry, ok := rc.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry, true
+}
+
+// writeCachedResponse отдаёт ответ из кэша или 304 Not Modified
+func writeCachedResponse(c *gin.Context, entry *cachedResponse, cacheStatus string) {
+	c.Header("ETag", entry.etag)
+	c.Header("Last-Modified", entry.lastModified.Format(http.TimeFormat))
+	c.Header("X-Cache", cacheStatus)
+
+	if notModified(c.Request, entry) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, entry.contentType, entry.body)
+}
+
+// notModified проверяет условные заголовки If-None-Match / If-Modified-Since
+func notModified(r *http.Request, entry *cachedResponse) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == entry.etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" {
+		if t, err := http.ParseTime(ims); err == nil {
+			return !entry.lastModified.After(t)
+		}
+	}
+
+	return false
+}
+
+// bodyCaptureWriter буферизует тело ответа вместо немедленной отправки
+// Статус и заголовки остаются в исходном writer и отправляются при первой записи
+type bodyCaptureWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyCaptureWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+func (w *bodyCaptureWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newCachedEngine(rc *ResponseCache, calls *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(rc.Cache())
+	r.GET("/rooms", func(c *gin.Context) {
+		*calls++
+		c.JSON(http.StatusOK, gin.H{"rooms": []string{"A", "B"}})
+	})
+	return r
+}
+
+func TestResponseCache_HitAndETag(t *testing.T) {
+	calls := 0
+	r := newCachedEngine(NewResponseCache(time.Minute), &calls)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/rooms", nil))
+	if w.Code != http.StatusOK || w.Header().Get("X-Cache") != "MISS" {
+		t.Fatalf("Expected 200 MISS, got: %d %s", w.Code, w.Header().Get("X-Cache"))
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("Expected ETag header on first response")
+	}
+	body := w.Body.String()
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/rooms", nil))
+	if w.Header().Get("X-Cache") != "HIT" || w.Body.String() != body {
+		t.Errorf("Expected cached body, got: %s %s", w.Header().Get("X-Cache"), w.Body.String())
+	}
+	if calls != 1 {
+		t.Errorf("Expected handler to be called once, got: %d", calls)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/rooms", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNotModified {
+		t.Errorf("Expected 304, got: %d", w.Code)
+	}
+}
+
+func TestResponseCache_Purge(t *testing.T) {
+	calls := 0
+	rc := NewResponseCache(time.Minute)
+	r := newCachedEngine(rc, &calls)
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/rooms", nil))
+	rc.Purge()
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/rooms", nil))
+
+	if calls != 2 {
+		t.Errorf("Expected handler to be called twice after purge, got: %d", calls)
+	}
+}
+
+func TestResponseCache_KeyIgnoresOtherParams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	calls := 0
+	r := gin.New()
+	r.Use(NewResponseCache(time.Minute).Cache("with_equipment"))
+	r.GET("/rooms", func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusOK, gin.H{"with_equipment": c.Query("with_equipment")})
+	})
+
+	for _, target := range []string{"/rooms", "/rooms?nocache=1", "/rooms?nocache=2", "/rooms?with_equipment=true", "/rooms?with_equipment=true&x=1"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+	if calls != 2 {
+		t.Errorf("Expected one entry per with_equipment value, got %d handler calls", calls)
+	}
+}
+
+func TestResponseCache_MaxEntries(t *testing.T) {
+	rc := NewResponseCache(time.Minute)
+	rc.maxEntries = 2
+	now := time.Now()
+
+	rc.put("/expired", &cachedResponse{expiresAt: now.Add(-time.Second)})
+	rc.put("/a", &cachedResponse{expiresAt: now.Add(time.Minute)})
+	// Заполненный кэш сначала освобождается от истёкших записей
+	rc.put("/b", &cachedResponse{expiresAt: now.Add(2 * time.Minute)})
+	if _, ok := rc.entries["/expired"]; ok || len(rc.entries) != 2 {
+		t.Fatalf("Expected the expired entry to be evicted, got: %v", rc.entries)
+	}
+	// Без истёкших записей вытесняется самая старая
+	rc.put("/c", &cachedResponse{expiresAt: now.Add(3 * time.Minute)})
+	if _, ok := rc.entries["/a"]; ok || len(rc.entries) != 2 {
+		t.Errorf("Expected the oldest entry to be evicted, got: %v", rc.entries)
+	}
+}
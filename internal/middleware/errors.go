@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/space/backend/pkg/apperror"
+	"github.com/space/backend/pkg/response"
+	textvalidator "github.com/space/backend/pkg/validator"
+	"gorm.io/gorm"
+)
+
+// Errors renders the error a handler reported with c.Error
+// Единое сопоставление ошибок с ответами вместо switch в каждом обработчике: доменные ошибки
+// (pkg/apperror) - со своим статусом и кодом, ошибки полей - 400, отсутствующая запись - 404,
+// слишком большой файл - 413, остальные - 500 (504 по дедлайну запроса) с записью в лог.
+// Подключается глобально после Audit, чтобы журнал аудита и лог запросов видели итоговый статус
+func Errors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		last := c.Errors.Last()
+		if last == nil || c.Writer.Written() {
+			return
+		}
+		respondError(c, last.Err)
+	}
+}
+
+// respondError отправляет ответ для ошибки сервиса
+func respondError(c *gin.Context, err error) {
+	// Ошибки полей проверяются первыми: бизнес-проверки полей содержат доменные ошибки
+	var validationErrs validator.ValidationErrors
+	var fieldErrs textvalidator.Errors
+	if errors.As(err, &validationErrs) || errors.As(err, &fieldErrs) {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if appErr, ok := apperror.As(err); ok {
+		if appErr.Status >= 500 {
+			requestLogger(c).Error("request failed", "code", appErr.Code, "error", err)
+		}
+		response.AppError(c, err)
+		return
+	}
+
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		response.NotFound(c, err)
+	case errors.As(err, &tooLarge):
+		// Загружаемый файл больше лимита http.MaxBytesReader
+		response.Error(c, http.StatusRequestEntityTooLarge, err)
+	default:
+		requestLogger(c).Error("request failed", "error", err)
+		response.InternalServerError(c, err)
+	}
+}
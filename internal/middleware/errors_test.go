@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/pkg/apperror"
+	"gorm.io/gorm"
+)
+
+func serveError(err error) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Errors())
+	r.GET("/rooms/1", func(c *gin.Context) {
+		_ = c.Error(err)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/rooms/1", nil))
+	return w
+}
+
+func TestErrors_MapsStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"domain error", fmt.Errorf("get room: %w", apperror.NotFound("room_not_found", "room not found")), http.StatusNotFound, "room_not_found"},
+		{"record not found", gorm.ErrRecordNotFound, http.StatusNotFound, ""},
+		{"too large", &http.MaxBytesError{Limit: 10}, http.StatusRequestEntityTooLarge, ""},
+		{"unknown", errors.New("db is down"), http.StatusInternalServerError, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveError(tt.err)
+			if w.Code != tt.status {
+				t.Fatalf("Expected %d, got: %d %s", tt.status, w.Code, w.Body.String())
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Expected JSON body, got: %v", err)
+			}
+			if tt.code != "" && body["code"] != tt.code {
+				t.Errorf("Expected code %s, got: %v", tt.code, body)
+			}
+		})
+	}
+}
+
+func TestErrors_KeepsWrittenResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Errors())
+	r.GET("/export", func(c *gin.Context) {
+		c.String(http.StatusOK, "partial")
+		_ = c.Error(errors.New("stream broken"))
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/export", nil))
+	if w.Code != http.StatusOK || w.Body.String() != "partial" {
+		t.Errorf("Expected the written response to be kept, got: %d %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/pkg/i18n"
+)
+
+// Locale определяет язык ответа по заголовку Accept-Language
+// Если заголовок не содержит поддерживаемого языка, язык берётся из профиля
+// пользователя после аутентификации (см. setUserLocale), иначе используется i18n.Default
+func Locale() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept-Language")
+
+		if lang := i18n.ParseAcceptLanguage(c.GetHeader("Accept-Language")); lang != "" {
+			c.Set(i18n.ContextKey, lang)
+		}
+
+		c.Next()
+	}
+}
+
+// setUserLocale использует language_code пользователя Telegram,
+// если язык не был явно задан заголовком Accept-Language
+func setUserLocale(c *gin.Context, user *models.User) {
+	if _, exists := c.Get(i18n.ContextKey); exists {
+		return
+	}
+	if lang := i18n.Normalize(user.LanguageCode); lang != "" {
+		c.Set(i18n.ContextKey, lang)
+	}
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/logger"
+	"github.com/space/backend/internal/models"
+)
+
+// maxRequestIDLength ограничивает длину X-Request-ID от клиента
+const maxRequestIDLength = 128
+
+// RequestLogger добавляет request_id и структурированный логгер в контекст запроса
+// и логирует каждый завершённый запрос. Должен быть первым middleware
+func RequestLogger(base *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		// Используем request ID от прокси/клиента, если он есть
+		requestID := c.GetHeader("X-Request-ID")
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = newRequestID()
+		}
+		c.Set("requestID", requestID)
+		c.Writer.Header().Set("X-Request-ID", requestID)
+
+		reqLogger := base.With(
+			"request_id", requestID,
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+		)
+		c.Request = c.Request.WithContext(logger.WithContext(c.Request.Context(), reqLogger))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+
+		// Логгер мог быть дополнен user_id в auth middleware
+		requestLogger(c).Log(c.Request.Context(), level, "request completed",
+			"status", status,
+			"latency_ms", time.Since(start).Milliseconds(),
+			"client_ip", c.ClientIP(),
+			"bytes", c.Writer.Size(),
+		)
+	}
+}
+
+// requestLogger возвращает логгер текущего запроса
+func requestLogger(c *gin.Context) *slog.Logger {
+	return logger.FromContext(c.Request.Context())
+}
+
+// setAuthenticatedUser сохраняет пользователя в контекст и добавляет user_id в логгер запроса
+func setAuthenticatedUser(c *gin.Context, user *models.User) {
+	c.Set("userID", user.ID)
+	c.Set("user", user)
+	setUserLocale(c, user)
+
+	reqLogger := requestLogger(c).With("user_id", user.ID)
+	c.Request = c.Request.WithContext(logger.WithContext(c.Request.Context(), reqLogger))
+}
+
+// newRequestID генерирует случайный идентификатор запроса
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return time.Now().Format("20060102150405.000000000")
+	}
+	return hex.EncodeToString(b)
+}
@@ -1,40 +1,55 @@
 package middleware
 
 import (
-	"log"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/pkg/response"
 )
 
-// RateLimiter структура для хранения информации о запросах с IP
+// ErrTooManyRequests возвращается при превышении лимита запросов
+var ErrTooManyRequests = errors.New("too many requests")
+
+// RateLimiter ограничивает запросы по ключу (IP или пользователь) алгоритмом token bucket:
+// у ключа до rate токенов, запрос тратит один, за window пополняется rate токенов.
+// На ключ хранится одна запись фиксированного размера, проверка - O(1) под коротким локом
 type RateLimiter struct {
-	visitors map[string]*Visitor
-	mu       sync.RWMutex
-	rate     int           // количество запросов
-	window   time.Duration // временное окно
+	visitors        map[string]bucket
+	mu              sync.Mutex
+	rate            int           // количество запросов
+	window          time.Duration // временное окно
+	cleanupInterval time.Duration // период очистки неактивных посетителей
+	stop            chan struct{} // закрывается в Stop и завершает cleanupLoop
+	stopOnce        sync.Once
 }
 
-// Visitor хранит информацию о запросах с одного IP
-type Visitor struct {
-	lastSeen  time.Time
-	requests  []time.Time
-	blocked   bool
-	blockTime time.Time
+// bucket - токены одного ключа на момент последнего обращения
+type bucket struct {
+	tokens   float64
+	lastSeen time.Time
 }
 
 // NewRateLimiter создаёт новый rate limiter
 // rate: количество запросов
 // window: временное окно (например, 1 минута)
-func NewRateLimiter(rate int, window time.Duration) *RateLimiter {
+// cleanupInterval: период очистки неактивных посетителей (RATE_LIMIT_CLEANUP_INTERVAL)
+func NewRateLimiter(rate int, window, cleanupInterval time.Duration) *RateLimiter {
 	rl := &RateLimiter{
-		visitors: make(map[string]*Visitor),
-		rate:     rate,
-		window:   window,
+		visitors:        make(map[string]bucket),
+		rate:            rate,
+		window:          window,
+		cleanupInterval: cleanupInterval,
+		stop:            make(chan struct{}),
 	}
 
-	// Запускаем очистку старых записей каждые 5 минут
+	// Запускаем периодическую очистку старых записей
 	go rl.cleanupLoop()
 
 	return rl
@@ -43,86 +58,188 @@ func NewRateLimiter(rate int, window time.Duration) *RateLimiter {
 // RateLimit middleware для ограничения количества запросов с одного IP
 func (rl *RateLimiter) RateLimit() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ip := c.ClientIP()
-
-		// Проверяем лимит
-		if !rl.allow(ip) {
-			log.Printf("🚨 RATE LIMIT: Blocked IP %s (exceeded %d requests per %v)", ip, rl.rate, rl.window)
-			c.JSON(429, gin.H{
-				"error":   "too many requests",
-				"message": "Rate limit exceeded. Please try again later.",
-			})
-			c.Abort()
+		rl.limit(c, "ip:"+c.ClientIP())
+	}
+}
+
+// RateLimitAnonymous ограничивает по IP запросы без авторизации
+// Запросы с X-Telegram-Init-Data / X-Bot-Token / X-API-Key лимитируются по пользователю после
+// авторизации (RateLimitPerUser), чтобы офис за одним NAT не делил общий лимит. Лимит IP с них
+// снимается только при успешной авторизации: неудачная тратит токен IP, и подбор токенов или
+// мусорные заголовки упираются в тот же лимит, что и анонимные запросы
+func (rl *RateLimiter) RateLimitAnonymous() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := "ip:" + c.ClientIP()
+		if c.GetHeader("X-Telegram-Init-Data") == "" && c.GetHeader("X-Bot-Token") == "" && c.GetHeader("X-API-Key") == "" {
+			rl.limit(c, key)
 			return
 		}
 
+		// До авторизации запас IP только проверяется
+		if q := rl.check(key, time.Now(), false); !q.allowed {
+			rl.reject(c, key, q)
+			return
+		}
 		c.Next()
+		if _, authenticated := c.Get("userID"); !authenticated {
+			rl.reserve(key, time.Now())
+		}
 	}
 }
 
-// allow проверяет, разрешён ли запрос с данного IP
-func (rl *RateLimiter) allow(ip string) bool {
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
+// RateLimitPerUser ограничивает запросы по авторизованному пользователю
+// Должен использоваться после auth middleware; без пользователя лимитирует по IP
+func (rl *RateLimiter) RateLimitPerUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rl.limit(c, rateLimitKey(c))
+	}
+}
 
-	now := time.Now()
-	visitor, exists := rl.visitors[ip]
+// limit проверяет лимит для ключа и прерывает запрос с 429 при превышении
+// Состояние лимита отдаётся в заголовках X-RateLimit-*, при отказе - ещё и Retry-After
+func (rl *RateLimiter) limit(c *gin.Context, key string) {
+	q := rl.reserve(key, time.Now())
+	if !q.allowed {
+		rl.reject(c, key, q)
+		return
+	}
+	setQuotaHeaders(c, q)
 
-	if !exists {
-		// Новый посетитель
-		rl.visitors[ip] = &Visitor{
-			lastSeen: now,
-			requests: []time.Time{now},
-			blocked:  false,
+	c.Next()
+}
+
+// reject прерывает запрос с 429 и заголовками X-RateLimit-* и Retry-After
+func (rl *RateLimiter) reject(c *gin.Context, key string, q quota) {
+	setQuotaHeaders(c, q)
+	c.Header("Retry-After", strconv.Itoa(ceilSeconds(q.retryAfter)))
+	requestLogger(c).Warn("rate limit exceeded", "key", key, "window", rl.window.String())
+	response.ErrorWithMessage(c, http.StatusTooManyRequests, ErrTooManyRequests, "Rate limit exceeded. Please try again later.")
+	c.Abort()
+}
+
+// quota - состояние лимита ключа после обращения
+type quota struct {
+	allowed    bool
+	limit      int
+	remaining  int           // Целых токенов после запроса
+	reset      time.Duration // Через сколько запас восстановится полностью
+	retryAfter time.Duration // Через сколько появится следующий токен
+}
+
+// setQuotaHeaders выставляет заголовки X-RateLimit-* (Reset - секунд до полного восстановления)
+// Запрос проходит через несколько лимитеров (пользователь, маршрут) - клиенту показывается самый строгий
+func setQuotaHeaders(c *gin.Context, q quota) {
+	if current := c.Writer.Header().Get("X-RateLimit-Remaining"); current != "" {
+		if remaining, err := strconv.Atoi(current); err == nil && remaining < q.remaining {
+			return
 		}
-		return true
 	}
+	c.Header("X-RateLimit-Limit", strconv.Itoa(q.limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(q.remaining))
+	c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(q.reset)))
+}
 
-	// Обновляем время последнего визита
-	visitor.lastSeen = now
+// ceilSeconds округляет длительность вверх до целых секунд
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Seconds()))
+}
 
-	// Если заблокирован, проверяем, не истекло ли время блокировки
-	if visitor.blocked {
-		if now.Sub(visitor.blockTime) > rl.window {
-			// Разблокируем
-			visitor.blocked = false
-			visitor.requests = []time.Time{now}
-			return true
+// rateLimitKey возвращает ключ лимита: API ключ или пользователь, если он авторизован, иначе IP
+func rateLimitKey(c *gin.Context) string {
+	if apiKey, exists := c.Get("apiKey"); exists {
+		if key, ok := apiKey.(*models.APIKey); ok {
+			return fmt.Sprintf("apikey:%d", key.ID)
 		}
-		return false
 	}
+	if userID, exists := c.Get("userID"); exists {
+		return fmt.Sprintf("user:%v", userID)
+	}
+	return "ip:" + c.ClientIP()
+}
 
-	// Удаляем старые запросы, вышедшие за окно
-	validRequests := []time.Time{}
-	for _, reqTime := range visitor.requests {
-		if now.Sub(reqTime) <= rl.window {
-			validRequests = append(validRequests, reqTime)
-		}
+// SetRate меняет лимит запросов на окно (используется при перезагрузке конфигурации)
+func (rl *RateLimiter) SetRate(rate int) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.rate = rate
+}
+
+// take пополняет токены ключа за прошедшее время и тратит один, если он есть
+func (rl *RateLimiter) take(key string, now time.Time) bool {
+	return rl.reserve(key, now).allowed
+}
+
+// reserve тратит токен ключа, если он есть, и возвращает состояние лимита
+func (rl *RateLimiter) reserve(key string, now time.Time) quota {
+	return rl.check(key, now, true)
+}
+
+// check пополняет токены ключа за прошедшее время и возвращает состояние лимита;
+// spend - потратить токен, если он есть
+func (rl *RateLimiter) check(key string, now time.Time, spend bool) quota {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	capacity := float64(rl.rate)
+	b, exists := rl.visitors[key]
+	if !exists {
+		// Новый посетитель начинает с полным запасом
+		b.tokens = capacity
+	} else if elapsed := now.Sub(b.lastSeen); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * capacity / rl.window.Seconds()
+	}
+	// Запас не больше лимита, в том числе после его уменьшения через SetRate
+	if b.tokens > capacity {
+		b.tokens = capacity
 	}
+	b.lastSeen = now
 
-	// Проверяем лимит
-	if len(validRequests) >= rl.rate {
-		// Блокируем
-		visitor.blocked = true
-		visitor.blockTime = now
-		log.Printf("⚠️  SECURITY: IP %s exceeded rate limit (%d requests in %v)", ip, len(validRequests), rl.window)
-		return false
+	allowed := b.tokens >= 1
+	if allowed && spend {
+		b.tokens--
 	}
+	rl.visitors[key] = b
 
-	// Добавляем текущий запрос
-	validRequests = append(validRequests, now)
-	visitor.requests = validRequests
+	// Время пополнения одного токена; при нулевом лимите токены не появляются никогда
+	perToken := time.Duration(0)
+	if capacity > 0 {
+		perToken = time.Duration(float64(rl.window) / capacity)
+	}
+	q := quota{
+		allowed:   allowed,
+		limit:     rl.rate,
+		remaining: int(b.tokens),
+		reset:     time.Duration((capacity - b.tokens) * float64(perToken)),
+	}
+	if b.tokens < 1 {
+		q.retryAfter = time.Duration((1 - b.tokens) * float64(perToken))
+		if perToken == 0 {
+			q.retryAfter = rl.window
+		}
+	}
+	return q
+}
 
-	return true
+// Stop останавливает периодическую очистку; лимитер после этого больше не используется
+func (rl *RateLimiter) Stop() {
+	rl.stopOnce.Do(func() { close(rl.stop) })
 }
 
-// cleanupLoop периодически очищает старые записи
+// cleanupLoop периодически очищает старые записи до вызова Stop
 func (rl *RateLimiter) cleanupLoop() {
-	ticker := time.NewTicker(5 * time.Minute)
+	ticker := time.NewTicker(rl.cleanupInterval)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		rl.cleanup()
+	for {
+		select {
+		case <-ticker.C:
+			rl.cleanup()
+		case <-rl.stop:
+			return
+		}
 	}
 }
 
@@ -132,10 +249,77 @@ func (rl *RateLimiter) cleanup() {
 	defer rl.mu.Unlock()
 
 	now := time.Now()
-	for ip, visitor := range rl.visitors {
-		// Удаляем записи, которые не обращались больше 10 минут
-		if now.Sub(visitor.lastSeen) > 10*time.Minute {
-			delete(rl.visitors, ip)
+	// Окно может быть длиннее 10 минут (например, для политик маршрутов)
+	idle := 10 * time.Minute
+	if rl.window > idle {
+		idle = rl.window
+	}
+
+	for key, b := range rl.visitors {
+		// Удаляем записи, которые не обращались дольше окна неактивности (их запас уже полный)
+		if now.Sub(b.lastSeen) > idle {
+			delete(rl.visitors, key)
+		}
+	}
+}
+
+// RoutePolicy задаёт отдельный лимит для маршрута (метод + шаблон пути gin)
+type RoutePolicy struct {
+	Method string
+	Path   string // Шаблон маршрута, например /api/bookings/:id
+	Rate   int
+	Window time.Duration
+}
+
+// RouteRateLimiter применяет отдельные лимиты к конкретным маршрутам
+type RouteRateLimiter struct {
+	mu              sync.RWMutex
+	limiters        map[string]*RateLimiter
+	cleanupInterval time.Duration
+}
+
+// NewRouteRateLimiter создаёт лимитер с политиками для маршрутов
+func NewRouteRateLimiter(policies []RoutePolicy, cleanupInterval time.Duration) *RouteRateLimiter {
+	rrl := &RouteRateLimiter{cleanupInterval: cleanupInterval}
+	rrl.SetPolicies(policies)
+	return rrl
+}
+
+// SetPolicies заменяет политики маршрутов (используется при перезагрузке конфигурации)
+// Счётчики маршрутов с неизменной политикой сохраняются, заменённые лимитеры останавливаются
+func (rrl *RouteRateLimiter) SetPolicies(policies []RoutePolicy) {
+	rrl.mu.Lock()
+	defer rrl.mu.Unlock()
+
+	limiters := make(map[string]*RateLimiter, len(policies))
+	for _, p := range policies {
+		key := p.Method + " " + p.Path
+		if existing, ok := rrl.limiters[key]; ok && existing.window == p.Window {
+			existing.SetRate(p.Rate)
+			limiters[key] = existing
+			continue
+		}
+		limiters[key] = NewRateLimiter(p.Rate, p.Window, rrl.cleanupInterval)
+	}
+	for key, previous := range rrl.limiters {
+		if limiters[key] != previous {
+			previous.Stop()
+		}
+	}
+	rrl.limiters = limiters
+}
+
+// RateLimit middleware применяет политику маршрута (если она есть) по пользователю
+// Должен использоваться после auth middleware
+func (rrl *RouteRateLimiter) RateLimit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rrl.mu.RLock()
+		rl, ok := rrl.limiters[c.Request.Method+" "+c.FullPath()]
+		rrl.mu.RUnlock()
+		if !ok {
+			c.Next()
+			return
 		}
+		rl.limit(c, rateLimitKey(c))
 	}
 }
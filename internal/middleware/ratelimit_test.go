@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRateLimiter_TokenBucket(t *testing.T) {
+	rl := &RateLimiter{visitors: make(map[string]bucket), rate: 3, window: time.Minute}
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		if !rl.take("ip:1", now) {
+			t.Fatalf("Expected request %d within the limit to be allowed", i+1)
+		}
+	}
+	if rl.take("ip:1", now) {
+		t.Error("Expected request over the limit to be rejected")
+	}
+	if !rl.take("ip:2", now) {
+		t.Error("Expected other keys to have their own limit")
+	}
+
+	// За треть окна пополняется один токен
+	if !rl.take("ip:1", now.Add(20*time.Second)) || rl.take("ip:1", now.Add(20*time.Second)) {
+		t.Error("Expected exactly one request after a third of the window")
+	}
+
+	// После долгого простоя запас не превышает лимит
+	later := now.Add(time.Hour)
+	allowed := 0
+	for i := 0; i < 5; i++ {
+		if rl.take("ip:1", later) {
+			allowed++
+		}
+	}
+	if allowed != 3 {
+		t.Errorf("Expected burst capped at the limit, got: %d", allowed)
+	}
+
+	rl.SetRate(1)
+	if !rl.take("ip:3", later) || rl.take("ip:3", later) {
+		t.Error("Expected the new rate to apply")
+	}
+}
+
+func TestRateLimiter_Headers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rl := &RateLimiter{visitors: make(map[string]bucket), rate: 2, window: time.Minute}
+	r := gin.New()
+	r.GET("/", rl.RateLimit(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	do := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		return w
+	}
+
+	w := do()
+	if w.Code != http.StatusOK || w.Header().Get("X-RateLimit-Limit") != "2" || w.Header().Get("X-RateLimit-Remaining") != "1" || w.Header().Get("X-RateLimit-Reset") != "30" {
+		t.Errorf("Unexpected quota headers: %d %v", w.Code, w.Header())
+	}
+	if w.Header().Get("Retry-After") != "" {
+		t.Error("Expected no Retry-After on an allowed request")
+	}
+
+	do()
+	w = do()
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("X-RateLimit-Remaining") != "0" {
+		t.Fatalf("Expected 429 with no remaining requests, got: %d %v", w.Code, w.Header())
+	}
+	// Следующий токен - через половину окна
+	if retry := w.Header().Get("Retry-After"); retry != "30" && retry != "29" {
+		t.Errorf("Expected Retry-After of about 30 seconds, got: %q", retry)
+	}
+}
+
+func TestRateLimiter_AnonymousChargesFailedAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rl := &RateLimiter{visitors: make(map[string]bucket), rate: 2, window: time.Minute}
+	r := gin.New()
+	r.GET("/", rl.RateLimitAnonymous(), func(c *gin.Context) {
+		if c.GetHeader("X-API-Key") != "valid" {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Set("userID", uint(1))
+		c.Status(http.StatusOK)
+	})
+
+	do := func(apiKey string) int {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Успешная авторизация лимит IP не тратит
+	for i := 0; i < 5; i++ {
+		if code := do("valid"); code != http.StatusOK {
+			t.Fatalf("Expected authenticated request %d to bypass the IP limit, got: %d", i+1, code)
+		}
+	}
+	// Подбор ключа тратит лимит IP, как и анонимные запросы
+	if do("guess-1") != http.StatusUnauthorized || do("guess-2") != http.StatusUnauthorized {
+		t.Fatal("Expected failed auth within the IP limit to reach the auth middleware")
+	}
+	if code := do("guess-3"); code != http.StatusTooManyRequests {
+		t.Errorf("Expected failed auth over the IP limit to be throttled, got: %d", code)
+	}
+	if code := do(""); code != http.StatusTooManyRequests {
+		t.Errorf("Expected anonymous requests to share the IP limit, got: %d", code)
+	}
+}
+
+func TestRouteRateLimiter_SetPoliciesStopsReplacedLimiters(t *testing.T) {
+	rrl := NewRouteRateLimiter([]RoutePolicy{
+		{Method: http.MethodPost, Path: "/api/bookings", Rate: 5, Window: time.Minute},
+		{Method: http.MethodGet, Path: "/api/rooms", Rate: 5, Window: time.Minute},
+		{Method: http.MethodGet, Path: "/api/export", Rate: 1, Window: time.Hour},
+	}, time.Minute)
+	kept := rrl.limiters["POST /api/bookings"]
+	replaced := rrl.limiters["GET /api/rooms"]
+	removed := rrl.limiters["GET /api/export"]
+
+	// Изменение окна создаёт новый лимитер, удалённая политика - тоже останавливается
+	rrl.SetPolicies([]RoutePolicy{
+		{Method: http.MethodPost, Path: "/api/bookings", Rate: 10, Window: time.Minute},
+		{Method: http.MethodGet, Path: "/api/rooms", Rate: 5, Window: time.Hour},
+	})
+	defer rrl.limiters["POST /api/bookings"].Stop()
+	defer rrl.limiters["GET /api/rooms"].Stop()
+
+	stopped := func(rl *RateLimiter) bool {
+		select {
+		case <-rl.stop:
+			return true
+		default:
+			return false
+		}
+	}
+	if rrl.limiters["POST /api/bookings"] != kept || stopped(kept) {
+		t.Error("Expected the limiter with the same window to be kept running")
+	}
+	if !stopped(replaced) || !stopped(removed) {
+		t.Error("Expected replaced and removed limiters to be stopped")
+	}
+}
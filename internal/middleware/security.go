@@ -1,15 +1,19 @@
 package middleware
 
 import (
-	"log"
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/space/backend/pkg/response"
 )
 
+// ErrForbiddenOrigin возвращается для запросов с недоверенным Referer/Origin
+var ErrForbiddenOrigin = errors.New("forbidden")
+
 // SecurityLogger логирует подозрительные запросы
-func SecurityLogger(allowedOrigins []string) gin.HandlerFunc {
+func SecurityLogger(allowedOrigins func() []string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		origin := c.GetHeader("Origin")
 		referer := c.GetHeader("Referer")
@@ -17,17 +21,17 @@ func SecurityLogger(allowedOrigins []string) gin.HandlerFunc {
 
 		// Проверяем, является ли origin доверенным
 		isTrusted := false
-		for _, allowed := range allowedOrigins {
+		for _, allowed := range allowedOrigins() {
 			if origin == allowed {
 				isTrusted = true
 				break
 			}
 		}
 
-		// Логируем все запросы с неизвестных доменов
-		if !isTrusted && origin != "" {
-			log.Printf("⚠️  SECURITY: Unknown origin: %s, Referer: %s, UA: %s, IP: %s, Path: %s",
-				origin, referer, userAgent, c.ClientIP(), c.Request.URL.Path)
+		// Логируем все запросы с неизвестных доменов (origin виджета проверяет WidgetAuth)
+		if !isTrusted && origin != "" && !strings.HasPrefix(c.Request.URL.Path, "/api/widget") {
+			requestLogger(c).Warn("security: unknown origin",
+				"origin", origin, "referer", referer, "user_agent", userAgent, "client_ip", c.ClientIP())
 		}
 
 		c.Next()
@@ -35,7 +39,7 @@ func SecurityLogger(allowedOrigins []string) gin.HandlerFunc {
 }
 
 // RefererCheck проверяет Referer заголовок для дополнительной защиты
-func RefererCheck(allowedOrigins []string) gin.HandlerFunc {
+func RefererCheck(allowedOrigins func() []string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Пропускаем OPTIONS запросы
 		if c.Request.Method == "OPTIONS" {
@@ -43,8 +47,13 @@ func RefererCheck(allowedOrigins []string) gin.HandlerFunc {
 			return
 		}
 
-		// Пропускаем health check и публичные эндпоинты
-		if c.Request.URL.Path == "/health" || strings.HasPrefix(c.Request.URL.Path, "/api/public") {
+		// Пропускаем health check, публичные эндпоинты, Swagger UI (его страница ссылается сама на себя)
+		// и редиректы входа через OIDC (Referer - страница провайдера; callback защищён state и PKCE).
+		// Виджет встраивается на сайт не из ALLOWED_ORIGINS - его origin проверяет WidgetAuth
+		if strings.HasPrefix(c.Request.URL.Path, "/health") || strings.HasPrefix(c.Request.URL.Path, "/api/public") ||
+			strings.HasPrefix(c.Request.URL.Path, "/api/widget") ||
+			strings.HasPrefix(c.Request.URL.Path, "/api/docs") ||
+			c.Request.URL.Path == "/api/auth/oidc/login" || c.Request.URL.Path == "/api/auth/oidc/callback" {
 			c.Next()
 			return
 		}
@@ -55,15 +64,15 @@ func RefererCheck(allowedOrigins []string) gin.HandlerFunc {
 		// Если нет ни referer, ни origin - подозрительно, но можем пропустить для прямых API запросов
 		if referer == "" && origin == "" {
 			// Логируем для мониторинга
-			log.Printf("⚠️  SECURITY: No Referer/Origin from IP: %s, Path: %s, UA: %s",
-				c.ClientIP(), c.Request.URL.Path, c.GetHeader("User-Agent"))
+			requestLogger(c).Debug("security: no Referer/Origin",
+				"client_ip", c.ClientIP(), "user_agent", c.GetHeader("User-Agent"))
 			c.Next()
 			return
 		}
 
 		// Проверяем, что referer или origin из доверенного домена
 		isValid := false
-		for _, domain := range allowedOrigins {
+		for _, domain := range allowedOrigins() {
 			if strings.HasPrefix(referer, domain) || origin == domain {
 				isValid = true
 				break
@@ -71,11 +80,9 @@ func RefererCheck(allowedOrigins []string) gin.HandlerFunc {
 		}
 
 		if !isValid && (referer != "" || origin != "") {
-			log.Printf("🚨 SECURITY ALERT: Blocked suspicious referer/origin - Referer: %s, Origin: %s, IP: %s, Path: %s",
-				referer, origin, c.ClientIP(), c.Request.URL.Path)
-			c.JSON(403, gin.H{
-				"error": "forbidden",
-			})
+			requestLogger(c).Warn("security: blocked suspicious referer/origin",
+				"referer", referer, "origin", origin, "client_ip", c.ClientIP())
+			response.Forbidden(c, ErrForbiddenOrigin)
 			c.Abort()
 			return
 		}
@@ -85,7 +92,8 @@ func RefererCheck(allowedOrigins []string) gin.HandlerFunc {
 }
 
 // HTTPSEnforcement перенаправляет HTTP на HTTPS в production
-func HTTPSEnforcement(environment string) gin.HandlerFunc {
+// hsts - значение Strict-Transport-Security (пустое - заголовок не отправляется)
+func HTTPSEnforcement(environment string, hsts string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if environment == "production" {
 			// Проверяем X-Forwarded-Proto заголовок (используется балансировщиками)
@@ -98,34 +106,45 @@ func HTTPSEnforcement(environment string) gin.HandlerFunc {
 			}
 
 			// Добавляем HSTS заголовок
-			c.Writer.Header().Set("Strict-Transport-Security", "max-age=31536000; includeSubDomains; preload")
+			if hsts != "" {
+				c.Writer.Header().Set("Strict-Transport-Security", hsts)
+			}
 		}
 
 		c.Next()
 	}
 }
 
+// SecurityHeadersConfig содержит значения заголовков безопасности
+// Пустое значение - заголовок не отправляется
+type SecurityHeadersConfig struct {
+	ContentSecurityPolicy string
+	FrameOptions          string
+	ReferrerPolicy        string
+	PermissionsPolicy     string
+}
+
 // SecurityHeaders добавляет заголовки безопасности
-func SecurityHeaders() gin.HandlerFunc {
+func SecurityHeaders(cfg SecurityHeadersConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Предотвращает MIME type sniffing
 		c.Writer.Header().Set("X-Content-Type-Options", "nosniff")
 
 		// Защита от clickjacking
-		c.Writer.Header().Set("X-Frame-Options", "DENY")
+		// Для Telegram WebView используется CSP frame-ancestors вместо X-Frame-Options: DENY
+		setHeaderIfNotEmpty(c, "X-Frame-Options", cfg.FrameOptions)
 
 		// XSS защита (для старых браузеров)
 		c.Writer.Header().Set("X-XSS-Protection", "1; mode=block")
 
 		// Контроль Referer
-		c.Writer.Header().Set("Referrer-Policy", "strict-origin-when-cross-origin")
+		setHeaderIfNotEmpty(c, "Referrer-Policy", cfg.ReferrerPolicy)
 
-		// Content Security Policy - базовая политика
-		// Настройте под ваши нужды
-		c.Writer.Header().Set("Content-Security-Policy", "default-src 'self'")
+		// Content Security Policy
+		setHeaderIfNotEmpty(c, "Content-Security-Policy", cfg.ContentSecurityPolicy)
 
 		// Permissions Policy (бывший Feature-Policy)
-		c.Writer.Header().Set("Permissions-Policy", "geolocation=(), microphone=(), camera=()")
+		setHeaderIfNotEmpty(c, "Permissions-Policy", cfg.PermissionsPolicy)
 
 		// Явно указываем UTF-8 для всех JSON ответов
 		c.Writer.Header().Set("Content-Type", "application/json; charset=utf-8")
@@ -133,3 +152,10 @@ func SecurityHeaders() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// setHeaderIfNotEmpty устанавливает заголовок, только если значение задано
+func setHeaderIfNotEmpty(c *gin.Context, key, value string) {
+	if value != "" {
+		c.Writer.Header().Set(key, value)
+	}
+}
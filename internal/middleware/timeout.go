@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/pkg/response"
+)
+
+// ErrRequestTimeout возвращается клиенту, если запрос не уложился в дедлайн
+var ErrRequestTimeout = errors.New("request timed out")
+
+// Timeout устанавливает дедлайн для контекста запроса
+// Обработчики передают c.Request.Context() в сервисы; репозитории привязывают к нему запросы
+// (db.WithContext в dbFromContext), вызовы Telegram API - HTTP-запросы, поэтому по истечении
+// дедлайна прерываются и выполняющиеся запросы к БД. Если к моменту завершения
+// обработчика ответ ещё не отправлен, клиент получает 504
+func Timeout(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			requestLogger(c).Warn("request timed out", "timeout", timeout.String())
+			response.Error(c, http.StatusGatewayTimeout, ErrRequestTimeout)
+			c.Abort()
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTimeout_CancelsRequestContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Timeout(10 * time.Millisecond))
+
+	var workErr error
+	r.GET("/slow", func(c *gin.Context) {
+		// Так ведёт себя запрос к БД через db.WithContext: он прерывается вместе с контекстом запроса
+		select {
+		case <-c.Request.Context().Done():
+			workErr = c.Request.Context().Err()
+		case <-time.After(time.Second):
+		}
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("Expected 504, got: %d", w.Code)
+	}
+	if !errors.Is(workErr, context.DeadlineExceeded) {
+		t.Errorf("Expected the handler's work to be cancelled, got: %v", workErr)
+	}
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"time"
+)
+
+// TokenSet хранит текущий токен и предыдущий токен, который продолжает
+// приниматься до окончания grace-периода после ротации
+type TokenSet struct {
+	Current    string
+	Previous   string
+	GraceUntil time.Time
+}
+
+// Active возвращает токены, которые принимаются в данный момент
+// Текущий токен всегда идёт первым
+func (t TokenSet) Active() []string {
+	return t.activeAt(time.Now())
+}
+
+// activeAt возвращает токены, принимаемые в момент now
+func (t TokenSet) activeAt(now time.Time) []string {
+	tokens := []string{t.Current}
+	if t.Previous != "" && t.Previous != t.Current && now.Before(t.GraceUntil) {
+		tokens = append(tokens, t.Previous)
+	}
+	return tokens
+}
+
+// Match проверяет токен против активных токенов за константное время
+// Возвращает true вторым значением, если совпал предыдущий токен
+func (t TokenSet) Match(provided string) (bool, bool) {
+	return t.matchAt(provided, time.Now())
+}
+
+// matchAt проверяет токен против токенов, активных в момент now
+func (t TokenSet) matchAt(provided string, now time.Time) (bool, bool) {
+	for i, token := range t.activeAt(now) {
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1 {
+			return true, i > 0
+		}
+	}
+	return false, false
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenSet_Match(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	rotated := TokenSet{Current: "new-token", Previous: "old-token", GraceUntil: now.Add(time.Hour)}
+
+	tests := []struct {
+		name         string
+		set          TokenSet
+		provided     string
+		at           time.Time
+		wantMatch    bool
+		wantPrevious bool
+		wantActive   int
+	}{
+		{"current token", rotated, "new-token", now, true, false, 2},
+		{"previous token within grace", rotated, "old-token", now.Add(59 * time.Minute), true, true, 2},
+		{"previous token at grace end", rotated, "old-token", now.Add(time.Hour), false, false, 1},
+		{"previous token after grace", rotated, "old-token", now.Add(2 * time.Hour), false, false, 1},
+		{"current token after grace", rotated, "new-token", now.Add(2 * time.Hour), true, false, 1},
+		{"unknown token", rotated, "other-token", now, false, false, 2},
+		{"empty previous never matches", TokenSet{Current: "new-token", GraceUntil: now.Add(time.Hour)}, "", now, false, false, 1},
+		{"previous equal to current", TokenSet{Current: "new-token", Previous: "new-token", GraceUntil: now.Add(time.Hour)}, "new-token", now, true, false, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matched, previous := tt.set.matchAt(tt.provided, tt.at)
+			if matched != tt.wantMatch || previous != tt.wantPrevious {
+				t.Errorf("Expected match=%v previous=%v, got match=%v previous=%v", tt.wantMatch, tt.wantPrevious, matched, previous)
+			}
+			if active := tt.set.activeAt(tt.at); len(active) != tt.wantActive || active[0] != tt.set.Current {
+				t.Errorf("Expected %d active tokens starting with the current one, got: %v", tt.wantActive, active)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/pkg/response"
+)
+
+var ErrInvalidWidgetToken = errors.New("invalid widget token")
+
+// WidgetAuth проверяет токен виджета в ?token= и разрешает CORS только для сайта виджета
+// Токен встраивается в страницу сайта, поэтому передаётся в query: GET без заголовков не требует preflight.
+// Запросы с другим Origin отклоняются; cookies и авторизация не нужны, поэтому Allow-Credentials не выставляется
+func WidgetAuth(token, origin string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if requestOrigin := c.GetHeader("Origin"); requestOrigin != "" {
+			if requestOrigin != origin {
+				requestLogger(c).Warn("security: widget request from foreign origin",
+					"origin", requestOrigin, "client_ip", c.ClientIP())
+				response.Forbidden(c, ErrForbiddenOrigin)
+				c.Abort()
+				return
+			}
+			c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
+			c.Writer.Header().Del("Access-Control-Allow-Credentials")
+		}
+		c.Writer.Header().Add("Vary", "Origin")
+
+		if subtle.ConstantTimeCompare([]byte(c.Query("token")), []byte(token)) != 1 {
+			response.Unauthorized(c, ErrInvalidWidgetToken)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// AbuseReason определяет правило, по которому пользователь отмечен
+type AbuseReason string
+
+const (
+	AbuseReasonCancellations AbuseReason = "cancellations" // Слишком много отмен за окно проверки
+	AbuseReasonLongBookings  AbuseReason = "long_bookings" // Длинные бронирования подряд, день за днём
+)
+
+// AbuseFlagStatus определяет состояние отметки в очереди проверки
+type AbuseFlagStatus string
+
+const (
+	AbuseFlagOpen      AbuseFlagStatus = "open"      // Ждёт проверки администратором
+	AbuseFlagDismissed AbuseFlagStatus = "dismissed" // Ложное срабатывание
+	AbuseFlagConfirmed AbuseFlagStatus = "confirmed" // Злоупотребление подтверждено
+)
+
+// AbuseFlag is an entry of the admin review queue created by the booking abuse detector
+type AbuseFlag struct {
+	ID           uint            `gorm:"primaryKey" json:"id"`
+	UserID       uint            `gorm:"not null;index;uniqueIndex:idx_abuse_flags_open,where:status = 'open'" json:"user_id"`
+	Reason       AbuseReason     `gorm:"type:varchar(30);not null;uniqueIndex:idx_abuse_flags_open" json:"reason"`
+	Count        int             `gorm:"not null" json:"count"` // Сколько отмен или длинных бронирований подряд найдено
+	Status       AbuseFlagStatus `gorm:"type:varchar(20);not null;default:'open';index" json:"status"`
+	BookingLimit *int            `json:"booking_limit,omitempty"` // Лимит, назначенный пользователю автоматически
+	ReviewedByID *uint           `json:"reviewed_by_id,omitempty"`
+	ReviewedAt   *time.Time      `json:"reviewed_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Связи
+	User       User  `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	ReviewedBy *User `gorm:"foreignKey:ReviewedByID" json:"-"`
+}
+
+// TableName specifies the table name for AbuseFlag
+func (AbuseFlag) TableName() string {
+	return "abuse_flags"
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// APIKeyScope определяет право доступа API ключа
+type APIKeyScope string
+
+const (
+	ScopeReadRooms      APIKeyScope = "read:rooms"        // Просмотр комнат
+	ScopeWriteRooms     APIKeyScope = "write:rooms"       // Управление комнатами
+	ScopeReadBookings   APIKeyScope = "read:bookings"     // Просмотр бронирований
+	ScopeWriteBookings  APIKeyScope = "write:bookings"    // Создание бронирований
+	ScopeProvisionUsers APIKeyScope = "provision:users"   // Синхронизация пользователей и команд из каталога (SCIM)
+	ScopeCleaning       APIKeyScope = "facility:cleaning" // Задачи уборки для персонала пространства
+)
+
+// ValidAPIKeyScopes - все поддерживаемые scopes
+var ValidAPIKeyScopes = []APIKeyScope{
+	ScopeReadRooms,
+	ScopeWriteRooms,
+	ScopeReadBookings,
+	ScopeWriteBookings,
+	ScopeProvisionUsers,
+	ScopeCleaning,
+}
+
+// APIKey represents a third-party API key (dashboards, scripts)
+// Сам секрет не хранится - только его SHA-256 хэш
+type APIKey struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	Name        string     `gorm:"not null" json:"name"`                           // Название интеграции
+	Prefix      string     `gorm:"type:varchar(16);not null" json:"prefix"`        // Начало ключа для идентификации в UI
+	SecretHash  string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"` // SHA-256 от полного ключа
+	Scopes      string     `gorm:"type:varchar(500);not null" json:"scopes"`       // Scopes через запятую
+	CreatedByID uint       `gorm:"not null;index" json:"created_by_id"`            // Админ, создавший ключ
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`                           // Срок действия (nil - бессрочный)
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`                         // Время последнего использования
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Связи
+	CreatedBy User `gorm:"foreignKey:CreatedByID" json:"-"`
+}
+
+// ScopeList returns the key scopes as a slice
+func (k *APIKey) ScopeList() []APIKeyScope {
+	var scopes []APIKeyScope
+	for _, s := range strings.Split(k.Scopes, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			scopes = append(scopes, APIKeyScope(s))
+		}
+	}
+	return scopes
+}
+
+// HasScope checks if the key grants the scope
+func (k *APIKey) HasScope(scope APIKeyScope) bool {
+	for _, s := range k.ScopeList() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExpired checks if the key is past its expiry
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return k.ExpiresAt != nil && now.After(*k.ExpiresAt)
+}
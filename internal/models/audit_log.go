@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// AuditActorType определяет, кто выполнил действие
+type AuditActorType string
+
+const (
+	AuditActorUser      AuditActorType = "user"      // Пользователь Mini App / Login Widget
+	AuditActorBot       AuditActorType = "bot"       // Telegram бот от имени пользователя
+	AuditActorAPIKey    AuditActorType = "api_key"   // Сторонняя интеграция по API ключу
+	AuditActorAnonymous AuditActorType = "anonymous" // Запрос без аутентификации
+)
+
+// AuditLog represents a single state-changing request (POST/PATCH/PUT/DELETE)
+// Записи не удаляются мягко - старые записи физически удаляются по retention policy
+type AuditLog struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	ActorID   *uint          `gorm:"index" json:"actor_id,omitempty"`             // Пользователь, выполнивший действие
+	ActorType AuditActorType `gorm:"type:varchar(20);not null" json:"actor_type"` // user, bot, api_key, anonymous
+	APIKeyID  *uint          `json:"api_key_id,omitempty"`                        // API ключ, если запрос от интеграции
+	Method    string         `gorm:"type:varchar(10);not null" json:"method"`
+	Route     string         `gorm:"type:varchar(255);not null;index" json:"route"` // Шаблон маршрута, например /api/bookings/:id
+	Path      string         `gorm:"type:varchar(500);not null" json:"path"`        // Фактический путь запроса
+	EntityID  string         `gorm:"type:varchar(64);index" json:"entity_id,omitempty"`
+	Status    int            `gorm:"not null" json:"status"`
+	ClientIP  string         `gorm:"type:varchar(64)" json:"client_ip"`
+	RequestID string         `gorm:"type:varchar(128)" json:"request_id,omitempty"`
+	CreatedAt time.Time      `gorm:"index" json:"created_at"`
+}
@@ -32,9 +32,19 @@ type Booking struct {
 	// Дополнительные параметры
 	EstimatedParticipants int  `gorm:"default:1" json:"estimated_participants"` // Предполагаемое количество участников
 	IsJoinable           bool `gorm:"default:false" json:"is_joinable"`        // Можно ли присоединиться к мероприятию
+	MaxParticipants      int  `gorm:"not null;default:0" json:"max_participants"` // Мест вместе с создателем; 0 - без ограничения, иначе сверх лимита - очередь
 
 	Status BookingStatus `gorm:"type:varchar(20);default:'confirmed'" json:"status"`
 
+	LicensePlate string `gorm:"type:varchar(20)" json:"license_plate,omitempty"` // Номер автомобиля (только для парковочных мест)
+
+	// Оценка стоимости по цене часа комнаты на момент бронирования, в минимальных единицах валюты PRICE_CURRENCY
+	EstimatedCost int64 `gorm:"not null;default:0" json:"estimated_cost"`
+
+	ReminderSentAt *time.Time `json:"-"` // Когда разослано напоминание о начале; повторно не отправляется
+	RetentionExempt bool     `gorm:"not null;default:false" json:"-"` // Не удаляется правилами хранения данных
+	MultiDay        bool     `gorm:"-" json:"-"` // Пересекает полночь или длиннее максимума; заполняется сервисом при создании
+
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
@@ -66,3 +76,22 @@ func (b *Booking) BeforeUpdate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// BookingSummary is a compact projection of a booking for calendar views
+// Вместо связей - название комнаты, имя создателя и число участников (выбираются одним запросом)
+type BookingSummary struct {
+	ID                uint          `json:"id"`
+	RoomID            uint          `json:"room_id"`
+	RoomName          string        `json:"room_name"`
+	Title             string        `json:"title"`
+	StartTime         time.Time     `json:"start_time"`
+	EndTime           time.Time     `json:"end_time"`
+	Status            BookingStatus `json:"status"`
+	IsJoinable        bool          `json:"is_joinable"`
+	CreatorID         uint          `json:"creator_id"`
+	CreatorFirstName  string        `json:"creator_first_name"`
+	CreatorLastName   string        `json:"creator_last_name"`
+	CreatorTelegramID int64         `json:"creator_telegram_id,omitempty"` // Для упоминаний в сообщениях бота
+	CreatorUsername   string        `json:"creator_username,omitempty"`
+	ParticipantCount  int           `json:"participant_count"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// BookingCheckIn records that a participant arrived for a booking (scanned the room's QR code)
+// Одна отметка на участника бронирования; повторное сканирование её не меняет
+type BookingCheckIn struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	BookingID   uint      `gorm:"not null;uniqueIndex:idx_booking_check_ins_booking_user" json:"booking_id"`
+	UserID      uint      `gorm:"not null;uniqueIndex:idx_booking_check_ins_booking_user;index" json:"user_id"`
+	CheckedInAt time.Time `gorm:"not null" json:"checked_in_at"`
+
+	// Связи
+	Booking Booking `gorm:"foreignKey:BookingID" json:"-"`
+	User    User    `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// TableName specifies the table name for BookingCheckIn
+func (BookingCheckIn) TableName() string {
+	return "booking_check_ins"
+}
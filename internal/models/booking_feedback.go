@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Границы оценки комнаты
+const (
+	MinFeedbackRating = 1
+	MaxFeedbackRating = 5
+)
+
+// BookingFeedback is a participant's rating of the room and its equipment after a booking
+// Один отзыв на участника бронирования; повторная отправка заменяет оценку и комментарий
+type BookingFeedback struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	BookingID uint      `gorm:"not null;uniqueIndex:idx_booking_feedback_booking_user" json:"booking_id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_booking_feedback_booking_user;index" json:"user_id"`
+	RoomID    uint      `gorm:"not null;index" json:"room_id"` // Копия room_id бронирования для отчёта по комнатам
+	Rating    int       `gorm:"not null" json:"rating"`        // 1-5
+	Comment   string    `gorm:"type:text" json:"comment,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Связи
+	Booking Booking `gorm:"foreignKey:BookingID" json:"-"`
+	User    User    `gorm:"foreignKey:UserID" json:"-"`
+	Room    Room    `gorm:"foreignKey:RoomID" json:"-"`
+}
+
+// TableName specifies the table name for BookingFeedback
+func (BookingFeedback) TableName() string {
+	return "booking_feedback"
+}
+
+// RoomRating is the aggregated feedback rating of a room
+type RoomRating struct {
+	Average float64 `json:"average"` // Средняя оценка, округлённая до десятых
+	Count   int     `json:"count"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// BookingHistoryEventType определяет переход бронирования в его жизненном цикле
+type BookingHistoryEventType string
+
+const (
+	BookingHistoryCreated   BookingHistoryEventType = "created"   // Бронирование создано (или импортировано)
+	BookingHistoryUpdated   BookingHistoryEventType = "updated"   // Изменены время или описание
+	BookingHistoryCancelled BookingHistoryEventType = "cancelled" // Отменено создателем, администратором или при уходе пользователя
+	BookingHistoryJoined    BookingHistoryEventType = "joined"    // Участник присоединился
+	BookingHistoryLeft      BookingHistoryEventType = "left"      // Участник покинул бронирование
+	BookingHistoryCompleted BookingHistoryEventType = "completed" // Закончилось по времени или комната освобождена досрочно
+)
+
+// BookingHistoryEvent is an immutable record of a booking state transition
+// Поток событий бронирования упорядочен по ID; записи не изменяются и удаляются только вместе с бронированием
+type BookingHistoryEvent struct {
+	ID        uint                    `gorm:"primaryKey" json:"id"` // Позиция в общем потоке событий
+	BookingID uint                    `gorm:"not null;index" json:"booking_id"`
+	Type      BookingHistoryEventType `gorm:"type:varchar(20);not null" json:"type"`
+	ActorID   *uint                   `json:"actor_id,omitempty"`                  // Кто выполнил переход; пусто - система
+	Data      datatypes.JSON          `json:"data,omitempty" swaggertype:"object"` // BookingHistoryData
+	CreatedAt time.Time               `gorm:"not null;index" json:"created_at"`
+
+	// Связи
+	Booking Booking `gorm:"foreignKey:BookingID" json:"-"`
+}
+
+// TableName specifies the table name for BookingHistoryEvent
+func (BookingHistoryEvent) TableName() string {
+	return "booking_events"
+}
+
+// BookingHistoryData is the payload of a booking history event
+// created несёт всё состояние бронирования, updated - редактируемые поля после изменения,
+// joined и left - участника, completed - фактическое время окончания
+type BookingHistoryData struct {
+	RoomID                *uint      `json:"room_id,omitempty"`
+	CreatorID             *uint      `json:"creator_id,omitempty"`
+	StartTime             *time.Time `json:"start_time,omitempty"`
+	EndTime               *time.Time `json:"end_time,omitempty"`
+	Title                 *string    `json:"title,omitempty"`
+	Description           *string    `json:"description,omitempty"`
+	EstimatedParticipants *int       `json:"estimated_participants,omitempty"`
+	IsJoinable            *bool      `json:"is_joinable,omitempty"`
+	MaxParticipants       *int       `json:"max_participants,omitempty"`
+	ParticipantIDs        []uint     `json:"participant_ids,omitempty"`
+	UserID                *uint      `json:"user_id,omitempty"`
+}
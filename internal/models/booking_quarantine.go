@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// QuarantineStatus определяет состояние задержанной рассылки в очереди проверки
+type QuarantineStatus string
+
+const (
+	QuarantinePending  QuarantineStatus = "pending"  // Рассылка задержана до проверки администратором
+	QuarantineReleased QuarantineStatus = "released" // Ложное срабатывание: событие разослано
+	QuarantineRejected QuarantineStatus = "rejected" // Событие не разослано; бронирование остаётся, отменяется отдельно
+)
+
+// BookingQuarantine is a booking event held back from room subscribers because the content filter
+// flagged the booking's title or description
+// У бронирования не больше одной записи в ожидании: события, пришедшие до проверки, объединяются с ней
+type BookingQuarantine struct {
+	ID           uint             `gorm:"primaryKey" json:"id"`
+	BookingID    uint             `gorm:"not null;index;uniqueIndex:idx_booking_quarantines_pending,where:status = 'pending'" json:"booking_id"`
+	Event        string           `gorm:"type:varchar(40);not null" json:"event"`       // Задержанное событие: booking.created или booking.updated
+	Changes      datatypes.JSON   `json:"changes,omitempty" swaggertype:"array,object"` // Изменения для booking.updated
+	Violations   datatypes.JSON   `json:"violations" swaggertype:"array,object"`        // [{"field":"title","rule":"wordlist","match":"..."}]
+	Status       QuarantineStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	ReviewedByID *uint            `json:"reviewed_by_id,omitempty"`
+	ReviewedAt   *time.Time       `json:"reviewed_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Связи
+	Booking Booking `gorm:"foreignKey:BookingID" json:"booking,omitempty"`
+}
+
+// TableName specifies the table name for BookingQuarantine
+func (BookingQuarantine) TableName() string {
+	return "booking_quarantines"
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// BookingWaitlistEntry is a user waiting for a seat at a full joinable booking
+// Очередь на место в мероприятии, а не на время комнаты: когда участник уходит,
+// первый в очереди становится участником. Один пользователь - одна запись на бронирование
+type BookingWaitlistEntry struct {
+	ID        uint      `gorm:"primaryKey" json:"id"` // Порядок очереди
+	BookingID uint      `gorm:"not null;uniqueIndex:idx_booking_waitlist_booking_user" json:"booking_id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_booking_waitlist_booking_user;index" json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Связи
+	Booking Booking `gorm:"foreignKey:BookingID" json:"-"`
+	User    User    `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// TableName specifies the table name for BookingWaitlistEntry
+func (BookingWaitlistEntry) TableName() string {
+	return "booking_waitlist"
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// CleaningTaskStatus определяет состояние задачи уборки
+type CleaningTaskStatus string
+
+const (
+	CleaningTaskOpen CleaningTaskStatus = "open" // Ждёт уборки
+	CleaningTaskDone CleaningTaskStatus = "done" // Комната убрана
+)
+
+// CleaningTask is a room reset generated after bookings of a room flagged as reset_required
+type CleaningTask struct {
+	ID            uint               `gorm:"primaryKey" json:"id"`
+	RoomID        uint               `gorm:"not null;index" json:"room_id"`
+	BookingID     *uint              `json:"booking_id,omitempty"`          // Последнее бронирование перед уборкой
+	UsageMinutes  int                `gorm:"not null" json:"usage_minutes"` // Использование комнаты с предыдущей уборки
+	Status        CleaningTaskStatus `gorm:"type:varchar(20);not null;default:'open';index" json:"status"`
+	CompletedByID *uint              `json:"completed_by_id,omitempty"`
+	CompletedAt   *time.Time         `json:"completed_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Связи
+	Room        Room     `gorm:"foreignKey:RoomID" json:"room,omitempty"`
+	Booking     *Booking `gorm:"foreignKey:BookingID" json:"-"`
+	CompletedBy *User    `gorm:"foreignKey:CompletedByID" json:"-"`
+}
+
+// TableName specifies the table name for CleaningTask
+func (CleaningTask) TableName() string {
+	return "cleaning_tasks"
+}
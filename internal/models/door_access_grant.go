@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// DoorAccessGrant is door access issued to a booking's creator and participants
+// Строка создаётся до вызова драйвера (уникальность booking_id не даёт двум репликам выдать доступ дважды)
+type DoorAccessGrant struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	BookingID  uint       `gorm:"not null;uniqueIndex" json:"booking_id"`
+	DoorID     string     `gorm:"type:varchar(100);not null" json:"door_id"`
+	ExternalID string     `gorm:"type:varchar(255)" json:"-"`            // Идентификатор доступа у драйвера
+	PIN        string     `gorm:"type:varchar(32)" json:"pin,omitempty"` // Код замка, если драйвер его выдал
+	ValidFrom  time.Time  `gorm:"not null" json:"valid_from"`
+	ValidUntil time.Time  `gorm:"not null;index" json:"valid_until"`
+	GrantedAt  *time.Time `json:"granted_at,omitempty"` // nil - драйвер ещё не подтвердил выдачу
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Связи
+	Booking Booking `gorm:"foreignKey:BookingID" json:"-"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Floor is a floor of the space with its plan image; rooms and parking spots are placed on the plan by coordinates
+type Floor struct {
+	ID    uint   `gorm:"primaryKey" json:"id"`
+	Name  string `gorm:"type:varchar(255);uniqueIndex;not null" json:"name"` // Например "2 этаж"
+	Level int    `gorm:"not null;default:0" json:"level"`                    // Порядок этажей в Mini App
+
+	// План этажа хранится файлом в STORAGE_PATH/floors; отдаётся через /api/floors/:id/image
+	ImagePath      string     `gorm:"type:varchar(255);not null;default:''" json:"-"`
+	ImageType      string     `gorm:"type:varchar(50);not null;default:''" json:"-"` // MIME-тип изображения
+	ImageUpdatedAt *time.Time `json:"image_updated_at,omitempty"`                    // Пусто - план не загружен
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for Floor
+func (Floor) TableName() string {
+	return "floors"
+}
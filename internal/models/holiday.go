@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// HolidayDateLayout - формат даты нерабочего дня
+const HolidayDateLayout = "2006-01-02"
+
+// Источники нерабочих дней
+const (
+	HolidaySourceManual = "manual" // Добавлен администратором
+	HolidaySourceImport = "import" // Импортирован из API государственных праздников
+)
+
+// Holiday is a day the space is closed; bookings on it are rejected
+// Дата хранится строкой YYYY-MM-DD и относится к часовому поясу пространства (OFFICE_TIMEZONE)
+type Holiday struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Date      string    `gorm:"type:varchar(10);uniqueIndex;not null" json:"date"`
+	Name      string    `gorm:"type:varchar(255);not null" json:"name"`
+	Source    string    `gorm:"type:varchar(20);not null;default:manual" json:"source"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for Holiday
+func (Holiday) TableName() string {
+	return "holidays"
+}
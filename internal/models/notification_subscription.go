@@ -23,3 +23,11 @@ type NotificationSubscription struct {
 func (NotificationSubscription) TableName() string {
 	return "notification_subscriptions"
 }
+
+// RoomSubscriber is the part of a subscribed user needed to notify them in Telegram
+type RoomSubscriber struct {
+	UserID     uint
+	TelegramID int64
+	Username   string
+	FirstName  string
+}
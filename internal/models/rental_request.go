@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// RentalRequestStatus определяет состояние заявки в очереди модерации
+type RentalRequestStatus string
+
+const (
+	RentalRequestPending  RentalRequestStatus = "pending"  // Ждёт решения администратора
+	RentalRequestApproved RentalRequestStatus = "approved" // Одобрена, создано бронирование
+	RentalRequestRejected RentalRequestStatus = "rejected" // Отклонена
+)
+
+// RentalRequest is a request of an outside party to rent a room, submitted through the public form
+// Бронирование создаётся только после одобрения администратором; заявитель узнаёт о решении по email
+type RentalRequest struct {
+	ID     uint `gorm:"primaryKey" json:"id"`
+	RoomID uint `gorm:"not null;index" json:"room_id"`
+
+	// Контакты заявителя - не пользователя пространства
+	Name         string `gorm:"not null" json:"name"`
+	Email        string `gorm:"not null" json:"email"`
+	Phone        string `gorm:"not null;default:''" json:"phone"`
+	Organization string `gorm:"not null;default:''" json:"organization"`
+	Language     string `gorm:"type:varchar(8);not null;default:''" json:"language"` // Язык писем заявителю
+
+	// Мероприятие
+	Title        string    `gorm:"not null" json:"title"`
+	Description  string    `gorm:"type:text;not null;default:''" json:"description"`
+	Participants int       `gorm:"not null;default:1" json:"participants"`
+	StartTime    time.Time `gorm:"not null" json:"start_time"`
+	EndTime      time.Time `gorm:"not null" json:"end_time"`
+
+	Status       RentalRequestStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	RejectReason string              `gorm:"type:text;not null;default:''" json:"reject_reason,omitempty"` // Отправляется заявителю
+	BookingID    *uint               `json:"booking_id,omitempty"`                                         // Бронирование одобренной заявки
+	ReviewedByID *uint               `json:"reviewed_by_id,omitempty"`
+	ReviewedAt   *time.Time          `json:"reviewed_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Связи
+	Room Room `gorm:"foreignKey:RoomID" json:"room,omitempty"`
+}
+
+// TableName specifies the table name for RentalRequest
+func (RentalRequest) TableName() string {
+	return "rental_requests"
+}
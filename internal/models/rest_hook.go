@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RESTHook is an event subscription of a no-code integration (Zapier, Make)
+// Подписка принадлежит API ключу: при отзыве ключа события перестают отправляться
+type RESTHook struct {
+	ID        uint   `gorm:"primaryKey" json:"id"`
+	APIKeyID  uint   `gorm:"not null;index" json:"api_key_id"`
+	Event     string `gorm:"type:varchar(50);not null;index" json:"event"`  // booking.created, booking.cancelled, booking.reminder, booking.released, booking.updated
+	TargetURL string `gorm:"type:varchar(1000);not null" json:"target_url"` // Куда отправлять события
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Связи
+	APIKey APIKey `gorm:"foreignKey:APIKeyID" json:"-"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Сущности, для которых можно задать срок хранения
+const (
+	RetentionEntityBookings  = "bookings"   // По окончанию бронирования; отменённые тоже
+	RetentionEntityAuditLogs = "audit_logs" // По времени записи
+)
+
+// RetentionEntities - все сущности, поддерживаемые правилами хранения
+var RetentionEntities = []string{RetentionEntityBookings, RetentionEntityAuditLogs}
+
+// RetentionRule tells the retention job to permanently delete rows of an entity older than MaxAgeDays
+type RetentionRule struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Entity     string    `gorm:"type:varchar(50);uniqueIndex;not null" json:"entity"`
+	MaxAgeDays int       `gorm:"not null" json:"max_age_days"`
+	Enabled    bool      `gorm:"not null;default:true" json:"enabled"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for RetentionRule
+func (RetentionRule) TableName() string {
+	return "retention_rules"
+}
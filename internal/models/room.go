@@ -7,7 +7,15 @@ import (
 	"gorm.io/gorm"
 )
 
-// Room represents a bookable room in the coworking space
+// RoomKind определяет вид бронируемого ресурса
+type RoomKind string
+
+const (
+	RoomKindRoom    RoomKind = "room"    // Переговорная, зал, рабочее место
+	RoomKindParking RoomKind = "parking" // Парковочное место: бронируется через /api/parking, не показывается в списках комнат
+)
+
+// Room represents a bookable resource in the coworking space: a room or a parking spot
 type Room struct {
 	ID          uint   `gorm:"primaryKey" json:"id"`
 	Name        string `gorm:"uniqueIndex;not null" json:"name"` // Название комнаты
@@ -15,9 +23,36 @@ type Room struct {
 	Capacity    int    `gorm:"default:1" json:"capacity"`        // Вместимость
 	IsActive    bool   `gorm:"default:true" json:"is_active"`    // Активна ли комната
 
+	Kind RoomKind `gorm:"type:varchar(20);not null;default:'room';index" json:"kind"` // room или parking
+
+	// Часовой пояс комнаты (IANA, например Asia/Novosibirsk); пусто - часовой пояс пространства (OFFICE_TIMEZONE)
+	Timezone string `gorm:"type:varchar(64);not null;default:''" json:"timezone"`
+
+	// Положение на плане этажа: доли ширины и высоты изображения (0..1) от левого верхнего угла
+	FloorID *uint    `gorm:"index" json:"floor_id,omitempty"`
+	MapX    *float64 `json:"map_x,omitempty"`
+	MapY    *float64 `json:"map_y,omitempty"`
+
 	// Дополнительные параметры в виде JSON
 	// Например: {"color": "#FF5733", "location": "2 этаж", "area_sqm": 25}
-	Attributes datatypes.JSON `json:"attributes,omitempty"`
+	Attributes datatypes.JSON `json:"attributes,omitempty" swaggertype:"object"`
+
+	BroadcastAt *time.Time `json:"-"` // Последнее объявление подписчикам (не чаще BROADCAST_MIN_INTERVAL)
+
+	// Уборка после использования
+	ResetRequired   bool       `gorm:"not null;default:false" json:"reset_required"` // После использования создаётся задача уборки
+	ResetAfterHours int        `gorm:"not null;default:0" json:"reset_after_hours"`  // Уборка после стольких часов использования (0 - после каждого бронирования)
+	NeedsCleaning   bool       `gorm:"not null;default:false" json:"needs_cleaning"` // Есть невыполненная задача уборки - комната не готова
+	CleaningCursor  *time.Time `json:"-"`                                            // Конец последнего учтённого бронирования
+	UsageMinutes    int        `gorm:"not null;default:0" json:"-"`                  // Использование с последней задачи уборки
+
+	// Незадолго до начала бронирования бот объявляет его в групповом чате (событие booking.starting)
+	AnnounceStart bool `gorm:"not null;default:false" json:"announce_start"`
+
+	// Цена часа в минимальных единицах валюты PRICE_CURRENCY (копейках); 0 - бесплатно
+	HourlyPrice int64 `gorm:"not null;default:0" json:"hourly_price"`
+
+	Rating *RoomRating `gorm:"-" json:"rating,omitempty"` // Оценка по отзывам участников; заполняется сервисом комнат
 
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
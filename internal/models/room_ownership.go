@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// RoomOwnership assigns a room to a team permanently (resident team of the coworking space)
+// Пока закрепление действует, бронировать комнату могут только участники команды
+// и те, кому они передали время (RoomDelegation)
+type RoomOwnership struct {
+	ID     uint       `gorm:"primaryKey" json:"id"`
+	RoomID uint       `gorm:"not null;uniqueIndex" json:"room_id"`
+	TeamID uint       `gorm:"not null;index" json:"team_id"`
+	Note   string     `gorm:"type:text;not null;default:''" json:"note"` // Например номер договора аренды
+	Until  *time.Time `json:"until,omitempty"`                           // Окончание закрепления; nil - бессрочно
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Связи
+	Room Room `gorm:"foreignKey:RoomID" json:"room,omitempty"`
+	Team Team `gorm:"foreignKey:TeamID" json:"team,omitempty"`
+}
+
+// TableName specifies the table name for RoomOwnership
+func (RoomOwnership) TableName() string {
+	return "room_ownerships"
+}
+
+// ActiveAt reports whether the ownership is in force at t
+func (o *RoomOwnership) ActiveAt(t time.Time) bool {
+	return o.Until == nil || t.Before(*o.Until)
+}
+
+// RoomDelegation lets a user outside the owning team book a dedicated room for a period
+// Выдаётся участником команды-владельца; бронирование должно целиком лежать в периоде
+type RoomDelegation struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	OwnershipID uint      `gorm:"not null;index" json:"ownership_id"`
+	UserID      uint      `gorm:"not null;index" json:"user_id"`
+	StartTime   time.Time `gorm:"not null" json:"start_time"`
+	EndTime     time.Time `gorm:"not null" json:"end_time"`
+	CreatedByID uint      `gorm:"not null" json:"created_by_id"`
+
+	CreatedAt time.Time `json:"created_at"`
+
+	// Связи
+	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// TableName specifies the table name for RoomDelegation
+func (RoomDelegation) TableName() string {
+	return "room_delegations"
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SlackTargetKind определяет способ доставки сообщений в Slack
+type SlackTargetKind string
+
+const (
+	SlackTargetWebhook SlackTargetKind = "webhook" // Incoming webhook: канал зашит в URL
+	SlackTargetBot     SlackTargetKind = "bot"     // Bot token + chat.postMessage в указанный канал
+)
+
+// SlackTarget is a Slack channel that receives booking notifications
+// RoomID nil - уведомления обо всех комнатах (весь workspace)
+type SlackTarget struct {
+	ID          uint            `gorm:"primaryKey" json:"id"`
+	Name        string          `gorm:"not null" json:"name"`
+	Kind        SlackTargetKind `gorm:"type:varchar(10);not null" json:"kind"`
+	RoomID      *uint           `gorm:"index" json:"room_id,omitempty"`
+	WebhookURL  string          `gorm:"type:varchar(500)" json:"-"` // Секрет: любой, кто знает URL, может писать в канал
+	BotToken    string          `gorm:"type:varchar(255)" json:"-"` // Секрет: xoxb-...
+	Channel     string          `gorm:"type:varchar(100)" json:"channel,omitempty"`
+	Events      string          `gorm:"type:varchar(255);not null" json:"events"` // События через запятую
+	CreatedByID uint            `gorm:"not null;index" json:"created_by_id"`
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Связи
+	Room      *Room `gorm:"foreignKey:RoomID" json:"-"`
+	CreatedBy User  `gorm:"foreignKey:CreatedByID" json:"-"`
+}
+
+// WantsEvent checks if the target is subscribed to the event
+func (t *SlackTarget) WantsEvent(event string) bool {
+	for _, e := range strings.Split(t.Events, ",") {
+		if strings.TrimSpace(e) == event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Team is a group of users (department, resident company)
+// Состав команд может вести HR-каталог через SCIM (Groups)
+type Team struct {
+	ID         uint    `gorm:"primaryKey" json:"id"`
+	Name       string  `gorm:"type:varchar(255);not null;index" json:"name"`
+	ExternalID *string `gorm:"type:varchar(255);uniqueIndex" json:"-"` // Идентификатор в каталоге (SCIM externalId)
+
+	IncludedHours *int `json:"included_hours,omitempty"` // Часов бронирований в месяц по тарифу (nil - не тарифицируется)
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Связи
+	Members []User `gorm:"many2many:team_members;" json:"members,omitempty"`
+}
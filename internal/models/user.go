@@ -17,7 +17,7 @@ const (
 // User represents a user in the system
 type User struct {
 	ID           uint           `gorm:"primaryKey" json:"id"`
-	TelegramID   int64          `gorm:"uniqueIndex;not null" json:"telegram_id"`
+	TelegramID   int64          `gorm:"uniqueIndex:idx_users_telegram_id,where:telegram_id <> 0;not null" json:"telegram_id"` // 0 - вход только через OIDC
 	Username     string         `gorm:"index" json:"username"`
 	FirstName    string         `json:"first_name,omitempty"`
 	LastName     string         `json:"last_name,omitempty"`
@@ -27,9 +27,22 @@ type User struct {
 	Userpic      string         `gorm:"type:varchar(500)" json:"userpic,omitempty"`        // URL профильной фотографии из Telegram
 	About        string         `gorm:"type:varchar(500)" json:"about,omitempty"`          // Описание/био пользователя
 
+	UserpicSyncedAt *time.Time `json:"-"` // Последний запрос userpic из Telegram (синхронизация не чаще USERPIC_SYNC_INTERVAL)
+
 	// Телефонная книга - пользователь показывается только если заполнены имя/фамилия и телефон
 	IsInPhoneBook bool `gorm:"default:false" json:"is_in_phonebook"`
 
+	// Вход через OIDC: email подтверждён провайдером, subject - идентификатор у провайдера
+	Email       *string `gorm:"type:varchar(320);uniqueIndex" json:"-"`
+	OIDCSubject *string `gorm:"column:oidc_subject;type:varchar(255);uniqueIndex" json:"-"`
+
+	// Каталог (SCIM): идентификатор в HR-системе и отключение ушедших сотрудников
+	ExternalID    *string    `gorm:"type:varchar(255);uniqueIndex" json:"-"`
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty"` // Отключённый пользователь не проходит авторизацию
+
+	// Максимум активных предстоящих бронирований (nil - без ограничения); снижается при злоупотреблениях
+	BookingLimit *int `json:"booking_limit,omitempty"`
+
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
@@ -44,6 +57,11 @@ func (u *User) IsAdmin() bool {
 	return u.Role == RoleAdmin
 }
 
+// IsDeactivated checks if the user was deactivated (left the organisation)
+func (u *User) IsDeactivated() bool {
+	return u.DeactivatedAt != nil
+}
+
 // BeforeSave hook для автоматической установки флага IsInPhoneBook
 func (u *User) BeforeSave(tx *gorm.DB) error {
 	// Пользователь попадает в телефонную книгу только если указал ФИО и телефон
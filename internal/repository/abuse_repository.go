@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UserCount is a number of matching rows of a user
+type UserCount struct {
+	UserID uint
+	Count  int
+}
+
+// AbuseRepository handles database queries of the booking abuse detector and its review queue
+type AbuseRepository struct {
+	db *gorm.DB
+}
+
+// NewAbuseRepository creates a new abuse repository
+func NewAbuseRepository(db *gorm.DB) *AbuseRepository {
+	return &AbuseRepository{db: db}
+}
+
+// CountCancellationsSince gets users who cancelled at least min bookings since the given time (read replica)
+// Отмена - мягкое удаление, поэтому время отмены - deleted_at; отключённые пользователи не учитываются
+func (r *AbuseRepository) CountCancellationsSince(ctx context.Context, since time.Time, min int) ([]UserCount, error) {
+	var counts []UserCount
+	err := onReplica(dbFromContext(ctx, r.db)).Unscoped().Model(&models.Booking{}).
+		Select("bookings.creator_id AS user_id, COUNT(*) AS count").
+		Joins("JOIN users ON users.id = bookings.creator_id AND users.deleted_at IS NULL AND users.deactivated_at IS NULL").
+		Where("bookings.deleted_at >= ?", since).
+		Group("bookings.creator_id").
+		Having("COUNT(*) >= ?", min).
+		Order("bookings.creator_id").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// GetActiveBookingSpans gets creator and time of active bookings starting in [start, end), ordered by creator and start (read replica)
+func (r *AbuseRepository) GetActiveBookingSpans(ctx context.Context, start, end time.Time) ([]models.Booking, error) {
+	var bookings []models.Booking
+	err := onReplica(dbFromContext(ctx, r.db)).
+		Select("id", "creator_id", "start_time", "end_time").
+		Where(activeBookingCondition+" AND start_time >= ? AND start_time < ?", start, end).
+		Order("creator_id, start_time").
+		Find(&bookings).Error
+	return bookings, err
+}
+
+// CreateFlag adds a flag to the review queue
+// Возвращает false, если у пользователя уже есть открытая отметка по той же причине
+func (r *AbuseRepository) CreateFlag(ctx context.Context, flag *models.AbuseFlag) (bool, error) {
+	result := dbFromContext(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(flag)
+	return result.RowsAffected == 1, result.Error
+}
+
+// ListFlags gets a page of flags with their users, newest first; empty status - all flags
+func (r *AbuseRepository) ListFlags(ctx context.Context, status models.AbuseFlagStatus, limit, offset int) ([]models.AbuseFlag, int64, error) {
+	query := dbFromContext(ctx, r.db).Model(&models.AbuseFlag{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var flags []models.AbuseFlag
+	err := query.Preload("User").Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&flags).Error
+	return flags, total, err
+}
+
+// GetFlag gets a flag with its user
+func (r *AbuseRepository) GetFlag(ctx context.Context, id uint) (*models.AbuseFlag, error) {
+	var flag models.AbuseFlag
+	err := dbFromContext(ctx, r.db).Preload("User").First(&flag, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &flag, nil
+}
+
+// ReviewFlag closes an open flag with the reviewer's decision
+// Возвращает false, если отметки нет или она уже рассмотрена
+func (r *AbuseRepository) ReviewFlag(ctx context.Context, id uint, status models.AbuseFlagStatus, reviewerID uint, now time.Time) (bool, error) {
+	result := dbFromContext(ctx, r.db).Model(&models.AbuseFlag{}).
+		Where("id = ? AND status = ?", id, models.AbuseFlagOpen).
+		Updates(map[string]interface{}{
+			"status":         status,
+			"reviewed_by_id": reviewerID,
+			"reviewed_at":    now,
+		})
+	return result.RowsAffected == 1, result.Error
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// APIKeyRepository handles database operations for API keys
+type APIKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository creates a new API key repository
+func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// Create creates a new API key
+func (r *APIKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	return dbFromContext(ctx, r.db).Create(key).Error
+}
+
+// GetBySecretHash gets an API key by the hash of its secret
+func (r *APIKeyRepository) GetBySecretHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	var key models.APIKey
+	err := dbFromContext(ctx, r.db).Where("secret_hash = ?", hash).First(&key).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// GetAll gets all API keys, newest first unless order is given
+func (r *APIKeyRepository) GetAll(ctx context.Context, order string) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	err := orderBy(dbFromContext(ctx, r.db), order, "created_at DESC").Find(&keys).Error
+	return keys, err
+}
+
+// TouchLastUsed updates last usage time of an API key
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id uint, at time.Time) error {
+	return dbFromContext(ctx, r.db).Model(&models.APIKey{}).Where("id = ?", id).UpdateColumn("last_used_at", at).Error
+}
+
+// Delete soft deletes (revokes) an API key
+func (r *APIKeyRepository) Delete(ctx context.Context, id uint) error {
+	result := dbFromContext(ctx, r.db).Delete(&models.APIKey{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
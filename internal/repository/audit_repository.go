@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// AuditFilter описывает фильтры выборки журнала аудита
+type AuditFilter struct {
+	ActorID  *uint
+	Method   string
+	Route    string
+	EntityID string
+	Status   int
+	From     *time.Time
+	To       *time.Time
+	Limit    int
+	Offset   int
+	Order    string // Проверенная сортировка из ?sort=, пусто - новые первыми
+}
+
+// AuditRepository handles database operations for audit logs
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository creates a new audit repository
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Create creates a new audit log entry
+func (r *AuditRepository) Create(ctx context.Context, entry *models.AuditLog) error {
+	return dbFromContext(ctx, r.db).Create(entry).Error
+}
+
+// List gets audit log entries matching the filter, newest first unless filter.Order is set
+func (r *AuditRepository) List(ctx context.Context, filter AuditFilter) ([]models.AuditLog, int64, error) {
+	query := dbFromContext(ctx, r.db).Model(&models.AuditLog{})
+
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.Method != "" {
+		query = query.Where("method = ?", filter.Method)
+	}
+	if filter.Route != "" {
+		query = query.Where("route = ?", filter.Route)
+	}
+	if filter.EntityID != "" {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.Status != 0 {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []models.AuditLog
+	err := orderBy(query, filter.Order, "created_at DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&entries).Error
+	return entries, total, err
+}
+
+// DeleteOlderThan permanently deletes audit log entries created before the cutoff
+func (r *AuditRepository) DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := dbFromContext(ctx, r.db).Where("created_at < ?", cutoff).Delete(&models.AuditLog{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// TeamMembership links a user to a team
+type TeamMembership struct {
+	TeamID uint
+	UserID uint
+}
+
+// BillingRepository handles database queries of team usage billing
+type BillingRepository struct {
+	db *gorm.DB
+}
+
+// NewBillingRepository creates a new billing repository
+func NewBillingRepository(db *gorm.DB) *BillingRepository {
+	return &BillingRepository{db: db}
+}
+
+// ListTeams gets all teams without members, by ID (read replica)
+func (r *BillingRepository) ListTeams(ctx context.Context) ([]models.Team, error) {
+	var teams []models.Team
+	err := onReplica(dbFromContext(ctx, r.db)).Order("id").Find(&teams).Error
+	return teams, err
+}
+
+// GetMemberships gets all team memberships (read replica)
+func (r *BillingRepository) GetMemberships(ctx context.Context) ([]TeamMembership, error) {
+	var memberships []TeamMembership
+	err := onReplica(dbFromContext(ctx, r.db)).Table("team_members").
+		Select("team_id, user_id").
+		Order("user_id, team_id").
+		Scan(&memberships).Error
+	return memberships, err
+}
+
+// GetEndedBookingSpans gets creator, time and estimated cost of active bookings overlapping [start, end) that ended by now (read replica)
+// Идущие и будущие бронирования не учитываются: счёт выставляется за фактически прошедшее время; парковка не тарифицируется
+func (r *BillingRepository) GetEndedBookingSpans(ctx context.Context, start, end, now time.Time) ([]models.Booking, error) {
+	var bookings []models.Booking
+	err := onReplica(dbFromContext(ctx, r.db)).
+		Select("id", "creator_id", "start_time", "end_time", "estimated_cost").
+		Where(activeBookingCondition+" AND "+roomBookingCondition+" AND start_time < ? AND end_time > ? AND end_time <= ?", end, start, now).
+		Order("start_time, id").
+		Find(&bookings).Error
+	return bookings, err
+}
+
+// GetUsers gets users by IDs (read replica)
+func (r *BillingRepository) GetUsers(ctx context.Context, ids []uint) ([]models.User, error) {
+	var users []models.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	err := onReplica(dbFromContext(ctx, r.db)).Where("id IN ?", ids).Find(&users).Error
+	return users, err
+}
+
+// SetIncludedHours sets the monthly included hours of a team; nil stops billing the team
+func (r *BillingRepository) SetIncludedHours(ctx context.Context, teamID uint, hours *int) error {
+	result := dbFromContext(ctx, r.db).Model(&models.Team{}).Where("id = ?", teamID).Update("included_hours", hours)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetTeam gets a team without members
+func (r *BillingRepository) GetTeam(ctx context.Context, id uint) (*models.Team, error) {
+	var team models.Team
+	if err := dbFromContext(ctx, r.db).First(&team, id).Error; err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
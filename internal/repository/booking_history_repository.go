@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/space/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BookingHistoryRepository handles database operations for the booking event stream
+// Поток только дополняется: методов изменения событий нет
+type BookingHistoryRepository struct {
+	db *gorm.DB
+}
+
+// NewBookingHistoryRepository creates a new booking history repository
+func NewBookingHistoryRepository(db *gorm.DB) *BookingHistoryRepository {
+	return &BookingHistoryRepository{db: db}
+}
+
+// Append adds an event to the stream; called in the transaction of the booking change
+func (r *BookingHistoryRepository) Append(ctx context.Context, event *models.BookingHistoryEvent) error {
+	return dbFromContext(ctx, r.db).Omit(clause.Associations).Create(event).Error
+}
+
+// ListByBooking gets the events of a booking in stream order
+func (r *BookingHistoryRepository) ListByBooking(ctx context.Context, bookingID uint) ([]models.BookingHistoryEvent, error) {
+	var events []models.BookingHistoryEvent
+	err := dbFromContext(ctx, r.db).
+		Where("booking_id = ?", bookingID).
+		Order("id").
+		Find(&events).Error
+	return events, err
+}
+
+// ListAfter gets up to limit events after the cursor in stream order
+func (r *BookingHistoryRepository) ListAfter(ctx context.Context, afterID uint, limit int) ([]models.BookingHistoryEvent, error) {
+	var events []models.BookingHistoryEvent
+	err := dbFromContext(ctx, r.db).
+		Where("id > ?", afterID).
+		Order("id").
+		Limit(limit).
+		Find(&events).Error
+	return events, err
+}
@@ -1,12 +1,22 @@
 package repository
 
 import (
+	"context"
 	"time"
 
 	"github.com/space/backend/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// activeBookingCondition совпадает с предикатом частичных индексов idx_bookings_active_*
+// (миграция 000002). Статус задан литералом: с параметром планировщик не может
+// доказать, что запрос подходит под частичный индекс
+const activeBookingCondition = "status <> 'cancelled'"
+
+// roomBookingCondition оставляет бронирования комнат: парковка не показывается в календарях и ленте ближайших встреч
+const roomBookingCondition = "room_id IN (SELECT id FROM rooms WHERE kind = 'room')"
+
 // BookingRepository handles database operations for bookings
 type BookingRepository struct {
 	db *gorm.DB
@@ -17,15 +27,17 @@ func NewBookingRepository(db *gorm.DB) *BookingRepository {
 	return &BookingRepository{db: db}
 }
 
-// Create creates a new booking
-func (r *BookingRepository) Create(booking *models.Booking) error {
-	return r.db.Create(booking).Error
+// Create creates a new booking and links its participants
+// Комната, создатель и участники уже существуют: GORM не переписывает их upsert'ом,
+// а только вставляет строки booking_participants
+func (r *BookingRepository) Create(ctx context.Context, booking *models.Booking) error {
+	return dbFromContext(ctx, r.db).Omit("Room", "Creator", "Participants.*").Create(booking).Error
 }
 
 // GetByID gets a booking by ID with all relations
-func (r *BookingRepository) GetByID(id uint) (*models.Booking, error) {
+func (r *BookingRepository) GetByID(ctx context.Context, id uint) (*models.Booking, error) {
 	var booking models.Booking
-	err := r.db.Preload("Room").
+	err := dbFromContext(ctx, r.db).Preload("Room").
 		Preload("Creator").
 		Preload("Participants").
 		First(&booking, id).Error
@@ -35,41 +47,65 @@ func (r *BookingRepository) GetByID(id uint) (*models.Booking, error) {
 	return &booking, nil
 }
 
-// GetByUserID gets all bookings for a user (created or participating)
-func (r *BookingRepository) GetByUserID(userID uint) ([]models.Booking, error) {
-	var bookings []models.Booking
+// GetByUserID gets a page of bookings for a user (created or participating), newest first
+// unless order is given, and the total number of such bookings
+func (r *BookingRepository) GetByUserID(ctx context.Context, userID uint, limit, offset int, order string) ([]models.Booking, int64, error) {
+	var total int64
+	if err := involvingUser(dbFromContext(ctx, r.db).Model(&models.Booking{}), userID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
 
-	// Получаем бронирования где пользователь - создатель или участник
-	err := r.db.Preload("Room").
+	var bookings []models.Booking
+	query := involvingUser(dbFromContext(ctx, r.db), userID).
+		Preload("Room").
 		Preload("Creator").
-		Preload("Participants").
-		Where("creator_id = ?", userID).
-		Or("id IN (SELECT booking_id FROM booking_participants WHERE user_id = ?)", userID).
-		Order("start_time DESC").
+		Preload("Participants")
+	err := orderBy(query, order, "start_time DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
 		Find(&bookings).Error
+	return bookings, total, err
+}
 
+// GetByUserInRange gets active bookings of a user (created or participating) overlapping a time range, earliest first
+func (r *BookingRepository) GetByUserInRange(ctx context.Context, userID uint, start, end time.Time) ([]models.Booking, error) {
+	var bookings []models.Booking
+	err := involvingUser(dbFromContext(ctx, r.db), userID).
+		Preload("Room").
+		Where(activeBookingCondition+" AND start_time < ? AND end_time > ?", end, start).
+		Order("start_time, id").
+		Find(&bookings).Error
 	return bookings, err
 }
 
-// GetByRoomAndTimeRange gets bookings for a room in a time range
-func (r *BookingRepository) GetByRoomAndTimeRange(roomID uint, start, end time.Time) ([]models.Booking, error) {
+// involvingUser ограничивает выборку бронированиями, где пользователь создатель или участник
+// OR собирается отдельной группой в скобках: условия, добавленные к запросу позже
+// (в том числе soft delete), применяются к обеим веткам, а не только к последней
+func involvingUser(db *gorm.DB, userID uint) *gorm.DB {
+	root := db.Session(&gorm.Session{NewDB: true})
+	participating := root.Table("booking_participants").Select("booking_id").Where("user_id = ?", userID)
+	return db.Where(root.Where("creator_id = ?", userID).Or("id IN (?)", participating))
+}
+
+// GetByRoomAndTimeRange gets bookings for a room in a time range (read replica)
+func (r *BookingRepository) GetByRoomAndTimeRange(ctx context.Context, roomID uint, start, end time.Time) ([]models.Booking, error) {
 	var bookings []models.Booking
-	err := r.db.Preload("Room").
+	err := onReplica(dbFromContext(ctx, r.db)).Preload("Room").
 		Preload("Creator").
 		Preload("Participants").
-		Where("room_id = ? AND status != ? AND start_time < ? AND end_time > ?",
-			roomID, models.BookingStatusCancelled, end, start).
+		Where(activeBookingCondition+" AND room_id = ? AND start_time < ? AND end_time > ?",
+			roomID, end, start).
 		Order("start_time").
 		Find(&bookings).Error
 	return bookings, err
 }
 
 // CheckConflict checks if there's a booking conflict
-func (r *BookingRepository) CheckConflict(roomID uint, start, end time.Time, excludeBookingID *uint) (bool, error) {
+func (r *BookingRepository) CheckConflict(ctx context.Context, roomID uint, start, end time.Time, excludeBookingID *uint) (bool, error) {
 	var count int64
-	query := r.db.Model(&models.Booking{}).
-		Where("room_id = ? AND status != ? AND start_time < ? AND end_time > ?",
-			roomID, models.BookingStatusCancelled, end, start)
+	query := dbFromContext(ctx, r.db).Model(&models.Booking{}).
+		Where(activeBookingCondition+" AND room_id = ? AND start_time < ? AND end_time > ?",
+			roomID, end, start)
 
 	// Исключаем конкретное бронирование (для обновления)
 	if excludeBookingID != nil {
@@ -81,13 +117,13 @@ func (r *BookingRepository) CheckConflict(roomID uint, start, end time.Time, exc
 }
 
 // GetConflictingBookings returns all bookings that conflict with the given time range
-func (r *BookingRepository) GetConflictingBookings(roomID uint, start, end time.Time, excludeBookingID *uint) ([]models.Booking, error) {
+func (r *BookingRepository) GetConflictingBookings(ctx context.Context, roomID uint, start, end time.Time, excludeBookingID *uint) ([]models.Booking, error) {
 	var bookings []models.Booking
-	query := r.db.Preload("Room").
+	query := dbFromContext(ctx, r.db).Preload("Room").
 		Preload("Creator").
 		Preload("Participants").
-		Where("room_id = ? AND status != ? AND start_time < ? AND end_time > ?",
-			roomID, models.BookingStatusCancelled, end, start)
+		Where(activeBookingCondition+" AND room_id = ? AND start_time < ? AND end_time > ?",
+			roomID, end, start)
 
 	// Исключаем конкретное бронирование (для обновления)
 	if excludeBookingID != nil {
@@ -99,14 +135,14 @@ func (r *BookingRepository) GetConflictingBookings(roomID uint, start, end time.
 }
 
 // GetUpcoming gets upcoming bookings
-func (r *BookingRepository) GetUpcoming(limit int) ([]models.Booking, error) {
+func (r *BookingRepository) GetUpcoming(ctx context.Context, limit int) ([]models.Booking, error) {
 	var bookings []models.Booking
 	now := time.Now()
 
-	err := r.db.Preload("Room").
+	err := dbFromContext(ctx, r.db).Preload("Room").
 		Preload("Creator").
 		Preload("Participants").
-		Where("start_time > ? AND status = ?", now, models.BookingStatusConfirmed).
+		Where("start_time > ? AND status = ? AND "+roomBookingCondition, now, models.BookingStatusConfirmed).
 		Order("start_time ASC").
 		Limit(limit).
 		Find(&bookings).Error
@@ -114,46 +150,266 @@ func (r *BookingRepository) GetUpcoming(limit int) ([]models.Booking, error) {
 	return bookings, err
 }
 
-// GetForCalendar gets all bookings in a time range for calendar view
-func (r *BookingRepository) GetForCalendar(start, end time.Time) ([]models.Booking, error) {
+// GetForCalendar gets all bookings in a time range for calendar view (read replica)
+func (r *BookingRepository) GetForCalendar(ctx context.Context, start, end time.Time) ([]models.Booking, error) {
 	var bookings []models.Booking
-	err := r.db.Preload("Room").
+	err := onReplica(dbFromContext(ctx, r.db)).Preload("Room").
 		Preload("Creator").
 		Preload("Participants").
-		Where("status != ? AND start_time < ? AND end_time > ?",
-			models.BookingStatusCancelled, end, start).
+		Where(activeBookingCondition+" AND "+roomBookingCondition+" AND start_time < ? AND end_time > ?",
+			end, start).
 		Order("start_time").
 		Find(&bookings).Error
 	return bookings, err
 }
 
-// Update updates a booking
-func (r *BookingRepository) Update(booking *models.Booking) error {
-	return r.db.Save(booking).Error
+// ExportBatches passes active bookings in a time range (of one room if roomID != 0) to fn
+// in batches of batchSize, ordered by start time (read replica).
+// Пачки читаются keyset-запросами после последней строки предыдущей пачки, поэтому
+// в памяти одновременно только одна пачка, а глубина выгрузки не замедляет запросы, как OFFSET
+func (r *BookingRepository) ExportBatches(ctx context.Context, start, end time.Time, roomID uint, batchSize int, fn func([]models.Booking) error) error {
+	var after *models.Booking
+	for {
+		query := onReplica(dbFromContext(ctx, r.db)).Preload("Room").
+			Preload("Creator").
+			Preload("Participants").
+			Where(activeBookingCondition+" AND start_time < ? AND end_time > ?", end, start)
+		if roomID != 0 {
+			query = query.Where("room_id = ?", roomID)
+		}
+		if after != nil {
+			query = query.Where("(start_time > ? OR (start_time = ? AND id > ?))", after.StartTime, after.StartTime, after.ID)
+		}
+
+		var batch []models.Booking
+		if err := query.Order("start_time, id").Limit(batchSize).Find(&batch).Error; err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if len(batch) < batchSize {
+			return nil
+		}
+		after = &batch[len(batch)-1]
+	}
+}
+
+// GetCalendarSummaries gets compact summaries of active bookings in a time range (read replica)
+// Одна выборка с JOIN и подзапросом вместо трёх Preload с полными объектами пользователей
+func (r *BookingRepository) GetCalendarSummaries(ctx context.Context, start, end time.Time) ([]models.BookingSummary, error) {
+	root := dbFromContext(ctx, r.db)
+	participantCount := root.Table("booking_participants").
+		Select("COUNT(*)").
+		Where("booking_participants.booking_id = bookings.id")
+
+	var summaries []models.BookingSummary
+	err := onReplica(root).Model(&models.Booking{}).
+		Select("bookings.id, bookings.room_id, rooms.name AS room_name, bookings.title, "+
+			"bookings.start_time, bookings.end_time, bookings.status, bookings.is_joinable, bookings.creator_id, "+
+			"users.first_name AS creator_first_name, users.last_name AS creator_last_name, "+
+			"users.telegram_id AS creator_telegram_id, users.username AS creator_username, "+
+			"(?) AS participant_count", participantCount).
+		Joins("LEFT JOIN rooms ON rooms.id = bookings.room_id").
+		Joins("LEFT JOIN users ON users.id = bookings.creator_id").
+		Where("bookings."+activeBookingCondition+" AND rooms.kind = ? AND bookings.start_time < ? AND bookings.end_time > ?", models.RoomKindRoom, end, start).
+		Order("bookings.start_time").
+		Scan(&summaries).Error
+	return summaries, err
+}
+
+// Update updates the editable columns of a booking; loaded relations are not saved
+// (участники меняются через AddParticipant и RemoveParticipant).
+// Полная перезапись строки через Save затёрла бы reminder_sent_at, отмеченный параллельно задачей напоминаний
+func (r *BookingRepository) Update(ctx context.Context, booking *models.Booking) error {
+	return dbFromContext(ctx, r.db).
+		Select("start_time", "end_time", "title", "description", "estimated_participants", "is_joinable", "max_participants", "estimated_cost").
+		Updates(booking).Error
 }
 
 // Delete soft deletes a booking
-func (r *BookingRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Booking{}, id).Error
+func (r *BookingRepository) Delete(ctx context.Context, id uint) error {
+	return dbFromContext(ctx, r.db).Delete(&models.Booking{}, id).Error
+}
+
+// GetDueForReminder gets active bookings starting in (from, to] that have not been reminded about yet
+func (r *BookingRepository) GetDueForReminder(ctx context.Context, from, to time.Time) ([]models.Booking, error) {
+	var bookings []models.Booking
+	err := dbFromContext(ctx, r.db).Preload("Room").
+		Preload("Creator").
+		Preload("Participants").
+		Where(activeBookingCondition+" AND reminder_sent_at IS NULL AND start_time > ? AND start_time <= ?", from, to).
+		Order("start_time").
+		Find(&bookings).Error
+	return bookings, err
+}
+
+// MarkReminderSent records that the reminder for a booking was sent
+// Возвращает false, если напоминание уже отметила другая реплика
+func (r *BookingRepository) MarkReminderSent(ctx context.Context, id uint, at time.Time) (bool, error) {
+	result := dbFromContext(ctx, r.db).Model(&models.Booking{}).
+		Where("id = ? AND reminder_sent_at IS NULL", id).
+		UpdateColumn("reminder_sent_at", at)
+	return result.RowsAffected == 1, result.Error
+}
+
+// GetCurrentByRoom gets the active booking of a room that is in progress at now
+func (r *BookingRepository) GetCurrentByRoom(ctx context.Context, roomID uint, now time.Time) (*models.Booking, error) {
+	var booking models.Booking
+	err := dbFromContext(ctx, r.db).Preload("Room").
+		Preload("Creator").
+		Preload("Participants").
+		Where(activeBookingCondition+" AND room_id = ? AND start_time < ? AND end_time > ?", roomID, now, now).
+		Order("start_time").
+		First(&booking).Error
+	if err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+// Release ends an active booking in progress at now: status completed, end_time = now
+// Возвращает false, если бронирование уже закончилось или отменено
+func (r *BookingRepository) Release(ctx context.Context, id uint, now time.Time) (bool, error) {
+	result := dbFromContext(ctx, r.db).Model(&models.Booking{}).
+		Where("id = ? AND "+activeBookingCondition+" AND start_time < ? AND end_time > ?", id, now, now).
+		Updates(map[string]interface{}{
+			"status":   models.BookingStatusCompleted,
+			"end_time": now,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+// ListByRoomKind gets active bookings in resources of a kind overlapping [start, end) (no upper bound if end is zero),
+// only of the creator if creatorID != 0, by start time
+func (r *BookingRepository) ListByRoomKind(ctx context.Context, kind models.RoomKind, creatorID uint, start, end time.Time) ([]models.Booking, error) {
+	query := dbFromContext(ctx, r.db).Preload("Room").
+		Where(activeBookingCondition+" AND room_id IN (SELECT id FROM rooms WHERE kind = ?) AND end_time > ?", kind, start)
+	if !end.IsZero() {
+		query = query.Where("start_time < ?", end)
+	}
+	if creatorID != 0 {
+		query = query.Where("creator_id = ?", creatorID)
+	}
+
+	var bookings []models.Booking
+	err := query.Order("start_time, id").Find(&bookings).Error
+	return bookings, err
+}
+
+// GetEndedConfirmed gets up to limit confirmed bookings that ended by now, oldest first
+func (r *BookingRepository) GetEndedConfirmed(ctx context.Context, now time.Time, limit int) ([]models.Booking, error) {
+	var bookings []models.Booking
+	err := dbFromContext(ctx, r.db).
+		Select("id", "room_id", "end_time", "status").
+		Where("status = ? AND end_time <= ?", models.BookingStatusConfirmed, now).
+		Order("end_time, id").
+		Limit(limit).
+		Find(&bookings).Error
+	return bookings, err
+}
+
+// Complete marks a confirmed booking that ended by now as completed
+// Возвращает false, если бронирование уже завершено, отменено или перенесено на будущее
+func (r *BookingRepository) Complete(ctx context.Context, id uint, now time.Time) (bool, error) {
+	result := dbFromContext(ctx, r.db).Model(&models.Booking{}).
+		Where("id = ? AND status = ? AND end_time <= ?", id, models.BookingStatusConfirmed, now).
+		UpdateColumn("status", models.BookingStatusCompleted)
+	return result.RowsAffected == 1, result.Error
 }
 
 // Cancel cancels a booking (soft delete - sets deleted_at timestamp)
-func (r *BookingRepository) Cancel(id uint) error {
-	return r.db.Delete(&models.Booking{}, id).Error
+func (r *BookingRepository) Cancel(ctx context.Context, id uint) error {
+	return dbFromContext(ctx, r.db).Delete(&models.Booking{}, id).Error
+}
+
+// CountUpcomingByCreator counts active bookings of a user that have not ended yet
+func (r *BookingRepository) CountUpcomingByCreator(ctx context.Context, userID uint, now time.Time) (int64, error) {
+	var count int64
+	err := dbFromContext(ctx, r.db).Model(&models.Booking{}).
+		Where(activeBookingCondition+" AND creator_id = ? AND end_time > ?", userID, now).
+		Count(&count).Error
+	return count, err
+}
+
+// GetFutureByCreator gets active bookings of a user that have not started yet
+func (r *BookingRepository) GetFutureByCreator(ctx context.Context, userID uint, now time.Time) ([]models.Booking, error) {
+	var bookings []models.Booking
+	err := dbFromContext(ctx, r.db).Preload("Room").
+		Preload("Creator").
+		Preload("Participants").
+		Where(activeBookingCondition+" AND creator_id = ? AND start_time > ?", userID, now).
+		Order("start_time").
+		Find(&bookings).Error
+	return bookings, err
+}
+
+// RemoveFromFutureBookings removes a user from participants of bookings that have not started yet
+// Возвращает ID бронирований, из которых пользователь удалён
+func (r *BookingRepository) RemoveFromFutureBookings(ctx context.Context, userID uint, now time.Time) ([]uint, error) {
+	var bookingIDs []uint
+	err := dbFromContext(ctx, r.db).
+		Raw("DELETE FROM booking_participants WHERE user_id = ? AND booking_id IN (SELECT id FROM bookings WHERE start_time > ?) RETURNING booking_id", userID, now).
+		Scan(&bookingIDs).Error
+	return bookingIDs, err
 }
 
 // AddParticipant adds a participant to a booking
-func (r *BookingRepository) AddParticipant(bookingID, userID uint) error {
-	return r.db.Exec(
-		"INSERT INTO booking_participants (booking_id, user_id) VALUES (?, ?) ON CONFLICT DO NOTHING",
-		bookingID, userID,
-	).Error
+// Возвращает false, если пользователь уже участвует
+func (r *BookingRepository) AddParticipant(ctx context.Context, bookingID, userID uint) (bool, error) {
+	// clause.OnConflict строится драйвером: ON CONFLICT DO NOTHING и в PostgreSQL, и в SQLite
+	result := dbFromContext(ctx, r.db).Table("booking_participants").
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(map[string]interface{}{"booking_id": bookingID, "user_id": userID})
+	return result.RowsAffected == 1, result.Error
 }
 
 // RemoveParticipant removes a participant from a booking
-func (r *BookingRepository) RemoveParticipant(bookingID, userID uint) error {
-	return r.db.Exec(
+// Возвращает false, если пользователь не участвовал
+func (r *BookingRepository) RemoveParticipant(ctx context.Context, bookingID, userID uint) (bool, error) {
+	result := dbFromContext(ctx, r.db).Exec(
 		"DELETE FROM booking_participants WHERE booking_id = ? AND user_id = ?",
 		bookingID, userID,
-	).Error
+	)
+	return result.RowsAffected > 0, result.Error
+}
+
+// CountParticipants counts the participants of a booking, not including the creator
+func (r *BookingRepository) CountParticipants(ctx context.Context, bookingID uint) (int64, error) {
+	var count int64
+	err := dbFromContext(ctx, r.db).Table("booking_participants").
+		Where("booking_id = ?", bookingID).
+		Count(&count).Error
+	return count, err
+}
+
+// GetWaitlist gets the seat waitlist of a booking in queue order with users
+func (r *BookingRepository) GetWaitlist(ctx context.Context, bookingID uint) ([]models.BookingWaitlistEntry, error) {
+	var entries []models.BookingWaitlistEntry
+	err := dbFromContext(ctx, r.db).Preload("User").
+		Where("booking_id = ?", bookingID).
+		Order("id").
+		Find(&entries).Error
+	return entries, err
+}
+
+// AddToWaitlist puts a user at the end of the seat waitlist of a booking
+// Возвращает false, если пользователь уже в очереди
+func (r *BookingRepository) AddToWaitlist(ctx context.Context, bookingID, userID uint) (bool, error) {
+	result := dbFromContext(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.BookingWaitlistEntry{BookingID: bookingID, UserID: userID})
+	return result.RowsAffected == 1, result.Error
+}
+
+// RemoveFromWaitlist removes a user from the seat waitlist of a booking
+// Возвращает false, если пользователя не было в очереди
+func (r *BookingRepository) RemoveFromWaitlist(ctx context.Context, bookingID, userID uint) (bool, error) {
+	result := dbFromContext(ctx, r.db).
+		Where("booking_id = ? AND user_id = ?", bookingID, userID).
+		Delete(&models.BookingWaitlistEntry{})
+	return result.RowsAffected > 0, result.Error
 }
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CheckInRepository handles database operations for booking check-ins
+type CheckInRepository struct {
+	db *gorm.DB
+}
+
+// NewCheckInRepository creates a new check-in repository
+func NewCheckInRepository(db *gorm.DB) *CheckInRepository {
+	return &CheckInRepository{db: db}
+}
+
+// FindCheckInBooking gets the confirmed booking of a room the user takes part in (creator or participant)
+// that has not ended at now and starts no later than opensAt, earliest first
+// Освобождённые (completed) и отменённые бронирования не подходят
+func (r *CheckInRepository) FindCheckInBooking(ctx context.Context, roomID, userID uint, now, opensAt time.Time) (*models.Booking, error) {
+	var booking models.Booking
+	err := involvingUser(dbFromContext(ctx, r.db), userID).Preload("Room").
+		Where("room_id = ? AND status = ? AND start_time <= ? AND end_time > ?", roomID, models.BookingStatusConfirmed, opensAt, now).
+		Order("start_time, id").
+		First(&booking).Error
+	if err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
+// CheckIn records a check-in; returns the existing one if the user has already checked in to the booking
+func (r *CheckInRepository) CheckIn(ctx context.Context, checkIn *models.BookingCheckIn) (bool, error) {
+	db := dbFromContext(ctx, r.db)
+	result := db.Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(checkIn)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return true, nil
+	}
+	return false, db.Where("booking_id = ? AND user_id = ?", checkIn.BookingID, checkIn.UserID).First(checkIn).Error
+}
+
+// BookingAttendance is the planned and observed attendance of a finished booking
+type BookingAttendance struct {
+	BookingID             uint
+	RoomID                uint
+	CreatorID             uint
+	EstimatedParticipants int
+	ParticipantCount      int // Присоединившиеся участники, без создателя
+	CheckInCount          int // Отметившиеся по QR-коду, включая создателя
+}
+
+// GetAttendance gets the attendance of room bookings that started in [from, to) and ended by to (read replica)
+// Отменённые бронирования и парковка не учитываются
+func (r *CheckInRepository) GetAttendance(ctx context.Context, from, to time.Time) ([]BookingAttendance, error) {
+	root := dbFromContext(ctx, r.db)
+	participantCount := root.Table("booking_participants").
+		Select("COUNT(*)").
+		Where("booking_participants.booking_id = bookings.id")
+	checkInCount := root.Table("booking_check_ins").
+		Select("COUNT(*)").
+		Where("booking_check_ins.booking_id = bookings.id")
+
+	var attendance []BookingAttendance
+	err := onReplica(root).Model(&models.Booking{}).
+		Select("bookings.id AS booking_id, bookings.room_id, bookings.creator_id, bookings.estimated_participants, "+
+			"(?) AS participant_count, (?) AS check_in_count", participantCount, checkInCount).
+		Joins("JOIN rooms ON rooms.id = bookings.room_id").
+		Where("bookings.status <> ? AND rooms.kind = ? AND bookings.start_time >= ? AND bookings.start_time < ? AND bookings.end_time <= ?",
+			models.BookingStatusCancelled, models.RoomKindRoom, from, to, to).
+		Order("bookings.id").
+		Scan(&attendance).Error
+	return attendance, err
+}
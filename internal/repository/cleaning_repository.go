@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// CleaningRepository handles database operations for room cleaning tasks
+type CleaningRepository struct {
+	db *gorm.DB
+}
+
+// NewCleaningRepository creates a new cleaning repository
+func NewCleaningRepository(db *gorm.DB) *CleaningRepository {
+	return &CleaningRepository{db: db}
+}
+
+// GetResetRoomIDs gets IDs of rooms that need cleaning after use
+func (r *CleaningRepository) GetResetRoomIDs(ctx context.Context) ([]uint, error) {
+	var ids []uint
+	err := dbFromContext(ctx, r.db).Model(&models.Room{}).
+		Where("reset_required = ?", true).
+		Order("id").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// GetEndedBookingSpans gets time of active bookings of a room that ended in (after, until], by end time
+func (r *CleaningRepository) GetEndedBookingSpans(ctx context.Context, roomID uint, after, until time.Time) ([]models.Booking, error) {
+	var bookings []models.Booking
+	err := dbFromContext(ctx, r.db).
+		Select("id", "room_id", "start_time", "end_time").
+		Where(activeBookingCondition+" AND room_id = ? AND end_time > ? AND end_time <= ?", roomID, after, until).
+		Order("end_time, id").
+		Find(&bookings).Error
+	return bookings, err
+}
+
+// SaveRoomProgress saves the booking cursor, accumulated usage and readiness of a room
+func (r *CleaningRepository) SaveRoomProgress(ctx context.Context, room *models.Room) error {
+	return dbFromContext(ctx, r.db).Model(&models.Room{}).
+		Where("id = ?", room.ID).
+		Updates(map[string]interface{}{
+			"cleaning_cursor": room.CleaningCursor,
+			"usage_minutes":   room.UsageMinutes,
+			"needs_cleaning":  room.NeedsCleaning,
+		}).Error
+}
+
+// CreateTask creates a cleaning task
+func (r *CleaningRepository) CreateTask(ctx context.Context, task *models.CleaningTask) error {
+	return dbFromContext(ctx, r.db).Omit("Room", "Booking", "CompletedBy").Create(task).Error
+}
+
+// ListTasks gets a page of tasks with their rooms, open ones oldest first and done ones newest first,
+// and the total number of such tasks; empty status - all tasks, zero roomID - all rooms
+func (r *CleaningRepository) ListTasks(ctx context.Context, status models.CleaningTaskStatus, roomID uint, limit, offset int) ([]models.CleaningTask, int64, error) {
+	query := dbFromContext(ctx, r.db).Model(&models.CleaningTask{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if roomID != 0 {
+		query = query.Where("room_id = ?", roomID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	order := "created_at, id"
+	if status == models.CleaningTaskDone {
+		order = "completed_at DESC, id DESC"
+	}
+	var tasks []models.CleaningTask
+	err := query.Preload("Room").Order(order).Limit(limit).Offset(offset).Find(&tasks).Error
+	return tasks, total, err
+}
+
+// GetTask gets a task with its room
+func (r *CleaningRepository) GetTask(ctx context.Context, id uint) (*models.CleaningTask, error) {
+	var task models.CleaningTask
+	if err := dbFromContext(ctx, r.db).Preload("Room").First(&task, id).Error; err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// CompleteTask marks an open task as done
+// Возвращает false, если задачи нет или она уже выполнена
+func (r *CleaningRepository) CompleteTask(ctx context.Context, id, userID uint, now time.Time) (bool, error) {
+	result := dbFromContext(ctx, r.db).Model(&models.CleaningTask{}).
+		Where("id = ? AND status = ?", id, models.CleaningTaskOpen).
+		Updates(map[string]interface{}{
+			"status":          models.CleaningTaskDone,
+			"completed_by_id": userID,
+			"completed_at":    now,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+// CountOpenTasks counts open tasks of a room
+func (r *CleaningRepository) CountOpenTasks(ctx context.Context, roomID uint) (int64, error) {
+	var count int64
+	err := dbFromContext(ctx, r.db).Model(&models.CleaningTask{}).
+		Where("room_id = ? AND status = ?", roomID, models.CleaningTaskOpen).
+		Count(&count).Error
+	return count, err
+}
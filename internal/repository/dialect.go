@@ -0,0 +1,20 @@
+package repository
+
+import "gorm.io/gorm"
+
+// Диалектно-зависимые фрагменты SQL: PostgreSQL в production, SQLite для разработки и тестов
+
+// isSQLite сообщает, работает ли соединение через драйвер SQLite
+func isSQLite(db *gorm.DB) bool {
+	return db.Dialector.Name() == "sqlite"
+}
+
+// ilike возвращает условие регистронезависимого поиска по шаблону для column
+// Шаблон экранируется validator.EscapeLike, поэтому escape-символ указан явно
+// В SQLite LIKE регистронезависим только для ASCII
+func ilike(db *gorm.DB, column string) string {
+	if isSQLite(db) {
+		return column + ` LIKE ? ESCAPE '\'`
+	}
+	return column + ` ILIKE ? ESCAPE '\'`
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DoorAccessRepository handles database operations for door access grants
+type DoorAccessRepository struct {
+	db *gorm.DB
+}
+
+// NewDoorAccessRepository creates a new door access repository
+func NewDoorAccessRepository(db *gorm.DB) *DoorAccessRepository {
+	return &DoorAccessRepository{db: db}
+}
+
+// GetBookingsToGrant gets active bookings overlapping [from, to) that have no grant yet
+func (r *DoorAccessRepository) GetBookingsToGrant(ctx context.Context, from, to time.Time) ([]models.Booking, error) {
+	var bookings []models.Booking
+	err := dbFromContext(ctx, r.db).Preload("Room").
+		Preload("Creator").
+		Preload("Participants").
+		Where(activeBookingCondition+" AND start_time < ? AND end_time > ?", to, from).
+		Where("NOT EXISTS (SELECT 1 FROM door_access_grants g WHERE g.booking_id = bookings.id)").
+		Order("start_time").
+		Find(&bookings).Error
+	return bookings, err
+}
+
+// Claim inserts a grant unless the booking already has one
+// Возвращает false, если доступ по бронированию уже выдаёт другая реплика
+func (r *DoorAccessRepository) Claim(ctx context.Context, grant *models.DoorAccessGrant) (bool, error) {
+	result := dbFromContext(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(grant)
+	return result.RowsAffected == 1, result.Error
+}
+
+// MarkGranted saves the credential issued for a claimed grant
+func (r *DoorAccessRepository) MarkGranted(ctx context.Context, grant *models.DoorAccessGrant) error {
+	return dbFromContext(ctx, r.db).Select("external_id", "pin", "granted_at").Updates(grant).Error
+}
+
+// Delete removes a grant so that it is claimed again on the next run
+func (r *DoorAccessRepository) Delete(ctx context.Context, id uint) error {
+	return dbFromContext(ctx, r.db).Delete(&models.DoorAccessGrant{}, id).Error
+}
+
+// GetByBookingID gets the grant of a booking
+func (r *DoorAccessRepository) GetByBookingID(ctx context.Context, bookingID uint) (*models.DoorAccessGrant, error) {
+	var grant models.DoorAccessGrant
+	if err := dbFromContext(ctx, r.db).Where("booking_id = ?", bookingID).First(&grant).Error; err != nil {
+		return nil, err
+	}
+	return &grant, nil
+}
+
+// GetExpired gets issued grants whose window has ended but that are not revoked yet
+func (r *DoorAccessRepository) GetExpired(ctx context.Context, now time.Time) ([]models.DoorAccessGrant, error) {
+	var grants []models.DoorAccessGrant
+	err := dbFromContext(ctx, r.db).
+		Where("granted_at IS NOT NULL AND revoked_at IS NULL AND valid_until <= ?", now).
+		Order("valid_until").
+		Find(&grants).Error
+	return grants, err
+}
+
+// MarkRevoked records revocation once; false if another replica already did
+func (r *DoorAccessRepository) MarkRevoked(ctx context.Context, id uint, at time.Time) (bool, error) {
+	result := dbFromContext(ctx, r.db).Model(&models.DoorAccessGrant{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		UpdateColumn("revoked_at", at)
+	return result.RowsAffected == 1, result.Error
+}
+
+// ClearRevoked undoes MarkRevoked when the driver failed, so the next run retries
+func (r *DoorAccessRepository) ClearRevoked(ctx context.Context, id uint) error {
+	return dbFromContext(ctx, r.db).Model(&models.DoorAccessGrant{}).
+		Where("id = ?", id).
+		UpdateColumn("revoked_at", nil).Error
+}
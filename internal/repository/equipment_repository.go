@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"github.com/space/backend/internal/models"
 	"gorm.io/gorm"
 )
@@ -16,14 +17,14 @@ func NewEquipmentRepository(db *gorm.DB) *EquipmentRepository {
 }
 
 // Create creates new equipment
-func (r *EquipmentRepository) Create(equipment *models.Equipment) error {
-	return r.db.Create(equipment).Error
+func (r *EquipmentRepository) Create(ctx context.Context, equipment *models.Equipment) error {
+	return dbFromContext(ctx, r.db).Create(equipment).Error
 }
 
 // GetByID gets equipment by ID with instructions
-func (r *EquipmentRepository) GetByID(id uint) (*models.Equipment, error) {
+func (r *EquipmentRepository) GetByID(ctx context.Context, id uint) (*models.Equipment, error) {
 	var equipment models.Equipment
-	err := r.db.Preload("Instructions", func(db *gorm.DB) *gorm.DB {
+	err := dbFromContext(ctx, r.db).Preload("Instructions", func(db *gorm.DB) *gorm.DB {
 		return db.Order("\"order\" ASC")
 	}).Preload("Room").First(&equipment, id).Error
 	if err != nil {
@@ -33,27 +34,27 @@ func (r *EquipmentRepository) GetByID(id uint) (*models.Equipment, error) {
 }
 
 // GetByRoomID gets all equipment for a specific room
-func (r *EquipmentRepository) GetByRoomID(roomID uint) ([]models.Equipment, error) {
+func (r *EquipmentRepository) GetByRoomID(ctx context.Context, roomID uint) ([]models.Equipment, error) {
 	var equipment []models.Equipment
-	err := r.db.Preload("Instructions", func(db *gorm.DB) *gorm.DB {
+	err := dbFromContext(ctx, r.db).Preload("Instructions", func(db *gorm.DB) *gorm.DB {
 		return db.Order("\"order\" ASC")
 	}).Where("room_id = ?", roomID).Order("name").Find(&equipment).Error
 	return equipment, err
 }
 
 // Update updates equipment
-func (r *EquipmentRepository) Update(equipment *models.Equipment) error {
-	return r.db.Save(equipment).Error
+func (r *EquipmentRepository) Update(ctx context.Context, equipment *models.Equipment) error {
+	return dbFromContext(ctx, r.db).Save(equipment).Error
 }
 
 // Delete soft deletes equipment
-func (r *EquipmentRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Equipment{}, id).Error
+func (r *EquipmentRepository) Delete(ctx context.Context, id uint) error {
+	return dbFromContext(ctx, r.db).Delete(&models.Equipment{}, id).Error
 }
 
 // GetAll gets all equipment
-func (r *EquipmentRepository) GetAll() ([]models.Equipment, error) {
+func (r *EquipmentRepository) GetAll(ctx context.Context) ([]models.Equipment, error) {
 	var equipment []models.Equipment
-	err := r.db.Preload("Room").Preload("Instructions").Order("name").Find(&equipment).Error
+	err := dbFromContext(ctx, r.db).Preload("Room").Preload("Instructions").Order("name").Find(&equipment).Error
 	return equipment, err
 }
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// lowFeedbackRating - оценка не выше считается жалобой на комнату
+const lowFeedbackRating = 2
+
+// RoomRatingStats is aggregated feedback of a room
+type RoomRatingStats struct {
+	RoomID   uint    `json:"room_id"`
+	RoomName string  `json:"room_name"`
+	Average  float64 `json:"average"`
+	Count    int     `json:"count"`
+	LowCount int     `json:"low_count"` // Оценок 1-2
+}
+
+// FeedbackRepository handles database operations for booking feedback
+type FeedbackRepository struct {
+	db *gorm.DB
+}
+
+// NewFeedbackRepository creates a new feedback repository
+func NewFeedbackRepository(db *gorm.DB) *FeedbackRepository {
+	return &FeedbackRepository{db: db}
+}
+
+// Upsert saves a participant's feedback; an existing one for the same booking is replaced
+func (r *FeedbackRepository) Upsert(ctx context.Context, feedback *models.BookingFeedback) error {
+	return dbFromContext(ctx, r.db).Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "booking_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rating", "comment", "updated_at"}),
+	}).Create(feedback).Error
+}
+
+// RoomRatingStats gets feedback aggregates of rooms that have feedback since the given time, worst rated first (read replica)
+// Нулевое время - за всё время; удалённые комнаты не учитываются
+func (r *FeedbackRepository) RoomRatingStats(ctx context.Context, since time.Time) ([]RoomRatingStats, error) {
+	var stats []RoomRatingStats
+	query := onReplica(dbFromContext(ctx, r.db)).Model(&models.BookingFeedback{}).
+		Select("booking_feedback.room_id, rooms.name AS room_name, AVG(booking_feedback.rating) AS average, "+
+			"COUNT(*) AS count, SUM(CASE WHEN booking_feedback.rating <= ? THEN 1 ELSE 0 END) AS low_count", lowFeedbackRating).
+		Joins("JOIN rooms ON rooms.id = booking_feedback.room_id AND rooms.deleted_at IS NULL")
+	if !since.IsZero() {
+		query = query.Where("booking_feedback.updated_at >= ?", since)
+	}
+	err := query.Group("booking_feedback.room_id, rooms.name").
+		Order("average, booking_feedback.room_id").
+		Scan(&stats).Error
+	return stats, err
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/space/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// FloorRepository stores floors and the placement of rooms on floor plans
+type FloorRepository struct {
+	db *gorm.DB
+}
+
+// NewFloorRepository creates a new floor repository
+func NewFloorRepository(db *gorm.DB) *FloorRepository {
+	return &FloorRepository{db: db}
+}
+
+// List gets all floors by level, then name
+func (r *FloorRepository) List(ctx context.Context) ([]models.Floor, error) {
+	var floors []models.Floor
+	err := dbFromContext(ctx, r.db).Order("level, name").Find(&floors).Error
+	return floors, err
+}
+
+// GetByID gets a floor by ID
+func (r *FloorRepository) GetByID(ctx context.Context, id uint) (*models.Floor, error) {
+	var floor models.Floor
+	if err := dbFromContext(ctx, r.db).First(&floor, id).Error; err != nil {
+		return nil, err
+	}
+	return &floor, nil
+}
+
+// Create creates a floor
+func (r *FloorRepository) Create(ctx context.Context, floor *models.Floor) error {
+	return dbFromContext(ctx, r.db).Create(floor).Error
+}
+
+// Update saves a floor
+func (r *FloorRepository) Update(ctx context.Context, floor *models.Floor) error {
+	return dbFromContext(ctx, r.db).Save(floor).Error
+}
+
+// Delete deletes a floor and removes its rooms from the plan
+// Комнаты открепляются явно: в SQLite (AutoMigrate) нет ON DELETE SET NULL миграции
+func (r *FloorRepository) Delete(ctx context.Context, id uint) error {
+	db := dbFromContext(ctx, r.db)
+	err := db.Model(&models.Room{}).Where("floor_id = ?", id).
+		Updates(map[string]interface{}{"floor_id": nil, "map_x": nil, "map_y": nil}).Error
+	if err != nil {
+		return err
+	}
+	result := db.Delete(&models.Floor{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetRooms gets active rooms and parking spots placed on a floor, by name
+func (r *FloorRepository) GetRooms(ctx context.Context, floorID uint) ([]models.Room, error) {
+	var rooms []models.Room
+	err := dbFromContext(ctx, r.db).
+		Where("floor_id = ? AND is_active = ?", floorID, true).
+		Order("name").
+		Find(&rooms).Error
+	return rooms, err
+}
+
+// SetRoomPlacement places a room on a floor plan; nil floorID removes it from the plan
+// Обновляются только колонки положения: параллельное редактирование комнаты не затирается
+func (r *FloorRepository) SetRoomPlacement(ctx context.Context, roomID uint, floorID *uint, x, y *float64) error {
+	result := dbFromContext(ctx, r.db).Model(&models.Room{}).Where("id = ?", roomID).
+		Updates(map[string]interface{}{"floor_id": floorID, "map_x": x, "map_y": y})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/space/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// HolidayRepository stores the closure calendar of the space
+type HolidayRepository struct {
+	db *gorm.DB
+}
+
+// NewHolidayRepository creates a new holiday repository
+func NewHolidayRepository(db *gorm.DB) *HolidayRepository {
+	return &HolidayRepository{db: db}
+}
+
+// ListBetween gets closure days from one date to another inclusive (YYYY-MM-DD), by date
+func (r *HolidayRepository) ListBetween(ctx context.Context, from, to string) ([]models.Holiday, error) {
+	var holidays []models.Holiday
+	err := dbFromContext(ctx, r.db).
+		Where("date >= ? AND date <= ?", from, to).
+		Order("date").
+		Find(&holidays).Error
+	return holidays, err
+}
+
+// GetByID gets a closure day by ID
+func (r *HolidayRepository) GetByID(ctx context.Context, id uint) (*models.Holiday, error) {
+	var holiday models.Holiday
+	if err := dbFromContext(ctx, r.db).First(&holiday, id).Error; err != nil {
+		return nil, err
+	}
+	return &holiday, nil
+}
+
+// Create adds a closure day; returns false if the date is already in the calendar
+func (r *HolidayRepository) Create(ctx context.Context, holiday *models.Holiday) (bool, error) {
+	result := dbFromContext(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "date"}},
+		DoNothing: true,
+	}).Create(holiday)
+	return result.RowsAffected == 1, result.Error
+}
+
+// Update saves the date and name of a closure day
+func (r *HolidayRepository) Update(ctx context.Context, holiday *models.Holiday) error {
+	return dbFromContext(ctx, r.db).Save(holiday).Error
+}
+
+// Delete deletes a closure day
+func (r *HolidayRepository) Delete(ctx context.Context, id uint) error {
+	result := dbFromContext(ctx, r.db).Delete(&models.Holiday{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Проверка планов запросов требует настоящего PostgreSQL:
+// TEST_DATABASE_URL=postgres://... go test ./internal/repository/ -run TestBookingIndexes
+// Объясняются запросы, которые строят сами методы репозитория, а не их копии:
+// изменение условия activeBookingCondition или порядка фильтров сразу проверяется планом
+func TestBookingIndexes_UsedByConflictQueries(t *testing.T) {
+	databaseURL := os.Getenv("TEST_DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	migrator, err := database.NewMigrator(databaseURL)
+	if err != nil {
+		t.Fatalf("Failed to create migrator: %v", err)
+	}
+	defer migrator.Close()
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("Failed to apply migrations: %v", err)
+	}
+
+	db, err := database.Connect(databaseURL, database.ConnectOptions{Backoff: time.Second})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer database.Close(db)
+
+	ctx := context.Background()
+	start := time.Now().UTC()
+	end := start.Add(time.Hour)
+	excluded := uint(7)
+
+	tests := []struct {
+		name  string
+		query func(r *BookingRepository) error
+		index string
+	}{
+		{
+			name: "room conflict check",
+			query: func(r *BookingRepository) error {
+				_, err := r.CheckConflict(ctx, 1, start, end, &excluded)
+				return err
+			},
+			index: "idx_bookings_active_room_time",
+		},
+		{
+			name: "conflicting bookings",
+			query: func(r *BookingRepository) error {
+				_, err := r.GetConflictingBookings(ctx, 1, start, end, nil)
+				return err
+			},
+			index: "idx_bookings_active_room_time",
+		},
+		{
+			name: "calendar range",
+			query: func(r *BookingRepository) error {
+				_, err := r.GetForCalendar(ctx, start, end)
+				return err
+			},
+			index: "idx_bookings_active_time",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := explain(t, db, captureSQL(t, db, tt.query))
+			if !strings.Contains(plan, tt.index) {
+				t.Errorf("Expected plan to use %s, got:\n%s", tt.index, plan)
+			}
+		})
+	}
+}
+
+// sqlCapture запоминает SQL запросов с подставленными параметрами
+type sqlCapture struct {
+	logger.Interface
+	statements []string
+}
+
+func (c *sqlCapture) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	sql, _ := fc()
+	c.statements = append(c.statements, sql)
+}
+
+// captureSQL возвращает первый запрос метода репозитория, собранный GORM без выполнения (DryRun)
+// Следующие запросы - preload связей - к индексам бронирований не относятся
+func captureSQL(t *testing.T, db *gorm.DB, query func(r *BookingRepository) error) string {
+	t.Helper()
+
+	capture := &sqlCapture{Interface: logger.Discard}
+	if err := query(NewBookingRepository(db.Session(&gorm.Session{DryRun: true, Logger: capture}))); err != nil {
+		t.Fatalf("Failed to build query: %v", err)
+	}
+	if len(capture.statements) == 0 {
+		t.Fatal("Expected the repository to build a query")
+	}
+	return capture.statements[0]
+}
+
+// explain возвращает план запроса; seq scan отключён, чтобы на пустой таблице
+// планировщик выбирал индекс, если он вообще применим
+func explain(t *testing.T, db *gorm.DB, query string) string {
+	t.Helper()
+
+	var lines []string
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SET LOCAL enable_seqscan = off").Error; err != nil {
+			return err
+		}
+		return tx.Raw("EXPLAIN " + query).Scan(&lines).Error
+	})
+	if err != nil {
+		t.Fatalf("Failed to explain query: %v", err)
+	}
+	return strings.Join(lines, "\n")
+}
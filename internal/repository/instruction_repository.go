@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"github.com/space/backend/internal/models"
 	"gorm.io/gorm"
 )
@@ -16,14 +17,14 @@ func NewInstructionRepository(db *gorm.DB) *InstructionRepository {
 }
 
 // Create creates a new instruction
-func (r *InstructionRepository) Create(instruction *models.Instruction) error {
-	return r.db.Create(instruction).Error
+func (r *InstructionRepository) Create(ctx context.Context, instruction *models.Instruction) error {
+	return dbFromContext(ctx, r.db).Create(instruction).Error
 }
 
 // GetByID gets an instruction by ID
-func (r *InstructionRepository) GetByID(id uint) (*models.Instruction, error) {
+func (r *InstructionRepository) GetByID(ctx context.Context, id uint) (*models.Instruction, error) {
 	var instruction models.Instruction
-	err := r.db.Preload("Equipment").Preload("Equipment.Room").First(&instruction, id).Error
+	err := dbFromContext(ctx, r.db).Preload("Equipment").Preload("Equipment.Room").First(&instruction, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -31,25 +32,25 @@ func (r *InstructionRepository) GetByID(id uint) (*models.Instruction, error) {
 }
 
 // GetByEquipmentID gets all instructions for specific equipment
-func (r *InstructionRepository) GetByEquipmentID(equipmentID uint) ([]models.Instruction, error) {
+func (r *InstructionRepository) GetByEquipmentID(ctx context.Context, equipmentID uint) ([]models.Instruction, error) {
 	var instructions []models.Instruction
-	err := r.db.Where("equipment_id = ?", equipmentID).Order("\"order\" ASC").Find(&instructions).Error
+	err := dbFromContext(ctx, r.db).Where("equipment_id = ?", equipmentID).Order("\"order\" ASC").Find(&instructions).Error
 	return instructions, err
 }
 
 // Update updates an instruction
-func (r *InstructionRepository) Update(instruction *models.Instruction) error {
-	return r.db.Save(instruction).Error
+func (r *InstructionRepository) Update(ctx context.Context, instruction *models.Instruction) error {
+	return dbFromContext(ctx, r.db).Save(instruction).Error
 }
 
 // Delete soft deletes an instruction
-func (r *InstructionRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Instruction{}, id).Error
+func (r *InstructionRepository) Delete(ctx context.Context, id uint) error {
+	return dbFromContext(ctx, r.db).Delete(&models.Instruction{}, id).Error
 }
 
 // GetAll gets all instructions
-func (r *InstructionRepository) GetAll() ([]models.Instruction, error) {
+func (r *InstructionRepository) GetAll(ctx context.Context) ([]models.Instruction, error) {
 	var instructions []models.Instruction
-	err := r.db.Preload("Equipment").Order("equipment_id, \"order\"").Find(&instructions).Error
+	err := dbFromContext(ctx, r.db).Preload("Equipment").Order("equipment_id, \"order\"").Find(&instructions).Error
 	return instructions, err
 }
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"github.com/space/backend/internal/models"
 	"gorm.io/gorm"
 )
@@ -14,10 +15,10 @@ func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
 }
 
 // Subscribe creates a subscription for a user to receive notifications about a room
-func (r *NotificationRepository) Subscribe(userID uint, roomID uint) error {
+func (r *NotificationRepository) Subscribe(ctx context.Context, userID uint, roomID uint) error {
 	// Проверяем что подписка не существует
 	var existing models.NotificationSubscription
-	err := r.db.Where("user_id = ? AND room_id = ?", userID, roomID).First(&existing).Error
+	err := dbFromContext(ctx, r.db).Where("user_id = ? AND room_id = ?", userID, roomID).First(&existing).Error
 
 	if err == nil {
 		// Подписка уже существует
@@ -34,33 +35,47 @@ func (r *NotificationRepository) Subscribe(userID uint, roomID uint) error {
 		RoomID: roomID,
 	}
 
-	return r.db.Create(&subscription).Error
+	return dbFromContext(ctx, r.db).Create(&subscription).Error
 }
 
 // Unsubscribe removes a subscription
-func (r *NotificationRepository) Unsubscribe(userID uint, roomID uint) error {
-	return r.db.Where("user_id = ? AND room_id = ?", userID, roomID).
+func (r *NotificationRepository) Unsubscribe(ctx context.Context, userID uint, roomID uint) error {
+	return dbFromContext(ctx, r.db).Where("user_id = ? AND room_id = ?", userID, roomID).
 		Delete(&models.NotificationSubscription{}).Error
 }
 
 // GetUserSubscriptions returns all rooms a user is subscribed to
-func (r *NotificationRepository) GetUserSubscriptions(userID uint) ([]models.NotificationSubscription, error) {
+func (r *NotificationRepository) GetUserSubscriptions(ctx context.Context, userID uint) ([]models.NotificationSubscription, error) {
 	var subscriptions []models.NotificationSubscription
-	err := r.db.Preload("Room").Where("user_id = ?", userID).Find(&subscriptions).Error
+	err := dbFromContext(ctx, r.db).Preload("Room").Where("user_id = ?", userID).Find(&subscriptions).Error
 	return subscriptions, err
 }
 
 // GetRoomSubscribers returns all users subscribed to a room
-func (r *NotificationRepository) GetRoomSubscribers(roomID uint) ([]models.NotificationSubscription, error) {
+func (r *NotificationRepository) GetRoomSubscribers(ctx context.Context, roomID uint) ([]models.NotificationSubscription, error) {
 	var subscriptions []models.NotificationSubscription
-	err := r.db.Preload("User").Where("room_id = ?", roomID).Find(&subscriptions).Error
+	err := dbFromContext(ctx, r.db).Preload("User").Where("room_id = ?", roomID).Find(&subscriptions).Error
 	return subscriptions, err
 }
 
+// GetRoomSubscriberContacts returns Telegram contacts of users subscribed to a room
+// Одна выборка нужных колонок с JOIN вместо подписок с полными объектами пользователей;
+// удалённые пользователи и пользователи без Telegram ID пропускаются
+func (r *NotificationRepository) GetRoomSubscriberContacts(ctx context.Context, roomID uint) ([]models.RoomSubscriber, error) {
+	var subscribers []models.RoomSubscriber
+	err := dbFromContext(ctx, r.db).Model(&models.NotificationSubscription{}).
+		Select("users.id AS user_id, users.telegram_id, users.username, users.first_name").
+		Joins("JOIN users ON users.id = notification_subscriptions.user_id AND users.deleted_at IS NULL").
+		Where("notification_subscriptions.room_id = ? AND users.telegram_id <> 0", roomID).
+		Order("notification_subscriptions.id").
+		Scan(&subscribers).Error
+	return subscribers, err
+}
+
 // IsSubscribed checks if a user is subscribed to a room
-func (r *NotificationRepository) IsSubscribed(userID uint, roomID uint) (bool, error) {
+func (r *NotificationRepository) IsSubscribed(ctx context.Context, userID uint, roomID uint) (bool, error) {
 	var count int64
-	err := r.db.Model(&models.NotificationSubscription{}).
+	err := dbFromContext(ctx, r.db).Model(&models.NotificationSubscription{}).
 		Where("user_id = ? AND room_id = ?", userID, roomID).
 		Count(&count).Error
 	return count > 0, err
@@ -0,0 +1,13 @@
+package repository
+
+import "gorm.io/gorm"
+
+// orderBy применяет сортировку из ?sort= или порядок по умолчанию
+// order приходит уже проверенным по белому списку (response.ParseSort); при явной
+// сортировке id добавляется последним, чтобы страницы не пересекались при равных значениях
+func orderBy(query *gorm.DB, order, fallback string) *gorm.DB {
+	if order == "" {
+		return query.Order(fallback)
+	}
+	return query.Order(order).Order("id")
+}
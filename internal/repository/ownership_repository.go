@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// OwnershipRepository stores rooms dedicated to teams and the slots their owners delegate
+type OwnershipRepository struct {
+	db *gorm.DB
+}
+
+// NewOwnershipRepository creates a new ownership repository
+func NewOwnershipRepository(db *gorm.DB) *OwnershipRepository {
+	return &OwnershipRepository{db: db}
+}
+
+// List gets all room ownerships with their rooms and teams, by room ID
+func (r *OwnershipRepository) List(ctx context.Context) ([]models.RoomOwnership, error) {
+	var ownerships []models.RoomOwnership
+	err := dbFromContext(ctx, r.db).Preload("Room").Preload("Team").Order("room_id").Find(&ownerships).Error
+	return ownerships, err
+}
+
+// GetActive gets ownerships in force at or after since, with their teams
+func (r *OwnershipRepository) GetActive(ctx context.Context, since time.Time) ([]models.RoomOwnership, error) {
+	var ownerships []models.RoomOwnership
+	err := dbFromContext(ctx, r.db).Preload("Team").
+		Where("until IS NULL OR until > ?", since).
+		Order("room_id").
+		Find(&ownerships).Error
+	return ownerships, err
+}
+
+// GetByID gets an ownership by ID with its room and team
+func (r *OwnershipRepository) GetByID(ctx context.Context, id uint) (*models.RoomOwnership, error) {
+	var ownership models.RoomOwnership
+	if err := dbFromContext(ctx, r.db).Preload("Room").Preload("Team").First(&ownership, id).Error; err != nil {
+		return nil, err
+	}
+	return &ownership, nil
+}
+
+// GetByRoomID gets the ownership of a room
+func (r *OwnershipRepository) GetByRoomID(ctx context.Context, roomID uint) (*models.RoomOwnership, error) {
+	var ownership models.RoomOwnership
+	if err := dbFromContext(ctx, r.db).Where("room_id = ?", roomID).First(&ownership).Error; err != nil {
+		return nil, err
+	}
+	return &ownership, nil
+}
+
+// Create creates an ownership
+func (r *OwnershipRepository) Create(ctx context.Context, ownership *models.RoomOwnership) error {
+	return dbFromContext(ctx, r.db).Omit("Room", "Team").Create(ownership).Error
+}
+
+// Update saves an ownership
+func (r *OwnershipRepository) Update(ctx context.Context, ownership *models.RoomOwnership) error {
+	return dbFromContext(ctx, r.db).Omit("Room", "Team").Save(ownership).Error
+}
+
+// Delete deletes an ownership and its delegations
+// Делегирования удаляются явно: в SQLite (AutoMigrate) нет ON DELETE CASCADE миграции
+func (r *OwnershipRepository) Delete(ctx context.Context, id uint) error {
+	db := dbFromContext(ctx, r.db)
+	if err := db.Where("ownership_id = ?", id).Delete(&models.RoomDelegation{}).Error; err != nil {
+		return err
+	}
+	result := db.Delete(&models.RoomOwnership{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// IsTeamMember checks if a user is a member of a team
+func (r *OwnershipRepository) IsTeamMember(ctx context.Context, teamID, userID uint) (bool, error) {
+	var count int64
+	err := dbFromContext(ctx, r.db).Table("team_members").
+		Where("team_id = ? AND user_id = ?", teamID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// HasDelegation checks if a user was delegated a slot of an ownership covering [start, end]
+func (r *OwnershipRepository) HasDelegation(ctx context.Context, ownershipID, userID uint, start, end time.Time) (bool, error) {
+	var count int64
+	err := dbFromContext(ctx, r.db).Model(&models.RoomDelegation{}).
+		Where("ownership_id = ? AND user_id = ? AND start_time <= ? AND end_time >= ?", ownershipID, userID, start, end).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ListDelegations gets delegations of an ownership that end after the given time, by start time
+func (r *OwnershipRepository) ListDelegations(ctx context.Context, ownershipID uint, after time.Time) ([]models.RoomDelegation, error) {
+	var delegations []models.RoomDelegation
+	err := dbFromContext(ctx, r.db).Preload("User").
+		Where("ownership_id = ? AND end_time > ?", ownershipID, after).
+		Order("start_time").
+		Find(&delegations).Error
+	return delegations, err
+}
+
+// CreateDelegation creates a delegation
+func (r *OwnershipRepository) CreateDelegation(ctx context.Context, delegation *models.RoomDelegation) error {
+	return dbFromContext(ctx, r.db).Omit("User").Create(delegation).Error
+}
+
+// DeleteDelegation deletes a delegation of an ownership
+func (r *OwnershipRepository) DeleteDelegation(ctx context.Context, ownershipID, id uint) error {
+	result := dbFromContext(ctx, r.db).Where("ownership_id = ?", ownershipID).Delete(&models.RoomDelegation{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// PurgeRepository permanently deletes soft-deleted rows past the retention window
+// Строки, на которые ещё ссылаются другие записи, пропускаются - их удаление нарушило бы FK
+type PurgeRepository struct {
+	db *gorm.DB
+}
+
+// NewPurgeRepository creates a new purge repository
+func NewPurgeRepository(db *gorm.DB) *PurgeRepository {
+	return &PurgeRepository{db: db}
+}
+
+// PurgeBookings hard-deletes bookings soft-deleted before the cutoff together with their participants
+// Бронирования с retention_exempt не удаляются, в том числе отменённые
+func (r *PurgeRepository) PurgeBookings(ctx context.Context, cutoff time.Time) (int64, error) {
+	db := dbFromContext(ctx, r.db)
+
+	expired := db.Unscoped().Model(&models.Booking{}).Select("id").Where("deleted_at < ? AND retention_exempt = ?", cutoff, false)
+	if err := deleteBookingDependents(db, expired); err != nil {
+		return 0, err
+	}
+
+	result := db.Unscoped().Where("deleted_at < ? AND retention_exempt = ?", cutoff, false).Delete(&models.Booking{})
+	return result.RowsAffected, result.Error
+}
+
+// deleteBookingDependents удаляет строки, ссылающиеся на бронирования из подзапроса ids, перед их удалением
+// Общий для окончательного удаления и правил хранения: новая дочерняя таблица бронирований добавляется здесь
+func deleteBookingDependents(db *gorm.DB, ids *gorm.DB) error {
+	for _, table := range []string{"booking_participants", "door_access_grants", "booking_feedback", "booking_check_ins", "booking_events"} {
+		if err := db.Exec("DELETE FROM "+table+" WHERE booking_id IN (?)", ids).Error; err != nil {
+			return err
+		}
+	}
+	// Задача уборки остаётся, но теряет ссылку на удаляемое бронирование
+	return db.Exec("UPDATE cleaning_tasks SET booking_id = NULL WHERE booking_id IN (?)", ids).Error
+}
+
+// PurgeRooms hard-deletes rooms soft-deleted before the cutoff that nothing references anymore
+func (r *PurgeRepository) PurgeRooms(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := dbFromContext(ctx, r.db).Unscoped().
+		Where("deleted_at < ?", cutoff).
+		Where("NOT EXISTS (SELECT 1 FROM bookings WHERE bookings.room_id = rooms.id)").
+		Where("NOT EXISTS (SELECT 1 FROM equipment WHERE equipment.room_id = rooms.id)").
+		Where("NOT EXISTS (SELECT 1 FROM notification_subscriptions ns WHERE ns.room_id = rooms.id)").
+		Where("NOT EXISTS (SELECT 1 FROM slack_targets st WHERE st.room_id = rooms.id)").
+		Where("NOT EXISTS (SELECT 1 FROM booking_feedback bf WHERE bf.room_id = rooms.id)").
+		Where("NOT EXISTS (SELECT 1 FROM cleaning_tasks ct WHERE ct.room_id = rooms.id)").
+		Delete(&models.Room{})
+	return result.RowsAffected, result.Error
+}
+
+// PurgeUsers hard-deletes users soft-deleted before the cutoff that nothing references anymore
+func (r *PurgeRepository) PurgeUsers(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := dbFromContext(ctx, r.db).Unscoped().
+		Where("deleted_at < ?", cutoff).
+		Where("NOT EXISTS (SELECT 1 FROM bookings WHERE bookings.creator_id = users.id)").
+		Where("NOT EXISTS (SELECT 1 FROM booking_participants bp WHERE bp.user_id = users.id)").
+		Where("NOT EXISTS (SELECT 1 FROM notification_subscriptions ns WHERE ns.user_id = users.id)").
+		Where("NOT EXISTS (SELECT 1 FROM api_keys WHERE api_keys.created_by_id = users.id)").
+		Where("NOT EXISTS (SELECT 1 FROM slack_targets st WHERE st.created_by_id = users.id)").
+		Where("NOT EXISTS (SELECT 1 FROM team_members tm WHERE tm.user_id = users.id)").
+		Where("NOT EXISTS (SELECT 1 FROM abuse_flags af WHERE af.user_id = users.id OR af.reviewed_by_id = users.id)").
+		Where("NOT EXISTS (SELECT 1 FROM booking_feedback bf WHERE bf.user_id = users.id)").
+		Where("NOT EXISTS (SELECT 1 FROM booking_check_ins ci WHERE ci.user_id = users.id)").
+		Where("NOT EXISTS (SELECT 1 FROM cleaning_tasks ct WHERE ct.completed_by_id = users.id)").
+		Delete(&models.User{})
+	return result.RowsAffected, result.Error
+}
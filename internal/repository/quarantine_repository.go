@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// QuarantineRepository handles database operations of booking events held by the content filter
+type QuarantineRepository struct {
+	db *gorm.DB
+}
+
+// NewQuarantineRepository creates a new quarantine repository
+func NewQuarantineRepository(db *gorm.DB) *QuarantineRepository {
+	return &QuarantineRepository{db: db}
+}
+
+// withBooking загружает бронирование с комнатой и создателем, в том числе отменённое
+func withBooking(db *gorm.DB) *gorm.DB {
+	return db.Preload("Booking", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Booking.Room").
+		Preload("Booking.Creator")
+}
+
+// List gets a page of held events with their bookings, newest first; empty status - all entries
+func (r *QuarantineRepository) List(ctx context.Context, status models.QuarantineStatus, limit, offset int) ([]models.BookingQuarantine, int64, error) {
+	query := dbFromContext(ctx, r.db).Model(&models.BookingQuarantine{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []models.BookingQuarantine
+	err := withBooking(query).Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&entries).Error
+	return entries, total, err
+}
+
+// GetByID gets a held event with its booking
+func (r *QuarantineRepository) GetByID(ctx context.Context, id uint) (*models.BookingQuarantine, error) {
+	var entry models.BookingQuarantine
+	if err := withBooking(dbFromContext(ctx, r.db)).First(&entry, id).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// GetPendingByBooking gets the entry of a booking awaiting review
+func (r *QuarantineRepository) GetPendingByBooking(ctx context.Context, bookingID uint) (*models.BookingQuarantine, error) {
+	var entry models.BookingQuarantine
+	err := dbFromContext(ctx, r.db).
+		Where("booking_id = ? AND status = ?", bookingID, models.QuarantinePending).
+		First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Create adds a held event to the review queue
+// Возвращает false, если у бронирования уже есть запись в ожидании
+func (r *QuarantineRepository) Create(ctx context.Context, entry *models.BookingQuarantine) (bool, error) {
+	result := dbFromContext(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(entry)
+	return result.RowsAffected == 1, result.Error
+}
+
+// UpdateHeld replaces the changes and violations of an entry that absorbed a later event
+func (r *QuarantineRepository) UpdateHeld(ctx context.Context, id uint, changes, violations datatypes.JSON) error {
+	return dbFromContext(ctx, r.db).Model(&models.BookingQuarantine{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"changes":    changes,
+			"violations": violations,
+		}).Error
+}
+
+// Review closes a pending entry with the reviewer's decision
+// Возвращает false, если записи нет или она уже рассмотрена
+func (r *QuarantineRepository) Review(ctx context.Context, id uint, status models.QuarantineStatus, reviewerID uint, now time.Time) (bool, error) {
+	result := dbFromContext(ctx, r.db).Model(&models.BookingQuarantine{}).
+		Where("id = ? AND status = ?", id, models.QuarantinePending).
+		Updates(map[string]interface{}{
+			"status":         status,
+			"reviewed_by_id": reviewerID,
+			"reviewed_at":    now,
+		})
+	return result.RowsAffected == 1, result.Error
+}
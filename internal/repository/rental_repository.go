@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// RentalRepository handles database operations of external rental requests
+type RentalRepository struct {
+	db *gorm.DB
+}
+
+// NewRentalRepository creates a new rental request repository
+func NewRentalRepository(db *gorm.DB) *RentalRepository {
+	return &RentalRepository{db: db}
+}
+
+// List gets a page of requests with their rooms, newest first; empty status - all requests
+func (r *RentalRepository) List(ctx context.Context, status models.RentalRequestStatus, limit, offset int) ([]models.RentalRequest, int64, error) {
+	query := dbFromContext(ctx, r.db).Model(&models.RentalRequest{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var requests []models.RentalRequest
+	err := query.Preload("Room").Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&requests).Error
+	return requests, total, err
+}
+
+// GetByID gets a request with its room
+func (r *RentalRepository) GetByID(ctx context.Context, id uint) (*models.RentalRequest, error) {
+	var request models.RentalRequest
+	if err := dbFromContext(ctx, r.db).Preload("Room").First(&request, id).Error; err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// Create adds a request to the moderation queue
+func (r *RentalRepository) Create(ctx context.Context, request *models.RentalRequest) error {
+	return dbFromContext(ctx, r.db).Create(request).Error
+}
+
+// Review closes a pending request with the reviewer's decision
+// Возвращает false, если заявки нет или она уже рассмотрена
+func (r *RentalRepository) Review(ctx context.Context, id uint, status models.RentalRequestStatus, reason string, reviewerID uint, now time.Time) (bool, error) {
+	result := dbFromContext(ctx, r.db).Model(&models.RentalRequest{}).
+		Where("id = ? AND status = ?", id, models.RentalRequestPending).
+		Updates(map[string]interface{}{
+			"status":         status,
+			"reject_reason":  reason,
+			"reviewed_by_id": reviewerID,
+			"reviewed_at":    now,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+// SetBooking links an approved request to the booking created for it
+func (r *RentalRepository) SetBooking(ctx context.Context, id, bookingID uint) error {
+	return dbFromContext(ctx, r.db).Model(&models.RentalRequest{}).Where("id = ?", id).Update("booking_id", bookingID).Error
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"github.com/space/backend/internal/database"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// onReplica направляет чтение на реплику (DATABASE_REPLICA_URL), если она настроена
+// Preload-запросы наследуют соединение реплики; внутри транзакции запрос остаётся на основной БД
+// Только для чтений, где допустимо небольшое отставание реплики
+func onReplica(db *gorm.DB) *gorm.DB {
+	return db.Clauses(dbresolver.Use(database.ReplicaResolver))
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// RESTHookRepository handles database operations for REST hook subscriptions
+type RESTHookRepository struct {
+	db *gorm.DB
+}
+
+// NewRESTHookRepository creates a new REST hook repository
+func NewRESTHookRepository(db *gorm.DB) *RESTHookRepository {
+	return &RESTHookRepository{db: db}
+}
+
+// Create creates a new REST hook subscription
+func (r *RESTHookRepository) Create(ctx context.Context, hook *models.RESTHook) error {
+	return dbFromContext(ctx, r.db).Create(hook).Error
+}
+
+// GetByAPIKey gets REST hooks of an API key
+func (r *RESTHookRepository) GetByAPIKey(ctx context.Context, apiKeyID uint) ([]models.RESTHook, error) {
+	var hooks []models.RESTHook
+	err := dbFromContext(ctx, r.db).Where("api_key_id = ?", apiKeyID).Order("id").Find(&hooks).Error
+	return hooks, err
+}
+
+// CountByAPIKey counts REST hooks of an API key
+func (r *RESTHookRepository) CountByAPIKey(ctx context.Context, apiKeyID uint) (int64, error) {
+	var count int64
+	err := dbFromContext(ctx, r.db).Model(&models.RESTHook{}).Where("api_key_id = ?", apiKeyID).Count(&count).Error
+	return count, err
+}
+
+// GetByEvent gets REST hooks subscribed to the event whose API key is still valid
+func (r *RESTHookRepository) GetByEvent(ctx context.Context, event string, now time.Time) ([]models.RESTHook, error) {
+	db := dbFromContext(ctx, r.db)
+	activeKeys := db.Session(&gorm.Session{NewDB: true}).Model(&models.APIKey{}).
+		Select("id").
+		Where("expires_at IS NULL OR expires_at > ?", now)
+
+	var hooks []models.RESTHook
+	err := db.Where("event = ? AND api_key_id IN (?)", event, activeKeys).Order("id").Find(&hooks).Error
+	return hooks, err
+}
+
+// Delete soft deletes a REST hook of an API key
+// Чужие подписки не видны: для них возвращается gorm.ErrRecordNotFound
+func (r *RESTHookRepository) Delete(ctx context.Context, id, apiKeyID uint) error {
+	result := dbFromContext(ctx, r.db).Where("api_key_id = ?", apiKeyID).Delete(&models.RESTHook{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RetentionRepository stores retention rules and permanently deletes rows they expire
+type RetentionRepository struct {
+	db *gorm.DB
+}
+
+// NewRetentionRepository creates a new retention repository
+func NewRetentionRepository(db *gorm.DB) *RetentionRepository {
+	return &RetentionRepository{db: db}
+}
+
+// ListRules gets all retention rules
+func (r *RetentionRepository) ListRules(ctx context.Context) ([]models.RetentionRule, error) {
+	var rules []models.RetentionRule
+	err := dbFromContext(ctx, r.db).Order("entity").Find(&rules).Error
+	return rules, err
+}
+
+// UpsertRule creates the rule of an entity or replaces its age and enabled flag
+func (r *RetentionRepository) UpsertRule(ctx context.Context, rule *models.RetentionRule) error {
+	return dbFromContext(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "entity"}},
+		DoUpdates: clause.AssignmentColumns([]string{"max_age_days", "enabled", "updated_at"}),
+	}).Create(rule).Error
+}
+
+// DeleteRule deletes the rule of an entity
+func (r *RetentionRepository) DeleteRule(ctx context.Context, entity string) error {
+	result := dbFromContext(ctx, r.db).Where("entity = ?", entity).Delete(&models.RetentionRule{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// expiredBookings выбирает бронирования, закончившиеся до cutoff, включая мягко удалённые
+func expiredBookings(db *gorm.DB, cutoff time.Time) *gorm.DB {
+	return db.Unscoped().Model(&models.Booking{}).Where("end_time < ?", cutoff)
+}
+
+// CountExpiredBookings counts bookings that ended before the cutoff: deletable ones and exempt ones
+func (r *RetentionRepository) CountExpiredBookings(ctx context.Context, cutoff time.Time) (matched, exempt int64, err error) {
+	db := dbFromContext(ctx, r.db)
+	if err = onReplica(expiredBookings(db, cutoff)).Where("retention_exempt = ?", false).Count(&matched).Error; err != nil {
+		return 0, 0, err
+	}
+	err = onReplica(expiredBookings(db, cutoff)).Where("retention_exempt = ?", true).Count(&exempt).Error
+	return matched, exempt, err
+}
+
+// DeleteExpiredBookings hard-deletes non-exempt bookings that ended before the cutoff together with their participants
+func (r *RetentionRepository) DeleteExpiredBookings(ctx context.Context, cutoff time.Time) (int64, error) {
+	db := dbFromContext(ctx, r.db)
+
+	expired := expiredBookings(db, cutoff).Select("id").Where("retention_exempt = ?", false)
+	if err := deleteBookingDependents(db, expired); err != nil {
+		return 0, err
+	}
+
+	result := db.Unscoped().Where("end_time < ? AND retention_exempt = ?", cutoff, false).Delete(&models.Booking{})
+	return result.RowsAffected, result.Error
+}
+
+// CountExpiredAuditLogs counts audit log entries recorded before the cutoff
+func (r *RetentionRepository) CountExpiredAuditLogs(ctx context.Context, cutoff time.Time) (int64, error) {
+	var count int64
+	err := onReplica(dbFromContext(ctx, r.db)).Model(&models.AuditLog{}).Where("created_at < ?", cutoff).Count(&count).Error
+	return count, err
+}
+
+// DeleteExpiredAuditLogs deletes audit log entries recorded before the cutoff
+func (r *RetentionRepository) DeleteExpiredAuditLogs(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := dbFromContext(ctx, r.db).Where("created_at < ?", cutoff).Delete(&models.AuditLog{})
+	return result.RowsAffected, result.Error
+}
+
+// SetBookingExempt sets the retention exemption flag of a booking, including a soft-deleted one
+func (r *RetentionRepository) SetBookingExempt(ctx context.Context, bookingID uint, exempt bool) error {
+	result := dbFromContext(ctx, r.db).Unscoped().Model(&models.Booking{}).
+		Where("id = ?", bookingID).
+		UpdateColumn("retention_exempt", exempt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -1,8 +1,12 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"github.com/space/backend/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // RoomRepository handles database operations for rooms
@@ -16,52 +20,84 @@ func NewRoomRepository(db *gorm.DB) *RoomRepository {
 }
 
 // Create creates a new room
-func (r *RoomRepository) Create(room *models.Room) error {
-	return r.db.Create(room).Error
+func (r *RoomRepository) Create(ctx context.Context, room *models.Room) error {
+	return dbFromContext(ctx, r.db).Create(room).Error
 }
 
 // GetByID gets a room by ID with its equipment
-func (r *RoomRepository) GetByID(id uint) (*models.Room, error) {
+func (r *RoomRepository) GetByID(ctx context.Context, id uint) (*models.Room, error) {
+	var room models.Room
+	err := dbFromContext(ctx, r.db).Preload("Equipment").First(&room, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &room, nil
+}
+
+// LockByID gets a room by ID and locks its row until the transaction ends (SELECT ... FOR UPDATE)
+// Сериализует бронирования одной комнаты: проверка конфликтов и вставка выполняются без гонки
+// Должен вызываться внутри TxManager.WithinTx
+func (r *RoomRepository) LockByID(ctx context.Context, id uint) (*models.Room, error) {
 	var room models.Room
-	err := r.db.Preload("Equipment").First(&room, id).Error
+	err := dbFromContext(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).First(&room, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &room, nil
 }
 
-// GetAll gets all active rooms
-func (r *RoomRepository) GetAll() ([]models.Room, error) {
+// GetAll gets all active rooms, by name unless order is given
+// Парковочные места в списки комнат не входят (см. GetParkingSpots)
+func (r *RoomRepository) GetAll(ctx context.Context, order string) ([]models.Room, error) {
 	var rooms []models.Room
-	err := r.db.Where("is_active = ?", true).Preload("Equipment").Order("name").Find(&rooms).Error
+	query := dbFromContext(ctx, r.db).Where("is_active = ? AND kind = ?", true, models.RoomKindRoom).Preload("Equipment")
+	err := orderBy(query, order, "name").Find(&rooms).Error
 	return rooms, err
 }
 
-// GetAllWithEquipment gets all active rooms with their equipment
-func (r *RoomRepository) GetAllWithEquipment() ([]models.Room, error) {
+// GetAllWithEquipment gets all active rooms with their equipment, by name unless order is given
+func (r *RoomRepository) GetAllWithEquipment(ctx context.Context, order string) ([]models.Room, error) {
 	var rooms []models.Room
-	err := r.db.Where("is_active = ?", true).
+	query := dbFromContext(ctx, r.db).Where("is_active = ? AND kind = ?", true, models.RoomKindRoom).
 		Preload("Equipment").
-		Preload("Equipment.Instructions").
-		Order("name").
-		Find(&rooms).Error
+		Preload("Equipment.Instructions")
+	err := orderBy(query, order, "name").Find(&rooms).Error
 	return rooms, err
 }
 
+// GetParkingSpots gets all active parking spots by name
+func (r *RoomRepository) GetParkingSpots(ctx context.Context) ([]models.Room, error) {
+	var spots []models.Room
+	err := dbFromContext(ctx, r.db).
+		Where("is_active = ? AND kind = ?", true, models.RoomKindParking).
+		Order("name").
+		Find(&spots).Error
+	return spots, err
+}
+
 // Update updates a room
-func (r *RoomRepository) Update(room *models.Room) error {
-	return r.db.Save(room).Error
+func (r *RoomRepository) Update(ctx context.Context, room *models.Room) error {
+	return dbFromContext(ctx, r.db).Save(room).Error
+}
+
+// ClaimBroadcast marks the room as broadcast to at now unless it was broadcast to after notBefore
+// Возвращает false, если объявление уже отправлено недавно (в том числе параллельным запросом)
+func (r *RoomRepository) ClaimBroadcast(ctx context.Context, roomID uint, now, notBefore time.Time) (bool, error) {
+	result := dbFromContext(ctx, r.db).Model(&models.Room{}).
+		Where("id = ? AND (broadcast_at IS NULL OR broadcast_at < ?)", roomID, notBefore).
+		UpdateColumn("broadcast_at", now)
+	return result.RowsAffected == 1, result.Error
 }
 
 // Delete soft deletes a room
-func (r *RoomRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Room{}, id).Error
+func (r *RoomRepository) Delete(ctx context.Context, id uint) error {
+	return dbFromContext(ctx, r.db).Delete(&models.Room{}, id).Error
 }
 
 // GetByName gets a room by name
-func (r *RoomRepository) GetByName(name string) (*models.Room, error) {
+func (r *RoomRepository) GetByName(ctx context.Context, name string) (*models.Room, error) {
 	var room models.Room
-	err := r.db.Where("name = ?", name).First(&room).Error
+	err := dbFromContext(ctx, r.db).Where("name = ?", name).First(&room).Error
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/space/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// SlackTargetRepository handles database operations for Slack notification targets
+type SlackTargetRepository struct {
+	db *gorm.DB
+}
+
+// NewSlackTargetRepository creates a new Slack target repository
+func NewSlackTargetRepository(db *gorm.DB) *SlackTargetRepository {
+	return &SlackTargetRepository{db: db}
+}
+
+// Create creates a new Slack target
+func (r *SlackTargetRepository) Create(ctx context.Context, target *models.SlackTarget) error {
+	return dbFromContext(ctx, r.db).Create(target).Error
+}
+
+// GetAll gets all Slack targets
+func (r *SlackTargetRepository) GetAll(ctx context.Context) ([]models.SlackTarget, error) {
+	var targets []models.SlackTarget
+	err := dbFromContext(ctx, r.db).Order("id").Find(&targets).Error
+	return targets, err
+}
+
+// GetForRoom gets Slack targets of a room and workspace-wide targets
+func (r *SlackTargetRepository) GetForRoom(ctx context.Context, roomID uint) ([]models.SlackTarget, error) {
+	var targets []models.SlackTarget
+	err := dbFromContext(ctx, r.db).Where("room_id IS NULL OR room_id = ?", roomID).Order("id").Find(&targets).Error
+	return targets, err
+}
+
+// Delete soft deletes a Slack target
+func (r *SlackTargetRepository) Delete(ctx context.Context, id uint) error {
+	result := dbFromContext(ctx, r.db).Delete(&models.SlackTarget{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,1884 @@
+//go:build sqlite
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/database"
+	"github.com/space/backend/internal/models"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Репозитории на SQLite в памяти: make test-sqlite (go test -tags sqlite ./...)
+
+func newSQLiteDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := database.Connect("sqlite://:memory:", database.ConnectOptions{
+		Backoff:                time.Second,
+		PrepareStmt:            true,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to open SQLite: %v", err)
+	}
+	t.Cleanup(func() { database.Close(db) })
+
+	if err := database.MigrateSQLite(db); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	return db
+}
+
+func TestSQLite_UserSearch(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	users := NewUserRepository(db)
+
+	// В телефонную книгу попадают только пользователи с ФИО и телефоном (User.BeforeSave)
+	for _, u := range []models.User{
+		{TelegramID: 1, Username: "ivan_dev", FirstName: "Ivan", LastName: "Petrov", PhoneNumber: "+79990000001"},
+		{TelegramID: 2, Username: "maria", FirstName: "Maria", LastName: "Kozlova", PhoneNumber: "+79990000002"},
+		{TelegramID: 3, Username: "hidden_ivan", FirstName: "Ivan"},
+	} {
+		if err := users.Create(ctx, &u); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+	}
+
+	found, total, err := users.Search(ctx, "IVAN", 10, 0, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(found) != 1 || found[0].Username != "ivan_dev" || total != 1 {
+		t.Errorf("Expected only ivan_dev, got: %v (total %d)", found, total)
+	}
+
+	// "_" экранируется и не совпадает с любым символом
+	found, _, err = users.Search(ctx, "n_d", 10, 0, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(found) != 1 {
+		t.Errorf("Expected 1 user for literal underscore, got: %d", len(found))
+	}
+}
+
+func TestSQLite_BookingConflictsAndParticipants(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	users := NewUserRepository(db)
+	rooms := NewRoomRepository(db)
+	bookings := NewBookingRepository(db)
+	txManager := NewTxManager(db)
+
+	creator := &models.User{TelegramID: 1, FirstName: "Creator"}
+	guest := &models.User{TelegramID: 2, FirstName: "Guest"}
+	for _, u := range []*models.User{creator, guest} {
+		if err := users.Create(ctx, u); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+	}
+	room := &models.Room{Name: "Орбита", Capacity: 4, IsActive: true}
+	if err := rooms.Create(ctx, room); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	start := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+	booking := &models.Booking{
+		RoomID:    room.ID,
+		CreatorID: creator.ID,
+		StartTime: start,
+		EndTime:   start.Add(time.Hour),
+		Title:     "Standup",
+		Status:    models.BookingStatusConfirmed,
+	}
+
+	// LockByID (FOR UPDATE) в SQLite игнорируется, транзакция работает как обычно
+	err := txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := rooms.LockByID(ctx, room.ID); err != nil {
+			return err
+		}
+		return bookings.Create(ctx, booking)
+	})
+	if err != nil {
+		t.Fatalf("Failed to create booking: %v", err)
+	}
+
+	conflicts, err := bookings.GetConflictingBookings(ctx, room.ID, start.Add(30*time.Minute), start.Add(90*time.Minute), nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(conflicts) != 1 {
+		t.Errorf("Expected 1 conflicting booking, got: %d", len(conflicts))
+	}
+
+	// Повторное добавление участника не должно падать (ON CONFLICT DO NOTHING)
+	for i := 0; i < 2; i++ {
+		added, err := bookings.AddParticipant(ctx, booking.ID, guest.ID)
+		if err != nil {
+			t.Fatalf("Failed to add participant: %v", err)
+		}
+		if added != (i == 0) {
+			t.Errorf("Expected only the first add to report a new participant, attempt %d got: %v", i, added)
+		}
+	}
+	loaded, err := bookings.GetByID(ctx, booking.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(loaded.Participants) != 1 {
+		t.Errorf("Expected 1 participant, got: %d", len(loaded.Participants))
+	}
+}
+
+func TestSQLite_GetByUserIDPrecedence(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	users := NewUserRepository(db)
+	rooms := NewRoomRepository(db)
+	bookings := NewBookingRepository(db)
+
+	owner := &models.User{TelegramID: 1, FirstName: "Owner"}
+	other := &models.User{TelegramID: 2, FirstName: "Other"}
+	for _, u := range []*models.User{owner, other} {
+		if err := users.Create(ctx, u); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+	}
+	room := &models.Room{Name: "Орбита", Capacity: 4, IsActive: true}
+	if err := rooms.Create(ctx, room); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	start := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+	newBooking := func(creatorID uint, offset time.Duration, title string) *models.Booking {
+		b := &models.Booking{
+			RoomID:    room.ID,
+			CreatorID: creatorID,
+			StartTime: start.Add(offset),
+			EndTime:   start.Add(offset + time.Hour),
+			Title:     title,
+			Status:    models.BookingStatusConfirmed,
+		}
+		if err := bookings.Create(ctx, b); err != nil {
+			t.Fatalf("Failed to create booking: %v", err)
+		}
+		return b
+	}
+
+	newBooking(owner.ID, 0, "own")
+	cancelled := newBooking(owner.ID, 8*time.Hour, "cancelled")
+	if err := db.Model(cancelled).UpdateColumn("status", models.BookingStatusCancelled).Error; err != nil {
+		t.Fatalf("Failed to cancel booking: %v", err)
+	}
+	joined := newBooking(other.ID, 2*time.Hour, "joined")
+	deletedJoined := newBooking(other.ID, 4*time.Hour, "deleted joined")
+	newBooking(other.ID, 6*time.Hour, "foreign")
+	for _, b := range []*models.Booking{joined, deletedJoined} {
+		if _, err := bookings.AddParticipant(ctx, b.ID, owner.ID); err != nil {
+			t.Fatalf("Failed to add participant: %v", err)
+		}
+	}
+	if err := db.Delete(deletedJoined).Error; err != nil {
+		t.Fatalf("Failed to delete booking: %v", err)
+	}
+
+	// Удалённое бронирование, где пользователь участник, не должно попадать в выдачу:
+	// условие soft delete обязано применяться к обеим веткам OR
+	found, total, err := bookings.GetByUserID(ctx, owner.ID, 10, 0, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(found) != 3 || found[0].Title != "cancelled" || found[1].Title != "joined" || found[2].Title != "own" {
+		t.Errorf("Expected [cancelled joined own], got: %v", bookingTitles(found))
+	}
+	if total != 3 {
+		t.Errorf("Expected total 3, got: %d", total)
+	}
+
+	page, total, err := bookings.GetByUserID(ctx, owner.ID, 1, 2, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(page) != 1 || page[0].Title != "own" || total != 3 {
+		t.Errorf("Expected third page [own] of 3, got: %v (total %d)", bookingTitles(page), total)
+	}
+
+	sorted, _, err := bookings.GetByUserID(ctx, owner.ID, 10, 0, "title ASC")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(sorted) != 3 || sorted[0].Title != "cancelled" || sorted[1].Title != "joined" || sorted[2].Title != "own" {
+		t.Errorf("Expected [cancelled joined own] by title, got: %v", bookingTitles(sorted))
+	}
+
+	// Условие, добавленное после группы, ограничивает обе ветки OR:
+	// без скобок "creator_id = ? OR id IN (...) AND status <> 'cancelled'" вернул бы отменённое бронирование
+	var active []models.Booking
+	if err := involvingUser(db, owner.ID).Where(activeBookingCondition).Order("start_time").Find(&active).Error; err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(active) != 2 || active[0].Title != "own" || active[1].Title != "joined" {
+		t.Errorf("Expected [own joined], got: %v", bookingTitles(active))
+	}
+
+	// Неделя для экспорта: без отменённых, удалённых и чужих, по времени начала
+	week, err := bookings.GetByUserInRange(ctx, owner.ID, start.Add(-30*time.Minute), start.Add(9*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(week) != 2 || week[0].Title != "own" || week[1].Title != "joined" || week[1].Room.Name != "Орбита" {
+		t.Errorf("Expected [own joined] with rooms, got: %v", bookingTitles(week))
+	}
+}
+
+func TestSQLite_BookingReminders(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	users := NewUserRepository(db)
+	rooms := NewRoomRepository(db)
+	bookings := NewBookingRepository(db)
+
+	owner := &models.User{TelegramID: 1, Username: "owner"}
+	if err := users.Create(ctx, owner); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	room := &models.Room{Name: "Room", IsActive: true}
+	if err := rooms.Create(ctx, room); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	now := time.Now()
+	for _, start := range []time.Duration{5 * time.Minute, time.Hour} {
+		b := &models.Booking{RoomID: room.ID, CreatorID: owner.ID, Title: start.String(), StartTime: now.Add(start), EndTime: now.Add(start + time.Hour)}
+		if err := bookings.Create(ctx, b); err != nil {
+			t.Fatalf("Failed to create booking: %v", err)
+		}
+	}
+
+	due, err := bookings.GetDueForReminder(ctx, now, now.Add(15*time.Minute))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(due) != 1 || due[0].Title != "5m0s" {
+		t.Fatalf("Expected only the booking starting in 5m, got: %v", bookingTitles(due))
+	}
+
+	// Вторая реплика не должна отправить то же напоминание
+	for i, want := range []bool{true, false} {
+		claimed, err := bookings.MarkReminderSent(ctx, due[0].ID, now)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if claimed != want {
+			t.Errorf("Attempt %d: expected claimed=%v, got: %v", i+1, want, claimed)
+		}
+	}
+
+	if due, _ = bookings.GetDueForReminder(ctx, now, now.Add(15*time.Minute)); len(due) != 0 {
+		t.Errorf("Expected no due reminders after marking, got: %v", bookingTitles(due))
+	}
+}
+
+func TestSQLite_RESTHooksOfActiveKeys(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	users := NewUserRepository(db)
+	keys := NewAPIKeyRepository(db)
+	hooks := NewRESTHookRepository(db)
+
+	admin := &models.User{TelegramID: 1, Username: "admin"}
+	if err := users.Create(ctx, admin); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	active := &models.APIKey{Name: "zapier", Prefix: "spk_1", SecretHash: "1", Scopes: "read:bookings", CreatedByID: admin.ID}
+	expired := &models.APIKey{Name: "old", Prefix: "spk_2", SecretHash: "2", Scopes: "read:bookings", CreatedByID: admin.ID, ExpiresAt: &past}
+	revoked := &models.APIKey{Name: "revoked", Prefix: "spk_3", SecretHash: "3", Scopes: "read:bookings", CreatedByID: admin.ID}
+	for _, k := range []*models.APIKey{active, expired, revoked} {
+		if err := keys.Create(ctx, k); err != nil {
+			t.Fatalf("Failed to create API key: %v", err)
+		}
+		hook := &models.RESTHook{APIKeyID: k.ID, Event: "booking.created", TargetURL: "https://example.com/" + k.Name}
+		if err := hooks.Create(ctx, hook); err != nil {
+			t.Fatalf("Failed to create hook: %v", err)
+		}
+	}
+	if err := keys.Delete(ctx, revoked.ID); err != nil {
+		t.Fatalf("Failed to revoke key: %v", err)
+	}
+
+	found, err := hooks.GetByEvent(ctx, "booking.created", now)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(found) != 1 || found[0].APIKeyID != active.ID {
+		t.Errorf("Expected only the hook of the active key, got: %+v", found)
+	}
+
+	// Чужую подписку удалить нельзя
+	if err := hooks.Delete(ctx, found[0].ID, expired.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected ErrRecordNotFound for another key's hook, got: %v", err)
+	}
+}
+
+func bookingTitles(bookings []models.Booking) []string {
+	titles := make([]string, 0, len(bookings))
+	for _, b := range bookings {
+		titles = append(titles, b.Title)
+	}
+	return titles
+}
+
+func TestSQLite_DoorAccessGrants(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	users := NewUserRepository(db)
+	rooms := NewRoomRepository(db)
+	bookings := NewBookingRepository(db)
+	grants := NewDoorAccessRepository(db)
+
+	owner := &models.User{TelegramID: 1, Username: "owner"}
+	if err := users.Create(ctx, owner); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	room := &models.Room{Name: "Room", IsActive: true, Attributes: []byte(`{"door_id":"front"}`)}
+	if err := rooms.Create(ctx, room); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	now := time.Now()
+	for _, start := range []time.Duration{2 * time.Minute, time.Hour} {
+		b := &models.Booking{RoomID: room.ID, CreatorID: owner.ID, Title: start.String(), StartTime: now.Add(start), EndTime: now.Add(start + time.Hour)}
+		if err := bookings.Create(ctx, b); err != nil {
+			t.Fatalf("Failed to create booking: %v", err)
+		}
+	}
+
+	due, err := grants.GetBookingsToGrant(ctx, now, now.Add(5*time.Minute))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(due) != 1 || due[0].Title != "2m0s" || due[0].Room.Name != "Room" {
+		t.Fatalf("Expected only the booking starting in 2m with its room, got: %v", bookingTitles(due))
+	}
+	bookingID := due[0].ID
+
+	// Вторая реплика не должна выдать тот же доступ
+	for i, want := range []bool{true, false} {
+		grant := &models.DoorAccessGrant{BookingID: bookingID, DoorID: "front", ValidFrom: now, ValidUntil: now.Add(-time.Second)}
+		claimed, err := grants.Claim(ctx, grant)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if claimed != want {
+			t.Errorf("Attempt %d: expected claimed=%v, got: %v", i+1, want, claimed)
+		}
+	}
+	if due, _ = grants.GetBookingsToGrant(ctx, now, now.Add(5*time.Minute)); len(due) != 0 {
+		t.Errorf("Expected no bookings to grant after claiming, got: %v", bookingTitles(due))
+	}
+
+	// Не подтверждённый драйвером доступ не отзывается
+	if expired, _ := grants.GetExpired(ctx, now); len(expired) != 0 {
+		t.Errorf("Expected no expired grants before granting, got: %d", len(expired))
+	}
+
+	grant, err := grants.GetByBookingID(ctx, bookingID)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	grant.GrantedAt = &now
+	if err := grants.MarkGranted(ctx, grant); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expired, err := grants.GetExpired(ctx, now)
+	if err != nil || len(expired) != 1 {
+		t.Fatalf("Expected one expired grant, got: %d (%v)", len(expired), err)
+	}
+	for i, want := range []bool{true, false} {
+		claimed, err := grants.MarkRevoked(ctx, grant.ID, now)
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if claimed != want {
+			t.Errorf("Revoke %d: expected claimed=%v, got: %v", i+1, want, claimed)
+		}
+	}
+	if expired, _ = grants.GetExpired(ctx, now); len(expired) != 0 {
+		t.Errorf("Expected no expired grants after revoking, got: %d", len(expired))
+	}
+}
+
+func TestSQLite_OIDCUsersWithoutTelegram(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	users := NewUserRepository(db)
+
+	// telegram_id = 0 у всех пользователей без Telegram - уникальность только для настоящих ID
+	for _, email := range []string{"anna@example.com", "bob@example.com"} {
+		email, subject := email, "sub-"+email
+		if err := users.Create(ctx, &models.User{Email: &email, OIDCSubject: &subject}); err != nil {
+			t.Fatalf("Failed to create OIDC user: %v", err)
+		}
+	}
+	if err := users.Create(ctx, &models.User{TelegramID: 1}); err != nil {
+		t.Fatalf("Failed to create Telegram user: %v", err)
+	}
+	if err := users.Create(ctx, &models.User{TelegramID: 1}); err == nil {
+		t.Error("Expected duplicate Telegram ID to be rejected")
+	}
+
+	user, err := users.GetByEmail(ctx, "bob@example.com")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	bySubject, err := users.GetByOIDCSubject(ctx, "sub-bob@example.com")
+	if err != nil || bySubject.ID != user.ID {
+		t.Errorf("Expected the same user by subject, got: %v, %v", bySubject, err)
+	}
+}
+
+func TestSQLite_DirectoryAndTeams(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	users := NewUserRepository(db)
+	teams := NewTeamRepository(db)
+
+	email, externalID := "anna@example.com", "hr-1"
+	anna := &models.User{Email: &email, ExternalID: &externalID}
+	telegramUser := &models.User{TelegramID: 1}
+	for _, u := range []*models.User{anna, telegramUser} {
+		if err := users.Create(ctx, u); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+	}
+
+	// Каталогу видны только пользователи с email
+	found, total, err := users.ListDirectory(ctx, DirectoryFilter{}, 10, 0)
+	if err != nil || total != 1 || found[0].ID != anna.ID {
+		t.Errorf("Expected only the directory user, got: %v (total %d), %v", found, total, err)
+	}
+	if found, _, _ = users.ListDirectory(ctx, DirectoryFilter{ExternalID: "hr-2"}, 10, 0); len(found) != 0 {
+		t.Errorf("Expected no users for unknown externalId, got: %v", found)
+	}
+
+	team := &models.Team{Name: "Design"}
+	if err := teams.Create(ctx, team); err != nil {
+		t.Fatalf("Failed to create team: %v", err)
+	}
+	// Повторное добавление участника не дублирует строку
+	for i := 0; i < 2; i++ {
+		if err := teams.AddMembers(ctx, team.ID, []uint{anna.ID, telegramUser.ID}); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+	if err := teams.RemoveMembers(ctx, team.ID, []uint{telegramUser.ID}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	list, total, err := teams.List(ctx, TeamFilter{Name: "Design"}, 10, 0)
+	if err != nil || total != 1 || len(list[0].Members) != 1 || list[0].Members[0].ID != anna.ID {
+		t.Errorf("Expected Design with one member, got: %+v, %v", list, err)
+	}
+
+	if err := teams.Delete(ctx, team.ID); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := teams.Delete(ctx, team.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected ErrRecordNotFound, got: %v", err)
+	}
+}
+
+func TestSQLite_FutureBookingsOfDepartedUser(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	users := NewUserRepository(db)
+	rooms := NewRoomRepository(db)
+	bookings := NewBookingRepository(db)
+
+	departed := &models.User{TelegramID: 1}
+	colleague := &models.User{TelegramID: 2}
+	for _, u := range []*models.User{departed, colleague} {
+		if err := users.Create(ctx, u); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+	}
+	room := &models.Room{Name: "Room", IsActive: true}
+	if err := rooms.Create(ctx, room); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	now := time.Now()
+	for _, b := range []*models.Booking{
+		{RoomID: room.ID, CreatorID: departed.ID, Title: "past", StartTime: now.Add(-2 * time.Hour), EndTime: now.Add(-time.Hour)},
+		{RoomID: room.ID, CreatorID: departed.ID, Title: "future", StartTime: now.Add(time.Hour), EndTime: now.Add(2 * time.Hour)},
+		{RoomID: room.ID, CreatorID: colleague.ID, Title: "colleague", StartTime: now.Add(3 * time.Hour), EndTime: now.Add(4 * time.Hour)},
+	} {
+		if err := bookings.Create(ctx, b); err != nil {
+			t.Fatalf("Failed to create booking: %v", err)
+		}
+		if b.Title == "colleague" {
+			if _, err := bookings.AddParticipant(ctx, b.ID, departed.ID); err != nil {
+				t.Fatalf("Failed to add participant: %v", err)
+			}
+		}
+	}
+
+	future, err := bookings.GetFutureByCreator(ctx, departed.ID, now)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(future) != 1 || future[0].Title != "future" {
+		t.Errorf("Expected only the future booking, got: %v", bookingTitles(future))
+	}
+
+	removed, err := bookings.RemoveFromFutureBookings(ctx, departed.ID, now)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(removed) != 1 {
+		t.Errorf("Expected the colleague booking to be reported, got: %v", removed)
+	}
+	var participations int64
+	db.Table("booking_participants").Where("user_id = ?", departed.ID).Count(&participations)
+	if participations != 0 {
+		t.Errorf("Expected no future participations, got: %d", participations)
+	}
+}
+
+func TestSQLite_CreateAndUpdateDoNotSaveRelations(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	users := NewUserRepository(db)
+	rooms := NewRoomRepository(db)
+	bookings := NewBookingRepository(db)
+
+	creator := &models.User{TelegramID: 1, FirstName: "Creator"}
+	guest := &models.User{TelegramID: 2, FirstName: "Guest"}
+	for _, u := range []*models.User{creator, guest} {
+		if err := users.Create(ctx, u); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+	}
+	room := &models.Room{Name: "Орбита", Capacity: 4, IsActive: true}
+	if err := rooms.Create(ctx, room); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	// Связи заполнены устаревшими копиями - они не должны попасть в базу
+	staleGuest := *guest
+	staleGuest.FirstName = "Stale"
+	start := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+	booking := &models.Booking{
+		RoomID:       room.ID,
+		CreatorID:    creator.ID,
+		StartTime:    start,
+		EndTime:      start.Add(time.Hour),
+		Title:        "Standup",
+		Status:       models.BookingStatusConfirmed,
+		Participants: []models.User{staleGuest},
+	}
+	if err := bookings.Create(ctx, booking); err != nil {
+		t.Fatalf("Failed to create booking: %v", err)
+	}
+
+	booking.Title = "Retro"
+	booking.Room = models.Room{ID: room.ID, Name: "Stale room"}
+	if err := bookings.Update(ctx, booking); err != nil {
+		t.Fatalf("Failed to update booking: %v", err)
+	}
+
+	loaded, err := bookings.GetByID(ctx, booking.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if loaded.Title != "Retro" || len(loaded.Participants) != 1 {
+		t.Fatalf("Expected updated title and linked participant, got: %+v", loaded)
+	}
+	if loaded.Participants[0].FirstName != "Guest" || loaded.Room.Name != "Орбита" {
+		t.Errorf("Expected related rows untouched, got participant %q and room %q", loaded.Participants[0].FirstName, loaded.Room.Name)
+	}
+}
+
+func TestSQLite_TargetedUpdates(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	users := NewUserRepository(db)
+	rooms := NewRoomRepository(db)
+	bookings := NewBookingRepository(db)
+
+	user := &models.User{TelegramID: 1, FirstName: "Anna"}
+	if err := users.Create(ctx, user); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	room := &models.Room{Name: "Орбита", Capacity: 4, IsActive: true}
+	if err := rooms.Create(ctx, room); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+	start := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+	booking := &models.Booking{RoomID: room.ID, CreatorID: user.ID, StartTime: start, EndTime: start.Add(time.Hour), Title: "Standup", Status: models.BookingStatusConfirmed}
+	if err := bookings.Create(ctx, booking); err != nil {
+		t.Fatalf("Failed to create booking: %v", err)
+	}
+
+	// Параллельно задача напоминаний отмечает бронирование, а администратор меняет роль
+	if _, err := bookings.MarkReminderSent(ctx, booking.ID, time.Now()); err != nil {
+		t.Fatalf("Failed to mark reminder: %v", err)
+	}
+	if err := users.UpdateRole(ctx, user.ID, models.RoleAdmin); err != nil {
+		t.Fatalf("Failed to update role: %v", err)
+	}
+
+	booking.Title = "Retro"
+	booking.IsJoinable = false
+	if err := bookings.Update(ctx, booking); err != nil {
+		t.Fatalf("Failed to update booking: %v", err)
+	}
+	loaded, err := bookings.GetByID(ctx, booking.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if loaded.Title != "Retro" || loaded.ReminderSentAt == nil {
+		t.Errorf("Expected new title and preserved reminder mark, got %q and %v", loaded.Title, loaded.ReminderSentAt)
+	}
+
+	user.LastName = "Ivanova"
+	user.PhoneNumber = "+79990000000"
+	if err := users.UpdateColumns(ctx, user, "last_name", "phone_number", "is_in_phone_book"); err != nil {
+		t.Fatalf("Failed to update user: %v", err)
+	}
+	reloaded, err := users.GetByID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !reloaded.IsInPhoneBook || reloaded.PhoneNumber != "+79990000000" {
+		t.Errorf("Expected user to join the phonebook, got: %+v", reloaded)
+	}
+	if reloaded.Role != models.RoleAdmin {
+		t.Errorf("Expected concurrent role change to survive, got: %q", reloaded.Role)
+	}
+}
+
+func TestSQLite_CalendarSummaries(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	users := NewUserRepository(db)
+	rooms := NewRoomRepository(db)
+	bookings := NewBookingRepository(db)
+
+	creator := &models.User{TelegramID: 1, FirstName: "Anna", LastName: "Ivanova"}
+	guest := &models.User{TelegramID: 2, FirstName: "Guest"}
+	for _, u := range []*models.User{creator, guest} {
+		if err := users.Create(ctx, u); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+	}
+	room := &models.Room{Name: "Орбита", Capacity: 4, IsActive: true}
+	if err := rooms.Create(ctx, room); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	start := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+	for i, status := range []models.BookingStatus{models.BookingStatusConfirmed, models.BookingStatusCancelled, models.BookingStatusConfirmed} {
+		booking := &models.Booking{
+			RoomID:       room.ID,
+			CreatorID:    creator.ID,
+			StartTime:    start.Add(time.Duration(i) * time.Hour),
+			EndTime:      start.Add(time.Duration(i)*time.Hour + 30*time.Minute),
+			Title:        "Meeting",
+			Status:       status,
+			Participants: []models.User{*guest},
+		}
+		if err := bookings.Create(ctx, booking); err != nil {
+			t.Fatalf("Failed to create booking: %v", err)
+		}
+		if i == 2 {
+			if err := bookings.Delete(ctx, booking.ID); err != nil {
+				t.Fatalf("Failed to delete booking: %v", err)
+			}
+		}
+	}
+
+	summaries, err := bookings.GetCalendarSummaries(ctx, start.Add(-time.Hour), start.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(summaries) != 1 {
+		t.Fatalf("Expected only the active, not deleted booking, got: %+v", summaries)
+	}
+	s := summaries[0]
+	if s.RoomName != "Орбита" || s.CreatorFirstName != "Anna" || s.CreatorLastName != "Ivanova" || s.CreatorTelegramID != 1 || s.ParticipantCount != 1 || !s.StartTime.Equal(start) {
+		t.Errorf("Unexpected summary: %+v", s)
+	}
+}
+
+func TestSQLite_ExportBatches(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	users := NewUserRepository(db)
+	rooms := NewRoomRepository(db)
+	bookings := NewBookingRepository(db)
+
+	// Однофамильцы проверяют переход keyset-курсора внутри одинаковых фамилий
+	for i, name := range [][2]string{{"Anna", "Ivanova"}, {"Boris", "Ivanov"}, {"Anna", "Ivanov"}, {"Vera", "Ivanov"}, {"Gleb", "Petrov"}} {
+		u := &models.User{TelegramID: int64(i + 1), FirstName: name[0], LastName: name[1], PhoneNumber: "+7999000000" + strconv.Itoa(i)}
+		if err := users.Create(ctx, u); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+	}
+	var names []string
+	batches := 0
+	err := users.ExportPhonebookBatches(ctx, 2, func(batch []models.User) error {
+		batches++
+		for _, u := range batch {
+			names = append(names, u.FirstName+" "+u.LastName)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	want := []string{"Anna Ivanov", "Boris Ivanov", "Vera Ivanov", "Anna Ivanova", "Gleb Petrov"}
+	if strings.Join(names, ",") != strings.Join(want, ",") || batches != 3 {
+		t.Errorf("Expected %v in 3 batches, got %v in %d", want, names, batches)
+	}
+
+	room := &models.Room{Name: "Орбита", Capacity: 4, IsActive: true}
+	other := &models.Room{Name: "Луна", Capacity: 4, IsActive: true}
+	for _, r := range []*models.Room{room, other} {
+		if err := rooms.Create(ctx, r); err != nil {
+			t.Fatalf("Failed to create room: %v", err)
+		}
+	}
+	start := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+	// Три бронирования начинаются одновременно - порядок внутри них задаёт ID
+	for i, offset := range []int{0, 0, 0, 1, 2} {
+		booking := &models.Booking{
+			RoomID:    room.ID,
+			CreatorID: 1,
+			StartTime: start.Add(time.Duration(offset) * time.Hour),
+			EndTime:   start.Add(time.Duration(offset)*time.Hour + 30*time.Minute),
+			Title:     "Meeting " + strconv.Itoa(i),
+			Status:    models.BookingStatusConfirmed,
+		}
+		if err := bookings.Create(ctx, booking); err != nil {
+			t.Fatalf("Failed to create booking: %v", err)
+		}
+	}
+	foreign := &models.Booking{RoomID: other.ID, CreatorID: 1, StartTime: start, EndTime: start.Add(time.Hour), Title: "Foreign", Status: models.BookingStatusConfirmed}
+	if err := bookings.Create(ctx, foreign); err != nil {
+		t.Fatalf("Failed to create booking: %v", err)
+	}
+
+	var titles []string
+	err = bookings.ExportBatches(ctx, start.Add(-time.Hour), start.Add(24*time.Hour), room.ID, 2, func(batch []models.Booking) error {
+		for _, b := range batch {
+			if b.Creator.FirstName == "" {
+				t.Errorf("Expected creator to be preloaded for booking %d", b.ID)
+			}
+			titles = append(titles, b.Title)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if got := strings.Join(titles, ","); got != "Meeting 0,Meeting 1,Meeting 2,Meeting 3,Meeting 4" {
+		t.Errorf("Expected every booking of the room exactly once in order, got: %s", got)
+	}
+}
+
+func TestSQLite_RoomSubscriberContacts(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	users := NewUserRepository(db)
+	rooms := NewRoomRepository(db)
+	notifications := NewNotificationRepository(db)
+
+	room := &models.Room{Name: "Орбита", Capacity: 4, IsActive: true}
+	other := &models.Room{Name: "Луна", Capacity: 4, IsActive: true}
+	for _, r := range []*models.Room{room, other} {
+		if err := rooms.Create(ctx, r); err != nil {
+			t.Fatalf("Failed to create room: %v", err)
+		}
+	}
+	anna := &models.User{TelegramID: 10, Username: "anna", FirstName: "Anna"}
+	boris := &models.User{TelegramID: 20, FirstName: "Boris"}
+	gone := &models.User{TelegramID: 30, FirstName: "Gone"}
+	for _, u := range []*models.User{anna, boris, gone} {
+		if err := users.Create(ctx, u); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+		if err := notifications.Subscribe(ctx, u.ID, room.ID); err != nil {
+			t.Fatalf("Failed to subscribe: %v", err)
+		}
+	}
+	if err := notifications.Subscribe(ctx, anna.ID, other.ID); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	if err := notifications.Unsubscribe(ctx, boris.ID, room.ID); err != nil {
+		t.Fatalf("Failed to unsubscribe: %v", err)
+	}
+	if err := db.Delete(&models.User{}, gone.ID).Error; err != nil {
+		t.Fatalf("Failed to delete user: %v", err)
+	}
+
+	contacts, err := notifications.GetRoomSubscriberContacts(ctx, room.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	want := models.RoomSubscriber{UserID: anna.ID, TelegramID: 10, Username: "anna", FirstName: "Anna"}
+	if len(contacts) != 1 || contacts[0] != want {
+		t.Errorf("Expected only Anna's contact, got: %+v", contacts)
+	}
+}
+
+func TestSQLite_ClaimBroadcast(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	rooms := NewRoomRepository(db)
+
+	room := &models.Room{Name: "Орбита", Capacity: 4, IsActive: true}
+	if err := rooms.Create(ctx, room); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	now := time.Now().UTC()
+	if claimed, err := rooms.ClaimBroadcast(ctx, room.ID, now, now.Add(-10*time.Minute)); err != nil || !claimed {
+		t.Fatalf("Expected first broadcast to be claimed, got %v (%v)", claimed, err)
+	}
+	if claimed, _ := rooms.ClaimBroadcast(ctx, room.ID, now.Add(time.Minute), now.Add(-9*time.Minute)); claimed {
+		t.Error("Expected a broadcast within the interval to be rejected")
+	}
+	if claimed, _ := rooms.ClaimBroadcast(ctx, room.ID, now.Add(11*time.Minute), now.Add(time.Minute)); !claimed {
+		t.Error("Expected a broadcast after the interval to be claimed")
+	}
+}
+
+func TestSQLite_Retention(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	users := NewUserRepository(db)
+	rooms := NewRoomRepository(db)
+	bookings := NewBookingRepository(db)
+	retention := NewRetentionRepository(db)
+
+	owner := &models.User{TelegramID: 1, Username: "owner"}
+	if err := users.Create(ctx, owner); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	room := &models.Room{Name: "Room", IsActive: true}
+	if err := rooms.Create(ctx, room); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	now := time.Now().UTC()
+	old := now.AddDate(-2, 0, 0)
+	var created []*models.Booking
+	for _, start := range []time.Time{old, old.Add(24 * time.Hour), now} {
+		b := &models.Booking{RoomID: room.ID, CreatorID: owner.ID, Title: start.Format(time.DateOnly), StartTime: start, EndTime: start.Add(time.Hour)}
+		if err := bookings.Create(ctx, b); err != nil {
+			t.Fatalf("Failed to create booking: %v", err)
+		}
+		created = append(created, b)
+	}
+	if _, err := bookings.AddParticipant(ctx, created[0].ID, owner.ID); err != nil {
+		t.Fatalf("Failed to add participant: %v", err)
+	}
+	if err := retention.SetBookingExempt(ctx, created[1].ID, true); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := retention.SetBookingExempt(ctx, 999, true); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected ErrRecordNotFound for a missing booking, got: %v", err)
+	}
+
+	cutoff := now.AddDate(-1, 0, 0)
+	matched, exempt, err := retention.CountExpiredBookings(ctx, cutoff)
+	if err != nil || matched != 1 || exempt != 1 {
+		t.Fatalf("Expected 1 expired and 1 exempt booking, got %d/%d (%v)", matched, exempt, err)
+	}
+	deleted, err := retention.DeleteExpiredBookings(ctx, cutoff)
+	if err != nil || deleted != 1 {
+		t.Fatalf("Expected 1 deleted booking, got %d (%v)", deleted, err)
+	}
+	var remaining int64
+	db.Unscoped().Model(&models.Booking{}).Count(&remaining)
+	if remaining != 2 {
+		t.Errorf("Expected the exempt and the recent booking to remain, got: %d", remaining)
+	}
+
+	// Повторная установка правила сущности заменяет его, а не создаёт второе
+	for _, days := range []int{365, 30} {
+		if err := retention.UpsertRule(ctx, &models.RetentionRule{Entity: models.RetentionEntityAuditLogs, MaxAgeDays: days, Enabled: true}); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+	rules, err := retention.ListRules(ctx)
+	if err != nil || len(rules) != 1 || rules[0].MaxAgeDays != 30 {
+		t.Errorf("Expected a single rule with 30 days, got: %+v (%v)", rules, err)
+	}
+	if err := retention.DeleteRule(ctx, models.RetentionEntityBookings); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected ErrRecordNotFound for a missing rule, got: %v", err)
+	}
+}
+
+func TestSQLite_PurgeKeepsRetentionExemptBookings(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	users := NewUserRepository(db)
+	rooms := NewRoomRepository(db)
+	bookings := NewBookingRepository(db)
+
+	owner := &models.User{TelegramID: 1, Username: "owner"}
+	if err := users.Create(ctx, owner); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	room := &models.Room{Name: "Room", IsActive: true}
+	if err := rooms.Create(ctx, room); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	start := time.Now().UTC().Add(-48 * time.Hour)
+	var created []*models.Booking
+	for _, title := range []string{"Cancelled", "Exempt"} {
+		b := &models.Booking{RoomID: room.ID, CreatorID: owner.ID, Title: title, StartTime: start, EndTime: start.Add(time.Hour)}
+		if err := bookings.Create(ctx, b); err != nil {
+			t.Fatalf("Failed to create booking: %v", err)
+		}
+		if _, err := bookings.AddParticipant(ctx, b.ID, owner.ID); err != nil {
+			t.Fatalf("Failed to add participant: %v", err)
+		}
+		if err := bookings.Cancel(ctx, b.ID); err != nil {
+			t.Fatalf("Failed to cancel booking: %v", err)
+		}
+		created = append(created, b)
+	}
+	if err := NewRetentionRepository(db).SetBookingExempt(ctx, created[1].ID, true); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	purged, err := NewPurgeRepository(db).PurgeBookings(ctx, time.Now().Add(time.Minute))
+	if err != nil || purged != 1 {
+		t.Fatalf("Expected 1 purged booking, got %d (%v)", purged, err)
+	}
+	var remaining []models.Booking
+	db.Unscoped().Find(&remaining)
+	if len(remaining) != 1 || remaining[0].ID != created[1].ID {
+		t.Errorf("Expected only the exempt booking to remain, got: %+v", remaining)
+	}
+	var participants int64
+	db.Table("booking_participants").Where("booking_id = ?", created[1].ID).Count(&participants)
+	if participants != 1 {
+		t.Errorf("Expected the exempt booking to keep its participant, got: %d", participants)
+	}
+}
+
+func TestSQLite_AbuseFlags(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	users := NewUserRepository(db)
+	rooms := NewRoomRepository(db)
+	bookings := NewBookingRepository(db)
+	abuse := NewAbuseRepository(db)
+
+	owner := &models.User{TelegramID: 1, Username: "owner"}
+	admin := &models.User{TelegramID: 2, Username: "admin", Role: models.RoleAdmin}
+	for _, u := range []*models.User{owner, admin} {
+		if err := users.Create(ctx, u); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+	}
+	room := &models.Room{Name: "Room", IsActive: true}
+	if err := rooms.Create(ctx, room); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	now := time.Now().UTC()
+	for i := 0; i < 3; i++ {
+		start := now.Add(time.Duration(i+1) * time.Hour)
+		b := &models.Booking{RoomID: room.ID, CreatorID: owner.ID, Title: strconv.Itoa(i), StartTime: start, EndTime: start.Add(30 * time.Minute)}
+		if err := bookings.Create(ctx, b); err != nil {
+			t.Fatalf("Failed to create booking: %v", err)
+		}
+		if i < 2 {
+			if err := bookings.Cancel(ctx, b.ID); err != nil {
+				t.Fatalf("Failed to cancel booking: %v", err)
+			}
+		}
+	}
+
+	counts, err := abuse.CountCancellationsSince(ctx, now.Add(-time.Hour), 2)
+	if err != nil || len(counts) != 1 || counts[0] != (UserCount{UserID: owner.ID, Count: 2}) {
+		t.Errorf("Expected 2 cancellations of the owner, got: %+v (%v)", counts, err)
+	}
+	if upcoming, err := bookings.CountUpcomingByCreator(ctx, owner.ID, now); err != nil || upcoming != 1 {
+		t.Errorf("Expected 1 upcoming booking, got: %d (%v)", upcoming, err)
+	}
+
+	// Вторая открытая отметка по той же причине не создаётся
+	for i, want := range []bool{true, false} {
+		created, err := abuse.CreateFlag(ctx, &models.AbuseFlag{UserID: owner.ID, Reason: models.AbuseReasonCancellations, Count: 2, Status: models.AbuseFlagOpen})
+		if err != nil || created != want {
+			t.Errorf("Attempt %d: expected created=%v, got: %v (%v)", i+1, want, created, err)
+		}
+	}
+	flags, total, err := abuse.ListFlags(ctx, models.AbuseFlagOpen, 10, 0)
+	if err != nil || total != 1 || flags[0].User.Username != "owner" {
+		t.Fatalf("Expected one open flag with its user, got: %+v (%v)", flags, err)
+	}
+	if reviewed, err := abuse.ReviewFlag(ctx, flags[0].ID, models.AbuseFlagDismissed, admin.ID, now); err != nil || !reviewed {
+		t.Errorf("Expected the open flag to be reviewed, got: %v (%v)", reviewed, err)
+	}
+	if reviewed, _ := abuse.ReviewFlag(ctx, flags[0].ID, models.AbuseFlagConfirmed, admin.ID, now); reviewed {
+		t.Error("Expected a reviewed flag not to be reviewed again")
+	}
+	if created, _ := abuse.CreateFlag(ctx, &models.AbuseFlag{UserID: owner.ID, Reason: models.AbuseReasonCancellations, Count: 3, Status: models.AbuseFlagOpen}); !created {
+		t.Error("Expected a new flag after the previous one was reviewed")
+	}
+
+	// Лимит только снижается
+	for i, tt := range []struct {
+		limit int
+		want  bool
+	}{{3, true}, {5, false}, {2, true}} {
+		if tightened, err := users.TightenBookingLimit(ctx, owner.ID, tt.limit); err != nil || tightened != tt.want {
+			t.Errorf("Step %d: expected tightened=%v, got: %v (%v)", i+1, tt.want, tightened, err)
+		}
+	}
+	if err := users.SetBookingLimit(ctx, owner.ID, nil); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if user, _ := users.GetByID(ctx, owner.ID); user.BookingLimit != nil {
+		t.Errorf("Expected the limit to be removed, got: %d", *user.BookingLimit)
+	}
+}
+
+func TestSQLite_ReleaseCurrentBooking(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	users := NewUserRepository(db)
+	rooms := NewRoomRepository(db)
+	bookings := NewBookingRepository(db)
+
+	owner := &models.User{TelegramID: 1, Username: "owner"}
+	if err := users.Create(ctx, owner); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	room := &models.Room{Name: "Room", IsActive: true}
+	if err := rooms.Create(ctx, room); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	now := time.Now().UTC()
+	for _, start := range []time.Time{now.Add(-time.Hour), now.Add(time.Hour)} {
+		b := &models.Booking{RoomID: room.ID, CreatorID: owner.ID, Title: start.Format(time.Kitchen), StartTime: start, EndTime: start.Add(90 * time.Minute)}
+		if err := bookings.Create(ctx, b); err != nil {
+			t.Fatalf("Failed to create booking: %v", err)
+		}
+	}
+
+	current, err := bookings.GetCurrentByRoom(ctx, room.ID, now)
+	if err != nil || current.Creator.Username != "owner" || !current.StartTime.Before(now) {
+		t.Fatalf("Expected the booking in progress with its creator, got: %+v (%v)", current, err)
+	}
+	for i, want := range []bool{true, false} {
+		released, err := bookings.Release(ctx, current.ID, now)
+		if err != nil || released != want {
+			t.Errorf("Attempt %d: expected released=%v, got: %v (%v)", i+1, want, released, err)
+		}
+	}
+
+	stored, _ := bookings.GetByID(ctx, current.ID)
+	if stored.Status != models.BookingStatusCompleted || !stored.EndTime.Equal(now) {
+		t.Errorf("Expected a completed booking ending now, got: %s %v", stored.Status, stored.EndTime)
+	}
+	if _, err := bookings.GetCurrentByRoom(ctx, room.ID, now.Add(time.Second)); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected the room to be free, got: %v", err)
+	}
+}
+
+func TestSQLite_Billing(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	users := NewUserRepository(db)
+	rooms := NewRoomRepository(db)
+	bookings := NewBookingRepository(db)
+	teams := NewTeamRepository(db)
+	billing := NewBillingRepository(db)
+
+	member := &models.User{TelegramID: 1, Username: "member"}
+	if err := users.Create(ctx, member); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	room := &models.Room{Name: "Room", IsActive: true}
+	if err := rooms.Create(ctx, room); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+	team := &models.Team{Name: "Acme"}
+	if err := teams.Create(ctx, team); err != nil {
+		t.Fatalf("Failed to create team: %v", err)
+	}
+	if err := teams.AddMembers(ctx, team.ID, []uint{member.ID}); err != nil {
+		t.Fatalf("Failed to add member: %v", err)
+	}
+
+	hours := 40
+	if err := billing.SetIncludedHours(ctx, team.ID, &hours); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := billing.SetIncludedHours(ctx, 999, &hours); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected ErrRecordNotFound for a missing team, got: %v", err)
+	}
+	list, err := billing.ListTeams(ctx)
+	if err != nil || len(list) != 1 || list[0].IncludedHours == nil || *list[0].IncludedHours != 40 {
+		t.Errorf("Expected the team with 40 included hours, got: %+v (%v)", list, err)
+	}
+	memberships, err := billing.GetMemberships(ctx)
+	if err != nil || len(memberships) != 1 || memberships[0] != (TeamMembership{TeamID: team.ID, UserID: member.ID}) {
+		t.Errorf("Expected one membership, got: %+v (%v)", memberships, err)
+	}
+
+	now := time.Now().UTC()
+	var created []*models.Booking
+	for _, start := range []time.Time{now.Add(-3 * time.Hour), now.Add(-30 * time.Minute), now.Add(-5 * time.Hour)} {
+		b := &models.Booking{RoomID: room.ID, CreatorID: member.ID, Title: "Meeting", StartTime: start, EndTime: start.Add(time.Hour)}
+		if err := bookings.Create(ctx, b); err != nil {
+			t.Fatalf("Failed to create booking: %v", err)
+		}
+		created = append(created, b)
+	}
+	if err := bookings.Cancel(ctx, created[2].ID); err != nil {
+		t.Fatalf("Failed to cancel booking: %v", err)
+	}
+
+	// Идущее и отменённое бронирования не учитываются
+	spans, err := billing.GetEndedBookingSpans(ctx, now.Add(-24*time.Hour), now.Add(24*time.Hour), now)
+	if err != nil || len(spans) != 1 || spans[0].ID != created[0].ID || spans[0].CreatorID != member.ID {
+		t.Errorf("Expected only the ended booking, got: %+v (%v)", spans, err)
+	}
+}
+
+func TestSQLite_Holidays(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	holidays := NewHolidayRepository(db)
+
+	for _, h := range []models.Holiday{{Date: "2025-05-09", Name: "День Победы"}, {Date: "2025-05-01", Name: "Праздник весны и труда"}} {
+		created, err := holidays.Create(ctx, &h)
+		if err != nil || !created {
+			t.Fatalf("Failed to create holiday: %v", err)
+		}
+	}
+
+	// Дата уже в календаре - запись не дублируется
+	created, err := holidays.Create(ctx, &models.Holiday{Date: "2025-05-01", Name: "Duplicate"})
+	if err != nil || created {
+		t.Errorf("Expected a duplicate date to be skipped, got: %v (%v)", created, err)
+	}
+
+	list, err := holidays.ListBetween(ctx, "2025-05-01", "2025-05-08")
+	if err != nil || len(list) != 1 || list[0].Name != "Праздник весны и труда" {
+		t.Errorf("Expected only May 1, got: %+v (%v)", list, err)
+	}
+
+	if err := holidays.Delete(ctx, list[0].ID); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if err := holidays.Delete(ctx, list[0].ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected ErrRecordNotFound, got: %v", err)
+	}
+}
+
+func TestSQLite_BookingFeedback(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	users := NewUserRepository(db)
+	rooms := NewRoomRepository(db)
+	bookings := NewBookingRepository(db)
+	feedback := NewFeedbackRepository(db)
+
+	var participants []*models.User
+	for i := 1; i <= 2; i++ {
+		u := &models.User{TelegramID: int64(i), Username: "user" + strconv.Itoa(i)}
+		if err := users.Create(ctx, u); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+		participants = append(participants, u)
+	}
+	var created []*models.Room
+	for _, name := range []string{"Good Room", "Bad Room"} {
+		room := &models.Room{Name: name, IsActive: true}
+		if err := rooms.Create(ctx, room); err != nil {
+			t.Fatalf("Failed to create room: %v", err)
+		}
+		created = append(created, room)
+	}
+
+	start := time.Now().Add(-3 * time.Hour).UTC()
+	ratings := map[*models.Room][]int{created[0]: {5, 4}, created[1]: {1, 3}}
+	for room, values := range ratings {
+		b := &models.Booking{RoomID: room.ID, CreatorID: participants[0].ID, Title: "Meeting", StartTime: start, EndTime: start.Add(time.Hour)}
+		if err := bookings.Create(ctx, b); err != nil {
+			t.Fatalf("Failed to create booking: %v", err)
+		}
+		for i, rating := range values {
+			if err := feedback.Upsert(ctx, &models.BookingFeedback{BookingID: b.ID, UserID: participants[i].ID, RoomID: room.ID, Rating: rating}); err != nil {
+				t.Fatalf("Failed to save feedback: %v", err)
+			}
+		}
+		// Повторный отзыв участника заменяет прежний
+		if room == created[1] {
+			if err := feedback.Upsert(ctx, &models.BookingFeedback{BookingID: b.ID, UserID: participants[1].ID, RoomID: room.ID, Rating: 2}); err != nil {
+				t.Fatalf("Failed to replace feedback: %v", err)
+			}
+		}
+	}
+
+	stats, err := feedback.RoomRatingStats(ctx, time.Time{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(stats) != 2 || stats[0].RoomName != "Bad Room" || stats[0].Average != 1.5 || stats[0].Count != 2 || stats[0].LowCount != 2 {
+		t.Errorf("Expected the bad room first with two low ratings, got: %+v", stats)
+	}
+
+	stats, err = feedback.RoomRatingStats(ctx, time.Now().Add(time.Hour))
+	if err != nil || len(stats) != 0 {
+		t.Errorf("Expected no feedback in the future, got: %+v (%v)", stats, err)
+	}
+}
+
+func TestSQLite_CheckIns(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	users := NewUserRepository(db)
+	rooms := NewRoomRepository(db)
+	bookings := NewBookingRepository(db)
+	checkIns := NewCheckInRepository(db)
+
+	creator := &models.User{TelegramID: 1, Username: "creator"}
+	guest := &models.User{TelegramID: 2, Username: "guest"}
+	for _, u := range []*models.User{creator, guest} {
+		if err := users.Create(ctx, u); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+	}
+	room := &models.Room{Name: "Room", IsActive: true}
+	if err := rooms.Create(ctx, room); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	now := time.Now().UTC()
+	current := &models.Booking{RoomID: room.ID, CreatorID: creator.ID, Title: "Current", StartTime: now.Add(-time.Hour), EndTime: now.Add(time.Hour),
+		Participants: []models.User{*guest}}
+	later := &models.Booking{RoomID: room.ID, CreatorID: guest.ID, Title: "Later", StartTime: now.Add(2 * time.Hour), EndTime: now.Add(3 * time.Hour)}
+	for _, b := range []*models.Booking{current, later} {
+		if err := bookings.Create(ctx, b); err != nil {
+			t.Fatalf("Failed to create booking: %v", err)
+		}
+	}
+
+	found, err := checkIns.FindCheckInBooking(ctx, room.ID, guest.ID, now, now.Add(15*time.Minute))
+	if err != nil || found.ID != current.ID || found.Room.Name != "Room" {
+		t.Fatalf("Expected the current booking for the participant, got: %+v (%v)", found, err)
+	}
+	if _, err := checkIns.FindCheckInBooking(ctx, room.ID, creator.ID, now.Add(90*time.Minute), now.Add(105*time.Minute)); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected no booking of the creator between meetings, got: %v", err)
+	}
+
+	first := &models.BookingCheckIn{BookingID: current.ID, UserID: guest.ID, CheckedInAt: now}
+	if created, err := checkIns.CheckIn(ctx, first); err != nil || !created {
+		t.Fatalf("Expected a new check-in, got: %v (%v)", created, err)
+	}
+	again := &models.BookingCheckIn{BookingID: current.ID, UserID: guest.ID, CheckedInAt: now.Add(time.Minute)}
+	if created, err := checkIns.CheckIn(ctx, again); err != nil || created || again.ID != first.ID || !again.CheckedInAt.Equal(first.CheckedInAt) {
+		t.Errorf("Expected the existing check-in, got: %+v %v (%v)", again, created, err)
+	}
+}
+
+func TestSQLite_CleaningTasks(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	users := NewUserRepository(db)
+	rooms := NewRoomRepository(db)
+	bookings := NewBookingRepository(db)
+	cleaning := NewCleaningRepository(db)
+
+	user := &models.User{TelegramID: 1, Username: "staff"}
+	if err := users.Create(ctx, user); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	room := &models.Room{Name: "Kitchen", IsActive: true, ResetRequired: true}
+	other := &models.Room{Name: "Hall", IsActive: true}
+	for _, r := range []*models.Room{room, other} {
+		if err := rooms.Create(ctx, r); err != nil {
+			t.Fatalf("Failed to create room: %v", err)
+		}
+	}
+
+	ids, err := cleaning.GetResetRoomIDs(ctx)
+	if err != nil || len(ids) != 1 || ids[0] != room.ID {
+		t.Errorf("Expected only the kitchen, got: %v (%v)", ids, err)
+	}
+
+	now := time.Now().UTC()
+	ended := &models.Booking{RoomID: room.ID, CreatorID: user.ID, Title: "Lunch", StartTime: now.Add(-2 * time.Hour), EndTime: now.Add(-time.Hour)}
+	cancelled := &models.Booking{RoomID: room.ID, CreatorID: user.ID, Title: "Cancelled", StartTime: now.Add(-2 * time.Hour), EndTime: now.Add(-time.Hour)}
+	running := &models.Booking{RoomID: room.ID, CreatorID: user.ID, Title: "Running", StartTime: now.Add(-time.Hour), EndTime: now.Add(time.Hour)}
+	for _, b := range []*models.Booking{ended, cancelled, running} {
+		if err := bookings.Create(ctx, b); err != nil {
+			t.Fatalf("Failed to create booking: %v", err)
+		}
+	}
+	if err := bookings.Cancel(ctx, cancelled.ID); err != nil {
+		t.Fatalf("Failed to cancel booking: %v", err)
+	}
+
+	spans, err := cleaning.GetEndedBookingSpans(ctx, room.ID, now.Add(-3*time.Hour), now)
+	if err != nil || len(spans) != 1 || spans[0].ID != ended.ID {
+		t.Errorf("Expected only the ended booking, got: %+v (%v)", spans, err)
+	}
+
+	task := &models.CleaningTask{RoomID: room.ID, BookingID: &ended.ID, UsageMinutes: 60, Status: models.CleaningTaskOpen}
+	if err := cleaning.CreateTask(ctx, task); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	room.NeedsCleaning = true
+	room.CleaningCursor = &ended.EndTime
+	if err := cleaning.SaveRoomProgress(ctx, room); err != nil {
+		t.Fatalf("Failed to save room progress: %v", err)
+	}
+	stored, err := rooms.GetByID(ctx, room.ID)
+	if err != nil || !stored.NeedsCleaning || stored.CleaningCursor == nil {
+		t.Errorf("Expected the room to need cleaning, got: %+v (%v)", stored, err)
+	}
+
+	tasks, total, err := cleaning.ListTasks(ctx, models.CleaningTaskOpen, 0, 10, 0)
+	if err != nil || total != 1 || tasks[0].Room.Name != "Kitchen" {
+		t.Errorf("Expected the open kitchen task, got: %+v %d (%v)", tasks, total, err)
+	}
+	if completed, err := cleaning.CompleteTask(ctx, task.ID, user.ID, now); err != nil || !completed {
+		t.Fatalf("Expected the task to be completed, got: %v (%v)", completed, err)
+	}
+	if completed, err := cleaning.CompleteTask(ctx, task.ID, user.ID, now); err != nil || completed {
+		t.Errorf("Expected a done task not to be completed again, got: %v (%v)", completed, err)
+	}
+	if open, err := cleaning.CountOpenTasks(ctx, room.ID); err != nil || open != 0 {
+		t.Errorf("Expected no open tasks, got: %d (%v)", open, err)
+	}
+}
+
+func TestSQLite_BookingHistory(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	users := NewUserRepository(db)
+	rooms := NewRoomRepository(db)
+	bookings := NewBookingRepository(db)
+	history := NewBookingHistoryRepository(db)
+
+	user := &models.User{TelegramID: 1, Username: "owner"}
+	guest := &models.User{TelegramID: 2, Username: "guest"}
+	for _, u := range []*models.User{user, guest} {
+		if err := users.Create(ctx, u); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+	}
+	room := &models.Room{Name: "Room", IsActive: true}
+	if err := rooms.Create(ctx, room); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	now := time.Now().UTC()
+	ended := &models.Booking{RoomID: room.ID, CreatorID: user.ID, Title: "Ended", StartTime: now.Add(-2 * time.Hour), EndTime: now.Add(-time.Hour), Status: models.BookingStatusConfirmed}
+	future := &models.Booking{RoomID: room.ID, CreatorID: user.ID, Title: "Future", StartTime: now.Add(time.Hour), EndTime: now.Add(2 * time.Hour), Status: models.BookingStatusConfirmed}
+	for _, b := range []*models.Booking{ended, future} {
+		if err := bookings.Create(ctx, b); err != nil {
+			t.Fatalf("Failed to create booking: %v", err)
+		}
+		if err := history.Append(ctx, &models.BookingHistoryEvent{BookingID: b.ID, Type: models.BookingHistoryCreated, CreatedAt: now}); err != nil {
+			t.Fatalf("Failed to append event: %v", err)
+		}
+	}
+
+	if removed, err := bookings.RemoveParticipant(ctx, ended.ID, guest.ID); err != nil || removed {
+		t.Errorf("Expected nothing to remove, got: %v (%v)", removed, err)
+	}
+
+	due, err := bookings.GetEndedConfirmed(ctx, now, 10)
+	if err != nil || len(due) != 1 || due[0].ID != ended.ID {
+		t.Fatalf("Expected only the ended booking, got: %+v (%v)", due, err)
+	}
+	if done, err := bookings.Complete(ctx, ended.ID, now); err != nil || !done {
+		t.Errorf("Expected the booking to be completed, got: %v (%v)", done, err)
+	}
+	if done, err := bookings.Complete(ctx, ended.ID, now); err != nil || done {
+		t.Errorf("Expected a completed booking to be skipped, got: %v (%v)", done, err)
+	}
+	if err := history.Append(ctx, &models.BookingHistoryEvent{BookingID: ended.ID, Type: models.BookingHistoryCompleted, CreatedAt: now}); err != nil {
+		t.Fatalf("Failed to append event: %v", err)
+	}
+
+	events, err := history.ListByBooking(ctx, ended.ID)
+	if err != nil || len(events) != 2 || events[1].Type != models.BookingHistoryCompleted {
+		t.Errorf("Expected created and completed events, got: %+v (%v)", events, err)
+	}
+	feed, err := history.ListAfter(ctx, events[0].ID, 10)
+	if err != nil || len(feed) != 2 || feed[0].BookingID != future.ID {
+		t.Errorf("Expected the events after the cursor, got: %+v (%v)", feed, err)
+	}
+
+	// Окончательное удаление бронирования удаляет и его поток событий
+	if err := bookings.Cancel(ctx, ended.ID); err != nil {
+		t.Fatalf("Failed to cancel booking: %v", err)
+	}
+	if _, err := NewPurgeRepository(db).PurgeBookings(ctx, time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if events, _ := history.ListByBooking(ctx, ended.ID); len(events) != 0 {
+		t.Errorf("Expected the purged booking history to be removed, got: %+v", events)
+	}
+}
+
+func TestSQLite_ParkingSpots(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	users := NewUserRepository(db)
+	rooms := NewRoomRepository(db)
+	bookings := NewBookingRepository(db)
+
+	user := &models.User{TelegramID: 1, Username: "driver"}
+	if err := users.Create(ctx, user); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	room := &models.Room{Name: "Room", IsActive: true}
+	spot := &models.Room{Name: "P1", IsActive: true, Kind: models.RoomKindParking}
+	for _, r := range []*models.Room{room, spot} {
+		if err := rooms.Create(ctx, r); err != nil {
+			t.Fatalf("Failed to create room: %v", err)
+		}
+	}
+
+	// Парковочные места не попадают в списки комнат
+	all, err := rooms.GetAll(ctx, "")
+	if err != nil || len(all) != 1 || all[0].ID != room.ID {
+		t.Errorf("Expected only the room, got: %+v (%v)", all, err)
+	}
+	spots, err := rooms.GetParkingSpots(ctx)
+	if err != nil || len(spots) != 1 || spots[0].ID != spot.ID {
+		t.Errorf("Expected only the parking spot, got: %+v (%v)", spots, err)
+	}
+
+	start := time.Now().UTC().Add(time.Hour)
+	meeting := &models.Booking{RoomID: room.ID, CreatorID: user.ID, Title: "Meeting", StartTime: start, EndTime: start.Add(time.Hour), Status: models.BookingStatusConfirmed}
+	parking := &models.Booking{RoomID: spot.ID, CreatorID: user.ID, Title: "P1", StartTime: start, EndTime: start.Add(time.Hour), Status: models.BookingStatusConfirmed, LicensePlate: "A123BC"}
+	for _, b := range []*models.Booking{meeting, parking} {
+		if err := bookings.Create(ctx, b); err != nil {
+			t.Fatalf("Failed to create booking: %v", err)
+		}
+	}
+
+	found, err := bookings.ListByRoomKind(ctx, models.RoomKindParking, user.ID, start.Add(-time.Hour), time.Time{})
+	if err != nil || len(found) != 1 || found[0].ID != parking.ID || found[0].LicensePlate != "A123BC" || found[0].Room.Name != "P1" {
+		t.Errorf("Expected only the parking booking, got: %+v (%v)", found, err)
+	}
+	if found, err := bookings.ListByRoomKind(ctx, models.RoomKindParking, user.ID, start.Add(2*time.Hour), start.Add(3*time.Hour)); err != nil || len(found) != 0 {
+		t.Errorf("Expected nothing after the booking, got: %+v (%v)", found, err)
+	}
+
+	// Календарь и лента ближайших встреч - только комнаты
+	calendar, err := bookings.GetForCalendar(ctx, start.Add(-time.Hour), start.Add(2*time.Hour))
+	if err != nil || len(calendar) != 1 || calendar[0].ID != meeting.ID {
+		t.Errorf("Expected only the meeting in the calendar, got: %v (%v)", bookingTitles(calendar), err)
+	}
+	summaries, err := bookings.GetCalendarSummaries(ctx, start.Add(-time.Hour), start.Add(2*time.Hour))
+	if err != nil || len(summaries) != 1 || summaries[0].ID != meeting.ID {
+		t.Errorf("Expected only the meeting summary, got: %+v (%v)", summaries, err)
+	}
+	upcoming, err := bookings.GetUpcoming(ctx, 10)
+	if err != nil || len(upcoming) != 1 || upcoming[0].ID != meeting.ID {
+		t.Errorf("Expected only the upcoming meeting, got: %v (%v)", bookingTitles(upcoming), err)
+	}
+}
+
+func TestSQLite_FloorPlacement(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	rooms := NewRoomRepository(db)
+	floors := NewFloorRepository(db)
+
+	floor := &models.Floor{Name: "2 этаж", Level: 2}
+	ground := &models.Floor{Name: "1 этаж", Level: 1}
+	for _, f := range []*models.Floor{floor, ground} {
+		if err := floors.Create(ctx, f); err != nil {
+			t.Fatalf("Failed to create floor: %v", err)
+		}
+	}
+	list, err := floors.List(ctx)
+	if err != nil || len(list) != 2 || list[0].ID != ground.ID {
+		t.Errorf("Expected floors by level, got: %+v (%v)", list, err)
+	}
+
+	room := &models.Room{Name: "Room", IsActive: true}
+	inactive := &models.Room{Name: "Closed", IsActive: true}
+	for _, r := range []*models.Room{room, inactive} {
+		if err := rooms.Create(ctx, r); err != nil {
+			t.Fatalf("Failed to create room: %v", err)
+		}
+	}
+	x, y := 0.5, 0.25
+	for _, r := range []*models.Room{room, inactive} {
+		if err := floors.SetRoomPlacement(ctx, r.ID, &floor.ID, &x, &y); err != nil {
+			t.Fatalf("Failed to place room: %v", err)
+		}
+	}
+	if err := db.Model(&models.Room{}).Where("id = ?", inactive.ID).Update("is_active", false).Error; err != nil {
+		t.Fatalf("Failed to deactivate room: %v", err)
+	}
+	if err := floors.SetRoomPlacement(ctx, 999, &floor.ID, &x, &y); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected ErrRecordNotFound for an unknown room, got: %v", err)
+	}
+
+	placed, err := floors.GetRooms(ctx, floor.ID)
+	if err != nil || len(placed) != 1 || placed[0].ID != room.ID || placed[0].MapX == nil || *placed[0].MapX != x {
+		t.Errorf("Expected only the active room with its position, got: %+v (%v)", placed, err)
+	}
+
+	// Удаление этажа убирает комнаты с плана
+	if err := floors.Delete(ctx, floor.ID); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	stored, err := rooms.GetByID(ctx, room.ID)
+	if err != nil || stored.FloorID != nil || stored.MapX != nil || stored.MapY != nil {
+		t.Errorf("Expected the room to leave the plan, got: %+v (%v)", stored, err)
+	}
+	if err := floors.Delete(ctx, floor.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected ErrRecordNotFound for a deleted floor, got: %v", err)
+	}
+}
+
+func TestSQLite_Attendance(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	users := NewUserRepository(db)
+	rooms := NewRoomRepository(db)
+	bookings := NewBookingRepository(db)
+	checkIns := NewCheckInRepository(db)
+
+	creator := &models.User{TelegramID: 1, Username: "creator"}
+	guest := &models.User{TelegramID: 2, Username: "guest"}
+	for _, u := range []*models.User{creator, guest} {
+		if err := users.Create(ctx, u); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+	}
+	room := &models.Room{Name: "Room", IsActive: true, Capacity: 6}
+	parking := &models.Room{Name: "P1", IsActive: true, Kind: models.RoomKindParking}
+	for _, r := range []*models.Room{room, parking} {
+		if err := rooms.Create(ctx, r); err != nil {
+			t.Fatalf("Failed to create room: %v", err)
+		}
+	}
+
+	now := time.Now().UTC()
+	finished := &models.Booking{RoomID: room.ID, CreatorID: creator.ID, Title: "Finished", StartTime: now.Add(-3 * time.Hour), EndTime: now.Add(-2 * time.Hour),
+		EstimatedParticipants: 5, Participants: []models.User{*guest}}
+	cancelled := &models.Booking{RoomID: room.ID, CreatorID: creator.ID, Title: "Cancelled", StartTime: now.Add(-2 * time.Hour), EndTime: now.Add(-time.Hour),
+		Status: models.BookingStatusCancelled}
+	running := &models.Booking{RoomID: room.ID, CreatorID: creator.ID, Title: "Running", StartTime: now.Add(-30 * time.Minute), EndTime: now.Add(30 * time.Minute)}
+	parked := &models.Booking{RoomID: parking.ID, CreatorID: creator.ID, Title: "Parking", StartTime: now.Add(-3 * time.Hour), EndTime: now.Add(-2 * time.Hour)}
+	for _, b := range []*models.Booking{finished, cancelled, running, parked} {
+		if err := bookings.Create(ctx, b); err != nil {
+			t.Fatalf("Failed to create booking: %v", err)
+		}
+	}
+	if _, err := checkIns.CheckIn(ctx, &models.BookingCheckIn{BookingID: finished.ID, UserID: creator.ID, CheckedInAt: finished.StartTime}); err != nil {
+		t.Fatalf("Failed to check in: %v", err)
+	}
+
+	attendance, err := checkIns.GetAttendance(ctx, now.Add(-24*time.Hour), now)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(attendance) != 1 {
+		t.Fatalf("Expected only the finished room booking, got: %+v", attendance)
+	}
+	if got := attendance[0]; got.BookingID != finished.ID || got.CreatorID != creator.ID || got.EstimatedParticipants != 5 || got.ParticipantCount != 1 || got.CheckInCount != 1 {
+		t.Errorf("Unexpected attendance: %+v", got)
+	}
+}
+
+func TestSQLite_RoomOwnership(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	users := NewUserRepository(db)
+	rooms := NewRoomRepository(db)
+	teams := NewTeamRepository(db)
+	ownerships := NewOwnershipRepository(db)
+
+	member := &models.User{TelegramID: 1, Username: "member"}
+	guest := &models.User{TelegramID: 2, Username: "guest"}
+	for _, u := range []*models.User{member, guest} {
+		if err := users.Create(ctx, u); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+	}
+	room := &models.Room{Name: "Studio", IsActive: true}
+	if err := rooms.Create(ctx, room); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+	team := &models.Team{Name: "Residents"}
+	if err := teams.Create(ctx, team); err != nil {
+		t.Fatalf("Failed to create team: %v", err)
+	}
+	if err := teams.AddMembers(ctx, team.ID, []uint{member.ID}); err != nil {
+		t.Fatalf("Failed to add members: %v", err)
+	}
+
+	ownership := &models.RoomOwnership{RoomID: room.ID, TeamID: team.ID}
+	if err := ownerships.Create(ctx, ownership); err != nil {
+		t.Fatalf("Failed to create ownership: %v", err)
+	}
+	if err := ownerships.Create(ctx, &models.RoomOwnership{RoomID: room.ID, TeamID: team.ID}); err == nil {
+		t.Error("Expected a unique room violation for a second owner")
+	}
+	active, err := ownerships.GetActive(ctx, time.Now())
+	if err != nil || len(active) != 1 || active[0].Team.Name != "Residents" {
+		t.Errorf("Expected the ownership with its team, got: %+v (%v)", active, err)
+	}
+
+	if ok, err := ownerships.IsTeamMember(ctx, team.ID, member.ID); err != nil || !ok {
+		t.Errorf("Expected the member of the team, got: %v (%v)", ok, err)
+	}
+	if ok, _ := ownerships.IsTeamMember(ctx, team.ID, guest.ID); ok {
+		t.Error("Expected the guest not to be a member")
+	}
+
+	start := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	delegation := &models.RoomDelegation{OwnershipID: ownership.ID, UserID: guest.ID, StartTime: start, EndTime: start.Add(2 * time.Hour), CreatedByID: member.ID}
+	if err := ownerships.CreateDelegation(ctx, delegation); err != nil {
+		t.Fatalf("Failed to create delegation: %v", err)
+	}
+	if ok, err := ownerships.HasDelegation(ctx, ownership.ID, guest.ID, start.Add(30*time.Minute), start.Add(90*time.Minute)); err != nil || !ok {
+		t.Errorf("Expected a delegation covering the slot, got: %v (%v)", ok, err)
+	}
+	if ok, _ := ownerships.HasDelegation(ctx, ownership.ID, guest.ID, start.Add(time.Hour), start.Add(3*time.Hour)); ok {
+		t.Error("Expected no delegation for a slot past its end")
+	}
+	list, err := ownerships.ListDelegations(ctx, ownership.ID, time.Now())
+	if err != nil || len(list) != 1 || list[0].User.Username != "guest" {
+		t.Errorf("Expected the delegation with its user, got: %+v (%v)", list, err)
+	}
+
+	if err := ownerships.Delete(ctx, ownership.ID); err != nil {
+		t.Fatalf("Failed to delete ownership: %v", err)
+	}
+	if list, _ := ownerships.ListDelegations(ctx, ownership.ID, time.Now()); len(list) != 0 {
+		t.Errorf("Expected delegations deleted with the ownership, got: %+v", list)
+	}
+}
+
+func TestSQLite_RentalRequests(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	rooms := NewRoomRepository(db)
+	rentals := NewRentalRepository(db)
+
+	room := &models.Room{Name: "Hall", IsActive: true}
+	if err := rooms.Create(ctx, room); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+	start := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	for _, name := range []string{"Anna", "Boris"} {
+		request := &models.RentalRequest{RoomID: room.ID, Name: name, Email: "guest@example.com", Title: "Workshop", StartTime: start, EndTime: start.Add(time.Hour)}
+		if err := rentals.Create(ctx, request); err != nil {
+			t.Fatalf("Failed to create request: %v", err)
+		}
+	}
+
+	pending, total, err := rentals.List(ctx, models.RentalRequestPending, 10, 0)
+	if err != nil || total != 2 || len(pending) != 2 || pending[0].Room.Name != "Hall" {
+		t.Fatalf("Expected 2 pending requests with rooms, got: %+v, %d (%v)", pending, total, err)
+	}
+
+	id := pending[0].ID
+	if ok, err := rentals.Review(ctx, id, models.RentalRequestRejected, "Busy", 1, time.Now()); err != nil || !ok {
+		t.Fatalf("Expected the request to be reviewed, got: %v (%v)", ok, err)
+	}
+	if ok, _ := rentals.Review(ctx, id, models.RentalRequestApproved, "", 1, time.Now()); ok {
+		t.Error("Expected a reviewed request not to be reviewed again")
+	}
+	request, err := rentals.GetByID(ctx, id)
+	if err != nil || request.Status != models.RentalRequestRejected || request.RejectReason != "Busy" || request.ReviewedAt == nil {
+		t.Errorf("Expected the rejection to be stored, got: %+v (%v)", request, err)
+	}
+	if _, total, _ := rentals.List(ctx, models.RentalRequestPending, 10, 0); total != 1 {
+		t.Errorf("Expected 1 pending request left, got: %d", total)
+	}
+}
+
+func TestSQLite_BookingWaitlist(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	users := NewUserRepository(db)
+	rooms := NewRoomRepository(db)
+	bookings := NewBookingRepository(db)
+
+	creator := &models.User{TelegramID: 1, FirstName: "Creator"}
+	anna := &models.User{TelegramID: 2, FirstName: "Anna"}
+	boris := &models.User{TelegramID: 3, FirstName: "Boris"}
+	for _, u := range []*models.User{creator, anna, boris} {
+		if err := users.Create(ctx, u); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+	}
+	room := &models.Room{Name: "Hall", IsActive: true}
+	if err := rooms.Create(ctx, room); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+	start := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+	booking := &models.Booking{RoomID: room.ID, CreatorID: creator.ID, StartTime: start, EndTime: start.Add(time.Hour), Title: "Talk", IsJoinable: true, MaxParticipants: 2}
+	if err := bookings.Create(ctx, booking); err != nil {
+		t.Fatalf("Failed to create booking: %v", err)
+	}
+	if _, err := bookings.AddParticipant(ctx, booking.ID, anna.ID); err != nil {
+		t.Fatalf("Failed to add participant: %v", err)
+	}
+	if count, err := bookings.CountParticipants(ctx, booking.ID); err != nil || count != 1 {
+		t.Errorf("Expected 1 participant, got: %d (%v)", count, err)
+	}
+
+	// Повторная запись в очередь не создаёт второй строки
+	for i := 0; i < 2; i++ {
+		added, err := bookings.AddToWaitlist(ctx, booking.ID, boris.ID)
+		if err != nil || added != (i == 0) {
+			t.Fatalf("Attempt %d: expected added=%v, got: %v (%v)", i, i == 0, added, err)
+		}
+	}
+	if _, err := bookings.AddToWaitlist(ctx, booking.ID, anna.ID); err != nil {
+		t.Fatalf("Failed to add to waitlist: %v", err)
+	}
+	entries, err := bookings.GetWaitlist(ctx, booking.ID)
+	if err != nil || len(entries) != 2 || entries[0].UserID != boris.ID || entries[0].User.FirstName != "Boris" {
+		t.Fatalf("Expected Boris first in a queue of 2, got: %+v (%v)", entries, err)
+	}
+
+	if removed, err := bookings.RemoveFromWaitlist(ctx, booking.ID, boris.ID); err != nil || !removed {
+		t.Errorf("Expected Boris to be removed, got: %v (%v)", removed, err)
+	}
+	if removed, _ := bookings.RemoveFromWaitlist(ctx, booking.ID, boris.ID); removed {
+		t.Error("Expected nothing to remove the second time")
+	}
+
+	booking.MaxParticipants = 3
+	if err := bookings.Update(ctx, booking); err != nil {
+		t.Fatalf("Failed to update booking: %v", err)
+	}
+	if loaded, _ := bookings.GetByID(ctx, booking.ID); loaded.MaxParticipants != 3 {
+		t.Errorf("Expected max_participants to be updated, got: %d", loaded.MaxParticipants)
+	}
+}
+
+func TestSQLite_BookingQuarantine(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	users := NewUserRepository(db)
+	rooms := NewRoomRepository(db)
+	bookings := NewBookingRepository(db)
+	quarantine := NewQuarantineRepository(db)
+
+	creator := &models.User{TelegramID: 1, FirstName: "Creator"}
+	if err := users.Create(ctx, creator); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	room := &models.Room{Name: "Hall", IsActive: true}
+	if err := rooms.Create(ctx, room); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+	start := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+	booking := &models.Booking{RoomID: room.ID, CreatorID: creator.ID, StartTime: start, EndTime: start.Add(time.Hour), Title: "Casino night"}
+	if err := bookings.Create(ctx, booking); err != nil {
+		t.Fatalf("Failed to create booking: %v", err)
+	}
+
+	// Вторая запись в ожидании для того же бронирования не создаётся
+	for i := 0; i < 2; i++ {
+		entry := &models.BookingQuarantine{BookingID: booking.ID, Event: "booking.created", Status: models.QuarantinePending, Violations: datatypes.JSON(`[{"field":"title","rule":"wordlist"}]`)}
+		created, err := quarantine.Create(ctx, entry)
+		if err != nil || created != (i == 0) {
+			t.Fatalf("Attempt %d: expected created=%v, got: %v (%v)", i, i == 0, created, err)
+		}
+	}
+	pending, err := quarantine.GetPendingByBooking(ctx, booking.ID)
+	if err != nil {
+		t.Fatalf("Failed to get pending entry: %v", err)
+	}
+	if err := quarantine.UpdateHeld(ctx, pending.ID, datatypes.JSON(`[{"field":"start_time"}]`), pending.Violations); err != nil {
+		t.Fatalf("Failed to update entry: %v", err)
+	}
+
+	// Запись с отменённым бронированием по-прежнему показывает его
+	if err := bookings.Cancel(ctx, booking.ID); err != nil {
+		t.Fatalf("Failed to cancel booking: %v", err)
+	}
+	entries, total, err := quarantine.List(ctx, models.QuarantinePending, 10, 0)
+	if err != nil || total != 1 || len(entries) != 1 {
+		t.Fatalf("Expected 1 pending entry, got: %d (%v)", total, err)
+	}
+	if got := entries[0]; got.Booking.Title != "Casino night" || got.Booking.Room.Name != "Hall" || got.Booking.Creator.FirstName != "Creator" || string(got.Changes) != `[{"field":"start_time"}]` {
+		t.Errorf("Expected the entry with its booking and absorbed changes, got: %+v", got)
+	}
+
+	if reviewed, err := quarantine.Review(ctx, pending.ID, models.QuarantineRejected, creator.ID, time.Now()); err != nil || !reviewed {
+		t.Fatalf("Expected the entry to be reviewed, got: %v (%v)", reviewed, err)
+	}
+	if reviewed, _ := quarantine.Review(ctx, pending.ID, models.QuarantineReleased, creator.ID, time.Now()); reviewed {
+		t.Error("Expected a reviewed entry not to be reviewed again")
+	}
+	if _, err := quarantine.GetPendingByBooking(ctx, booking.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected no pending entry, got: %v", err)
+	}
+
+	// После рассмотрения бронирование снова может попасть в карантин
+	if created, err := quarantine.Create(ctx, &models.BookingQuarantine{BookingID: booking.ID, Event: "booking.updated", Status: models.QuarantinePending}); err != nil || !created {
+		t.Errorf("Expected a new pending entry, got: %v (%v)", created, err)
+	}
+	if _, total, _ := quarantine.List(ctx, "", 10, 0); total != 2 {
+		t.Errorf("Expected 2 entries in total, got: %d", total)
+	}
+}
+
+func TestSQLite_RequestDeadlineCancelsQueries(t *testing.T) {
+	db := newSQLiteDB(t)
+	rooms := NewRoomRepository(db)
+	if err := rooms.Create(context.Background(), &models.Room{Name: "Big Room", IsActive: true}); err != nil {
+		t.Fatalf("Failed to create room: %v", err)
+	}
+
+	// Дедлайн запроса (middleware.Timeout) уже истёк: запрос к БД не выполняется ни вне транзакции, ни в ней
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	if _, err := rooms.GetByID(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got: %v", err)
+	}
+	err := NewTxManager(db).WithinTx(ctx, func(ctx context.Context) error {
+		_, err := rooms.GetByID(ctx, 1)
+		return err
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded in a transaction, got: %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/space/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TeamRepository handles database operations for teams
+type TeamRepository struct {
+	db *gorm.DB
+}
+
+// NewTeamRepository creates a new team repository
+func NewTeamRepository(db *gorm.DB) *TeamRepository {
+	return &TeamRepository{db: db}
+}
+
+// Create creates a team
+func (r *TeamRepository) Create(ctx context.Context, team *models.Team) error {
+	// Участники добавляются отдельно (AddMembers): сами пользователи не пересохраняются
+	return dbFromContext(ctx, r.db).Omit("Members").Create(team).Error
+}
+
+// GetByID gets a team with its members
+func (r *TeamRepository) GetByID(ctx context.Context, id uint) (*models.Team, error) {
+	var team models.Team
+	if err := dbFromContext(ctx, r.db).Preload("Members").First(&team, id).Error; err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+// TeamFilter narrows List; empty fields are ignored
+type TeamFilter struct {
+	Name       string
+	ExternalID string
+}
+
+// List gets a page of teams with members and their total count, by ID
+func (r *TeamRepository) List(ctx context.Context, filter TeamFilter, limit, offset int) ([]models.Team, int64, error) {
+	query := dbFromContext(ctx, r.db).Model(&models.Team{})
+	if filter.Name != "" {
+		query = query.Where("name = ?", filter.Name)
+	}
+	if filter.ExternalID != "" {
+		query = query.Where("external_id = ?", filter.ExternalID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var teams []models.Team
+	err := query.Preload("Members").Order("id").Limit(limit).Offset(offset).Find(&teams).Error
+	return teams, total, err
+}
+
+// Update saves the team's own fields
+func (r *TeamRepository) Update(ctx context.Context, team *models.Team) error {
+	return dbFromContext(ctx, r.db).Omit("Members").Save(team).Error
+}
+
+// AddMembers adds users to a team; existing members are skipped
+func (r *TeamRepository) AddMembers(ctx context.Context, teamID uint, userIDs []uint) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	rows := make([]map[string]interface{}, len(userIDs))
+	for i, id := range userIDs {
+		rows[i] = map[string]interface{}{"team_id": teamID, "user_id": id}
+	}
+	return dbFromContext(ctx, r.db).Table("team_members").
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(rows).Error
+}
+
+// RemoveMembers removes users from a team
+func (r *TeamRepository) RemoveMembers(ctx context.Context, teamID uint, userIDs []uint) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	return dbFromContext(ctx, r.db).
+		Exec("DELETE FROM team_members WHERE team_id = ? AND user_id IN ?", teamID, userIDs).Error
+}
+
+// ClearMembers removes all members of a team
+func (r *TeamRepository) ClearMembers(ctx context.Context, teamID uint) error {
+	return dbFromContext(ctx, r.db).Exec("DELETE FROM team_members WHERE team_id = ?", teamID).Error
+}
+
+// Delete deletes a team and its memberships
+func (r *TeamRepository) Delete(ctx context.Context, id uint) error {
+	db := dbFromContext(ctx, r.db)
+	if err := db.Exec("DELETE FROM team_members WHERE team_id = ?", id).Error; err != nil {
+		return err
+	}
+	result := db.Delete(&models.Team{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
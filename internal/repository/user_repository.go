@@ -1,7 +1,9 @@
 package repository
 
 import (
-	"log"
+	"context"
+	"log/slog"
+	"time"
 
 	"github.com/space/backend/internal/models"
 	"github.com/space/backend/pkg/validator"
@@ -19,14 +21,14 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 }
 
 // Create creates a new user
-func (r *UserRepository) Create(user *models.User) error {
-	return r.db.Create(user).Error
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	return dbFromContext(ctx, r.db).Create(user).Error
 }
 
 // GetByID gets a user by ID
-func (r *UserRepository) GetByID(id uint) (*models.User, error) {
+func (r *UserRepository) GetByID(ctx context.Context, id uint) (*models.User, error) {
 	var user models.User
-	err := r.db.First(&user, id).Error
+	err := dbFromContext(ctx, r.db).First(&user, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -34,9 +36,29 @@ func (r *UserRepository) GetByID(id uint) (*models.User, error) {
 }
 
 // GetByTelegramID gets a user by Telegram ID
-func (r *UserRepository) GetByTelegramID(telegramID int64) (*models.User, error) {
+func (r *UserRepository) GetByTelegramID(ctx context.Context, telegramID int64) (*models.User, error) {
 	var user models.User
-	err := r.db.Where("telegram_id = ?", telegramID).First(&user).Error
+	err := dbFromContext(ctx, r.db).Where("telegram_id = ?", telegramID).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetByEmail gets a user by email (stored lowercase)
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	var user models.User
+	err := dbFromContext(ctx, r.db).Where("email = ?", email).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetByOIDCSubject gets a user by the subject of the OIDC provider
+func (r *UserRepository) GetByOIDCSubject(ctx context.Context, subject string) (*models.User, error) {
+	var user models.User
+	err := dbFromContext(ctx, r.db).Where("oidc_subject = ?", subject).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
@@ -45,12 +67,12 @@ func (r *UserRepository) GetByTelegramID(telegramID int64) (*models.User, error)
 
 // GetOrCreate gets a user by Telegram ID or creates a new one
 // NOTE: This method does NOT update existing users. Use SyncFromTelegram() for that.
-func (r *UserRepository) GetOrCreate(telegramID int64, username, firstName, lastName, languageCode string) (*models.User, error) {
-	user, err := r.GetByTelegramID(telegramID)
+func (r *UserRepository) GetOrCreate(ctx context.Context, telegramID int64, username, firstName, lastName, languageCode string) (*models.User, error) {
+	user, err := r.GetByTelegramID(ctx, telegramID)
 	if err == nil {
 		// Пользователь существует - возвращаем без изменений
 		// Данные пользователя могут быть отредактированы вручную, не перезаписываем их
-		log.Printf("DEBUG: Found existing user (ID: %d, TelegramID: %d) - keeping user-managed data", user.ID, user.TelegramID)
+		slog.Debug("found existing user, keeping user-managed data", "user_id", user.ID, "telegram_id", user.TelegramID)
 		return user, nil
 	}
 
@@ -59,7 +81,7 @@ func (r *UserRepository) GetOrCreate(telegramID int64, username, firstName, last
 	}
 
 	// Создаём нового пользователя с данными из Telegram
-	log.Printf("DEBUG: Creating NEW user (TelegramID: %d, username: %s)", telegramID, username)
+	slog.Debug("creating new user", "telegram_id", telegramID, "username", username)
 
 	user = &models.User{
 		TelegramID:   telegramID,
@@ -71,110 +93,237 @@ func (r *UserRepository) GetOrCreate(telegramID int64, username, firstName, last
 		// Userpic будет установлен через SyncUserpic после создания
 	}
 
-	err = r.Create(user)
+	err = r.Create(ctx, user)
 	if err != nil {
 		return nil, err
 	}
 
-	log.Printf("DEBUG: Created user with ID: %d, Role: %s", user.ID, user.Role)
+	slog.Info("created user", "user_id", user.ID, "telegram_id", telegramID, "role", user.Role)
 	return user, nil
 }
 
 // SyncFromTelegram explicitly updates user data from Telegram
 // Use this when you want to sync user's Telegram profile changes
-func (r *UserRepository) SyncFromTelegram(telegramID int64, username, firstName, lastName, languageCode string) (*models.User, error) {
-	user, err := r.GetByTelegramID(telegramID)
+func (r *UserRepository) SyncFromTelegram(ctx context.Context, telegramID int64, username, firstName, lastName, languageCode string) (*models.User, error) {
+	user, err := r.GetByTelegramID(ctx, telegramID)
 	if err != nil {
 		return nil, err
 	}
 
-	log.Printf("DEBUG: Syncing user ID %d from Telegram", user.ID)
+	slog.Debug("syncing user from Telegram", "user_id", user.ID)
 	updated := false
 
 	if user.Username != username {
-		log.Printf("DEBUG: Username changed: '%s' -> '%s'", user.Username, username)
+		slog.Debug("username changed", "user_id", user.ID, "old", user.Username, "new", username)
 		user.Username = username
 		updated = true
 	}
 	if user.FirstName != firstName {
-		log.Printf("DEBUG: FirstName changed: '%s' -> '%s'", user.FirstName, firstName)
+		slog.Debug("first name changed", "user_id", user.ID, "old", user.FirstName, "new", firstName)
 		user.FirstName = firstName
 		updated = true
 	}
 	if user.LastName != lastName {
-		log.Printf("DEBUG: LastName changed: '%s' -> '%s'", user.LastName, lastName)
+		slog.Debug("last name changed", "user_id", user.ID, "old", user.LastName, "new", lastName)
 		user.LastName = lastName
 		updated = true
 	}
 	if user.LanguageCode != languageCode {
-		log.Printf("DEBUG: LanguageCode changed: '%s' -> '%s'", user.LanguageCode, languageCode)
+		slog.Debug("language code changed", "user_id", user.ID, "old", user.LanguageCode, "new", languageCode)
 		user.LanguageCode = languageCode
 		updated = true
 	}
 
 	if updated {
-		log.Printf("DEBUG: Updating user ID %d with Telegram data", user.ID)
-		if err := r.Update(user); err != nil {
+		slog.Debug("updating user with Telegram data", "user_id", user.ID)
+		if err := r.Update(ctx, user); err != nil {
 			return nil, err
 		}
 	} else {
-		log.Printf("DEBUG: No changes for user ID %d", user.ID)
+		slog.Debug("no Telegram changes for user", "user_id", user.ID)
 	}
 
 	return user, nil
 }
 
 // SyncUserpic updates user's profile picture URL
-func (r *UserRepository) SyncUserpic(telegramID int64, userpicURL string) error {
-	user, err := r.GetByTelegramID(telegramID)
+func (r *UserRepository) SyncUserpic(ctx context.Context, telegramID int64, userpicURL string) error {
+	user, err := r.GetByTelegramID(ctx, telegramID)
 	if err != nil {
 		return err
 	}
 
 	// Обновляем только если URL изменился
 	if user.Userpic != userpicURL {
-		log.Printf("DEBUG: Updating userpic for user ID %d: '%s' -> '%s'", user.ID, user.Userpic, userpicURL)
-		user.Userpic = userpicURL
-		return r.Update(user)
+		slog.Debug("updating userpic", "user_id", user.ID)
+		return dbFromContext(ctx, r.db).Model(&models.User{}).Where("id = ?", user.ID).
+			UpdateColumn("userpic", userpicURL).Error
 	}
 
-	log.Printf("DEBUG: Userpic unchanged for user ID %d", user.ID)
+	slog.Debug("userpic unchanged", "user_id", user.ID)
 	return nil
 }
 
+// ClaimUserpicSync marks the user's userpic as synced at now unless it was synced after notBefore
+// Возвращает false, если синхронизация не нужна или её уже забрал параллельный запрос
+func (r *UserRepository) ClaimUserpicSync(ctx context.Context, userID uint, now, notBefore time.Time) (bool, error) {
+	result := dbFromContext(ctx, r.db).Model(&models.User{}).
+		Where("id = ? AND (userpic_synced_at IS NULL OR userpic_synced_at < ?)", userID, notBefore).
+		UpdateColumn("userpic_synced_at", now)
+	return result.RowsAffected == 1, result.Error
+}
+
 // UpdateAbout updates user's about/bio field
-func (r *UserRepository) UpdateAbout(userID uint, about string) error {
-	return r.db.Model(&models.User{}).Where("id = ?", userID).Update("about", about).Error
+func (r *UserRepository) UpdateAbout(ctx context.Context, userID uint, about string) error {
+	return dbFromContext(ctx, r.db).Model(&models.User{}).Where("id = ?", userID).Update("about", about).Error
 }
 
 // Update updates a user
-func (r *UserRepository) Update(user *models.User) error {
-	return r.db.Save(user).Error
+func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
+	return dbFromContext(ctx, r.db).Save(user).Error
 }
 
-// GetPhonebook gets all users in the phonebook
-func (r *UserRepository) GetPhonebook() ([]models.User, error) {
-	var users []models.User
-	err := r.db.Where("is_in_phone_book = ?", true).Order("last_name, first_name").Find(&users).Error
-	return users, err
+// UpdateColumns updates only the given columns of a user (and updated_at)
+// В отличие от Save не переписывает всю строку: параллельные изменения других полей не теряются
+func (r *UserRepository) UpdateColumns(ctx context.Context, user *models.User, columns ...string) error {
+	return dbFromContext(ctx, r.db).Select(columns).Updates(user).Error
 }
 
-// Search searches users by name or username
-func (r *UserRepository) Search(query string) ([]models.User, error) {
-	var users []models.User
+// SetBookingLimit sets the maximum number of active upcoming bookings of a user; nil removes the limit
+func (r *UserRepository) SetBookingLimit(ctx context.Context, userID uint, limit *int) error {
+	result := dbFromContext(ctx, r.db).Model(&models.User{}).Where("id = ?", userID).UpdateColumn("booking_limit", limit)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// TightenBookingLimit lowers the booking limit of a user to limit unless it is already lower
+// Возвращает false, если лимит не изменился
+func (r *UserRepository) TightenBookingLimit(ctx context.Context, userID uint, limit int) (bool, error) {
+	result := dbFromContext(ctx, r.db).Model(&models.User{}).
+		Where("id = ? AND (booking_limit IS NULL OR booking_limit > ?)", userID, limit).
+		UpdateColumn("booking_limit", limit)
+	return result.RowsAffected == 1, result.Error
+}
+
+// GetPhonebook gets a page of users in the phonebook and their total count (read replica)
+func (r *UserRepository) GetPhonebook(ctx context.Context, limit, offset int, order string) ([]models.User, int64, error) {
+	query := onReplica(dbFromContext(ctx, r.db)).Model(&models.User{}).
+		Where("is_in_phone_book = ? AND deactivated_at IS NULL", true)
+	return pageOfUsers(query, limit, offset, order)
+}
+
+// ExportPhonebookBatches passes the phonebook to fn in batches of batchSize,
+// ordered by last name, first name and ID (read replica)
+// Как и ExportBatches бронирований, пачки читаются keyset-запросами без OFFSET
+func (r *UserRepository) ExportPhonebookBatches(ctx context.Context, batchSize int, fn func([]models.User) error) error {
+	var after *models.User
+	for {
+		query := onReplica(dbFromContext(ctx, r.db)).
+			Where("is_in_phone_book = ? AND deactivated_at IS NULL", true)
+		if after != nil {
+			query = query.Where("(last_name > ? OR (last_name = ? AND (first_name > ? OR (first_name = ? AND id > ?))))",
+				after.LastName, after.LastName, after.FirstName, after.FirstName, after.ID)
+		}
+
+		var batch []models.User
+		if err := query.Order("last_name, first_name, id").Limit(batchSize).Find(&batch).Error; err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if len(batch) < batchSize {
+			return nil
+		}
+		after = &batch[len(batch)-1]
+	}
+}
+
+// Search searches users in the phonebook by name or username (read replica)
+func (r *UserRepository) Search(ctx context.Context, search string, limit, offset int, order string) ([]models.User, int64, error) {
 	// Экранируем специальные символы LIKE для безопасности
-	escapedQuery := validator.EscapeLike(query)
+	escapedQuery := validator.EscapeLike(search)
 	searchPattern := "%" + escapedQuery + "%"
-	err := r.db.Where(
-		"is_in_phone_book = ? AND (first_name ILIKE ? OR last_name ILIKE ? OR username ILIKE ?)",
-		true, searchPattern, searchPattern, searchPattern,
-	).Order("last_name, first_name").Find(&users).Error
-	return users, err
+	// Группа OR строится от r.db, чтобы условия не попали в общий statement запроса
+	nameMatches := r.db.Where(ilike(r.db, "first_name"), searchPattern).
+		Or(ilike(r.db, "last_name"), searchPattern).
+		Or(ilike(r.db, "username"), searchPattern)
+	query := onReplica(dbFromContext(ctx, r.db)).Model(&models.User{}).
+		Where("is_in_phone_book = ? AND deactivated_at IS NULL", true).
+		Where(nameMatches)
+	return pageOfUsers(query, limit, offset, order)
+}
+
+// pageOfUsers считает строки запроса телефонной книги и читает одну страницу
+// Без order телефонная книга сортируется по фамилии и имени
+func pageOfUsers(query *gorm.DB, limit, offset int, order string) ([]models.User, int64, error) {
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []models.User
+	err := orderBy(query, order, "last_name, first_name, id").Limit(limit).Offset(offset).Find(&users).Error
+	return users, total, err
+}
+
+// List gets a page of all users and their total count, by ID unless order is given
+func (r *UserRepository) List(ctx context.Context, limit, offset int, order string) ([]models.User, int64, error) {
+	query := dbFromContext(ctx, r.db).Model(&models.User{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []models.User
+	err := orderBy(query, order, "id").Limit(limit).Offset(offset).Find(&users).Error
+	return users, total, err
+}
+
+// DirectoryFilter narrows ListDirectory; empty fields are ignored
+type DirectoryFilter struct {
+	Email      string
+	ExternalID string
+}
+
+// ListDirectory gets a page of users managed through the directory (with an email), by ID
+// Отключённые пользователи тоже возвращаются: каталог видит их как active = false
+func (r *UserRepository) ListDirectory(ctx context.Context, filter DirectoryFilter, limit, offset int) ([]models.User, int64, error) {
+	query := dbFromContext(ctx, r.db).Model(&models.User{}).Where("email IS NOT NULL")
+	if filter.Email != "" {
+		query = query.Where("email = ?", filter.Email)
+	}
+	if filter.ExternalID != "" {
+		query = query.Where("external_id = ?", filter.ExternalID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []models.User
+	err := query.Order("id").Limit(limit).Offset(offset).Find(&users).Error
+	return users, total, err
+}
+
+// UpdateRole updates user's role
+func (r *UserRepository) UpdateRole(ctx context.Context, userID uint, role models.UserRole) error {
+	return dbFromContext(ctx, r.db).Model(&models.User{}).Where("id = ?", userID).Update("role", role).Error
 }
 
 // GetByIDs gets multiple users by their IDs
-func (r *UserRepository) GetByIDs(ids []uint) ([]models.User, error) {
+func (r *UserRepository) GetByIDs(ctx context.Context, ids []uint) ([]models.User, error) {
 	var users []models.User
-	err := r.db.Where("id IN ?", ids).Find(&users).Error
+	err := dbFromContext(ctx, r.db).Where("id IN ?", ids).Find(&users).Error
 	return users, err
 }
@@ -0,0 +1,41 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+
+	"github.com/space/backend/docs"
+	"github.com/space/backend/internal/buildinfo"
+	"github.com/space/backend/internal/config"
+)
+
+// docsCSP разрешает inline-скрипты и стили страницы Swagger UI; общий CSP их блокирует
+const docsCSP = "default-src 'self'; script-src 'self' 'unsafe-inline'; style-src 'self' 'unsafe-inline'; img-src 'self' data:"
+
+// registerDocs отдаёт Swagger UI и спецификацию OpenAPI под /api/docs, пока enabled (API_DOCS_ENABLED)
+// Спецификация генерируется `make docs` и встроена в бинарник (пакет docs)
+func registerDocs(api *gin.RouterGroup, cfg *config.Config, enabled func() bool) {
+	docs.SwaggerInfo.Version = buildinfo.Version
+
+	group := api.Group("/docs", featureFlag(enabled))
+	if cfg.APIDocsPassword != "" {
+		group.Use(gin.BasicAuth(gin.Accounts{cfg.APIDocsUser: cfg.APIDocsPassword}))
+	}
+	group.Use(docsHeaders)
+
+	group.GET("", func(c *gin.Context) {
+		c.Redirect(http.StatusFound, c.Request.URL.Path+"/index.html")
+	})
+	group.GET("/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL("doc.json")))
+}
+
+// docsHeaders заменяет заголовки SecurityHeaders, рассчитанные на JSON API
+func docsHeaders(c *gin.Context) {
+	c.Writer.Header().Set("Content-Security-Policy", docsCSP)
+	// Тип ответа определяет file server по расширению, а не глобальный application/json
+	c.Writer.Header().Del("Content-Type")
+	c.Next()
+}
@@ -0,0 +1,35 @@
+package router
+
+import (
+	"context"
+	"net/http/pprof"
+
+	"github.com/gin-gonic/gin"
+)
+
+// registerPprof подключает net/http/pprof к группе администратора, пока enabled (PPROF_ENABLED)
+// Пути: <group>/debug/pprof/ (индекс), /heap, /goroutine, /allocs, /profile?seconds=N, /trace?seconds=N
+func registerPprof(admin *gin.RouterGroup, enabled func() bool) {
+	debug := admin.Group("/debug/pprof", featureFlag(enabled))
+
+	debug.GET("/", gin.WrapF(pprof.Index))
+	debug.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+	debug.GET("/symbol", gin.WrapF(pprof.Symbol))
+	debug.POST("/symbol", gin.WrapF(pprof.Symbol))
+
+	// CPU-профиль и трасса длятся ?seconds=N и не должны обрываться общим REQUEST_TIMEOUT
+	debug.GET("/profile", withoutDeadline, gin.WrapF(pprof.Profile))
+	debug.GET("/trace", withoutDeadline, gin.WrapF(pprof.Trace))
+
+	// pprof.Index определяет профиль по префиксу /debug/pprof/, поэтому
+	// именованные профили (heap, goroutine, allocs, block, mutex, threadcreate) отдаются напрямую
+	debug.GET("/:profile", func(c *gin.Context) {
+		pprof.Handler(c.Param("profile")).ServeHTTP(c.Writer, c.Request)
+	})
+}
+
+// withoutDeadline снимает дедлайн middleware.Timeout с контекста запроса
+func withoutDeadline(c *gin.Context) {
+	c.Request = c.Request.WithContext(context.WithoutCancel(c.Request.Context()))
+	c.Next()
+}
@@ -1,47 +1,144 @@
 package router
 
 import (
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/space/backend/internal/buildinfo"
+	"github.com/space/backend/internal/config"
 	"github.com/space/backend/internal/handler"
+	"github.com/space/backend/internal/metrics"
 	"github.com/space/backend/internal/middleware"
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/internal/scheduler"
 	"github.com/space/backend/internal/service"
+	"github.com/space/backend/pkg/telegram"
 )
 
 // SetupRouter configures all routes for the application
 func SetupRouter(
-	botToken string,
-	botAPIToken string,
-	allowedChatID int64,
-	allowedOrigins []string,
-	environment string,
-	authDateTTLMiniApp int64,
-	authDateTTLLoginWidget int64,
+	liveConfig *config.Live,
 	userService *service.UserService,
 	roomService *service.RoomService,
 	bookingService *service.BookingService,
 	notificationService *service.NotificationService,
+	slackService *service.SlackService,
+	hookService *service.HookService,
+	doorAccessService *service.DoorAccessService,
+	oidcService *service.OIDCService,
+	scimService *service.SCIMService,
+	importService *service.ImportService,
+	widgetService *service.WidgetService,
+	broadcastService *service.BroadcastService,
+	apiKeyService *service.APIKeyService,
+	auditService *service.AuditService,
+	purgeService *service.PurgeService,
+	retentionService *service.RetentionService,
+	abuseService *service.AbuseService,
+	billingService *service.BillingService,
+	holidayService *service.HolidayService,
+	feedbackService *service.FeedbackService,
+	checkInService *service.CheckInService,
+	noShowService *service.NoShowService,
+	utilizationService *service.UtilizationService,
+	cleaningService *service.CleaningService,
+	bookingHistoryService *service.BookingHistoryService,
+	parkingService *service.ParkingService,
+	floorService *service.FloorService,
+	ownershipService *service.OwnershipService,
+	rentalService *service.RentalService,
+	moderationService *service.ModerationService,
+	healthService *service.HealthService,
+	sched *scheduler.Scheduler,
+	logger *slog.Logger,
 ) *gin.Engine {
-	r := gin.Default()
+	// Снимок конфигурации на момент запуска; перезагружаемые параметры
+	// (origins, chat ID, лимиты) читаются из liveConfig
+	cfg := liveConfig.Get()
+
+	// gin.New вместо gin.Default: логирование запросов делает RequestLogger
+	r := gin.New()
+	r.Use(gin.Recovery())
+
+	// Токены с поддержкой ротации: предыдущий принимается до окончания grace-периода
+	botTokens := middleware.TokenSet{
+		Current:    cfg.TelegramBotToken,
+		Previous:   cfg.TelegramBotTokenPrevious,
+		GraceUntil: cfg.TokenRotationGraceUntil,
+	}
+	botAPITokens := middleware.TokenSet{
+		Current:    cfg.BotAPIToken,
+		Previous:   cfg.BotAPITokenPrevious,
+		GraceUntil: cfg.TokenRotationGraceUntil,
+	}
+
+	// Membership-проверки всегда выполняются текущим токеном бота
+	botToken := botTokens.Current
+	allowedChatID := liveConfig.AllowedChatID
+	allowedOrigins := liveConfig.AllowedOrigins
+	environment := cfg.Environment
 
 	// Настройка доверенных прокси - отключаем для безопасности
 	// Если используете прокси (nginx, CloudFlare и т.д.), укажите их IP
 	r.SetTrustedProxies(nil)
 
+	// Структурированный лог запросов с request_id - первым, чтобы логгер был доступен всем
+	r.Use(middleware.RequestLogger(logger))
+
+	// Дедлайн запроса - контекст передаётся в обработчики и внешние вызовы
+	r.Use(middleware.Timeout(cfg.RequestTimeout))
+
+	// Язык ответа по Accept-Language (или language_code пользователя)
+	r.Use(middleware.Locale())
+	registerJSONFieldNames()
+
+	// Журнал аудита изменяющих запросов (актор определяется после auth middleware)
+	r.Use(middleware.Audit(auditService))
+
+	// Ответы на ошибки сервисов, переданные обработчиками через c.Error
+	r.Use(middleware.Errors())
+
 	// Global middleware - безопасность
 	// 1. Security Headers - должны быть первыми
-	r.Use(middleware.SecurityHeaders())
+	r.Use(middleware.SecurityHeaders(middleware.SecurityHeadersConfig{
+		ContentSecurityPolicy: cfg.SecurityCSP,
+		FrameOptions:          cfg.SecurityFrameOptions,
+		ReferrerPolicy:        cfg.SecurityReferrerPolicy,
+		PermissionsPolicy:     cfg.SecurityPermissionsPolicy,
+	}))
 
 	// 2. HTTPS Enforcement - перенаправление на HTTPS в production
-	r.Use(middleware.HTTPSEnforcement(environment))
+	r.Use(middleware.HTTPSEnforcement(environment, cfg.SecurityHSTS))
 
 	// 3. CORS с ограничением по доменам
 	r.Use(middleware.CORS(allowedOrigins))
 
-	// 4. Rate Limiting - 100 запросов в минуту с одного IP
-	rateLimiter := middleware.NewRateLimiter(100, 1*time.Minute)
-	r.Use(rateLimiter.RateLimit())
+	// 4. Rate Limiting - RATE_LIMIT_RPM запросов в минуту с одного IP для анонимных запросов
+	// Авторизованные запросы лимитируются по пользователю после auth middleware
+	rateLimiter := middleware.NewRateLimiter(cfg.RateLimitRPM, 1*time.Minute, cfg.RateLimitCleanupInterval)
+	r.Use(rateLimiter.RateLimitAnonymous())
+
+	// Лимиты на пользователя и на отдельные маршруты (применяются после авторизации)
+	userRateLimiter := middleware.NewRateLimiter(cfg.RateLimitUserRPM, 1*time.Minute, cfg.RateLimitCleanupInterval)
+	routeRateLimiter := middleware.NewRouteRateLimiter(routePolicies(cfg.RouteRateLimits), cfg.RateLimitCleanupInterval)
+
+	// Применяем перезагруженные лимиты и сбрасываем кэш членства при смене группы
+	lastChatID := cfg.AllowedChatID
+	liveConfig.OnReload(func(next *config.Config) {
+		rateLimiter.SetRate(next.RateLimitRPM)
+		userRateLimiter.SetRate(next.RateLimitUserRPM)
+		routeRateLimiter.SetPolicies(routePolicies(next.RouteRateLimits))
+		if next.AllowedChatID != lastChatID {
+			telegram.GlobalCache.Reset()
+			lastChatID = next.AllowedChatID
+		}
+	})
 
 	// 5. Логирование подозрительных запросов
 	r.Use(middleware.SecurityLogger(allowedOrigins))
@@ -49,42 +146,96 @@ func SetupRouter(
 	// 6. Проверка Referer (только для защищённых эндпоинтов)
 	r.Use(middleware.RefererCheck(allowedOrigins))
 
-	// Health check
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status":  "ok",
-			"service": "Space Backend API",
-		})
+	// Liveness: процесс жив и отвечает (/health оставлен для существующих проверок)
+	healthHandler := handler.NewHealthHandler(healthService)
+	r.GET("/health", healthHandler.Live)
+	r.GET("/health/live", healthHandler.Live)
+
+	// Версия сборки: сопоставление поведения с конкретным деплоем
+	r.GET("/version", func(c *gin.Context) {
+		c.JSON(200, buildinfo.Get())
 	})
 
+	// Readiness: БД, миграции, планировщик (503), Telegram API и webhook бота (degraded);
+	// при остановке сервера - 503 draining
+	r.GET("/health/ready", healthHandler.Ready)
+
 	// API group
 	api := r.Group("/api")
 
+	// Swagger UI и OpenAPI-спецификация (в production - только с паролем)
+	// Пароль задаётся при старте: без него в production документацию не включает и перезагрузка
+	registerDocs(api, cfg, func() bool {
+		return liveConfig.Get().APIDocsEnabled && (cfg.APIDocsPassword != "" || cfg.Environment != "production")
+	})
+
+	// Короткий кэш публичных эндпоинтов с ETag - киоски опрашивают их каждые несколько секунд
+	publicCache := middleware.NewResponseCache(cfg.PublicCacheTTL)
+
 	// Public routes (no auth required)
 	public := api.Group("/public")
+	if cfg.PublicCacheTTL > 0 {
+		public.Use(publicCache.Cache("with_equipment"))
+	}
 	{
 		roomHandler := handler.NewRoomHandler(roomService)
 		public.GET("/rooms", roomHandler.GetAllRooms)
 		public.GET("/rooms/:id", roomHandler.GetRoom)
 	}
 
-	// Protected routes (require Telegram auth and group membership)
+	// Заявки сторонних организаций на аренду: форма с капчей, без авторизации
+	var rentalHandler *handler.RentalHandler
+	if rentalService != nil {
+		rentalHandler = handler.NewRentalHandler(rentalService)
+		public.POST("/rental-requests", rentalHandler.SubmitRequest)
+	}
+
+	// Виджет доступности для публичного сайта: токен в query, CORS только для WIDGET_ORIGIN
+	if widgetService != nil {
+		widget := api.Group("/widget")
+		widget.Use(middleware.WidgetAuth(cfg.WidgetToken, cfg.WidgetOrigin))
+		if cfg.PublicCacheTTL > 0 {
+			widget.Use(publicCache.Cache("start", "end"))
+		}
+		widget.GET("/calendar", handler.NewWidgetHandler(widgetService).GetCalendar)
+	}
+
+	// Вход через OIDC: браузер приходит сюда редиректами, без initData
+	var sessions middleware.SessionAuthenticator
+	var oidcHandler *handler.OIDCHandler
+	if oidcService != nil {
+		sessions = oidcService
+		oidcHandler = handler.NewOIDCHandler(oidcService, cfg.OIDCRedirectURL, cfg.OIDCPostLoginURL, environment == "production")
+		api.GET("/auth/oidc/login", oidcHandler.Login)
+		api.GET("/auth/oidc/callback", oidcHandler.Callback)
+	}
+
+	// Protected routes (require Telegram auth or OIDC session, and group membership)
 	protected := api.Group("")
-	protected.Use(middleware.TelegramAuthMiddleware(botToken, userService, authDateTTLMiniApp, authDateTTLLoginWidget))
-	protected.Use(middleware.RequireChatMembership(botToken, allowedChatID, environment))
+	protected.Use(middleware.TelegramAuthMiddleware(botTokens, userService, sessions, cfg.AuthDateTTLMiniApp, cfg.AuthDateTTLLoginWidget))
+	protected.Use(middleware.RequireChatMembership(botToken, allowedChatID, environment, cfg.MembershipCacheTTL))
+	protected.Use(userRateLimiter.RateLimitPerUser())
+	protected.Use(routeRateLimiter.RateLimit())
 	{
 		// User routes
 		userHandler := handler.NewUserHandler(userService)
+		subscriptionHandler := handler.NewSubscriptionHandler(notificationService)
 		users := protected.Group("/users")
 		{
 			users.GET("/me", userHandler.GetProfile)
 			users.PATCH("/me", userHandler.UpdateProfile)
+			users.GET("/me/subscriptions", subscriptionHandler.GetMySubscriptions)
 			users.POST("/me/sync-telegram", userHandler.SyncFromTelegram) // Синхронизация данных из Telegram
 			users.GET("/phonebook", userHandler.GetPhonebook)
 			users.GET("/:id", userHandler.GetUserByID)     // Получить пользователя по ID
 			users.PATCH("/:id", userHandler.UpdateUserByID) // Обновить пользователя (себя или админ)
 		}
 
+		// Привязка OIDC к текущему аккаунту Telegram
+		if oidcHandler != nil {
+			protected.POST("/auth/oidc/link", oidcHandler.CreateLink)
+		}
+
 		// Room routes
 		roomHandler := handler.NewRoomHandler(roomService)
 		rooms := protected.Group("/rooms")
@@ -92,14 +243,27 @@ func SetupRouter(
 			rooms.GET("", roomHandler.GetAllRooms)
 			rooms.GET("/:id", roomHandler.GetRoom)
 			rooms.GET("/:id/equipment", roomHandler.GetRoomEquipment)
+			// Самая маленькая свободная комната под ожидаемое число участников
+			rooms.GET("/suggest", handler.NewUtilizationHandler(utilizationService).SuggestRoom)
+			// Отметка о приходе по QR-коду комнаты
+			rooms.POST("/:id/checkin", handler.NewCheckInHandler(checkInService).CheckIn)
+			// Подписка на уведомления бота о бронированиях комнаты
+			rooms.POST("/:id/subscribe", subscriptionHandler.Subscribe)
+			rooms.DELETE("/:id/subscribe", subscriptionHandler.Unsubscribe)
+			// Время закреплённой комнаты, переданное владельцами другим пользователям
+			ownershipHandler := handler.NewOwnershipHandler(ownershipService)
+			rooms.GET("/:id/delegations", ownershipHandler.ListDelegations)
+			rooms.POST("/:id/delegations", ownershipHandler.Delegate)
+			rooms.DELETE("/:id/delegations/:delegation_id", ownershipHandler.RevokeDelegation)
 
-			// Admin-only routes
-			adminRooms := rooms.Group("")
-			adminRooms.Use(middleware.RequireAdmin())
+			// Deprecated: admin-маршруты комнат перенесены в /api/admin/rooms
+			// Оставлены для совместимости со старыми клиентами
+			legacyAdminRooms := rooms.Group("")
+			legacyAdminRooms.Use(middleware.RequireAdmin(), publicCache.InvalidateOnSuccess())
 			{
-				adminRooms.POST("", roomHandler.CreateRoom)
-				adminRooms.PATCH("/:id", roomHandler.UpdateRoom)
-				adminRooms.DELETE("/:id", roomHandler.DeleteRoom)
+				legacyAdminRooms.POST("", roomHandler.CreateRoom)
+				legacyAdminRooms.PATCH("/:id", roomHandler.UpdateRoom)
+				legacyAdminRooms.DELETE("/:id", roomHandler.DeleteRoom)
 			}
 		}
 
@@ -115,19 +279,235 @@ func SetupRouter(
 			bookings.DELETE("/:id", bookingHandler.CancelBooking)
 			bookings.POST("/:id/join", bookingHandler.JoinBooking)
 			bookings.POST("/:id/leave", bookingHandler.LeaveBooking)
+			// Очередь на место в заполненном мероприятии (max_participants)
+			bookings.GET("/:id/waitlist", bookingHandler.GetWaitlistPosition)
+			bookings.POST("/:id/waitlist", bookingHandler.JoinWaitlist)
+			bookings.DELETE("/:id/waitlist", bookingHandler.LeaveWaitlist)
+
+			doorAccessHandler := handler.NewDoorAccessHandler(doorAccessService)
+			bookings.GET("/:id/access", doorAccessHandler.GetAccess)
+
+			feedbackHandler := handler.NewFeedbackHandler(feedbackService)
+			bookings.POST("/:id/feedback", feedbackHandler.SubmitFeedback)
+		}
+		// Проверка свободного времени перед отправкой формы бронирования
+		rooms.GET("/:id/availability/check", bookingHandler.CheckAvailability)
+
+		// Вкладка парковки в Mini App
+		parkingHandler := handler.NewParkingHandler(parkingService)
+		parking := protected.Group("/parking")
+		{
+			parking.GET("/spots", parkingHandler.GetSpots)
+			parking.GET("/bookings", parkingHandler.GetMyBookings)
+			parking.POST("/bookings", parkingHandler.CreateBooking)
+			parking.DELETE("/bookings/:id", parkingHandler.CancelBooking)
+		}
+
+		// Карта этажей для выбора комнаты и парковочного места
+		floorHandler := handler.NewFloorHandler(floorService)
+		floors := protected.Group("/floors")
+		{
+			floors.GET("", floorHandler.ListFloors)
+			floors.GET("/:id/map", floorHandler.GetMap)
+			floors.GET("/:id/image", floorHandler.GetImage)
+		}
+
+		// Календарь нерабочих дней пространства
+		holidayHandler := handler.NewHolidayHandler(holidayService)
+		protected.GET("/holidays", holidayHandler.ListHolidays)
+
+		// Несколько запросов за один round trip (Mini App на нестабильной мобильной сети)
+		// Подзапросы проходят через роутер заново - с авторизацией, лимитами и аудитом
+		batchHandler := handler.NewBatchHandler(r)
+		protected.POST("/batch", batchHandler.Execute)
+
+		// Admin routes - все admin-only обработчики живут здесь
+		admin := protected.Group("/admin")
+		admin.Use(middleware.RequireAdmin())
+		{
+			adminHandler := handler.NewAdminHandler(userService)
+
+			adminRooms := admin.Group("/rooms")
+			adminRooms.Use(publicCache.InvalidateOnSuccess())
+			{
+				adminRooms.POST("", roomHandler.CreateRoom)
+				adminRooms.PATCH("/:id", roomHandler.UpdateRoom)
+				adminRooms.DELETE("/:id", roomHandler.DeleteRoom)
+				// Досрочное завершение текущей встречи меняет занятость - публичный кэш сбрасывается
+				adminRooms.POST("/:id/release", bookingHandler.ReleaseRoom)
+				adminRooms.PUT("/:id/placement", floorHandler.PlaceRoom)
+			}
+			// Объявление подписчикам комнаты не меняет комнату - публичный кэш не сбрасывается
+			broadcastHandler := handler.NewBroadcastHandler(broadcastService)
+			admin.POST("/rooms/:id/broadcast", broadcastHandler.Broadcast)
+
+			adminUsers := admin.Group("/users")
+			{
+				adminUsers.GET("", adminHandler.ListUsers)
+				adminUsers.PATCH("/:id/role", adminHandler.SetUserRole)
+			}
+
+			// Очередь проверки злоупотреблений бронированиями и лимиты пользователей
+			abuseHandler := handler.NewAbuseHandler(abuseService)
+			admin.GET("/abuse-flags", abuseHandler.ListFlags)
+			admin.PATCH("/abuse-flags/:id", abuseHandler.ReviewFlag)
+			adminUsers.PUT("/:id/booking-limit", abuseHandler.SetBookingLimit)
+
+			// Учёт использования команд для выставления счетов
+			billingHandler := handler.NewBillingHandler(billingService)
+			admin.GET("/billing/usage", billingHandler.GetUsage)
+			admin.GET("/billing/costs", billingHandler.GetCosts)
+			admin.PUT("/billing/teams/:id", billingHandler.SetIncludedHours)
+
+			// Отчёт по оценкам комнат: какие комнаты требуют внимания
+			admin.GET("/room-ratings", handler.NewFeedbackHandler(feedbackService).GetRoomRatings)
+			// Заявленное и фактическое число участников: какие комнаты можно заменить меньшими
+			admin.GET("/room-utilization", handler.NewUtilizationHandler(utilizationService).GetRoomUtilization)
+			// Неявки по отметкам о приходе: кто и где систематически бронирует впустую
+			admin.GET("/reports/no-shows", handler.NewNoShowHandler(noShowService).GetNoShows)
+
+			// Задачи уборки; персонал без прав администратора работает через API-ключ (facility:cleaning)
+			// Выполнение задачи меняет готовность комнаты - публичный кэш сбрасывается
+			cleaningHandler := handler.NewCleaningHandler(cleaningService)
+			admin.GET("/cleaning-tasks", cleaningHandler.ListTasks)
+			admin.POST("/cleaning-tasks/:id/complete", publicCache.InvalidateOnSuccess(), cleaningHandler.CompleteTask)
+
+			adminFloors := admin.Group("/floors")
+			{
+				adminFloors.POST("", floorHandler.CreateFloor)
+				adminFloors.PUT("/:id", floorHandler.UpdateFloor)
+				adminFloors.DELETE("/:id", publicCache.InvalidateOnSuccess(), floorHandler.DeleteFloor)
+				adminFloors.PUT("/:id/image", floorHandler.UploadImage)
+			}
+
+			// Комнаты, закреплённые за командами-резидентами
+			ownershipHandler := handler.NewOwnershipHandler(ownershipService)
+			adminOwnerships := admin.Group("/room-ownerships")
+			{
+				adminOwnerships.GET("", ownershipHandler.ListOwnerships)
+				adminOwnerships.POST("", ownershipHandler.CreateOwnership)
+				adminOwnerships.PUT("/:id", ownershipHandler.UpdateOwnership)
+				adminOwnerships.DELETE("/:id", ownershipHandler.DeleteOwnership)
+			}
+
+			// Очередь модерации заявок на аренду: бронирование создаётся при одобрении
+			if rentalHandler != nil {
+				adminRentals := admin.Group("/rental-requests")
+				{
+					adminRentals.GET("", rentalHandler.ListRequests)
+					adminRentals.POST("/:id/approve", rentalHandler.ApproveRequest)
+					adminRentals.POST("/:id/reject", rentalHandler.RejectRequest)
+				}
+			}
+
+			// Карантин фильтра контента: рассылка о бронировании с подозрительным текстом ждёт проверки
+			if moderationService != nil {
+				moderationHandler := handler.NewModerationHandler(moderationService)
+				adminQuarantine := admin.Group("/booking-quarantine")
+				{
+					adminQuarantine.GET("", moderationHandler.ListQuarantine)
+					adminQuarantine.POST("/:id/release", moderationHandler.ReleaseQuarantine)
+					adminQuarantine.POST("/:id/reject", moderationHandler.RejectQuarantine)
+				}
+			}
+
+			adminHolidays := admin.Group("/holidays")
+			{
+				adminHolidays.POST("", holidayHandler.CreateHoliday)
+				adminHolidays.POST("/import", holidayHandler.ImportHolidays)
+				adminHolidays.PUT("/:id", holidayHandler.UpdateHoliday)
+				adminHolidays.DELETE("/:id", holidayHandler.DeleteHoliday)
+			}
+
+			apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
+			adminAPIKeys := admin.Group("/api-keys")
+			{
+				adminAPIKeys.GET("", apiKeyHandler.ListKeys)
+				adminAPIKeys.POST("", apiKeyHandler.CreateKey)
+				adminAPIKeys.DELETE("/:id", apiKeyHandler.RevokeKey)
+			}
+
+			slackHandler := handler.NewSlackHandler(slackService)
+			adminSlack := admin.Group("/slack-targets")
+			{
+				adminSlack.GET("", slackHandler.ListTargets)
+				adminSlack.POST("", slackHandler.CreateTarget)
+				adminSlack.DELETE("/:id", slackHandler.DeleteTarget)
+			}
+
+			auditHandler := handler.NewAuditHandler(auditService)
+			admin.GET("/audit", auditHandler.ListAuditLog)
+
+			// Выгрузки в CSV (открываются в Excel) и JSON для офис-менеджеров, отдаются потоком
+			exportHandler := handler.NewExportHandler(bookingService, userService)
+			adminExport := admin.Group("/export")
+			{
+				adminExport.GET("/bookings", exportHandler.ExportBookings)
+				adminExport.GET("/phonebook", exportHandler.ExportPhonebook)
+			}
+
+			// Импорт из CSV при переезде с таблиц и других систем бронирования
+			importHandler := handler.NewImportHandler(importService)
+			adminImport := admin.Group("/import")
+			{
+				adminImport.POST("/rooms", publicCache.InvalidateOnSuccess(), importHandler.ImportRooms)
+				adminImport.POST("/bookings", importHandler.ImportBookings)
+			}
+
+			configHandler := handler.NewConfigHandler(liveConfig)
+			admin.POST("/config/reload", configHandler.Reload)
+
+			purgeHandler := handler.NewPurgeHandler(purgeService)
+			admin.POST("/purge", purgeHandler.Purge)
+
+			// Правила хранения данных; применяются ежедневной фоновой задачей
+			retentionHandler := handler.NewRetentionHandler(retentionService)
+			adminRetention := admin.Group("/retention")
+			{
+				adminRetention.GET("/rules", retentionHandler.ListRules)
+				adminRetention.PUT("/rules/:entity", retentionHandler.SetRule)
+				adminRetention.DELETE("/rules/:entity", retentionHandler.DeleteRule)
+				adminRetention.GET("/preview", retentionHandler.Preview)
+			}
+			admin.PUT("/bookings/:id/retention-exempt", retentionHandler.SetBookingExempt)
+
+			// Поток событий бронирования и состояние, восстановленное из него
+			admin.GET("/bookings/:id/history", handler.NewBookingHistoryHandler(bookingHistoryService).GetHistory)
+
+			// Счётчики процесса (expvar): медленные запросы к БД, memstats
+			admin.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+			jobsHandler := handler.NewJobsHandler(sched)
+			admin.GET("/jobs", jobsHandler.ListJobs)
+
+			// Профилирование работающего процесса (память кэшей, rate limiter, горутины)
+			registerPprof(admin, func() bool { return liveConfig.Get().PprofEnabled })
 		}
 	}
 
 	// Bot API routes (require bot authentication)
 	botAPI := api.Group("/bot")
-	botAPI.Use(middleware.BotAuthMiddleware(botAPIToken, botToken, allowedChatID, environment, userService))
+	botAPI.Use(middleware.BotAuthMiddleware(botAPITokens, botToken, allowedChatID, environment, cfg.MembershipCacheTTL, userService))
+	botAPI.Use(userRateLimiter.RateLimitPerUser())
+	botAPI.Use(routeRateLimiter.RateLimit())
 	{
-		botHandler := handler.NewBotHandler(bookingService, notificationService)
+		botHandler := handler.NewBotHandler(bookingService, notificationService, userService)
 
 		// Booking endpoints for bot
 		botAPI.POST("/bookings", botHandler.CreateBooking)
+		botAPI.PATCH("/bookings/:id", botHandler.UpdateBooking)
+		botAPI.DELETE("/bookings/:id", botHandler.CancelBooking)
+		botAPI.POST("/bookings/:id/join", botHandler.JoinBooking)
+		botAPI.POST("/bookings/:id/leave", botHandler.LeaveBooking)
 		botAPI.GET("/bookings/user/:telegram_id", botHandler.GetUserBookings)
+		botAPI.POST("/bookings/user/:telegram_id/export", botHandler.ExportUserWeek)
 		botAPI.GET("/rooms/:id/bookings", botHandler.GetRoomBookings)
+		botAPI.GET("/rooms/:id/free-slots", botHandler.GetFreeSlots)
+		botAPI.GET("/digest", botHandler.GetDigest)
+
+		// Пользователи: зарегистрирован ли участник чата, обновление профиля
+		botAPI.GET("/users/:telegram_id", botHandler.GetUser)
+		botAPI.POST("/users/sync", botHandler.SyncUser)
 
 		// Notification subscription endpoints
 		botAPI.POST("/notifications/subscribe", botHandler.Subscribe)
@@ -139,8 +519,115 @@ func SetupRouter(
 		{
 			rooms.GET("", roomBotHandler.GetAllRooms)
 			rooms.GET("/:id", roomBotHandler.GetRoom)
+			rooms.GET("/:id/equipment", roomBotHandler.GetEquipmentSummaries)
 		}
+		// Инструкции к оборудованию: "как включить проектор во 2-й переговорке?"
+		botAPI.GET("/equipment/:id/instructions", roomBotHandler.GetEquipmentGuide)
+	}
+
+	// Integration routes для сторонних систем (дашборды, скрипты) по API ключам со scopes
+	integration := api.Group("/integration")
+	integration.Use(middleware.APIKeyAuthMiddleware(apiKeyService))
+	integration.Use(userRateLimiter.RateLimitPerUser())
+	integration.Use(routeRateLimiter.RateLimit())
+	{
+		roomHandler := handler.NewRoomHandler(roomService)
+		bookingHandler := handler.NewBookingHandler(bookingService)
+		botHandler := handler.NewBotHandler(bookingService, notificationService, userService)
+
+		readRooms := middleware.RequireScope(models.ScopeReadRooms)
+		readBookings := middleware.RequireScope(models.ScopeReadBookings)
+		writeBookings := middleware.RequireScope(models.ScopeWriteBookings)
+		writeRooms := middleware.RequireScope(models.ScopeWriteRooms)
+
+		integration.GET("/rooms", readRooms, roomHandler.GetAllRooms)
+		integration.GET("/rooms/:id", readRooms, roomHandler.GetRoom)
+		integration.GET("/rooms/:id/bookings", readBookings, botHandler.GetRoomBookings)
+		integration.POST("/rooms", writeRooms, publicCache.InvalidateOnSuccess(), roomHandler.CreateRoom)
+		integration.PATCH("/rooms/:id", writeRooms, publicCache.InvalidateOnSuccess(), roomHandler.UpdateRoom)
+		integration.GET("/bookings/calendar", readBookings, bookingHandler.GetCalendarEvents)
+		integration.POST("/bookings", writeBookings, bookingHandler.CreateBooking)
+		// Лента событий бронирований с курсором: надёжнее push-уведомлений для синхронизации внешних систем
+		integration.GET("/booking-events", readBookings, handler.NewBookingHistoryHandler(bookingHistoryService).GetFeed)
+
+		// Задачи уборки для персонала пространства
+		cleaning := middleware.RequireScope(models.ScopeCleaning)
+		cleaningHandler := handler.NewCleaningHandler(cleaningService)
+		integration.GET("/cleaning-tasks", cleaning, cleaningHandler.ListTasks)
+		integration.POST("/cleaning-tasks/:id/complete", cleaning, publicCache.InvalidateOnSuccess(), cleaningHandler.CompleteTask)
+
+		// REST hooks для Zapier/Make: события содержат данные бронирований
+		hookHandler := handler.NewHookHandler(hookService)
+		integration.GET("/hooks", readBookings, hookHandler.ListHooks)
+		integration.POST("/hooks", readBookings, hookHandler.Subscribe)
+		integration.GET("/hooks/samples", readBookings, hookHandler.Samples)
+		integration.DELETE("/hooks/:id", readBookings, hookHandler.Unsubscribe)
+	}
+
+	// SCIM 2.0 для синхронизации пользователей и команд из HR-каталога
+	scim := api.Group("/scim/v2")
+	scim.Use(middleware.APIKeyAuthMiddleware(apiKeyService))
+	scim.Use(middleware.RequireScope(models.ScopeProvisionUsers))
+	scim.Use(userRateLimiter.RateLimitPerUser())
+	scim.Use(routeRateLimiter.RateLimit())
+	{
+		scimHandler := handler.NewSCIMHandler(scimService)
+		scim.GET("/ServiceProviderConfig", scimHandler.ServiceProviderConfig)
+		scim.GET("/Users", scimHandler.ListUsers)
+		scim.POST("/Users", scimHandler.CreateUser)
+		scim.GET("/Users/:id", scimHandler.GetUser)
+		scim.PUT("/Users/:id", scimHandler.ReplaceUser)
+		scim.PATCH("/Users/:id", scimHandler.PatchUser)
+		scim.DELETE("/Users/:id", scimHandler.DeleteUser)
+		scim.GET("/Groups", scimHandler.ListGroups)
+		scim.POST("/Groups", scimHandler.CreateGroup)
+		scim.GET("/Groups/:id", scimHandler.GetGroup)
+		scim.PUT("/Groups/:id", scimHandler.ReplaceGroup)
+		scim.PATCH("/Groups/:id", scimHandler.PatchGroup)
+		scim.DELETE("/Groups/:id", scimHandler.DeleteGroup)
 	}
 
 	return r
 }
+
+// featureFlag отвечает 404, пока enabled возвращает false: маршрут выглядит незарегистрированным,
+// а флаг переключается перезагрузкой конфигурации
+func featureFlag(enabled func() bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !enabled() {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+		c.Next()
+	}
+}
+
+// routePolicies преобразует лимиты маршрутов из конфигурации в политики middleware
+func routePolicies(limits []config.RouteRateLimit) []middleware.RoutePolicy {
+	policies := make([]middleware.RoutePolicy, 0, len(limits))
+	for _, l := range limits {
+		policies = append(policies, middleware.RoutePolicy{
+			Method: l.Method,
+			Path:   l.Path,
+			Rate:   l.Requests,
+			Window: l.Window,
+		})
+	}
+	return policies
+}
+
+// registerJSONFieldNames заставляет валидатор gin называть поля по json-тегам,
+// чтобы сообщения об ошибках валидации совпадали с полями запроса
+func registerJSONFieldNames() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "" || name == "-" {
+			return field.Name
+		}
+		return name
+	})
+}
@@ -0,0 +1,205 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/space/backend/internal/metrics"
+)
+
+// Job - периодическая фоновая задача
+type Job struct {
+	Name     string
+	Interval time.Duration // Пауза между запусками
+	Jitter   time.Duration // Случайная добавка к паузе [0, Jitter): реплики не запускают задачу одновременно
+	Run      func(ctx context.Context) error
+}
+
+// JobStatus describes the state of a registered job
+type JobStatus struct {
+	Name      string        `json:"name"`
+	Interval  time.Duration `json:"interval" swaggertype:"integer"` // Наносекунды
+	Runs      int64         `json:"runs"`
+	Failures  int64         `json:"failures"`
+	LastRun   time.Time     `json:"last_run,omitempty"`
+	LastError string        `json:"last_error,omitempty"`
+	Running   bool          `json:"running"`
+}
+
+// Scheduler запускает зарегистрированные задачи по расписанию
+// Каждая задача работает в своей горутине и не пересекается сама с собой;
+// паника в задаче логируется и считается ошибкой запуска, остальные задачи продолжают работать
+type Scheduler struct {
+	logger *slog.Logger
+
+	mu      sync.Mutex
+	jobs    []*jobState
+	started bool
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+type jobState struct {
+	Job
+	status JobStatus
+}
+
+// New creates a scheduler
+func New(logger *slog.Logger) *Scheduler {
+	return &Scheduler{logger: logger}
+}
+
+// Register adds a job; jobs must be registered before Start
+func (s *Scheduler) Register(job Job) {
+	if job.Interval <= 0 {
+		panic(fmt.Sprintf("scheduler: job %q must have a positive interval", job.Name))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		panic(fmt.Sprintf("scheduler: job %q registered after Start", job.Name))
+	}
+	s.jobs = append(s.jobs, &jobState{
+		Job:    job,
+		status: JobStatus{Name: job.Name, Interval: job.Interval},
+	})
+}
+
+// Start launches all registered jobs
+// Первый запуск - через Interval после старта, как и у прежних тикеров
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return
+	}
+	s.started = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+	}
+	s.logger.Info("scheduler started", "jobs", len(s.jobs))
+}
+
+// Stop cancels the context of running jobs and waits for them to finish
+// Если задачи не завершились до отмены ctx, возвращается ошибка ctx
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.started {
+		s.mu.Unlock()
+		return nil
+	}
+	s.started = false
+	s.cancel()
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.logger.Info("scheduler stopped")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("scheduler stop: %w", ctx.Err())
+	}
+}
+
+// Running reports whether the scheduler has been started and not stopped
+func (s *Scheduler) Running() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.started
+}
+
+// Jobs returns the status of all registered jobs
+func (s *Scheduler) Jobs() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]JobStatus, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		statuses = append(statuses, job.status)
+	}
+	return statuses
+}
+
+func (s *Scheduler) loop(ctx context.Context, job *jobState) {
+	defer s.wg.Done()
+
+	timer := time.NewTimer(nextDelay(job.Interval, job.Jitter))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		s.runOnce(ctx, job)
+		timer.Reset(nextDelay(job.Interval, job.Jitter))
+	}
+}
+
+// runOnce выполняет задачу один раз и обновляет статус и метрики
+func (s *Scheduler) runOnce(ctx context.Context, job *jobState) {
+	s.mu.Lock()
+	job.status.Running = true
+	s.mu.Unlock()
+
+	start := time.Now()
+	err := safeRun(ctx, job.Run)
+	elapsed := time.Since(start)
+
+	s.mu.Lock()
+	job.status.Running = false
+	job.status.Runs++
+	job.status.LastRun = start
+	job.status.LastError = ""
+	if err != nil {
+		job.status.Failures++
+		job.status.LastError = err.Error()
+	}
+	s.mu.Unlock()
+
+	metrics.SchedulerJobRuns.Add(job.Name, 1)
+	if err != nil {
+		metrics.SchedulerJobFailures.Add(job.Name, 1)
+		s.logger.Error("scheduled job failed", "job", job.Name, "duration", elapsed, "error", err)
+		return
+	}
+	s.logger.Debug("scheduled job finished", "job", job.Name, "duration", elapsed)
+}
+
+// safeRun превращает панику задачи в ошибку
+func safeRun(ctx context.Context, run func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return run(ctx)
+}
+
+func nextDelay(interval, jitter time.Duration) time.Duration {
+	if jitter <= 0 {
+		return interval
+	}
+	return interval + rand.N(jitter)
+}
@@ -0,0 +1,116 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestScheduler() *Scheduler {
+	return New(slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Condition not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSchedulerIsolatesPanics(t *testing.T) {
+	s := newTestScheduler()
+
+	var panics, runs atomic.Int32
+	s.Register(Job{Name: "panicking", Interval: 5 * time.Millisecond, Run: func(ctx context.Context) error {
+		panics.Add(1)
+		panic("boom")
+	}})
+	s.Register(Job{Name: "healthy", Interval: 5 * time.Millisecond, Run: func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	}})
+
+	s.Start()
+	waitFor(t, func() bool { return panics.Load() >= 2 && runs.Load() >= 2 })
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatalf("Expected clean stop, got: %v", err)
+	}
+
+	for _, status := range s.Jobs() {
+		switch status.Name {
+		case "panicking":
+			if status.Failures != status.Runs || status.LastError == "" {
+				t.Errorf("Expected every run to fail with an error, got: %+v", status)
+			}
+		case "healthy":
+			if status.Failures != 0 || status.Runs < 2 {
+				t.Errorf("Expected successful runs, got: %+v", status)
+			}
+		}
+	}
+}
+
+func TestSchedulerStopCancelsRunningJob(t *testing.T) {
+	s := newTestScheduler()
+
+	started := make(chan struct{})
+	var cancelled atomic.Bool
+	s.Register(Job{Name: "long", Interval: time.Millisecond, Run: func(ctx context.Context) error {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-ctx.Done()
+		cancelled.Store(true)
+		return ctx.Err()
+	}})
+
+	s.Start()
+	<-started
+	if !s.Running() {
+		t.Error("Expected scheduler to be running")
+	}
+
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatalf("Expected clean stop, got: %v", err)
+	}
+	if !cancelled.Load() {
+		t.Error("Expected running job context to be cancelled")
+	}
+	if s.Running() {
+		t.Error("Expected scheduler to be stopped")
+	}
+}
+
+func TestSchedulerStopTimeout(t *testing.T) {
+	s := newTestScheduler()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	s.Register(Job{Name: "stuck", Interval: time.Millisecond, Run: func(ctx context.Context) error {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release // задача игнорирует отмену
+		return nil
+	}})
+
+	s.Start()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := s.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got: %v", err)
+	}
+}
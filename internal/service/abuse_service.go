@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/pkg/apperror"
+)
+
+var (
+	ErrInvalidAbuseStatus  = apperror.BadRequest("invalid_abuse_status", "status must be one of: open, dismissed, confirmed")
+	ErrInvalidReview       = apperror.BadRequest("invalid_review_status", "status must be dismissed or confirmed")
+	ErrAbuseFlagReviewed   = apperror.Conflict("abuse_flag_reviewed", "abuse flag is already reviewed")
+	ErrInvalidBookingLimit = apperror.BadRequest("invalid_booking_limit", "booking_limit must not be negative")
+
+	// errAlreadyFlagged откатывает ужесточение лимита, если открытая отметка уже есть
+	errAlreadyFlagged = errors.New("user is already flagged")
+)
+
+// longBookingGap - перерыв между длинными бронированиями, при котором они ещё считаются идущими подряд
+const longBookingGap = 24 * time.Hour
+
+// AbuseThresholds configures the booking abuse detector; a zero threshold disables its rule
+type AbuseThresholds struct {
+	Window               time.Duration // Окно: отмены считаются назад от текущего момента, длинные бронирования - вперёд
+	MaxCancellations     int           // Отмен за окно, после которых пользователь отмечается
+	LongBooking          time.Duration // Бронирование не короче считается длинным (на весь день)
+	MaxLongBookingStreak int           // Длинных бронирований подряд, после которых пользователь отмечается
+	AutoBookingLimit     int           // Лимит активных бронирований отмеченного пользователя (0 - только очередь проверки)
+}
+
+// AbuseService detects booking hoarding and keeps the admin review queue
+// Повторные неявки не проверяются: бронирования не хранят отметок о присутствии
+type AbuseService struct {
+	txManager  TxRunner
+	abuseRepo  AbuseStore
+	userRepo   UserStore
+	thresholds AbuseThresholds
+	logger     *slog.Logger
+}
+
+// NewAbuseService creates a new abuse detection service
+func NewAbuseService(txManager TxRunner, abuseRepo AbuseStore, userRepo UserStore, thresholds AbuseThresholds, logger *slog.Logger) *AbuseService {
+	return &AbuseService{
+		txManager:  txManager,
+		abuseRepo:  abuseRepo,
+		userRepo:   userRepo,
+		thresholds: thresholds,
+		logger:     logger,
+	}
+}
+
+// Detect runs the rules and flags users; returns the flags created by this run
+// Пользователь с открытой отметкой по той же причине повторно не отмечается
+func (s *AbuseService) Detect(ctx context.Context) ([]models.AbuseFlag, error) {
+	now := time.Now()
+	var flags []models.AbuseFlag
+
+	if s.thresholds.MaxCancellations > 0 {
+		counts, err := s.abuseRepo.CountCancellationsSince(ctx, now.Add(-s.thresholds.Window), s.thresholds.MaxCancellations)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range counts {
+			flag, err := s.flag(ctx, c.UserID, models.AbuseReasonCancellations, c.Count)
+			if err != nil {
+				return flags, err
+			}
+			if flag != nil {
+				flags = append(flags, *flag)
+			}
+		}
+	}
+
+	if s.thresholds.MaxLongBookingStreak > 0 {
+		spans, err := s.abuseRepo.GetActiveBookingSpans(ctx, now, now.Add(s.thresholds.Window))
+		if err != nil {
+			return nil, err
+		}
+		for userID, streak := range longBookingStreaks(spans, s.thresholds.LongBooking) {
+			if streak < s.thresholds.MaxLongBookingStreak {
+				continue
+			}
+			flag, err := s.flag(ctx, userID, models.AbuseReasonLongBookings, streak)
+			if err != nil {
+				return flags, err
+			}
+			if flag != nil {
+				flags = append(flags, *flag)
+			}
+		}
+	}
+
+	return flags, nil
+}
+
+// RunScheduled runs the detector, used by the background job
+func (s *AbuseService) RunScheduled(ctx context.Context) error {
+	_, err := s.Detect(ctx)
+	return err
+}
+
+// flag ставит пользователя в очередь проверки и при необходимости снижает его лимит бронирований
+// Возвращает nil, если открытая отметка по этой причине уже есть
+func (s *AbuseService) flag(ctx context.Context, userID uint, reason models.AbuseReason, count int) (*models.AbuseFlag, error) {
+	flag := &models.AbuseFlag{
+		UserID: userID,
+		Reason: reason,
+		Count:  count,
+		Status: models.AbuseFlagOpen,
+	}
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if limit := s.thresholds.AutoBookingLimit; limit > 0 {
+			tightened, err := s.userRepo.TightenBookingLimit(ctx, userID, limit)
+			if err != nil {
+				return err
+			}
+			if tightened {
+				flag.BookingLimit = &limit
+			}
+		}
+
+		created, err := s.abuseRepo.CreateFlag(ctx, flag)
+		if err != nil {
+			return err
+		}
+		if !created {
+			return errAlreadyFlagged
+		}
+		return nil
+	})
+	if errors.Is(err, errAlreadyFlagged) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Warn("user flagged for booking abuse",
+		"user_id", userID,
+		"reason", reason,
+		"count", count,
+		"booking_limit", flag.BookingLimit,
+	)
+	return flag, nil
+}
+
+// longBookingStreaks находит у каждого пользователя самую длинную серию длинных бронирований подряд
+// spans упорядочены по создателю и началу; серия прерывается коротким бронированием или перерывом от суток
+func longBookingStreaks(spans []models.Booking, minDuration time.Duration) map[uint]int {
+	longest := make(map[uint]int)
+	var (
+		userID  uint
+		streak  int
+		lastEnd time.Time
+	)
+	for _, b := range spans {
+		if b.CreatorID != userID {
+			userID, streak = b.CreatorID, 0
+		}
+		if b.EndTime.Sub(b.StartTime) < minDuration {
+			streak = 0
+			continue
+		}
+		if streak > 0 && b.StartTime.Sub(lastEnd) >= longBookingGap {
+			streak = 0
+		}
+		streak++
+		lastEnd = b.EndTime
+		if streak > longest[userID] {
+			longest[userID] = streak
+		}
+	}
+	return longest
+}
+
+// ListFlags returns a page of the review queue, newest first; empty status - all flags
+func (s *AbuseService) ListFlags(ctx context.Context, status models.AbuseFlagStatus, limit, offset int) ([]models.AbuseFlag, int64, error) {
+	switch status {
+	case "", models.AbuseFlagOpen, models.AbuseFlagDismissed, models.AbuseFlagConfirmed:
+	default:
+		return nil, 0, ErrInvalidAbuseStatus
+	}
+	limit, offset = pageBounds(limit, offset, DefaultListPageSize, MaxListPageSize)
+	return s.abuseRepo.ListFlags(ctx, status, limit, offset)
+}
+
+// ReviewFlag closes an open flag as dismissed or confirmed
+// Лимит бронирований при этом не меняется - его снимают или задают через SetBookingLimit
+func (s *AbuseService) ReviewFlag(ctx context.Context, id, reviewerID uint, status models.AbuseFlagStatus) (*models.AbuseFlag, error) {
+	if status != models.AbuseFlagDismissed && status != models.AbuseFlagConfirmed {
+		return nil, ErrInvalidReview
+	}
+
+	reviewed, err := s.abuseRepo.ReviewFlag(ctx, id, status, reviewerID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	flag, err := s.abuseRepo.GetFlag(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !reviewed {
+		return nil, ErrAbuseFlagReviewed
+	}
+	return flag, nil
+}
+
+// SetBookingLimit sets the maximum number of active upcoming bookings of a user; nil removes the limit
+func (s *AbuseService) SetBookingLimit(ctx context.Context, userID uint, limit *int) (*models.User, error) {
+	if limit != nil && *limit < 0 {
+		return nil, ErrInvalidBookingLimit
+	}
+	if err := s.userRepo.SetBookingLimit(ctx, userID, limit); err != nil {
+		return nil, err
+	}
+	return s.userRepo.GetByID(ctx, userID)
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/internal/repository"
+)
+
+// fakeAbuseStore возвращает заданные отмены и бронирования и хранит отметки в памяти
+type fakeAbuseStore struct {
+	AbuseStore
+	cancellations []repository.UserCount
+	spans         []models.Booking
+	flags         []models.AbuseFlag
+}
+
+func (f *fakeAbuseStore) CountCancellationsSince(ctx context.Context, since time.Time, min int) ([]repository.UserCount, error) {
+	var counts []repository.UserCount
+	for _, c := range f.cancellations {
+		if c.Count >= min {
+			counts = append(counts, c)
+		}
+	}
+	return counts, nil
+}
+
+func (f *fakeAbuseStore) GetActiveBookingSpans(ctx context.Context, start, end time.Time) ([]models.Booking, error) {
+	return f.spans, nil
+}
+
+func (f *fakeAbuseStore) CreateFlag(ctx context.Context, flag *models.AbuseFlag) (bool, error) {
+	for _, existing := range f.flags {
+		if existing.UserID == flag.UserID && existing.Reason == flag.Reason && existing.Status == models.AbuseFlagOpen {
+			return false, nil
+		}
+	}
+	flag.ID = uint(len(f.flags) + 1)
+	f.flags = append(f.flags, *flag)
+	return true, nil
+}
+
+// fakeLimitUserStore снижает лимиты бронирований, как UserRepository.TightenBookingLimit
+type fakeLimitUserStore struct {
+	*fakeUserStore
+}
+
+func (f *fakeLimitUserStore) TightenBookingLimit(ctx context.Context, userID uint, limit int) (bool, error) {
+	user := f.users[userID]
+	if user.BookingLimit != nil && *user.BookingLimit <= limit {
+		return false, nil
+	}
+	user.BookingLimit = &limit
+	return true, nil
+}
+
+func TestAbuseService_Detect(t *testing.T) {
+	day := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	allDay := func(userID uint, offsetDays int) models.Booking {
+		start := day.AddDate(0, 0, offsetDays)
+		return models.Booking{CreatorID: userID, StartTime: start, EndTime: start.Add(9 * time.Hour)}
+	}
+
+	lowLimit := 1
+	users := &fakeLimitUserStore{&fakeUserStore{users: map[uint]*models.User{
+		10: {ID: 10},
+		11: {ID: 11},
+		12: {ID: 12, BookingLimit: &lowLimit},
+	}}}
+	store := &fakeAbuseStore{
+		cancellations: []repository.UserCount{{UserID: 10, Count: 12}, {UserID: 11, Count: 3}},
+		spans: []models.Booking{
+			// Три дня подряд на весь день - серия
+			allDay(12, 0), allDay(12, 1), allDay(12, 2),
+			// Перерыв в два дня прерывает серию
+			allDay(11, 0), allDay(11, 1), allDay(11, 4),
+		},
+	}
+	svc := NewAbuseService(fakeTx{}, store, users, AbuseThresholds{
+		Window:               7 * 24 * time.Hour,
+		MaxCancellations:     10,
+		LongBooking:          8 * time.Hour,
+		MaxLongBookingStreak: 3,
+		AutoBookingLimit:     2,
+	}, slog.Default())
+	ctx := context.Background()
+
+	flags, err := svc.Detect(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(flags) != 2 {
+		t.Fatalf("Expected users 10 and 12 to be flagged, got: %+v", flags)
+	}
+	if flags[0].UserID != 10 || flags[0].Reason != models.AbuseReasonCancellations || flags[0].Count != 12 {
+		t.Errorf("Expected user 10 flagged for 12 cancellations, got: %+v", flags[0])
+	}
+	if flags[1].UserID != 12 || flags[1].Reason != models.AbuseReasonLongBookings || flags[1].Count != 3 {
+		t.Errorf("Expected user 12 flagged for 3 long bookings in a row, got: %+v", flags[1])
+	}
+
+	// Лимит только снижается: у пользователя 12 он уже ниже автоматического
+	if limit := users.users[10].BookingLimit; limit == nil || *limit != 2 || flags[0].BookingLimit == nil {
+		t.Errorf("Expected user 10 limit to be tightened to 2, got: %v", limit)
+	}
+	if limit := users.users[12].BookingLimit; *limit != 1 || flags[1].BookingLimit != nil {
+		t.Errorf("Expected user 12 limit to stay 1, got: %v", *limit)
+	}
+
+	// Открытые отметки не дублируются
+	if flags, err := svc.Detect(ctx); err != nil || len(flags) != 0 {
+		t.Errorf("Expected no new flags on the second run, got: %+v (%v)", flags, err)
+	}
+}
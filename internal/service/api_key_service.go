@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/pkg/apperror"
+	"gorm.io/gorm"
+)
+
+// apiKeyPrefix - префикс всех API ключей, упрощает поиск утечек в логах и репозиториях
+const apiKeyPrefix = "spk_"
+
+var (
+	ErrInvalidAPIKey = apperror.New(http.StatusUnauthorized, "invalid_api_key", "invalid API key")
+	ErrAPIKeyExpired = apperror.New(http.StatusUnauthorized, "api_key_expired", "API key expired")
+	ErrInvalidScope  = apperror.BadRequest("invalid_scope", "invalid API key scope")
+	ErrInvalidExpiry = apperror.BadRequest("invalid_expiry", "expires_at must be in the future")
+)
+
+// APIKeyService handles third-party API keys
+type APIKeyService struct {
+	apiKeyRepo APIKeyStore
+	logger     *slog.Logger
+}
+
+// NewAPIKeyService creates a new API key service
+func NewAPIKeyService(apiKeyRepo APIKeyStore, logger *slog.Logger) *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepo: apiKeyRepo,
+		logger:     logger,
+	}
+}
+
+// CreateAPIKeyRequest represents a request to create an API key
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required"`
+	Scopes    []string   `json:"scopes" binding:"required"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// CreatedAPIKey contains a newly created key with its plaintext secret
+// Секрет возвращается только один раз при создании
+type CreatedAPIKey struct {
+	APIKey *models.APIKey `json:"api_key"`
+	Key    string         `json:"key"`
+}
+
+// CreateKey creates a new API key (admin only)
+func (s *APIKeyService) CreateKey(ctx context.Context, createdByID uint, req CreateAPIKeyRequest) (*CreatedAPIKey, error) {
+	scopes, err := normalizeScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidExpiry
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+	plaintext := apiKeyPrefix + hex.EncodeToString(secret)
+
+	key := &models.APIKey{
+		Name:        strings.TrimSpace(req.Name),
+		Prefix:      plaintext[:len(apiKeyPrefix)+8],
+		SecretHash:  hashAPIKey(plaintext),
+		Scopes:      strings.Join(scopes, ","),
+		CreatedByID: createdByID,
+		ExpiresAt:   req.ExpiresAt,
+	}
+
+	if err := s.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("API key created", "api_key_id", key.ID, "name", key.Name, "scopes", key.Scopes, "created_by", createdByID)
+	return &CreatedAPIKey{APIKey: key, Key: plaintext}, nil
+}
+
+// Authenticate validates a plaintext API key and returns it
+func (s *APIKeyService) Authenticate(ctx context.Context, plaintext string) (*models.APIKey, error) {
+	if !strings.HasPrefix(plaintext, apiKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key, err := s.apiKeyRepo.GetBySecretHash(ctx, hashAPIKey(plaintext))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if key.IsExpired(now) {
+		return nil, ErrAPIKeyExpired
+	}
+
+	// Время последнего использования не критично - не блокируем запрос при ошибке
+	if err := s.apiKeyRepo.TouchLastUsed(ctx, key.ID, now); err != nil {
+		s.logger.Warn("failed to update API key last usage", "api_key_id", key.ID, "error", err)
+	}
+
+	return key, nil
+}
+
+// ListKeys returns all API keys (admin only)
+func (s *APIKeyService) ListKeys(ctx context.Context, order string) ([]models.APIKey, error) {
+	return s.apiKeyRepo.GetAll(ctx, order)
+}
+
+// RevokeKey revokes an API key (admin only)
+func (s *APIKeyService) RevokeKey(ctx context.Context, id uint) error {
+	if err := s.apiKeyRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.logger.Info("API key revoked", "api_key_id", id)
+	return nil
+}
+
+// hashAPIKey returns hex-encoded SHA-256 of the key
+// Ключи высокоэнтропийные, поэтому медленный KDF (bcrypt) не нужен
+func hashAPIKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeScopes validates and deduplicates scopes
+func normalizeScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, ErrInvalidScope
+	}
+
+	seen := make(map[string]bool, len(scopes))
+	result := make([]string, 0, len(scopes))
+	for _, raw := range scopes {
+		scope := strings.TrimSpace(raw)
+		valid := false
+		for _, allowed := range models.ValidAPIKeyScopes {
+			if scope == string(allowed) {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidScope, raw)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/internal/repository"
+)
+
+// AuditService records and queries the audit log of state-changing requests
+type AuditService struct {
+	auditRepo AuditStore
+	retention time.Duration
+	logger    *slog.Logger
+}
+
+// NewAuditService creates a new audit service
+// retention <= 0 отключает удаление старых записей
+func NewAuditService(auditRepo AuditStore, retention time.Duration, logger *slog.Logger) *AuditService {
+	return &AuditService{
+		auditRepo: auditRepo,
+		retention: retention,
+		logger:    logger,
+	}
+}
+
+// AuditListResponse represents a page of audit log entries
+type AuditListResponse struct {
+	Items  []models.AuditLog `json:"items"`
+	Total  int64             `json:"total"`
+	Limit  int               `json:"limit"`
+	Offset int               `json:"offset"`
+}
+
+// Record saves an audit log entry
+// Ошибка записи не должна ломать сам запрос, поэтому она только логируется
+func (s *AuditService) Record(ctx context.Context, entry *models.AuditLog) {
+	if err := s.auditRepo.Create(ctx, entry); err != nil {
+		s.logger.Error("failed to write audit log", "method", entry.Method, "route", entry.Route, "error", err)
+	}
+}
+
+// List returns audit log entries matching the filter
+func (s *AuditService) List(ctx context.Context, filter repository.AuditFilter) (*AuditListResponse, error) {
+	filter.Limit, filter.Offset = pageBounds(filter.Limit, filter.Offset, DefaultAuditPageSize, MaxAuditPageSize)
+
+	entries, total, err := s.auditRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuditListResponse{
+		Items:  entries,
+		Total:  total,
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}, nil
+}
+
+// PurgeExpired deletes entries older than the retention period
+func (s *AuditService) PurgeExpired(ctx context.Context) (int64, error) {
+	if s.retention <= 0 {
+		return 0, nil
+	}
+
+	deleted, err := s.auditRepo.DeleteOlderThan(ctx, time.Now().Add(-s.retention))
+	if err != nil {
+		return 0, err
+	}
+	if deleted > 0 {
+		s.logger.Info("purged expired audit log entries", "count", deleted, "retention", s.retention.String())
+	}
+	return deleted, nil
+}
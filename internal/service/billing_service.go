@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/pkg/apperror"
+)
+
+// BillingMonthLayout - формат месяца в отчётах об использовании: 2025-03
+const BillingMonthLayout = "2006-01"
+
+var (
+	ErrInvalidBillingMonth  = apperror.BadRequest("invalid_billing_month", "month must be in YYYY-MM format")
+	ErrInvalidIncludedHours = apperror.BadRequest("invalid_included_hours", "included_hours must not be negative")
+	ErrInvalidCostGrouping  = apperror.BadRequest("invalid_cost_grouping", "group must be user or team")
+)
+
+// Группировка сводки стоимости бронирований
+const (
+	CostByUser = "user"
+	CostByTeam = "team"
+)
+
+// TeamUsage is the booked time of a team in a month
+type TeamUsage struct {
+	TeamID        uint     `json:"team_id"`
+	TeamName      string   `json:"team_name"`
+	Month         string   `json:"month"`
+	Bookings      int      `json:"bookings"`
+	UsedHours     float64  `json:"used_hours"`
+	IncludedHours *int     `json:"included_hours,omitempty"`
+	OverageHours  *float64 `json:"overage_hours,omitempty"` // Только у тарифицируемых команд
+}
+
+// CostSummary is the estimated cost of paid bookings of a user or team in a month
+type CostSummary struct {
+	Month    string  `json:"month"`
+	UserID   uint    `json:"user_id,omitempty"` // При группировке по пользователям
+	TeamID   uint    `json:"team_id,omitempty"` // При группировке по командам
+	Name     string  `json:"name"`
+	Bookings int     `json:"bookings"`
+	Hours    float64 `json:"hours"`
+	Cost     int64   `json:"cost"` // В минимальных единицах валюты (копейках)
+	Currency string  `json:"currency"`
+}
+
+// BillingService reports team usage for invoicing members of paid coworking spaces
+// Бронирование относится к команде создателя; если он состоит в нескольких командах - к команде
+// с наименьшим ID. Бронирования пользователей вне команд в отчёт не попадают
+type BillingService struct {
+	billingRepo BillingStore
+	currency    string
+	logger      *slog.Logger
+}
+
+// NewBillingService creates a new billing service
+func NewBillingService(billingRepo BillingStore, logger *slog.Logger) *BillingService {
+	return &BillingService{
+		billingRepo: billingRepo,
+		currency:    "RUB",
+		logger:      logger,
+	}
+}
+
+// SetCurrency sets the ISO 4217 currency of room prices and booking costs
+func (s *BillingService) SetCurrency(currency string) {
+	s.currency = currency
+}
+
+// ParseBillingMonth parses YYYY-MM into the first moment of the month in UTC; empty - current month
+func ParseBillingMonth(value string) (time.Time, error) {
+	if value == "" {
+		now := time.Now().UTC()
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC), nil
+	}
+	month, err := time.Parse(BillingMonthLayout, value)
+	if err != nil {
+		return time.Time{}, ErrInvalidBillingMonth
+	}
+	return month, nil
+}
+
+// Usage returns booked hours of every team (or only teamID if not 0) in the month starting at month
+// Учитываются бронирования, закончившиеся к моменту запроса; часть, выходящая за границы месяца, отбрасывается
+func (s *BillingService) Usage(ctx context.Context, month time.Time, teamID uint) ([]TeamUsage, error) {
+	start := month
+	end := month.AddDate(0, 1, 0)
+
+	teams, err := s.billingRepo.ListTeams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	memberships, err := s.billingRepo.GetMemberships(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bookings, err := s.billingRepo.GetEndedBookingSpans(ctx, start, end, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// Членства упорядочены по пользователю и команде: первая запись - команда с наименьшим ID
+	teamOf := make(map[uint]uint, len(memberships))
+	for _, m := range memberships {
+		if _, ok := teamOf[m.UserID]; !ok {
+			teamOf[m.UserID] = m.TeamID
+		}
+	}
+
+	booked := make(map[uint]time.Duration)
+	counts := make(map[uint]int)
+	for _, b := range bookings {
+		team, ok := teamOf[b.CreatorID]
+		if !ok {
+			continue
+		}
+		from, to := b.StartTime, b.EndTime
+		if from.Before(start) {
+			from = start
+		}
+		if to.After(end) {
+			to = end
+		}
+		booked[team] += to.Sub(from)
+		counts[team]++
+	}
+
+	usage := []TeamUsage{}
+	for _, team := range teams {
+		if teamID != 0 && team.ID != teamID {
+			continue
+		}
+		used := roundHours(booked[team.ID])
+		row := TeamUsage{
+			TeamID:        team.ID,
+			TeamName:      team.Name,
+			Month:         month.Format(BillingMonthLayout),
+			Bookings:      counts[team.ID],
+			UsedHours:     used,
+			IncludedHours: team.IncludedHours,
+		}
+		if team.IncludedHours != nil {
+			overage := math.Max(0, used-float64(*team.IncludedHours))
+			row.OverageHours = &overage
+		}
+		usage = append(usage, row)
+	}
+	return usage, nil
+}
+
+// Costs sums estimated costs of paid bookings in the month starting at month, by creator or by creator's team
+// Бронирование относится к месяцу своего начала и учитывается целиком, после окончания; стоимость - оценка,
+// зафиксированная при бронировании. Бесплатные бронирования и, при группировке по командам,
+// бронирования пользователей вне команд не учитываются. Сначала самые дорогие
+func (s *BillingService) Costs(ctx context.Context, month time.Time, group string) ([]CostSummary, error) {
+	if group != CostByUser && group != CostByTeam {
+		return nil, ErrInvalidCostGrouping
+	}
+	start := month
+	end := month.AddDate(0, 1, 0)
+
+	bookings, err := s.billingRepo.GetEndedBookingSpans(ctx, start, end, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var keyOf func(creatorID uint) (uint, bool)
+	names := make(map[uint]string)
+	if group == CostByTeam {
+		memberships, err := s.billingRepo.GetMemberships(ctx)
+		if err != nil {
+			return nil, err
+		}
+		teamOf := make(map[uint]uint, len(memberships))
+		for _, m := range memberships {
+			if _, ok := teamOf[m.UserID]; !ok {
+				teamOf[m.UserID] = m.TeamID
+			}
+		}
+		keyOf = func(creatorID uint) (uint, bool) {
+			team, ok := teamOf[creatorID]
+			return team, ok
+		}
+		teams, err := s.billingRepo.ListTeams(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, team := range teams {
+			names[team.ID] = team.Name
+		}
+	} else {
+		keyOf = func(creatorID uint) (uint, bool) { return creatorID, true }
+	}
+
+	summaries := make(map[uint]*CostSummary)
+	booked := make(map[uint]time.Duration)
+	var order []uint
+	for _, b := range bookings {
+		if b.EstimatedCost == 0 || b.StartTime.Before(start) {
+			continue
+		}
+		key, ok := keyOf(b.CreatorID)
+		if !ok {
+			continue
+		}
+		summary := summaries[key]
+		if summary == nil {
+			summary = &CostSummary{Month: month.Format(BillingMonthLayout), Currency: s.currency}
+			if group == CostByTeam {
+				summary.TeamID = key
+			} else {
+				summary.UserID = key
+			}
+			summaries[key] = summary
+			order = append(order, key)
+		}
+		summary.Bookings++
+		summary.Cost += b.EstimatedCost
+		booked[key] += b.EndTime.Sub(b.StartTime)
+	}
+
+	if group == CostByUser {
+		users, err := s.billingRepo.GetUsers(ctx, order)
+		if err != nil {
+			return nil, err
+		}
+		for i := range users {
+			names[users[i].ID] = userDisplayName(&users[i])
+		}
+	}
+
+	result := make([]CostSummary, 0, len(order))
+	for _, key := range order {
+		summary := summaries[key]
+		summary.Name = names[key]
+		summary.Hours = roundHours(booked[key])
+		result = append(result, *summary)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Cost > result[j].Cost })
+	return result, nil
+}
+
+// SetIncludedHours sets the monthly included hours of a team; nil stops billing the team
+func (s *BillingService) SetIncludedHours(ctx context.Context, teamID uint, hours *int) (*models.Team, error) {
+	if hours != nil && *hours < 0 {
+		return nil, ErrInvalidIncludedHours
+	}
+	if err := s.billingRepo.SetIncludedHours(ctx, teamID, hours); err != nil {
+		return nil, err
+	}
+	s.logger.Info("team included hours changed", "team_id", teamID, "included_hours", hours)
+	return s.billingRepo.GetTeam(ctx, teamID)
+}
+
+// userDisplayName возвращает имя и фамилию пользователя, а без них - username
+func userDisplayName(u *models.User) string {
+	if name := strings.TrimSpace(u.FirstName + " " + u.LastName); name != "" {
+		return name
+	}
+	return u.Username
+}
+
+// roundHours переводит длительность в часы с точностью до сотых
+func roundHours(d time.Duration) float64 {
+	return math.Round(d.Hours()*100) / 100
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/internal/repository"
+)
+
+// fakeBillingStore хранит команды, членства и бронирования в памяти
+type fakeBillingStore struct {
+	BillingStore
+	teams       []models.Team
+	memberships []repository.TeamMembership
+	bookings    []models.Booking
+	users       []models.User
+}
+
+func (f *fakeBillingStore) ListTeams(ctx context.Context) ([]models.Team, error) {
+	return f.teams, nil
+}
+
+func (f *fakeBillingStore) GetMemberships(ctx context.Context) ([]repository.TeamMembership, error) {
+	return f.memberships, nil
+}
+
+func (f *fakeBillingStore) GetEndedBookingSpans(ctx context.Context, start, end, now time.Time) ([]models.Booking, error) {
+	var bookings []models.Booking
+	for _, b := range f.bookings {
+		if b.StartTime.Before(end) && b.EndTime.After(start) && !b.EndTime.After(now) {
+			bookings = append(bookings, b)
+		}
+	}
+	return bookings, nil
+}
+
+func (f *fakeBillingStore) GetUsers(ctx context.Context, ids []uint) ([]models.User, error) {
+	var users []models.User
+	for _, u := range f.users {
+		for _, id := range ids {
+			if u.ID == id {
+				users = append(users, u)
+			}
+		}
+	}
+	return users, nil
+}
+
+func TestBillingService_Usage(t *testing.T) {
+	month := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	included := 2
+	store := &fakeBillingStore{
+		teams: []models.Team{
+			{ID: 1, Name: "Acme", IncludedHours: &included},
+			{ID: 2, Name: "Globex"},
+			{ID: 3, Name: "Idle"},
+		},
+		// Пользователь 1 в двух командах - учитывается в команде 1
+		memberships: []repository.TeamMembership{{TeamID: 1, UserID: 1}, {TeamID: 2, UserID: 1}, {TeamID: 2, UserID: 2}},
+		bookings: []models.Booking{
+			{ID: 1, CreatorID: 1, StartTime: month.Add(9 * time.Hour), EndTime: month.Add(11 * time.Hour)},
+			// Начался в феврале - учитывается только мартовская часть
+			{ID: 2, CreatorID: 1, StartTime: month.Add(-time.Hour), EndTime: month.Add(90 * time.Minute)},
+			{ID: 3, CreatorID: 2, StartTime: month.Add(10 * time.Hour), EndTime: month.Add(10*time.Hour + 20*time.Minute)},
+			// Пользователь вне команд не тарифицируется
+			{ID: 4, CreatorID: 3, StartTime: month.Add(9 * time.Hour), EndTime: month.Add(12 * time.Hour)},
+		},
+	}
+	svc := NewBillingService(store, slog.Default())
+
+	usage, err := svc.Usage(context.Background(), month, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(usage) != 3 {
+		t.Fatalf("Expected all 3 teams, got: %+v", usage)
+	}
+	if u := usage[0]; u.Month != "2025-03" || u.Bookings != 2 || u.UsedHours != 3.5 || u.OverageHours == nil || *u.OverageHours != 1.5 {
+		t.Errorf("Expected 3.5h used and 1.5h overage for Acme, got: %+v", u)
+	}
+	if u := usage[1]; u.Bookings != 1 || u.UsedHours != 0.33 || u.OverageHours != nil {
+		t.Errorf("Expected 0.33h without overage for Globex, got: %+v", u)
+	}
+	if u := usage[2]; u.Bookings != 0 || u.UsedHours != 0 {
+		t.Errorf("Expected no usage for Idle, got: %+v", u)
+	}
+
+	usage, _ = svc.Usage(context.Background(), month, 2)
+	if len(usage) != 1 || usage[0].TeamID != 2 {
+		t.Errorf("Expected only team 2, got: %+v", usage)
+	}
+
+	negative := -1
+	if _, err := svc.SetIncludedHours(context.Background(), 1, &negative); !errors.Is(err, ErrInvalidIncludedHours) {
+		t.Errorf("Expected ErrInvalidIncludedHours, got: %v", err)
+	}
+}
+
+func TestParseBillingMonth(t *testing.T) {
+	month, err := ParseBillingMonth("2025-03")
+	if err != nil || !month.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected March 2025, got: %v (%v)", month, err)
+	}
+	if _, err := ParseBillingMonth("03.2025"); !errors.Is(err, ErrInvalidBillingMonth) {
+		t.Errorf("Expected ErrInvalidBillingMonth, got: %v", err)
+	}
+	if month, _ := ParseBillingMonth(""); month.Day() != 1 || month.Month() != time.Now().UTC().Month() {
+		t.Errorf("Expected the current month by default, got: %v", month)
+	}
+}
+
+func TestBillingService_Costs(t *testing.T) {
+	month := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	store := &fakeBillingStore{
+		teams:       []models.Team{{ID: 1, Name: "Acme"}},
+		memberships: []repository.TeamMembership{{TeamID: 1, UserID: 1}, {TeamID: 1, UserID: 2}},
+		users:       []models.User{{ID: 1, FirstName: "Ivan"}, {ID: 2, Username: "petr"}, {ID: 3, FirstName: "Anna"}},
+		bookings: []models.Booking{
+			{ID: 1, CreatorID: 1, StartTime: month.Add(9 * time.Hour), EndTime: month.Add(11 * time.Hour), EstimatedCost: 100000},
+			{ID: 2, CreatorID: 2, StartTime: month.Add(9 * time.Hour), EndTime: month.Add(10 * time.Hour), EstimatedCost: 50000},
+			{ID: 3, CreatorID: 3, StartTime: month.Add(9 * time.Hour), EndTime: month.Add(12 * time.Hour), EstimatedCost: 200000},
+			// Бесплатная комната и бронирование, начавшееся в феврале, не учитываются
+			{ID: 4, CreatorID: 1, StartTime: month.Add(12 * time.Hour), EndTime: month.Add(13 * time.Hour)},
+			{ID: 5, CreatorID: 1, StartTime: month.Add(-time.Hour), EndTime: month.Add(time.Hour), EstimatedCost: 70000},
+		},
+	}
+	svc := NewBillingService(store, slog.Default())
+	ctx := context.Background()
+
+	costs, err := svc.Costs(ctx, month, CostByUser)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(costs) != 3 {
+		t.Fatalf("Expected 3 users, got: %+v", costs)
+	}
+	if c := costs[0]; c.UserID != 3 || c.Name != "Anna" || c.Cost != 200000 || c.Hours != 3 || c.Currency != "RUB" {
+		t.Errorf("Expected Anna to be the most expensive, got: %+v", c)
+	}
+	if c := costs[1]; c.UserID != 1 || c.Bookings != 1 || c.Cost != 100000 {
+		t.Errorf("Expected only the paid March booking of Ivan, got: %+v", c)
+	}
+	if c := costs[2]; c.Name != "petr" {
+		t.Errorf("Expected username without a name, got: %+v", c)
+	}
+
+	// Пользователь вне команд не учитывается при группировке по командам
+	costs, _ = svc.Costs(ctx, month, CostByTeam)
+	if len(costs) != 1 || costs[0].TeamID != 1 || costs[0].Name != "Acme" || costs[0].Cost != 150000 || costs[0].Bookings != 2 {
+		t.Errorf("Expected Acme with 2 bookings, got: %+v", costs)
+	}
+
+	if _, err := svc.Costs(ctx, month, "room"); !errors.Is(err, ErrInvalidCostGrouping) {
+		t.Errorf("Expected ErrInvalidCostGrouping, got: %v", err)
+	}
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/pkg/apperror"
+	"gorm.io/gorm"
+)
+
+// BookingFeedSettleDelay - события моложе этой задержки не отдаются в ленту:
+// ID выделяются до коммита, и более раннее событие параллельной транзакции может появиться позже следующего
+const BookingFeedSettleDelay = 15 * time.Second
+
+// completeBatchSize - сколько закончившихся бронирований завершается за один проход
+const completeBatchSize = 500
+
+var ErrBrokenHistory = apperror.New(http.StatusInternalServerError, "broken_history", "booking history must start with a created event")
+
+// BookingState is a booking rebuilt by replaying its event stream
+type BookingState struct {
+	ID                    uint                 `json:"id"`
+	RoomID                uint                 `json:"room_id"`
+	CreatorID             uint                 `json:"creator_id"`
+	StartTime             time.Time            `json:"start_time"`
+	EndTime               time.Time            `json:"end_time"`
+	Title                 string               `json:"title"`
+	Description           string               `json:"description,omitempty"`
+	EstimatedParticipants int                  `json:"estimated_participants"`
+	IsJoinable            bool                 `json:"is_joinable"`
+	Status                models.BookingStatus `json:"status"`
+	ParticipantIDs        []uint               `json:"participant_ids"`
+	Version               uint                 `json:"version"` // ID последнего применённого события
+}
+
+// BookingHistory is the event stream of a booking and the state rebuilt from it
+type BookingHistory struct {
+	Events []models.BookingHistoryEvent `json:"events"`
+	State  *BookingState                `json:"state"`
+}
+
+// BookingEventFeed is a page of the booking event stream for integrations
+type BookingEventFeed struct {
+	Events []models.BookingHistoryEvent `json:"events"`
+	Next   uint                         `json:"next"` // Курсор следующего запроса (after); равен after, если новых событий нет
+}
+
+// BookingHistoryService reads the booking event stream: per-booking history and a feed for integrations
+type BookingHistoryService struct {
+	history BookingHistoryStore
+}
+
+// NewBookingHistoryService creates a new booking history service
+func NewBookingHistoryService(history BookingHistoryStore) *BookingHistoryService {
+	return &BookingHistoryService{history: history}
+}
+
+// History returns the events of a booking and its state rebuilt from them
+// Отменённые бронирования удалены мягко, но их история остаётся доступной
+func (s *BookingHistoryService) History(ctx context.Context, bookingID uint) (*BookingHistory, error) {
+	events, err := s.history.ListByBooking(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	state, err := ReplayBooking(events)
+	if err != nil {
+		return nil, err
+	}
+	return &BookingHistory{Events: events, State: state}, nil
+}
+
+// Feed returns up to limit events after the cursor, in stream order
+// Лента отдаёт только события старше BookingFeedSettleDelay и обрывается на первом более новом,
+// поэтому потребитель, продолжающий с курсора next, не пропускает события
+func (s *BookingHistoryService) Feed(ctx context.Context, after uint, limit int) (*BookingEventFeed, error) {
+	limit, _ = pageBounds(limit, 0, DefaultListPageSize, MaxListPageSize)
+	events, err := s.history.ListAfter(ctx, after, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	settled := time.Now().Add(-BookingFeedSettleDelay)
+	for i := range events {
+		if events[i].CreatedAt.After(settled) {
+			events = events[:i]
+			break
+		}
+	}
+
+	feed := &BookingEventFeed{Events: events, Next: after}
+	if len(events) > 0 {
+		feed.Next = events[len(events)-1].ID
+	}
+	return feed, nil
+}
+
+// ReplayBooking rebuilds a booking from its events in stream order
+func ReplayBooking(events []models.BookingHistoryEvent) (*BookingState, error) {
+	if len(events) == 0 || events[0].Type != models.BookingHistoryCreated {
+		return nil, ErrBrokenHistory
+	}
+
+	state := &BookingState{ID: events[0].BookingID, Status: models.BookingStatusConfirmed}
+	participants := make(map[uint]bool)
+	for _, event := range events {
+		var data models.BookingHistoryData
+		if len(event.Data) > 0 {
+			if err := json.Unmarshal(event.Data, &data); err != nil {
+				return nil, fmt.Errorf("booking event %d: %w", event.ID, err)
+			}
+		}
+
+		switch event.Type {
+		case models.BookingHistoryCreated, models.BookingHistoryUpdated:
+			applyBookingData(state, &data)
+			for _, id := range data.ParticipantIDs {
+				participants[id] = true
+			}
+		case models.BookingHistoryJoined:
+			if data.UserID != nil {
+				participants[*data.UserID] = true
+			}
+		case models.BookingHistoryLeft:
+			if data.UserID != nil {
+				delete(participants, *data.UserID)
+			}
+		case models.BookingHistoryCancelled:
+			state.Status = models.BookingStatusCancelled
+		case models.BookingHistoryCompleted:
+			state.Status = models.BookingStatusCompleted
+			if data.EndTime != nil {
+				state.EndTime = *data.EndTime
+			}
+		}
+		state.Version = event.ID
+	}
+
+	state.ParticipantIDs = make([]uint, 0, len(participants))
+	for id := range participants {
+		state.ParticipantIDs = append(state.ParticipantIDs, id)
+	}
+	sort.Slice(state.ParticipantIDs, func(i, j int) bool { return state.ParticipantIDs[i] < state.ParticipantIDs[j] })
+	return state, nil
+}
+
+// applyBookingData переносит заданные поля события в состояние бронирования
+func applyBookingData(state *BookingState, data *models.BookingHistoryData) {
+	if data.RoomID != nil {
+		state.RoomID = *data.RoomID
+	}
+	if data.CreatorID != nil {
+		state.CreatorID = *data.CreatorID
+	}
+	if data.StartTime != nil {
+		state.StartTime = *data.StartTime
+	}
+	if data.EndTime != nil {
+		state.EndTime = *data.EndTime
+	}
+	if data.Title != nil {
+		state.Title = *data.Title
+	}
+	if data.Description != nil {
+		state.Description = *data.Description
+	}
+	if data.EstimatedParticipants != nil {
+		state.EstimatedParticipants = *data.EstimatedParticipants
+	}
+	if data.IsJoinable != nil {
+		state.IsJoinable = *data.IsJoinable
+	}
+}
+
+// recordHistory добавляет событие в поток бронирования; вызывается в транзакции изменения,
+// поэтому событие сохраняется тогда и только тогда, когда сохранено изменение. actorID 0 - система
+func recordHistory(ctx context.Context, history BookingHistoryStore, bookingID uint, eventType models.BookingHistoryEventType, actorID uint, data *models.BookingHistoryData) error {
+	if history == nil {
+		return nil
+	}
+	event := &models.BookingHistoryEvent{BookingID: bookingID, Type: eventType, CreatedAt: time.Now()}
+	if actorID != 0 {
+		event.ActorID = &actorID
+	}
+	if data != nil {
+		payload, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		event.Data = payload
+	}
+	return history.Append(ctx, event)
+}
+
+// createdHistoryData - полное состояние нового бронирования
+func createdHistoryData(booking *models.Booking) *models.BookingHistoryData {
+	data := updatedHistoryData(booking)
+	data.RoomID = &booking.RoomID
+	data.CreatorID = &booking.CreatorID
+	for _, participant := range booking.Participants {
+		data.ParticipantIDs = append(data.ParticipantIDs, participant.ID)
+	}
+	return data
+}
+
+// updatedHistoryData - редактируемые поля бронирования (см. BookingRepository.Update)
+func updatedHistoryData(booking *models.Booking) *models.BookingHistoryData {
+	return &models.BookingHistoryData{
+		StartTime:             &booking.StartTime,
+		EndTime:               &booking.EndTime,
+		Title:                 &booking.Title,
+		Description:           &booking.Description,
+		EstimatedParticipants: &booking.EstimatedParticipants,
+		IsJoinable:            &booking.IsJoinable,
+		MaxParticipants:       &booking.MaxParticipants,
+	}
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/models"
+)
+
+// fakeHistoryStore хранит поток событий бронирований в памяти
+type fakeHistoryStore struct {
+	events []models.BookingHistoryEvent
+}
+
+func (f *fakeHistoryStore) Append(ctx context.Context, event *models.BookingHistoryEvent) error {
+	event.ID = uint(len(f.events) + 1)
+	f.events = append(f.events, *event)
+	return nil
+}
+
+func (f *fakeHistoryStore) ListByBooking(ctx context.Context, bookingID uint) ([]models.BookingHistoryEvent, error) {
+	var events []models.BookingHistoryEvent
+	for _, event := range f.events {
+		if event.BookingID == bookingID {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (f *fakeHistoryStore) ListAfter(ctx context.Context, afterID uint, limit int) ([]models.BookingHistoryEvent, error) {
+	var events []models.BookingHistoryEvent
+	for _, event := range f.events {
+		if event.ID > afterID && len(events) < limit {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func historyEvent(t *testing.T, id uint, eventType models.BookingHistoryEventType, data *models.BookingHistoryData) models.BookingHistoryEvent {
+	t.Helper()
+	event := models.BookingHistoryEvent{ID: id, BookingID: 1, Type: eventType}
+	if data != nil {
+		payload, err := json.Marshal(data)
+		if err != nil {
+			t.Fatalf("Failed to encode event data: %v", err)
+		}
+		event.Data = payload
+	}
+	return event
+}
+
+func TestBookingService_RecordsHistory(t *testing.T) {
+	history := &fakeHistoryStore{}
+	rooms := &fakeRoomStore{rooms: map[uint]*models.Room{1: {ID: 1, Name: "Room 1", IsActive: true}}}
+	users := &fakeUserStore{users: map[uint]*models.User{
+		10: {ID: 10, Role: models.RoleUser},
+		11: {ID: 11, Role: models.RoleUser},
+		12: {ID: 12, Role: models.RoleAdmin},
+	}}
+	svc := NewBookingService(fakeTx{}, newFakeBookingStore(), rooms, users, nil, history, nil, slog.Default())
+	ctx := context.Background()
+
+	start := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	booking, err := svc.CreateBooking(ctx, 10, CreateBookingRequest{RoomID: 1, StartTime: start, EndTime: start.Add(time.Hour), Title: "Planning", ParticipantIDs: []uint{11}})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := svc.CancelBooking(ctx, booking.ID, 12); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	result, err := NewBookingHistoryService(history).History(ctx, booking.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(result.Events) != 2 || result.Events[1].ActorID == nil || *result.Events[1].ActorID != 12 {
+		t.Fatalf("Expected created and cancelled by the admin, got: %+v", result.Events)
+	}
+	state := result.State
+	if state.Status != models.BookingStatusCancelled || state.Title != "Planning" || !state.StartTime.Equal(start) ||
+		len(state.ParticipantIDs) != 1 || state.ParticipantIDs[0] != 11 || state.Version != 2 {
+		t.Errorf("Unexpected rebuilt state: %+v", state)
+	}
+}
+
+func TestReplayBooking(t *testing.T) {
+	start := time.Date(2025, 5, 12, 10, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	moved := start.Add(2 * time.Hour)
+	movedEnd := moved.Add(time.Hour)
+	releasedAt := moved.Add(30 * time.Minute)
+	room, creator, guest, other := uint(1), uint(10), uint(11), uint(12)
+	title, newTitle := "Sync", "Sync (moved)"
+
+	events := []models.BookingHistoryEvent{
+		historyEvent(t, 1, models.BookingHistoryCreated, &models.BookingHistoryData{RoomID: &room, CreatorID: &creator, StartTime: &start, EndTime: &end, Title: &title, ParticipantIDs: []uint{guest}}),
+		historyEvent(t, 2, models.BookingHistoryJoined, &models.BookingHistoryData{UserID: &other}),
+		historyEvent(t, 3, models.BookingHistoryLeft, &models.BookingHistoryData{UserID: &guest}),
+		historyEvent(t, 4, models.BookingHistoryUpdated, &models.BookingHistoryData{StartTime: &moved, EndTime: &movedEnd, Title: &newTitle}),
+		historyEvent(t, 5, models.BookingHistoryCompleted, &models.BookingHistoryData{EndTime: &releasedAt}),
+	}
+	state, err := ReplayBooking(events)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if state.RoomID != room || state.CreatorID != creator || state.Title != newTitle || !state.StartTime.Equal(moved) || !state.EndTime.Equal(releasedAt) {
+		t.Errorf("Unexpected rebuilt booking: %+v", state)
+	}
+	if state.Status != models.BookingStatusCompleted || len(state.ParticipantIDs) != 1 || state.ParticipantIDs[0] != other || state.Version != 5 {
+		t.Errorf("Unexpected status or participants: %+v", state)
+	}
+
+	if _, err := ReplayBooking(events[1:]); !errors.Is(err, ErrBrokenHistory) {
+		t.Errorf("Expected ErrBrokenHistory, got: %v", err)
+	}
+}
+
+func TestBookingHistoryService_FeedStopsAtUnsettledEvents(t *testing.T) {
+	now := time.Now()
+	history := &fakeHistoryStore{events: []models.BookingHistoryEvent{
+		{ID: 1, BookingID: 1, Type: models.BookingHistoryCreated, CreatedAt: now.Add(-time.Minute)},
+		{ID: 2, BookingID: 2, Type: models.BookingHistoryCreated, CreatedAt: now},
+		{ID: 3, BookingID: 1, Type: models.BookingHistoryCancelled, CreatedAt: now.Add(-time.Minute)},
+	}}
+	svc := NewBookingHistoryService(history)
+
+	feed, err := svc.Feed(context.Background(), 0, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	// Событие 3 старше задержки, но за ним не идут, пока не устоится событие 2
+	if len(feed.Events) != 1 || feed.Next != 1 {
+		t.Errorf("Expected the feed to stop before the unsettled event, got: %+v", feed)
+	}
+
+	empty, err := svc.Feed(context.Background(), 3, 10)
+	if err != nil || len(empty.Events) != 0 || empty.Next != 3 {
+		t.Errorf("Expected an empty page keeping the cursor, got: %+v (%v)", empty, err)
+	}
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/pkg/apperror"
+)
+
+var (
+	ErrMultiDayBooking       = apperror.BadRequest("multi_day_booking", "booking must not cross midnight or exceed the maximum duration")
+	ErrMultiDayRequiresAdmin = apperror.Forbidden("multi_day_requires_admin", "multi-day bookings require an administrator with allow_multi_day")
+)
+
+// MultiDayWarning - предупреждение в ответе на создание многодневного бронирования при политике allow
+const MultiDayWarning = "booking crosses midnight or exceeds the maximum duration"
+
+// MultiDayPolicy decides what happens to a booking that crosses midnight or exceeds the maximum duration
+type MultiDayPolicy string
+
+const (
+	MultiDayAllow  MultiDayPolicy = "allow"  // Бронирование создаётся, ответ содержит предупреждение
+	MultiDayReject MultiDayPolicy = "reject" // Бронирование отклоняется
+	MultiDayAdmin  MultiDayPolicy = "admin"  // Только администратор с флагом allow_multi_day запроса
+)
+
+// MultiDaySettings configures multi-day bookings
+type MultiDaySettings struct {
+	Policy      MultiDayPolicy // Пусто - allow
+	MaxDuration time.Duration  // Бронирование длиннее тоже многодневное; 0 - только пересечение полуночи
+	Location    *time.Location // Часовой пояс пространства для комнат без своего; nil - UTC
+}
+
+// SetMultiDaySettings sets the policy for bookings crossing midnight or exceeding the maximum duration
+func (s *BookingService) SetMultiDaySettings(settings MultiDaySettings) {
+	s.multiDay = settings
+}
+
+// CalendarLocation returns the timezone of the space: days of rooms without their own timezone and of the calendar
+func (s *BookingService) CalendarLocation() *time.Location {
+	if s.multiDay.Location == nil {
+		return time.UTC
+	}
+	return s.multiDay.Location
+}
+
+// IsMultiDay reports whether a booking of room over [start, end) crosses midnight in the room timezone
+// or exceeds the maximum duration
+func (s *BookingService) IsMultiDay(room *models.Room, start, end time.Time) bool {
+	if s.multiDay.MaxDuration > 0 && end.Sub(start) > s.multiDay.MaxDuration {
+		return true
+	}
+	loc := roomLocation(room)
+	if loc == nil {
+		loc = s.CalendarLocation()
+	}
+	return crossesMidnight(start, end, loc)
+}
+
+// checkMultiDay применяет политику многодневных бронирований; allowed - флаг allow_multi_day запроса
+func (s *BookingService) checkMultiDay(room *models.Room, actor *models.User, start, end time.Time, allowed bool) error {
+	if !s.IsMultiDay(room, start, end) {
+		return nil
+	}
+	switch s.multiDay.Policy {
+	case MultiDayReject:
+		return ErrMultiDayBooking
+	case MultiDayAdmin:
+		if !allowed || !actor.IsAdmin() {
+			return ErrMultiDayRequiresAdmin
+		}
+	}
+	return nil
+}
+
+// crossesMidnight - период [start, end) захватывает больше одного календарного дня в loc;
+// окончание ровно в полночь следующий день не захватывает
+func crossesMidnight(start, end time.Time, loc *time.Location) bool {
+	if !end.After(start) {
+		return false
+	}
+	sy, sm, sd := start.In(loc).Date()
+	ey, em, ed := end.Add(-time.Nanosecond).In(loc).Date()
+	return sy != ey || sm != em || sd != ed
+}
+
+// SplitCalendarEvent splits a FullCalendar event over [start, end) into one event per day in loc
+// FullCalendar растягивает событие с временем через несколько дней только в сетке недели; части
+// одного бронирования связаны groupId (id бронирования), полный период - в extendedProps
+// booking_start и booking_end. Событие в пределах дня возвращается без изменений
+func SplitCalendarEvent(event map[string]interface{}, start, end time.Time, loc *time.Location) []map[string]interface{} {
+	if !crossesMidnight(start, end, loc) {
+		return []map[string]interface{}{event}
+	}
+
+	var parts []map[string]interface{}
+	for from := start.In(loc); from.Before(end); {
+		y, m, d := from.Date()
+		to := time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+		if to.After(end) {
+			to = end.In(loc)
+		}
+
+		part := make(map[string]interface{}, len(event)+1)
+		for k, v := range event {
+			part[k] = v
+		}
+		part["groupId"] = event["id"]
+		part["start"] = from.Format(time.RFC3339)
+		part["end"] = to.Format(time.RFC3339)
+
+		props := map[string]interface{}{}
+		if extended, ok := event["extendedProps"].(map[string]interface{}); ok {
+			for k, v := range extended {
+				props[k] = v
+			}
+		}
+		props["booking_start"] = start.Format(time.RFC3339)
+		props["booking_end"] = end.Format(time.RFC3339)
+		props["segment"] = len(parts) + 1
+		part["extendedProps"] = props
+
+		parts = append(parts, part)
+		from = to
+	}
+	for _, part := range parts {
+		part["extendedProps"].(map[string]interface{})["segments"] = len(parts)
+	}
+	return parts
+}
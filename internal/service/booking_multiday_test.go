@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/models"
+)
+
+func TestCreateBooking_MultiDayPolicy(t *testing.T) {
+	moscow, _ := time.LoadLocation("Europe/Moscow")
+	evening := time.Now().In(moscow).AddDate(0, 0, 1)
+	evening = time.Date(evening.Year(), evening.Month(), evening.Day(), 22, 0, 0, 0, moscow)
+	overnight := CreateBookingRequest{RoomID: 1, StartTime: evening, EndTime: evening.Add(4 * time.Hour), Title: "Hackathon"}
+
+	svc := newTestBookingService(newFakeBookingStore())
+	svc.SetMultiDaySettings(MultiDaySettings{Policy: MultiDayReject, MaxDuration: 24 * time.Hour, Location: moscow})
+	ctx := context.Background()
+
+	if _, err := svc.CreateBooking(ctx, 10, overnight); !errors.Is(err, ErrMultiDayBooking) {
+		t.Errorf("Expected ErrMultiDayBooking, got: %v", err)
+	}
+	// Окончание ровно в полночь следующий день не захватывает
+	untilMidnight := overnight
+	untilMidnight.EndTime = evening.Add(2 * time.Hour)
+	if booking, err := svc.CreateBooking(ctx, 10, untilMidnight); err != nil || booking.MultiDay {
+		t.Errorf("Expected a booking until midnight to be allowed as a single day, got: %+v %v", booking, err)
+	}
+
+	svc.SetMultiDaySettings(MultiDaySettings{Policy: MultiDayAdmin, Location: moscow})
+	overnight.StartTime = overnight.StartTime.AddDate(0, 0, 1)
+	overnight.EndTime = overnight.EndTime.AddDate(0, 0, 1)
+	overnight.AllowMultiDay = true
+	if _, err := svc.CreateBooking(ctx, 10, overnight); !errors.Is(err, ErrMultiDayRequiresAdmin) {
+		t.Errorf("Expected ErrMultiDayRequiresAdmin for a user, got: %v", err)
+	}
+	booking, err := svc.CreateBooking(ctx, 12, overnight)
+	if err != nil {
+		t.Fatalf("Expected an administrator with allow_multi_day to book overnight, got: %v", err)
+	}
+	if !booking.MultiDay {
+		t.Error("Expected the overnight booking to be marked multi-day for the response warning")
+	}
+
+	// Перенос проверяется так же; изменение названия - нет
+	svc.SetMultiDaySettings(MultiDaySettings{Policy: MultiDayReject, Location: moscow})
+	title := "Hackathon finals"
+	if _, err := svc.UpdateBooking(ctx, booking.ID, 12, UpdateBookingRequest{Title: &title}); err != nil {
+		t.Errorf("Expected a title change to be allowed, got: %v", err)
+	}
+	end := booking.EndTime.Add(time.Hour)
+	if _, err := svc.UpdateBooking(ctx, booking.ID, 12, UpdateBookingRequest{EndTime: &end}); !errors.Is(err, ErrMultiDayBooking) {
+		t.Errorf("Expected ErrMultiDayBooking on reschedule, got: %v", err)
+	}
+}
+
+func TestIsMultiDay(t *testing.T) {
+	svc := newTestBookingService(newFakeBookingStore())
+	svc.SetMultiDaySettings(MultiDaySettings{MaxDuration: 10 * time.Hour})
+	start := time.Date(2026, time.March, 2, 20, 0, 0, 0, time.UTC)
+
+	// В часовом поясе комнаты 20:00-23:00 UTC - это 01:00-04:00 следующего дня в Новосибирске
+	novosibirsk := &models.Room{Timezone: "Asia/Novosibirsk"}
+	tests := []struct {
+		name  string
+		room  *models.Room
+		start time.Time
+		end   time.Time
+		want  bool
+	}{
+		{"same day", &models.Room{}, start, start.Add(3 * time.Hour), false},
+		{"crosses midnight", &models.Room{}, start, start.Add(5 * time.Hour), true},
+		{"room timezone", novosibirsk, start, start.Add(3 * time.Hour), false},
+		{"longer than max duration", novosibirsk, start, start.Add(11 * time.Hour), true},
+	}
+	for _, tt := range tests {
+		if got := svc.IsMultiDay(tt.room, tt.start, tt.end); got != tt.want {
+			t.Errorf("%s: expected %v, got: %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestSplitCalendarEvent(t *testing.T) {
+	start := time.Date(2026, time.March, 2, 22, 0, 0, 0, time.UTC)
+	end := start.Add(28 * time.Hour)
+	event := map[string]interface{}{
+		"id":            "7",
+		"title":         "Hackathon",
+		"start":         start.Format(time.RFC3339),
+		"end":           end.Format(time.RFC3339),
+		"extendedProps": map[string]interface{}{"status": models.BookingStatusConfirmed},
+	}
+
+	parts := SplitCalendarEvent(event, start, end, time.UTC)
+	if len(parts) != 3 {
+		t.Fatalf("Expected three days, got: %d", len(parts))
+	}
+	wantRanges := [][2]string{
+		{"2026-03-02T22:00:00Z", "2026-03-03T00:00:00Z"},
+		{"2026-03-03T00:00:00Z", "2026-03-04T00:00:00Z"},
+		{"2026-03-04T00:00:00Z", "2026-03-04T02:00:00Z"},
+	}
+	for i, part := range parts {
+		if part["start"] != wantRanges[i][0] || part["end"] != wantRanges[i][1] || part["groupId"] != "7" || part["title"] != "Hackathon" {
+			t.Errorf("Part %d: unexpected event %v", i, part)
+		}
+		props := part["extendedProps"].(map[string]interface{})
+		if props["segment"] != i+1 || props["segments"] != 3 || props["booking_end"] != end.Format(time.RFC3339) || props["status"] != models.BookingStatusConfirmed {
+			t.Errorf("Part %d: unexpected extendedProps %v", i, props)
+		}
+	}
+	if _, ok := event["groupId"]; ok {
+		t.Error("Expected the source event to stay unchanged")
+	}
+
+	if parts := SplitCalendarEvent(event, start, start.Add(2*time.Hour), time.UTC); len(parts) != 1 || parts[0]["start"] != event["start"] {
+		t.Errorf("Expected an event until midnight to stay whole, got: %v", parts)
+	}
+}
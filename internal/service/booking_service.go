@@ -1,56 +1,80 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"math"
 	"time"
 
 	"github.com/space/backend/internal/models"
-	"github.com/space/backend/internal/repository"
+	"github.com/space/backend/pkg/apperror"
+	"github.com/space/backend/pkg/validator"
 	"gorm.io/gorm"
 )
 
 var (
-	ErrBookingConflict = errors.New("booking conflict: room is already booked for this time")
-	ErrInvalidTime     = errors.New("invalid time: end time must be after start time")
-	ErrPastBooking     = errors.New("cannot create booking in the past")
-	ErrRoomNotFound    = errors.New("room not found")
-	ErrNotAuthorized   = errors.New("not authorized to perform this action")
+	ErrBookingConflict     = apperror.Conflict("booking_conflict", "booking conflict: room is already booked for this time") // Данные - conflicting_bookings
+	ErrInvalidTime         = apperror.BadRequest("invalid_time", "invalid time: end time must be after start time")
+	ErrPastBooking         = apperror.BadRequest("past_booking", "cannot create booking in the past")
+	ErrRoomNotFound        = apperror.NotFound("room_not_found", "room not found")
+	ErrNotAuthorized       = apperror.Forbidden("not_authorized", "not authorized to perform this action")
+	ErrBookingLimitReached = apperror.Forbidden("booking_limit_reached", "active booking limit reached")
+	ErrRoomNotOccupied     = apperror.Conflict("room_not_occupied", "room has no booking in progress")
+	ErrRoomInactive        = apperror.BadRequest("room_inactive", "room is not active")
+	ErrCreatorCannotLeave  = apperror.BadRequest("creator_cannot_leave", "creator cannot leave booking, use cancel instead")
+	ErrClosedDay           = apperror.Conflict("closed_day", "the space is closed on this day") // Данные - holiday
 )
 
-// BookingConflictError represents a conflict error with details about conflicting bookings
-type BookingConflictError struct {
-	Message            string            `json:"message"`
-	ConflictingBookings []models.Booking `json:"conflicting_bookings"`
-}
-
-func (e *BookingConflictError) Error() string {
-	return e.Message
-}
+// maxReminderShiftDays ограничивает перенос напоминания с нерабочих дней
+const maxReminderShiftDays = 14
 
 // BookingService handles booking business logic
 type BookingService struct {
-	bookingRepo         *repository.BookingRepository
-	roomRepo            *repository.RoomRepository
-	userRepo            *repository.UserRepository
-	notificationService *NotificationService
+	txManager   TxRunner
+	bookingRepo BookingStore
+	roomRepo    RoomStore
+	userRepo    UserStore
+	closures    ClosureCalendar     // nil - календарь нерабочих дней не используется
+	history     BookingHistoryStore // nil - поток событий бронирований не записывается
+	dedicated   DedicatedRooms      // nil - комнаты за командами не закрепляются
+	moderator   ContentModerator    // nil - тексты бронирований рассылаются без проверки
+	events      *EventBus
+	textLimits  TextLimits
+	multiDay    MultiDaySettings
+	logger      *slog.Logger
 }
 
 // NewBookingService creates a new booking service
 func NewBookingService(
-	bookingRepo *repository.BookingRepository,
-	roomRepo *repository.RoomRepository,
-	userRepo *repository.UserRepository,
-	notificationService *NotificationService,
+	txManager TxRunner,
+	bookingRepo BookingStore,
+	roomRepo RoomStore,
+	userRepo UserStore,
+	closures ClosureCalendar,
+	history BookingHistoryStore,
+	events *EventBus,
+	logger *slog.Logger,
 ) *BookingService {
 	return &BookingService{
-		bookingRepo:         bookingRepo,
-		roomRepo:            roomRepo,
-		userRepo:            userRepo,
-		notificationService: notificationService,
+		txManager:   txManager,
+		bookingRepo: bookingRepo,
+		roomRepo:    roomRepo,
+		userRepo:    userRepo,
+		closures:    closures,
+		history:     history,
+		events:      events,
+		textLimits:  DefaultTextLimits(),
+		logger:      logger,
 	}
 }
 
+// SetTextLimits sets the maximum lengths of booking titles and descriptions
+func (s *BookingService) SetTextLimits(limits TextLimits) {
+	s.textLimits = limits.withDefaults()
+}
+
 // CreateBookingRequest represents a request to create a booking
 type CreateBookingRequest struct {
 	RoomID                uint      `json:"room_id" binding:"required"`
@@ -60,114 +84,216 @@ type CreateBookingRequest struct {
 	Description           string    `json:"description"`
 	EstimatedParticipants int       `json:"estimated_participants"`
 	IsJoinable            bool      `json:"is_joinable"`
+	MaxParticipants       int       `json:"max_participants"` // Мест вместе с создателем; 0 - без ограничения
 	ParticipantIDs        []uint    `json:"participant_ids"`
+	LicensePlate          string    `json:"license_plate"`   // Обязателен для парковочного места, для комнат игнорируется
+	AllowMultiDay         bool      `json:"allow_multi_day"` // Бронирование на несколько дней при политике admin (только администраторы)
+}
+
+// SetDedicatedRooms enables rooms dedicated to teams: others book them only in delegated slots
+func (s *BookingService) SetDedicatedRooms(dedicated DedicatedRooms) {
+	s.dedicated = dedicated
+}
+
+// SetModerator enables the content filter: events of bookings with flagged texts wait for an admin review
+func (s *BookingService) SetModerator(moderator ContentModerator) {
+	s.moderator = moderator
+}
+
+// hold передаёт событие фильтру содержимого; true - рассылка задержана до проверки
+func (s *BookingService) hold(ctx context.Context, event BookingEvent) (bool, error) {
+	if s.moderator == nil {
+		return false, nil
+	}
+	return s.moderator.Hold(ctx, event)
 }
 
 // CreateBooking creates a new booking with validation
-func (s *BookingService) CreateBooking(creatorID uint, req CreateBookingRequest) (*models.Booking, error) {
+func (s *BookingService) CreateBooking(ctx context.Context, creatorID uint, req CreateBookingRequest) (*models.Booking, error) {
+	// Текстовые поля: управляющие символы убираются, длина ограничена
+	var fieldErrs validator.Errors
+	req.Title = fieldErrs.Text("title", req.Title, s.textLimits.Title, true)
+	req.Description = fieldErrs.Text("description", req.Description, s.textLimits.Description, false)
+	if req.MaxParticipants < 0 {
+		fieldErrs.Add("max_participants", ErrInvalidMaxParticipants)
+	}
+
 	// Валидация времени
 	if !req.EndTime.After(req.StartTime) {
-		return nil, ErrInvalidTime
+		fieldErrs.Add("end_time", ErrInvalidTime)
 	}
 
 	// Проверка что бронирование не в прошлом
 	if req.StartTime.Before(time.Now()) {
-		return nil, ErrPastBooking
+		fieldErrs.Add("start_time", ErrPastBooking)
 	}
-
-	// Проверка существования комнаты
-	room, err := s.roomRepo.GetByID(req.RoomID)
-	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, ErrRoomNotFound
-		}
+	if err := fieldErrs.Err(); err != nil {
 		return nil, err
 	}
 
-	if !room.IsActive {
-		return nil, errors.New("room is not active")
-	}
+	// Проверка комнаты, конфликтов и вставка - одна транзакция:
+	// строка комнаты блокируется, поэтому параллельные бронирования не пересекутся.
+	// Связи собираются из уже загруженных комнаты и пользователей - бронирование не перечитывается
+	var (
+		fullBooking *models.Booking
+		held        bool
+	)
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		// Проверка существования комнаты
+		room, err := s.roomRepo.LockByID(ctx, req.RoomID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return ErrRoomNotFound
+			}
+			return err
+		}
 
-	// Проверка на конфликты
-	conflictingBookings, err := s.bookingRepo.GetConflictingBookings(req.RoomID, req.StartTime, req.EndTime, nil)
-	if err != nil {
-		return nil, err
-	}
-	if len(conflictingBookings) > 0 {
-		return nil, &BookingConflictError{
-			Message:            "booking conflict: room is already booked for this time",
-			ConflictingBookings: conflictingBookings,
+		if !room.IsActive {
+			return ErrRoomInactive
+		}
+
+		// В нерабочие дни пространства (праздники, закрытия) бронировать нельзя; дни - в часовом поясе комнаты
+		if err := s.checkClosedDays(ctx, roomLocation(room), req.StartTime, req.EndTime); err != nil {
+			return err
+		}
+
+		var licensePlate string
+		if room.Kind == models.RoomKindParking {
+			if licensePlate, err = s.checkParkingRules(ctx, roomLocation(room), creatorID, req.StartTime, req.EndTime, req.LicensePlate, 0); err != nil {
+				return err
+			}
 		}
-	}
 
-	// Получаем участников если они указаны
-	var participants []models.User
-	if len(req.ParticipantIDs) > 0 {
-		participants, err = s.userRepo.GetByIDs(req.ParticipantIDs)
+		// Проверка на конфликты
+		conflictingBookings, err := s.bookingRepo.GetConflictingBookings(ctx, req.RoomID, req.StartTime, req.EndTime, nil)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		if len(conflictingBookings) > 0 {
+			return ErrBookingConflict.WithDetails("conflicting_bookings", conflictingBookings)
 		}
-	}
 
-	// Создаем бронирование
-	booking := &models.Booking{
-		RoomID:                req.RoomID,
-		CreatorID:             creatorID,
-		StartTime:             req.StartTime,
-		EndTime:               req.EndTime,
-		Title:                 req.Title,
-		Description:           req.Description,
-		EstimatedParticipants: req.EstimatedParticipants,
-		IsJoinable:            req.IsJoinable,
-		Status:                models.BookingStatusConfirmed,
-		Participants:          participants,
-	}
+		// Создатель и участники - одним запросом
+		users, err := s.userRepo.GetByIDs(ctx, append([]uint{creatorID}, req.ParticipantIDs...))
+		if err != nil {
+			return err
+		}
+		var creator *models.User
+		requested := make(map[uint]bool, len(req.ParticipantIDs))
+		for _, id := range req.ParticipantIDs {
+			requested[id] = true
+		}
+		var participants []models.User
+		for i := range users {
+			if users[i].ID == creatorID {
+				creator = &users[i]
+			}
+			if requested[users[i].ID] {
+				participants = append(participants, users[i])
+			}
+		}
+		if creator == nil {
+			return gorm.ErrRecordNotFound
+		}
 
-	err = s.bookingRepo.Create(booking)
-	if err != nil {
-		return nil, err
-	}
+		// Бронирование через полночь или длиннее порога - по политике многодневных бронирований
+		if err := s.checkMultiDay(room, creator, req.StartTime, req.EndTime, req.AllowMultiDay); err != nil {
+			return err
+		}
+
+		// Комнату, закреплённую за командой, бронируют её участники и те, кому они передали время
+		if s.dedicated != nil {
+			if err := s.dedicated.CheckBookingAccess(ctx, room.ID, creator, req.StartTime, req.EndTime); err != nil {
+				return err
+			}
+		}
+
+		// Лимит активных бронирований (задаётся администратором или при выявлении злоупотреблений)
+		// Параллельные бронирования в разные комнаты могут превысить лимит на одно - это допустимо
+		if creator.BookingLimit != nil {
+			upcoming, err := s.bookingRepo.CountUpcomingByCreator(ctx, creatorID, time.Now())
+			if err != nil {
+				return err
+			}
+			if upcoming >= int64(*creator.BookingLimit) {
+				return ErrBookingLimitReached
+			}
+		}
 
-	// Загружаем полную информацию о бронировании
-	fullBooking, err := s.bookingRepo.GetByID(booking.ID)
+		// Создаем бронирование
+		booking := &models.Booking{
+			RoomID:                req.RoomID,
+			CreatorID:             creatorID,
+			StartTime:             req.StartTime,
+			EndTime:               req.EndTime,
+			Title:                 req.Title,
+			Description:           req.Description,
+			EstimatedParticipants: req.EstimatedParticipants,
+			IsJoinable:            req.IsJoinable,
+			MaxParticipants:       req.MaxParticipants,
+			Status:                models.BookingStatusConfirmed,
+			LicensePlate:          licensePlate,
+			EstimatedCost:         BookingCost(room.HourlyPrice, req.StartTime, req.EndTime),
+			Participants:          participants,
+		}
+
+		if err := s.bookingRepo.Create(ctx, booking); err != nil {
+			return err
+		}
+		if err := recordHistory(ctx, s.history, booking.ID, models.BookingHistoryCreated, creatorID, createdHistoryData(booking)); err != nil {
+			return err
+		}
+
+		booking.Room = *room
+		booking.Creator = *creator
+		booking.MultiDay = s.IsMultiDay(room, booking.StartTime, booking.EndTime)
+		fullBooking = booking
+
+		// Подозрительные название или описание - рассылка ждёт проверки администратором
+		held, err = s.hold(ctx, BookingEvent{Type: EventBookingCreated, Booking: booking})
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// Отправляем уведомление боту о новом бронировании (асинхронно, не блокируя создание)
-	if s.notificationService != nil {
-		go func() {
-			if err := s.notificationService.NotifyBookingCreated(fullBooking); err != nil {
-				// Логируем ошибку, но не прерываем процесс создания бронирования
-				fmt.Printf("Failed to send booking notification: %v\n", err)
-			}
-		}()
+	// Уведомления (webhook бота, Slack) отправляются асинхронно, не блокируя создание
+	if !held {
+		s.events.Publish(BookingEvent{Type: EventBookingCreated, Booking: fullBooking})
 	}
 
 	return fullBooking, nil
 }
 
 // GetBooking gets a booking by ID
-func (s *BookingService) GetBooking(id uint) (*models.Booking, error) {
-	return s.bookingRepo.GetByID(id)
+func (s *BookingService) GetBooking(ctx context.Context, id uint) (*models.Booking, error) {
+	return s.bookingRepo.GetByID(ctx, id)
 }
 
-// GetUserBookings gets all bookings for a user
-func (s *BookingService) GetUserBookings(userID uint) ([]models.Booking, error) {
-	return s.bookingRepo.GetByUserID(userID)
+// GetUserBookings gets a page of bookings for a user, newest first unless order is given
+// limit <= 0 - размер страницы по умолчанию
+func (s *BookingService) GetUserBookings(ctx context.Context, userID uint, limit, offset int, order string) ([]models.Booking, int64, error) {
+	limit, offset = pageBounds(limit, offset, DefaultBookingPageSize, MaxBookingPageSize)
+	return s.bookingRepo.GetByUserID(ctx, userID, limit, offset, order)
 }
 
-// GetUserBookingsByTelegramID gets all bookings for a user by Telegram ID
-func (s *BookingService) GetUserBookingsByTelegramID(telegramID int64) ([]models.Booking, error) {
-	user, err := s.userRepo.GetByTelegramID(telegramID)
+// GetUserBookingsByTelegramID gets a page of bookings for a user by Telegram ID
+// Чужие бронирования доступны только администратору; actor - пользователь, от имени которого действует бот
+func (s *BookingService) GetUserBookingsByTelegramID(ctx context.Context, actor *models.User, telegramID int64, limit, offset int, order string) ([]models.Booking, int64, error) {
+	if actor.TelegramID != telegramID && !actor.IsAdmin() {
+		return nil, 0, ErrNotAuthorized
+	}
+	user, err := s.userRepo.GetByTelegramID(ctx, telegramID)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
-	return s.bookingRepo.GetByUserID(user.ID)
+	limit, offset = pageBounds(limit, offset, DefaultBookingPageSize, MaxBookingPageSize)
+	return s.bookingRepo.GetByUserID(ctx, user.ID, limit, offset, order)
 }
 
 // CreateSimpleBooking creates a new booking (simplified version for bot API)
 func (s *BookingService) CreateSimpleBooking(
+	ctx context.Context,
 	roomID uint,
 	creatorID uint,
 	startTime time.Time,
@@ -186,28 +312,89 @@ func (s *BookingService) CreateSimpleBooking(
 		EstimatedParticipants: estimatedParticipants,
 		IsJoinable:            isJoinable,
 	}
-	return s.CreateBooking(creatorID, req)
+	return s.CreateBooking(ctx, creatorID, req)
 }
 
 // GetUpcomingBookings gets upcoming bookings
-func (s *BookingService) GetUpcomingBookings(limit int) ([]models.Booking, error) {
-	return s.bookingRepo.GetUpcoming(limit)
+func (s *BookingService) GetUpcomingBookings(ctx context.Context, limit int) ([]models.Booking, error) {
+	return s.bookingRepo.GetUpcoming(ctx, limit)
 }
 
 // GetCalendarEvents gets bookings for calendar view
-func (s *BookingService) GetCalendarEvents(start, end time.Time) ([]models.Booking, error) {
-	return s.bookingRepo.GetForCalendar(start, end)
+func (s *BookingService) GetCalendarEvents(ctx context.Context, start, end time.Time) ([]models.Booking, error) {
+	return s.bookingRepo.GetForCalendar(ctx, start, end)
+}
+
+// ExportBookings passes active bookings in a time range (of one room if roomID != 0)
+// to fn in batches of ExportBatchSize, ordered by start time
+func (s *BookingService) ExportBookings(ctx context.Context, start, end time.Time, roomID uint, fn func([]models.Booking) error) error {
+	if !end.After(start) {
+		return ErrInvalidTime
+	}
+	return s.bookingRepo.ExportBatches(ctx, start, end, roomID, ExportBatchSize, fn)
+}
+
+// GetClosedDays gets closure days overlapping a time range for calendar views
+func (s *BookingService) GetClosedDays(ctx context.Context, start, end time.Time) ([]models.Holiday, error) {
+	if s.closures == nil {
+		return nil, nil
+	}
+	closed, err := s.closures.ClosedDays(ctx, start, end, nil)
+	if err != nil {
+		return nil, err
+	}
+	return closed.List(), nil
+}
+
+// GetDedicatedRooms gets rooms dedicated to teams in force at or after start; nil if dedicated rooms are disabled
+func (s *BookingService) GetDedicatedRooms(ctx context.Context, start time.Time) ([]models.RoomOwnership, error) {
+	if s.dedicated == nil {
+		return nil, nil
+	}
+	return s.dedicated.ActiveOwnerships(ctx, start)
+}
+
+// checkClosedDays отклоняет бронирование, пересекающее нерабочий день в часовом поясе loc
+func (s *BookingService) checkClosedDays(ctx context.Context, loc *time.Location, start, end time.Time) error {
+	holiday, err := s.closedDay(ctx, loc, start, end)
+	if err != nil {
+		return err
+	}
+	if holiday != nil {
+		return ErrClosedDay.WithDetails("holiday", *holiday)
+	}
+	return nil
+}
+
+// closedDay возвращает нерабочий день в периоде; nil - период рабочий или календарь не используется
+func (s *BookingService) closedDay(ctx context.Context, loc *time.Location, start, end time.Time) (*models.Holiday, error) {
+	if s.closures == nil {
+		return nil, nil
+	}
+	closed, err := s.closures.ClosedDays(ctx, start, end, loc)
+	if err != nil {
+		return nil, err
+	}
+	if holiday, ok := closed.Overlapping(start, end); ok {
+		return holiday, nil
+	}
+	return nil, nil
+}
+
+// GetCalendarSummaries gets compact bookings for calendar view: creator name and participant count instead of relations
+func (s *BookingService) GetCalendarSummaries(ctx context.Context, start, end time.Time) ([]models.BookingSummary, error) {
+	return s.bookingRepo.GetCalendarSummaries(ctx, start, end)
 }
 
 // CancelBooking cancels a booking (creator or admin can cancel)
-func (s *BookingService) CancelBooking(bookingID, userID uint) error {
-	booking, err := s.bookingRepo.GetByID(bookingID)
+func (s *BookingService) CancelBooking(ctx context.Context, bookingID, userID uint) error {
+	booking, err := s.bookingRepo.GetByID(ctx, bookingID)
 	if err != nil {
 		return err
 	}
 
 	// Получаем пользователя для проверки прав
-	user, err := s.userRepo.GetByID(userID)
+	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return err
 	}
@@ -217,54 +404,337 @@ func (s *BookingService) CancelBooking(bookingID, userID uint) error {
 		return ErrNotAuthorized
 	}
 
-	return s.bookingRepo.Cancel(bookingID)
+	if err := s.cancel(ctx, bookingID, userID); err != nil {
+		return err
+	}
+
+	booking.Status = models.BookingStatusCancelled
+	s.events.Publish(BookingEvent{Type: EventBookingCancelled, Booking: booking})
+	return nil
+}
+
+// cancel отменяет бронирование и записывает событие cancelled; actorID 0 - система
+func (s *BookingService) cancel(ctx context.Context, bookingID, actorID uint) error {
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.bookingRepo.Cancel(ctx, bookingID); err != nil {
+			return err
+		}
+		return recordHistory(ctx, s.history, bookingID, models.BookingHistoryCancelled, actorID, nil)
+	})
+}
+
+// ReleaseRoom ends the booking in progress in a room now (admin): status completed, end_time = now
+// Создатель получает уведомление, комната сразу показывается свободной
+func (s *BookingService) ReleaseRoom(ctx context.Context, roomID uint) (*models.Booking, error) {
+	if _, err := s.roomRepo.GetByID(ctx, roomID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoomNotFound
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	booking, err := s.bookingRepo.GetCurrentByRoom(ctx, roomID, now)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRoomNotOccupied
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Бронирование могли отменить или оно закончилось между выборкой и обновлением
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		released, err := s.bookingRepo.Release(ctx, booking.ID, now)
+		if err != nil {
+			return err
+		}
+		if !released {
+			return ErrRoomNotOccupied
+		}
+		return recordHistory(ctx, s.history, booking.ID, models.BookingHistoryCompleted, 0, &models.BookingHistoryData{EndTime: &now})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	booking.Status = models.BookingStatusCompleted
+	booking.EndTime = now
+	s.events.Publish(BookingEvent{Type: EventBookingReleased, Booking: booking})
+	return booking, nil
+}
+
+// CancelFutureBookingsOf cancels bookings of a departed user that have not started yet
+// and removes the user from participants of other future bookings
+func (s *BookingService) CancelFutureBookingsOf(ctx context.Context, userID uint) (int, error) {
+	now := time.Now()
+	bookings, err := s.bookingRepo.GetFutureByCreator(ctx, userID, now)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range bookings {
+		if err := s.cancel(ctx, bookings[i].ID, 0); err != nil {
+			return i, err
+		}
+		bookings[i].Status = models.BookingStatusCancelled
+		s.events.Publish(BookingEvent{Type: EventBookingCancelled, Booking: &bookings[i]})
+	}
+
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		bookingIDs, err := s.bookingRepo.RemoveFromFutureBookings(ctx, userID, now)
+		if err != nil {
+			return err
+		}
+		for _, bookingID := range bookingIDs {
+			if err := recordHistory(ctx, s.history, bookingID, models.BookingHistoryLeft, 0, &models.BookingHistoryData{UserID: &userID}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return len(bookings), err
+	}
+	return len(bookings), nil
+}
+
+// CompleteEnded marks confirmed bookings that have ended as completed and records the completed event
+// Возвращает число завершённых бронирований; параллельные реплики не завершают одно бронирование дважды
+func (s *BookingService) CompleteEnded(ctx context.Context) (int, error) {
+	now := time.Now()
+	completed := 0
+	for {
+		bookings, err := s.bookingRepo.GetEndedConfirmed(ctx, now, completeBatchSize)
+		if err != nil {
+			return completed, err
+		}
+		for i := range bookings {
+			booking := &bookings[i]
+			var done bool
+			err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+				var err error
+				if done, err = s.bookingRepo.Complete(ctx, booking.ID, now); err != nil || !done {
+					return err
+				}
+				return recordHistory(ctx, s.history, booking.ID, models.BookingHistoryCompleted, 0, &models.BookingHistoryData{EndTime: &booking.EndTime})
+			})
+			if err != nil {
+				return completed, err
+			}
+			if done {
+				completed++
+			}
+		}
+		if len(bookings) < completeBatchSize {
+			return completed, nil
+		}
+	}
+}
+
+// RunScheduled completes ended bookings (background job)
+func (s *BookingService) RunScheduled(ctx context.Context) error {
+	completed, err := s.CompleteEnded(ctx)
+	if completed > 0 {
+		s.logger.Info("ended bookings completed", "count", completed)
+	}
+	return err
+}
+
+// SendReminders publishes booking.reminder for bookings starting within lead
+// Каждое бронирование напоминается один раз, даже если задача работает на нескольких репликах.
+// Напоминание, выпадающее на нерабочий день, переносится на то же время последнего рабочего дня перед ним
+func (s *BookingService) SendReminders(ctx context.Context, lead time.Duration) (int, error) {
+	now := time.Now()
+	var closed *ClosedDays
+	if s.closures != nil {
+		var err error
+		if closed, err = s.closures.ClosedDays(ctx, now, now.Add(lead).AddDate(0, 0, maxReminderShiftDays+1), nil); err != nil {
+			return 0, err
+		}
+	}
+
+	// Окно выборки расширяется на идущие подряд нерабочие дни: напоминания с них переносятся на сегодня
+	horizon := now.Add(lead)
+	for day := 0; day <= maxReminderShiftDays; day++ {
+		if _, ok := closed.On(now.AddDate(0, 0, day)); ok {
+			horizon = now.Add(lead).AddDate(0, 0, day+1)
+		} else if day > 0 {
+			break
+		}
+	}
+
+	bookings, err := s.bookingRepo.GetDueForReminder(ctx, now, horizon)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range bookings {
+		if reminderTime(bookings[i].StartTime, lead, closed).After(now) {
+			continue
+		}
+		claimed, err := s.bookingRepo.MarkReminderSent(ctx, bookings[i].ID, now)
+		if err != nil {
+			return sent, err
+		}
+		if !claimed {
+			continue
+		}
+		s.events.Publish(BookingEvent{Type: EventBookingReminder, Booking: &bookings[i]})
+		sent++
+	}
+	return sent, nil
+}
+
+// reminderTime возвращает момент напоминания о бронировании с учётом переноса с нерабочих дней
+func reminderTime(start time.Time, lead time.Duration, closed *ClosedDays) time.Time {
+	at := start.Add(-lead)
+	for shifted := 0; shifted < maxReminderShiftDays; shifted++ {
+		if _, ok := closed.On(at); !ok {
+			break
+		}
+		at = at.AddDate(0, 0, -1)
+	}
+	return at
 }
 
 // JoinBooking allows a user to join a joinable booking
-func (s *BookingService) JoinBooking(bookingID, userID uint) error {
-	booking, err := s.bookingRepo.GetByID(bookingID)
+func (s *BookingService) JoinBooking(ctx context.Context, bookingID, userID uint) error {
+	booking, err := s.bookingRepo.GetByID(ctx, bookingID)
 	if err != nil {
 		return err
 	}
 
-	if !booking.IsJoinable {
-		return errors.New("this booking is not joinable")
+	if err := checkJoinable(booking); err != nil {
+		return err
 	}
-
-	if booking.Status != models.BookingStatusConfirmed {
-		return errors.New("cannot join cancelled or completed booking")
+	if isBookingMember(booking, userID) {
+		return nil
 	}
 
-	return s.bookingRepo.AddParticipant(bookingID, userID)
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		// Блокировка комнаты упорядочивает присоединения: свободное место не займут двое
+		if _, err := s.roomRepo.LockByID(ctx, booking.RoomID); err != nil {
+			return err
+		}
+		taken, err := s.takenSeats(ctx, bookingID)
+		if err != nil {
+			return err
+		}
+		if !hasFreeSeat(booking, taken) {
+			return ErrBookingFull
+		}
+
+		added, err := s.bookingRepo.AddParticipant(ctx, bookingID, userID)
+		if err != nil || !added {
+			return err
+		}
+		// Присоединившийся напрямую больше не ждёт места
+		if _, err := s.bookingRepo.RemoveFromWaitlist(ctx, bookingID, userID); err != nil {
+			return err
+		}
+		return recordHistory(ctx, s.history, bookingID, models.BookingHistoryJoined, userID, &models.BookingHistoryData{UserID: &userID})
+	})
 }
 
 // LeaveBooking allows a participant to leave a booking
-func (s *BookingService) LeaveBooking(bookingID, userID uint) error {
-	booking, err := s.bookingRepo.GetByID(bookingID)
+// Освободившееся место получает первый в очереди (JoinWaitlist)
+func (s *BookingService) LeaveBooking(ctx context.Context, bookingID, userID uint) error {
+	booking, err := s.bookingRepo.GetByID(ctx, bookingID)
 	if err != nil {
 		return err
 	}
 
 	// Создатель не может покинуть бронирование, только отменить
 	if booking.CreatorID == userID {
-		return errors.New("creator cannot leave booking, use cancel instead")
+		return ErrCreatorCannotLeave
 	}
 
-	return s.bookingRepo.RemoveParticipant(bookingID, userID)
+	var promoted []models.User
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.roomRepo.LockByID(ctx, booking.RoomID); err != nil {
+			return err
+		}
+		removed, err := s.bookingRepo.RemoveParticipant(ctx, bookingID, userID)
+		if err != nil || !removed {
+			return err
+		}
+		if err := recordHistory(ctx, s.history, bookingID, models.BookingHistoryLeft, userID, &models.BookingHistoryData{UserID: &userID}); err != nil {
+			return err
+		}
+		promoted, err = s.promoteWaitlist(ctx, booking)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.publishPromotions(ctx, bookingID, promoted)
+	return nil
+}
+
+// AvailabilityCheck is the result of checking a room for a time period before booking it
+type AvailabilityCheck struct {
+	Available           bool             `json:"available"`
+	ConflictingBookings []models.Booking `json:"conflicting_bookings"`
+	Holiday             *models.Holiday  `json:"holiday,omitempty"` // Нерабочий день пространства в периоде
+	MultiDay            bool             `json:"multi_day"`         // Период через полночь или длиннее порога: предупреждение или отказ по политике
 }
 
 // CheckAvailability checks if a room is available for a time period
-func (s *BookingService) CheckAvailability(roomID uint, start, end time.Time) (bool, error) {
-	hasConflict, err := s.bookingRepo.CheckConflict(roomID, start, end, nil)
+// Проверки те же, что при создании бронирования: пересечения и нерабочие дни в часовом поясе комнаты
+func (s *BookingService) CheckAvailability(ctx context.Context, roomID uint, start, end time.Time) (*AvailabilityCheck, error) {
+	if !end.After(start) {
+		return nil, ErrInvalidTime
+	}
+
+	room, err := s.roomRepo.GetByID(ctx, roomID)
 	if err != nil {
-		return false, err
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoomNotFound
+		}
+		return nil, err
+	}
+
+	result := &AvailabilityCheck{ConflictingBookings: []models.Booking{}, MultiDay: s.IsMultiDay(room, start, end)}
+	if result.Holiday, err = s.closedDay(ctx, roomLocation(room), start, end); err != nil {
+		return nil, err
+	}
+
+	conflicting, err := s.bookingRepo.GetConflictingBookings(ctx, roomID, start, end, nil)
+	if err != nil {
+		return nil, err
 	}
-	return !hasConflict, nil
+	if len(conflicting) > 0 {
+		result.ConflictingBookings = conflicting
+	}
+
+	result.Available = result.Holiday == nil && len(conflicting) == 0
+	return result, nil
 }
 
 // GetRoomBookings gets all bookings for a specific room in a time range
-func (s *BookingService) GetRoomBookings(roomID uint, start, end time.Time) ([]models.Booking, error) {
-	return s.bookingRepo.GetByRoomAndTimeRange(roomID, start, end)
+func (s *BookingService) GetRoomBookings(ctx context.Context, roomID uint, start, end time.Time) ([]models.Booking, error) {
+	return s.bookingRepo.GetByRoomAndTimeRange(ctx, roomID, start, end)
+}
+
+// GetRoomDayBookings gets bookings of a room on the calendar day of date
+// День считается в часовом поясе комнаты; у комнаты без часового пояса - в часовом поясе date
+func (s *BookingService) GetRoomDayBookings(ctx context.Context, roomID uint, date time.Time) ([]models.Booking, error) {
+	room, err := s.roomRepo.GetByID(ctx, roomID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoomNotFound
+		}
+		return nil, err
+	}
+	loc := roomLocation(room)
+	if loc == nil {
+		loc = date.Location()
+	}
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+	end := start.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	return s.bookingRepo.GetByRoomAndTimeRange(ctx, roomID, start, end)
 }
 
 // UpdateBookingRequest represents a request to update a booking
@@ -275,17 +745,19 @@ type UpdateBookingRequest struct {
 	Description           *string    `json:"description"`
 	EstimatedParticipants *int       `json:"estimated_participants"`
 	IsJoinable            *bool      `json:"is_joinable"`
+	MaxParticipants       *int       `json:"max_participants"` // Увеличение лимита отдаёт места очереди
+	AllowMultiDay         bool       `json:"allow_multi_day"`  // Перенос на несколько дней при политике admin (только администраторы)
 }
 
 // UpdateBooking updates a booking (creator or admin can update)
-func (s *BookingService) UpdateBooking(bookingID, userID uint, req UpdateBookingRequest) (*models.Booking, error) {
-	booking, err := s.bookingRepo.GetByID(bookingID)
+func (s *BookingService) UpdateBooking(ctx context.Context, bookingID, userID uint, req UpdateBookingRequest) (*models.Booking, error) {
+	booking, err := s.bookingRepo.GetByID(ctx, bookingID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Получаем пользователя для проверки прав
-	user, err := s.userRepo.GetByID(userID)
+	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -295,7 +767,9 @@ func (s *BookingService) UpdateBooking(bookingID, userID uint, req UpdateBooking
 		return nil, ErrNotAuthorized
 	}
 
-	// Обновляем поля
+	// Обновляем поля; исходное состояние нужно для списка изменений в booking.updated
+	before := *booking
+	var fieldErrs validator.Errors
 	if req.StartTime != nil {
 		booking.StartTime = *req.StartTime
 	}
@@ -303,10 +777,10 @@ func (s *BookingService) UpdateBooking(bookingID, userID uint, req UpdateBooking
 		booking.EndTime = *req.EndTime
 	}
 	if req.Title != nil {
-		booking.Title = *req.Title
+		booking.Title = fieldErrs.Text("title", *req.Title, s.textLimits.Title, true)
 	}
 	if req.Description != nil {
-		booking.Description = *req.Description
+		booking.Description = fieldErrs.Text("description", *req.Description, s.textLimits.Description, false)
 	}
 	if req.EstimatedParticipants != nil {
 		booking.EstimatedParticipants = *req.EstimatedParticipants
@@ -314,33 +788,142 @@ func (s *BookingService) UpdateBooking(bookingID, userID uint, req UpdateBooking
 	if req.IsJoinable != nil {
 		booking.IsJoinable = *req.IsJoinable
 	}
+	if req.MaxParticipants != nil {
+		if *req.MaxParticipants < 0 {
+			fieldErrs.Add("max_participants", ErrInvalidMaxParticipants)
+		}
+		// Участники сверх уменьшенного лимита остаются, новые присоединяются только через очередь
+		booking.MaxParticipants = *req.MaxParticipants
+	}
 
 	// Валидация времени
 	if !booking.EndTime.After(booking.StartTime) {
-		return nil, ErrInvalidTime
+		fieldErrs.Add("end_time", ErrInvalidTime)
 	}
-
-	// Проверка на конфликты (исключая текущее бронирование)
-	conflictingBookings, err := s.bookingRepo.GetConflictingBookings(booking.RoomID, booking.StartTime, booking.EndTime, &bookingID)
-	if err != nil {
+	if err := fieldErrs.Err(); err != nil {
 		return nil, err
 	}
-	if len(conflictingBookings) > 0 {
-		return nil, &BookingConflictError{
-			Message:            "booking conflict: room is already booked for this time",
-			ConflictingBookings: conflictingBookings,
+
+	// Перенос на нерабочий день запрещён; прочие изменения бронирования, уже попавшего на такой день, допустимы
+	// Так же и с многодневными бронированиями: политика проверяется только при переносе
+	if req.StartTime != nil || req.EndTime != nil {
+		if err := s.checkMultiDay(&booking.Room, user, booking.StartTime, booking.EndTime, req.AllowMultiDay); err != nil {
+			return nil, err
+		}
+		if err := s.checkClosedDays(ctx, roomLocation(&booking.Room), booking.StartTime, booking.EndTime); err != nil {
+			return nil, err
+		}
+		if booking.Room.Kind == models.RoomKindParking {
+			if _, err := s.checkParkingRules(ctx, roomLocation(&booking.Room), booking.CreatorID, booking.StartTime, booking.EndTime, booking.LicensePlate, booking.ID); err != nil {
+				return nil, err
+			}
+		}
+		if s.dedicated != nil {
+			if err := s.dedicated.CheckBookingAccess(ctx, booking.RoomID, user, booking.StartTime, booking.EndTime); err != nil {
+				return nil, err
+			}
+		}
+		// Стоимость пересчитывается по текущей цене комнаты
+		booking.EstimatedCost = BookingCost(booking.Room.HourlyPrice, booking.StartTime, booking.EndTime)
+	}
+
+	// Связи загружены вместе с бронированием и не меняются - после сохранения оно не перечитывается
+	changes := bookingChanges(&before, booking)
+	var (
+		promoted []models.User
+		held     bool
+	)
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		// Блокируем комнату на время проверки конфликтов и сохранения
+		if _, err := s.roomRepo.LockByID(ctx, booking.RoomID); err != nil {
+			return err
+		}
+
+		// Проверка на конфликты (исключая текущее бронирование)
+		conflictingBookings, err := s.bookingRepo.GetConflictingBookings(ctx, booking.RoomID, booking.StartTime, booking.EndTime, &bookingID)
+		if err != nil {
+			return err
+		}
+		if len(conflictingBookings) > 0 {
+			return ErrBookingConflict.WithDetails("conflicting_bookings", conflictingBookings)
 		}
-	}
 
-	err = s.bookingRepo.Update(booking)
+		if err := s.bookingRepo.Update(ctx, booking); err != nil {
+			return err
+		}
+		if err := recordHistory(ctx, s.history, booking.ID, models.BookingHistoryUpdated, userID, updatedHistoryData(booking)); err != nil {
+			return err
+		}
+		if len(changes) > 0 {
+			if held, err = s.hold(ctx, BookingEvent{Type: EventBookingUpdated, Booking: booking, Changes: changes}); err != nil {
+				return err
+			}
+		}
+		// Лимит увеличен или снят, мероприятие снова открыто - места получает очередь
+		if booking.MaxParticipants != before.MaxParticipants || booking.IsJoinable != before.IsJoinable {
+			promoted, err = s.promoteWaitlist(ctx, booking)
+			return err
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return s.bookingRepo.GetByID(bookingID)
+	if len(changes) > 0 && !held {
+		s.events.Publish(BookingEvent{Type: EventBookingUpdated, Booking: booking, Changes: changes})
+	}
+	s.publishPromotions(ctx, booking.ID, promoted)
+	return booking, nil
+}
+
+// BookingCost estimates the cost of [start, end) at an hourly price, rounded to the minimal currency unit
+func BookingCost(hourlyPrice int64, start, end time.Time) int64 {
+	if hourlyPrice <= 0 || !end.After(start) {
+		return 0
+	}
+	return int64(math.Round(float64(hourlyPrice) * end.Sub(start).Hours()))
+}
+
+// FormatSummaryForCalendar formats a booking summary for FullCalendar in the shape of FormatBookingForCalendar
+// Вместо списка участников - participant_count, создатель - только id и имя
+func FormatSummaryForCalendar(summary *models.BookingSummary) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         fmt.Sprintf("%d", summary.ID),
+		"title":      summary.Title,
+		"start":      summary.StartTime.Format(time.RFC3339),
+		"end":        summary.EndTime.Format(time.RFC3339),
+		"resourceId": fmt.Sprintf("%d", summary.RoomID),
+		"calendarId": fmt.Sprintf("room_%d", summary.RoomID),
+		"creator": map[string]interface{}{
+			"id":         summary.CreatorID,
+			"first_name": summary.CreatorFirstName,
+			"last_name":  summary.CreatorLastName,
+		},
+		"extendedProps": map[string]interface{}{
+			"roomName":          summary.RoomName,
+			"participant_count": summary.ParticipantCount,
+			"allow_join":        summary.IsJoinable,
+			"status":            summary.Status,
+		},
+	}
+}
+
+// FormatHolidayForCalendar formats a closure day as an all-day FullCalendar background event
+func FormatHolidayForCalendar(holiday *models.Holiday) map[string]interface{} {
+	return map[string]interface{}{
+		"id":      fmt.Sprintf("holiday_%d", holiday.ID),
+		"title":   holiday.Name,
+		"start":   holiday.Date,
+		"allDay":  true,
+		"display": "background",
+		"extendedProps": map[string]interface{}{
+			"type":   "closure",
+			"source": holiday.Source,
+		},
+	}
 }
 
-// FormatBookingForCalendar formats booking for FullCalendar
 func FormatBookingForCalendar(booking *models.Booking) map[string]interface{} {
 	// Формируем информацию о создателе
 	creatorInfo := map[string]interface{}{
@@ -0,0 +1,391 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/pkg/apperror"
+	"github.com/space/backend/pkg/validator"
+)
+
+func newTestBookingService(bookings *fakeBookingStore) *BookingService {
+	rooms := &fakeRoomStore{rooms: map[uint]*models.Room{
+		1: {ID: 1, Name: "Room 1", IsActive: true},
+		2: {ID: 2, Name: "Closed", IsActive: false},
+	}}
+	users := &fakeUserStore{users: map[uint]*models.User{
+		10: {ID: 10, Role: models.RoleUser},
+		11: {ID: 11, Role: models.RoleUser},
+		12: {ID: 12, Role: models.RoleAdmin},
+	}}
+	return NewBookingService(fakeTx{}, bookings, rooms, users, nil, nil, nil, slog.Default())
+}
+
+func TestCreateBookingValidation(t *testing.T) {
+	svc := newTestBookingService(newFakeBookingStore())
+	ctx := context.Background()
+	start := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name string
+		req  CreateBookingRequest
+		want error
+	}{
+		{
+			name: "end before start",
+			req:  CreateBookingRequest{RoomID: 1, StartTime: start, EndTime: start.Add(-time.Minute), Title: "Standup"},
+			want: ErrInvalidTime,
+		},
+		{
+			name: "in the past",
+			req:  CreateBookingRequest{RoomID: 1, StartTime: time.Now().Add(-time.Hour), EndTime: time.Now(), Title: "Standup"},
+			want: ErrPastBooking,
+		},
+		{
+			name: "unknown room",
+			req:  CreateBookingRequest{RoomID: 99, StartTime: start, EndTime: start.Add(time.Hour), Title: "Standup"},
+			want: ErrRoomNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.CreateBooking(ctx, 10, tt.req)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got: %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestCreateBookingTextFields(t *testing.T) {
+	svc := newTestBookingService(newFakeBookingStore())
+	svc.SetTextLimits(TextLimits{Title: 10, Description: 20})
+	ctx := context.Background()
+	start := time.Now().Add(time.Hour)
+
+	_, err := svc.CreateBooking(ctx, 10, CreateBookingRequest{
+		RoomID: 1, StartTime: start, EndTime: start.Add(time.Hour), Title: " \x00 ", Description: strings.Repeat("д", 21),
+	})
+	var fieldErrs validator.Errors
+	if !errors.As(err, &fieldErrs) || len(fieldErrs) != 2 {
+		t.Fatalf("Expected errors for title and description, got: %v", err)
+	}
+	if fieldErrs[0].Field != "title" || fieldErrs[0].Rule != validator.RuleRequired ||
+		fieldErrs[1].Field != "description" || fieldErrs[1].Rule != validator.RuleMaxLength || fieldErrs[1].Param != "20" {
+		t.Errorf("Expected required title and too long description, got: %+v", fieldErrs)
+	}
+
+	// Управляющие символы убираются, длина считается в символах, а не байтах
+	booking, err := svc.CreateBooking(ctx, 10, CreateBookingRequest{
+		RoomID: 1, StartTime: start, EndTime: start.Add(time.Hour), Title: " Планёрка\x07 ", Description: strings.Repeat("д", 20),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if booking.Title != "Планёрка" {
+		t.Errorf("Expected sanitized title, got: %q", booking.Title)
+	}
+
+	title := strings.Repeat("x", 11)
+	if _, err := svc.UpdateBooking(ctx, booking.ID, 10, UpdateBookingRequest{Title: &title}); !errors.As(err, &fieldErrs) {
+		t.Errorf("Expected a field error on update, got: %v", err)
+	}
+}
+
+func TestCreateBookingConflict(t *testing.T) {
+	start := time.Now().Add(time.Hour).Truncate(time.Minute)
+	store := newFakeBookingStore(models.Booking{
+		ID: 1, RoomID: 1, CreatorID: 11, StartTime: start, EndTime: start.Add(time.Hour), Status: models.BookingStatusConfirmed,
+	})
+	svc := newTestBookingService(store)
+	ctx := context.Background()
+
+	_, err := svc.CreateBooking(ctx, 10, CreateBookingRequest{
+		RoomID: 1, StartTime: start.Add(30 * time.Minute), EndTime: start.Add(90 * time.Minute), Title: "Overlap",
+	})
+	conflict, ok := apperror.As(err)
+	if !ok || !errors.Is(err, ErrBookingConflict) {
+		t.Fatalf("Expected ErrBookingConflict, got: %v", err)
+	}
+	conflicting, _ := conflict.Details.([]models.Booking)
+	if conflict.DetailsName != "conflicting_bookings" || len(conflicting) != 1 || conflicting[0].ID != 1 {
+		t.Errorf("Expected conflict with booking 1, got: %s %+v", conflict.DetailsName, conflict.Details)
+	}
+
+	// Смежный слот не пересекается с существующим
+	booking, err := svc.CreateBooking(ctx, 10, CreateBookingRequest{
+		RoomID: 1, StartTime: start.Add(time.Hour), EndTime: start.Add(2 * time.Hour), Title: "Adjacent",
+	})
+	if err != nil {
+		t.Fatalf("Expected adjacent booking to succeed, got: %v", err)
+	}
+	if booking.Status != models.BookingStatusConfirmed {
+		t.Errorf("Expected status confirmed, got: %s", booking.Status)
+	}
+}
+
+func TestCheckAvailability(t *testing.T) {
+	start := time.Now().Add(time.Hour).Truncate(time.Minute)
+	store := newFakeBookingStore(models.Booking{
+		ID: 1, RoomID: 1, CreatorID: 11, StartTime: start, EndTime: start.Add(time.Hour), Status: models.BookingStatusConfirmed,
+	})
+	svc := newTestBookingService(store)
+	ctx := context.Background()
+
+	result, err := svc.CheckAvailability(ctx, 1, start.Add(30*time.Minute), start.Add(90*time.Minute))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result.Available || len(result.ConflictingBookings) != 1 || result.ConflictingBookings[0].ID != 1 {
+		t.Errorf("Expected a conflict with booking 1, got: %+v", result)
+	}
+
+	result, err = svc.CheckAvailability(ctx, 1, start.Add(time.Hour), start.Add(2*time.Hour))
+	if err != nil || !result.Available || result.ConflictingBookings == nil {
+		t.Errorf("Expected the adjacent slot to be available with an empty list, got: %+v, %v", result, err)
+	}
+
+	if _, err := svc.CheckAvailability(ctx, 99, start, start.Add(time.Hour)); !errors.Is(err, ErrRoomNotFound) {
+		t.Errorf("Expected ErrRoomNotFound, got: %v", err)
+	}
+	if _, err := svc.CheckAvailability(ctx, 1, start, start); !errors.Is(err, ErrInvalidTime) {
+		t.Errorf("Expected ErrInvalidTime, got: %v", err)
+	}
+}
+
+func TestGetUserBookingsByTelegramID_Access(t *testing.T) {
+	start := time.Now().Add(time.Hour)
+	store := newFakeBookingStore(models.Booking{ID: 1, RoomID: 1, CreatorID: 10, StartTime: start, EndTime: start.Add(time.Hour), Status: models.BookingStatusConfirmed})
+	users := &fakeUserStore{users: map[uint]*models.User{
+		10: {ID: 10, TelegramID: 100, Role: models.RoleUser},
+		11: {ID: 11, TelegramID: 110, Role: models.RoleUser},
+		12: {ID: 12, TelegramID: 120, Role: models.RoleAdmin},
+	}}
+	svc := NewBookingService(fakeTx{}, store, &fakeRoomStore{}, users, nil, nil, nil, slog.Default())
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		actor uint
+		want  error
+	}{
+		{"self", 10, nil},
+		{"another user", 11, ErrNotAuthorized},
+		{"admin", 12, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bookings, _, err := svc.GetUserBookingsByTelegramID(ctx, users.users[tt.actor], 100, 0, 0, "")
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Expected %v, got: %v", tt.want, err)
+			}
+			if tt.want == nil && len(bookings) != 1 {
+				t.Errorf("Expected the booking of user 100, got: %+v", bookings)
+			}
+		})
+	}
+}
+
+func TestCreateBookingLimit(t *testing.T) {
+	start := time.Now().Add(time.Hour).Truncate(time.Minute)
+	limit := 1
+	rooms := &fakeRoomStore{rooms: map[uint]*models.Room{1: {ID: 1, Name: "Room 1", IsActive: true}}}
+	users := &fakeUserStore{users: map[uint]*models.User{10: {ID: 10, Role: models.RoleUser, BookingLimit: &limit}}}
+	svc := NewBookingService(fakeTx{}, newFakeBookingStore(), rooms, users, nil, nil, nil, slog.Default())
+	ctx := context.Background()
+
+	if _, err := svc.CreateBooking(ctx, 10, CreateBookingRequest{RoomID: 1, StartTime: start, EndTime: start.Add(time.Hour), Title: "First"}); err != nil {
+		t.Fatalf("Expected the first booking within the limit, got: %v", err)
+	}
+	_, err := svc.CreateBooking(ctx, 10, CreateBookingRequest{RoomID: 1, StartTime: start.Add(time.Hour), EndTime: start.Add(2 * time.Hour), Title: "Second"})
+	if !errors.Is(err, ErrBookingLimitReached) {
+		t.Errorf("Expected ErrBookingLimitReached, got: %v", err)
+	}
+}
+
+// recordingEventSubscriber запоминает доставленные события
+type recordingEventSubscriber struct {
+	events []BookingEvent
+}
+
+func (r *recordingEventSubscriber) HandleBookingEvent(ctx context.Context, event BookingEvent) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func TestUpdateBookingPublishesChanges(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	store := newFakeBookingStore(models.Booking{
+		ID: 1, RoomID: 1, CreatorID: 10, Title: "Sync", StartTime: start, EndTime: start.Add(time.Hour), Status: models.BookingStatusConfirmed,
+	})
+	rooms := &fakeRoomStore{rooms: map[uint]*models.Room{1: {ID: 1, Name: "Room 1", IsActive: true}}}
+	users := &fakeUserStore{users: map[uint]*models.User{10: {ID: 10, Role: models.RoleUser}}}
+	events := NewEventBus(inlineTaskQueue{}, slog.Default())
+	recorder := &recordingEventSubscriber{}
+	events.Subscribe("recorder", recorder)
+	svc := NewBookingService(fakeTx{}, store, rooms, users, nil, nil, events, slog.Default())
+	ctx := context.Background()
+
+	newStart, newEnd, title := start.Add(time.Hour), start.Add(2*time.Hour), "Sync"
+	if _, err := svc.UpdateBooking(ctx, 1, 10, UpdateBookingRequest{StartTime: &newStart, EndTime: &newEnd, Title: &title}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(recorder.events) != 1 || recorder.events[0].Type != EventBookingUpdated {
+		t.Fatalf("Expected a booking.updated event, got: %+v", recorder.events)
+	}
+	changes := recorder.events[0].Changes
+	if len(changes) != 2 || changes[0].Field != "start_time" || !changes[0].Old.(time.Time).Equal(start) || !changes[0].New.(time.Time).Equal(newStart) || changes[1].Field != "end_time" {
+		t.Errorf("Expected start_time and end_time changes only, got: %+v", changes)
+	}
+
+	// Изменение без изменений - события нет
+	if _, err := svc.UpdateBooking(ctx, 1, 10, UpdateBookingRequest{Title: &title}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(recorder.events) != 1 {
+		t.Errorf("Expected no event for a no-op update, got: %+v", recorder.events)
+	}
+}
+
+func TestReleaseRoom(t *testing.T) {
+	now := time.Now()
+	store := newFakeBookingStore(models.Booking{
+		ID: 1, RoomID: 1, CreatorID: 10, Title: "Long meeting", StartTime: now.Add(-30 * time.Minute), EndTime: now.Add(time.Hour), Status: models.BookingStatusConfirmed,
+	})
+	rooms := &fakeRoomStore{rooms: map[uint]*models.Room{1: {ID: 1, Name: "Room 1", IsActive: true}, 2: {ID: 2, Name: "Free", IsActive: true}}}
+	events := NewEventBus(inlineTaskQueue{}, slog.Default())
+	recorder := &recordingEventSubscriber{}
+	events.Subscribe("recorder", recorder)
+	svc := NewBookingService(fakeTx{}, store, rooms, &fakeUserStore{}, nil, nil, events, slog.Default())
+	ctx := context.Background()
+
+	booking, err := svc.ReleaseRoom(ctx, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if booking.Status != models.BookingStatusCompleted || booking.EndTime.After(time.Now()) || store.bookings[1].Status != models.BookingStatusCompleted {
+		t.Errorf("Expected the booking to be completed now, got: %+v", booking)
+	}
+	if len(recorder.events) != 1 || recorder.events[0].Type != EventBookingReleased || recorder.events[0].Booking.ID != 1 {
+		t.Errorf("Expected a booking.released event, got: %+v", recorder.events)
+	}
+
+	// Повторное освобождение и свободная комната - нечего завершать
+	for _, roomID := range []uint{1, 2} {
+		if _, err := svc.ReleaseRoom(ctx, roomID); !errors.Is(err, ErrRoomNotOccupied) {
+			t.Errorf("Room %d: expected ErrRoomNotOccupied, got: %v", roomID, err)
+		}
+	}
+	if _, err := svc.ReleaseRoom(ctx, 99); !errors.Is(err, ErrRoomNotFound) {
+		t.Errorf("Expected ErrRoomNotFound, got: %v", err)
+	}
+}
+
+func TestCancelBookingAuthorization(t *testing.T) {
+	start := time.Now().Add(time.Hour)
+	store := newFakeBookingStore(models.Booking{
+		ID: 1, RoomID: 1, CreatorID: 10, StartTime: start, EndTime: start.Add(time.Hour), Status: models.BookingStatusConfirmed,
+	})
+	svc := newTestBookingService(store)
+	ctx := context.Background()
+
+	if err := svc.CancelBooking(ctx, 1, 11); !errors.Is(err, ErrNotAuthorized) {
+		t.Errorf("Expected ErrNotAuthorized for other user, got: %v", err)
+	}
+	if store.bookings[1].Status != models.BookingStatusConfirmed {
+		t.Errorf("Expected booking to stay confirmed, got: %s", store.bookings[1].Status)
+	}
+
+	if err := svc.CancelBooking(ctx, 1, 12); err != nil {
+		t.Errorf("Expected admin to cancel booking, got: %v", err)
+	}
+	if store.bookings[1].Status != models.BookingStatusCancelled {
+		t.Errorf("Expected booking to be cancelled, got: %s", store.bookings[1].Status)
+	}
+}
+
+func TestBookingCost(t *testing.T) {
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	if cost := BookingCost(150000, start, start.Add(90*time.Minute)); cost != 225000 {
+		t.Errorf("Expected 225000, got: %d", cost)
+	}
+	if cost := BookingCost(0, start, start.Add(time.Hour)); cost != 0 {
+		t.Errorf("Expected free booking, got: %d", cost)
+	}
+}
+
+func TestBookingWaitlist(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	store := newFakeBookingStore(models.Booking{
+		ID: 1, RoomID: 1, CreatorID: 10, Title: "Talk", StartTime: start, EndTime: start.Add(time.Hour),
+		Status: models.BookingStatusConfirmed, IsJoinable: true, MaxParticipants: 2,
+	})
+	rooms := &fakeRoomStore{rooms: map[uint]*models.Room{1: {ID: 1, Name: "Room 1", IsActive: true}}}
+	users := &fakeUserStore{users: map[uint]*models.User{10: {ID: 10, Role: models.RoleUser}}}
+	events := NewEventBus(inlineTaskQueue{}, slog.Default())
+	recorder := &recordingEventSubscriber{}
+	events.Subscribe("recorder", recorder)
+	svc := NewBookingService(fakeTx{}, store, rooms, users, nil, nil, events, slog.Default())
+	ctx := context.Background()
+
+	if _, err := svc.JoinWaitlist(ctx, 1, 11); !errors.Is(err, ErrBookingHasSeats) {
+		t.Errorf("Expected ErrBookingHasSeats while a seat is free, got: %v", err)
+	}
+	if err := svc.JoinBooking(ctx, 1, 11); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := svc.JoinBooking(ctx, 1, 12); !errors.Is(err, ErrBookingFull) {
+		t.Fatalf("Expected ErrBookingFull, got: %v", err)
+	}
+	if _, err := svc.JoinWaitlist(ctx, 1, 11); !errors.Is(err, ErrAlreadyParticipant) {
+		t.Errorf("Expected ErrAlreadyParticipant, got: %v", err)
+	}
+
+	for i, userID := range []uint{12, 13, 14} {
+		position, err := svc.JoinWaitlist(ctx, 1, userID)
+		if err != nil || position.Position != i+1 || position.Length != i+1 {
+			t.Fatalf("User %d: expected position %d, got: %+v (%v)", userID, i+1, position, err)
+		}
+	}
+	if err := svc.LeaveWaitlist(ctx, 1, 13); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := svc.LeaveWaitlist(ctx, 1, 13); !errors.Is(err, ErrNotOnWaitlist) {
+		t.Errorf("Expected ErrNotOnWaitlist, got: %v", err)
+	}
+
+	// Ушедший участник освобождает место первому в очереди
+	if err := svc.LeaveBooking(ctx, 1, 11); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(recorder.events) != 1 || recorder.events[0].Type != EventBookingWaitlistPromoted || recorder.events[0].User.ID != 12 {
+		t.Fatalf("Expected user 12 to be promoted, got: %+v", recorder.events)
+	}
+	if participants := store.bookings[1].Participants; len(participants) != 1 || participants[0].ID != 12 {
+		t.Errorf("Expected user 12 to be the only participant, got: %+v", participants)
+	}
+	if position, _ := svc.GetWaitlistPosition(ctx, 1, 14); position.Position != 1 || position.Length != 1 {
+		t.Errorf("Expected user 14 to move up, got: %+v", position)
+	}
+
+	// Снятый лимит отдаёт места всей очереди
+	unlimited := 0
+	if _, err := svc.UpdateBooking(ctx, 1, 10, UpdateBookingRequest{MaxParticipants: &unlimited}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	last := recorder.events[len(recorder.events)-1]
+	if last.Type != EventBookingWaitlistPromoted || last.User.ID != 14 || len(store.waitlist[1]) != 0 {
+		t.Errorf("Expected user 14 to be promoted after the limit was lifted, got: %+v", recorder.events)
+	}
+
+	negative := -1
+	if _, err := svc.UpdateBooking(ctx, 1, 10, UpdateBookingRequest{MaxParticipants: &negative}); !errors.Is(err, ErrInvalidMaxParticipants) {
+		t.Errorf("Expected ErrInvalidMaxParticipants, got: %v", err)
+	}
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/pkg/apperror"
+)
+
+var (
+	ErrBookingFull            = apperror.Conflict("booking_full", "booking is full: join the waitlist to get a seat when someone leaves")
+	ErrBookingHasSeats        = apperror.Conflict("booking_has_seats", "booking has free seats: join it directly")
+	ErrAlreadyParticipant     = apperror.Conflict("already_participant", "already a participant of this booking")
+	ErrNotOnWaitlist          = apperror.NotFound("not_on_waitlist", "not on the waitlist of this booking")
+	ErrInvalidMaxParticipants = apperror.BadRequest("invalid_max_participants", "max_participants must not be negative")
+	ErrBookingNotJoinable     = apperror.BadRequest("booking_not_joinable", "this booking is not joinable")
+	ErrBookingClosed          = apperror.BadRequest("booking_closed", "cannot join cancelled or completed booking")
+)
+
+// WaitlistPosition is the place of a user in the seat waitlist of a booking
+type WaitlistPosition struct {
+	BookingID uint `json:"booking_id"`
+	Position  int  `json:"position"` // С 1; 0 - пользователь не в очереди
+	Length    int  `json:"length"`   // Сколько всего ждут места
+}
+
+// JoinWaitlist puts a user in the queue for a seat at a full joinable booking
+// Очередь - только для заполненного мероприятия: при свободном месте нужно присоединиться (JoinBooking)
+func (s *BookingService) JoinWaitlist(ctx context.Context, bookingID, userID uint) (*WaitlistPosition, error) {
+	booking, err := s.bookingRepo.GetByID(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkJoinable(booking); err != nil {
+		return nil, err
+	}
+	if isBookingMember(booking, userID) {
+		return nil, ErrAlreadyParticipant
+	}
+
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		// Та же блокировка, что у JoinBooking и LeaveBooking: место не освободится между проверкой и записью в очередь
+		if _, err := s.roomRepo.LockByID(ctx, booking.RoomID); err != nil {
+			return err
+		}
+		taken, err := s.takenSeats(ctx, bookingID)
+		if err != nil {
+			return err
+		}
+		if hasFreeSeat(booking, taken) {
+			return ErrBookingHasSeats
+		}
+		// Повторная запись оставляет пользователя на прежнем месте
+		_, err = s.bookingRepo.AddToWaitlist(ctx, bookingID, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetWaitlistPosition(ctx, bookingID, userID)
+}
+
+// LeaveWaitlist removes a user from the seat waitlist of a booking
+func (s *BookingService) LeaveWaitlist(ctx context.Context, bookingID, userID uint) error {
+	removed, err := s.bookingRepo.RemoveFromWaitlist(ctx, bookingID, userID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrNotOnWaitlist
+	}
+	return nil
+}
+
+// GetWaitlistPosition returns the place of a user in the seat waitlist of a booking
+func (s *BookingService) GetWaitlistPosition(ctx context.Context, bookingID, userID uint) (*WaitlistPosition, error) {
+	if _, err := s.bookingRepo.GetByID(ctx, bookingID); err != nil {
+		return nil, err
+	}
+	entries, err := s.bookingRepo.GetWaitlist(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	position := &WaitlistPosition{BookingID: bookingID, Length: len(entries)}
+	for i := range entries {
+		if entries[i].UserID == userID {
+			position.Position = i + 1
+			break
+		}
+	}
+	return position, nil
+}
+
+// promoteWaitlist отдаёт свободные места первым в очереди; вызывается в транзакции с заблокированной комнатой
+// Бронирование - в состоянии после изменения; получившие место добавляются в booking.Participants
+func (s *BookingService) promoteWaitlist(ctx context.Context, booking *models.Booking) ([]models.User, error) {
+	if checkJoinable(booking) != nil || !booking.EndTime.After(time.Now()) {
+		return nil, nil
+	}
+
+	entries, err := s.bookingRepo.GetWaitlist(ctx, booking.ID)
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	taken, err := s.takenSeats(ctx, booking.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var promoted []models.User
+	for _, entry := range entries {
+		if !hasFreeSeat(booking, taken) {
+			break
+		}
+		added, err := s.bookingRepo.AddParticipant(ctx, booking.ID, entry.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.bookingRepo.RemoveFromWaitlist(ctx, booking.ID, entry.UserID); err != nil {
+			return nil, err
+		}
+		if !added {
+			continue
+		}
+		if err := recordHistory(ctx, s.history, booking.ID, models.BookingHistoryJoined, entry.UserID, &models.BookingHistoryData{UserID: &entry.UserID}); err != nil {
+			return nil, err
+		}
+		taken++
+		promoted = append(promoted, entry.User)
+		booking.Participants = append(booking.Participants, entry.User)
+	}
+	return promoted, nil
+}
+
+// publishPromotions уведомляет получивших место из очереди; бронирование перечитывается с новым списком участников
+func (s *BookingService) publishPromotions(ctx context.Context, bookingID uint, promoted []models.User) {
+	if len(promoted) == 0 {
+		return
+	}
+	booking, err := s.bookingRepo.GetByID(ctx, bookingID)
+	if err != nil {
+		s.logger.Error("failed to load booking for waitlist notifications", "booking_id", bookingID, "error", err)
+		return
+	}
+	for i := range promoted {
+		s.logger.Info("waitlisted user got a seat", "booking_id", bookingID, "user_id", promoted[i].ID)
+		s.events.Publish(BookingEvent{Type: EventBookingWaitlistPromoted, Booking: booking, User: &promoted[i]})
+	}
+}
+
+// takenSeats - занятые места: создатель и участники
+func (s *BookingService) takenSeats(ctx context.Context, bookingID uint) (int, error) {
+	participants, err := s.bookingRepo.CountParticipants(ctx, bookingID)
+	if err != nil {
+		return 0, err
+	}
+	return int(participants) + 1, nil
+}
+
+// hasFreeSeat проверяет лимит мест мероприятия; 0 - без ограничения
+func hasFreeSeat(booking *models.Booking, taken int) bool {
+	return booking.MaxParticipants == 0 || taken < booking.MaxParticipants
+}
+
+// checkJoinable проверяет, что к бронированию можно присоединиться
+func checkJoinable(booking *models.Booking) error {
+	if !booking.IsJoinable {
+		return ErrBookingNotJoinable
+	}
+	if booking.Status != models.BookingStatusConfirmed {
+		return ErrBookingClosed
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/pkg/apperror"
+)
+
+// MaxBroadcastLength ограничивает текст объявления (в символах)
+const MaxBroadcastLength = 1000
+
+var (
+	ErrEmptyBroadcast   = apperror.BadRequest("empty_broadcast", "broadcast message must not be empty")
+	ErrBroadcastTooLong = apperror.BadRequest("broadcast_too_long", "broadcast message must not exceed 1000 characters")
+	ErrBroadcastTooSoon = apperror.New(http.StatusTooManyRequests, "broadcast_too_soon", "an announcement was already sent to this room recently")
+)
+
+// RoomBroadcast is an admin announcement to everyone following a room ("projector replaced")
+type RoomBroadcast struct {
+	Room     *models.Room
+	Message  string
+	SentByID uint
+	SentAt   time.Time
+}
+
+// BroadcastSubscriber is a delivery channel for room announcements (bot webhook, Slack)
+type BroadcastSubscriber interface {
+	HandleRoomBroadcast(ctx context.Context, broadcast RoomBroadcast) error
+}
+
+// BroadcastService sends admin announcements to room subscribers through the delivery channels
+// Как и EventBus, каждый канал получает объявление отдельной задачей в пуле исходящих вызовов
+type BroadcastService struct {
+	roomRepo    RoomStore
+	tasks       TaskQueue
+	minInterval time.Duration
+	channels    []namedBroadcastSubscriber
+	logger      *slog.Logger
+}
+
+type namedBroadcastSubscriber struct {
+	name string
+	BroadcastSubscriber
+}
+
+// NewBroadcastService creates a broadcast service; minInterval limits announcements per room (0 - no limit)
+func NewBroadcastService(roomRepo RoomStore, tasks TaskQueue, minInterval time.Duration, logger *slog.Logger) *BroadcastService {
+	return &BroadcastService{
+		roomRepo:    roomRepo,
+		tasks:       tasks,
+		minInterval: minInterval,
+		logger:      logger,
+	}
+}
+
+// Subscribe adds a delivery channel; channels must be added before announcements are sent
+func (s *BroadcastService) Subscribe(name string, channel BroadcastSubscriber) {
+	s.channels = append(s.channels, namedBroadcastSubscriber{name: name, BroadcastSubscriber: channel})
+}
+
+// Broadcast queues an announcement to the room's subscribers in every channel
+// Комната захватывается атомарно: параллельные и слишком частые объявления отклоняются с ErrBroadcastTooSoon
+func (s *BroadcastService) Broadcast(ctx context.Context, roomID, sentByID uint, message string) (*RoomBroadcast, error) {
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return nil, ErrEmptyBroadcast
+	}
+	if utf8.RuneCountInString(message) > MaxBroadcastLength {
+		return nil, ErrBroadcastTooLong
+	}
+
+	room, err := s.roomRepo.GetByID(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	claimed, err := s.roomRepo.ClaimBroadcast(ctx, room.ID, now, now.Add(-s.minInterval))
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrBroadcastTooSoon
+	}
+
+	broadcast := RoomBroadcast{Room: room, Message: message, SentByID: sentByID, SentAt: now.UTC()}
+	for _, channel := range s.channels {
+		err := s.tasks.Submit(channel.name+":room.broadcast", func(ctx context.Context) {
+			if err := channel.HandleRoomBroadcast(ctx, broadcast); err != nil {
+				s.logger.Error("failed to deliver room broadcast", "channel", channel.name, "room_id", room.ID, "error", err)
+			}
+		})
+		if err != nil {
+			s.logger.Warn("room broadcast dropped", "channel", channel.name, "room_id", room.ID, "error", err)
+		}
+	}
+
+	s.logger.Info("room broadcast sent", "room_id", room.ID, "sent_by", sentByID, "channels", len(s.channels))
+	return &broadcast, nil
+}
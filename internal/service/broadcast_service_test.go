@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// inlineTaskQueue выполняет задачи сразу
+type inlineTaskQueue struct{}
+
+func (inlineTaskQueue) Submit(name string, fn func(ctx context.Context)) error {
+	fn(context.Background())
+	return nil
+}
+
+// fakeBroadcastRoomStore хранит время последнего объявления, как RoomRepository.ClaimBroadcast
+type fakeBroadcastRoomStore struct {
+	*fakeRoomStore
+}
+
+func (f *fakeBroadcastRoomStore) ClaimBroadcast(ctx context.Context, roomID uint, now, notBefore time.Time) (bool, error) {
+	room := f.rooms[roomID]
+	if room.BroadcastAt != nil && !room.BroadcastAt.Before(notBefore) {
+		return false, nil
+	}
+	room.BroadcastAt = &now
+	return true, nil
+}
+
+type recordingBroadcastChannel struct {
+	received []RoomBroadcast
+}
+
+func (c *recordingBroadcastChannel) HandleRoomBroadcast(ctx context.Context, broadcast RoomBroadcast) error {
+	c.received = append(c.received, broadcast)
+	return nil
+}
+
+func TestBroadcastService(t *testing.T) {
+	rooms := &fakeBroadcastRoomStore{&fakeRoomStore{rooms: map[uint]*models.Room{
+		1: {ID: 1, Name: "Big Room", IsActive: true},
+	}}}
+	bot, slack := &recordingBroadcastChannel{}, &recordingBroadcastChannel{}
+	svc := NewBroadcastService(rooms, inlineTaskQueue{}, 10*time.Minute, slog.Default())
+	svc.Subscribe("bot_webhook", bot)
+	svc.Subscribe("slack", slack)
+	ctx := context.Background()
+
+	broadcast, err := svc.Broadcast(ctx, 1, 42, "  Projector replaced  ")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if broadcast.Message != "Projector replaced" || broadcast.SentByID != 42 {
+		t.Errorf("Unexpected broadcast: %+v", broadcast)
+	}
+	if len(bot.received) != 1 || len(slack.received) != 1 || bot.received[0].Room.Name != "Big Room" {
+		t.Errorf("Expected both channels to receive the announcement, got %d and %d", len(bot.received), len(slack.received))
+	}
+
+	// Повторное объявление в пределах интервала отклоняется и никуда не уходит
+	if _, err := svc.Broadcast(ctx, 1, 42, "Room closed Friday"); !errors.Is(err, ErrBroadcastTooSoon) {
+		t.Errorf("Expected ErrBroadcastTooSoon, got: %v", err)
+	}
+	if len(bot.received) != 1 {
+		t.Errorf("Expected rejected announcement not to be delivered, got: %d", len(bot.received))
+	}
+
+	if _, err := svc.Broadcast(ctx, 1, 42, "   "); !errors.Is(err, ErrEmptyBroadcast) {
+		t.Errorf("Expected ErrEmptyBroadcast, got: %v", err)
+	}
+	if _, err := svc.Broadcast(ctx, 1, 42, strings.Repeat("я", MaxBroadcastLength+1)); !errors.Is(err, ErrBroadcastTooLong) {
+		t.Errorf("Expected ErrBroadcastTooLong, got: %v", err)
+	}
+	if _, err := svc.Broadcast(ctx, 7, 42, "Hello"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected ErrRecordNotFound for unknown room, got: %v", err)
+	}
+}
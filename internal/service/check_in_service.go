@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/pkg/apperror"
+	"gorm.io/gorm"
+)
+
+var ErrNoBookingToCheckIn = apperror.Forbidden("no_booking_to_check_in", "you have no booking in this room right now")
+
+// CheckInEarly - за сколько до начала бронирования можно отметиться о приходе
+const CheckInEarly = 15 * time.Minute
+
+// CheckInResult is the booking a user checked in to
+type CheckInResult struct {
+	Booking          *models.Booking `json:"booking"`
+	CheckedInAt      time.Time       `json:"checked_in_at"`
+	AlreadyCheckedIn bool            `json:"already_checked_in"` // Повторное сканирование: отметка уже была
+}
+
+// CheckInService checks participants in to their bookings when they scan the room's QR code
+// QR-код комнаты содержит только ID комнаты: бронирование находится по сканирующему пользователю
+type CheckInService struct {
+	roomRepo    RoomReader
+	checkInRepo CheckInStore
+	logger      *slog.Logger
+}
+
+// NewCheckInService creates a new check-in service
+func NewCheckInService(roomRepo RoomReader, checkInRepo CheckInStore, logger *slog.Logger) *CheckInService {
+	return &CheckInService{
+		roomRepo:    roomRepo,
+		checkInRepo: checkInRepo,
+		logger:      logger,
+	}
+}
+
+// CheckIn checks the user in to their booking of the room that is in progress or starts within CheckInEarly
+// Если таких бронирований несколько, выбирается начинающееся раньше
+func (s *CheckInService) CheckIn(ctx context.Context, roomID, userID uint) (*CheckInResult, error) {
+	if _, err := s.roomRepo.GetByID(ctx, roomID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoomNotFound
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	booking, err := s.checkInRepo.FindCheckInBooking(ctx, roomID, userID, now, now.Add(CheckInEarly))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNoBookingToCheckIn
+		}
+		return nil, err
+	}
+
+	checkIn := &models.BookingCheckIn{BookingID: booking.ID, UserID: userID, CheckedInAt: now}
+	created, err := s.checkInRepo.CheckIn(ctx, checkIn)
+	if err != nil {
+		return nil, err
+	}
+	if created {
+		s.logger.Info("checked in", "booking_id", booking.ID, "room_id", roomID, "user_id", userID)
+	}
+	return &CheckInResult{Booking: booking, CheckedInAt: checkIn.CheckedInAt, AlreadyCheckedIn: !created}, nil
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// fakeCheckInStore выбирает бронирование комнаты по участнику и окну, как CheckInRepository
+type fakeCheckInStore struct {
+	bookings []models.Booking
+	checkIns []models.BookingCheckIn
+}
+
+func (f *fakeCheckInStore) FindCheckInBooking(ctx context.Context, roomID, userID uint, now, opensAt time.Time) (*models.Booking, error) {
+	for i := range f.bookings {
+		b := &f.bookings[i]
+		if b.RoomID == roomID && b.Status == models.BookingStatusConfirmed && !b.StartTime.After(opensAt) && b.EndTime.After(now) &&
+			isBookingParticipant(b, userID) {
+			return b, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeCheckInStore) CheckIn(ctx context.Context, checkIn *models.BookingCheckIn) (bool, error) {
+	for _, existing := range f.checkIns {
+		if existing.BookingID == checkIn.BookingID && existing.UserID == checkIn.UserID {
+			*checkIn = existing
+			return false, nil
+		}
+	}
+	f.checkIns = append(f.checkIns, *checkIn)
+	return true, nil
+}
+
+func TestCheckInService_CheckIn(t *testing.T) {
+	now := time.Now()
+	rooms := &fakeRoomStore{rooms: map[uint]*models.Room{1: {ID: 1, Name: "Room 1", IsActive: true}, 2: {ID: 2, Name: "Room 2", IsActive: true}}}
+	store := &fakeCheckInStore{bookings: []models.Booking{
+		{ID: 1, RoomID: 1, CreatorID: 10, StartTime: now.Add(-time.Hour), EndTime: now.Add(time.Hour), Status: models.BookingStatusConfirmed,
+			Participants: []models.User{{ID: 11}}},
+		// Начнётся через 10 минут - отметиться уже можно
+		{ID: 2, RoomID: 2, CreatorID: 10, StartTime: now.Add(10 * time.Minute), EndTime: now.Add(time.Hour), Status: models.BookingStatusConfirmed},
+		// Начнётся через час - слишком рано
+		{ID: 3, RoomID: 2, CreatorID: 12, StartTime: now.Add(time.Hour), EndTime: now.Add(2 * time.Hour), Status: models.BookingStatusConfirmed},
+	}}
+	svc := NewCheckInService(rooms, store, slog.Default())
+	ctx := context.Background()
+
+	result, err := svc.CheckIn(ctx, 1, 11)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result.Booking.ID != 1 || result.AlreadyCheckedIn {
+		t.Errorf("Expected a new check-in to booking 1, got: %+v", result)
+	}
+	again, err := svc.CheckIn(ctx, 1, 11)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !again.AlreadyCheckedIn || !again.CheckedInAt.Equal(result.CheckedInAt) || len(store.checkIns) != 1 {
+		t.Errorf("Expected the repeated scan to return the first check-in, got: %+v", again)
+	}
+
+	if result, err := svc.CheckIn(ctx, 2, 10); err != nil || result.Booking.ID != 2 {
+		t.Errorf("Expected a check-in shortly before the start, got: %+v (%v)", result, err)
+	}
+
+	tests := []struct {
+		name   string
+		roomID uint
+		userID uint
+		want   error
+	}{
+		{"not a participant", 1, 12, ErrNoBookingToCheckIn},
+		{"too early", 2, 12, ErrNoBookingToCheckIn},
+		{"unknown room", 99, 10, ErrRoomNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.CheckIn(ctx, tt.roomID, tt.userID); !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got: %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/pkg/apperror"
+)
+
+var (
+	ErrInvalidCleaningStatus = apperror.BadRequest("invalid_cleaning_status", "status must be one of: open, done")
+	ErrCleaningTaskDone      = apperror.Conflict("cleaning_task_done", "cleaning task is already done")
+	ErrInvalidResetHours     = apperror.BadRequest("invalid_reset_hours", "reset_after_hours must not be negative")
+)
+
+// CleaningService generates cleaning tasks after bookings of rooms flagged as reset_required
+// and tracks their completion; a room with an open task is reported as needs_cleaning
+type CleaningService struct {
+	txManager    TxRunner
+	roomRepo     RoomStore
+	cleaningRepo CleaningStore
+	roomCache    RoomListInvalidator
+	logger       *slog.Logger
+}
+
+// NewCleaningService creates a new cleaning service
+func NewCleaningService(txManager TxRunner, roomRepo RoomStore, cleaningRepo CleaningStore, roomCache RoomListInvalidator, logger *slog.Logger) *CleaningService {
+	return &CleaningService{
+		txManager:    txManager,
+		roomRepo:     roomRepo,
+		cleaningRepo: cleaningRepo,
+		roomCache:    roomCache,
+		logger:       logger,
+	}
+}
+
+// GenerateTasks creates cleaning tasks for rooms used since the last run; returns the number of tasks created
+// Комната с reset_after_hours = 0 убирается после каждого бронирования, иначе - когда использование
+// с предыдущей уборки достигнет reset_after_hours. Пока задача не выполнена, новая не создаётся
+func (s *CleaningService) GenerateTasks(ctx context.Context) (int, error) {
+	roomIDs, err := s.cleaningRepo.GetResetRoomIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	created := 0
+	for _, roomID := range roomIDs {
+		var task *models.CleaningTask
+		// Строка комнаты блокируется: несколько реплик не учтут одни и те же бронирования дважды
+		err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+			room, err := s.roomRepo.LockByID(ctx, roomID)
+			if err != nil {
+				return err
+			}
+			if !room.ResetRequired {
+				return nil
+			}
+			task, err = s.advanceRoom(ctx, room, now)
+			return err
+		})
+		if err != nil {
+			return created, err
+		}
+		if task != nil {
+			created++
+			s.logger.Info("cleaning task created", "task_id", task.ID, "room_id", roomID, "usage_minutes", task.UsageMinutes)
+		}
+	}
+
+	if created > 0 {
+		s.roomCache.InvalidateRoomList()
+	}
+	return created, nil
+}
+
+// RunScheduled generates cleaning tasks, used by the background job
+func (s *CleaningService) RunScheduled(ctx context.Context) error {
+	_, err := s.GenerateTasks(ctx)
+	return err
+}
+
+// advanceRoom учитывает бронирования комнаты, закончившиеся после курсора, и при необходимости создаёт задачу
+// Курсор впервые ставится на текущий момент: бронирования до включения уборки не учитываются
+func (s *CleaningService) advanceRoom(ctx context.Context, room *models.Room, now time.Time) (*models.CleaningTask, error) {
+	if room.CleaningCursor == nil {
+		room.CleaningCursor = &now
+		return nil, s.cleaningRepo.SaveRoomProgress(ctx, room)
+	}
+
+	spans, err := s.cleaningRepo.GetEndedBookingSpans(ctx, room.ID, *room.CleaningCursor, now)
+	if err != nil || len(spans) == 0 {
+		return nil, err
+	}
+	for _, b := range spans {
+		room.UsageMinutes += int(b.EndTime.Sub(b.StartTime).Minutes())
+	}
+	last := spans[len(spans)-1]
+	room.CleaningCursor = &last.EndTime
+
+	var task *models.CleaningTask
+	if room.ResetAfterHours == 0 || room.UsageMinutes >= room.ResetAfterHours*60 {
+		// Невыполненная задача покрывает и это использование
+		if !room.NeedsCleaning {
+			task = &models.CleaningTask{
+				RoomID:       room.ID,
+				BookingID:    &last.ID,
+				UsageMinutes: room.UsageMinutes,
+				Status:       models.CleaningTaskOpen,
+			}
+			if err := s.cleaningRepo.CreateTask(ctx, task); err != nil {
+				return nil, err
+			}
+			room.NeedsCleaning = true
+		}
+		room.UsageMinutes = 0
+	}
+	return task, s.cleaningRepo.SaveRoomProgress(ctx, room)
+}
+
+// ListTasks gets a page of cleaning tasks; empty status - all tasks, zero roomID - all rooms
+func (s *CleaningService) ListTasks(ctx context.Context, status models.CleaningTaskStatus, roomID uint, limit, offset int) ([]models.CleaningTask, int64, error) {
+	switch status {
+	case "", models.CleaningTaskOpen, models.CleaningTaskDone:
+	default:
+		return nil, 0, ErrInvalidCleaningStatus
+	}
+	limit, offset = pageBounds(limit, offset, DefaultListPageSize, MaxListPageSize)
+	return s.cleaningRepo.ListTasks(ctx, status, roomID, limit, offset)
+}
+
+// CompleteTask marks a task as done; the room is ready again once it has no open tasks
+func (s *CleaningService) CompleteTask(ctx context.Context, id, userID uint) (*models.CleaningTask, error) {
+	task, err := s.cleaningRepo.GetTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ready := false
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		room, err := s.roomRepo.LockByID(ctx, task.RoomID)
+		if err != nil {
+			return err
+		}
+		completed, err := s.cleaningRepo.CompleteTask(ctx, id, userID, time.Now())
+		if err != nil {
+			return err
+		}
+		if !completed {
+			return ErrCleaningTaskDone
+		}
+		open, err := s.cleaningRepo.CountOpenTasks(ctx, room.ID)
+		if err != nil || open > 0 {
+			return err
+		}
+		room.NeedsCleaning = false
+		ready = true
+		return s.cleaningRepo.SaveRoomProgress(ctx, room)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if ready {
+		s.roomCache.InvalidateRoomList()
+	}
+	s.logger.Info("cleaning task completed", "task_id", id, "room_id", task.RoomID, "completed_by", userID)
+	return s.cleaningRepo.GetTask(ctx, id)
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/models"
+)
+
+// fakeCleaningStore хранит задачи уборки и прогресс комнат поверх fakeRoomStore
+type fakeCleaningStore struct {
+	CleaningStore
+	rooms    *fakeRoomStore
+	bookings []models.Booking
+	tasks    []models.CleaningTask
+}
+
+func (f *fakeCleaningStore) GetResetRoomIDs(ctx context.Context) ([]uint, error) {
+	var ids []uint
+	for id, room := range f.rooms.rooms {
+		if room.ResetRequired {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (f *fakeCleaningStore) GetEndedBookingSpans(ctx context.Context, roomID uint, after, until time.Time) ([]models.Booking, error) {
+	var spans []models.Booking
+	for _, b := range f.bookings {
+		if b.RoomID == roomID && b.EndTime.After(after) && !b.EndTime.After(until) {
+			spans = append(spans, b)
+		}
+	}
+	return spans, nil
+}
+
+func (f *fakeCleaningStore) SaveRoomProgress(ctx context.Context, room *models.Room) error {
+	stored := f.rooms.rooms[room.ID]
+	stored.CleaningCursor, stored.UsageMinutes, stored.NeedsCleaning = room.CleaningCursor, room.UsageMinutes, room.NeedsCleaning
+	return nil
+}
+
+func (f *fakeCleaningStore) CreateTask(ctx context.Context, task *models.CleaningTask) error {
+	task.ID = uint(len(f.tasks) + 1)
+	f.tasks = append(f.tasks, *task)
+	return nil
+}
+
+func (f *fakeCleaningStore) GetTask(ctx context.Context, id uint) (*models.CleaningTask, error) {
+	if id == 0 || int(id) > len(f.tasks) {
+		return nil, errors.New("not found")
+	}
+	task := f.tasks[id-1]
+	return &task, nil
+}
+
+func (f *fakeCleaningStore) CompleteTask(ctx context.Context, id, userID uint, now time.Time) (bool, error) {
+	task := &f.tasks[id-1]
+	if task.Status != models.CleaningTaskOpen {
+		return false, nil
+	}
+	task.Status, task.CompletedByID, task.CompletedAt = models.CleaningTaskDone, &userID, &now
+	return true, nil
+}
+
+func (f *fakeCleaningStore) CountOpenTasks(ctx context.Context, roomID uint) (int64, error) {
+	var count int64
+	for _, task := range f.tasks {
+		if task.RoomID == roomID && task.Status == models.CleaningTaskOpen {
+			count++
+		}
+	}
+	return count, nil
+}
+
+type countingInvalidator struct{ calls int }
+
+func (c *countingInvalidator) InvalidateRoomList() { c.calls++ }
+
+func TestCleaningService_GenerateAndComplete(t *testing.T) {
+	now := time.Now()
+	cursor := now.Add(-4 * time.Hour)
+	rooms := &fakeRoomStore{rooms: map[uint]*models.Room{
+		// Уборка после каждого бронирования
+		1: {ID: 1, Name: "Kitchen", ResetRequired: true, CleaningCursor: &cursor},
+		// Уборка после 3 часов использования
+		2: {ID: 2, Name: "Studio", ResetRequired: true, ResetAfterHours: 3, CleaningCursor: &cursor},
+		// Уборка только что включена - прошлые бронирования не учитываются
+		3: {ID: 3, Name: "Lab", ResetRequired: true},
+		4: {ID: 4, Name: "Hall"},
+	}}
+	store := &fakeCleaningStore{rooms: rooms, bookings: []models.Booking{
+		{ID: 1, RoomID: 1, StartTime: now.Add(-3 * time.Hour), EndTime: now.Add(-2 * time.Hour)},
+		{ID: 2, RoomID: 1, StartTime: now.Add(-90 * time.Minute), EndTime: now.Add(-time.Hour)},
+		{ID: 3, RoomID: 2, StartTime: now.Add(-3 * time.Hour), EndTime: now.Add(-time.Hour)},
+		{ID: 4, RoomID: 3, StartTime: now.Add(-3 * time.Hour), EndTime: now.Add(-time.Hour)},
+		{ID: 5, RoomID: 4, StartTime: now.Add(-3 * time.Hour), EndTime: now.Add(-time.Hour)},
+	}}
+	invalidator := &countingInvalidator{}
+	svc := NewCleaningService(fakeTx{}, rooms, store, invalidator, slog.Default())
+	ctx := context.Background()
+
+	created, err := svc.GenerateTasks(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if created != 1 || store.tasks[0].RoomID != 1 || *store.tasks[0].BookingID != 2 || store.tasks[0].UsageMinutes != 90 {
+		t.Fatalf("Expected one task for the kitchen after its last booking, got: %d %+v", created, store.tasks)
+	}
+	if !rooms.rooms[1].NeedsCleaning || invalidator.calls != 1 {
+		t.Error("Expected the kitchen to need cleaning and the room list to be invalidated")
+	}
+	if rooms.rooms[2].UsageMinutes != 120 || rooms.rooms[2].NeedsCleaning {
+		t.Errorf("Expected 2 hours of studio usage without a task, got: %+v", rooms.rooms[2])
+	}
+	if rooms.rooms[3].CleaningCursor == nil || rooms.rooms[3].UsageMinutes != 0 {
+		t.Errorf("Expected the lab cursor to start now, got: %+v", rooms.rooms[3])
+	}
+
+	// Ещё час в студии - порог пройден; кухня ещё не убрана - вторая задача не создаётся
+	store.bookings = append(store.bookings,
+		models.Booking{ID: 6, RoomID: 2, StartTime: now.Add(-61 * time.Minute), EndTime: now.Add(-time.Minute)},
+		models.Booking{ID: 7, RoomID: 1, StartTime: now.Add(-50 * time.Minute), EndTime: now.Add(-time.Minute)},
+	)
+	if created, err = svc.GenerateTasks(ctx); err != nil || created != 1 || store.tasks[1].RoomID != 2 {
+		t.Fatalf("Expected one task for the studio, got: %d (%v) %+v", created, err, store.tasks)
+	}
+	if rooms.rooms[2].UsageMinutes != 0 {
+		t.Errorf("Expected studio usage to reset, got: %d", rooms.rooms[2].UsageMinutes)
+	}
+
+	task, err := svc.CompleteTask(ctx, 1, 12)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if task.Status != models.CleaningTaskDone || rooms.rooms[1].NeedsCleaning {
+		t.Errorf("Expected the kitchen to be ready, got task %+v", task)
+	}
+	if _, err := svc.CompleteTask(ctx, 1, 12); !errors.Is(err, ErrCleaningTaskDone) {
+		t.Errorf("Expected ErrCleaningTaskDone, got: %v", err)
+	}
+	if _, _, err := svc.ListTasks(ctx, "pending", 0, 0, 0); !errors.Is(err, ErrInvalidCleaningStatus) {
+		t.Errorf("Expected ErrInvalidCleaningStatus, got: %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DigestBooking is a booking in the daily schedule posted by the bot
+type DigestBooking struct {
+	ID                uint      `json:"id"`
+	Title             string    `json:"title"`
+	From              string    `json:"from"` // ЧЧ:ММ в часовом поясе дня; начавшееся накануне - 00:00
+	To                string    `json:"to"`   // Закончится на следующий день - 24:00
+	StartTime         time.Time `json:"start_time"`
+	EndTime           time.Time `json:"end_time"`
+	CreatorName       string    `json:"creator_name"` // Имя и первая буква фамилии
+	CreatorTelegramID int64     `json:"creator_telegram_id,omitempty"`
+	CreatorUsername   string    `json:"creator_username,omitempty"`
+	ParticipantCount  int       `json:"participant_count"`
+}
+
+// DigestRoom is the schedule of one room for the day
+type DigestRoom struct {
+	ID       uint            `json:"id"`
+	Name     string          `json:"name"`
+	Bookings []DigestBooking `json:"bookings"`
+}
+
+// DailyDigest is the schedule of all rooms for a day
+type DailyDigest struct {
+	Date      string       `json:"date"`       // YYYY-MM-DD
+	UTCOffset string       `json:"utc_offset"` // Смещение, в котором указаны from и to, например +03:00
+	Total     int          `json:"total"`      // Бронирований за день
+	Rooms     []DigestRoom `json:"rooms"`      // Все активные комнаты по имени, свободные - с пустым списком
+}
+
+// GetDailyDigest gets the bookings of all rooms for the day of date, grouped by room
+// День считается в часовом поясе date
+func (s *BookingService) GetDailyDigest(ctx context.Context, date time.Time) (*DailyDigest, error) {
+	start, end := dayBounds(date, date.Location())
+
+	rooms, err := s.roomRepo.GetAll(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	summaries, err := s.bookingRepo.GetCalendarSummaries(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := &DailyDigest{
+		Date:      start.Format("2006-01-02"),
+		UTCOffset: start.Format("-07:00"),
+		Rooms:     make([]DigestRoom, 0, len(rooms)),
+	}
+	index := make(map[uint]int, len(rooms))
+	for _, room := range rooms {
+		index[room.ID] = len(digest.Rooms)
+		digest.Rooms = append(digest.Rooms, DigestRoom{ID: room.ID, Name: room.Name, Bookings: []DigestBooking{}})
+	}
+
+	for _, summary := range summaries {
+		i, ok := index[summary.RoomID]
+		if !ok {
+			continue // комната отключена
+		}
+		from, to := "00:00", "24:00"
+		if !summary.StartTime.Before(start) {
+			from = summary.StartTime.In(start.Location()).Format("15:04")
+		}
+		if summary.EndTime.Before(end) {
+			to = summary.EndTime.In(start.Location()).Format("15:04")
+		}
+		digest.Rooms[i].Bookings = append(digest.Rooms[i].Bookings, DigestBooking{
+			ID:                summary.ID,
+			Title:             summary.Title,
+			From:              from,
+			To:                to,
+			StartTime:         summary.StartTime,
+			EndTime:           summary.EndTime,
+			CreatorName:       shortName(summary.CreatorFirstName, summary.CreatorLastName),
+			CreatorTelegramID: summary.CreatorTelegramID,
+			CreatorUsername:   summary.CreatorUsername,
+			ParticipantCount:  summary.ParticipantCount,
+		})
+		digest.Total++
+	}
+	return digest, nil
+}
+
+// shortName сокращает имя для сообщений бота: "Анна И."
+func shortName(firstName, lastName string) string {
+	lastName = strings.TrimSpace(lastName)
+	if lastName == "" {
+		return strings.TrimSpace(firstName)
+	}
+	initial, _ := utf8.DecodeRuneInString(lastName)
+	return strings.TrimSpace(firstName + " " + string(initial) + ".")
+}
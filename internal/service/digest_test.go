@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/models"
+)
+
+func TestGetDailyDigest(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	day := time.Date(2025, 5, 12, 0, 0, 0, 0, moscow)
+	rooms := &fakeRoomStateRoomStore{&fakeRoomStore{rooms: map[uint]*models.Room{
+		1: {ID: 1, Name: "Big Room", IsActive: true},
+		2: {ID: 2, Name: "Small Room", IsActive: true},
+		3: {ID: 3, Name: "Closed", IsActive: false},
+	}}}
+	bookings := &fakeRoomStateBookingStore{newFakeBookingStore(
+		models.Booking{ID: 1, RoomID: 1, Title: "Standup", StartTime: day.Add(10 * time.Hour), EndTime: day.Add(10*time.Hour + 15*time.Minute), Status: models.BookingStatusConfirmed},
+		models.Booking{ID: 2, RoomID: 1, Title: "Night", StartTime: day.Add(-2 * time.Hour), EndTime: day.Add(2 * time.Hour), Status: models.BookingStatusConfirmed},
+		models.Booking{ID: 3, RoomID: 3, Title: "Hidden", StartTime: day.Add(12 * time.Hour), EndTime: day.Add(13 * time.Hour), Status: models.BookingStatusConfirmed},
+		models.Booking{ID: 4, RoomID: 2, Title: "Tomorrow", StartTime: day.AddDate(0, 0, 1), EndTime: day.AddDate(0, 0, 1).Add(time.Hour), Status: models.BookingStatusConfirmed},
+	)}
+	svc := NewBookingService(fakeTx{}, bookings, rooms, &fakeUserStore{}, nil, nil, nil, slog.Default())
+
+	digest, err := svc.GetDailyDigest(context.Background(), day.Add(15*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if digest.Date != "2025-05-12" || digest.UTCOffset != "+03:00" || digest.Total != 2 || len(digest.Rooms) != 2 {
+		t.Fatalf("Expected two bookings in two active rooms, got: %+v", digest)
+	}
+	big := digest.Rooms[0].Bookings
+	if len(big) != 2 || big[0].From != "00:00" || big[0].To != "02:00" || big[1].From != "10:00" || big[1].To != "10:15" {
+		t.Errorf("Expected times clipped to the day, got: %+v", big)
+	}
+	if digest.Rooms[1].Bookings == nil || len(digest.Rooms[1].Bookings) != 0 {
+		t.Errorf("Expected a free room with an empty list, got: %+v", digest.Rooms[1])
+	}
+
+	if got := shortName("Анна", "Иванова"); got != "Анна И." {
+		t.Errorf("Expected short name, got: %q", got)
+	}
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/space/backend/internal/access"
+	"github.com/space/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// doorIDAttribute - ключ в attributes комнаты с идентификатором двери у драйвера
+const doorIDAttribute = "door_id"
+
+// DoorAccessService grants door access to the creator and participants for the booking window
+// Доступ выдаётся за lead до начала и отзывается по окончании или при отмене;
+// комнаты без attributes.door_id пропускаются
+type DoorAccessService struct {
+	driver      access.Driver
+	grantRepo   DoorAccessStore
+	bookingRepo BookingStore
+	userRepo    UserStore
+	lead        time.Duration
+	logger      *slog.Logger
+}
+
+// NewDoorAccessService creates a new door access service
+func NewDoorAccessService(driver access.Driver, grantRepo DoorAccessStore, bookingRepo BookingStore, userRepo UserStore, lead time.Duration, logger *slog.Logger) *DoorAccessService {
+	return &DoorAccessService{
+		driver:      driver,
+		grantRepo:   grantRepo,
+		bookingRepo: bookingRepo,
+		userRepo:    userRepo,
+		lead:        lead,
+		logger:      logger,
+	}
+}
+
+// RunScheduled grants access for bookings starting within lead and revokes expired grants
+// Ошибка одного бронирования не мешает остальным; повтор - на следующем запуске
+func (s *DoorAccessService) RunScheduled(ctx context.Context) error {
+	now := time.Now()
+
+	bookings, err := s.grantRepo.GetBookingsToGrant(ctx, now, now.Add(s.lead))
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i := range bookings {
+		if err := s.grant(ctx, &bookings[i]); err != nil {
+			errs = append(errs, fmt.Errorf("booking %d: %w", bookings[i].ID, err))
+		}
+	}
+
+	expired, err := s.grantRepo.GetExpired(ctx, now)
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	for i := range expired {
+		if err := s.revoke(ctx, &expired[i]); err != nil {
+			errs = append(errs, fmt.Errorf("grant %d: %w", expired[i].ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// HandleBookingEvent revokes access of a cancelled or released booking
+func (s *DoorAccessService) HandleBookingEvent(ctx context.Context, event BookingEvent) error {
+	if event.Type != EventBookingCancelled && event.Type != EventBookingReleased {
+		return nil
+	}
+
+	grant, err := s.grantRepo.GetByBookingID(ctx, event.Booking.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if grant.GrantedAt == nil || grant.RevokedAt != nil {
+		return nil
+	}
+	return s.revoke(ctx, grant)
+}
+
+// GetAccess returns the active grant of a booking to its creator, participants or an admin
+func (s *DoorAccessService) GetAccess(ctx context.Context, bookingID, userID uint) (*models.DoorAccessGrant, error) {
+	booking, err := s.bookingRepo.GetByID(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isBookingMember(booking, userID) {
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !user.IsAdmin() {
+			return nil, ErrNotAuthorized
+		}
+	}
+
+	grant, err := s.grantRepo.GetByBookingID(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	// Ещё не выданный или уже отозванный доступ показывать нечего
+	if grant.GrantedAt == nil || grant.RevokedAt != nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return grant, nil
+}
+
+// grant выдаёт доступ по бронированию; строка захватывается до вызова драйвера
+func (s *DoorAccessService) grant(ctx context.Context, booking *models.Booking) error {
+	doorID := roomDoorID(&booking.Room)
+	if doorID == "" {
+		return nil
+	}
+
+	record := &models.DoorAccessGrant{
+		BookingID:  booking.ID,
+		DoorID:     doorID,
+		ValidFrom:  booking.StartTime.Add(-s.lead),
+		ValidUntil: booking.EndTime,
+	}
+	claimed, err := s.grantRepo.Claim(ctx, record)
+	if err != nil || !claimed {
+		return err
+	}
+
+	credential, err := s.driver.Grant(ctx, accessGrant(booking, record))
+	if err != nil {
+		// Снимаем захват, чтобы следующий запуск попробовал снова
+		if delErr := s.grantRepo.Delete(ctx, record.ID); delErr != nil {
+			return errors.Join(err, delErr)
+		}
+		return err
+	}
+
+	now := time.Now()
+	record.ExternalID = credential.ExternalID
+	record.PIN = credential.PIN
+	record.GrantedAt = &now
+	if err := s.grantRepo.MarkGranted(ctx, record); err != nil {
+		return err
+	}
+
+	s.logger.Info("door access granted", "booking_id", booking.ID, "door_id", doorID, "valid_until", record.ValidUntil)
+	return nil
+}
+
+// revoke отзывает доступ один раз, даже если задача работает на нескольких репликах
+func (s *DoorAccessService) revoke(ctx context.Context, record *models.DoorAccessGrant) error {
+	claimed, err := s.grantRepo.MarkRevoked(ctx, record.ID, time.Now())
+	if err != nil || !claimed {
+		return err
+	}
+
+	grant := access.Grant{
+		BookingID:  record.BookingID,
+		DoorID:     record.DoorID,
+		ValidFrom:  record.ValidFrom,
+		ValidUntil: record.ValidUntil,
+		ExternalID: record.ExternalID,
+	}
+	if err := s.driver.Revoke(ctx, grant); err != nil {
+		if clearErr := s.grantRepo.ClearRevoked(ctx, record.ID); clearErr != nil {
+			return errors.Join(err, clearErr)
+		}
+		return err
+	}
+
+	s.logger.Info("door access revoked", "booking_id", record.BookingID, "door_id", record.DoorID)
+	return nil
+}
+
+// accessGrant собирает запрос к драйверу: создатель и участники бронирования
+func accessGrant(booking *models.Booking, record *models.DoorAccessGrant) access.Grant {
+	users := make([]access.User, 0, len(booking.Participants)+1)
+	users = append(users, accessUser(&booking.Creator))
+	for i := range booking.Participants {
+		if booking.Participants[i].ID == booking.CreatorID {
+			continue
+		}
+		users = append(users, accessUser(&booking.Participants[i]))
+	}
+
+	return access.Grant{
+		BookingID:  booking.ID,
+		RoomID:     booking.RoomID,
+		DoorID:     record.DoorID,
+		ValidFrom:  record.ValidFrom,
+		ValidUntil: record.ValidUntil,
+		Users:      users,
+	}
+}
+
+func accessUser(u *models.User) access.User {
+	return access.User{
+		ID:         u.ID,
+		TelegramID: u.TelegramID,
+		Username:   u.Username,
+		Name:       strings.TrimSpace(u.FirstName + " " + u.LastName),
+		Phone:      u.PhoneNumber,
+	}
+}
+
+// roomDoorID возвращает attributes.door_id комнаты или пустую строку
+func roomDoorID(room *models.Room) string {
+	if len(room.Attributes) == 0 {
+		return ""
+	}
+	var attrs map[string]interface{}
+	if err := json.Unmarshal(room.Attributes, &attrs); err != nil {
+		return ""
+	}
+	switch v := attrs[doorIDAttribute].(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return fmt.Sprintf("%.0f", v)
+	default:
+		return ""
+	}
+}
+
+// isBookingMember проверяет, является ли пользователь создателем или участником бронирования
+func isBookingMember(booking *models.Booking, userID uint) bool {
+	if booking.CreatorID == userID {
+		return true
+	}
+	for _, p := range booking.Participants {
+		if p.ID == userID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/access"
+	"github.com/space/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type fakeDoorAccessStore struct {
+	DoorAccessStore
+	bookings []models.Booking
+	grants   map[uint]*models.DoorAccessGrant // по booking_id
+}
+
+func (f *fakeDoorAccessStore) GetBookingsToGrant(ctx context.Context, from, to time.Time) ([]models.Booking, error) {
+	var due []models.Booking
+	for _, b := range f.bookings {
+		if _, ok := f.grants[b.ID]; !ok {
+			due = append(due, b)
+		}
+	}
+	return due, nil
+}
+
+func (f *fakeDoorAccessStore) Claim(ctx context.Context, grant *models.DoorAccessGrant) (bool, error) {
+	if _, ok := f.grants[grant.BookingID]; ok {
+		return false, nil
+	}
+	grant.ID = grant.BookingID
+	f.grants[grant.BookingID] = grant
+	return true, nil
+}
+
+func (f *fakeDoorAccessStore) MarkGranted(ctx context.Context, grant *models.DoorAccessGrant) error {
+	f.grants[grant.BookingID] = grant
+	return nil
+}
+
+func (f *fakeDoorAccessStore) Delete(ctx context.Context, id uint) error {
+	delete(f.grants, id)
+	return nil
+}
+
+func (f *fakeDoorAccessStore) GetByBookingID(ctx context.Context, bookingID uint) (*models.DoorAccessGrant, error) {
+	grant, ok := f.grants[bookingID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return grant, nil
+}
+
+func (f *fakeDoorAccessStore) GetExpired(ctx context.Context, now time.Time) ([]models.DoorAccessGrant, error) {
+	return nil, nil
+}
+
+func (f *fakeDoorAccessStore) MarkRevoked(ctx context.Context, id uint, at time.Time) (bool, error) {
+	grant := f.grants[id]
+	if grant.RevokedAt != nil {
+		return false, nil
+	}
+	grant.RevokedAt = &at
+	return true, nil
+}
+
+func (f *fakeDoorAccessStore) ClearRevoked(ctx context.Context, id uint) error {
+	f.grants[id].RevokedAt = nil
+	return nil
+}
+
+type fakeDoorDriver struct {
+	granted []access.Grant
+	revoked []access.Grant
+	err     error
+}
+
+func (d *fakeDoorDriver) Grant(ctx context.Context, grant access.Grant) (*access.Credential, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	d.granted = append(d.granted, grant)
+	return &access.Credential{ExternalID: "ext-1", PIN: "4821"}, nil
+}
+
+func (d *fakeDoorDriver) Revoke(ctx context.Context, grant access.Grant) error {
+	if d.err != nil {
+		return d.err
+	}
+	d.revoked = append(d.revoked, grant)
+	return nil
+}
+
+func TestDoorAccess_GrantAndRevokeOnCancel(t *testing.T) {
+	ctx := context.Background()
+	start := time.Now().Add(3 * time.Minute)
+	booking := models.Booking{
+		ID: 5, RoomID: 1, CreatorID: 10, StartTime: start, EndTime: start.Add(time.Hour),
+		Room:         models.Room{ID: 1, Attributes: []byte(`{"door_id":"front"}`)},
+		Creator:      models.User{ID: 10, FirstName: "Anna"},
+		Participants: []models.User{{ID: 10}, {ID: 11, Username: "bob"}},
+	}
+	noDoor := models.Booking{ID: 6, RoomID: 2, CreatorID: 10, StartTime: start, EndTime: start.Add(time.Hour)}
+
+	store := &fakeDoorAccessStore{bookings: []models.Booking{booking, noDoor}, grants: map[uint]*models.DoorAccessGrant{}}
+	driver := &fakeDoorDriver{err: errors.New("lock offline")}
+	users := &fakeUserStore{users: map[uint]*models.User{12: {ID: 12, Role: models.RoleUser}}}
+	svc := NewDoorAccessService(driver, store, newFakeBookingStore(booking), users, 5*time.Minute, slog.Default())
+
+	// Ошибка драйвера снимает захват - следующий запуск повторит выдачу
+	if err := svc.RunScheduled(ctx); err == nil {
+		t.Fatal("Expected driver error")
+	}
+	if len(store.grants) != 0 {
+		t.Fatalf("Expected claim to be released, got: %v", store.grants)
+	}
+
+	driver.err = nil
+	if err := svc.RunScheduled(ctx); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(driver.granted) != 1 {
+		t.Fatalf("Expected one grant (room without door skipped), got: %d", len(driver.granted))
+	}
+	if g := driver.granted[0]; g.DoorID != "front" || len(g.Users) != 2 || !g.ValidFrom.Equal(start.Add(-5*time.Minute)) {
+		t.Errorf("Unexpected grant: %+v", g)
+	}
+
+	grant, err := svc.GetAccess(ctx, 5, 11)
+	if err != nil || grant.PIN != "4821" {
+		t.Fatalf("Expected participant to get the PIN, got: %v, %v", grant, err)
+	}
+	if _, err := svc.GetAccess(ctx, 5, 12); !errors.Is(err, ErrNotAuthorized) {
+		t.Errorf("Expected ErrNotAuthorized for outsider, got: %v", err)
+	}
+
+	cancelled := booking
+	if err := svc.HandleBookingEvent(ctx, BookingEvent{Type: EventBookingCancelled, Booking: &cancelled}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(driver.revoked) != 1 || driver.revoked[0].ExternalID != "ext-1" {
+		t.Errorf("Expected grant to be revoked with its external ID, got: %+v", driver.revoked)
+	}
+	if _, err := svc.GetAccess(ctx, 5, 10); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected revoked access to be hidden, got: %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"html"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/pkg/apperror"
+	"gorm.io/gorm"
+)
+
+// ErrEquipmentNotFound is returned for unknown equipment
+var ErrEquipmentNotFound = apperror.NotFound("equipment_not_found", "equipment not found")
+
+// telegramMessageLimit - максимальная длина текста сообщения Telegram в символах
+const telegramMessageLimit = 4096
+
+// EquipmentSummary is a piece of room equipment in the bot's answer
+type EquipmentSummary struct {
+	ID               uint   `json:"id"`
+	Name             string `json:"name"`
+	Description      string `json:"description,omitempty"`
+	IsAvailable      bool   `json:"is_available"`
+	InstructionCount int    `json:"instruction_count"`
+}
+
+// InstructionMessage is an instruction formatted for a Telegram message
+type InstructionMessage struct {
+	ID    uint                   `json:"id"`
+	Title string                 `json:"title"`
+	Type  models.InstructionType `json:"type"`
+	URL   string                 `json:"url,omitempty"`
+	Text  string                 `json:"text"` // HTML для parse_mode=HTML
+}
+
+// EquipmentGuide is equipment with its instructions, ready to be sent by the bot
+type EquipmentGuide struct {
+	EquipmentID  uint                 `json:"equipment_id"`
+	Name         string               `json:"name"`
+	RoomID       uint                 `json:"room_id"`
+	RoomName     string               `json:"room_name"`
+	Instructions []InstructionMessage `json:"instructions"`
+	Text         string               `json:"text"` // Одно сообщение целиком (HTML, не длиннее 4096 символов)
+}
+
+// GetEquipmentSummaries gets the equipment of a room with the number of instructions of each
+func (s *RoomService) GetEquipmentSummaries(ctx context.Context, roomID uint) ([]EquipmentSummary, error) {
+	if _, err := s.roomRepo.GetByID(ctx, roomID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoomNotFound
+		}
+		return nil, err
+	}
+	equipment, err := s.equipmentRepo.GetByRoomID(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]EquipmentSummary, len(equipment))
+	for i, e := range equipment {
+		result[i] = EquipmentSummary{
+			ID:               e.ID,
+			Name:             e.Name,
+			Description:      e.Description,
+			IsAvailable:      e.IsAvailable,
+			InstructionCount: len(e.Instructions),
+		}
+	}
+	return result, nil
+}
+
+// GetEquipmentGuide gets the instructions of equipment formatted for Telegram (parse_mode=HTML)
+// Текстовые инструкции идут содержимым, ссылки - ссылкой; у документов и видео без URL - только название
+func (s *RoomService) GetEquipmentGuide(ctx context.Context, equipmentID uint) (*EquipmentGuide, error) {
+	equipment, err := s.equipmentRepo.GetByID(ctx, equipmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEquipmentNotFound
+		}
+		return nil, err
+	}
+
+	guide := &EquipmentGuide{
+		EquipmentID:  equipment.ID,
+		Name:         equipment.Name,
+		RoomID:       equipment.RoomID,
+		RoomName:     equipment.Room.Name,
+		Instructions: make([]InstructionMessage, len(equipment.Instructions)),
+	}
+	parts := []string{"<b>" + html.EscapeString(equipment.Name) + "</b> · " + html.EscapeString(equipment.Room.Name)}
+	for i, instruction := range equipment.Instructions {
+		text := instructionHTML(&instruction)
+		guide.Instructions[i] = InstructionMessage{
+			ID:    instruction.ID,
+			Title: instruction.Title,
+			Type:  instruction.Type,
+			URL:   instruction.URL,
+			Text:  text,
+		}
+		parts = append(parts, text)
+	}
+	guide.Text = truncateMessage(strings.Join(parts, "\n\n"), telegramMessageLimit)
+	return guide, nil
+}
+
+// instructionHTML форматирует инструкцию: заголовок, описание, текст и ссылка
+func instructionHTML(instruction *models.Instruction) string {
+	lines := []string{"<b>" + html.EscapeString(instruction.Title) + "</b>"}
+	if instruction.Description != "" {
+		lines = append(lines, "<i>"+html.EscapeString(instruction.Description)+"</i>")
+	}
+	if instruction.Type == models.InstructionTypeText && instruction.Content != "" {
+		lines = append(lines, html.EscapeString(instruction.Content))
+	}
+	if instruction.URL != "" {
+		lines = append(lines, `<a href="`+html.EscapeString(instruction.URL)+`">`+html.EscapeString(instruction.URL)+"</a>")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// truncateMessage обрезает текст до limit символов по границе абзаца
+// Разметка целых абзацев не разрывается; абзац длиннее лимита отбрасывается целиком
+func truncateMessage(text string, limit int) string {
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	const ellipsis = "\n\n…"
+	paragraphs := strings.Split(text, "\n\n")
+	var b strings.Builder
+	for i, p := range paragraphs {
+		sep := ""
+		if i > 0 {
+			sep = "\n\n"
+		}
+		if utf8.RuneCountInString(b.String())+utf8.RuneCountInString(sep+p)+utf8.RuneCountInString(ellipsis) > limit {
+			break
+		}
+		b.WriteString(sep + p)
+	}
+	return b.String() + ellipsis
+}
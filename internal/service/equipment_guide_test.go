@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/space/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type fakeEquipmentStore struct {
+	EquipmentStore
+	equipment map[uint]*models.Equipment
+}
+
+func (f *fakeEquipmentStore) GetByID(ctx context.Context, id uint) (*models.Equipment, error) {
+	e, ok := f.equipment[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return e, nil
+}
+
+func (f *fakeEquipmentStore) GetByRoomID(ctx context.Context, roomID uint) ([]models.Equipment, error) {
+	var result []models.Equipment
+	for _, e := range f.equipment {
+		if e.RoomID == roomID {
+			result = append(result, *e)
+		}
+	}
+	return result, nil
+}
+
+func TestRoomService_GetEquipmentGuide(t *testing.T) {
+	room := models.Room{ID: 2, Name: "Room 2", IsActive: true}
+	projector := &models.Equipment{ID: 7, RoomID: 2, Room: room, Name: "Projector <HD>", IsAvailable: true,
+		Instructions: []models.Instruction{
+			{ID: 1, Title: "Turn on", Type: models.InstructionTypeText, Content: "Press the button & wait"},
+			{ID: 2, Title: "Manual", Type: models.InstructionTypeLink, URL: "https://example.com/manual?a=1&b=2"},
+			{ID: 3, Title: "Video", Type: models.InstructionTypeVideo, FilePath: "uploads/video.mp4"},
+		}}
+	svc := NewRoomService(&fakeRoomStore{rooms: map[uint]*models.Room{2: &room}},
+		&fakeEquipmentStore{equipment: map[uint]*models.Equipment{7: projector}}, nil, 0)
+	ctx := context.Background()
+
+	summaries, err := svc.GetEquipmentSummaries(ctx, 2)
+	if err != nil || len(summaries) != 1 || summaries[0].InstructionCount != 3 {
+		t.Fatalf("Expected one projector with 3 instructions, got %+v, %v", summaries, err)
+	}
+	if _, err := svc.GetEquipmentSummaries(ctx, 99); !errors.Is(err, ErrRoomNotFound) {
+		t.Errorf("Expected ErrRoomNotFound, got: %v", err)
+	}
+
+	guide, err := svc.GetEquipmentGuide(ctx, 7)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if guide.RoomName != "Room 2" || len(guide.Instructions) != 3 {
+		t.Fatalf("Unexpected guide: %+v", guide)
+	}
+	for _, want := range []string{"<b>Projector &lt;HD&gt;</b>", "Press the button &amp; wait", `<a href="https://example.com/manual?a=1&amp;b=2">`} {
+		if !strings.Contains(guide.Text, want) {
+			t.Errorf("Expected text to contain %q, got:\n%s", want, guide.Text)
+		}
+	}
+	// Файл без ссылки не отдаётся - только название
+	if guide.Instructions[2].Text != "<b>Video</b>" {
+		t.Errorf("Expected title only for a file instruction, got: %q", guide.Instructions[2].Text)
+	}
+
+	if _, err := svc.GetEquipmentGuide(ctx, 99); !errors.Is(err, ErrEquipmentNotFound) {
+		t.Errorf("Expected ErrEquipmentNotFound, got: %v", err)
+	}
+}
+
+func TestTruncateMessage(t *testing.T) {
+	text := strings.Repeat("абв", 10) + "\n\n" + strings.Repeat("где", 10)
+	got := truncateMessage(text, 40)
+	if utf8.RuneCountInString(got) > 40 || !strings.HasPrefix(got, strings.Repeat("абв", 10)) || strings.Contains(got, "где") {
+		t.Errorf("Expected the second paragraph to be dropped, got: %q", got)
+	}
+	if truncateMessage("short", 40) != "short" {
+		t.Error("Expected short text to be kept")
+	}
+}
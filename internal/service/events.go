@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/space/backend/internal/models"
+)
+
+// BookingEventType определяет тип события бронирования
+type BookingEventType string
+
+const (
+	EventBookingCreated   BookingEventType = "booking.created"
+	EventBookingCancelled BookingEventType = "booking.cancelled"
+	EventBookingReminder  BookingEventType = "booking.reminder" // Скоро начало (BOOKING_REMINDER_LEAD)
+	EventBookingReleased  BookingEventType = "booking.released" // Администратор досрочно освободил комнату
+	EventBookingUpdated   BookingEventType = "booking.updated"  // Изменены время или описание; список изменений - в Changes
+
+	// Пользователь из очереди получил место в мероприятии (User). Личное уведомление:
+	// доставляется только ботом, каналы комнаты на него не подписываются
+	EventBookingWaitlistPromoted BookingEventType = "booking.waitlist_promoted"
+)
+
+// ValidBookingEvents - все события, на которые могут подписаться каналы доставки
+var ValidBookingEvents = []BookingEventType{
+	EventBookingCreated,
+	EventBookingCancelled,
+	EventBookingReminder,
+	EventBookingReleased,
+	EventBookingUpdated,
+}
+
+// BookingEvent describes something that happened to a booking
+// Booking загружен со связями Room, Creator и Participants
+type BookingEvent struct {
+	Type    BookingEventType
+	Booking *models.Booking
+	Changes []FieldChange // Только для booking.updated
+	User    *models.User  // Только для booking.waitlist_promoted: кто получил место
+}
+
+// FieldChange is a booking field changed by an update
+// Old и New - значения в JSON-представлении поля: время - RFC3339, остальное - как в бронировании
+type FieldChange struct {
+	Field string `json:"field"` // Имя поля в JSON бронирования: start_time, end_time, title, description, estimated_participants, is_joinable, max_participants
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// bookingChanges сравнивает редактируемые поля бронирования до и после изменения
+func bookingChanges(before, after *models.Booking) []FieldChange {
+	var changes []FieldChange
+	if !before.StartTime.Equal(after.StartTime) {
+		changes = append(changes, FieldChange{Field: "start_time", Old: before.StartTime, New: after.StartTime})
+	}
+	if !before.EndTime.Equal(after.EndTime) {
+		changes = append(changes, FieldChange{Field: "end_time", Old: before.EndTime, New: after.EndTime})
+	}
+	if before.Title != after.Title {
+		changes = append(changes, FieldChange{Field: "title", Old: before.Title, New: after.Title})
+	}
+	if before.Description != after.Description {
+		changes = append(changes, FieldChange{Field: "description", Old: before.Description, New: after.Description})
+	}
+	if before.EstimatedParticipants != after.EstimatedParticipants {
+		changes = append(changes, FieldChange{Field: "estimated_participants", Old: before.EstimatedParticipants, New: after.EstimatedParticipants})
+	}
+	if before.IsJoinable != after.IsJoinable {
+		changes = append(changes, FieldChange{Field: "is_joinable", Old: before.IsJoinable, New: after.IsJoinable})
+	}
+	if before.MaxParticipants != after.MaxParticipants {
+		changes = append(changes, FieldChange{Field: "max_participants", Old: before.MaxParticipants, New: after.MaxParticipants})
+	}
+	return changes
+}
+
+// EventSubscriber is a delivery channel for booking events (bot webhook, Slack)
+type EventSubscriber interface {
+	HandleBookingEvent(ctx context.Context, event BookingEvent) error
+}
+
+// EventBus fans booking events out to delivery channels
+// Каждый канал получает событие отдельной задачей в пуле исходящих вызовов:
+// медленный или недоступный канал не задерживает остальные и сам запрос
+type EventBus struct {
+	tasks       TaskQueue
+	subscribers []namedSubscriber
+	logger      *slog.Logger
+}
+
+type namedSubscriber struct {
+	name string
+	EventSubscriber
+}
+
+// NewEventBus creates an event bus that delivers events through the task queue
+func NewEventBus(tasks TaskQueue, logger *slog.Logger) *EventBus {
+	return &EventBus{tasks: tasks, logger: logger}
+}
+
+// Subscribe adds a delivery channel; channels must be added before events are published
+func (b *EventBus) Subscribe(name string, subscriber EventSubscriber) {
+	b.subscribers = append(b.subscribers, namedSubscriber{name: name, EventSubscriber: subscriber})
+}
+
+// Publish queues the event for every channel
+// Ошибки доставки только логируются: событие уже произошло и не откатывается
+func (b *EventBus) Publish(event BookingEvent) {
+	if b == nil {
+		return
+	}
+	for _, sub := range b.subscribers {
+		err := b.tasks.Submit(sub.name+":"+string(event.Type), func(ctx context.Context) {
+			if err := sub.HandleBookingEvent(ctx, event); err != nil {
+				b.logger.Error("failed to deliver booking event", "channel", sub.name, "event", event.Type, "booking_id", event.Booking.ID, "error", err)
+			}
+		})
+		if err != nil {
+			b.logger.Warn("booking event dropped", "channel", sub.name, "event", event.Type, "booking_id", event.Booking.ID, "error", err)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// In-memory fakes для изолированных тестов сервисов
+// Встроенный интерфейс покрывает методы, которые тест не использует: их вызов паникует
+
+type fakeTx struct{}
+
+func (fakeTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeRoomStore struct {
+	RoomStore
+	rooms map[uint]*models.Room
+}
+
+func (f *fakeRoomStore) GetByID(ctx context.Context, id uint) (*models.Room, error) {
+	room, ok := f.rooms[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return room, nil
+}
+
+func (f *fakeRoomStore) LockByID(ctx context.Context, id uint) (*models.Room, error) {
+	return f.GetByID(ctx, id)
+}
+
+func (f *fakeRoomStore) GetParkingSpots(ctx context.Context) ([]models.Room, error) {
+	var spots []models.Room
+	for _, room := range f.rooms {
+		if room.IsActive && room.Kind == models.RoomKindParking {
+			spots = append(spots, *room)
+		}
+	}
+	sort.Slice(spots, func(i, j int) bool { return spots[i].Name < spots[j].Name })
+	return spots, nil
+}
+
+type fakeUserStore struct {
+	UserStore
+	users map[uint]*models.User
+}
+
+func (f *fakeUserStore) GetByID(ctx context.Context, id uint) (*models.User, error) {
+	user, ok := f.users[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return user, nil
+}
+
+func (f *fakeUserStore) GetByIDs(ctx context.Context, ids []uint) ([]models.User, error) {
+	var users []models.User
+	for _, id := range ids {
+		if user, ok := f.users[id]; ok {
+			users = append(users, *user)
+		}
+	}
+	return users, nil
+}
+
+func (f *fakeUserStore) GetByTelegramID(ctx context.Context, telegramID int64) (*models.User, error) {
+	for _, user := range f.users {
+		if user.TelegramID == telegramID {
+			return user, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// UpdateColumns - пользователи хранятся указателями, изменения уже в карте
+func (f *fakeUserStore) UpdateColumns(ctx context.Context, user *models.User, columns ...string) error {
+	return nil
+}
+
+type fakeBookingStore struct {
+	BookingStore
+	bookings map[uint]*models.Booking
+	waitlist map[uint][]models.BookingWaitlistEntry
+	nextID   uint
+}
+
+func newFakeBookingStore(bookings ...models.Booking) *fakeBookingStore {
+	f := &fakeBookingStore{bookings: make(map[uint]*models.Booking)}
+	for i := range bookings {
+		b := bookings[i]
+		f.bookings[b.ID] = &b
+		if b.ID > f.nextID {
+			f.nextID = b.ID
+		}
+	}
+	return f
+}
+
+func (f *fakeBookingStore) Create(ctx context.Context, booking *models.Booking) error {
+	f.nextID++
+	booking.ID = f.nextID
+	stored := *booking
+	f.bookings[booking.ID] = &stored
+	return nil
+}
+
+func (f *fakeBookingStore) GetByID(ctx context.Context, id uint) (*models.Booking, error) {
+	booking, ok := f.bookings[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *booking
+	return &copied, nil
+}
+
+func (f *fakeBookingStore) Update(ctx context.Context, booking *models.Booking) error {
+	stored := *booking
+	f.bookings[booking.ID] = &stored
+	return nil
+}
+
+func (f *fakeBookingStore) GetConflictingBookings(ctx context.Context, roomID uint, start, end time.Time, excludeBookingID *uint) ([]models.Booking, error) {
+	var conflicts []models.Booking
+	for _, b := range f.bookings {
+		if b.RoomID != roomID || b.Status == models.BookingStatusCancelled {
+			continue
+		}
+		if excludeBookingID != nil && b.ID == *excludeBookingID {
+			continue
+		}
+		if b.StartTime.Before(end) && b.EndTime.After(start) {
+			conflicts = append(conflicts, *b)
+		}
+	}
+	return conflicts, nil
+}
+
+func (f *fakeBookingStore) GetByRoomAndTimeRange(ctx context.Context, roomID uint, start, end time.Time) ([]models.Booking, error) {
+	return f.GetConflictingBookings(ctx, roomID, start, end, nil)
+}
+
+func (f *fakeBookingStore) GetByUserID(ctx context.Context, userID uint, limit, offset int, order string) ([]models.Booking, int64, error) {
+	var bookings []models.Booking
+	for _, b := range f.bookings {
+		if b.CreatorID == userID {
+			bookings = append(bookings, *b)
+		}
+	}
+	return bookings, int64(len(bookings)), nil
+}
+
+func (f *fakeBookingStore) GetByUserInRange(ctx context.Context, userID uint, start, end time.Time) ([]models.Booking, error) {
+	var bookings []models.Booking
+	for _, b := range f.bookings {
+		if b.Status == models.BookingStatusCancelled || !b.StartTime.Before(end) || !b.EndTime.After(start) {
+			continue
+		}
+		if isBookingMember(b, userID) {
+			bookings = append(bookings, *b)
+		}
+	}
+	sort.Slice(bookings, func(i, j int) bool { return bookings[i].StartTime.Before(bookings[j].StartTime) })
+	return bookings, nil
+}
+
+func (f *fakeBookingStore) Cancel(ctx context.Context, id uint) error {
+	booking, ok := f.bookings[id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	booking.Status = models.BookingStatusCancelled
+	return nil
+}
+
+func (f *fakeBookingStore) CountUpcomingByCreator(ctx context.Context, userID uint, now time.Time) (int64, error) {
+	var count int64
+	for _, b := range f.bookings {
+		if b.CreatorID == userID && b.Status != models.BookingStatusCancelled && b.EndTime.After(now) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (f *fakeBookingStore) GetCurrentByRoom(ctx context.Context, roomID uint, now time.Time) (*models.Booking, error) {
+	for _, b := range f.bookings {
+		if b.RoomID == roomID && b.Status != models.BookingStatusCancelled && b.StartTime.Before(now) && b.EndTime.After(now) {
+			copied := *b
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeBookingStore) Release(ctx context.Context, id uint, now time.Time) (bool, error) {
+	b, ok := f.bookings[id]
+	if !ok || b.Status == models.BookingStatusCancelled || !b.EndTime.After(now) {
+		return false, nil
+	}
+	b.Status = models.BookingStatusCompleted
+	b.EndTime = now
+	return true, nil
+}
+
+func (f *fakeBookingStore) AddParticipant(ctx context.Context, bookingID, userID uint) (bool, error) {
+	booking := f.bookings[bookingID]
+	for _, p := range booking.Participants {
+		if p.ID == userID {
+			return false, nil
+		}
+	}
+	booking.Participants = append(booking.Participants, models.User{ID: userID})
+	return true, nil
+}
+
+func (f *fakeBookingStore) RemoveParticipant(ctx context.Context, bookingID, userID uint) (bool, error) {
+	booking := f.bookings[bookingID]
+	for i, p := range booking.Participants {
+		if p.ID == userID {
+			booking.Participants = append(booking.Participants[:i:i], booking.Participants[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeBookingStore) CountParticipants(ctx context.Context, bookingID uint) (int64, error) {
+	return int64(len(f.bookings[bookingID].Participants)), nil
+}
+
+// Очередь хранится по бронированиям в порядке записи
+func (f *fakeBookingStore) GetWaitlist(ctx context.Context, bookingID uint) ([]models.BookingWaitlistEntry, error) {
+	return append([]models.BookingWaitlistEntry(nil), f.waitlist[bookingID]...), nil
+}
+
+func (f *fakeBookingStore) AddToWaitlist(ctx context.Context, bookingID, userID uint) (bool, error) {
+	for _, e := range f.waitlist[bookingID] {
+		if e.UserID == userID {
+			return false, nil
+		}
+	}
+	if f.waitlist == nil {
+		f.waitlist = make(map[uint][]models.BookingWaitlistEntry)
+	}
+	f.waitlist[bookingID] = append(f.waitlist[bookingID], models.BookingWaitlistEntry{BookingID: bookingID, UserID: userID, User: models.User{ID: userID}})
+	return true, nil
+}
+
+func (f *fakeBookingStore) RemoveFromWaitlist(ctx context.Context, bookingID, userID uint) (bool, error) {
+	for i, e := range f.waitlist[bookingID] {
+		if e.UserID == userID {
+			f.waitlist[bookingID] = append(f.waitlist[bookingID][:i:i], f.waitlist[bookingID][i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
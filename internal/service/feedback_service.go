@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/internal/repository"
+	"github.com/space/backend/pkg/apperror"
+)
+
+var (
+	ErrInvalidRating          = apperror.BadRequest("invalid_rating", "rating must be between 1 and 5")
+	ErrFeedbackCommentTooLong = apperror.BadRequest("feedback_comment_too_long", "comment is too long (max 2000 characters)")
+	ErrFeedbackTooEarly       = apperror.Conflict("feedback_too_early", "feedback can be left after the booking ends")
+	ErrFeedbackCancelled      = apperror.Conflict("feedback_booking_cancelled", "cannot leave feedback for a cancelled booking")
+	ErrFeedbackNotParticipant = apperror.Forbidden("feedback_not_participant", "only participants of the booking can leave feedback")
+)
+
+// MaxFeedbackComment ограничивает длину комментария к отзыву
+const MaxFeedbackComment = 2000
+
+// FeedbackRequest represents a participant's rating of the room after a booking
+type FeedbackRequest struct {
+	Rating  int    `json:"rating" binding:"required,min=1,max=5"`
+	Comment string `json:"comment" binding:"max=2000"` // Замечания о комнате и оборудовании
+}
+
+// FeedbackService collects feedback of booking participants and reports room ratings
+type FeedbackService struct {
+	bookingRepo  BookingStore
+	feedbackRepo FeedbackStore
+	logger       *slog.Logger
+}
+
+// NewFeedbackService creates a new feedback service
+func NewFeedbackService(bookingRepo BookingStore, feedbackRepo FeedbackStore, logger *slog.Logger) *FeedbackService {
+	return &FeedbackService{
+		bookingRepo:  bookingRepo,
+		feedbackRepo: feedbackRepo,
+		logger:       logger,
+	}
+}
+
+// Submit saves the feedback of a participant (creator or joined user) on an ended booking
+// Повторная отправка заменяет прежнюю оценку и комментарий
+func (s *FeedbackService) Submit(ctx context.Context, bookingID, userID uint, req FeedbackRequest) (*models.BookingFeedback, error) {
+	if req.Rating < models.MinFeedbackRating || req.Rating > models.MaxFeedbackRating {
+		return nil, ErrInvalidRating
+	}
+	comment := strings.TrimSpace(req.Comment)
+	if len([]rune(comment)) > MaxFeedbackComment {
+		return nil, ErrFeedbackCommentTooLong
+	}
+
+	booking, err := s.bookingRepo.GetByID(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if booking.Status == models.BookingStatusCancelled {
+		return nil, ErrFeedbackCancelled
+	}
+	// Досрочно освобождённое бронирование завершено, даже если его время ещё не вышло
+	if booking.Status != models.BookingStatusCompleted && booking.EndTime.After(time.Now()) {
+		return nil, ErrFeedbackTooEarly
+	}
+	if !isBookingParticipant(booking, userID) {
+		return nil, ErrFeedbackNotParticipant
+	}
+
+	feedback := &models.BookingFeedback{
+		BookingID: booking.ID,
+		UserID:    userID,
+		RoomID:    booking.RoomID,
+		Rating:    req.Rating,
+		Comment:   comment,
+	}
+	if err := s.feedbackRepo.Upsert(ctx, feedback); err != nil {
+		return nil, err
+	}
+	s.logger.Info("booking feedback saved", "booking_id", booking.ID, "room_id", booking.RoomID, "rating", feedback.Rating)
+	return feedback, nil
+}
+
+// RoomReport gets feedback aggregates of rooms since the given time, worst rated first
+func (s *FeedbackService) RoomReport(ctx context.Context, since time.Time) ([]repository.RoomRatingStats, error) {
+	stats, err := s.feedbackRepo.RoomRatingStats(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	for i := range stats {
+		stats[i].Average = roundRating(stats[i].Average)
+	}
+	return stats, nil
+}
+
+// isBookingParticipant проверяет, что пользователь создал бронирование или присоединился к нему
+func isBookingParticipant(booking *models.Booking, userID uint) bool {
+	if booking.CreatorID == userID {
+		return true
+	}
+	for _, participant := range booking.Participants {
+		if participant.ID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// roundRating округляет среднюю оценку до десятых
+func roundRating(average float64) float64 {
+	return math.Round(average*10) / 10
+}
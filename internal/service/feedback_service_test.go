@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/internal/repository"
+)
+
+// fakeFeedbackStore хранит отзывы в памяти; повторный отзыв участника заменяет прежний
+type fakeFeedbackStore struct {
+	feedback []models.BookingFeedback
+	stats    []repository.RoomRatingStats
+}
+
+func (f *fakeFeedbackStore) Upsert(ctx context.Context, feedback *models.BookingFeedback) error {
+	for i := range f.feedback {
+		if f.feedback[i].BookingID == feedback.BookingID && f.feedback[i].UserID == feedback.UserID {
+			f.feedback[i] = *feedback
+			return nil
+		}
+	}
+	f.feedback = append(f.feedback, *feedback)
+	return nil
+}
+
+func (f *fakeFeedbackStore) RoomRatingStats(ctx context.Context, since time.Time) ([]repository.RoomRatingStats, error) {
+	return f.stats, nil
+}
+
+func TestFeedbackService_Submit(t *testing.T) {
+	now := time.Now()
+	bookings := newFakeBookingStore(
+		models.Booking{ID: 1, RoomID: 7, CreatorID: 10, StartTime: now.Add(-2 * time.Hour), EndTime: now.Add(-time.Hour),
+			Status: models.BookingStatusConfirmed, Participants: []models.User{{ID: 11}}},
+		models.Booking{ID: 2, RoomID: 7, CreatorID: 10, StartTime: now.Add(-time.Hour), EndTime: now.Add(time.Hour), Status: models.BookingStatusConfirmed},
+		models.Booking{ID: 3, RoomID: 7, CreatorID: 10, StartTime: now.Add(-time.Hour), EndTime: now.Add(time.Hour), Status: models.BookingStatusCompleted},
+	)
+	store := &fakeFeedbackStore{}
+	svc := NewFeedbackService(bookings, store, slog.Default())
+	ctx := context.Background()
+
+	feedback, err := svc.Submit(ctx, 1, 11, FeedbackRequest{Rating: 2, Comment: "  Проектор не работает "})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if feedback.RoomID != 7 || feedback.Comment != "Проектор не работает" {
+		t.Errorf("Unexpected feedback: %+v", feedback)
+	}
+	if _, err := svc.Submit(ctx, 1, 11, FeedbackRequest{Rating: 4}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(store.feedback) != 1 || store.feedback[0].Rating != 4 {
+		t.Errorf("Expected repeated feedback to replace the previous one, got: %+v", store.feedback)
+	}
+
+	// Досрочно освобождённое бронирование можно оценить до окончания его времени
+	if _, err := svc.Submit(ctx, 3, 10, FeedbackRequest{Rating: 5}); err != nil {
+		t.Errorf("Expected feedback on a released booking, got: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		bookingID uint
+		userID    uint
+		rating    int
+		want      error
+	}{
+		{"not ended", 2, 10, 5, ErrFeedbackTooEarly},
+		{"not a participant", 1, 12, 5, ErrFeedbackNotParticipant},
+		{"rating out of range", 1, 10, 6, ErrInvalidRating},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.Submit(ctx, tt.bookingID, tt.userID, FeedbackRequest{Rating: tt.rating}); !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got: %v", tt.want, err)
+			}
+		})
+	}
+}
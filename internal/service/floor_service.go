@@ -0,0 +1,283 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/pkg/apperror"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrFloorNotFound      = apperror.NotFound("floor_not_found", "floor not found")
+	ErrNoFloorImage       = apperror.NotFound("floor_image_missing", "floor plan image is not uploaded")
+	ErrInvalidFloorImage  = apperror.BadRequest("invalid_floor_image", "floor plan must be a PNG, JPEG or WebP image")
+	ErrInvalidMapPosition = apperror.BadRequest("invalid_map_position", "map_x and map_y must be between 0 and 1")
+)
+
+// floorImageTypes - допустимые форматы плана этажа и расширения файлов
+// SVG не принимается: файл отдаётся с домена API и может содержать скрипты
+var floorImageTypes = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/webp": ".webp",
+}
+
+// FloorRequest represents a request to create or change a floor
+type FloorRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Level int    `json:"level"` // Порядок этажей в Mini App
+}
+
+// RoomPlacementRequest places a room on a floor plan
+type RoomPlacementRequest struct {
+	FloorID *uint    `json:"floor_id"` // null - убрать комнату с плана
+	MapX    *float64 `json:"map_x"`    // Доля ширины изображения (0..1) от левого края
+	MapY    *float64 `json:"map_y"`    // Доля высоты изображения (0..1) от верхнего края
+}
+
+// FloorMapRoom is a room or parking spot marker on a floor plan
+type FloorMapRoom struct {
+	ID       uint            `json:"id"`
+	Name     string          `json:"name"`
+	Kind     models.RoomKind `json:"kind"`
+	Capacity int             `json:"capacity"`
+	X        float64         `json:"x"`
+	Y        float64         `json:"y"`
+}
+
+// FloorMap is a floor plan with clickable markers of its rooms and parking spots
+type FloorMap struct {
+	Floor    models.Floor   `json:"floor"`
+	ImageURL string         `json:"image_url,omitempty"` // Пусто - план не загружен
+	Rooms    []FloorMapRoom `json:"rooms"`
+}
+
+// FloorService manages floors, their plan images and the placement of rooms on them
+type FloorService struct {
+	floorRepo FloorStore
+	roomCache RoomListInvalidator
+	imageDir  string
+	logger    *slog.Logger
+}
+
+// NewFloorService creates a new floor service
+// Планы этажей хранятся файлами в imageDir (STORAGE_PATH/floors)
+func NewFloorService(floorRepo FloorStore, roomCache RoomListInvalidator, imageDir string, logger *slog.Logger) *FloorService {
+	return &FloorService{
+		floorRepo: floorRepo,
+		roomCache: roomCache,
+		imageDir:  imageDir,
+		logger:    logger,
+	}
+}
+
+// ListFloors gets all floors by level
+func (s *FloorService) ListFloors(ctx context.Context) ([]models.Floor, error) {
+	return s.floorRepo.List(ctx)
+}
+
+// CreateFloor creates a floor without a plan (admin only)
+func (s *FloorService) CreateFloor(ctx context.Context, req FloorRequest) (*models.Floor, error) {
+	floor := &models.Floor{Name: req.Name, Level: req.Level}
+	if err := s.floorRepo.Create(ctx, floor); err != nil {
+		return nil, err
+	}
+	return floor, nil
+}
+
+// UpdateFloor changes the name and level of a floor (admin only)
+func (s *FloorService) UpdateFloor(ctx context.Context, id uint, req FloorRequest) (*models.Floor, error) {
+	floor, err := s.getFloor(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	floor.Name = req.Name
+	floor.Level = req.Level
+	if err := s.floorRepo.Update(ctx, floor); err != nil {
+		return nil, err
+	}
+	return floor, nil
+}
+
+// DeleteFloor deletes a floor and its plan; its rooms stay bookable but leave the map (admin only)
+func (s *FloorService) DeleteFloor(ctx context.Context, id uint) error {
+	floor, err := s.getFloor(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.floorRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.roomCache.InvalidateRoomList()
+	s.removeImage(floor.ImagePath)
+	return nil
+}
+
+// UploadImage stores a new plan image of a floor, replacing the previous one (admin only)
+// Формат определяется по содержимому, а не по имени файла или Content-Type запроса
+func (s *FloorService) UploadImage(ctx context.Context, id uint, image io.Reader) (*models.Floor, error) {
+	floor, err := s.getFloor(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(image, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		if errors.Is(err, io.EOF) {
+			return nil, ErrInvalidFloorImage
+		}
+		return nil, err
+	}
+	head = head[:n]
+	contentType := http.DetectContentType(head)
+	ext, ok := floorImageTypes[contentType]
+	if !ok {
+		return nil, ErrInvalidFloorImage
+	}
+
+	// Новое имя файла на каждую загрузку: старый план отдаётся, пока не сохранён новый
+	now := time.Now()
+	name := fmt.Sprintf("%d-%d%s", floor.ID, now.UnixNano(), ext)
+	if err := s.writeImage(name, io.MultiReader(bytes.NewReader(head), image)); err != nil {
+		return nil, err
+	}
+
+	previous := floor.ImagePath
+	floor.ImagePath = name
+	floor.ImageType = contentType
+	floor.ImageUpdatedAt = &now
+	if err := s.floorRepo.Update(ctx, floor); err != nil {
+		s.removeImage(name)
+		return nil, err
+	}
+	s.removeImage(previous)
+
+	s.logger.Info("floor plan uploaded", "floor_id", floor.ID, "type", contentType)
+	return floor, nil
+}
+
+// OpenImage returns the path and MIME type of a floor plan image
+func (s *FloorService) OpenImage(ctx context.Context, id uint) (string, string, error) {
+	floor, err := s.getFloor(ctx, id)
+	if err != nil {
+		return "", "", err
+	}
+	if floor.ImagePath == "" {
+		return "", "", ErrNoFloorImage
+	}
+	return filepath.Join(s.imageDir, floor.ImagePath), floor.ImageType, nil
+}
+
+// GetMap returns a floor with its plan URL and the markers of active rooms and parking spots placed on it
+func (s *FloorService) GetMap(ctx context.Context, id uint) (*FloorMap, error) {
+	floor, err := s.getFloor(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	rooms, err := s.floorRepo.GetRooms(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &FloorMap{Floor: *floor, Rooms: make([]FloorMapRoom, 0, len(rooms))}
+	if floor.ImageUpdatedAt != nil {
+		// Версия в URL - изображение можно кэшировать, новая загрузка меняет адрес
+		result.ImageURL = fmt.Sprintf("/api/floors/%d/image?v=%d", floor.ID, floor.ImageUpdatedAt.Unix())
+	}
+	for _, room := range rooms {
+		if room.MapX == nil || room.MapY == nil {
+			continue
+		}
+		result.Rooms = append(result.Rooms, FloorMapRoom{
+			ID:       room.ID,
+			Name:     room.Name,
+			Kind:     room.Kind,
+			Capacity: room.Capacity,
+			X:        *room.MapX,
+			Y:        *room.MapY,
+		})
+	}
+	return result, nil
+}
+
+// PlaceRoom places a room or parking spot on a floor plan, or removes it from the plan (admin only)
+func (s *FloorService) PlaceRoom(ctx context.Context, roomID uint, req RoomPlacementRequest) error {
+	if req.FloorID == nil {
+		req.MapX, req.MapY = nil, nil
+	} else {
+		if !validMapCoordinate(req.MapX) || !validMapCoordinate(req.MapY) {
+			return ErrInvalidMapPosition
+		}
+		if _, err := s.getFloor(ctx, *req.FloorID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.floorRepo.SetRoomPlacement(ctx, roomID, req.FloorID, req.MapX, req.MapY); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRoomNotFound
+		}
+		return err
+	}
+	s.roomCache.InvalidateRoomList()
+	return nil
+}
+
+// getFloor загружает этаж; отсутствующий этаж - ErrFloorNotFound
+func (s *FloorService) getFloor(ctx context.Context, id uint) (*models.Floor, error) {
+	floor, err := s.floorRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFloorNotFound
+		}
+		return nil, err
+	}
+	return floor, nil
+}
+
+// writeImage записывает план во временный файл и переименовывает его: недописанный файл не отдаётся
+func (s *FloorService) writeImage(name string, image io.Reader) error {
+	if err := os.MkdirAll(s.imageDir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.imageDir, ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // после переименования - no-op
+
+	if _, err := io.Copy(tmp, image); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(s.imageDir, name))
+}
+
+// removeImage удаляет файл плана; ошибка только логируется - запись в базе уже изменена
+func (s *FloorService) removeImage(name string) {
+	if name == "" {
+		return
+	}
+	if err := os.Remove(filepath.Join(s.imageDir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		s.logger.Warn("failed to remove floor plan", "file", name, "error", err)
+	}
+}
+
+// validMapCoordinate проверяет координату на плане: доля размера изображения
+func validMapCoordinate(v *float64) bool {
+	return v != nil && *v >= 0 && *v <= 1
+}
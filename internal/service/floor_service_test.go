@@ -0,0 +1,164 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/space/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// fakeFloorStore хранит этажи и положение комнат в памяти
+type fakeFloorStore struct {
+	FloorStore
+	floors map[uint]*models.Floor
+	rooms  map[uint]*models.Room
+}
+
+func (f *fakeFloorStore) GetByID(ctx context.Context, id uint) (*models.Floor, error) {
+	floor, ok := f.floors[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *floor
+	return &copied, nil
+}
+
+func (f *fakeFloorStore) Update(ctx context.Context, floor *models.Floor) error {
+	stored := *floor
+	f.floors[floor.ID] = &stored
+	return nil
+}
+
+func (f *fakeFloorStore) GetRooms(ctx context.Context, floorID uint) ([]models.Room, error) {
+	var rooms []models.Room
+	for _, room := range f.rooms {
+		if room.FloorID != nil && *room.FloorID == floorID {
+			rooms = append(rooms, *room)
+		}
+	}
+	return rooms, nil
+}
+
+func (f *fakeFloorStore) SetRoomPlacement(ctx context.Context, roomID uint, floorID *uint, x, y *float64) error {
+	room, ok := f.rooms[roomID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	room.FloorID, room.MapX, room.MapY = floorID, x, y
+	return nil
+}
+
+func pngImage(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 3))); err != nil {
+		t.Fatalf("Failed to encode image: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestFloorService_UploadImage(t *testing.T) {
+	dir := t.TempDir()
+	store := &fakeFloorStore{floors: map[uint]*models.Floor{1: {ID: 1, Name: "2 этаж"}}}
+	svc := NewFloorService(store, &countingInvalidator{}, dir, slog.Default())
+	ctx := context.Background()
+
+	if _, err := svc.UploadImage(ctx, 1, strings.NewReader("<svg onload=\"alert(1)\"></svg>")); !errors.Is(err, ErrInvalidFloorImage) {
+		t.Errorf("Expected ErrInvalidFloorImage for SVG, got: %v", err)
+	}
+	if _, err := svc.UploadImage(ctx, 1, strings.NewReader("")); !errors.Is(err, ErrInvalidFloorImage) {
+		t.Errorf("Expected ErrInvalidFloorImage for an empty file, got: %v", err)
+	}
+	if _, _, err := svc.OpenImage(ctx, 1); !errors.Is(err, ErrNoFloorImage) {
+		t.Errorf("Expected ErrNoFloorImage before upload, got: %v", err)
+	}
+
+	first, err := svc.UploadImage(ctx, 1, bytes.NewReader(pngImage(t)))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	second, err := svc.UploadImage(ctx, 1, bytes.NewReader(pngImage(t)))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	path, contentType, err := svc.OpenImage(ctx, 1)
+	if err != nil || contentType != "image/png" || filepath.Base(path) != second.ImagePath {
+		t.Errorf("Expected the second plan, got: %s %s (%v)", path, contentType, err)
+	}
+	if data, err := os.ReadFile(path); err != nil || !bytes.Equal(data, pngImage(t)) {
+		t.Errorf("Expected the stored plan to match the upload (%v)", err)
+	}
+
+	// Предыдущий план и временные файлы удалены
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 || entries[0].Name() == first.ImagePath {
+		t.Errorf("Expected only the current plan in the directory, got: %v", entries)
+	}
+}
+
+func TestFloorService_PlaceRoomAndMap(t *testing.T) {
+	store := &fakeFloorStore{
+		floors: map[uint]*models.Floor{1: {ID: 1, Name: "2 этаж"}},
+		rooms: map[uint]*models.Room{
+			1: {ID: 1, Name: "Room 1", Kind: models.RoomKindRoom, Capacity: 6},
+			2: {ID: 2, Name: "P1", Kind: models.RoomKindParking},
+		},
+	}
+	cache := &countingInvalidator{}
+	svc := NewFloorService(store, cache, t.TempDir(), slog.Default())
+	ctx := context.Background()
+	floorID, x, y, outside := uint(1), 0.25, 0.75, 1.5
+	missingFloor := uint(9)
+
+	tests := []struct {
+		name   string
+		roomID uint
+		req    RoomPlacementRequest
+		want   error
+	}{
+		{"outside the plan", 1, RoomPlacementRequest{FloorID: &floorID, MapX: &outside, MapY: &y}, ErrInvalidMapPosition},
+		{"no coordinates", 1, RoomPlacementRequest{FloorID: &floorID}, ErrInvalidMapPosition},
+		{"unknown floor", 1, RoomPlacementRequest{FloorID: &missingFloor, MapX: &x, MapY: &y}, ErrFloorNotFound},
+		{"unknown room", 9, RoomPlacementRequest{FloorID: &floorID, MapX: &x, MapY: &y}, ErrRoomNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := svc.PlaceRoom(ctx, tt.roomID, tt.req); !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got: %v", tt.want, err)
+			}
+		})
+	}
+
+	for _, roomID := range []uint{1, 2} {
+		if err := svc.PlaceRoom(ctx, roomID, RoomPlacementRequest{FloorID: &floorID, MapX: &x, MapY: &y}); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+	// Снятие с плана сбрасывает и координаты
+	if err := svc.PlaceRoom(ctx, 2, RoomPlacementRequest{MapX: &x, MapY: &y}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if store.rooms[2].FloorID != nil || store.rooms[2].MapX != nil {
+		t.Errorf("Expected the parking spot to leave the plan, got: %+v", store.rooms[2])
+	}
+	if cache.calls != 3 {
+		t.Errorf("Expected the room list cache to be dropped on each placement, got: %d", cache.calls)
+	}
+
+	floorMap, err := svc.GetMap(ctx, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if floorMap.ImageURL != "" || len(floorMap.Rooms) != 1 || floorMap.Rooms[0].ID != 1 || floorMap.Rooms[0].X != x || floorMap.Rooms[0].Y != y {
+		t.Errorf("Expected one marker and no plan, got: %+v", floorMap)
+	}
+}
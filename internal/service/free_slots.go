@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/space/backend/pkg/apperror"
+)
+
+// ErrInvalidDuration is returned for a free-slot query with a duration out of range
+var ErrInvalidDuration = apperror.BadRequest("invalid_duration", "duration must be between 15 and 720 minutes")
+
+// Подбор свободных слотов: у пространства нет расписания работы, поэтому слоты ищутся в дневные часы
+const (
+	freeSlotsDayStart   = 8 * time.Hour
+	freeSlotsDayEnd     = 22 * time.Hour
+	freeSlotStep        = 15 * time.Minute // Предлагаемое начало выравнивается на четверть часа
+	MinFreeSlotDuration = 15 * time.Minute
+	MaxFreeSlotDuration = 12 * time.Hour
+)
+
+// FreeSlot is a suggested booking slot inside a free window of a room
+type FreeSlot struct {
+	Start       time.Time `json:"start"`        // Предлагаемое начало
+	End         time.Time `json:"end"`          // Start + запрошенная длительность
+	WindowStart time.Time `json:"window_start"` // Свободный промежуток, в котором лежит слот
+	WindowEnd   time.Time `json:"window_end"`
+}
+
+// GetFreeSlots finds free windows of a room on the day of date that fit duration, one suggested slot per window
+// Окна ищутся с 08:00 до 22:00 в часовом поясе комнаты (у комнаты без часового пояса - в часовом поясе date)
+// и не раньше текущего времени. С preferred слоты отсортированы по близости начала к нему, без - по времени
+func (s *BookingService) GetFreeSlots(ctx context.Context, roomID uint, date time.Time, duration time.Duration, preferred time.Time) ([]FreeSlot, error) {
+	if duration < MinFreeSlotDuration || duration > MaxFreeSlotDuration {
+		return nil, ErrInvalidDuration
+	}
+
+	room, err := s.roomRepo.GetByID(ctx, roomID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoomNotFound
+		}
+		return nil, err
+	}
+	slots := []FreeSlot{}
+	if !room.IsActive {
+		return slots, nil
+	}
+
+	loc := roomLocation(room)
+	if loc == nil {
+		loc = date.Location()
+	}
+	date = date.In(loc)
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+	from, to := day.Add(freeSlotsDayStart), day.Add(freeSlotsDayEnd)
+	if now := time.Now().In(loc); now.After(from) {
+		from = ceilToStep(now, freeSlotStep)
+	}
+	if to.Sub(from) < duration {
+		return slots, nil
+	}
+
+	// В нерабочий день пространства свободных слотов нет
+	holiday, err := s.closedDay(ctx, loc, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if holiday != nil {
+		return slots, nil
+	}
+
+	bookings, err := s.bookingRepo.GetByRoomAndTimeRange(ctx, roomID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(bookings, func(i, j int) bool { return bookings[i].StartTime.Before(bookings[j].StartTime) })
+
+	if !preferred.IsZero() {
+		preferred = preferred.In(loc)
+	}
+	addWindow := func(start, end time.Time) {
+		if end.Sub(start) < duration {
+			return
+		}
+		slot := ceilToStep(start, freeSlotStep)
+		if slot.Add(duration).After(end) {
+			slot = start
+		}
+		if !preferred.IsZero() && preferred.After(slot) {
+			slot = preferred
+			if latest := end.Add(-duration); slot.After(latest) {
+				slot = latest
+			}
+		}
+		slots = append(slots, FreeSlot{Start: slot, End: slot.Add(duration), WindowStart: start, WindowEnd: end})
+	}
+
+	cursor := from
+	for _, b := range bookings {
+		if b.StartTime.After(cursor) {
+			addWindow(cursor, b.StartTime.In(loc))
+		}
+		if b.EndTime.After(cursor) {
+			cursor = b.EndTime.In(loc)
+		}
+	}
+	addWindow(cursor, to)
+
+	if !preferred.IsZero() {
+		sort.SliceStable(slots, func(i, j int) bool {
+			return absDuration(slots[i].Start.Sub(preferred)) < absDuration(slots[j].Start.Sub(preferred))
+		})
+	}
+	return slots, nil
+}
+
+// ceilToStep округляет время вверх до шага (от начала часа)
+func ceilToStep(t time.Time, step time.Duration) time.Time {
+	truncated := t.Truncate(step)
+	if truncated.Equal(t) {
+		return t
+	}
+	return truncated.Add(step)
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
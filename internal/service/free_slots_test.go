@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/models"
+)
+
+func TestGetFreeSlots(t *testing.T) {
+	day := time.Now().AddDate(0, 0, 2).UTC()
+	at := func(hour, minute int) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, time.UTC)
+	}
+	store := newFakeBookingStore(
+		models.Booking{ID: 1, RoomID: 1, StartTime: at(9, 0), EndTime: at(12, 10), Status: models.BookingStatusConfirmed},
+		models.Booking{ID: 2, RoomID: 1, StartTime: at(13, 0), EndTime: at(17, 0), Status: models.BookingStatusConfirmed},
+		models.Booking{ID: 3, RoomID: 1, StartTime: at(17, 0), EndTime: at(21, 30), Status: models.BookingStatusCancelled},
+	)
+	svc := newTestBookingService(store)
+	ctx := context.Background()
+
+	slots, err := svc.GetFreeSlots(ctx, 1, at(0, 0), time.Hour, time.Time{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	// 08:00-09:00 и 17:00-22:00; 12:10-13:00 короче часа, отменённое бронирование не занимает комнату
+	if len(slots) != 2 || !slots[0].Start.Equal(at(8, 0)) || !slots[1].Start.Equal(at(17, 0)) || !slots[1].WindowEnd.Equal(at(22, 0)) {
+		t.Fatalf("Expected windows from 08:00 and 17:00, got: %+v", slots)
+	}
+
+	// С желаемым временем слот сдвигается к нему внутри окна, ближайший - первым
+	slots, err = svc.GetFreeSlots(ctx, 1, at(14, 0), 30*time.Minute, at(14, 0))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(slots) != 3 || !slots[0].Start.Equal(at(12, 30)) || !slots[1].Start.Equal(at(17, 0)) || !slots[2].Start.Equal(at(8, 30)) {
+		t.Errorf("Expected slots ranked by distance to 14:00, got: %+v", slots)
+	}
+
+	if _, err := svc.GetFreeSlots(ctx, 1, at(0, 0), 5*time.Minute, time.Time{}); !errors.Is(err, ErrInvalidDuration) {
+		t.Errorf("Expected ErrInvalidDuration, got: %v", err)
+	}
+	if _, err := svc.GetFreeSlots(ctx, 99, at(0, 0), time.Hour, time.Time{}); !errors.Is(err, ErrRoomNotFound) {
+		t.Errorf("Expected ErrRoomNotFound, got: %v", err)
+	}
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/space/backend/internal/config"
+	"github.com/space/backend/internal/database"
+	"github.com/space/backend/pkg/telegram"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// Статусы проверки зависимостей
+const (
+	HealthStatusOK          = "ok"
+	HealthStatusDegraded    = "degraded"    // недоступна некритичная зависимость
+	HealthStatusUnavailable = "unavailable" // недоступна критичная зависимость (БД)
+	HealthStatusError       = "error"
+	HealthStatusSkipped     = "skipped"
+	HealthStatusDraining    = "draining" // сервер останавливается, трафик на него больше не направляется
+)
+
+// healthCheckTimeout ограничивает каждую проверку, чтобы probe оркестратора не зависал
+const healthCheckTimeout = 3 * time.Second
+
+// DependencyStatus represents the result of a single dependency probe
+type DependencyStatus struct {
+	Status    string `json:"status"`
+	Critical  bool   `json:"critical"`
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// HealthReport represents the aggregated readiness state
+type HealthReport struct {
+	Status       string                      `json:"status"`
+	Dependencies map[string]DependencyStatus `json:"dependencies"`
+}
+
+// JobRunner reports whether background jobs are running (scheduler.Scheduler)
+type JobRunner interface {
+	Running() bool
+}
+
+// HealthService probes the database and external dependencies
+type HealthService struct {
+	db         *gorm.DB
+	config     *config.Live
+	jobs       JobRunner
+	httpClient *http.Client
+	logger     *slog.Logger
+
+	// draining выставляется при остановке: readiness отвечает 503, чтобы балансировщик
+	// перестал направлять запросы до того, как сервер закроется
+	draining atomic.Bool
+}
+
+// NewHealthService creates a new health service
+func NewHealthService(db *gorm.DB, cfg *config.Live, jobs JobRunner, logger *slog.Logger) *HealthService {
+	return &HealthService{
+		db:     db,
+		config: cfg,
+		jobs:   jobs,
+		// Редиректы не нужны: любой HTTP ответ означает, что цель доступна
+		httpClient: &http.Client{
+			Timeout: healthCheckTimeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		logger: logger,
+	}
+}
+
+// dependencyCheck - проверка одной зависимости; critical определяет, валит ли она readiness
+type dependencyCheck struct {
+	name     string
+	critical bool
+	check    func(ctx context.Context) error // nil - проверка не настроена
+}
+
+// StartDraining switches readiness to draining; it is never switched back
+func (s *HealthService) StartDraining() {
+	s.draining.Store(true)
+}
+
+// Check probes all dependencies concurrently
+// Недоступная БД, неприменённые миграции или остановленный планировщик делают сервис unavailable,
+// Telegram и webhook - только degraded
+func (s *HealthService) Check(ctx context.Context) *HealthReport {
+	if s.draining.Load() {
+		return &HealthReport{Status: HealthStatusDraining, Dependencies: map[string]DependencyStatus{}}
+	}
+
+	cfg := s.config.Get()
+
+	checks := []dependencyCheck{
+		{name: "database", critical: true, check: s.checkDatabase},
+		{name: "scheduler", critical: true, check: s.checkScheduler},
+		{name: "telegram_api", check: func(ctx context.Context) error {
+			return telegram.CheckBot(ctx, cfg.TelegramBotToken)
+		}},
+	}
+	// Схемой SQLite управляет AutoMigrate, таблицы schema_migrations там нет
+	migrations := dependencyCheck{name: "migrations", critical: true}
+	if !database.IsSQLite(cfg.DatabaseURL) {
+		migrations.check = func(ctx context.Context) error {
+			return database.CheckSchemaVersion(ctx, s.db)
+		}
+	}
+	checks = append(checks, migrations)
+	if cfg.DatabaseReplicaURL != "" {
+		checks = append(checks, dependencyCheck{name: "database_replica", check: s.checkReplica})
+	}
+	webhook := dependencyCheck{name: "bot_webhook"}
+	if cfg.BotWebhookURL != "" {
+		webhook.check = func(ctx context.Context) error {
+			return s.checkWebhook(ctx, cfg.BotWebhookURL)
+		}
+	}
+	checks = append(checks, webhook)
+
+	results := make(map[string]DependencyStatus, len(checks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, dc := range checks {
+		wg.Add(1)
+		go func(dc dependencyCheck) {
+			defer wg.Done()
+			status := s.runCheck(ctx, dc)
+			mu.Lock()
+			results[dc.name] = status
+			mu.Unlock()
+		}(dc)
+	}
+	wg.Wait()
+
+	return &HealthReport{
+		Status:       overallStatus(results),
+		Dependencies: results,
+	}
+}
+
+// runCheck выполняет проверку с таймаутом и замеряет время
+func (s *HealthService) runCheck(ctx context.Context, dc dependencyCheck) DependencyStatus {
+	if dc.check == nil {
+		return DependencyStatus{Status: HealthStatusSkipped, Critical: dc.critical}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := dc.check(ctx)
+	status := DependencyStatus{
+		Status:    HealthStatusOK,
+		Critical:  dc.critical,
+		LatencyMS: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		s.logger.Warn("health check failed", "dependency", dc.name, "error", err)
+		status.Status = HealthStatusError
+		status.Error = err.Error()
+	}
+	return status
+}
+
+// overallStatus сводит статусы зависимостей в общий статус
+func overallStatus(results map[string]DependencyStatus) string {
+	overall := HealthStatusOK
+	for _, r := range results {
+		if r.Status != HealthStatusError {
+			continue
+		}
+		if r.Critical {
+			return HealthStatusUnavailable
+		}
+		overall = HealthStatusDegraded
+	}
+	return overall
+}
+
+// checkDatabase проверяет соединение с БД
+func (s *HealthService) checkDatabase(ctx context.Context) error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// checkScheduler проверяет, что фоновые задачи запущены
+func (s *HealthService) checkScheduler(ctx context.Context) error {
+	if !s.jobs.Running() {
+		return errors.New("scheduler is not running")
+	}
+	return nil
+}
+
+// checkReplica проверяет соединение с репликой чтения
+// Реплика некритична: при её недоступности сервис работает в режиме degraded
+func (s *HealthService) checkReplica(ctx context.Context) error {
+	var one int
+	return s.db.WithContext(ctx).
+		Clauses(dbresolver.Use(database.ReplicaResolver), dbresolver.Read).
+		Raw("SELECT 1").
+		Scan(&one).Error
+}
+
+// checkWebhook проверяет, что хост webhook бота отвечает
+// Код ответа не важен (эндпоинт принимает только POST), важна сетевая доступность
+func (s *HealthService) checkWebhook(ctx context.Context, webhookURL string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, webhookURL, nil)
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook target unreachable: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
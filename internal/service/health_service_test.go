@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"testing"
+)
+
+func TestOverallStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		results map[string]DependencyStatus
+		want    string
+	}{
+		{
+			name: "all ok",
+			results: map[string]DependencyStatus{
+				"database":     {Status: HealthStatusOK, Critical: true},
+				"telegram_api": {Status: HealthStatusOK},
+				"bot_webhook":  {Status: HealthStatusSkipped},
+			},
+			want: HealthStatusOK,
+		},
+		{
+			name: "non-critical failure",
+			results: map[string]DependencyStatus{
+				"database":     {Status: HealthStatusOK, Critical: true},
+				"telegram_api": {Status: HealthStatusError},
+			},
+			want: HealthStatusDegraded,
+		},
+		{
+			name: "critical failure",
+			results: map[string]DependencyStatus{
+				"database":     {Status: HealthStatusError, Critical: true},
+				"telegram_api": {Status: HealthStatusError},
+			},
+			want: HealthStatusUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := overallStatus(tt.results); got != tt.want {
+				t.Errorf("Expected %s, got: %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCheckDraining(t *testing.T) {
+	s := &HealthService{}
+	s.StartDraining()
+
+	report := s.Check(context.Background())
+	if report.Status != HealthStatusDraining {
+		t.Errorf("Expected %s, got: %s", HealthStatusDraining, report.Status)
+	}
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/pkg/apperror"
+	"github.com/space/backend/pkg/holidays"
+)
+
+var (
+	ErrInvalidHolidayDate     = apperror.BadRequest("invalid_holiday_date", "date must be in YYYY-MM-DD format")
+	ErrHolidayNameRequired    = apperror.BadRequest("holiday_name_required", "holiday name is required")
+	ErrHolidayExists          = apperror.Conflict("holiday_exists", "a closure day with this date already exists")
+	ErrHolidayCountryRequired = apperror.BadRequest("holiday_country_required", "country_code is required: set it in the request or HOLIDAY_COUNTRY")
+	ErrHolidayImportDisabled  = apperror.New(http.StatusNotImplemented, "holiday_import_disabled", "holiday import is not configured")
+	ErrUnknownHolidayCountry  = apperror.BadRequest("unknown_country", holidays.ErrUnknownCountry.Error())
+	ErrHolidayProvider        = apperror.New(http.StatusBadGateway, "holiday_provider_failed", "failed to fetch public holidays")
+)
+
+// HolidayProvider returns public holidays of a country (Nager.Date API)
+type HolidayProvider interface {
+	PublicHolidays(ctx context.Context, year int, countryCode string) ([]holidays.Holiday, error)
+}
+
+var _ HolidayProvider = (*holidays.Client)(nil)
+
+// ClosureCalendar tells on which days the space is closed
+// Реализуется HolidayService; BookingService отклоняет бронирования на эти дни и переносит напоминания.
+// Границы дней считаются в loc (часовой пояс комнаты), nil - в часовом поясе пространства
+type ClosureCalendar interface {
+	ClosedDays(ctx context.Context, start, end time.Time, loc *time.Location) (*ClosedDays, error)
+}
+
+var _ ClosureCalendar = (*HolidayService)(nil)
+
+// ClosedDays is a set of closure days in the timezone of the space or of a room
+// Нулевой указатель - нерабочих дней нет
+type ClosedDays struct {
+	loc  *time.Location
+	days map[string]models.Holiday
+	list []models.Holiday
+}
+
+// On returns the closure day containing t
+func (d *ClosedDays) On(t time.Time) (*models.Holiday, bool) {
+	if d == nil {
+		return nil, false
+	}
+	holiday, ok := d.days[t.In(d.loc).Format(models.HolidayDateLayout)]
+	return &holiday, ok
+}
+
+// Overlapping returns the first closure day overlapping [start, end)
+func (d *ClosedDays) Overlapping(start, end time.Time) (*models.Holiday, bool) {
+	if d == nil {
+		return nil, false
+	}
+	local := start.In(d.loc)
+	for day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, d.loc); day.Before(end); day = day.AddDate(0, 0, 1) {
+		if holiday, ok := d.days[day.Format(models.HolidayDateLayout)]; ok {
+			return &holiday, true
+		}
+	}
+	return nil, false
+}
+
+// List returns the closure days ordered by date
+func (d *ClosedDays) List() []models.Holiday {
+	if d == nil {
+		return nil
+	}
+	return d.list
+}
+
+// HolidayRequest represents a closure day created or changed by an admin
+type HolidayRequest struct {
+	Date string `json:"date" binding:"required"` // YYYY-MM-DD
+	Name string `json:"name" binding:"required"`
+}
+
+// HolidayImportResult reports an import of public holidays
+type HolidayImportResult struct {
+	Year     int    `json:"year"`
+	Country  string `json:"country_code"`
+	Imported int    `json:"imported"`
+	Skipped  int    `json:"skipped"` // Дата уже была в календаре или праздник только региональный
+}
+
+// HolidayService manages the closure calendar: holidays and other days the space is closed
+// Даты относятся к часовому поясу пространства (OFFICE_TIMEZONE)
+type HolidayService struct {
+	holidayRepo HolidayStore
+	provider    HolidayProvider // nil - импорт выключен
+	country     string          // Страна импорта по умолчанию (HOLIDAY_COUNTRY)
+	loc         *time.Location
+	logger      *slog.Logger
+}
+
+// NewHolidayService creates a new holiday service
+func NewHolidayService(holidayRepo HolidayStore, provider HolidayProvider, country string, loc *time.Location, logger *slog.Logger) *HolidayService {
+	return &HolidayService{
+		holidayRepo: holidayRepo,
+		provider:    provider,
+		country:     country,
+		loc:         loc,
+		logger:      logger,
+	}
+}
+
+// List returns closure days of a year
+func (s *HolidayService) List(ctx context.Context, year int) ([]models.Holiday, error) {
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, s.loc)
+	return s.holidayRepo.ListBetween(ctx, from.Format(models.HolidayDateLayout), from.AddDate(1, 0, -1).Format(models.HolidayDateLayout))
+}
+
+// ClosedDays returns closure days overlapping [start, end) with day boundaries in loc (nil - the space timezone)
+func (s *HolidayService) ClosedDays(ctx context.Context, start, end time.Time, loc *time.Location) (*ClosedDays, error) {
+	if loc == nil {
+		loc = s.loc
+	}
+	last := end
+	if end.After(start) {
+		last = end.Add(-time.Nanosecond)
+	}
+	list, err := s.holidayRepo.ListBetween(ctx, start.In(loc).Format(models.HolidayDateLayout), last.In(loc).Format(models.HolidayDateLayout))
+	if err != nil {
+		return nil, err
+	}
+	days := &ClosedDays{loc: loc, days: make(map[string]models.Holiday, len(list)), list: list}
+	for _, holiday := range list {
+		days.days[holiday.Date] = holiday
+	}
+	return days, nil
+}
+
+// Create adds a closure day
+func (s *HolidayService) Create(ctx context.Context, req HolidayRequest) (*models.Holiday, error) {
+	holiday := &models.Holiday{Source: models.HolidaySourceManual}
+	if err := applyHolidayRequest(holiday, req); err != nil {
+		return nil, err
+	}
+	created, err := s.holidayRepo.Create(ctx, holiday)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, ErrHolidayExists
+	}
+	s.logger.Info("closure day added", "date", holiday.Date, "name", holiday.Name)
+	return holiday, nil
+}
+
+// Update changes the date and name of a closure day
+func (s *HolidayService) Update(ctx context.Context, id uint, req HolidayRequest) (*models.Holiday, error) {
+	holiday, err := s.holidayRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	previous := holiday.Date
+	if err := applyHolidayRequest(holiday, req); err != nil {
+		return nil, err
+	}
+	if holiday.Date != previous {
+		existing, err := s.holidayRepo.ListBetween(ctx, holiday.Date, holiday.Date)
+		if err != nil {
+			return nil, err
+		}
+		if len(existing) > 0 {
+			return nil, ErrHolidayExists
+		}
+	}
+	if err := s.holidayRepo.Update(ctx, holiday); err != nil {
+		return nil, err
+	}
+	return holiday, nil
+}
+
+// Delete removes a closure day
+func (s *HolidayService) Delete(ctx context.Context, id uint) error {
+	return s.holidayRepo.Delete(ctx, id)
+}
+
+// Import adds nationwide public holidays of a country for a year; dates already in the calendar are kept as is
+// Пустой countryCode - страна из HOLIDAY_COUNTRY
+func (s *HolidayService) Import(ctx context.Context, year int, countryCode string) (*HolidayImportResult, error) {
+	if s.provider == nil {
+		return nil, ErrHolidayImportDisabled
+	}
+	if countryCode == "" {
+		countryCode = s.country
+	}
+	if countryCode == "" {
+		return nil, ErrHolidayCountryRequired
+	}
+
+	public, err := s.provider.PublicHolidays(ctx, year, countryCode)
+	if errors.Is(err, holidays.ErrUnknownCountry) {
+		return nil, ErrUnknownHolidayCountry.Wrap(err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrHolidayProvider, err)
+	}
+
+	result := &HolidayImportResult{Year: year, Country: strings.ToUpper(countryCode)}
+	for _, p := range public {
+		if !p.Global {
+			result.Skipped++
+			continue
+		}
+		name := p.LocalName
+		if name == "" {
+			name = p.Name
+		}
+		holiday := &models.Holiday{Source: models.HolidaySourceImport}
+		if err := applyHolidayRequest(holiday, HolidayRequest{Date: p.Date, Name: name}); err != nil {
+			s.logger.Warn("skipping invalid public holiday", "date", p.Date, "error", err)
+			result.Skipped++
+			continue
+		}
+		created, err := s.holidayRepo.Create(ctx, holiday)
+		if err != nil {
+			return result, err
+		}
+		if created {
+			result.Imported++
+		} else {
+			result.Skipped++
+		}
+	}
+
+	s.logger.Info("public holidays imported", "year", year, "country", result.Country, "imported", result.Imported, "skipped", result.Skipped)
+	return result, nil
+}
+
+// applyHolidayRequest проверяет дату и название и переносит их в holiday
+func applyHolidayRequest(holiday *models.Holiday, req HolidayRequest) error {
+	date, err := time.Parse(models.HolidayDateLayout, strings.TrimSpace(req.Date))
+	if err != nil {
+		return ErrInvalidHolidayDate
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return ErrHolidayNameRequired
+	}
+	holiday.Date = date.Format(models.HolidayDateLayout)
+	holiday.Name = name
+	return nil
+}
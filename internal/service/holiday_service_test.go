@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/pkg/apperror"
+	"github.com/space/backend/pkg/holidays"
+)
+
+// fakeHolidayStore хранит нерабочие дни в памяти
+type fakeHolidayStore struct {
+	HolidayStore
+	holidays []models.Holiday
+}
+
+func (f *fakeHolidayStore) ListBetween(ctx context.Context, from, to string) ([]models.Holiday, error) {
+	var list []models.Holiday
+	for _, h := range f.holidays {
+		if h.Date >= from && h.Date <= to {
+			list = append(list, h)
+		}
+	}
+	return list, nil
+}
+
+func (f *fakeHolidayStore) Create(ctx context.Context, holiday *models.Holiday) (bool, error) {
+	for _, h := range f.holidays {
+		if h.Date == holiday.Date {
+			return false, nil
+		}
+	}
+	holiday.ID = uint(len(f.holidays) + 1)
+	f.holidays = append(f.holidays, *holiday)
+	return true, nil
+}
+
+type fakeHolidayProvider struct {
+	holidays []holidays.Holiday
+}
+
+func (f fakeHolidayProvider) PublicHolidays(ctx context.Context, year int, countryCode string) ([]holidays.Holiday, error) {
+	return f.holidays, nil
+}
+
+func TestHolidayService_ClosedDays(t *testing.T) {
+	loc := time.FixedZone("MSK", 3*60*60)
+	store := &fakeHolidayStore{holidays: []models.Holiday{{ID: 1, Date: "2025-05-09", Name: "День Победы"}}}
+	svc := NewHolidayService(store, nil, "", loc, slog.Default())
+	ctx := context.Background()
+
+	// 22:00 UTC 8 мая - уже 9 мая по времени пространства
+	start := time.Date(2025, 5, 8, 22, 0, 0, 0, time.UTC)
+	closed, err := svc.ClosedDays(ctx, start, start.Add(time.Hour), nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if holiday, ok := closed.Overlapping(start, start.Add(time.Hour)); !ok || holiday.ID != 1 {
+		t.Errorf("Expected the booking to overlap the closure day, got %v %v", holiday, ok)
+	}
+
+	// Бронирование, заканчивающееся ровно в полночь по времени пространства, нерабочий день не задевает
+	before := time.Date(2025, 5, 8, 20, 0, 0, 0, time.UTC)
+	closed, err = svc.ClosedDays(ctx, before, before.Add(time.Hour), nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, ok := closed.Overlapping(before, before.Add(time.Hour)); ok {
+		t.Error("Expected no closure day before midnight of the space timezone")
+	}
+}
+
+func TestHolidayService_Import(t *testing.T) {
+	store := &fakeHolidayStore{holidays: []models.Holiday{{ID: 1, Date: "2025-01-01", Name: "Новый год", Source: models.HolidaySourceManual}}}
+	provider := fakeHolidayProvider{holidays: []holidays.Holiday{
+		{Date: "2025-01-01", LocalName: "Новый год", Global: true},
+		{Date: "2025-01-07", LocalName: "Рождество", Global: true},
+		{Date: "2025-03-08", Name: "Regional day", Global: false},
+	}}
+	ctx := context.Background()
+
+	disabled := NewHolidayService(store, nil, "RU", time.UTC, slog.Default())
+	if _, err := disabled.Import(ctx, 2025, ""); !errors.Is(err, ErrHolidayImportDisabled) {
+		t.Errorf("Expected ErrHolidayImportDisabled, got: %v", err)
+	}
+
+	svc := NewHolidayService(store, provider, "", time.UTC, slog.Default())
+	if _, err := svc.Import(ctx, 2025, ""); !errors.Is(err, ErrHolidayCountryRequired) {
+		t.Errorf("Expected ErrHolidayCountryRequired, got: %v", err)
+	}
+
+	result, err := svc.Import(ctx, 2025, "ru")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result.Imported != 1 || result.Skipped != 2 || result.Country != "RU" {
+		t.Errorf("Unexpected import result: %+v", result)
+	}
+	if store.holidays[0].Source != models.HolidaySourceManual {
+		t.Error("Expected an existing closure day to be kept as is")
+	}
+}
+
+func TestCreateBookingOnClosedDay(t *testing.T) {
+	start := time.Now().Add(48 * time.Hour).UTC()
+	store := &fakeHolidayStore{holidays: []models.Holiday{{ID: 1, Date: start.Format(models.HolidayDateLayout), Name: "Closed"}}}
+	rooms := &fakeRoomStore{rooms: map[uint]*models.Room{1: {ID: 1, Name: "Room 1", IsActive: true}}}
+	users := &fakeUserStore{users: map[uint]*models.User{10: {ID: 10, Role: models.RoleUser}}}
+	closures := NewHolidayService(store, nil, "", time.UTC, slog.Default())
+	svc := NewBookingService(fakeTx{}, newFakeBookingStore(), rooms, users, closures, nil, nil, slog.Default())
+
+	_, err := svc.CreateBooking(context.Background(), 10, CreateBookingRequest{RoomID: 1, StartTime: start, EndTime: start.Add(time.Hour), Title: "Meeting"})
+	var holiday models.Holiday
+	if closedErr, ok := apperror.As(err); ok {
+		holiday, _ = closedErr.Details.(models.Holiday)
+	}
+	if !errors.Is(err, ErrClosedDay) || holiday.ID != 1 {
+		t.Errorf("Expected ErrClosedDay with the holiday, got: %v", err)
+	}
+
+	// Проверка перед бронированием сообщает о нерабочем дне
+	check, err := svc.CheckAvailability(context.Background(), 1, start, start.Add(time.Hour))
+	if err != nil || check.Available || check.Holiday == nil || check.Holiday.ID != 1 {
+		t.Errorf("Expected the closed day in the availability check, got: %+v, %v", check, err)
+	}
+}
+
+func TestReminderTime_ShiftsOffClosedDays(t *testing.T) {
+	store := &fakeHolidayStore{holidays: []models.Holiday{
+		{ID: 1, Date: "2025-05-01", Name: "Праздник весны и труда"},
+		{ID: 2, Date: "2025-05-02", Name: "Выходной"},
+	}}
+	svc := NewHolidayService(store, nil, "", time.UTC, slog.Default())
+	start := time.Date(2025, 5, 2, 10, 0, 0, 0, time.UTC)
+	closed, err := svc.ClosedDays(context.Background(), start.AddDate(0, 0, -3), start.AddDate(0, 0, 1), nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Напоминание за 24 часа попадает на 1 мая и переносится на 30 апреля
+	want := time.Date(2025, 4, 30, 10, 0, 0, 0, time.UTC)
+	if got := reminderTime(start, 24*time.Hour, closed); !got.Equal(want) {
+		t.Errorf("Expected reminder at %v, got %v", want, got)
+	}
+	if got := reminderTime(start, 24*time.Hour, nil); !got.Equal(start.Add(-24 * time.Hour)) {
+		t.Errorf("Expected an unshifted reminder without a calendar, got %v", got)
+	}
+}
+
+func TestCreateBookingOnClosedDay_RoomTimezone(t *testing.T) {
+	// 22:00-23:00 UTC накануне нерабочего дня: в UTC день ещё рабочий, в Москве уже нерабочий
+	day := time.Now().AddDate(0, 0, 3).UTC()
+	start := time.Date(day.Year(), day.Month(), day.Day(), 22, 0, 0, 0, time.UTC)
+	holiday := start.AddDate(0, 0, 1).Format(models.HolidayDateLayout)
+	store := &fakeHolidayStore{holidays: []models.Holiday{{ID: 1, Date: holiday, Name: "Closed"}}}
+	rooms := &fakeRoomStore{rooms: map[uint]*models.Room{
+		1: {ID: 1, Name: "Moscow", IsActive: true, Timezone: "Europe/Moscow"},
+		2: {ID: 2, Name: "Space timezone", IsActive: true},
+	}}
+	users := &fakeUserStore{users: map[uint]*models.User{10: {ID: 10, Role: models.RoleUser}}}
+	closures := NewHolidayService(store, nil, "", time.UTC, slog.Default())
+	svc := NewBookingService(fakeTx{}, newFakeBookingStore(), rooms, users, closures, nil, nil, slog.Default())
+	ctx := context.Background()
+
+	_, err := svc.CreateBooking(ctx, 10, CreateBookingRequest{RoomID: 1, StartTime: start, EndTime: start.Add(time.Hour), Title: "Meeting"})
+	if !errors.Is(err, ErrClosedDay) {
+		t.Errorf("Expected ErrClosedDay in the room timezone, got: %v", err)
+	}
+
+	if _, err := svc.CreateBooking(ctx, 10, CreateBookingRequest{RoomID: 2, StartTime: start, EndTime: start.Add(time.Hour), Title: "Meeting"}); err != nil {
+		t.Errorf("Expected a booking before midnight of the space timezone, got: %v", err)
+	}
+}
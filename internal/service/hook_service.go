@@ -0,0 +1,295 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/pkg/apperror"
+)
+
+const (
+	// maxHooksPerAPIKey ограничивает число подписок одной интеграции
+	maxHooksPerAPIKey = 50
+	hookTimeout       = 10 * time.Second
+	// HookPayloadVersion меняется только при несовместимом изменении HookPayload
+	HookPayloadVersion = 1
+)
+
+var (
+	ErrInvalidHookURL = apperror.BadRequest("invalid_hook_url", "target_url must be a public https URL")
+	ErrTooManyHooks   = apperror.Conflict("too_many_hooks", fmt.Sprintf("at most %d hooks per API key", maxHooksPerAPIKey))
+
+	// errHookInternalAddress - адрес получателя оказался внутренним уже при соединении
+	// (имя хоста резолвится в приватный IP или DNS поменялся после подписки)
+	errHookInternalAddress = errors.New("hook target resolves to an internal address")
+)
+
+// HookService manages REST hook subscriptions for no-code tools (Zapier, Make)
+// Протокол REST hooks: интеграция подписывается POST-запросом с target_url,
+// получает события POST-запросами и отписывается DELETE; ответ 410 Gone на событие
+// тоже отменяет подписку
+type HookService struct {
+	hookRepo    RESTHookStore
+	bookingRepo BookingStore
+	httpClient  *http.Client
+	logger      *slog.Logger
+}
+
+// NewHookService creates a new REST hook service
+func NewHookService(hookRepo RESTHookStore, bookingRepo BookingStore, logger *slog.Logger) *HookService {
+	return &HookService{
+		hookRepo:    hookRepo,
+		bookingRepo: bookingRepo,
+		httpClient:  newHookClient(isPublicIP),
+		logger:      logger,
+	}
+}
+
+// SubscribeHookRequest represents a REST hook subscription request
+type SubscribeHookRequest struct {
+	TargetURL string `json:"target_url" binding:"required"`
+	Event     string `json:"event" binding:"required" example:"booking.created"`
+}
+
+// HookPayload is the body of every REST hook delivery
+// Поля плоские, чтобы no-code инструменты сразу раскладывали их по колонкам (например, Google Sheets);
+// набор и смысл полей не меняются в пределах одной версии
+type HookPayload struct {
+	ID                    string        `json:"id"` // Уникален для события: по нему Zapier отбрасывает повторы
+	Event                 string        `json:"event"`
+	Version               int           `json:"version"`
+	OccurredAt            time.Time     `json:"occurred_at"`
+	BookingID             uint          `json:"booking_id"`
+	RoomID                uint          `json:"room_id"`
+	RoomName              string        `json:"room_name"`
+	Title                 string        `json:"title"`
+	Description           string        `json:"description"`
+	StartTime             time.Time     `json:"start_time"`
+	EndTime               time.Time     `json:"end_time"`
+	Status                string        `json:"status"`
+	CreatorID             uint          `json:"creator_id"`
+	CreatorName           string        `json:"creator_name"`
+	CreatorUsername       string        `json:"creator_username"`
+	ParticipantCount      int           `json:"participant_count"`
+	EstimatedParticipants int           `json:"estimated_participants"`
+	Changes               []FieldChange `json:"changes,omitempty"` // Только для booking.updated
+}
+
+// NewHookPayload builds the delivery body for a booking event
+func NewHookPayload(event BookingEvent, occurredAt time.Time) HookPayload {
+	b := event.Booking
+	id := fmt.Sprintf("%s-%d", event.Type, b.ID)
+	if event.Type == EventBookingUpdated {
+		// Бронирование может меняться много раз - каждое изменение отдельное событие
+		id = fmt.Sprintf("%s-%d", id, b.UpdatedAt.UnixMilli())
+	}
+	return HookPayload{
+		ID:                    id,
+		Event:                 string(event.Type),
+		Version:               HookPayloadVersion,
+		OccurredAt:            occurredAt.UTC(),
+		BookingID:             b.ID,
+		RoomID:                b.RoomID,
+		RoomName:              b.Room.Name,
+		Title:                 b.Title,
+		Description:           b.Description,
+		StartTime:             b.StartTime.UTC(),
+		EndTime:               b.EndTime.UTC(),
+		Status:                string(b.Status),
+		CreatorID:             b.CreatorID,
+		CreatorName:           strings.TrimSpace(b.Creator.FirstName + " " + b.Creator.LastName),
+		CreatorUsername:       b.Creator.Username,
+		ParticipantCount:      len(b.Participants),
+		EstimatedParticipants: b.EstimatedParticipants,
+		Changes:               event.Changes,
+	}
+}
+
+// Subscribe creates a REST hook for an API key
+func (s *HookService) Subscribe(ctx context.Context, apiKeyID uint, req SubscribeHookRequest) (*models.RESTHook, error) {
+	event := strings.TrimSpace(req.Event)
+	if !slices.Contains(ValidBookingEvents, BookingEventType(event)) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidEvent, event)
+	}
+	targetURL := strings.TrimSpace(req.TargetURL)
+	if err := validateHookURL(targetURL); err != nil {
+		return nil, err
+	}
+
+	count, err := s.hookRepo.CountByAPIKey(ctx, apiKeyID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxHooksPerAPIKey {
+		return nil, ErrTooManyHooks
+	}
+
+	hook := &models.RESTHook{APIKeyID: apiKeyID, Event: event, TargetURL: targetURL}
+	if err := s.hookRepo.Create(ctx, hook); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("REST hook subscribed", "hook_id", hook.ID, "api_key_id", apiKeyID, "event", event)
+	return hook, nil
+}
+
+// Unsubscribe deletes a REST hook of an API key
+func (s *HookService) Unsubscribe(ctx context.Context, apiKeyID, id uint) error {
+	if err := s.hookRepo.Delete(ctx, id, apiKeyID); err != nil {
+		return err
+	}
+	s.logger.Info("REST hook unsubscribed", "hook_id", id, "api_key_id", apiKeyID)
+	return nil
+}
+
+// ListHooks returns REST hooks of an API key
+func (s *HookService) ListHooks(ctx context.Context, apiKeyID uint) ([]models.RESTHook, error) {
+	return s.hookRepo.GetByAPIKey(ctx, apiKeyID)
+}
+
+// Samples returns payloads for the nearest upcoming bookings
+// No-code инструменты показывают их при настройке интеграции, до первого реального события
+func (s *HookService) Samples(ctx context.Context, event string) ([]HookPayload, error) {
+	if !slices.Contains(ValidBookingEvents, BookingEventType(event)) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidEvent, event)
+	}
+
+	bookings, err := s.bookingRepo.GetUpcoming(ctx, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]HookPayload, len(bookings))
+	for i := range bookings {
+		samples[i] = NewHookPayload(BookingEvent{Type: BookingEventType(event), Booking: &bookings[i]}, bookings[i].CreatedAt)
+	}
+	return samples, nil
+}
+
+// HandleBookingEvent delivers the event to every subscribed REST hook
+func (s *HookService) HandleBookingEvent(ctx context.Context, event BookingEvent) error {
+	now := time.Now()
+	hooks, err := s.hookRepo.GetByEvent(ctx, string(event.Type), now)
+	if err != nil {
+		return err
+	}
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(NewHookPayload(event, now))
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, hook := range hooks {
+		gone, err := s.deliver(ctx, hook.TargetURL, string(event.Type), body)
+		if gone {
+			// 410 Gone - интеграция удалена на своей стороне, подписка больше не нужна
+			if err := s.hookRepo.Delete(ctx, hook.ID, hook.APIKeyID); err != nil {
+				errs = append(errs, fmt.Errorf("hook %d: %w", hook.ID, err))
+			} else {
+				s.logger.Info("REST hook removed: target is gone", "hook_id", hook.ID, "api_key_id", hook.APIKeyID)
+			}
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("hook %d: %w", hook.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deliver отправляет событие на target_url; возвращает true, если получатель ответил 410 Gone
+func (s *HookService) deliver(ctx context.Context, targetURL, event string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, targetURL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Hook-Event", event)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode == http.StatusGone {
+		return true, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return false, fmt.Errorf("target returned status %d", resp.StatusCode)
+	}
+	return false, nil
+}
+
+// validateHookURL допускает только внешние https-адреса: сервер не должен ходить во внутреннюю сеть
+// Имена хостов проверяются ещё раз при соединении (newHookClient)
+func validateHookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+		return ErrInvalidHookURL
+	}
+
+	host := u.Hostname()
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrInvalidHookURL
+	}
+	if ip := net.ParseIP(host); ip != nil && !isPublicIP(ip) {
+		return ErrInvalidHookURL
+	}
+	return nil
+}
+
+// isPublicIP отсекает loopback, приватные, link-local и неуказанные адреса
+func isPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() && !ip.IsUnspecified()
+}
+
+// newHookClient создаёт клиент доставки событий, который соединяется только с адресами, разрешёнными allowIP
+// Адрес проверяется после резолва DNS, непосредственно перед соединением: проверка target_url при подписке
+// не защищает от имён, указывающих во внутреннюю сеть. Редиректы не выполняются - их цель не проверялась,
+// ответ 3xx считается ошибкой доставки. Прокси не используется, чтобы проверялся адрес самого получателя
+func newHookClient(allowIP func(net.IP) bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   hookTimeout,
+		KeepAlive: 30 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !allowIP(ip) {
+				return errHookInternalAddress
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   hookTimeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type fakeRESTHookStore struct {
+	RESTHookStore
+	hooks []models.RESTHook
+}
+
+func (f *fakeRESTHookStore) GetByEvent(ctx context.Context, event string, now time.Time) ([]models.RESTHook, error) {
+	var hooks []models.RESTHook
+	for _, h := range f.hooks {
+		if h.Event == event {
+			hooks = append(hooks, h)
+		}
+	}
+	return hooks, nil
+}
+
+func (f *fakeRESTHookStore) Delete(ctx context.Context, id, apiKeyID uint) error {
+	for i, h := range f.hooks {
+		if h.ID == id && h.APIKeyID == apiKeyID {
+			f.hooks = append(f.hooks[:i], f.hooks[i+1:]...)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func TestValidateHookURL(t *testing.T) {
+	valid := []string{"https://hooks.zapier.com/hooks/standard/1/abc/", "https://hook.eu1.make.com/xyz"}
+	invalid := []string{"http://hooks.zapier.com/x", "https://localhost/x", "https://127.0.0.1/x", "https://10.0.0.5/x", "https://[::1]/x", "ftp://example.com", "not a url"}
+
+	for _, u := range valid {
+		if err := validateHookURL(u); err != nil {
+			t.Errorf("%s: expected valid, got: %v", u, err)
+		}
+	}
+	for _, u := range invalid {
+		if err := validateHookURL(u); !errors.Is(err, ErrInvalidHookURL) {
+			t.Errorf("%s: expected ErrInvalidHookURL, got: %v", u, err)
+		}
+	}
+}
+
+func TestHookDelivery(t *testing.T) {
+	var payload HookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gone" {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+	}))
+	defer server.Close()
+
+	store := &fakeRESTHookStore{hooks: []models.RESTHook{
+		{ID: 1, APIKeyID: 7, Event: "booking.created", TargetURL: server.URL + "/sheet"},
+		{ID: 2, APIKeyID: 7, Event: "booking.created", TargetURL: server.URL + "/gone"},
+		{ID: 3, APIKeyID: 7, Event: "booking.cancelled", TargetURL: server.URL + "/sheet"},
+	}}
+	svc := NewHookService(store, nil, slog.Default())
+	// Тестовый сервер слушает loopback
+	svc.httpClient = newHookClient(func(net.IP) bool { return true })
+
+	booking := &models.Booking{ID: 5, RoomID: 1, Room: models.Room{Name: "Room 1"}, Title: "Standup", Status: models.BookingStatusConfirmed}
+	if err := svc.HandleBookingEvent(context.Background(), BookingEvent{Type: EventBookingCreated, Booking: booking}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if payload.ID != "booking.created-5" || payload.RoomName != "Room 1" || payload.Version != HookPayloadVersion {
+		t.Errorf("Unexpected payload: %+v", payload)
+	}
+	if len(store.hooks) != 2 || store.hooks[0].ID != 1 || store.hooks[1].ID != 3 {
+		t.Errorf("Expected hook answering 410 to be removed, got: %+v", store.hooks)
+	}
+}
+
+func TestHookDelivery_RejectsInternalAddresses(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+	defer server.Close()
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+
+	// Имя хоста проходит проверку формы, но резолвится в loopback
+	svc := NewHookService(&fakeRESTHookStore{}, nil, slog.Default())
+	if _, err := svc.deliver(context.Background(), "http://localhost:"+port+"/sheet", "booking.created", []byte("{}")); !errors.Is(err, errHookInternalAddress) {
+		t.Errorf("Expected errHookInternalAddress, got: %v", err)
+	}
+	if hits != 0 {
+		t.Errorf("Expected no request to reach an internal address, got: %d", hits)
+	}
+}
+
+func TestHookDelivery_DoesNotFollowRedirects(t *testing.T) {
+	var redirected bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/internal" {
+			redirected = true
+			return
+		}
+		http.Redirect(w, r, "/internal", http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	svc := NewHookService(&fakeRESTHookStore{}, nil, slog.Default())
+	svc.httpClient = newHookClient(func(net.IP) bool { return true })
+	if _, err := svc.deliver(context.Background(), server.URL+"/sheet", "booking.created", []byte("{}")); err == nil {
+		t.Error("Expected a redirect to fail the delivery")
+	}
+	if redirected {
+		t.Error("Expected the redirect not to be followed")
+	}
+}
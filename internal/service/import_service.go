@@ -0,0 +1,479 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/pkg/apperror"
+	"gorm.io/gorm"
+)
+
+// MaxImportRows ограничивает число строк в одном файле импорта
+const MaxImportRows = 5000
+
+// utf8BOM - Excel добавляет его в начало CSV в UTF-8
+const utf8BOM = "\xef\xbb\xbf"
+
+var (
+	ErrImportInvalid = apperror.New(http.StatusUnprocessableEntity, "import_invalid", "import file contains invalid rows, nothing was imported") // Данные - import
+	ErrImportFormat  = apperror.BadRequest("invalid_import_file", "invalid CSV file")
+)
+
+// importTimeLayouts - форматы времени без часового пояса; время с поясом задаётся в RFC 3339
+// Первый формат совпадает с выгрузкой /api/admin/export/bookings
+var importTimeLayouts = []string{
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02T15:04:05",
+	"02.01.2006 15:04",
+}
+
+// ImportRowError describes a problem in a row of the imported file
+type ImportRowError struct {
+	Row     int    `json:"row"` // Номер строки файла; заголовок - строка 1
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportResult describes the outcome of an import
+type ImportResult struct {
+	DryRun   bool             `json:"dry_run"`
+	Rows     int              `json:"rows"`     // Строк данных в файле
+	Imported int              `json:"imported"` // Созданных записей; 0 при dry run и ошибках
+	Errors   []ImportRowError `json:"errors"`
+}
+
+// RoomListInvalidator drops cached room lists (RoomService)
+type RoomListInvalidator interface {
+	InvalidateRoomList()
+}
+
+// ImportService imports rooms and bookings from CSV (migration from spreadsheets and other tools)
+// Файл импортируется целиком или не импортируется вовсе: при ошибке в любой строке
+// возвращаются ошибки всех строк, а транзакция откатывается
+type ImportService struct {
+	txManager   TxRunner
+	roomRepo    RoomStore
+	bookingRepo BookingStore
+	history     BookingHistoryStore // nil - импортированные бронирования не попадают в поток событий
+	userRepo    UserStore
+	roomCache   RoomListInvalidator
+	logger      *slog.Logger
+}
+
+// NewImportService creates a new import service
+func NewImportService(txManager TxRunner, roomRepo RoomStore, bookingRepo BookingStore, history BookingHistoryStore, userRepo UserStore, roomCache RoomListInvalidator, logger *slog.Logger) *ImportService {
+	return &ImportService{
+		txManager:   txManager,
+		roomRepo:    roomRepo,
+		bookingRepo: bookingRepo,
+		history:     history,
+		userRepo:    userRepo,
+		roomCache:   roomCache,
+		logger:      logger,
+	}
+}
+
+// ImportRooms imports rooms with their equipment
+// Колонки: name (обязательна), description, capacity, is_active, equipment (названия через ";")
+func (s *ImportService) ImportRooms(ctx context.Context, r io.Reader, dryRun bool) (*ImportResult, error) {
+	table, err := readImportCSV(r, "name")
+	if err != nil {
+		return nil, err
+	}
+	result := &ImportResult{DryRun: dryRun, Rows: len(table.rows), Errors: []ImportRowError{}}
+
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		seen := make(map[string]int)
+		rooms := make([]*models.Room, 0, len(table.rows))
+		for i, row := range table.rows {
+			line := i + 2
+			rowErr := func(column, message string) {
+				result.Errors = append(result.Errors, ImportRowError{Row: line, Column: column, Message: message})
+			}
+
+			room := &models.Room{
+				Name:        row.get("name"),
+				Description: row.get("description"),
+				Capacity:    1,
+				IsActive:    true,
+			}
+			if room.Name == "" {
+				rowErr("name", "name is required")
+			} else if first, ok := seen[strings.ToLower(room.Name)]; ok {
+				rowErr("name", fmt.Sprintf("duplicates row %d", first))
+			} else {
+				seen[strings.ToLower(room.Name)] = line
+				if _, err := s.roomRepo.GetByName(ctx, room.Name); err == nil {
+					rowErr("name", "room already exists")
+				} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+					return err
+				}
+			}
+
+			if v := row.get("capacity"); v != "" {
+				capacity, err := strconv.Atoi(v)
+				if err != nil || capacity < 1 {
+					rowErr("capacity", "must be a positive integer")
+				}
+				room.Capacity = capacity
+			}
+			if v := row.get("is_active"); v != "" {
+				active, err := parseImportBool(v)
+				if err != nil {
+					rowErr("is_active", "must be true or false")
+				}
+				room.IsActive = active
+			}
+			for _, name := range strings.Split(row.get("equipment"), ";") {
+				if name = strings.TrimSpace(name); name != "" {
+					room.Equipment = append(room.Equipment, models.Equipment{Name: name, IsAvailable: true})
+				}
+			}
+			rooms = append(rooms, room)
+		}
+
+		if len(result.Errors) > 0 {
+			return ErrImportInvalid.WithDetails("import", result)
+		}
+		if dryRun {
+			return nil
+		}
+		for _, room := range rooms {
+			if err := s.roomRepo.Create(ctx, room); err != nil {
+				return err
+			}
+		}
+		result.Imported = len(rooms)
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	if !dryRun {
+		s.roomCache.InvalidateRoomList()
+		s.logger.Info("rooms imported", "count", result.Imported)
+	}
+	return result, nil
+}
+
+// ImportBookings imports past and planned bookings
+// Колонки: room (название), title, start, end - обязательны; description, status (confirmed, cancelled, completed),
+// estimated_participants, is_joinable, creator_email, creator_telegram_id. Без создателя - импортирующий админ.
+// Время без часового пояса читается в loc. Уведомления и webhook по импортированным бронированиям не отправляются
+func (s *ImportService) ImportBookings(ctx context.Context, r io.Reader, loc *time.Location, adminID uint, dryRun bool) (*ImportResult, error) {
+	table, err := readImportCSV(r, "room", "title", "start", "end")
+	if err != nil {
+		return nil, err
+	}
+	result := &ImportResult{DryRun: dryRun, Rows: len(table.rows), Errors: []ImportRowError{}}
+
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		rooms := make(map[string]*models.Room)
+		creators := make(map[string]uint)
+		bookings := make([]*models.Booking, 0, len(table.rows))
+		for i, row := range table.rows {
+			line := i + 2
+			rowErr := func(column, message string) {
+				result.Errors = append(result.Errors, ImportRowError{Row: line, Column: column, Message: message})
+			}
+
+			booking := &models.Booking{
+				Title:                 row.get("title"),
+				Description:           row.get("description"),
+				Status:                models.BookingStatusConfirmed,
+				EstimatedParticipants: 1,
+				CreatorID:             adminID,
+			}
+			if booking.Title == "" {
+				rowErr("title", "title is required")
+			}
+
+			room, err := s.importRoom(ctx, rooms, row.get("room"))
+			switch {
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				rowErr("room", "room not found")
+			case err != nil:
+				return err
+			default:
+				booking.RoomID = room.ID
+			}
+
+			start, startErr := parseImportTime(row.get("start"), loc)
+			if startErr != nil {
+				rowErr("start", startErr.Error())
+			}
+			end, endErr := parseImportTime(row.get("end"), loc)
+			if endErr != nil {
+				rowErr("end", endErr.Error())
+			}
+			booking.StartTime, booking.EndTime = start, end
+			if startErr == nil && endErr == nil && !end.After(start) {
+				rowErr("end", ErrInvalidTime.Error())
+			}
+
+			switch status := models.BookingStatus(strings.ToLower(row.get("status"))); status {
+			case "":
+			case models.BookingStatusConfirmed, models.BookingStatusCancelled, models.BookingStatusCompleted:
+				booking.Status = status
+			default:
+				rowErr("status", "must be confirmed, cancelled or completed")
+			}
+			if v := row.get("estimated_participants"); v != "" {
+				n, err := strconv.Atoi(v)
+				if err != nil || n < 1 {
+					rowErr("estimated_participants", "must be a positive integer")
+				}
+				booking.EstimatedParticipants = n
+			}
+			if v := row.get("is_joinable"); v != "" {
+				joinable, err := parseImportBool(v)
+				if err != nil {
+					rowErr("is_joinable", "must be true or false")
+				}
+				booking.IsJoinable = joinable
+			}
+
+			column, creatorID, err := s.importCreator(ctx, creators, row)
+			switch {
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				rowErr(column, "user not found")
+			case err != nil:
+				return err
+			case creatorID != 0:
+				booking.CreatorID = creatorID
+			}
+
+			// Конфликты проверяются с бронированиями в базе и с предыдущими строками файла
+			if booking.RoomID != 0 && booking.EndTime.After(booking.StartTime) && booking.Status != models.BookingStatusCancelled {
+				if first := overlappingRow(bookings, booking); first > 0 {
+					rowErr("start", fmt.Sprintf("overlaps the booking in row %d", first))
+				} else {
+					conflicts, err := s.bookingRepo.GetConflictingBookings(ctx, booking.RoomID, booking.StartTime, booking.EndTime, nil)
+					if err != nil {
+						return err
+					}
+					if len(conflicts) > 0 {
+						rowErr("start", fmt.Sprintf("room is already booked for this time (booking %d)", conflicts[0].ID))
+					}
+				}
+			}
+			bookings = append(bookings, booking)
+		}
+
+		if len(result.Errors) > 0 {
+			return ErrImportInvalid.WithDetails("import", result)
+		}
+		if dryRun {
+			return nil
+		}
+		for _, booking := range bookings {
+			if err := s.bookingRepo.Create(ctx, booking); err != nil {
+				return err
+			}
+			if err := s.recordImported(ctx, booking, adminID); err != nil {
+				return err
+			}
+		}
+		result.Imported = len(bookings)
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	if !dryRun {
+		s.logger.Info("bookings imported", "count", result.Imported, "admin_id", adminID)
+	}
+	return result, nil
+}
+
+// recordImported записывает импортированное бронирование в поток событий:
+// created и, для уже завершённых или отменённых, завершающее событие
+func (s *ImportService) recordImported(ctx context.Context, booking *models.Booking, adminID uint) error {
+	if err := recordHistory(ctx, s.history, booking.ID, models.BookingHistoryCreated, adminID, createdHistoryData(booking)); err != nil {
+		return err
+	}
+	switch booking.Status {
+	case models.BookingStatusCompleted:
+		return recordHistory(ctx, s.history, booking.ID, models.BookingHistoryCompleted, adminID, &models.BookingHistoryData{EndTime: &booking.EndTime})
+	case models.BookingStatusCancelled:
+		return recordHistory(ctx, s.history, booking.ID, models.BookingHistoryCancelled, adminID, nil)
+	}
+	return nil
+}
+
+// importRoom находит комнату по названию и блокирует её до конца импорта, как при обычном бронировании
+func (s *ImportService) importRoom(ctx context.Context, cache map[string]*models.Room, name string) (*models.Room, error) {
+	if name == "" {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if room, ok := cache[name]; ok {
+		if room == nil {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return room, nil
+	}
+
+	room, err := s.roomRepo.GetByName(ctx, name)
+	if err == nil {
+		room, err = s.roomRepo.LockByID(ctx, room.ID)
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	cache[name] = room
+	return room, err
+}
+
+// importCreator находит создателя по creator_email или creator_telegram_id; 0 - колонки пусты
+func (s *ImportService) importCreator(ctx context.Context, cache map[string]uint, row importRow) (string, uint, error) {
+	column, value := "creator_email", strings.ToLower(row.get("creator_email"))
+	if value == "" {
+		column, value = "creator_telegram_id", row.get("creator_telegram_id")
+	}
+	if value == "" {
+		return "", 0, nil
+	}
+	key := column + ":" + value
+	if id, ok := cache[key]; ok {
+		if id == 0 {
+			return column, 0, gorm.ErrRecordNotFound
+		}
+		return column, id, nil
+	}
+
+	var user *models.User
+	var err error
+	if column == "creator_email" {
+		user, err = s.userRepo.GetByEmail(ctx, value)
+	} else if telegramID, parseErr := strconv.ParseInt(value, 10, 64); parseErr != nil || telegramID <= 0 {
+		err = gorm.ErrRecordNotFound
+	} else {
+		user, err = s.userRepo.GetByTelegramID(ctx, telegramID)
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			cache[key] = 0
+		}
+		return column, 0, err
+	}
+	cache[key] = user.ID
+	return column, user.ID, nil
+}
+
+// overlappingRow возвращает номер строки файла с пересекающимся бронированием той же комнаты (0 - нет)
+func overlappingRow(previous []*models.Booking, b *models.Booking) int {
+	for i, p := range previous {
+		if p.RoomID == b.RoomID && p.Status != models.BookingStatusCancelled &&
+			p.StartTime.Before(b.EndTime) && p.EndTime.After(b.StartTime) {
+			return i + 2
+		}
+	}
+	return 0
+}
+
+// importTable - разобранный CSV: колонки по именам из заголовка
+type importTable struct {
+	rows []importRow
+}
+
+type importRow map[string]string
+
+// get возвращает значение колонки без пробелов по краям; нет колонки - пустая строка
+func (r importRow) get(column string) string {
+	return strings.TrimSpace(r[column])
+}
+
+// readImportCSV читает CSV с заголовком; разделитель - запятая или точка с запятой (так сохраняет Excel)
+// Имена колонок не зависят от регистра, пробелы заменяются на "_"; неизвестные колонки игнорируются
+func readImportCSV(r io.Reader, required ...string) (*importTable, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	text := strings.TrimPrefix(string(data), utf8BOM)
+
+	reader := csv.NewReader(strings.NewReader(text))
+	firstLine, _, _ := strings.Cut(text, "\n")
+	if strings.Count(firstLine, ";") > strings.Count(firstLine, ",") {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrImportFormat, err)
+	}
+	columns := make([]string, len(header))
+	present := make(map[string]bool, len(header))
+	for i, name := range header {
+		columns[i] = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "_")
+		present[columns[i]] = true
+	}
+	for _, name := range required {
+		if !present[name] {
+			return nil, fmt.Errorf("%w: missing column %q", ErrImportFormat, name)
+		}
+	}
+
+	table := &importTable{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrImportFormat, err)
+		}
+		if len(table.rows) == MaxImportRows {
+			return nil, fmt.Errorf("%w: more than %d rows", ErrImportFormat, MaxImportRows)
+		}
+		row := make(importRow, len(columns))
+		for i, value := range record {
+			if i < len(columns) {
+				row[columns[i]] = value
+			}
+		}
+		table.rows = append(table.rows, row)
+	}
+	return table, nil
+}
+
+// parseImportTime разбирает время в RFC 3339 или в одном из importTimeLayouts в поясе loc
+func parseImportTime(value string, loc *time.Location) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errors.New("time is required")
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range importTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, expected YYYY-MM-DD HH:MM or RFC 3339", value)
+}
+
+// parseImportBool разбирает булево значение, включая да/нет
+func parseImportBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "yes", "y", "да":
+		return true, nil
+	case "no", "n", "нет":
+		return false, nil
+	}
+	return strconv.ParseBool(strings.ToLower(value))
+}
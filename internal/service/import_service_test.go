@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// fakeImportRoomStore добавляет поиск по названию и создание к fakeRoomStore
+type fakeImportRoomStore struct {
+	*fakeRoomStore
+}
+
+func (f *fakeImportRoomStore) GetByName(ctx context.Context, name string) (*models.Room, error) {
+	for _, room := range f.rooms {
+		if room.Name == name {
+			return room, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeImportRoomStore) Create(ctx context.Context, room *models.Room) error {
+	room.ID = uint(len(f.rooms) + 1)
+	f.rooms[room.ID] = room
+	return nil
+}
+
+// fakeImportUserStore добавляет поиск по Telegram ID к fakeOIDCUserStore
+type fakeImportUserStore struct {
+	*fakeOIDCUserStore
+}
+
+func (f *fakeImportUserStore) GetByTelegramID(ctx context.Context, telegramID int64) (*models.User, error) {
+	return f.find(func(u *models.User) bool { return u.TelegramID == telegramID })
+}
+
+func newTestImportService(bookings *fakeBookingStore) (*ImportService, *fakeImportRoomStore) {
+	rooms := &fakeImportRoomStore{&fakeRoomStore{rooms: map[uint]*models.Room{
+		1: {ID: 1, Name: "Big Room", IsActive: true},
+	}}}
+	email := "anna@example.com"
+	users := &fakeImportUserStore{&fakeOIDCUserStore{&fakeUserStore{users: map[uint]*models.User{
+		7: {ID: 7, Email: &email},
+	}}}}
+	return NewImportService(fakeTx{}, rooms, bookings, nil, users, NewRoomService(rooms, nil, nil, 0), slog.Default()), rooms
+}
+
+func TestImportRooms(t *testing.T) {
+	svc, rooms := newTestImportService(newFakeBookingStore())
+
+	// Excel сохраняет CSV с BOM и точкой с запятой
+	csv := "\xef\xbb\xbfName;Capacity;Equipment\nSmall Room;4;\"Projector; Whiteboard\"\n"
+	result, err := svc.ImportRooms(context.Background(), strings.NewReader(csv), true)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if result.Rows != 1 || result.Imported != 0 || len(rooms.rooms) != 1 {
+		t.Errorf("Expected dry run not to import, got: %+v", result)
+	}
+
+	result, err = svc.ImportRooms(context.Background(), strings.NewReader(csv), false)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	room := rooms.rooms[2]
+	if result.Imported != 1 || room == nil || room.Capacity != 4 || len(room.Equipment) != 2 || room.Equipment[1].Name != "Whiteboard" {
+		t.Errorf("Unexpected import: %+v, room %+v", result, room)
+	}
+
+	csv = "name,capacity\nBig Room,2\nNew Room,-1\nNew Room,3\n"
+	result, err = svc.ImportRooms(context.Background(), strings.NewReader(csv), false)
+	if !errors.Is(err, ErrImportInvalid) {
+		t.Fatalf("Expected ErrImportInvalid, got: %v", err)
+	}
+	want := []ImportRowError{
+		{Row: 2, Column: "name", Message: "room already exists"},
+		{Row: 3, Column: "capacity", Message: "must be a positive integer"},
+		{Row: 4, Column: "name", Message: "duplicates row 3"},
+	}
+	if len(result.Errors) != len(want) {
+		t.Fatalf("Expected %d errors, got: %+v", len(want), result.Errors)
+	}
+	for i := range want {
+		if result.Errors[i] != want[i] {
+			t.Errorf("Error %d: expected %+v, got: %+v", i, want[i], result.Errors[i])
+		}
+	}
+	if len(rooms.rooms) != 2 {
+		t.Errorf("Expected nothing imported from an invalid file, got %d rooms", len(rooms.rooms))
+	}
+}
+
+func TestImportBookings(t *testing.T) {
+	existing := models.Booking{ID: 1, RoomID: 1, StartTime: time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC), EndTime: time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC), Status: models.BookingStatusConfirmed}
+	bookings := newFakeBookingStore(existing)
+	svc, _ := newTestImportService(bookings)
+	moscow := time.FixedZone("MSK", 3*60*60)
+
+	csv := strings.Join([]string{
+		"Room,Title,Start,End,Status,creator_email",
+		"Big Room,Retro,2025-03-10 14:00,2025-03-10 15:00,,Anna@Example.com",
+		"Big Room,Cancelled sync,2025-03-10 14:00,2025-03-10 15:00,cancelled,",
+		"Big Room,Planning,2025-03-11T09:00:00Z,2025-03-11T10:00:00Z,completed,",
+	}, "\n")
+	result, err := svc.ImportBookings(context.Background(), strings.NewReader(csv), moscow, 42, false)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v (%+v)", err, result)
+	}
+	if result.Imported != 3 {
+		t.Errorf("Expected 3 imported bookings, got: %+v", result)
+	}
+	retro := bookings.bookings[2]
+	if retro.CreatorID != 7 || !retro.StartTime.Equal(time.Date(2025, 3, 10, 11, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected Anna's booking at 11:00 UTC, got: %+v", retro)
+	}
+	if bookings.bookings[3].CreatorID != 42 || bookings.bookings[4].Status != models.BookingStatusCompleted {
+		t.Errorf("Expected admin as default creator and status from file")
+	}
+
+	csv = strings.Join([]string{
+		"room,title,start,end,creator_telegram_id",
+		"Big Room,Overlaps existing,2025-03-10 12:30,2025-03-10 13:30,",
+		"Big Room,First,2025-03-12 10:00,2025-03-12 11:00,",
+		"Big Room,Overlaps row 3,2025-03-12 10:30,2025-03-12 11:30,",
+		"Nowhere,Lost,2025-03-12 10:00,2025-03-12 09:00,999",
+	}, "\n")
+	result, err = svc.ImportBookings(context.Background(), strings.NewReader(csv), moscow, 42, true)
+	if !errors.Is(err, ErrImportInvalid) {
+		t.Fatalf("Expected ErrImportInvalid, got: %v", err)
+	}
+	want := []ImportRowError{
+		{Row: 2, Column: "start", Message: "room is already booked for this time (booking 1)"},
+		{Row: 4, Column: "start", Message: "overlaps the booking in row 3"},
+		{Row: 5, Column: "room", Message: "room not found"},
+		{Row: 5, Column: "end", Message: ErrInvalidTime.Error()},
+		{Row: 5, Column: "creator_telegram_id", Message: "user not found"},
+	}
+	if len(result.Errors) != len(want) {
+		t.Fatalf("Expected %d errors, got: %+v", len(want), result.Errors)
+	}
+	for i := range want {
+		if result.Errors[i] != want[i] {
+			t.Errorf("Error %d: expected %+v, got: %+v", i, want[i], result.Errors[i])
+		}
+	}
+
+	if _, err := svc.ImportBookings(context.Background(), strings.NewReader("room,title\n"), moscow, 42, true); !errors.Is(err, ErrImportFormat) {
+		t.Errorf("Expected ErrImportFormat for missing columns, got: %v", err)
+	}
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/pkg/apperror"
+	"github.com/space/backend/pkg/contentfilter"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidQuarantineStatus = apperror.BadRequest("invalid_quarantine_status", "status must be one of: pending, released, rejected")
+	ErrQuarantineReviewed      = apperror.Conflict("quarantine_reviewed", "quarantined event is already reviewed")
+)
+
+// ContentModerator holds booking events with flagged titles or descriptions until an admin reviews them
+// Вызывается в транзакции изменения бронирования; true - событие задержано и не рассылается
+type ContentModerator interface {
+	Hold(ctx context.Context, event BookingEvent) (bool, error)
+}
+
+var _ ContentModerator = (*ModerationService)(nil)
+
+// ContentViolation is a content filter violation in a booking field
+type ContentViolation struct {
+	Field string `json:"field"` // title или description
+	contentfilter.Violation
+}
+
+// ModerationService checks booking texts before they are broadcast and keeps the admin quarantine
+// Бронирование с подозрительным текстом создаётся как обычно, но подписчики комнаты, Slack и hooks
+// узнают о нём только после проверки администратором - вместо молчаливого отказа
+type ModerationService struct {
+	quarantineRepo QuarantineStore
+	bookingRepo    BookingStore
+	filter         *contentfilter.Filter
+	events         *EventBus
+	logger         *slog.Logger
+	disabled       atomic.Bool // CONTENT_FILTER_ENABLED=false: события не задерживаются, карантин доступен
+}
+
+// NewModerationService creates a new moderation service
+func NewModerationService(quarantineRepo QuarantineStore, bookingRepo BookingStore, filter *contentfilter.Filter, events *EventBus, logger *slog.Logger) *ModerationService {
+	return &ModerationService{
+		quarantineRepo: quarantineRepo,
+		bookingRepo:    bookingRepo,
+		filter:         filter,
+		events:         events,
+		logger:         logger,
+	}
+}
+
+// SetEnabled turns holding of new events on or off; quarantined events stay available for review
+func (s *ModerationService) SetEnabled(enabled bool) {
+	s.disabled.Store(!enabled)
+}
+
+// CheckBooking runs the content filter over the title and description of a booking
+func (s *ModerationService) CheckBooking(booking *models.Booking) []ContentViolation {
+	var violations []ContentViolation
+	for _, field := range []struct{ name, text string }{
+		{"title", booking.Title},
+		{"description", booking.Description},
+	} {
+		for _, v := range s.filter.Check(field.text) {
+			violations = append(violations, ContentViolation{Field: field.name, Violation: v})
+		}
+	}
+	return violations
+}
+
+// Hold quarantines booking.created and booking.updated events whose booking texts break the filter
+// Пока у бронирования есть запись в ожидании, его следующие изменения тоже задерживаются и объединяются с ней.
+// Изменение без новых названия или описания (перенос времени) повторно не проверяется
+func (s *ModerationService) Hold(ctx context.Context, event BookingEvent) (bool, error) {
+	if s.disabled.Load() || (event.Type != EventBookingCreated && event.Type != EventBookingUpdated) {
+		return false, nil
+	}
+
+	violations := s.CheckBooking(event.Booking)
+	pending, err := s.quarantineRepo.GetPendingByBooking(ctx, event.Booking.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+	if pending != nil {
+		return true, s.absorb(ctx, pending, event, violations)
+	}
+	if len(violations) == 0 || (event.Type == EventBookingUpdated && !changesText(event.Changes)) {
+		return false, nil
+	}
+
+	entry := &models.BookingQuarantine{
+		BookingID: event.Booking.ID,
+		Event:     string(event.Type),
+		Status:    models.QuarantinePending,
+	}
+	if entry.Violations, err = json.Marshal(violations); err != nil {
+		return false, err
+	}
+	if event.Type == EventBookingUpdated {
+		if entry.Changes, err = json.Marshal(event.Changes); err != nil {
+			return false, err
+		}
+	}
+	// Запись не создана - параллельное изменение уже поставило бронирование в очередь, событие всё равно задерживается
+	if _, err := s.quarantineRepo.Create(ctx, entry); err != nil {
+		return false, err
+	}
+
+	s.logger.Warn("booking event quarantined by content filter",
+		"booking_id", event.Booking.ID,
+		"event", event.Type,
+		"violations", len(violations),
+	)
+	return true, nil
+}
+
+// absorb объединяет событие с ожидающей записью: изменения складываются, нарушения - по последнему тексту
+// Исправленный текст без нарушений не снимает запись - её закрывает администратор
+func (s *ModerationService) absorb(ctx context.Context, pending *models.BookingQuarantine, event BookingEvent, violations []ContentViolation) error {
+	changes := pending.Changes
+	if pending.Event == string(EventBookingUpdated) && event.Type == EventBookingUpdated {
+		var held []FieldChange
+		if len(pending.Changes) > 0 {
+			if err := json.Unmarshal(pending.Changes, &held); err != nil {
+				return err
+			}
+		}
+		merged, err := json.Marshal(mergeChanges(held, event.Changes))
+		if err != nil {
+			return err
+		}
+		changes = merged
+	}
+
+	found := pending.Violations
+	if len(violations) > 0 {
+		var err error
+		if found, err = json.Marshal(violations); err != nil {
+			return err
+		}
+	}
+	return s.quarantineRepo.UpdateHeld(ctx, pending.ID, changes, found)
+}
+
+// mergeChanges дополняет задержанные изменения более поздними: для уже изменённого поля
+// остаётся исходное старое значение и последнее новое
+func mergeChanges(held, later []FieldChange) []FieldChange {
+	merged := append([]FieldChange(nil), held...)
+	for _, change := range later {
+		replaced := false
+		for i := range merged {
+			if merged[i].Field == change.Field {
+				merged[i].New = change.New
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, change)
+		}
+	}
+	return merged
+}
+
+// changesText - изменились название или описание
+func changesText(changes []FieldChange) bool {
+	for _, change := range changes {
+		if change.Field == "title" || change.Field == "description" {
+			return true
+		}
+	}
+	return false
+}
+
+// List returns a page of the quarantine, newest first; empty status - all entries
+func (s *ModerationService) List(ctx context.Context, status models.QuarantineStatus, limit, offset int) ([]models.BookingQuarantine, int64, error) {
+	switch status {
+	case "", models.QuarantinePending, models.QuarantineReleased, models.QuarantineRejected:
+	default:
+		return nil, 0, ErrInvalidQuarantineStatus
+	}
+	limit, offset = pageBounds(limit, offset, DefaultListPageSize, MaxListPageSize)
+	return s.quarantineRepo.List(ctx, status, limit, offset)
+}
+
+// Release marks an entry as a false positive and broadcasts the held event with the current booking
+// Отменённое тем временем бронирование не рассылается
+func (s *ModerationService) Release(ctx context.Context, id, reviewerID uint) (*models.BookingQuarantine, error) {
+	entry, err := s.review(ctx, id, reviewerID, models.QuarantineReleased)
+	if err != nil {
+		return nil, err
+	}
+
+	booking, err := s.bookingRepo.GetByID(ctx, entry.BookingID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entry, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if booking.Status == models.BookingStatusCancelled {
+		return entry, nil
+	}
+
+	var changes []FieldChange
+	if len(entry.Changes) > 0 {
+		if err := json.Unmarshal(entry.Changes, &changes); err != nil {
+			return nil, err
+		}
+	}
+	s.events.Publish(BookingEvent{Type: BookingEventType(entry.Event), Booking: booking, Changes: changes})
+	return entry, nil
+}
+
+// Reject closes an entry without broadcasting the event
+// Бронирование остаётся; при необходимости администратор отменяет его отдельно
+func (s *ModerationService) Reject(ctx context.Context, id, reviewerID uint) (*models.BookingQuarantine, error) {
+	return s.review(ctx, id, reviewerID, models.QuarantineRejected)
+}
+
+func (s *ModerationService) review(ctx context.Context, id, reviewerID uint, status models.QuarantineStatus) (*models.BookingQuarantine, error) {
+	reviewed, err := s.quarantineRepo.Review(ctx, id, status, reviewerID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	entry, err := s.quarantineRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !reviewed {
+		return nil, ErrQuarantineReviewed
+	}
+	return entry, nil
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/pkg/contentfilter"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type fakeQuarantineStore struct {
+	QuarantineStore
+	entries map[uint]*models.BookingQuarantine
+}
+
+func (f *fakeQuarantineStore) GetByID(ctx context.Context, id uint) (*models.BookingQuarantine, error) {
+	entry, ok := f.entries[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *entry
+	return &copied, nil
+}
+
+func (f *fakeQuarantineStore) GetPendingByBooking(ctx context.Context, bookingID uint) (*models.BookingQuarantine, error) {
+	for _, entry := range f.entries {
+		if entry.BookingID == bookingID && entry.Status == models.QuarantinePending {
+			copied := *entry
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeQuarantineStore) Create(ctx context.Context, entry *models.BookingQuarantine) (bool, error) {
+	entry.ID = uint(len(f.entries) + 1)
+	stored := *entry
+	f.entries[entry.ID] = &stored
+	return true, nil
+}
+
+func (f *fakeQuarantineStore) UpdateHeld(ctx context.Context, id uint, changes, violations datatypes.JSON) error {
+	f.entries[id].Changes = changes
+	f.entries[id].Violations = violations
+	return nil
+}
+
+func (f *fakeQuarantineStore) Review(ctx context.Context, id uint, status models.QuarantineStatus, reviewerID uint, now time.Time) (bool, error) {
+	entry, ok := f.entries[id]
+	if !ok || entry.Status != models.QuarantinePending {
+		return false, nil
+	}
+	entry.Status = status
+	entry.ReviewedByID = &reviewerID
+	entry.ReviewedAt = &now
+	return true, nil
+}
+
+func TestModerationService_HoldAndReview(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	store := newFakeBookingStore()
+	rooms := &fakeRoomStore{rooms: map[uint]*models.Room{1: {ID: 1, Name: "Room 1", IsActive: true}}}
+	users := &fakeUserStore{users: map[uint]*models.User{10: {ID: 10, Role: models.RoleUser}}}
+	events := NewEventBus(inlineTaskQueue{}, slog.Default())
+	recorder := &recordingEventSubscriber{}
+	events.Subscribe("recorder", recorder)
+	quarantine := &fakeQuarantineStore{entries: map[uint]*models.BookingQuarantine{}}
+	filter := contentfilter.New(contentfilter.Config{Words: []string{"casino*"}, MaxRepeat: 5})
+	moderation := NewModerationService(quarantine, store, filter, events, slog.Default())
+	svc := NewBookingService(fakeTx{}, store, rooms, users, nil, nil, events, slog.Default())
+	svc.SetModerator(moderation)
+	ctx := context.Background()
+
+	create := func(title string, hour int) *models.Booking {
+		from := start.Add(time.Duration(hour) * time.Hour)
+		booking, err := svc.CreateBooking(ctx, 10, CreateBookingRequest{RoomID: 1, StartTime: from, EndTime: from.Add(time.Hour), Title: title})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		return booking
+	}
+
+	create("Planning", 0)
+	if len(recorder.events) != 1 || len(quarantine.entries) != 0 {
+		t.Fatalf("Expected a clean booking to be broadcast, got events %+v and entries %+v", recorder.events, quarantine.entries)
+	}
+
+	flagged := create("Casinos tonight!!!!!!", 2)
+	if len(recorder.events) != 1 {
+		t.Fatalf("Expected a flagged booking to be held, got: %+v", recorder.events)
+	}
+	entry := quarantine.entries[1]
+	var violations []ContentViolation
+	if err := json.Unmarshal(entry.Violations, &violations); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if entry.BookingID != flagged.ID || entry.Event != string(EventBookingCreated) || len(violations) != 2 ||
+		violations[0].Field != "title" || violations[0].Rule != contentfilter.RuleWordlist || violations[1].Rule != contentfilter.RuleRepeat {
+		t.Errorf("Expected a pending booking.created entry with wordlist and repeat violations, got: %+v %+v", entry, violations)
+	}
+
+	// Пока запись ждёт проверки, перенос времени тоже задерживается и не создаёт новую запись
+	newStart, newEnd := flagged.StartTime.Add(time.Hour), flagged.EndTime.Add(time.Hour)
+	if _, err := svc.UpdateBooking(ctx, flagged.ID, 10, UpdateBookingRequest{StartTime: &newStart, EndTime: &newEnd}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(recorder.events) != 1 || len(quarantine.entries) != 1 {
+		t.Errorf("Expected the update to be absorbed by the pending entry, got events %+v and entries %+v", recorder.events, quarantine.entries)
+	}
+
+	released, err := moderation.Release(ctx, entry.ID, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if released.Status != models.QuarantineReleased || released.ReviewedByID == nil || *released.ReviewedByID != 1 {
+		t.Errorf("Expected a released entry, got: %+v", released)
+	}
+	if len(recorder.events) != 2 || recorder.events[1].Type != EventBookingCreated || !recorder.events[1].Booking.StartTime.Equal(newStart) {
+		t.Fatalf("Expected the held booking.created with the current time, got: %+v", recorder.events)
+	}
+	if _, err := moderation.Reject(ctx, entry.ID, 1); !errors.Is(err, ErrQuarantineReviewed) {
+		t.Errorf("Expected ErrQuarantineReviewed, got: %v", err)
+	}
+
+	// Изменение без нового текста у отпущенного бронирования рассылается сразу
+	later, laterEnd := newStart.Add(time.Hour), newEnd.Add(time.Hour)
+	if _, err := svc.UpdateBooking(ctx, flagged.ID, 10, UpdateBookingRequest{StartTime: &later, EndTime: &laterEnd}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(recorder.events) != 3 || recorder.events[2].Type != EventBookingUpdated {
+		t.Errorf("Expected a booking.updated event, got: %+v", recorder.events)
+	}
+
+	// Новый подозрительный текст снова попадает в карантин; отклонение ничего не рассылает
+	title := "CASINO"
+	if _, err := svc.UpdateBooking(ctx, flagged.ID, 10, UpdateBookingRequest{Title: &title}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(recorder.events) != 3 || len(quarantine.entries) != 2 {
+		t.Fatalf("Expected the title change to be held, got events %+v and entries %+v", recorder.events, quarantine.entries)
+	}
+	rejected, err := moderation.Reject(ctx, 2, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if rejected.Status != models.QuarantineRejected || len(recorder.events) != 3 {
+		t.Errorf("Expected a rejected entry without a broadcast, got: %+v %+v", rejected, recorder.events)
+	}
+
+	if _, _, err := moderation.List(ctx, "unknown", 0, 0); !errors.Is(err, ErrInvalidQuarantineStatus) {
+		t.Errorf("Expected ErrInvalidQuarantineStatus, got: %v", err)
+	}
+	if _, err := moderation.Release(ctx, 99, 1); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected gorm.ErrRecordNotFound, got: %v", err)
+	}
+}
+
+func TestModerationService_SetEnabled(t *testing.T) {
+	quarantine := &fakeQuarantineStore{entries: map[uint]*models.BookingQuarantine{}}
+	filter := contentfilter.New(contentfilter.Config{Words: []string{"casino*"}})
+	moderation := NewModerationService(quarantine, newFakeBookingStore(), filter, NewEventBus(inlineTaskQueue{}, slog.Default()), slog.Default())
+	event := BookingEvent{Type: EventBookingCreated, Booking: &models.Booking{ID: 1, Title: "Casino"}}
+
+	moderation.SetEnabled(false)
+	if held, err := moderation.Hold(context.Background(), event); err != nil || held {
+		t.Fatalf("Expected a disabled filter not to hold events, got: %v %v", held, err)
+	}
+
+	moderation.SetEnabled(true)
+	if held, err := moderation.Hold(context.Background(), event); err != nil || !held {
+		t.Fatalf("Expected an enabled filter to hold the flagged event, got: %v %v", held, err)
+	}
+	if len(quarantine.entries) != 1 {
+		t.Errorf("Expected one quarantine entry, got: %+v", quarantine.entries)
+	}
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/space/backend/internal/repository"
+)
+
+// Параметры отчёта о неявках по умолчанию
+const (
+	DefaultNoShowMinBookings = 3  // Меньше бронирований - доля неявок ничего не говорит
+	DefaultNoShowLimit       = 20 // Строк в каждом списке
+	MaxNoShowLimit           = 200
+)
+
+// NoShowStats is the share of finished bookings of a user or a room nobody checked in to
+type NoShowStats struct {
+	ID            uint   `json:"id"`
+	Name          string `json:"name"`
+	Bookings      int    `json:"bookings"`        // Закончившихся бронирований в комнатах с отметками
+	NoShows       int    `json:"no_shows"`        // Бронирований без единой отметки о приходе
+	NoShowPercent int    `json:"no_show_percent"` // Доля неявок, %
+}
+
+// NoShowReport lists users and rooms with the highest no-show rates over a period
+type NoShowReport struct {
+	Since time.Time     `json:"since"`
+	Until time.Time     `json:"until"`
+	Users []NoShowStats `json:"users"` // Создатели бронирований
+	Rooms []NoShowStats `json:"rooms"`
+}
+
+// NoShowQuery selects the period and the size of a no-show report
+type NoShowQuery struct {
+	Since       time.Time
+	Until       time.Time
+	MinBookings int // <= 0 - DefaultNoShowMinBookings
+	Limit       int // <= 0 - DefaultNoShowLimit
+}
+
+// NoShowService reports bookings nobody came to, so that chronically wasted slots can be reclaimed
+// Неявка - закончившееся бронирование комнаты без единой отметки по QR-коду. Комнаты, в которых за период
+// никто не отметился, не учитываются: отметки там не используются, и все бронирования выглядели бы неявками
+type NoShowService struct {
+	attendanceRepo AttendanceStore
+	roomRepo       RoomStore
+	userRepo       UserStore
+}
+
+// NewNoShowService creates a new no-show report service
+func NewNoShowService(attendanceRepo AttendanceStore, roomRepo RoomStore, userRepo UserStore) *NoShowService {
+	return &NoShowService{attendanceRepo: attendanceRepo, roomRepo: roomRepo, userRepo: userRepo}
+}
+
+// Report gets users and rooms with at least MinBookings finished bookings in the period, highest no-show rate first
+// Неявка засчитывается создателю бронирования; строки без неявок в отчёт не попадают
+func (s *NoShowService) Report(ctx context.Context, query NoShowQuery) (*NoShowReport, error) {
+	if !query.Until.After(query.Since) {
+		return nil, ErrInvalidTime
+	}
+	if query.MinBookings <= 0 {
+		query.MinBookings = DefaultNoShowMinBookings
+	}
+	limit, _ := pageBounds(query.Limit, 0, DefaultNoShowLimit, MaxNoShowLimit)
+
+	attendance, err := s.attendanceRepo.GetAttendance(ctx, query.Since, query.Until)
+	if err != nil {
+		return nil, err
+	}
+
+	checkedRooms := map[uint]bool{}
+	for _, a := range attendance {
+		if a.CheckInCount > 0 {
+			checkedRooms[a.RoomID] = true
+		}
+	}
+	type totals struct{ bookings, noShows int }
+	byRoom := map[uint]*totals{}
+	byUser := map[uint]*totals{}
+	count := func(m map[uint]*totals, id uint, a repository.BookingAttendance) {
+		t := m[id]
+		if t == nil {
+			t = &totals{}
+			m[id] = t
+		}
+		t.bookings++
+		if a.CheckInCount == 0 {
+			t.noShows++
+		}
+	}
+	for _, a := range attendance {
+		if !checkedRooms[a.RoomID] {
+			continue
+		}
+		count(byRoom, a.RoomID, a)
+		count(byUser, a.CreatorID, a)
+	}
+
+	// Неактивные комнаты и удалённые пользователи в отчёт не попадают: освобождать нечего
+	rank := func(m map[uint]*totals, names map[uint]string) []NoShowStats {
+		stats := []NoShowStats{}
+		for id, t := range m {
+			name, ok := names[id]
+			if !ok || t.bookings < query.MinBookings || t.noShows == 0 {
+				continue
+			}
+			stats = append(stats, NoShowStats{
+				ID:            id,
+				Name:          name,
+				Bookings:      t.bookings,
+				NoShows:       t.noShows,
+				NoShowPercent: int(math.Round(float64(t.noShows) * 100 / float64(t.bookings))),
+			})
+		}
+		sort.Slice(stats, func(i, j int) bool {
+			a, b := stats[i], stats[j]
+			if a.NoShowPercent != b.NoShowPercent {
+				return a.NoShowPercent > b.NoShowPercent
+			}
+			if a.NoShows != b.NoShows {
+				return a.NoShows > b.NoShows
+			}
+			return a.ID < b.ID
+		})
+		if len(stats) > limit {
+			stats = stats[:limit]
+		}
+		return stats
+	}
+
+	rooms, err := s.roomRepo.GetAll(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	roomNames := make(map[uint]string, len(rooms))
+	for _, room := range rooms {
+		roomNames[room.ID] = room.Name
+	}
+	userIDs := make([]uint, 0, len(byUser))
+	for id := range byUser {
+		userIDs = append(userIDs, id)
+	}
+	users, err := s.userRepo.GetByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	userNames := make(map[uint]string, len(users))
+	for i := range users {
+		userNames[users[i].ID] = userDisplayName(&users[i])
+	}
+
+	return &NoShowReport{
+		Since: query.Since,
+		Until: query.Until,
+		Users: rank(byUser, userNames),
+		Rooms: rank(byRoom, roomNames),
+	}, nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/internal/repository"
+)
+
+func TestNoShowService_Report(t *testing.T) {
+	rooms := &fakeRoomStateRoomStore{&fakeRoomStore{rooms: map[uint]*models.Room{
+		1: {ID: 1, Name: "Hall", IsActive: true},
+		2: {ID: 2, Name: "Booth", IsActive: true},
+		3: {ID: 3, Name: "Lobby", IsActive: true},
+		4: {ID: 4, Name: "Closed", IsActive: false},
+	}}}
+	users := &fakeUserStore{users: map[uint]*models.User{
+		10: {ID: 10, FirstName: "Anna", LastName: "Petrova"},
+		20: {ID: 20, Username: "ivan"},
+		30: {ID: 30, Username: "olga"},
+	}}
+	// В зале пропускают 2 из 3 встреч, в будке - 1 из 3; в лобби отметками не пользуются
+	store := &fakeAttendanceStore{attendance: []repository.BookingAttendance{
+		{BookingID: 1, RoomID: 1, CreatorID: 10},
+		{BookingID: 2, RoomID: 1, CreatorID: 10},
+		{BookingID: 3, RoomID: 1, CreatorID: 20, CheckInCount: 2},
+		{BookingID: 4, RoomID: 2, CreatorID: 10, CheckInCount: 1},
+		{BookingID: 5, RoomID: 2, CreatorID: 20},
+		{BookingID: 6, RoomID: 2, CreatorID: 20, CheckInCount: 1},
+		{BookingID: 7, RoomID: 3, CreatorID: 30},
+		{BookingID: 8, RoomID: 3, CreatorID: 30},
+		{BookingID: 9, RoomID: 3, CreatorID: 30},
+		{BookingID: 10, RoomID: 4, CreatorID: 30},
+		{BookingID: 11, RoomID: 4, CreatorID: 30, CheckInCount: 1},
+		{BookingID: 12, RoomID: 4, CreatorID: 30},
+	}}
+	svc := NewNoShowService(store, rooms, users)
+	ctx := context.Background()
+	until := time.Now()
+	since := until.Add(-30 * 24 * time.Hour)
+
+	report, err := svc.Report(ctx, NoShowQuery{Since: since, Until: until})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(report.Rooms) != 2 || report.Rooms[0].ID != 1 || report.Rooms[1].ID != 2 {
+		t.Fatalf("Expected the hall and the booth, highest rate first, got: %+v", report.Rooms)
+	}
+	if hall := report.Rooms[0]; hall.Name != "Hall" || hall.Bookings != 3 || hall.NoShows != 2 || hall.NoShowPercent != 67 {
+		t.Errorf("Unexpected hall no-shows: %+v", hall)
+	}
+	// Бронирования лобби не учитываются, закрытой комнаты - учитываются только у создателя
+	if len(report.Users) != 3 || report.Users[0].ID != 10 || report.Users[1].ID != 30 || report.Users[2].ID != 20 {
+		t.Fatalf("Expected anna and olga tied by rate, then ivan, got: %+v", report.Users)
+	}
+	if anna := report.Users[0]; anna.Name != "Anna Petrova" || anna.Bookings != 3 || anna.NoShows != 2 {
+		t.Errorf("Unexpected user no-shows: %+v", anna)
+	}
+
+	report, err = svc.Report(ctx, NoShowQuery{Since: since, Until: until, MinBookings: 4, Limit: 1})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(report.Rooms) != 0 || len(report.Users) != 0 {
+		t.Errorf("Expected nothing with at least 4 bookings, got: %+v", report)
+	}
+	report, _ = svc.Report(ctx, NoShowQuery{Since: since, Until: until, Limit: 1})
+	if len(report.Rooms) != 1 || len(report.Users) != 1 {
+		t.Errorf("Expected one row per list, got: %+v", report)
+	}
+
+	if _, err := svc.Report(ctx, NoShowQuery{Since: until, Until: since}); !errors.Is(err, ErrInvalidTime) {
+		t.Errorf("Expected ErrInvalidTime, got: %v", err)
+	}
+}
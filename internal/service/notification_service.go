@@ -2,60 +2,74 @@ package service
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"html"
+	"io"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/space/backend/internal/buildinfo"
 	"github.com/space/backend/internal/config"
 	"github.com/space/backend/internal/models"
-	"github.com/space/backend/internal/repository"
+	"gorm.io/gorm"
 )
 
 type NotificationService struct {
-	notificationRepo *repository.NotificationRepository
-	roomRepo         *repository.RoomRepository
-	config           *config.Config
+	notificationRepo NotificationStore
+	roomRepo         RoomReader
+	config           *config.Live
+	httpClient       *http.Client // Переиспользуется между запросами (keep-alive)
+	logger           *slog.Logger
 }
 
-func NewNotificationService(notificationRepo *repository.NotificationRepository, roomRepo *repository.RoomRepository, cfg *config.Config) *NotificationService {
+func NewNotificationService(notificationRepo NotificationStore, roomRepo RoomReader, cfg *config.Live, logger *slog.Logger) *NotificationService {
 	return &NotificationService{
 		notificationRepo: notificationRepo,
 		roomRepo:         roomRepo,
 		config:           cfg,
+		httpClient:       &http.Client{Timeout: cfg.Get().BotWebhookTimeout},
+		logger:           logger,
 	}
 }
 
 // Subscribe subscribes a user to room notifications
-func (s *NotificationService) Subscribe(userID uint, roomID uint) error {
+func (s *NotificationService) Subscribe(ctx context.Context, userID uint, roomID uint) error {
 	// Проверяем что комната существует
-	_, err := s.roomRepo.GetByID(roomID)
+	_, err := s.roomRepo.GetByID(ctx, roomID)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRoomNotFound
+		}
 		return err
 	}
 
-	return s.notificationRepo.Subscribe(userID, roomID)
+	return s.notificationRepo.Subscribe(ctx, userID, roomID)
 }
 
 // Unsubscribe unsubscribes a user from room notifications
-func (s *NotificationService) Unsubscribe(userID uint, roomID uint) error {
-	return s.notificationRepo.Unsubscribe(userID, roomID)
+func (s *NotificationService) Unsubscribe(ctx context.Context, userID uint, roomID uint) error {
+	return s.notificationRepo.Unsubscribe(ctx, userID, roomID)
 }
 
 // GetUserSubscriptions returns all rooms a user is subscribed to
-func (s *NotificationService) GetUserSubscriptions(userID uint) ([]models.NotificationSubscription, error) {
-	return s.notificationRepo.GetUserSubscriptions(userID)
+func (s *NotificationService) GetUserSubscriptions(ctx context.Context, userID uint) ([]models.NotificationSubscription, error) {
+	return s.notificationRepo.GetUserSubscriptions(ctx, userID)
 }
 
 // GetRoomSubscribers returns all users subscribed to a room
-func (s *NotificationService) GetRoomSubscribers(roomID uint) ([]models.NotificationSubscription, error) {
-	return s.notificationRepo.GetRoomSubscribers(roomID)
+func (s *NotificationService) GetRoomSubscribers(ctx context.Context, roomID uint) ([]models.NotificationSubscription, error) {
+	return s.notificationRepo.GetRoomSubscribers(ctx, roomID)
 }
 
 // IsSubscribed checks if a user is subscribed to a room
-func (s *NotificationService) IsSubscribed(userID uint, roomID uint) (bool, error) {
-	return s.notificationRepo.IsSubscribed(userID, roomID)
+func (s *NotificationService) IsSubscribed(ctx context.Context, userID uint, roomID uint) (bool, error) {
+	return s.notificationRepo.IsSubscribed(ctx, userID, roomID)
 }
 
 // BookingWebhookData represents booking data for webhook
@@ -82,24 +96,252 @@ type BookingCreatedWebhook struct {
 	Event       string                  `json:"event"`
 	Booking     BookingWebhookData      `json:"booking"`
 	Subscribers []SubscriberWebhookData `json:"subscribers"`
+	Meta        WebhookMeta             `json:"meta"`
+}
+
+// BookingReleasedWebhook represents the webhook payload sent to the creator of a booking an admin ended early
+type BookingReleasedWebhook struct {
+	Event      string                  `json:"event"`
+	Booking    BookingWebhookData      `json:"booking"` // end_time - момент освобождения
+	Recipients []SubscriberWebhookData `json:"recipients"`
+	Meta       WebhookMeta             `json:"meta"`
+}
+
+// BookingWaitlistPromotedWebhook represents the webhook payload sent to a user who got a seat from the waitlist
+type BookingWaitlistPromotedWebhook struct {
+	Event      string                  `json:"event"`
+	Booking    BookingWebhookData      `json:"booking"`
+	Recipients []SubscriberWebhookData `json:"recipients"` // Получивший место
+	Meta       WebhookMeta             `json:"meta"`
+}
+
+// BookingUpdatedWebhook represents the webhook payload sent to the members of a changed booking
+type BookingUpdatedWebhook struct {
+	Event      string                  `json:"event"`
+	Booking    BookingWebhookData      `json:"booking"` // Состояние после изменения
+	Changes    []FieldChange           `json:"changes"` // Например [{"field":"start_time","old":"...T15:00:00Z","new":"...T16:00:00Z"}]
+	Recipients []SubscriberWebhookData `json:"recipients"`
+	Meta       WebhookMeta             `json:"meta"`
+}
+
+// BookingStartingWebhook represents the webhook payload announcing a booking about to start in the group chat
+// Отправляется вместе с напоминанием (BOOKING_REMINDER_LEAD) для комнат с announce_start
+type BookingStartingWebhook struct {
+	Event        string                  `json:"event"`
+	Booking      BookingWebhookData      `json:"booking"`
+	Participants []SubscriberWebhookData `json:"participants"` // Создатель и участники с Telegram, без повторов
+	Text         string                  `json:"text"`         // Готовое объявление для parse_mode=HTML с упоминаниями участников
+	Meta         WebhookMeta             `json:"meta"`
+}
+
+// RoomBroadcastWebhook represents the webhook payload for an admin announcement to room subscribers
+type RoomBroadcastWebhook struct {
+	Event       string                  `json:"event"`
+	RoomID      uint                    `json:"room_id"`
+	RoomName    string                  `json:"room_name"`
+	Message     string                  `json:"message"`
+	Subscribers []SubscriberWebhookData `json:"subscribers"`
+	Meta        WebhookMeta             `json:"meta"`
+}
+
+// WebhookMeta identifies the backend build that sent a webhook
+type WebhookMeta struct {
+	Version string    `json:"version"`
+	Commit  string    `json:"commit,omitempty"`
+	SentAt  time.Time `json:"sent_at"`
+}
+
+// newWebhookMeta описывает сборку, отправившую webhook
+func newWebhookMeta() WebhookMeta {
+	build := buildinfo.Get()
+	return WebhookMeta{
+		Version: build.Version,
+		Commit:  build.Commit,
+		SentAt:  time.Now().UTC(),
+	}
+}
+
+// HandleBookingEvent delivers booking events to the bot webhook
+// Бот принимает уведомления о новых и изменённых бронированиях, о досрочном освобождении комнаты,
+// о месте, полученном из очереди,
+// и объявления о скором начале бронирований в комнатах с announce_start
+func (s *NotificationService) HandleBookingEvent(ctx context.Context, event BookingEvent) error {
+	switch event.Type {
+	case EventBookingCreated:
+		return s.NotifyBookingCreated(ctx, event.Booking)
+	case EventBookingReleased:
+		return s.NotifyBookingReleased(ctx, event.Booking)
+	case EventBookingUpdated:
+		return s.NotifyBookingUpdated(ctx, event.Booking, event.Changes)
+	case EventBookingWaitlistPromoted:
+		return s.NotifyWaitlistPromoted(ctx, event.Booking, event.User)
+	case EventBookingReminder:
+		if event.Booking.Room.AnnounceStart {
+			return s.AnnounceBookingStarting(ctx, event.Booking)
+		}
+	}
+	return nil
 }
 
 // NotifyBookingCreated sends a webhook notification to the bot about a new booking
-func (s *NotificationService) NotifyBookingCreated(booking *models.Booking) error {
-	// Получаем подписчиков на комнату
-	subscriptions, err := s.GetRoomSubscribers(booking.RoomID)
+func (s *NotificationService) NotifyBookingCreated(ctx context.Context, booking *models.Booking) error {
+	// Получаем контакты подписчиков комнаты (только нужные колонки)
+	contacts, err := s.notificationRepo.GetRoomSubscriberContacts(ctx, booking.RoomID)
 	if err != nil {
-		log.Printf("Failed to get room subscribers: %v", err)
+		s.logger.Error("failed to get room subscribers", "room_id", booking.RoomID, "error", err)
 		return err
 	}
 
 	// Если нет подписчиков, не отправляем уведомление
-	if len(subscriptions) == 0 {
-		log.Printf("No subscribers for room %d, skipping notification", booking.RoomID)
+	if len(contacts) == 0 {
+		s.logger.Debug("no subscribers for room, skipping notification", "room_id", booking.RoomID)
 		return nil
 	}
 
-	// Формируем данные о бронировании
+	// Создаем webhook payload
+	webhook := BookingCreatedWebhook{
+		Event:       "booking.created",
+		Booking:     bookingWebhookData(booking),
+		Subscribers: subscriberWebhookData(contacts),
+		Meta:        newWebhookMeta(),
+	}
+
+	// Отправляем webhook
+	return s.sendWebhook(ctx, webhook, "booking_id", booking.ID)
+}
+
+// NotifyBookingReleased tells the creator through the bot that an admin ended the booking early
+// Создатель без Telegram (вход через OIDC) уведомление не получает
+func (s *NotificationService) NotifyBookingReleased(ctx context.Context, booking *models.Booking) error {
+	if booking.Creator.TelegramID == 0 {
+		s.logger.Debug("booking creator has no Telegram account, skipping release notification", "booking_id", booking.ID)
+		return nil
+	}
+
+	recipient := models.RoomSubscriber{
+		UserID:     booking.Creator.ID,
+		TelegramID: booking.Creator.TelegramID,
+		Username:   booking.Creator.Username,
+		FirstName:  booking.Creator.FirstName,
+	}
+	webhook := BookingReleasedWebhook{
+		Event:      string(EventBookingReleased),
+		Booking:    bookingWebhookData(booking),
+		Recipients: subscriberWebhookData([]models.RoomSubscriber{recipient}),
+		Meta:       newWebhookMeta(),
+	}
+	return s.sendWebhook(ctx, webhook, "booking_id", booking.ID)
+}
+
+// NotifyWaitlistPromoted tells a user through the bot that they got a seat from the waitlist
+// Пользователь без Telegram (вход через OIDC) уведомление не получает
+func (s *NotificationService) NotifyWaitlistPromoted(ctx context.Context, booking *models.Booking, user *models.User) error {
+	if user == nil || user.TelegramID == 0 {
+		s.logger.Debug("promoted user has no Telegram account, skipping waitlist notification", "booking_id", booking.ID)
+		return nil
+	}
+
+	recipient := models.RoomSubscriber{
+		UserID:     user.ID,
+		TelegramID: user.TelegramID,
+		Username:   user.Username,
+		FirstName:  user.FirstName,
+	}
+	webhook := BookingWaitlistPromotedWebhook{
+		Event:      string(EventBookingWaitlistPromoted),
+		Booking:    bookingWebhookData(booking),
+		Recipients: subscriberWebhookData([]models.RoomSubscriber{recipient}),
+		Meta:       newWebhookMeta(),
+	}
+	return s.sendWebhook(ctx, webhook, "booking_id", booking.ID, "user_id", user.ID)
+}
+
+// NotifyBookingUpdated tells the creator and participants through the bot what changed in a booking
+func (s *NotificationService) NotifyBookingUpdated(ctx context.Context, booking *models.Booking, changes []FieldChange) error {
+	recipients := bookingParticipants(booking)
+	if len(recipients) == 0 {
+		s.logger.Debug("no booking members with Telegram, skipping update notification", "booking_id", booking.ID)
+		return nil
+	}
+
+	webhook := BookingUpdatedWebhook{
+		Event:      string(EventBookingUpdated),
+		Booking:    bookingWebhookData(booking),
+		Changes:    changes,
+		Recipients: subscriberWebhookData(recipients),
+		Meta:       newWebhookMeta(),
+	}
+	return s.sendWebhook(ctx, webhook, "booking_id", booking.ID)
+}
+
+// AnnounceBookingStarting sends the bot an announcement of a booking about to start for the group chat
+func (s *NotificationService) AnnounceBookingStarting(ctx context.Context, booking *models.Booking) error {
+	loc := roomLocation(&booking.Room)
+	if loc == nil {
+		var err error
+		if loc, err = time.LoadLocation(s.config.Get().OfficeTimezone); err != nil {
+			loc = time.UTC
+		}
+	}
+
+	participants := bookingParticipants(booking)
+	webhook := BookingStartingWebhook{
+		Event:        "booking.starting",
+		Booking:      bookingWebhookData(booking),
+		Participants: subscriberWebhookData(participants),
+		Text:         formatStartingAnnouncement(booking, participants, loc),
+		Meta:         newWebhookMeta(),
+	}
+	return s.sendWebhook(ctx, webhook, "booking_id", booking.ID)
+}
+
+// bookingParticipants возвращает создателя и участников бронирования с Telegram, без повторов
+// Пользователи без Telegram (вход через OIDC) упомянуть в чате нельзя
+func bookingParticipants(booking *models.Booking) []models.RoomSubscriber {
+	users := append([]models.User{booking.Creator}, booking.Participants...)
+	result := make([]models.RoomSubscriber, 0, len(users))
+	seen := make(map[int64]bool, len(users))
+	for _, u := range users {
+		if u.TelegramID == 0 || seen[u.TelegramID] {
+			continue
+		}
+		seen[u.TelegramID] = true
+		result = append(result, models.RoomSubscriber{
+			UserID:     u.ID,
+			TelegramID: u.TelegramID,
+			Username:   u.Username,
+			FirstName:  u.FirstName,
+		})
+	}
+	return result
+}
+
+// formatStartingAnnouncement формирует объявление в разметке Telegram HTML
+// Участники упоминаются ссылкой tg://user: упоминание работает и без username
+func formatStartingAnnouncement(booking *models.Booking, participants []models.RoomSubscriber, loc *time.Location) string {
+	text := fmt.Sprintf("⏰ Starting soon in <b>%s</b>: <b>%s</b>\n%s–%s",
+		html.EscapeString(booking.Room.Name), html.EscapeString(booking.Title),
+		booking.StartTime.In(loc).Format("15:04"), booking.EndTime.In(loc).Format("15:04"))
+
+	mentions := make([]string, len(participants))
+	for i, p := range participants {
+		name := p.FirstName
+		if name == "" && p.Username != "" {
+			name = "@" + p.Username
+		}
+		if name == "" {
+			name = strconv.FormatInt(p.TelegramID, 10)
+		}
+		mentions[i] = fmt.Sprintf(`<a href="tg://user?id=%d">%s</a>`, p.TelegramID, html.EscapeString(name))
+	}
+	if len(mentions) > 0 {
+		text += "\n" + strings.Join(mentions, ", ")
+	}
+	return text
+}
+
+// bookingWebhookData формирует данные о бронировании для webhook бота
+func bookingWebhookData(booking *models.Booking) BookingWebhookData {
 	creatorName := booking.Creator.FirstName
 	if booking.Creator.LastName != "" {
 		creatorName += " " + booking.Creator.LastName
@@ -110,7 +352,7 @@ func (s *NotificationService) NotifyBookingCreated(booking *models.Booking) erro
 		creatorTelegramID = &booking.Creator.TelegramID
 	}
 
-	webhookBooking := BookingWebhookData{
+	return BookingWebhookData{
 		BookingID:         booking.ID,
 		RoomID:            booking.RoomID,
 		RoomName:          booking.Room.Name,
@@ -120,80 +362,111 @@ func (s *NotificationService) NotifyBookingCreated(booking *models.Booking) erro
 		CreatorName:       creatorName,
 		CreatorTelegramID: creatorTelegramID,
 	}
+}
 
-	// Формируем список подписчиков
-	subscribers := make([]SubscriberWebhookData, 0, len(subscriptions))
-	for _, sub := range subscriptions {
-		if sub.User != nil && sub.User.TelegramID != 0 {
-			var username *string
-			if sub.User.Username != "" {
-				username = &sub.User.Username
-			}
-
-			var firstName *string
-			if sub.User.FirstName != "" {
-				firstName = &sub.User.FirstName
-			}
-
-			subscribers = append(subscribers, SubscriberWebhookData{
-				TelegramID: sub.User.TelegramID,
-				Username:   username,
-				FirstName:  firstName,
-			})
-		}
+// HandleRoomBroadcast sends an admin announcement to the room's subscribers through the bot webhook
+func (s *NotificationService) HandleRoomBroadcast(ctx context.Context, broadcast RoomBroadcast) error {
+	contacts, err := s.notificationRepo.GetRoomSubscriberContacts(ctx, broadcast.Room.ID)
+	if err != nil {
+		return err
+	}
+	if len(contacts) == 0 {
+		s.logger.Debug("no subscribers for room, skipping broadcast", "room_id", broadcast.Room.ID)
+		return nil
 	}
 
-	// Создаем webhook payload
-	webhook := BookingCreatedWebhook{
-		Event:       "booking.created",
-		Booking:     webhookBooking,
-		Subscribers: subscribers,
+	webhook := RoomBroadcastWebhook{
+		Event:       "room.broadcast",
+		RoomID:      broadcast.Room.ID,
+		RoomName:    broadcast.Room.Name,
+		Message:     broadcast.Message,
+		Subscribers: subscriberWebhookData(contacts),
+		Meta:        newWebhookMeta(),
 	}
+	return s.sendWebhook(ctx, webhook, "room_id", broadcast.Room.ID)
+}
 
-	// Отправляем webhook
-	return s.sendWebhook(webhook)
+// subscriberWebhookData формирует список подписчиков для webhook бота
+func subscriberWebhookData(contacts []models.RoomSubscriber) []SubscriberWebhookData {
+	subscribers := make([]SubscriberWebhookData, len(contacts))
+	for i := range contacts {
+		contact := &contacts[i]
+		subscribers[i] = SubscriberWebhookData{TelegramID: contact.TelegramID}
+		if contact.Username != "" {
+			subscribers[i].Username = &contact.Username
+		}
+		if contact.FirstName != "" {
+			subscribers[i].FirstName = &contact.FirstName
+		}
+	}
+	return subscribers
 }
 
-// sendWebhook sends webhook data to the bot
-func (s *NotificationService) sendWebhook(webhook BookingCreatedWebhook) error {
-	// Формируем URL
-	webhookURL := fmt.Sprintf("%s/webhook/booking/created", s.config.BotWebhookURL)
+// sendWebhook sends webhook data to the bot; attrs identify the notification in logs
+// При сетевой ошибке или ответе 5xx/429 запрос повторяется BOT_WEBHOOK_RETRIES раз
+// с экспоненциальной паузой, начиная с BOT_WEBHOOK_BACKOFF
+func (s *NotificationService) sendWebhook(ctx context.Context, webhook interface{}, attrs ...any) error {
+	// Параметры webhook могут быть перезагружены без рестарта
+	cfg := s.config.Get()
+	webhookURL := strings.TrimSuffix(cfg.BotWebhookURL, "/") + cfg.BotWebhookPath
 
 	// Сериализуем данные в JSON
 	jsonData, err := json.Marshal(webhook)
 	if err != nil {
-		log.Printf("Failed to marshal webhook data: %v", err)
+		s.logger.Error("failed to marshal webhook data", "error", err)
 		return fmt.Errorf("failed to marshal webhook data: %w", err)
 	}
 
-	// Создаем HTTP запрос
-	req, err := http.NewRequest("POST", webhookURL, bytes.NewBuffer(jsonData))
+	backoff := cfg.BotWebhookBackoff
+	for attempt := 0; ; attempt++ {
+		retryable, err := s.postWebhook(ctx, cfg, webhookURL, jsonData)
+		if err == nil {
+			s.logger.Info("sent notification to bot", append(attrs, "attempt", attempt+1)...)
+			return nil
+		}
+
+		if !retryable || attempt >= cfg.BotWebhookRetries {
+			s.logger.Error("failed to send webhook", "url", webhookURL, "attempts", attempt+1, "error", err)
+			return err
+		}
+
+		s.logger.Warn("webhook attempt failed, retrying", "url", webhookURL, "attempt", attempt+1, "backoff", backoff.String(), "error", err)
+		// Пауза прерывается при остановке пула исходящих вызовов
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+// postWebhook выполняет одну попытку отправки webhook
+// Возвращает признак того, что ошибку имеет смысл повторить
+func (s *NotificationService) postWebhook(ctx context.Context, cfg *config.Config, webhookURL string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
 	if err != nil {
-		log.Printf("Failed to create webhook request: %v", err)
-		return fmt.Errorf("failed to create webhook request: %w", err)
+		return false, fmt.Errorf("failed to create webhook request: %w", err)
 	}
 
-	// Устанавливаем заголовки
+	// Дополнительные заголовки не могут переопределить авторизацию и тип содержимого
+	for name, value := range cfg.BotWebhookHeaders {
+		req.Header.Set(name, value)
+	}
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Bot-Token", s.config.BotAPIToken)
+	req.Header.Set("X-Bot-Token", cfg.BotAPIToken)
 
-	// Отправляем запрос
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
-	resp, err := client.Do(req)
+	resp, err := s.httpClient.Do(req)
 	if err != nil {
-		log.Printf("Failed to send webhook: %v", err)
-		return fmt.Errorf("failed to send webhook: %w", err)
+		return true, fmt.Errorf("failed to send webhook: %w", err)
 	}
 	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body) // дочитываем тело, чтобы соединение вернулось в пул
 
-	// Проверяем статус ответа
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		log.Printf("Webhook returned non-success status: %d", resp.StatusCode)
-		return fmt.Errorf("webhook returned non-success status: %d", resp.StatusCode)
+		retryable := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return retryable, fmt.Errorf("webhook returned non-success status: %d", resp.StatusCode)
 	}
 
-	log.Printf("Successfully sent booking notification to bot for booking #%d", webhook.Booking.BookingID)
-	return nil
+	return false, nil
 }
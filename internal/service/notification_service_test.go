@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/config"
+	"github.com/space/backend/internal/models"
+)
+
+func TestNotificationService_AnnounceBookingStarting(t *testing.T) {
+	var payloads []BookingStartingWebhook
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var webhook BookingStartingWebhook
+		if err := json.NewDecoder(r.Body).Decode(&webhook); err != nil {
+			t.Errorf("Failed to decode webhook: %v", err)
+		}
+		payloads = append(payloads, webhook)
+	}))
+	defer server.Close()
+
+	cfg := config.NewLive(&config.Config{BotWebhookURL: server.URL, BotWebhookTimeout: time.Second, OfficeTimezone: "UTC"}, "")
+	svc := NewNotificationService(nil, nil, cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	start := time.Date(2026, 10, 16, 11, 0, 0, 0, time.UTC)
+	anna := models.User{ID: 1, TelegramID: 100, FirstName: "Anna"}
+	booking := &models.Booking{
+		ID: 5, Title: "Design <review>", StartTime: start, EndTime: start.Add(time.Hour),
+		Room:         models.Room{ID: 2, Name: "Room 2", Timezone: "Europe/Moscow"},
+		Creator:      anna,
+		Participants: []models.User{anna, {ID: 2, TelegramID: 200, Username: "bob"}, {ID: 3}},
+	}
+	ctx := context.Background()
+
+	// Без announce_start напоминание в чат не объявляется
+	if err := svc.HandleBookingEvent(ctx, BookingEvent{Type: EventBookingReminder, Booking: booking}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(payloads) != 0 {
+		t.Fatalf("Expected no announcement, got: %+v", payloads)
+	}
+
+	booking.Room.AnnounceStart = true
+	if err := svc.HandleBookingEvent(ctx, BookingEvent{Type: EventBookingReminder, Booking: booking}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(payloads) != 1 {
+		t.Fatalf("Expected one announcement, got: %d", len(payloads))
+	}
+	got := payloads[0]
+	if got.Event != "booking.starting" || len(got.Participants) != 2 {
+		t.Errorf("Expected booking.starting with 2 participants, got: %+v", got)
+	}
+	for _, want := range []string{"<b>Design &lt;review&gt;</b>", "14:00–15:00", `<a href="tg://user?id=100">Anna</a>`, `<a href="tg://user?id=200">@bob</a>`} {
+		if !strings.Contains(got.Text, want) {
+			t.Errorf("Expected text to contain %q, got:\n%s", want, got.Text)
+		}
+	}
+}
+
+func TestNotificationService_SubscribeUnknownRoom(t *testing.T) {
+	cfg := config.NewLive(&config.Config{}, "")
+	svc := NewNotificationService(nil, &fakeRoomStore{rooms: map[uint]*models.Room{}}, cfg, slog.Default())
+
+	if err := svc.Subscribe(context.Background(), 1, 42); !errors.Is(err, ErrRoomNotFound) {
+		t.Errorf("Expected ErrRoomNotFound, got: %v", err)
+	}
+}
+
+func TestNotificationService_NotifyWaitlistPromoted(t *testing.T) {
+	var payloads []BookingWaitlistPromotedWebhook
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var webhook BookingWaitlistPromotedWebhook
+		if err := json.NewDecoder(r.Body).Decode(&webhook); err != nil {
+			t.Errorf("Failed to decode webhook: %v", err)
+		}
+		payloads = append(payloads, webhook)
+	}))
+	defer server.Close()
+
+	cfg := config.NewLive(&config.Config{BotWebhookURL: server.URL, BotWebhookTimeout: time.Second}, "")
+	svc := NewNotificationService(nil, nil, cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	start := time.Date(2026, 10, 16, 11, 0, 0, 0, time.UTC)
+	booking := &models.Booking{ID: 5, Title: "Talk", StartTime: start, EndTime: start.Add(time.Hour), Room: models.Room{ID: 2, Name: "Hall"}}
+	ctx := context.Background()
+
+	// Пользователь без Telegram уведомление не получает
+	if err := svc.HandleBookingEvent(ctx, BookingEvent{Type: EventBookingWaitlistPromoted, Booking: booking, User: &models.User{ID: 3}}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(payloads) != 0 {
+		t.Fatalf("Expected no webhook, got: %+v", payloads)
+	}
+
+	promoted := &models.User{ID: 4, TelegramID: 400, FirstName: "Anna"}
+	if err := svc.HandleBookingEvent(ctx, BookingEvent{Type: EventBookingWaitlistPromoted, Booking: booking, User: promoted}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(payloads) != 1 || payloads[0].Event != "booking.waitlist_promoted" || len(payloads[0].Recipients) != 1 || payloads[0].Recipients[0].TelegramID != 400 {
+		t.Errorf("Expected a promotion webhook for the promoted user, got: %+v", payloads)
+	}
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/pkg/apperror"
+	"github.com/space/backend/pkg/oidc"
+	"github.com/space/backend/pkg/session"
+	"gorm.io/gorm"
+)
+
+const (
+	// Назначения подписанных токенов: токен одного назначения не принимается вместо другого
+	sessionAudience  = "session"
+	oidcFlowAudience = "oidc_flow"
+	oidcLinkAudience = "oidc_link"
+
+	// OIDCFlowTTL - сколько живёт незавершённый вход (cookie с state, nonce и PKCE verifier)
+	OIDCFlowTTL = 10 * time.Minute
+	// oidcLinkTTL - сколько действует ссылка привязки OIDC к Telegram-аккаунту
+	oidcLinkTTL = 5 * time.Minute
+)
+
+var (
+	ErrOIDCLoginFailed       = apperror.BadRequest("oidc_login_failed", "OIDC login failed")
+	ErrEmailNotVerified      = apperror.Forbidden("email_not_verified", "email is not verified by the identity provider")
+	ErrEmailDomainNotAllowed = apperror.Forbidden("domain_not_allowed", "email domain is not allowed")
+	ErrIdentityLinked        = apperror.Conflict("identity_linked", "this identity is already linked to another account")
+)
+
+// OIDCProvider is the identity provider used for login (see pkg/oidc)
+type OIDCProvider interface {
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	Exchange(ctx context.Context, code, verifier string) (string, error)
+	VerifyIDToken(ctx context.Context, raw, nonce string, now time.Time) (*oidc.IDToken, error)
+}
+
+// OIDCService logs users in through an OpenID Connect provider (Google, Keycloak)
+// Пользователь OIDC - та же запись User: существующая находится по subject или подтверждённому email,
+// новая создаётся без Telegram (telegram_id = 0). Пользователь Telegram может привязать OIDC к своему аккаунту
+type OIDCService struct {
+	provider       OIDCProvider
+	userRepo       UserStore
+	secret         []byte
+	sessionTTL     time.Duration
+	allowedDomains []string
+	logger         *slog.Logger
+}
+
+// NewOIDCService creates a new OIDC service; secret signs session tokens (JWT_SECRET)
+func NewOIDCService(provider OIDCProvider, userRepo UserStore, secret string, sessionTTL time.Duration, allowedDomains []string, logger *slog.Logger) *OIDCService {
+	domains := make([]string, len(allowedDomains))
+	for i, d := range allowedDomains {
+		domains[i] = strings.ToLower(strings.TrimPrefix(d, "@"))
+	}
+	return &OIDCService{
+		provider:       provider,
+		userRepo:       userRepo,
+		secret:         []byte(secret),
+		sessionTTL:     sessionTTL,
+		allowedDomains: domains,
+		logger:         logger,
+	}
+}
+
+// StartLogin returns the provider URL and the flow token to keep in a cookie until the callback
+// linkTicket (из CreateLinkTicket) привязывает вход к уже авторизованному пользователю Telegram
+func (s *OIDCService) StartLogin(ctx context.Context, linkTicket string) (authURL, flowToken string, err error) {
+	data := map[string]string{}
+	if linkTicket != "" {
+		claims, err := session.Verify(s.secret, oidcLinkAudience, linkTicket, time.Now())
+		if err != nil {
+			return "", "", fmt.Errorf("%w: link ticket: %v", ErrOIDCLoginFailed, err)
+		}
+		data["link_user_id"] = claims.Subject
+	}
+
+	state, err := oidc.RandomString(16)
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := oidc.RandomString(16)
+	if err != nil {
+		return "", "", err
+	}
+	verifier, challenge, err := oidc.NewPKCE()
+	if err != nil {
+		return "", "", err
+	}
+	data["state"], data["nonce"], data["verifier"] = state, nonce, verifier
+
+	authURL, err = s.provider.AuthCodeURL(ctx, state, nonce, challenge)
+	if err != nil {
+		return "", "", err
+	}
+	flowToken, err = session.Sign(s.secret, oidcFlowAudience, "", data, OIDCFlowTTL)
+	if err != nil {
+		return "", "", err
+	}
+	return authURL, flowToken, nil
+}
+
+// CompleteLogin handles the provider callback and returns the logged-in user
+func (s *OIDCService) CompleteLogin(ctx context.Context, flowToken, state, code string) (*models.User, error) {
+	flow, err := session.Verify(s.secret, oidcFlowAudience, flowToken, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOIDCLoginFailed, err)
+	}
+	if subtle.ConstantTimeCompare([]byte(flow.Data["state"]), []byte(state)) != 1 {
+		return nil, fmt.Errorf("%w: state mismatch", ErrOIDCLoginFailed)
+	}
+
+	rawIDToken, err := s.provider.Exchange(ctx, code, flow.Data["verifier"])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOIDCLoginFailed, err)
+	}
+	token, err := s.provider.VerifyIDToken(ctx, rawIDToken, flow.Data["nonce"], time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOIDCLoginFailed, err)
+	}
+
+	// Email - ключ связывания с существующими пользователями, поэтому только подтверждённый
+	email := strings.ToLower(strings.TrimSpace(token.Email))
+	if email == "" || !bool(token.EmailVerified) {
+		return nil, ErrEmailNotVerified
+	}
+	if !s.domainAllowed(email) {
+		return nil, ErrEmailDomainNotAllowed
+	}
+
+	if linkUserID := flow.Data["link_user_id"]; linkUserID != "" {
+		id, err := strconv.ParseUint(linkUserID, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid link ticket", ErrOIDCLoginFailed)
+		}
+		return s.link(ctx, uint(id), token.Subject, email)
+	}
+	return s.findOrCreate(ctx, token, email)
+}
+
+// CreateLinkTicket returns a short-lived ticket that links the next OIDC login to the user
+func (s *OIDCService) CreateLinkTicket(userID uint) (string, error) {
+	return session.Sign(s.secret, oidcLinkAudience, strconv.FormatUint(uint64(userID), 10), nil, oidcLinkTTL)
+}
+
+// IssueSession returns a session token for the Authorization: Bearer header
+func (s *OIDCService) IssueSession(user *models.User) (string, time.Duration, error) {
+	token, err := session.Sign(s.secret, sessionAudience, strconv.FormatUint(uint64(user.ID), 10), nil, s.sessionTTL)
+	return token, s.sessionTTL, err
+}
+
+// AuthenticateSession returns the user of a session token
+// Удалённый пользователь больше не проходит авторизацию, даже если токен ещё не истёк
+func (s *OIDCService) AuthenticateSession(ctx context.Context, token string) (*models.User, error) {
+	claims, err := session.Verify(s.secret, sessionAudience, token, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	id, err := strconv.ParseUint(claims.Subject, 10, 32)
+	if err != nil {
+		return nil, session.ErrInvalidToken
+	}
+	user, err := s.userRepo.GetByID(ctx, uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, session.ErrInvalidToken
+	}
+	return user, err
+}
+
+// link привязывает OIDC-идентичность к пользователю Telegram
+func (s *OIDCService) link(ctx context.Context, userID uint, subject, email string) (*models.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Идентичность или email уже у другой записи - слияние аккаунтов делает администратор
+	for _, lookup := range []func() (*models.User, error){
+		func() (*models.User, error) { return s.userRepo.GetByOIDCSubject(ctx, subject) },
+		func() (*models.User, error) { return s.userRepo.GetByEmail(ctx, email) },
+	} {
+		other, err := lookup()
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if other != nil && other.ID != user.ID {
+			return nil, ErrIdentityLinked
+		}
+	}
+
+	user.OIDCSubject = &subject
+	user.Email = &email
+	if err := s.userRepo.UpdateColumns(ctx, user, "oidc_subject", "email"); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("OIDC identity linked", "user_id", user.ID)
+	return user, nil
+}
+
+// findOrCreate находит пользователя по subject, затем по email; иначе создаёт нового
+func (s *OIDCService) findOrCreate(ctx context.Context, token *oidc.IDToken, email string) (*models.User, error) {
+	user, err := s.userRepo.GetByOIDCSubject(ctx, token.Subject)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	user, err = s.userRepo.GetByEmail(ctx, email)
+	switch {
+	case err == nil:
+		if user.OIDCSubject != nil {
+			// Email подтверждён, но запись уже связана с другой идентичностью провайдера
+			return nil, ErrIdentityLinked
+		}
+		user.OIDCSubject = &token.Subject
+		if err := s.userRepo.UpdateColumns(ctx, user, "oidc_subject"); err != nil {
+			return nil, err
+		}
+		s.logger.Info("OIDC identity linked by email", "user_id", user.ID)
+		return user, nil
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	user = &models.User{
+		Email:        &email,
+		OIDCSubject:  &token.Subject,
+		FirstName:    token.GivenName,
+		LastName:     token.FamilyName,
+		LanguageCode: strings.SplitN(token.Locale, "-", 2)[0],
+		Role:         models.RoleUser,
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("OIDC user created", "user_id", user.ID)
+	return user, nil
+}
+
+// domainAllowed проверяет домен email по OIDC_ALLOWED_DOMAINS; пустой список - любой домен
+func (s *OIDCService) domainAllowed(email string) bool {
+	if len(s.allowedDomains) == 0 {
+		return true
+	}
+	at := strings.LastIndex(email, "@")
+	return at >= 0 && slices.Contains(s.allowedDomains, email[at+1:])
+}
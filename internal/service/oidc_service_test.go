@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/pkg/oidc"
+	"gorm.io/gorm"
+)
+
+type fakeOIDCProvider struct {
+	token oidc.IDToken
+	nonce string
+}
+
+func (p *fakeOIDCProvider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	p.nonce = nonce
+	return "https://idp.example.com/authorize?state=" + state, nil
+}
+
+func (p *fakeOIDCProvider) Exchange(ctx context.Context, code, verifier string) (string, error) {
+	return "raw-id-token", nil
+}
+
+func (p *fakeOIDCProvider) VerifyIDToken(ctx context.Context, raw, nonce string, now time.Time) (*oidc.IDToken, error) {
+	if nonce != p.nonce {
+		return nil, oidc.ErrInvalidToken
+	}
+	token := p.token
+	return &token, nil
+}
+
+// fakeOIDCUserStore добавляет поиск по email и subject к fakeUserStore
+type fakeOIDCUserStore struct {
+	*fakeUserStore
+}
+
+func (f *fakeOIDCUserStore) find(match func(u *models.User) bool) (*models.User, error) {
+	for _, u := range f.users {
+		if match(u) {
+			return u, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeOIDCUserStore) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	return f.find(func(u *models.User) bool { return u.Email != nil && *u.Email == email })
+}
+
+func (f *fakeOIDCUserStore) GetByOIDCSubject(ctx context.Context, subject string) (*models.User, error) {
+	return f.find(func(u *models.User) bool { return u.OIDCSubject != nil && *u.OIDCSubject == subject })
+}
+
+func (f *fakeOIDCUserStore) Create(ctx context.Context, user *models.User) error {
+	user.ID = uint(len(f.users) + 100)
+	f.users[user.ID] = user
+	return nil
+}
+
+func (f *fakeOIDCUserStore) Update(ctx context.Context, user *models.User) error {
+	f.users[user.ID] = user
+	return nil
+}
+
+func (f *fakeOIDCUserStore) UpdateColumns(ctx context.Context, user *models.User, columns ...string) error {
+	return f.Update(ctx, user)
+}
+
+// login проходит вход целиком: login -> провайдер -> callback
+func login(t *testing.T, svc *OIDCService, linkTicket string) (*models.User, error) {
+	t.Helper()
+	authURL, flowToken, err := svc.StartLogin(context.Background(), linkTicket)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	state := authURL[strings.Index(authURL, "state=")+len("state="):]
+	return svc.CompleteLogin(context.Background(), flowToken, state, "code")
+}
+
+func TestOIDCLogin(t *testing.T) {
+	provider := &fakeOIDCProvider{token: oidc.IDToken{Subject: "sub-1", Email: "Anna@Example.com", EmailVerified: true, GivenName: "Anna"}}
+	users := &fakeOIDCUserStore{&fakeUserStore{users: map[uint]*models.User{
+		1: {ID: 1, TelegramID: 111, Username: "telegram_user"},
+	}}}
+	svc := NewOIDCService(provider, users, strings.Repeat("s", 32), time.Hour, []string{"example.com"}, slog.Default())
+
+	// Новый пользователь без Telegram
+	user, err := login(t, svc, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if user.TelegramID != 0 || *user.Email != "anna@example.com" || user.FirstName != "Anna" {
+		t.Errorf("Unexpected user: %+v", user)
+	}
+	again, err := login(t, svc, "")
+	if err != nil || again.ID != user.ID {
+		t.Errorf("Expected the same user on repeated login, got: %v, %v", again, err)
+	}
+
+	// Сессия авторизует пользователя
+	token, _, err := svc.IssueSession(user)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if sessionUser, err := svc.AuthenticateSession(context.Background(), token); err != nil || sessionUser.ID != user.ID {
+		t.Errorf("Expected session of user %d, got: %v, %v", user.ID, sessionUser, err)
+	}
+
+	// Привязка этой же идентичности к аккаунту Telegram - конфликт
+	ticket, err := svc.CreateLinkTicket(1)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := login(t, svc, ticket); !errors.Is(err, ErrIdentityLinked) {
+		t.Errorf("Expected ErrIdentityLinked, got: %v", err)
+	}
+
+	// Другая идентичность привязывается к аккаунту Telegram
+	provider.token = oidc.IDToken{Subject: "sub-2", Email: "bob@example.com", EmailVerified: true}
+	linked, err := login(t, svc, ticket)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if linked.ID != 1 || *linked.OIDCSubject != "sub-2" {
+		t.Errorf("Expected identity linked to user 1, got: %+v", linked)
+	}
+
+	provider.token = oidc.IDToken{Subject: "sub-3", Email: "eve@gmail.com", EmailVerified: true}
+	if _, err := login(t, svc, ""); !errors.Is(err, ErrEmailDomainNotAllowed) {
+		t.Errorf("Expected ErrEmailDomainNotAllowed, got: %v", err)
+	}
+	provider.token = oidc.IDToken{Subject: "sub-4", Email: "carl@example.com"}
+	if _, err := login(t, svc, ""); !errors.Is(err, ErrEmailNotVerified) {
+		t.Errorf("Expected ErrEmailNotVerified, got: %v", err)
+	}
+}
+
+func TestOIDCLogin_RejectsForeignState(t *testing.T) {
+	provider := &fakeOIDCProvider{token: oidc.IDToken{Subject: "sub-1", Email: "anna@example.com", EmailVerified: true}}
+	users := &fakeOIDCUserStore{&fakeUserStore{users: map[uint]*models.User{}}}
+	svc := NewOIDCService(provider, users, strings.Repeat("s", 32), time.Hour, nil, slog.Default())
+
+	_, flowToken, err := svc.StartLogin(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := svc.CompleteLogin(context.Background(), flowToken, "attacker-state", "code"); !errors.Is(err, ErrOIDCLoginFailed) {
+		t.Errorf("Expected ErrOIDCLoginFailed, got: %v", err)
+	}
+	if _, err := svc.CompleteLogin(context.Background(), "", "", "code"); !errors.Is(err, ErrOIDCLoginFailed) {
+		t.Errorf("Expected ErrOIDCLoginFailed without flow cookie, got: %v", err)
+	}
+}
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/pkg/apperror"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOwnershipNotFound   = apperror.NotFound("ownership_not_found", "room ownership not found")
+	ErrRoomAlreadyOwned    = apperror.Conflict("room_already_owned", "room is already dedicated to a team")
+	ErrRoomDedicated       = apperror.Forbidden("room_dedicated", "room is dedicated to a team: ask its members to delegate a slot")
+	ErrNotRoomOwner        = apperror.Forbidden("not_room_owner", "only members of the owning team can manage its room")
+	ErrDelegationNotFound  = apperror.NotFound("delegation_not_found", "delegation not found")
+	ErrInvalidOwnershipEnd = apperror.BadRequest("invalid_ownership_end", "until must be in the future")
+	ErrTeamNotFound        = apperror.NotFound("team_not_found", "team not found")
+	ErrUserNotFound        = apperror.NotFound("user_not_found", "user not found")
+)
+
+// RoomOwnershipRequest dedicates a room to a team
+type RoomOwnershipRequest struct {
+	RoomID uint       `json:"room_id" binding:"required"`
+	TeamID uint       `json:"team_id" binding:"required"`
+	Note   string     `json:"note"`
+	Until  *time.Time `json:"until"` // null - бессрочно
+}
+
+// RoomDelegationRequest hands a slot of a dedicated room to a user outside the owning team
+type RoomDelegationRequest struct {
+	UserID    uint      `json:"user_id" binding:"required"`
+	StartTime time.Time `json:"start_time" binding:"required"`
+	EndTime   time.Time `json:"end_time" binding:"required"`
+}
+
+// DedicatedRooms restricts booking of rooms dedicated to teams
+// Реализуется OwnershipService; BookingService проверяет по нему создание и перенос бронирований
+type DedicatedRooms interface {
+	CheckBookingAccess(ctx context.Context, roomID uint, user *models.User, start, end time.Time) error
+	ActiveOwnerships(ctx context.Context, since time.Time) ([]models.RoomOwnership, error)
+}
+
+var _ DedicatedRooms = (*OwnershipService)(nil)
+
+// OwnershipService manages rooms dedicated to resident teams and the slots their owners delegate
+type OwnershipService struct {
+	ownershipRepo OwnershipStore
+	roomRepo      RoomReader
+	teamRepo      TeamStore
+	userRepo      UserStore
+}
+
+// NewOwnershipService creates a new ownership service
+func NewOwnershipService(ownershipRepo OwnershipStore, roomRepo RoomReader, teamRepo TeamStore, userRepo UserStore) *OwnershipService {
+	return &OwnershipService{
+		ownershipRepo: ownershipRepo,
+		roomRepo:      roomRepo,
+		teamRepo:      teamRepo,
+		userRepo:      userRepo,
+	}
+}
+
+// ListOwnerships gets all dedicated rooms with their teams (admin only)
+func (s *OwnershipService) ListOwnerships(ctx context.Context) ([]models.RoomOwnership, error) {
+	return s.ownershipRepo.List(ctx)
+}
+
+// ActiveOwnerships gets ownerships in force at or after since
+func (s *OwnershipService) ActiveOwnerships(ctx context.Context, since time.Time) ([]models.RoomOwnership, error) {
+	return s.ownershipRepo.GetActive(ctx, since)
+}
+
+// CreateOwnership dedicates a room to a team (admin only)
+// Уже созданные бронирования комнаты другими пользователями остаются в силе
+func (s *OwnershipService) CreateOwnership(ctx context.Context, req RoomOwnershipRequest) (*models.RoomOwnership, error) {
+	if err := s.validate(ctx, req); err != nil {
+		return nil, err
+	}
+	if _, err := s.ownershipRepo.GetByRoomID(ctx, req.RoomID); err == nil {
+		return nil, ErrRoomAlreadyOwned
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	ownership := &models.RoomOwnership{RoomID: req.RoomID, TeamID: req.TeamID, Note: req.Note, Until: req.Until}
+	if err := s.ownershipRepo.Create(ctx, ownership); err != nil {
+		return nil, err
+	}
+	return s.getOwnership(ctx, ownership.ID)
+}
+
+// UpdateOwnership changes the team, note or end of an ownership (admin only); the room is not changed
+func (s *OwnershipService) UpdateOwnership(ctx context.Context, id uint, req RoomOwnershipRequest) (*models.RoomOwnership, error) {
+	ownership, err := s.getOwnership(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	req.RoomID = ownership.RoomID
+	if err := s.validate(ctx, req); err != nil {
+		return nil, err
+	}
+
+	ownership.TeamID = req.TeamID
+	ownership.Note = req.Note
+	ownership.Until = req.Until
+	if err := s.ownershipRepo.Update(ctx, ownership); err != nil {
+		return nil, err
+	}
+	return s.getOwnership(ctx, id)
+}
+
+// DeleteOwnership returns a dedicated room to general booking (admin only)
+func (s *OwnershipService) DeleteOwnership(ctx context.Context, id uint) error {
+	if err := s.ownershipRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrOwnershipNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// CheckBookingAccess checks that a user may book a room for [start, end]
+// Комнату, закреплённую за командой, бронируют её участники, администраторы
+// и пользователи, которым владельцы передали охватывающий период
+func (s *OwnershipService) CheckBookingAccess(ctx context.Context, roomID uint, user *models.User, start, end time.Time) error {
+	if user.IsAdmin() {
+		return nil
+	}
+	ownership, err := s.ownershipRepo.GetByRoomID(ctx, roomID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if !ownership.ActiveAt(start) {
+		return nil
+	}
+
+	member, err := s.ownershipRepo.IsTeamMember(ctx, ownership.TeamID, user.ID)
+	if err != nil || member {
+		return err
+	}
+	delegated, err := s.ownershipRepo.HasDelegation(ctx, ownership.ID, user.ID, start, end)
+	if err != nil {
+		return err
+	}
+	if !delegated {
+		return ErrRoomDedicated
+	}
+	return nil
+}
+
+// ListDelegations gets current and future delegations of a dedicated room (owning team members and admins)
+func (s *OwnershipService) ListDelegations(ctx context.Context, roomID, actorID uint) ([]models.RoomDelegation, error) {
+	ownership, err := s.ownerOf(ctx, roomID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	return s.ownershipRepo.ListDelegations(ctx, ownership.ID, time.Now())
+}
+
+// Delegate hands a slot of a dedicated room to a user (owning team members and admins)
+func (s *OwnershipService) Delegate(ctx context.Context, roomID, actorID uint, req RoomDelegationRequest) (*models.RoomDelegation, error) {
+	if !req.EndTime.After(req.StartTime) {
+		return nil, ErrInvalidTime
+	}
+	if !req.EndTime.After(time.Now()) {
+		return nil, ErrPastBooking
+	}
+	ownership, err := s.ownerOf(ctx, roomID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetByID(ctx, req.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	delegation := &models.RoomDelegation{
+		OwnershipID: ownership.ID,
+		UserID:      user.ID,
+		StartTime:   req.StartTime,
+		EndTime:     req.EndTime,
+		CreatedByID: actorID,
+	}
+	if err := s.ownershipRepo.CreateDelegation(ctx, delegation); err != nil {
+		return nil, err
+	}
+	delegation.User = *user
+	return delegation, nil
+}
+
+// RevokeDelegation deletes a delegation of a dedicated room (owning team members and admins)
+// Бронирования, уже созданные по переданному времени, остаются в силе
+func (s *OwnershipService) RevokeDelegation(ctx context.Context, roomID, actorID, delegationID uint) error {
+	ownership, err := s.ownerOf(ctx, roomID, actorID)
+	if err != nil {
+		return err
+	}
+	if err := s.ownershipRepo.DeleteDelegation(ctx, ownership.ID, delegationID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrDelegationNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// ownerOf возвращает закрепление комнаты, если actor - участник команды-владельца или администратор
+func (s *OwnershipService) ownerOf(ctx context.Context, roomID, actorID uint) (*models.RoomOwnership, error) {
+	ownership, err := s.ownershipRepo.GetByRoomID(ctx, roomID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOwnershipNotFound
+		}
+		return nil, err
+	}
+	actor, err := s.userRepo.GetByID(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if actor.IsAdmin() {
+		return ownership, nil
+	}
+	member, err := s.ownershipRepo.IsTeamMember(ctx, ownership.TeamID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if !member {
+		return nil, ErrNotRoomOwner
+	}
+	return ownership, nil
+}
+
+func (s *OwnershipService) validate(ctx context.Context, req RoomOwnershipRequest) error {
+	if req.Until != nil && !req.Until.After(time.Now()) {
+		return ErrInvalidOwnershipEnd
+	}
+	if _, err := s.roomRepo.GetByID(ctx, req.RoomID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRoomNotFound
+		}
+		return err
+	}
+	if _, err := s.teamRepo.GetByID(ctx, req.TeamID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTeamNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *OwnershipService) getOwnership(ctx context.Context, id uint) (*models.RoomOwnership, error) {
+	ownership, err := s.ownershipRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOwnershipNotFound
+		}
+		return nil, err
+	}
+	return ownership, nil
+}
+
+// FormatOwnershipForCalendar formats a dedicated room as a FullCalendar background event of the room over [start, end)
+func FormatOwnershipForCalendar(ownership *models.RoomOwnership, start, end time.Time) map[string]interface{} {
+	if ownership.Until != nil && ownership.Until.Before(end) {
+		end = *ownership.Until
+	}
+	return map[string]interface{}{
+		"id":         fmt.Sprintf("dedicated_%d", ownership.ID),
+		"title":      ownership.Team.Name,
+		"start":      start.Format(time.RFC3339),
+		"end":        end.Format(time.RFC3339),
+		"resourceId": fmt.Sprintf("%d", ownership.RoomID),
+		"calendarId": fmt.Sprintf("room_%d", ownership.RoomID),
+		"display":    "background",
+		"extendedProps": map[string]interface{}{
+			"type":    "dedicated",
+			"team_id": ownership.TeamID,
+		},
+	}
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type fakeOwnershipStore struct {
+	OwnershipStore
+	ownerships  map[uint]*models.RoomOwnership
+	members     map[uint][]uint
+	delegations []models.RoomDelegation
+}
+
+func (f *fakeOwnershipStore) GetByID(ctx context.Context, id uint) (*models.RoomOwnership, error) {
+	ownership, ok := f.ownerships[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return ownership, nil
+}
+
+func (f *fakeOwnershipStore) GetByRoomID(ctx context.Context, roomID uint) (*models.RoomOwnership, error) {
+	for _, o := range f.ownerships {
+		if o.RoomID == roomID {
+			return o, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeOwnershipStore) Create(ctx context.Context, ownership *models.RoomOwnership) error {
+	ownership.ID = uint(len(f.ownerships) + 1)
+	f.ownerships[ownership.ID] = ownership
+	return nil
+}
+
+func (f *fakeOwnershipStore) IsTeamMember(ctx context.Context, teamID, userID uint) (bool, error) {
+	for _, id := range f.members[teamID] {
+		if id == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeOwnershipStore) HasDelegation(ctx context.Context, ownershipID, userID uint, start, end time.Time) (bool, error) {
+	for _, d := range f.delegations {
+		if d.OwnershipID == ownershipID && d.UserID == userID && !d.StartTime.After(start) && !d.EndTime.Before(end) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeOwnershipStore) CreateDelegation(ctx context.Context, delegation *models.RoomDelegation) error {
+	delegation.ID = uint(len(f.delegations) + 1)
+	f.delegations = append(f.delegations, *delegation)
+	return nil
+}
+
+func TestOwnershipService_CreateOwnership(t *testing.T) {
+	rooms := &fakeRoomStore{rooms: map[uint]*models.Room{1: {ID: 1, Name: "Studio", IsActive: true}}}
+	teams := &fakeTeamStore{teams: map[uint]*models.Team{5: {ID: 5, Name: "Residents"}}}
+	svc := NewOwnershipService(&fakeOwnershipStore{ownerships: map[uint]*models.RoomOwnership{}}, rooms, teams, &fakeUserStore{})
+	ctx := context.Background()
+
+	if _, err := svc.CreateOwnership(ctx, RoomOwnershipRequest{RoomID: 1, TeamID: 5}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := svc.CreateOwnership(ctx, RoomOwnershipRequest{RoomID: 1, TeamID: 5}); !errors.Is(err, ErrRoomAlreadyOwned) {
+		t.Errorf("Expected ErrRoomAlreadyOwned, got: %v", err)
+	}
+	if _, err := svc.CreateOwnership(ctx, RoomOwnershipRequest{RoomID: 1, TeamID: 9}); !errors.Is(err, ErrTeamNotFound) {
+		t.Errorf("Expected ErrTeamNotFound, got: %v", err)
+	}
+	past := time.Now().Add(-time.Hour)
+	if _, err := svc.CreateOwnership(ctx, RoomOwnershipRequest{RoomID: 1, TeamID: 5, Until: &past}); !errors.Is(err, ErrInvalidOwnershipEnd) {
+		t.Errorf("Expected ErrInvalidOwnershipEnd, got: %v", err)
+	}
+}
+
+func TestCreateBookingDedicatedRoom(t *testing.T) {
+	start := time.Now().Add(time.Hour).Truncate(time.Minute)
+	rooms := &fakeRoomStore{rooms: map[uint]*models.Room{1: {ID: 1, Name: "Studio", IsActive: true}}}
+	users := &fakeUserStore{users: map[uint]*models.User{
+		10: {ID: 10, Role: models.RoleUser}, // Участник команды-владельца
+		11: {ID: 11, Role: models.RoleUser},
+		12: {ID: 12, Role: models.RoleAdmin},
+	}}
+	ownerships := &fakeOwnershipStore{
+		ownerships: map[uint]*models.RoomOwnership{1: {ID: 1, RoomID: 1, TeamID: 5}},
+		members:    map[uint][]uint{5: {10}},
+	}
+	dedicated := NewOwnershipService(ownerships, rooms, &fakeTeamStore{}, users)
+	svc := NewBookingService(fakeTx{}, newFakeBookingStore(), rooms, users, nil, nil, nil, slog.Default())
+	svc.SetDedicatedRooms(dedicated)
+	ctx := context.Background()
+
+	slot := func(hour int) CreateBookingRequest {
+		from := start.Add(time.Duration(hour) * time.Hour)
+		return CreateBookingRequest{RoomID: 1, StartTime: from, EndTime: from.Add(time.Hour), Title: "Meeting"}
+	}
+
+	if _, err := svc.CreateBooking(ctx, 11, slot(0)); !errors.Is(err, ErrRoomDedicated) {
+		t.Fatalf("Expected ErrRoomDedicated for an outsider, got: %v", err)
+	}
+	if _, err := svc.CreateBooking(ctx, 10, slot(0)); err != nil {
+		t.Fatalf("Expected a team member to book, got: %v", err)
+	}
+	if _, err := svc.CreateBooking(ctx, 12, slot(1)); err != nil {
+		t.Fatalf("Expected an admin to book, got: %v", err)
+	}
+
+	// Участник команды передаёт время: бронирование должно целиком лежать в переданном периоде
+	if _, err := dedicated.Delegate(ctx, 1, 11, RoomDelegationRequest{UserID: 11, StartTime: start, EndTime: start.Add(24 * time.Hour)}); !errors.Is(err, ErrNotRoomOwner) {
+		t.Errorf("Expected ErrNotRoomOwner for an outsider, got: %v", err)
+	}
+	delegated := slot(2)
+	if _, err := dedicated.Delegate(ctx, 1, 10, RoomDelegationRequest{UserID: 11, StartTime: delegated.StartTime, EndTime: delegated.EndTime}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := svc.CreateBooking(ctx, 11, delegated); err != nil {
+		t.Errorf("Expected a booking in the delegated slot, got: %v", err)
+	}
+	if _, err := svc.CreateBooking(ctx, 11, slot(3)); !errors.Is(err, ErrRoomDedicated) {
+		t.Errorf("Expected ErrRoomDedicated outside the delegated slot, got: %v", err)
+	}
+
+	// После окончания закрепления комната снова общая
+	until := start.Add(4 * time.Hour)
+	ownerships.ownerships[1].Until = &until
+	if _, err := svc.CreateBooking(ctx, 11, slot(4)); err != nil {
+		t.Errorf("Expected general booking after the ownership ends, got: %v", err)
+	}
+}
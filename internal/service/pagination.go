@@ -0,0 +1,43 @@
+package service
+
+// Размеры страниц списков: значение по умолчанию и максимум
+// Обработчики используют их при разборе page/per_page, сервисы - как защиту от больших выборок
+const (
+	DefaultBookingPageSize = 100
+	MaxBookingPageSize     = 500
+
+	DefaultAuditPageSize = 50
+	MaxAuditPageSize     = 500
+
+	// Mini App показывает телефонную книгу целиком, поэтому страница по умолчанию - максимальная
+	DefaultPhonebookPageSize = 500
+	MaxPhonebookPageSize     = 500
+
+	DefaultUserPageSize = 100
+	MaxUserPageSize     = 500
+
+	// SCIM: клиенты каталога листают страницами по startIndex/count
+	DefaultSCIMPageSize = 100
+	MaxSCIMPageSize     = 200
+
+	// Комнаты и API-ключи читаются целиком и режутся на страницы в памяти
+	DefaultListPageSize = 100
+	MaxListPageSize     = 500
+
+	// Выгрузки читаются и отправляются клиенту пачками
+	ExportBatchSize = 500
+)
+
+// pageBounds приводит параметры пагинации к допустимым значениям
+func pageBounds(limit, offset, defaultSize, maxSize int) (int, int) {
+	if limit <= 0 {
+		limit = defaultSize
+	}
+	if limit > maxSize {
+		limit = maxSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
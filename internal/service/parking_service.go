@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/pkg/apperror"
+	"gorm.io/gorm"
+)
+
+// maxLicensePlateLength - длина колонки bookings.license_plate
+const maxLicensePlateLength = 20
+
+var (
+	ErrNotParkingSpot       = apperror.BadRequest("not_parking_spot", "not a parking spot")
+	ErrNotParkingBooking    = apperror.NotFound("not_parking_booking", "not a parking spot") // Бронирование обычной комнаты через /parking
+	ErrLicensePlateRequired = apperror.BadRequest("license_plate_required", "license plate is required for a parking booking")
+	ErrInvalidLicensePlate  = apperror.BadRequest("invalid_license_plate", "license plate must contain only letters and digits, up to 20 characters")
+	ErrParkingMultiDay      = apperror.BadRequest("parking_multi_day", "parking booking must start and end on the same day")
+	ErrParkingDailyLimit    = apperror.Forbidden("parking_daily_limit", "only one parking booking per day is allowed")
+)
+
+// ParkingSlot is a busy interval of a parking spot; own bookings are marked, others show no details
+type ParkingSlot struct {
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Mine      bool      `json:"mine"`
+}
+
+// ParkingSpotAvailability is a parking spot with its busy intervals on a day
+type ParkingSpotAvailability struct {
+	ID          uint          `json:"id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Busy        []ParkingSlot `json:"busy"`
+}
+
+// CreateParkingBookingRequest represents a request to book a parking spot
+type CreateParkingBookingRequest struct {
+	SpotID       uint      `json:"spot_id" binding:"required"`
+	StartTime    time.Time `json:"start_time" binding:"required"`
+	EndTime      time.Time `json:"end_time" binding:"required"`
+	LicensePlate string    `json:"license_plate" binding:"required"`
+}
+
+// ParkingService serves the parking tab of the Mini App
+// Парковочные места - те же ресурсы бронирования (Room с kind = parking): конфликты, нерабочие дни
+// и поток событий общие с комнатами, а правила парковки проверяет BookingService
+type ParkingService struct {
+	bookingService *BookingService
+	roomRepo       RoomStore
+	bookingRepo    BookingStore
+}
+
+// NewParkingService creates a new parking service
+func NewParkingService(bookingService *BookingService, roomRepo RoomStore, bookingRepo BookingStore) *ParkingService {
+	return &ParkingService{
+		bookingService: bookingService,
+		roomRepo:       roomRepo,
+		bookingRepo:    bookingRepo,
+	}
+}
+
+// GetSpots returns active parking spots with their busy intervals on the calendar day of date
+// День считается в часовом поясе date: Mini App передаёт дату со смещением пользователя
+func (s *ParkingService) GetSpots(ctx context.Context, userID uint, date time.Time) ([]ParkingSpotAvailability, error) {
+	spots, err := s.roomRepo.GetParkingSpots(ctx)
+	if err != nil {
+		return nil, err
+	}
+	start, end := dayBounds(date, date.Location())
+	bookings, err := s.bookingRepo.ListByRoomKind(ctx, models.RoomKindParking, 0, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ParkingSpotAvailability, 0, len(spots))
+	index := make(map[uint]int, len(spots))
+	for _, spot := range spots {
+		index[spot.ID] = len(result)
+		result = append(result, ParkingSpotAvailability{ID: spot.ID, Name: spot.Name, Description: spot.Description, Busy: []ParkingSlot{}})
+	}
+	for _, booking := range bookings {
+		i, ok := index[booking.RoomID]
+		if !ok {
+			continue // место отключено
+		}
+		result[i].Busy = append(result[i].Busy, ParkingSlot{
+			StartTime: booking.StartTime,
+			EndTime:   booking.EndTime,
+			Mine:      booking.CreatorID == userID,
+		})
+	}
+	return result, nil
+}
+
+// BookSpot books a parking spot for the user
+func (s *ParkingService) BookSpot(ctx context.Context, userID uint, req CreateParkingBookingRequest) (*models.Booking, error) {
+	spot, err := s.roomRepo.GetByID(ctx, req.SpotID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoomNotFound
+		}
+		return nil, err
+	}
+	if spot.Kind != models.RoomKindParking {
+		return nil, ErrNotParkingSpot
+	}
+
+	return s.bookingService.CreateBooking(ctx, userID, CreateBookingRequest{
+		RoomID:       spot.ID,
+		StartTime:    req.StartTime,
+		EndTime:      req.EndTime,
+		Title:        spot.Name,
+		LicensePlate: req.LicensePlate,
+	})
+}
+
+// GetUserBookings returns the user's parking bookings that have not ended yet, by start time
+func (s *ParkingService) GetUserBookings(ctx context.Context, userID uint) ([]models.Booking, error) {
+	return s.bookingRepo.ListByRoomKind(ctx, models.RoomKindParking, userID, time.Now(), time.Time{})
+}
+
+// CancelBooking cancels a parking booking of the user (or any, for an admin)
+func (s *ParkingService) CancelBooking(ctx context.Context, bookingID, userID uint) error {
+	booking, err := s.bookingRepo.GetByID(ctx, bookingID)
+	if err != nil {
+		return err
+	}
+	if booking.Room.Kind != models.RoomKindParking {
+		return ErrNotParkingBooking
+	}
+	return s.bookingService.CancelBooking(ctx, bookingID, userID)
+}
+
+// checkParkingRules проверяет правила парковки и возвращает нормализованный номер автомобиля:
+// бронирование укладывается в один день и у пользователя нет другого бронирования парковки в этот день.
+// День - в часовом поясе места, у места без часового пояса - в часовом поясе start.
+// Параллельные бронирования разных мест могут дать второе бронирование в день - это допустимо, как и с лимитом бронирований
+func (s *BookingService) checkParkingRules(ctx context.Context, loc *time.Location, creatorID uint, start, end time.Time, plate string, excludeBookingID uint) (string, error) {
+	plate, err := normalizeLicensePlate(plate)
+	if err != nil {
+		return "", err
+	}
+
+	if loc == nil {
+		loc = start.Location()
+	}
+	dayStart, dayEnd := dayBounds(start, loc)
+	if end.After(dayEnd) {
+		return "", ErrParkingMultiDay
+	}
+
+	bookings, err := s.bookingRepo.ListByRoomKind(ctx, models.RoomKindParking, creatorID, dayStart, dayEnd)
+	if err != nil {
+		return "", err
+	}
+	for _, booking := range bookings {
+		if booking.ID != excludeBookingID {
+			return "", ErrParkingDailyLimit
+		}
+	}
+	return plate, nil
+}
+
+// normalizeLicensePlate приводит номер к виду без пробелов и дефисов в верхнем регистре ("а 123 вс" -> "А123ВС")
+func normalizeLicensePlate(plate string) (string, error) {
+	var b strings.Builder
+	for _, r := range plate {
+		switch {
+		case unicode.IsSpace(r) || r == '-':
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(unicode.ToUpper(r))
+		default:
+			return "", ErrInvalidLicensePlate
+		}
+	}
+	normalized := b.String()
+	if normalized == "" {
+		return "", ErrLicensePlateRequired
+	}
+	if len([]rune(normalized)) > maxLicensePlateLength {
+		return "", ErrInvalidLicensePlate
+	}
+	return normalized, nil
+}
+
+// dayBounds возвращает начало календарного дня t в часовом поясе loc и начало следующего дня
+func dayBounds(t time.Time, loc *time.Location) (time.Time, time.Time) {
+	t = t.In(loc)
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	return start, start.AddDate(0, 0, 1)
+}
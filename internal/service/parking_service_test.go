@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/models"
+)
+
+// parkingBookingStore отбирает бронирования по виду ресурса через комнаты fakeRoomStore
+type parkingBookingStore struct {
+	*fakeBookingStore
+	rooms *fakeRoomStore
+}
+
+func (f *parkingBookingStore) ListByRoomKind(ctx context.Context, kind models.RoomKind, creatorID uint, start, end time.Time) ([]models.Booking, error) {
+	var bookings []models.Booking
+	for _, b := range f.bookings {
+		room, ok := f.rooms.rooms[b.RoomID]
+		if !ok || room.Kind != kind || b.Status == models.BookingStatusCancelled {
+			continue
+		}
+		if creatorID != 0 && b.CreatorID != creatorID {
+			continue
+		}
+		if b.EndTime.After(start) && (end.IsZero() || b.StartTime.Before(end)) {
+			bookings = append(bookings, *b)
+		}
+	}
+	return bookings, nil
+}
+
+// GetByID подгружает комнату, как BookingRepository.GetByID
+func (f *parkingBookingStore) GetByID(ctx context.Context, id uint) (*models.Booking, error) {
+	booking, err := f.fakeBookingStore.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if room, ok := f.rooms.rooms[booking.RoomID]; ok {
+		booking.Room = *room
+	}
+	return booking, nil
+}
+
+func newTestParkingService() (*ParkingService, *parkingBookingStore) {
+	rooms := &fakeRoomStore{rooms: map[uint]*models.Room{
+		1: {ID: 1, Name: "Room 1", IsActive: true, Kind: models.RoomKindRoom},
+		2: {ID: 2, Name: "P1", IsActive: true, Kind: models.RoomKindParking},
+		3: {ID: 3, Name: "P2", IsActive: true, Kind: models.RoomKindParking},
+	}}
+	users := &fakeUserStore{users: map[uint]*models.User{
+		10: {ID: 10, Role: models.RoleUser},
+		11: {ID: 11, Role: models.RoleUser},
+	}}
+	store := &parkingBookingStore{fakeBookingStore: newFakeBookingStore(), rooms: rooms}
+	bookings := NewBookingService(fakeTx{}, store, rooms, users, nil, nil, nil, slog.Default())
+	return NewParkingService(bookings, rooms, store), store
+}
+
+func TestParkingService_BookSpot(t *testing.T) {
+	svc, store := newTestParkingService()
+	ctx := context.Background()
+	tomorrow := time.Now().In(time.UTC).AddDate(0, 0, 1)
+	start := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 9, 0, 0, 0, time.UTC)
+
+	booking, err := svc.BookSpot(ctx, 10, CreateParkingBookingRequest{SpotID: 2, StartTime: start, EndTime: start.Add(8 * time.Hour), LicensePlate: "а 123-вс 77"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if booking.LicensePlate != "А123ВС77" || booking.Title != "P1" || store.bookings[booking.ID].LicensePlate != "А123ВС77" {
+		t.Errorf("Expected a normalized plate and the spot name as title, got: %+v", booking)
+	}
+
+	tests := []struct {
+		name   string
+		userID uint
+		req    CreateParkingBookingRequest
+		want   error
+	}{
+		{"second spot the same day", 10, CreateParkingBookingRequest{SpotID: 3, StartTime: start.Add(9 * time.Hour), EndTime: start.Add(10 * time.Hour), LicensePlate: "A123BC"}, ErrParkingDailyLimit},
+		{"over midnight", 11, CreateParkingBookingRequest{SpotID: 3, StartTime: start.Add(12 * time.Hour), EndTime: start.Add(16 * time.Hour), LicensePlate: "A123BC"}, ErrParkingMultiDay},
+		{"no plate", 11, CreateParkingBookingRequest{SpotID: 3, StartTime: start, EndTime: start.Add(time.Hour), LicensePlate: " - "}, ErrLicensePlateRequired},
+		{"invalid plate", 11, CreateParkingBookingRequest{SpotID: 3, StartTime: start, EndTime: start.Add(time.Hour), LicensePlate: "A123/BC"}, ErrInvalidLicensePlate},
+		{"meeting room", 11, CreateParkingBookingRequest{SpotID: 1, StartTime: start, EndTime: start.Add(time.Hour), LicensePlate: "A123BC"}, ErrNotParkingSpot},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.BookSpot(ctx, tt.userID, tt.req); !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got: %v", tt.want, err)
+			}
+		})
+	}
+
+	// Занятое место - общий механизм конфликтов
+	_, err = svc.BookSpot(ctx, 11, CreateParkingBookingRequest{SpotID: 2, StartTime: start.Add(time.Hour), EndTime: start.Add(2 * time.Hour), LicensePlate: "B456CD"})
+	if !errors.Is(err, ErrBookingConflict) {
+		t.Errorf("Expected ErrBookingConflict, got: %v", err)
+	}
+
+	// После отмены в тот же день можно забронировать снова
+	if err := svc.CancelBooking(ctx, booking.ID, 10); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := svc.BookSpot(ctx, 10, CreateParkingBookingRequest{SpotID: 3, StartTime: start, EndTime: start.Add(time.Hour), LicensePlate: "A123BC"}); err != nil {
+		t.Errorf("Expected a new booking after cancelling, got: %v", err)
+	}
+}
+
+func TestParkingService_GetSpots(t *testing.T) {
+	svc, store := newTestParkingService()
+	day := time.Date(2025, 5, 12, 0, 0, 0, 0, time.UTC)
+	store.bookings[1] = &models.Booking{ID: 1, RoomID: 2, CreatorID: 10, StartTime: day.Add(9 * time.Hour), EndTime: day.Add(18 * time.Hour), Status: models.BookingStatusConfirmed}
+	store.bookings[2] = &models.Booking{ID: 2, RoomID: 3, CreatorID: 11, StartTime: day.AddDate(0, 0, 1), EndTime: day.AddDate(0, 0, 1).Add(time.Hour), Status: models.BookingStatusConfirmed}
+	store.bookings[3] = &models.Booking{ID: 3, RoomID: 1, CreatorID: 11, StartTime: day.Add(9 * time.Hour), EndTime: day.Add(10 * time.Hour), Status: models.BookingStatusConfirmed}
+
+	spots, err := svc.GetSpots(context.Background(), 10, day.Add(12*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(spots) != 2 || len(spots[0].Busy) != 1 || !spots[0].Busy[0].Mine || len(spots[1].Busy) != 0 {
+		t.Errorf("Expected only spot P1 busy by the user that day, got: %+v", spots)
+	}
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/space/backend/pkg/apperror"
+)
+
+var (
+	ErrPurgeDisabled = apperror.Conflict("purge_disabled", "soft-delete purge is disabled (SOFT_DELETE_RETENTION_DAYS=0)")
+
+	// errDryRunRollback откатывает транзакцию пробного запуска
+	errDryRunRollback = errors.New("dry run rollback")
+)
+
+// PurgeResult represents the outcome of a soft-delete purge run
+type PurgeResult struct {
+	DryRun   bool      `json:"dry_run"`
+	Cutoff   time.Time `json:"cutoff"`
+	Bookings int64     `json:"bookings"`
+	Rooms    int64     `json:"rooms"`
+	Users    int64     `json:"users"`
+}
+
+// PurgeService hard-deletes soft-deleted bookings, rooms and users past the retention window
+type PurgeService struct {
+	txManager TxRunner
+	purgeRepo PurgeStore
+	retention time.Duration
+	dryRun    atomic.Bool // Режим по умолчанию для фонового запуска; меняется при перезагрузке конфигурации
+	logger    *slog.Logger
+}
+
+// NewPurgeService creates a new purge service
+// retention <= 0 отключает удаление
+func NewPurgeService(txManager TxRunner, purgeRepo PurgeStore, retention time.Duration, dryRun bool, logger *slog.Logger) *PurgeService {
+	s := &PurgeService{
+		txManager: txManager,
+		purgeRepo: purgeRepo,
+		retention: retention,
+		logger:    logger,
+	}
+	s.dryRun.Store(dryRun)
+	return s
+}
+
+// SetDryRun sets the mode of the background run
+func (s *PurgeService) SetDryRun(dryRun bool) {
+	s.dryRun.Store(dryRun)
+}
+
+// Purge deletes expired soft-deleted rows
+// Пробный запуск выполняет те же удаления в транзакции и откатывает её,
+// поэтому счётчики точные (комнаты и пользователи освобождаются удалёнными бронированиями)
+func (s *PurgeService) Purge(ctx context.Context, dryRun bool) (*PurgeResult, error) {
+	if s.retention <= 0 {
+		return nil, ErrPurgeDisabled
+	}
+
+	result := &PurgeResult{
+		DryRun: dryRun,
+		Cutoff: time.Now().Add(-s.retention),
+	}
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		// Порядок важен: бронирования ссылаются на комнаты и пользователей
+		if result.Bookings, err = s.purgeRepo.PurgeBookings(ctx, result.Cutoff); err != nil {
+			return err
+		}
+		if result.Rooms, err = s.purgeRepo.PurgeRooms(ctx, result.Cutoff); err != nil {
+			return err
+		}
+		if result.Users, err = s.purgeRepo.PurgeUsers(ctx, result.Cutoff); err != nil {
+			return err
+		}
+		if dryRun {
+			return errDryRunRollback
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRunRollback) {
+		return nil, err
+	}
+
+	s.logger.Info("soft-deleted rows purged",
+		"dry_run", dryRun,
+		"cutoff", result.Cutoff,
+		"bookings", result.Bookings,
+		"rooms", result.Rooms,
+		"users", result.Users,
+	)
+	return result, nil
+}
+
+// RunScheduled purges in the default mode (PURGE_DRY_RUN), used by the background job
+func (s *PurgeService) RunScheduled(ctx context.Context) error {
+	_, err := s.Purge(ctx, s.dryRun.Load())
+	return err
+}
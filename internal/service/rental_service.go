@@ -0,0 +1,331 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/pkg/apperror"
+	"github.com/space/backend/pkg/captcha"
+	"github.com/space/backend/pkg/i18n"
+	"github.com/space/backend/pkg/validator"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRentalNotFound      = apperror.NotFound("rental_not_found", "rental request not found")
+	ErrRentalReviewed      = apperror.Conflict("rental_reviewed", "rental request is already reviewed")
+	ErrInvalidRentalStatus = apperror.BadRequest("invalid_rental_status", "status must be pending, approved or rejected")
+	ErrCaptchaFailed       = apperror.Forbidden("captcha_failed", "captcha verification failed")
+	ErrRoomNotRentable     = apperror.BadRequest("room_not_rentable", "room is not available for rental")
+	ErrRentalUnavailable   = apperror.Conflict("rental_unavailable", "room is not available at this time")
+)
+
+// Длина контактных полей заявки
+const (
+	maxRentalContactLength = 200
+	maxRentalPhoneLength   = 32
+)
+
+// RentalRequestInput is a rental request submitted through the public form
+type RentalRequestInput struct {
+	RoomID       uint      `json:"room_id" binding:"required"`
+	Name         string    `json:"name" binding:"required"`
+	Email        string    `json:"email" binding:"required"`
+	Phone        string    `json:"phone"`
+	Organization string    `json:"organization"`
+	Title        string    `json:"title" binding:"required"` // Название мероприятия
+	Description  string    `json:"description"`
+	Participants int       `json:"participants"`
+	StartTime    time.Time `json:"start_time" binding:"required"`
+	EndTime      time.Time `json:"end_time" binding:"required"`
+	CaptchaToken string    `json:"captcha_token" binding:"required"` // Токен, выданный виджетом капчи
+}
+
+// CaptchaVerifier checks captcha tokens of the public form
+type CaptchaVerifier interface {
+	Verify(ctx context.Context, token, remoteIP string) error
+}
+
+// Mailer sends plain-text email
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// RentalBooker checks and creates bookings for rental requests; реализуется BookingService
+type RentalBooker interface {
+	CheckAvailability(ctx context.Context, roomID uint, start, end time.Time) (*AvailabilityCheck, error)
+	CreateBooking(ctx context.Context, creatorID uint, req CreateBookingRequest) (*models.Booking, error)
+}
+
+var _ RentalBooker = (*BookingService)(nil)
+
+// RentalService accepts rental requests of outside parties and moderates them
+// Заявка не занимает комнату: бронирование создаётся от имени администратора при одобрении
+type RentalService struct {
+	txManager  TxRunner
+	rentalRepo RentalStore
+	roomRepo   RoomReader
+	bookings   RentalBooker
+	captcha    CaptchaVerifier
+	mailer     Mailer
+	tasks      TaskQueue
+	textLimits TextLimits
+	loc        *time.Location
+	logger     *slog.Logger
+}
+
+// NewRentalService creates a new rental service; loc is the timezone of dates in emails for rooms without one
+func NewRentalService(txManager TxRunner, rentalRepo RentalStore, roomRepo RoomReader, bookings RentalBooker, captcha CaptchaVerifier, tasks TaskQueue, loc *time.Location, logger *slog.Logger) *RentalService {
+	return &RentalService{
+		txManager:  txManager,
+		rentalRepo: rentalRepo,
+		roomRepo:   roomRepo,
+		bookings:   bookings,
+		captcha:    captcha,
+		tasks:      tasks,
+		textLimits: DefaultTextLimits(),
+		loc:        loc,
+		logger:     logger,
+	}
+}
+
+// SetMailer enables email notifications of requesters about decisions
+func (s *RentalService) SetMailer(mailer Mailer) {
+	s.mailer = mailer
+}
+
+// SetTextLimits sets the maximum lengths of request titles, descriptions and names
+func (s *RentalService) SetTextLimits(limits TextLimits) {
+	s.textLimits = limits.withDefaults()
+}
+
+// Submit checks the captcha and adds a request to the moderation queue
+// lang - язык писем заявителю, remoteIP - адрес клиента для проверки капчи
+func (s *RentalService) Submit(ctx context.Context, req RentalRequestInput, lang, remoteIP string) (*models.RentalRequest, error) {
+	if err := s.captcha.Verify(ctx, req.CaptchaToken, remoteIP); err != nil {
+		if errors.Is(err, captcha.ErrInvalidToken) {
+			return nil, ErrCaptchaFailed
+		}
+		return nil, err
+	}
+
+	var fieldErrs validator.Errors
+	req.Name = fieldErrs.Text("name", req.Name, s.textLimits.Name, true)
+	req.Organization = fieldErrs.Text("organization", req.Organization, maxRentalContactLength, false)
+	req.Phone = fieldErrs.Text("phone", req.Phone, maxRentalPhoneLength, false)
+	req.Title = fieldErrs.Text("title", req.Title, s.textLimits.Title, true)
+	req.Description = fieldErrs.Text("description", req.Description, s.textLimits.Description, false)
+	email, err := mail.ParseAddress(strings.TrimSpace(req.Email))
+	if err != nil || len(email.Address) > maxRentalContactLength {
+		fieldErrs = append(fieldErrs, validator.FieldError{Field: "email", Rule: validator.RuleInvalid})
+	}
+	if err := fieldErrs.Err(); err != nil {
+		return nil, err
+	}
+
+	if !req.EndTime.After(req.StartTime) {
+		return nil, ErrInvalidTime
+	}
+	if req.StartTime.Before(time.Now()) {
+		return nil, ErrPastBooking
+	}
+	if req.Participants < 1 {
+		req.Participants = 1
+	}
+
+	room, err := s.roomRepo.GetByID(ctx, req.RoomID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoomNotFound
+		}
+		return nil, err
+	}
+	if !room.IsActive || room.Kind == models.RoomKindParking {
+		return nil, ErrRoomNotRentable
+	}
+	// Занятое время отклоняется сразу, чтобы не отправлять модераторам заведомо невыполнимую заявку
+	check, err := s.bookings.CheckAvailability(ctx, room.ID, req.StartTime, req.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	if !check.Available {
+		return nil, ErrRentalUnavailable
+	}
+
+	if lang = i18n.Normalize(lang); lang == "" {
+		lang = i18n.Default
+	}
+	request := &models.RentalRequest{
+		RoomID:       room.ID,
+		Name:         req.Name,
+		Email:        email.Address,
+		Phone:        req.Phone,
+		Organization: req.Organization,
+		Language:     lang,
+		Title:        req.Title,
+		Description:  req.Description,
+		Participants: req.Participants,
+		StartTime:    req.StartTime,
+		EndTime:      req.EndTime,
+		Status:       models.RentalRequestPending,
+	}
+	if err := s.rentalRepo.Create(ctx, request); err != nil {
+		return nil, err
+	}
+	request.Room = *room
+
+	s.logger.Info("rental request submitted", "rental_request_id", request.ID, "room_id", room.ID)
+	return request, nil
+}
+
+// List returns a page of the moderation queue, newest first; empty status - all requests
+func (s *RentalService) List(ctx context.Context, status models.RentalRequestStatus, limit, offset int) ([]models.RentalRequest, int64, error) {
+	switch status {
+	case "", models.RentalRequestPending, models.RentalRequestApproved, models.RentalRequestRejected:
+	default:
+		return nil, 0, ErrInvalidRentalStatus
+	}
+	limit, offset = pageBounds(limit, offset, DefaultListPageSize, MaxListPageSize)
+	return s.rentalRepo.List(ctx, status, limit, offset)
+}
+
+// Approve books the room for a pending request on behalf of the reviewer and emails the requester
+// Бронирование проходит обычные проверки: если время успели занять, заявка остаётся в очереди
+func (s *RentalService) Approve(ctx context.Context, id, reviewerID uint) (*models.RentalRequest, error) {
+	var request *models.RentalRequest
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if request, err = s.review(ctx, id, models.RentalRequestApproved, "", reviewerID); err != nil {
+			return err
+		}
+
+		booking, err := s.bookings.CreateBooking(ctx, reviewerID, CreateBookingRequest{
+			RoomID:                request.RoomID,
+			StartTime:             request.StartTime,
+			EndTime:               request.EndTime,
+			Title:                 request.Title,
+			Description:           request.Description,
+			EstimatedParticipants: request.Participants,
+		})
+		if err != nil {
+			return err
+		}
+		request.BookingID = &booking.ID
+		return s.rentalRepo.SetBooking(ctx, id, booking.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("rental request approved", "rental_request_id", id, "booking_id", *request.BookingID, "reviewed_by", reviewerID)
+	s.notify(request)
+	return request, nil
+}
+
+// Reject closes a pending request; reason is sent to the requester
+func (s *RentalService) Reject(ctx context.Context, id, reviewerID uint, reason string) (*models.RentalRequest, error) {
+	var fieldErrs validator.Errors
+	reason = fieldErrs.Text("reason", reason, s.textLimits.Description, false)
+	if err := fieldErrs.Err(); err != nil {
+		return nil, err
+	}
+
+	request, err := s.review(ctx, id, models.RentalRequestRejected, reason, reviewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("rental request rejected", "rental_request_id", id, "reviewed_by", reviewerID)
+	s.notify(request)
+	return request, nil
+}
+
+// review закрывает заявку решением и возвращает её в новом состоянии
+func (s *RentalService) review(ctx context.Context, id uint, status models.RentalRequestStatus, reason string, reviewerID uint) (*models.RentalRequest, error) {
+	reviewed, err := s.rentalRepo.Review(ctx, id, status, reason, reviewerID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	request, err := s.rentalRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRentalNotFound
+		}
+		return nil, err
+	}
+	if !reviewed {
+		return nil, ErrRentalReviewed
+	}
+	return request, nil
+}
+
+// notify отправляет заявителю письмо о решении через очередь фоновых задач; без SMTP письма не отправляются
+func (s *RentalService) notify(request *models.RentalRequest) {
+	if s.mailer == nil {
+		return
+	}
+	subject, body := s.decisionEmail(request)
+	err := s.tasks.Submit("rental_email", func(ctx context.Context) {
+		if err := s.mailer.Send(ctx, request.Email, subject, body); err != nil {
+			s.logger.Error("failed to email rental decision", "rental_request_id", request.ID, "error", err)
+		}
+	})
+	if err != nil {
+		s.logger.Warn("rental decision email dropped", "rental_request_id", request.ID, "error", err)
+	}
+}
+
+// rentalEmail - тексты писем заявителю на одном языке
+type rentalEmail struct {
+	approvedSubject string
+	approvedBody    string // Имя, мероприятие, комната, начало, окончание
+	rejectedSubject string
+	rejectedBody    string // Имя, мероприятие, комната, начало, окончание
+	reason          string // Причина отказа
+}
+
+var rentalEmails = map[string]rentalEmail{
+	i18n.RU: {
+		approvedSubject: "Заявка на аренду одобрена",
+		approvedBody:    "Здравствуйте, %s!\n\nВаша заявка «%s» на аренду комнаты «%s» одобрена.\nВремя: %s - %s.\n\nЖдём вас!\n",
+		rejectedSubject: "Заявка на аренду отклонена",
+		rejectedBody:    "Здравствуйте, %s!\n\nК сожалению, ваша заявка «%s» на аренду комнаты «%s» (%s - %s) отклонена.\n",
+		reason:          "Причина: %s\n",
+	},
+	i18n.EN: {
+		approvedSubject: "Your rental request is approved",
+		approvedBody:    "Hello %s,\n\nYour request \"%s\" to rent the room \"%s\" is approved.\nTime: %s - %s.\n\nSee you soon!\n",
+		rejectedSubject: "Your rental request is declined",
+		rejectedBody:    "Hello %s,\n\nUnfortunately, your request \"%s\" to rent the room \"%s\" (%s - %s) is declined.\n",
+		reason:          "Reason: %s\n",
+	},
+}
+
+// decisionEmail формирует письмо о решении на языке заявки; время - в часовом поясе комнаты
+func (s *RentalService) decisionEmail(request *models.RentalRequest) (string, string) {
+	texts, ok := rentalEmails[request.Language]
+	if !ok {
+		texts = rentalEmails[i18n.Default]
+	}
+	loc := roomLocation(&request.Room)
+	if loc == nil {
+		loc = s.loc
+	}
+	const layout = "02.01.2006 15:04 MST"
+	start, end := request.StartTime.In(loc).Format(layout), request.EndTime.In(loc).Format(layout)
+
+	if request.Status == models.RentalRequestApproved {
+		return texts.approvedSubject, fmt.Sprintf(texts.approvedBody, request.Name, request.Title, request.Room.Name, start, end)
+	}
+	body := fmt.Sprintf(texts.rejectedBody, request.Name, request.Title, request.Room.Name, start, end)
+	if request.RejectReason != "" {
+		body += fmt.Sprintf(texts.reason, request.RejectReason)
+	}
+	return texts.rejectedSubject, body
+}
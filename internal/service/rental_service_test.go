@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/pkg/captcha"
+	"github.com/space/backend/pkg/validator"
+	"gorm.io/gorm"
+)
+
+type fakeRentalStore struct {
+	RentalStore
+	requests map[uint]*models.RentalRequest
+	rooms    *fakeRoomStore
+}
+
+func (f *fakeRentalStore) GetByID(ctx context.Context, id uint) (*models.RentalRequest, error) {
+	request, ok := f.requests[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *request
+	copied.Room = *f.rooms.rooms[request.RoomID]
+	return &copied, nil
+}
+
+func (f *fakeRentalStore) Create(ctx context.Context, request *models.RentalRequest) error {
+	request.ID = uint(len(f.requests) + 1)
+	stored := *request
+	f.requests[request.ID] = &stored
+	return nil
+}
+
+func (f *fakeRentalStore) Review(ctx context.Context, id uint, status models.RentalRequestStatus, reason string, reviewerID uint, now time.Time) (bool, error) {
+	request, ok := f.requests[id]
+	if !ok || request.Status != models.RentalRequestPending {
+		return false, nil
+	}
+	request.Status = status
+	request.RejectReason = reason
+	request.ReviewedByID = &reviewerID
+	request.ReviewedAt = &now
+	return true, nil
+}
+
+func (f *fakeRentalStore) SetBooking(ctx context.Context, id, bookingID uint) error {
+	f.requests[id].BookingID = &bookingID
+	return nil
+}
+
+// fakeCaptcha принимает только токен "solved"
+type fakeCaptcha struct{}
+
+func (fakeCaptcha) Verify(ctx context.Context, token, remoteIP string) error {
+	if token != "solved" {
+		return captcha.ErrInvalidToken
+	}
+	return nil
+}
+
+type sentEmail struct {
+	to, subject, body string
+}
+
+type fakeMailer struct {
+	sent []sentEmail
+}
+
+func (f *fakeMailer) Send(ctx context.Context, to, subject, body string) error {
+	f.sent = append(f.sent, sentEmail{to: to, subject: subject, body: body})
+	return nil
+}
+
+func TestRentalService_SubmitAndReview(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	rooms := &fakeRoomStore{rooms: map[uint]*models.Room{
+		1: {ID: 1, Name: "Hall", IsActive: true, Kind: models.RoomKindRoom},
+		2: {ID: 2, Name: "P1", IsActive: true, Kind: models.RoomKindParking},
+	}}
+	users := &fakeUserStore{users: map[uint]*models.User{1: {ID: 1, Role: models.RoleAdmin}}}
+	bookings := newFakeBookingStore()
+	bookingService := NewBookingService(fakeTx{}, bookings, rooms, users, nil, nil, nil, slog.Default())
+	rentals := &fakeRentalStore{requests: map[uint]*models.RentalRequest{}, rooms: rooms}
+	mailer := &fakeMailer{}
+	svc := NewRentalService(fakeTx{}, rentals, rooms, bookingService, fakeCaptcha{}, inlineTaskQueue{}, time.UTC, slog.Default())
+	svc.SetMailer(mailer)
+	ctx := context.Background()
+
+	input := func(hour int) RentalRequestInput {
+		from := start.Add(time.Duration(hour) * time.Hour)
+		return RentalRequestInput{
+			RoomID: 1, Name: "Anna", Email: " Anna <anna@example.com> ", Organization: "Acme",
+			Title: "Workshop", StartTime: from, EndTime: from.Add(2 * time.Hour), CaptchaToken: "solved",
+		}
+	}
+
+	forged := input(0)
+	forged.CaptchaToken = "forged"
+	if _, err := svc.Submit(ctx, forged, "ru", ""); !errors.Is(err, ErrCaptchaFailed) {
+		t.Fatalf("Expected ErrCaptchaFailed, got: %v", err)
+	}
+	invalid := input(0)
+	invalid.Email = "not an email"
+	var fieldErrs validator.Errors
+	if _, err := svc.Submit(ctx, invalid, "ru", ""); !errors.As(err, &fieldErrs) || fieldErrs[0].Field != "email" {
+		t.Errorf("Expected an email field error, got: %v", err)
+	}
+	parking := input(0)
+	parking.RoomID = 2
+	if _, err := svc.Submit(ctx, parking, "ru", ""); !errors.Is(err, ErrRoomNotRentable) {
+		t.Errorf("Expected ErrRoomNotRentable, got: %v", err)
+	}
+
+	request, err := svc.Submit(ctx, input(0), "ru-RU", "203.0.113.7")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if request.Status != models.RentalRequestPending || request.Email != "anna@example.com" || request.Language != "ru" {
+		t.Errorf("Expected a pending request with a normalized email, got: %+v", request)
+	}
+	if len(bookings.bookings) != 0 {
+		t.Fatal("Expected no booking before approval")
+	}
+
+	approved, err := svc.Approve(ctx, request.ID, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if approved.Status != models.RentalRequestApproved || approved.BookingID == nil {
+		t.Fatalf("Expected an approved request with a booking, got: %+v", approved)
+	}
+	if booking := bookings.bookings[*approved.BookingID]; booking.CreatorID != 1 || booking.Title != "Workshop" {
+		t.Errorf("Expected the booking to be created by the reviewer, got: %+v", booking)
+	}
+	if len(mailer.sent) != 1 || mailer.sent[0].to != "anna@example.com" || mailer.sent[0].subject != "Заявка на аренду одобрена" {
+		t.Errorf("Expected an approval email in Russian, got: %+v", mailer.sent)
+	}
+	if _, err := svc.Approve(ctx, request.ID, 1); !errors.Is(err, ErrRentalReviewed) {
+		t.Errorf("Expected ErrRentalReviewed, got: %v", err)
+	}
+
+	// Одобренная заявка заняла время - пересекающаяся заявка отклоняется сразу
+	if _, err := svc.Submit(ctx, input(1), "en", ""); !errors.Is(err, ErrRentalUnavailable) {
+		t.Errorf("Expected ErrRentalUnavailable, got: %v", err)
+	}
+
+	second, err := svc.Submit(ctx, input(4), "en", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	rejected, err := svc.Reject(ctx, second.ID, 1, "Closed for maintenance")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if rejected.Status != models.RentalRequestRejected || rejected.BookingID != nil {
+		t.Errorf("Expected a rejected request without a booking, got: %+v", rejected)
+	}
+	if email := mailer.sent[len(mailer.sent)-1]; email.subject != "Your rental request is declined" || !strings.Contains(email.body, "Reason: Closed for maintenance") {
+		t.Errorf("Expected a rejection email with the reason, got: %+v", email)
+	}
+	if _, err := svc.Reject(ctx, 99, 1, ""); !errors.Is(err, ErrRentalNotFound) {
+		t.Errorf("Expected ErrRentalNotFound, got: %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/pkg/apperror"
+)
+
+var (
+	ErrInvalidRetentionEntity = apperror.BadRequest("invalid_retention_entity", "unknown retention entity")
+	ErrInvalidRetentionAge    = apperror.BadRequest("invalid_retention_age", "max_age_days must be at least 1")
+)
+
+// SetRetentionRuleRequest represents the retention rule of an entity
+type SetRetentionRuleRequest struct {
+	MaxAgeDays int   `json:"max_age_days" binding:"required"`
+	Enabled    *bool `json:"enabled"` // По умолчанию включено
+}
+
+// RetentionReport is what a retention rule deletes (preview) or deleted (enforcement)
+type RetentionReport struct {
+	Entity     string    `json:"entity"`
+	MaxAgeDays int       `json:"max_age_days"`
+	Cutoff     time.Time `json:"cutoff"`
+	Matched    int64     `json:"matched"`          // Удаляется (или удалено при применении)
+	Exempt     int64     `json:"exempt,omitempty"` // Старше срока, но защищено флагом исключения
+}
+
+// RetentionService manages per-entity retention rules and enforces them
+// Правила дополняют AUDIT_RETENTION_DAYS и SOFT_DELETE_RETENTION_DAYS: строка удаляется по
+// самому короткому из применимых сроков
+type RetentionService struct {
+	txManager     TxRunner
+	retentionRepo RetentionStore
+	logger        *slog.Logger
+}
+
+// NewRetentionService creates a new retention service
+func NewRetentionService(txManager TxRunner, retentionRepo RetentionStore, logger *slog.Logger) *RetentionService {
+	return &RetentionService{
+		txManager:     txManager,
+		retentionRepo: retentionRepo,
+		logger:        logger,
+	}
+}
+
+// ListRules returns all configured retention rules
+func (s *RetentionService) ListRules(ctx context.Context) ([]models.RetentionRule, error) {
+	return s.retentionRepo.ListRules(ctx)
+}
+
+// SetRule creates or replaces the retention rule of an entity
+func (s *RetentionService) SetRule(ctx context.Context, entity string, req SetRetentionRuleRequest) (*models.RetentionRule, error) {
+	if !slices.Contains(models.RetentionEntities, entity) {
+		return nil, ErrInvalidRetentionEntity
+	}
+	if req.MaxAgeDays < 1 {
+		return nil, ErrInvalidRetentionAge
+	}
+
+	rule := &models.RetentionRule{
+		Entity:     entity,
+		MaxAgeDays: req.MaxAgeDays,
+		Enabled:    req.Enabled == nil || *req.Enabled,
+	}
+	if err := s.retentionRepo.UpsertRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// DeleteRule removes the retention rule of an entity; its rows are kept indefinitely again
+func (s *RetentionService) DeleteRule(ctx context.Context, entity string) error {
+	if !slices.Contains(models.RetentionEntities, entity) {
+		return ErrInvalidRetentionEntity
+	}
+	return s.retentionRepo.DeleteRule(ctx, entity)
+}
+
+// SetBookingExempt protects a booking from retention rules or lifts the protection
+func (s *RetentionService) SetBookingExempt(ctx context.Context, bookingID uint, exempt bool) error {
+	return s.retentionRepo.SetBookingExempt(ctx, bookingID, exempt)
+}
+
+// Preview reports what the enabled rules would delete now without deleting anything
+func (s *RetentionService) Preview(ctx context.Context) ([]RetentionReport, error) {
+	return s.run(ctx, false)
+}
+
+// Enforce permanently deletes rows expired by the enabled rules in one transaction
+func (s *RetentionService) Enforce(ctx context.Context) ([]RetentionReport, error) {
+	var reports []RetentionReport
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		reports, err = s.run(ctx, true)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, report := range reports {
+		s.logger.Info("retention rule enforced",
+			"entity", report.Entity,
+			"cutoff", report.Cutoff,
+			"deleted", report.Matched,
+			"exempt", report.Exempt,
+		)
+	}
+	return reports, nil
+}
+
+// RunScheduled enforces the retention rules, used by the background job
+func (s *RetentionService) RunScheduled(ctx context.Context) error {
+	_, err := s.Enforce(ctx)
+	return err
+}
+
+// run считает (или удаляет при enforce) строки по каждому включённому правилу
+func (s *RetentionService) run(ctx context.Context, enforce bool) ([]RetentionReport, error) {
+	rules, err := s.retentionRepo.ListRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	reports := []RetentionReport{}
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		report := RetentionReport{
+			Entity:     rule.Entity,
+			MaxAgeDays: rule.MaxAgeDays,
+			Cutoff:     now.AddDate(0, 0, -rule.MaxAgeDays),
+		}
+
+		switch rule.Entity {
+		case models.RetentionEntityBookings:
+			// Исключённые считаются всегда: при применении отчёт показывает, сколько осталось под защитой
+			report.Matched, report.Exempt, err = s.retentionRepo.CountExpiredBookings(ctx, report.Cutoff)
+			if err == nil && enforce {
+				report.Matched, err = s.retentionRepo.DeleteExpiredBookings(ctx, report.Cutoff)
+			}
+		case models.RetentionEntityAuditLogs:
+			if enforce {
+				report.Matched, err = s.retentionRepo.DeleteExpiredAuditLogs(ctx, report.Cutoff)
+			} else {
+				report.Matched, err = s.retentionRepo.CountExpiredAuditLogs(ctx, report.Cutoff)
+			}
+		default:
+			// Правило сущности, которую эта версия не знает (например, после отката) - пропускается
+			s.logger.Warn("unknown retention entity skipped", "entity", rule.Entity)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", rule.Entity, err)
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/models"
+)
+
+// fakeRetentionStore хранит правила в памяти и удаляет записи старше cutoff из списков дат
+type fakeRetentionStore struct {
+	RetentionStore
+	rules     map[string]models.RetentionRule
+	bookings  []time.Time // Окончания неисключённых бронирований
+	exempt    []time.Time // Окончания исключённых бронирований
+	auditLogs []time.Time
+}
+
+func (f *fakeRetentionStore) ListRules(ctx context.Context) ([]models.RetentionRule, error) {
+	var rules []models.RetentionRule
+	for _, entity := range models.RetentionEntities {
+		if rule, ok := f.rules[entity]; ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func (f *fakeRetentionStore) UpsertRule(ctx context.Context, rule *models.RetentionRule) error {
+	f.rules[rule.Entity] = *rule
+	return nil
+}
+
+func (f *fakeRetentionStore) CountExpiredBookings(ctx context.Context, cutoff time.Time) (int64, int64, error) {
+	return countBefore(f.bookings, cutoff), countBefore(f.exempt, cutoff), nil
+}
+
+func (f *fakeRetentionStore) DeleteExpiredBookings(ctx context.Context, cutoff time.Time) (int64, error) {
+	var deleted int64
+	f.bookings, deleted = deleteBefore(f.bookings, cutoff)
+	return deleted, nil
+}
+
+func (f *fakeRetentionStore) CountExpiredAuditLogs(ctx context.Context, cutoff time.Time) (int64, error) {
+	return countBefore(f.auditLogs, cutoff), nil
+}
+
+func (f *fakeRetentionStore) DeleteExpiredAuditLogs(ctx context.Context, cutoff time.Time) (int64, error) {
+	var deleted int64
+	f.auditLogs, deleted = deleteBefore(f.auditLogs, cutoff)
+	return deleted, nil
+}
+
+func countBefore(times []time.Time, cutoff time.Time) int64 {
+	var count int64
+	for _, at := range times {
+		if at.Before(cutoff) {
+			count++
+		}
+	}
+	return count
+}
+
+func deleteBefore(times []time.Time, cutoff time.Time) ([]time.Time, int64) {
+	var kept []time.Time
+	for _, at := range times {
+		if !at.Before(cutoff) {
+			kept = append(kept, at)
+		}
+	}
+	return kept, int64(len(times) - len(kept))
+}
+
+func TestRetentionService(t *testing.T) {
+	now := time.Now()
+	store := &fakeRetentionStore{
+		rules:     map[string]models.RetentionRule{},
+		bookings:  []time.Time{now.AddDate(-3, 0, 0), now.AddDate(0, -1, 0)},
+		exempt:    []time.Time{now.AddDate(-3, 0, 0)},
+		auditLogs: []time.Time{now.AddDate(0, 0, -100), now.AddDate(0, 0, -10)},
+	}
+	svc := NewRetentionService(fakeTx{}, store, slog.Default())
+	ctx := context.Background()
+
+	if _, err := svc.SetRule(ctx, "notification_deliveries", SetRetentionRuleRequest{MaxAgeDays: 30}); !errors.Is(err, ErrInvalidRetentionEntity) {
+		t.Errorf("Expected ErrInvalidRetentionEntity, got: %v", err)
+	}
+	if _, err := svc.SetRule(ctx, models.RetentionEntityBookings, SetRetentionRuleRequest{MaxAgeDays: -1}); !errors.Is(err, ErrInvalidRetentionAge) {
+		t.Errorf("Expected ErrInvalidRetentionAge, got: %v", err)
+	}
+
+	disabled := false
+	if _, err := svc.SetRule(ctx, models.RetentionEntityBookings, SetRetentionRuleRequest{MaxAgeDays: 365}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if _, err := svc.SetRule(ctx, models.RetentionEntityAuditLogs, SetRetentionRuleRequest{MaxAgeDays: 30, Enabled: &disabled}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Выключенное правило не попадает ни в предпросмотр, ни в применение
+	preview, err := svc.Preview(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(preview) != 1 || preview[0].Entity != models.RetentionEntityBookings || preview[0].Matched != 1 || preview[0].Exempt != 1 {
+		t.Fatalf("Expected 1 expired and 1 exempt booking, got: %+v", preview)
+	}
+	if len(store.bookings) != 2 {
+		t.Errorf("Expected preview to delete nothing, got: %v", store.bookings)
+	}
+
+	reports, err := svc.Enforce(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(reports) != 1 || reports[0].Matched != 1 || len(store.bookings) != 1 || len(store.exempt) != 1 {
+		t.Errorf("Expected only the expired non-exempt booking to be deleted, got: %+v", reports)
+	}
+	if len(store.auditLogs) != 2 {
+		t.Errorf("Expected audit logs to be kept by the disabled rule, got: %d", len(store.auditLogs))
+	}
+}
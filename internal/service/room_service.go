@@ -1,81 +1,194 @@
 package service
 
 import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/space/backend/internal/metrics"
 	"github.com/space/backend/internal/models"
-	"github.com/space/backend/internal/repository"
+	"github.com/space/backend/pkg/apperror"
+	"github.com/space/backend/pkg/validator"
+)
+
+var (
+	ErrInvalidTimezone    = apperror.BadRequest("invalid_timezone", "timezone must be an IANA timezone such as Europe/Moscow")
+	ErrInvalidRoomKind    = apperror.BadRequest("invalid_room_kind", "kind must be room or parking")
+	ErrInvalidHourlyPrice = apperror.BadRequest("invalid_hourly_price", "hourly_price must not be negative")
 )
 
 // RoomService handles room business logic
 type RoomService struct {
-	roomRepo      *repository.RoomRepository
-	equipmentRepo *repository.EquipmentRepository
+	roomRepo      RoomStore
+	equipmentRepo EquipmentStore
+	feedbackRepo  FeedbackStore // nil - комнаты отдаются без оценок
+	cache         *roomListCache
 }
 
 // NewRoomService creates a new room service
-func NewRoomService(roomRepo *repository.RoomRepository, equipmentRepo *repository.EquipmentRepository) *RoomService {
+// Списки комнат кэшируются на cacheTTL (0 - без кэша); изменения через сервис сбрасывают кэш.
+// Оценки комнат кэшируются вместе со списком - новые отзывы появляются в нём через cacheTTL
+func NewRoomService(roomRepo RoomStore, equipmentRepo EquipmentStore, feedbackRepo FeedbackStore, cacheTTL time.Duration) *RoomService {
 	return &RoomService{
 		roomRepo:      roomRepo,
 		equipmentRepo: equipmentRepo,
+		feedbackRepo:  feedbackRepo,
+		cache:         newRoomListCache(cacheTTL),
 	}
 }
 
-// GetAllRooms gets all active rooms
-func (s *RoomService) GetAllRooms() ([]models.Room, error) {
-	return s.roomRepo.GetAll()
+// GetAllRooms gets all active rooms in the given order (empty - by name)
+// Возвращаемый срез может быть общим с кэшем - вызывающий не должен его изменять
+func (s *RoomService) GetAllRooms(ctx context.Context, order string) ([]models.Room, error) {
+	return s.cache.get("rooms:"+order, func() ([]models.Room, error) {
+		rooms, err := s.roomRepo.GetAll(ctx, order)
+		if err != nil {
+			return nil, err
+		}
+		return s.withRatings(ctx, rooms)
+	})
 }
 
 // GetAllRoomsWithEquipment gets all rooms with their equipment and instructions
-func (s *RoomService) GetAllRoomsWithEquipment() ([]models.Room, error) {
-	return s.roomRepo.GetAllWithEquipment()
+func (s *RoomService) GetAllRoomsWithEquipment(ctx context.Context, order string) ([]models.Room, error) {
+	return s.cache.get("equipment:"+order, func() ([]models.Room, error) {
+		rooms, err := s.roomRepo.GetAllWithEquipment(ctx, order)
+		if err != nil {
+			return nil, err
+		}
+		return s.withRatings(ctx, rooms)
+	})
+}
+
+// InvalidateRoomList drops cached room lists after rooms were changed outside the service (import)
+func (s *RoomService) InvalidateRoomList() {
+	s.cache.invalidate()
 }
 
-// GetRoom gets a room by ID with equipment
-func (s *RoomService) GetRoom(id uint) (*models.Room, error) {
-	return s.roomRepo.GetByID(id)
+// GetRoom gets a room by ID with equipment and rating
+func (s *RoomService) GetRoom(ctx context.Context, id uint) (*models.Room, error) {
+	room, err := s.roomRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	ratings, err := s.ratings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	room.Rating = ratings[room.ID]
+	return room, nil
+}
+
+// withRatings дополняет загруженные комнаты оценками по отзывам
+func (s *RoomService) withRatings(ctx context.Context, rooms []models.Room) ([]models.Room, error) {
+	ratings, err := s.ratings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rooms {
+		rooms[i].Rating = ratings[rooms[i].ID]
+	}
+	return rooms, nil
+}
+
+// ratings возвращает оценки комнат за всё время по ID комнаты
+func (s *RoomService) ratings(ctx context.Context) (map[uint]*models.RoomRating, error) {
+	if s.feedbackRepo == nil {
+		return nil, nil
+	}
+	stats, err := s.feedbackRepo.RoomRatingStats(ctx, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	ratings := make(map[uint]*models.RoomRating, len(stats))
+	for _, stat := range stats {
+		ratings[stat.RoomID] = &models.RoomRating{Average: roundRating(stat.Average), Count: stat.Count}
+	}
+	return ratings, nil
 }
 
 // GetRoomEquipment gets all equipment for a specific room
-func (s *RoomService) GetRoomEquipment(roomID uint) ([]models.Equipment, error) {
-	return s.equipmentRepo.GetByRoomID(roomID)
+func (s *RoomService) GetRoomEquipment(ctx context.Context, roomID uint) ([]models.Equipment, error) {
+	return s.equipmentRepo.GetByRoomID(ctx, roomID)
 }
 
 // CreateRoomRequest represents a request to create a room
 type CreateRoomRequest struct {
-	Name        string      `json:"name" binding:"required"`
-	Description string      `json:"description"`
-	Capacity    int         `json:"capacity"`
-	Attributes  interface{} `json:"attributes"`
+	Name            string      `json:"name" binding:"required"`
+	Description     string      `json:"description"`
+	Capacity        int         `json:"capacity"`
+	Attributes      interface{} `json:"attributes"`
+	Timezone        string      `json:"timezone"`          // IANA; пусто - часовой пояс пространства
+	Kind            string      `json:"kind"`              // room (по умолчанию) или parking
+	ResetRequired   bool        `json:"reset_required"`    // Создавать задачу уборки после использования
+	ResetAfterHours int         `json:"reset_after_hours"` // Уборка после стольких часов использования (0 - после каждого бронирования)
+	AnnounceStart   bool        `json:"announce_start"`    // Объявлять скорое начало бронирований в групповом чате
+	HourlyPrice     int64       `json:"hourly_price"`      // Цена часа в минимальных единицах валюты; 0 - бесплатно
 }
 
 // CreateRoom creates a new room (admin only)
-func (s *RoomService) CreateRoom(req CreateRoomRequest) (*models.Room, error) {
+func (s *RoomService) CreateRoom(ctx context.Context, req CreateRoomRequest) (*models.Room, error) {
+	// Ошибки полей возвращаются разом
+	var fieldErrs validator.Errors
+	if req.ResetAfterHours < 0 {
+		fieldErrs.Add("reset_after_hours", ErrInvalidResetHours)
+	}
+	if req.HourlyPrice < 0 {
+		fieldErrs.Add("hourly_price", ErrInvalidHourlyPrice)
+	}
+	if !validTimezone(req.Timezone) {
+		fieldErrs.Add("timezone", ErrInvalidTimezone)
+	}
+	kind := models.RoomKind(req.Kind)
+	switch kind {
+	case "":
+		kind = models.RoomKindRoom
+	case models.RoomKindRoom, models.RoomKindParking:
+	default:
+		fieldErrs.Add("kind", ErrInvalidRoomKind)
+	}
+	if err := fieldErrs.Err(); err != nil {
+		return nil, err
+	}
 	room := &models.Room{
-		Name:        req.Name,
-		Description: req.Description,
-		Capacity:    req.Capacity,
-		IsActive:    true,
+		Name:            req.Name,
+		Description:     req.Description,
+		Capacity:        req.Capacity,
+		IsActive:        true,
+		Timezone:        req.Timezone,
+		Kind:            kind,
+		ResetRequired:   req.ResetRequired,
+		ResetAfterHours: req.ResetAfterHours,
+		AnnounceStart:   req.AnnounceStart,
+		HourlyPrice:     req.HourlyPrice,
 	}
 
-	err := s.roomRepo.Create(room)
+	err := s.roomRepo.Create(ctx, room)
 	if err != nil {
 		return nil, err
 	}
+	s.cache.invalidate()
 
-	return s.roomRepo.GetByID(room.ID)
+	return s.roomRepo.GetByID(ctx, room.ID)
 }
 
 // UpdateRoomRequest represents a request to update a room
 type UpdateRoomRequest struct {
-	Name        *string     `json:"name"`
-	Description *string     `json:"description"`
-	Capacity    *int        `json:"capacity"`
-	IsActive    *bool       `json:"is_active"`
-	Attributes  interface{} `json:"attributes"`
+	Name            *string     `json:"name"`
+	Description     *string     `json:"description"`
+	Capacity        *int        `json:"capacity"`
+	IsActive        *bool       `json:"is_active"`
+	Attributes      interface{} `json:"attributes"`
+	Timezone        *string     `json:"timezone"` // Пустая строка - часовой пояс пространства
+	ResetRequired   *bool       `json:"reset_required"`
+	ResetAfterHours *int        `json:"reset_after_hours"`
+	AnnounceStart   *bool       `json:"announce_start"`
+	HourlyPrice     *int64      `json:"hourly_price"` // Действует для новых и переносимых бронирований
 }
 
 // UpdateRoom updates a room (admin only)
-func (s *RoomService) UpdateRoom(id uint, req UpdateRoomRequest) (*models.Room, error) {
-	room, err := s.roomRepo.GetByID(id)
+func (s *RoomService) UpdateRoom(ctx context.Context, id uint, req UpdateRoomRequest) (*models.Room, error) {
+	room, err := s.roomRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -92,16 +205,128 @@ func (s *RoomService) UpdateRoom(id uint, req UpdateRoomRequest) (*models.Room,
 	if req.IsActive != nil {
 		room.IsActive = *req.IsActive
 	}
+	var fieldErrs validator.Errors
+	if req.Timezone != nil {
+		if !validTimezone(*req.Timezone) {
+			fieldErrs.Add("timezone", ErrInvalidTimezone)
+		}
+		room.Timezone = *req.Timezone
+	}
+	if req.ResetAfterHours != nil {
+		if *req.ResetAfterHours < 0 {
+			fieldErrs.Add("reset_after_hours", ErrInvalidResetHours)
+		}
+		room.ResetAfterHours = *req.ResetAfterHours
+	}
+	if req.ResetRequired != nil {
+		// Учёт использования начинается заново с момента включения уборки
+		if *req.ResetRequired && !room.ResetRequired {
+			room.CleaningCursor = nil
+			room.UsageMinutes = 0
+		}
+		room.ResetRequired = *req.ResetRequired
+	}
+	if req.AnnounceStart != nil {
+		room.AnnounceStart = *req.AnnounceStart
+	}
+	if req.HourlyPrice != nil {
+		if *req.HourlyPrice < 0 {
+			fieldErrs.Add("hourly_price", ErrInvalidHourlyPrice)
+		}
+		room.HourlyPrice = *req.HourlyPrice
+	}
+	if err := fieldErrs.Err(); err != nil {
+		return nil, err
+	}
 
-	err = s.roomRepo.Update(room)
+	err = s.roomRepo.Update(ctx, room)
 	if err != nil {
 		return nil, err
 	}
+	s.cache.invalidate()
+
+	return s.roomRepo.GetByID(ctx, id)
+}
+
+// validTimezone проверяет часовой пояс комнаты; пустой - часовой пояс пространства
+func validTimezone(name string) bool {
+	if name == "" {
+		return true
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
 
-	return s.roomRepo.GetByID(id)
+// roomLocation возвращает часовой пояс комнаты или nil, если у комнаты он не задан
+func roomLocation(room *models.Room) *time.Location {
+	if room == nil || room.Timezone == "" {
+		return nil
+	}
+	loc, err := time.LoadLocation(room.Timezone)
+	if err != nil {
+		return nil
+	}
+	return loc
 }
 
 // DeleteRoom soft deletes a room (admin only)
-func (s *RoomService) DeleteRoom(id uint) error {
-	return s.roomRepo.Delete(id)
+func (s *RoomService) DeleteRoom(ctx context.Context, id uint) error {
+	if err := s.roomRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.cache.invalidate()
+	return nil
+}
+
+// roomListCache хранит списки комнат по виду и сортировке
+// Комнаты меняются редко, а список запрашивается при каждом открытии Mini App
+type roomListCache struct {
+	ttl        time.Duration
+	mu         sync.Mutex
+	entries    map[string]roomListEntry
+	generation uint64 // Увеличивается при сбросе: результат загрузки, начатой до сброса, не сохраняется
+}
+
+type roomListEntry struct {
+	rooms     []models.Room
+	expiresAt time.Time
+}
+
+func newRoomListCache(ttl time.Duration) *roomListCache {
+	return &roomListCache{ttl: ttl, entries: make(map[string]roomListEntry)}
+}
+
+func (c *roomListCache) get(key string, load func() ([]models.Room, error)) ([]models.Room, error) {
+	if c.ttl <= 0 {
+		return load()
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	generation := c.generation
+	c.mu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		metrics.RoomCacheHits.Add(1)
+		return entry.rooms, nil
+	}
+	metrics.RoomCacheMisses.Add(1)
+
+	rooms, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if c.generation == generation {
+		c.entries[key] = roomListEntry{rooms: rooms, expiresAt: time.Now().Add(c.ttl)}
+	}
+	c.mu.Unlock()
+	return rooms, nil
+}
+
+func (c *roomListCache) invalidate() {
+	c.mu.Lock()
+	c.entries = make(map[string]roomListEntry)
+	c.generation++
+	c.mu.Unlock()
 }
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/internal/repository"
+)
+
+// countingRoomStore считает загрузки списка комнат
+type countingRoomStore struct {
+	*fakeRoomStateRoomStore
+	loads int
+}
+
+func (f *countingRoomStore) GetAll(ctx context.Context, order string) ([]models.Room, error) {
+	f.loads++
+	return f.fakeRoomStateRoomStore.GetAll(ctx, order)
+}
+
+func (f *countingRoomStore) Update(ctx context.Context, room *models.Room) error {
+	f.rooms[room.ID] = room
+	return nil
+}
+
+func TestRoomService_ListCache(t *testing.T) {
+	store := &countingRoomStore{fakeRoomStateRoomStore: &fakeRoomStateRoomStore{&fakeRoomStore{rooms: map[uint]*models.Room{
+		1: {ID: 1, Name: "Big Room", IsActive: true},
+	}}}}
+	svc := NewRoomService(store, nil, nil, time.Minute)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := svc.GetAllRooms(ctx, ""); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+	if store.loads != 1 {
+		t.Errorf("Expected one load for repeated requests, got: %d", store.loads)
+	}
+
+	// Другая сортировка - отдельная запись кэша
+	_, _ = svc.GetAllRooms(ctx, "capacity DESC")
+	if store.loads != 2 {
+		t.Errorf("Expected a separate load per order, got: %d", store.loads)
+	}
+
+	name := "Renamed"
+	if _, err := svc.UpdateRoom(ctx, 1, UpdateRoomRequest{Name: &name}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	rooms, _ := svc.GetAllRooms(ctx, "")
+	if store.loads != 3 || rooms[0].Name != "Renamed" {
+		t.Errorf("Expected update to invalidate the cache, got %d loads and %+v", store.loads, rooms)
+	}
+
+	uncached := NewRoomService(store, nil, nil, 0)
+	_, _ = uncached.GetAllRooms(ctx, "")
+	_, _ = uncached.GetAllRooms(ctx, "")
+	if store.loads != 5 {
+		t.Errorf("Expected zero TTL to disable the cache, got: %d", store.loads)
+	}
+}
+
+func TestRoomService_Ratings(t *testing.T) {
+	rooms := &fakeRoomStateRoomStore{&fakeRoomStore{rooms: map[uint]*models.Room{
+		1: {ID: 1, Name: "Big Room", IsActive: true},
+		2: {ID: 2, Name: "Small Room", IsActive: true},
+	}}}
+	feedback := &fakeFeedbackStore{stats: []repository.RoomRatingStats{{RoomID: 1, Average: 3.666, Count: 3}}}
+	svc := NewRoomService(rooms, nil, feedback, 0)
+
+	room, err := svc.GetRoom(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if room.Rating == nil || room.Rating.Average != 3.7 || room.Rating.Count != 3 {
+		t.Errorf("Expected rating 3.7 of 3 reviews, got: %+v", room.Rating)
+	}
+
+	list, err := svc.GetAllRooms(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	for _, r := range list {
+		if (r.ID == 1) != (r.Rating != nil) {
+			t.Errorf("Expected only the rated room to have a rating, got room %d: %+v", r.ID, r.Rating)
+		}
+	}
+}
+
+func TestRoomService_UpdateTimezone(t *testing.T) {
+	store := &countingRoomStore{fakeRoomStateRoomStore: &fakeRoomStateRoomStore{&fakeRoomStore{rooms: map[uint]*models.Room{
+		1: {ID: 1, Name: "Big Room", IsActive: true},
+	}}}}
+	svc := NewRoomService(store, nil, nil, 0)
+	ctx := context.Background()
+
+	invalid := "Mars/Olympus"
+	if _, err := svc.UpdateRoom(ctx, 1, UpdateRoomRequest{Timezone: &invalid}); !errors.Is(err, ErrInvalidTimezone) {
+		t.Errorf("Expected ErrInvalidTimezone, got: %v", err)
+	}
+
+	tz := "Asia/Novosibirsk"
+	room, err := svc.UpdateRoom(ctx, 1, UpdateRoomRequest{Timezone: &tz})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if room.Timezone != tz || roomLocation(room).String() != tz {
+		t.Errorf("Expected room timezone %s, got: %q", tz, room.Timezone)
+	}
+}
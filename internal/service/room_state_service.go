@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/space/backend/internal/models"
+)
+
+const (
+	RoomStateBooked = "booked"
+	RoomStateFree   = "free"
+)
+
+const (
+	// roomStateHorizon - насколько вперёд ищется следующее событие
+	roomStateHorizon = 24 * time.Hour
+	// roomStateRefreshInterval - как часто состояния публикуются заново, даже если не изменились
+	// (QoS 0 не гарантирует доставку, а брокер мог потерять retained-сообщения после перезапуска)
+	roomStateRefreshInterval = 15 * time.Minute
+)
+
+// StatePublisher delivers retained messages to a message broker (MQTT)
+type StatePublisher interface {
+	Publish(ctx context.Context, topic string, payload []byte, retain bool) error
+}
+
+// RoomStateEvent is a booking as seen by room displays; creator and participants are not published
+type RoomStateEvent struct {
+	Title     string    `json:"title"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
+
+// RoomState is the payload published to {prefix}/rooms/{id}
+type RoomState struct {
+	RoomID  uint            `json:"room_id"`
+	Name    string          `json:"name"`
+	State   string          `json:"state"` // booked или free
+	Current *RoomStateEvent `json:"current"`
+	Next    *RoomStateEvent `json:"next"`
+}
+
+// RoomStateService publishes room state (booked/free, current and next event) for Home Assistant and signage
+// Топики (retained): {prefix}/rooms/{id} - JSON RoomState, {prefix}/rooms/{id}/state - booked или free.
+// Публикуются только изменения; события бронирований обновляют комнату сразу, периодический запуск
+// отслеживает начало и конец встреч
+type RoomStateService struct {
+	publisher   StatePublisher
+	prefix      string
+	roomRepo    RoomStore
+	bookingRepo BookingStore
+	logger      *slog.Logger
+
+	mu          sync.Mutex
+	published   map[uint][]byte // Последний опубликованный payload по комнатам
+	refreshedAt time.Time
+}
+
+// NewRoomStateService creates a new room state service
+func NewRoomStateService(publisher StatePublisher, prefix string, roomRepo RoomStore, bookingRepo BookingStore, logger *slog.Logger) *RoomStateService {
+	return &RoomStateService{
+		publisher:   publisher,
+		prefix:      prefix,
+		roomRepo:    roomRepo,
+		bookingRepo: bookingRepo,
+		logger:      logger,
+		published:   make(map[uint][]byte),
+	}
+}
+
+// RunScheduled publishes changed states of all active rooms and clears topics of removed or deactivated rooms
+func (s *RoomStateService) RunScheduled(ctx context.Context) error {
+	now := time.Now()
+
+	rooms, err := s.roomRepo.GetAll(ctx, "")
+	if err != nil {
+		return err
+	}
+	bookings, err := s.bookingRepo.GetCalendarSummaries(ctx, now, now.Add(roomStateHorizon))
+	if err != nil {
+		return err
+	}
+	byRoom := make(map[uint][]models.BookingSummary)
+	for _, booking := range bookings {
+		byRoom[booking.RoomID] = append(byRoom[booking.RoomID], booking)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	force := now.Sub(s.refreshedAt) >= roomStateRefreshInterval
+	if force {
+		s.refreshedAt = now
+	}
+
+	var errs []error
+	active := make(map[uint]bool, len(rooms))
+	for i := range rooms {
+		active[rooms[i].ID] = true
+		state := buildRoomState(&rooms[i], byRoom[rooms[i].ID], now)
+		if err := s.publish(ctx, state, force); err != nil {
+			errs = append(errs, fmt.Errorf("room %d: %w", rooms[i].ID, err))
+		}
+	}
+	for roomID := range s.published {
+		if !active[roomID] {
+			if err := s.clear(ctx, roomID); err != nil {
+				errs = append(errs, fmt.Errorf("room %d: %w", roomID, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// HandleBookingEvent republishes the state of the booking's room after it was booked, moved, cancelled or released
+func (s *RoomStateService) HandleBookingEvent(ctx context.Context, event BookingEvent) error {
+	switch event.Type {
+	case EventBookingCreated, EventBookingCancelled, EventBookingReleased:
+	case EventBookingUpdated:
+		if !changesTime(event.Changes) {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	room, err := s.roomRepo.GetByID(ctx, event.Booking.RoomID)
+	if err != nil {
+		return err
+	}
+	if !room.IsActive {
+		return nil
+	}
+
+	now := time.Now()
+	summaries, err := s.bookingRepo.GetCalendarSummaries(ctx, now, now.Add(roomStateHorizon))
+	if err != nil {
+		return err
+	}
+	var bookings []models.BookingSummary
+	for _, booking := range summaries {
+		if booking.RoomID == room.ID {
+			bookings = append(bookings, booking)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.publish(ctx, buildRoomState(room, bookings, now), false)
+}
+
+// publish отправляет состояние, если оно изменилось с прошлой публикации; вызывается под s.mu
+func (s *RoomStateService) publish(ctx context.Context, state RoomState, force bool) error {
+	payload, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	if !force && string(s.published[state.RoomID]) == string(payload) {
+		return nil
+	}
+
+	topic := s.topic(state.RoomID)
+	if err := s.publisher.Publish(ctx, topic, payload, true); err != nil {
+		return err
+	}
+	if err := s.publisher.Publish(ctx, topic+"/state", []byte(state.State), true); err != nil {
+		return err
+	}
+	s.published[state.RoomID] = payload
+
+	s.logger.Debug("Room state published", "room_id", state.RoomID, "state", state.State)
+	return nil
+}
+
+// clear удаляет retained-сообщения комнаты: пустой retained payload стирает их у брокера
+func (s *RoomStateService) clear(ctx context.Context, roomID uint) error {
+	topic := s.topic(roomID)
+	if err := s.publisher.Publish(ctx, topic, nil, true); err != nil {
+		return err
+	}
+	if err := s.publisher.Publish(ctx, topic+"/state", nil, true); err != nil {
+		return err
+	}
+	delete(s.published, roomID)
+	return nil
+}
+
+func (s *RoomStateService) topic(roomID uint) string {
+	return s.prefix + "/rooms/" + strconv.FormatUint(uint64(roomID), 10)
+}
+
+// buildRoomState определяет текущее и следующее событие; bookings - активные бронирования комнаты, упорядоченные по началу
+func buildRoomState(room *models.Room, bookings []models.BookingSummary, now time.Time) RoomState {
+	state := RoomState{RoomID: room.ID, Name: room.Name, State: RoomStateFree}
+	for i := range bookings {
+		b := &bookings[i]
+		event := &RoomStateEvent{Title: b.Title, StartTime: b.StartTime.UTC(), EndTime: b.EndTime.UTC()}
+		switch {
+		case !b.StartTime.After(now) && b.EndTime.After(now):
+			if state.Current == nil {
+				state.State, state.Current = RoomStateBooked, event
+			}
+		case b.StartTime.After(now):
+			if state.Next == nil {
+				state.Next = event
+			}
+		}
+	}
+	return state
+}
+
+// changesTime проверяет, изменилось ли время бронирования
+func changesTime(changes []FieldChange) bool {
+	for _, c := range changes {
+		if c.Field == "start_time" || c.Field == "end_time" {
+			return true
+		}
+	}
+	return false
+}
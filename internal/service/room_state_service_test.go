@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/models"
+)
+
+type publishedMessage struct {
+	topic   string
+	payload string
+}
+
+type fakeStatePublisher struct {
+	messages []publishedMessage
+}
+
+func (f *fakeStatePublisher) Publish(ctx context.Context, topic string, payload []byte, retain bool) error {
+	if !retain {
+		panic("room state must be retained")
+	}
+	f.messages = append(f.messages, publishedMessage{topic: topic, payload: string(payload)})
+	return nil
+}
+
+// fakeRoomStateRoomStore возвращает активные комнаты, как RoomRepository.GetAll
+type fakeRoomStateRoomStore struct {
+	*fakeRoomStore
+}
+
+func (f *fakeRoomStateRoomStore) GetAll(ctx context.Context, order string) ([]models.Room, error) {
+	var rooms []models.Room
+	for _, room := range f.rooms {
+		if room.IsActive {
+			rooms = append(rooms, *room)
+		}
+	}
+	sort.Slice(rooms, func(i, j int) bool { return rooms[i].ID < rooms[j].ID })
+	return rooms, nil
+}
+
+// fakeRoomStateBookingStore выбирает сводки активных бронирований по времени, как BookingRepository
+type fakeRoomStateBookingStore struct {
+	*fakeBookingStore
+}
+
+func (f *fakeRoomStateBookingStore) GetCalendarSummaries(ctx context.Context, start, end time.Time) ([]models.BookingSummary, error) {
+	var summaries []models.BookingSummary
+	for _, b := range f.bookings {
+		if b.Status != models.BookingStatusCancelled && b.StartTime.Before(end) && b.EndTime.After(start) {
+			summaries = append(summaries, models.BookingSummary{ID: b.ID, RoomID: b.RoomID, Title: b.Title, StartTime: b.StartTime, EndTime: b.EndTime, Status: b.Status})
+		}
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].StartTime.Before(summaries[j].StartTime) })
+	return summaries, nil
+}
+
+func TestRoomStateService(t *testing.T) {
+	now := time.Now().Truncate(time.Minute)
+	rooms := &fakeRoomStateRoomStore{&fakeRoomStore{rooms: map[uint]*models.Room{
+		1: {ID: 1, Name: "Big Room", IsActive: true},
+		2: {ID: 2, Name: "Small Room", IsActive: true},
+	}}}
+	bookings := &fakeRoomStateBookingStore{newFakeBookingStore(
+		models.Booking{ID: 1, RoomID: 1, Title: "Standup", StartTime: now.Add(-10 * time.Minute), EndTime: now.Add(20 * time.Minute), Status: models.BookingStatusConfirmed},
+		models.Booking{ID: 2, RoomID: 1, Title: "Retro", StartTime: now.Add(time.Hour), EndTime: now.Add(2 * time.Hour), Status: models.BookingStatusConfirmed},
+	)}
+	publisher := &fakeStatePublisher{}
+	svc := NewRoomStateService(publisher, "office", rooms, bookings, slog.Default())
+	ctx := context.Background()
+
+	if err := svc.RunScheduled(ctx); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(publisher.messages) != 4 {
+		t.Fatalf("Expected 2 topics for each of 2 rooms, got: %+v", publisher.messages)
+	}
+	if publisher.messages[0].topic != "office/rooms/1" || publisher.messages[1] != (publishedMessage{"office/rooms/1/state", RoomStateBooked}) {
+		t.Errorf("Unexpected messages for room 1: %+v", publisher.messages[:2])
+	}
+	var state RoomState
+	if err := json.Unmarshal([]byte(publisher.messages[0].payload), &state); err != nil {
+		t.Fatalf("Invalid payload: %v", err)
+	}
+	if state.Current == nil || state.Current.Title != "Standup" || state.Next == nil || state.Next.Title != "Retro" {
+		t.Errorf("Expected Standup now and Retro next, got: %+v", state)
+	}
+	if publisher.messages[3] != (publishedMessage{"office/rooms/2/state", RoomStateFree}) {
+		t.Errorf("Expected room 2 to be free, got: %+v", publisher.messages[3])
+	}
+
+	// Без изменений ничего не публикуется
+	publisher.messages = nil
+	if err := svc.RunScheduled(ctx); err != nil || len(publisher.messages) != 0 {
+		t.Errorf("Expected no messages for unchanged state, got: %+v (%v)", publisher.messages, err)
+	}
+
+	// Отмена текущей встречи освобождает комнату сразу
+	_ = bookings.Cancel(ctx, 1)
+	if err := svc.HandleBookingEvent(ctx, BookingEvent{Type: EventBookingCancelled, Booking: &models.Booking{ID: 1, RoomID: 1}}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(publisher.messages) != 2 || publisher.messages[1] != (publishedMessage{"office/rooms/1/state", RoomStateFree}) {
+		t.Errorf("Expected room 1 to become free, got: %+v", publisher.messages)
+	}
+
+	// Деактивированная комната удаляется из retained-сообщений
+	publisher.messages = nil
+	rooms.rooms[2].IsActive = false
+	if err := svc.RunScheduled(ctx); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	want := []publishedMessage{{"office/rooms/2", ""}, {"office/rooms/2/state", ""}}
+	if len(publisher.messages) != 2 || publisher.messages[0] != want[0] || publisher.messages[1] != want[1] {
+		t.Errorf("Expected room 2 topics to be cleared, got: %+v", publisher.messages)
+	}
+}
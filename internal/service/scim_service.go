@@ -0,0 +1,867 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/internal/repository"
+	"github.com/space/backend/pkg/apperror"
+	"gorm.io/gorm"
+)
+
+// Схемы SCIM 2.0 (RFC 7643, RFC 7644)
+const (
+	SCIMSchemaUser         = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SCIMSchemaGroup        = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SCIMSchemaListResponse = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SCIMSchemaPatchOp      = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SCIMSchemaError        = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+var (
+	ErrSCIMInvalidFilter = apperror.BadRequest("invalidFilter", `unsupported filter: only 'attribute eq "value"' is supported`)
+	ErrSCIMInvalidValue  = apperror.BadRequest("invalidValue", "invalid value")
+	ErrSCIMUniqueness    = apperror.Conflict("uniqueness", "resource with this userName or externalId already exists")
+)
+
+// scimFilterPattern - единственная поддерживаемая форма фильтра: attribute eq "value"
+// Её используют Okta, Entra ID и Keycloak для поиска ресурса перед созданием
+var scimFilterPattern = regexp.MustCompile(`^\s*([A-Za-z][\w.]*)\s+(?i:eq)\s+"((?:[^"\\]|\\.)*)"\s*$`)
+
+// SCIMUser is the SCIM representation of a user
+type SCIMUser struct {
+	Schemas      []string         `json:"schemas"`
+	ID           string           `json:"id,omitempty"`
+	ExternalID   string           `json:"externalId,omitempty"`
+	UserName     string           `json:"userName"`
+	Name         *SCIMName        `json:"name,omitempty"`
+	DisplayName  string           `json:"displayName,omitempty"`
+	Emails       []SCIMMultiValue `json:"emails,omitempty"`
+	PhoneNumbers []SCIMMultiValue `json:"phoneNumbers,omitempty"`
+	Active       *bool            `json:"active,omitempty"`
+	Meta         *SCIMMeta        `json:"meta,omitempty"`
+}
+
+// SCIMName is the name of a SCIM user
+type SCIMName struct {
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// SCIMMultiValue is an item of a multi-valued attribute (emails, phoneNumbers, members)
+type SCIMMultiValue struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// SCIMMeta is the resource metadata
+type SCIMMeta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+}
+
+// SCIMGroup is the SCIM representation of a team
+type SCIMGroup struct {
+	Schemas     []string         `json:"schemas"`
+	ID          string           `json:"id,omitempty"`
+	ExternalID  string           `json:"externalId,omitempty"`
+	DisplayName string           `json:"displayName"`
+	Members     []SCIMMultiValue `json:"members"`
+	Meta        *SCIMMeta        `json:"meta,omitempty"`
+}
+
+// SCIMListResponse is a page of SCIM resources
+type SCIMListResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int64       `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    interface{} `json:"Resources"`
+}
+
+// SCIMPatchRequest is a PATCH request body
+type SCIMPatchRequest struct {
+	Schemas    []string             `json:"schemas"`
+	Operations []SCIMPatchOperation `json:"Operations"`
+}
+
+// SCIMPatchOperation is a single PATCH operation
+type SCIMPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// FutureBookingCanceller cancels future bookings of a departed user (BookingService)
+type FutureBookingCanceller interface {
+	CancelFutureBookingsOf(ctx context.Context, userID uint) (int, error)
+}
+
+// SCIMService provisions users and teams from an HR directory (SCIM 2.0)
+// Пользователь каталога определяется по email (userName); Telegram он может привязать позже через OIDC.
+// Отключение (active = false или DELETE) не удаляет запись: пользователь теряет доступ,
+// его будущие бронирования отменяются, а участие в чужих - снимается
+type SCIMService struct {
+	userRepo UserStore
+	teamRepo TeamStore
+	bookings FutureBookingCanceller
+	logger   *slog.Logger
+}
+
+// NewSCIMService creates a new SCIM service
+func NewSCIMService(userRepo UserStore, teamRepo TeamStore, bookings FutureBookingCanceller, logger *slog.Logger) *SCIMService {
+	return &SCIMService{
+		userRepo: userRepo,
+		teamRepo: teamRepo,
+		bookings: bookings,
+		logger:   logger,
+	}
+}
+
+// ListUsers returns a page of directory users; startIndex is 1-based
+func (s *SCIMService) ListUsers(ctx context.Context, filter string, startIndex, count int) (*SCIMListResponse, error) {
+	var directoryFilter repository.DirectoryFilter
+	if filter != "" {
+		attr, value, err := parseSCIMFilter(filter)
+		if err != nil {
+			return nil, err
+		}
+		switch attr {
+		case "username", "emails.value":
+			directoryFilter.Email = strings.ToLower(value)
+		case "externalid":
+			directoryFilter.ExternalID = value
+		default:
+			return nil, ErrSCIMInvalidFilter
+		}
+	}
+
+	limit, offset := scimPage(startIndex, count)
+	users, total, err := s.userRepo.ListDirectory(ctx, directoryFilter, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]SCIMUser, len(users))
+	for i := range users {
+		resources[i] = *toSCIMUser(&users[i])
+	}
+	return newSCIMListResponse(total, offset, resources, len(resources)), nil
+}
+
+// GetUser returns a directory user
+func (s *SCIMService) GetUser(ctx context.Context, id string) (*SCIMUser, error) {
+	user, err := s.directoryUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toSCIMUser(user), nil
+}
+
+// CreateUser provisions a user
+// Если пользователь с таким email уже есть (например, вошёл через OIDC), клиент каталога
+// должен найти его фильтром и обновить - создание вернёт ErrSCIMUniqueness
+func (s *SCIMService) CreateUser(ctx context.Context, in *SCIMUser) (*SCIMUser, error) {
+	user := &models.User{Role: models.RoleUser}
+	if err := s.applyUser(ctx, user, in); err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	s.logger.Info("SCIM user provisioned", "user_id", user.ID)
+
+	if in.Active != nil && !*in.Active {
+		if err := s.setActive(ctx, user, false); err != nil {
+			return nil, err
+		}
+	}
+	return toSCIMUser(user), nil
+}
+
+// ReplaceUser replaces the user's directory attributes (PUT)
+func (s *SCIMService) ReplaceUser(ctx context.Context, id string, in *SCIMUser) (*SCIMUser, error) {
+	user, err := s.directoryUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	user.FirstName, user.LastName, user.PhoneNumber, user.ExternalID = "", "", "", nil
+	if err := s.applyUser(ctx, user, in); err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if in.Active != nil {
+		if err := s.setActive(ctx, user, *in.Active); err != nil {
+			return nil, err
+		}
+	}
+	return toSCIMUser(user), nil
+}
+
+// PatchUser applies PATCH operations to a user
+// Поддерживаются active, userName, externalId, name.givenName, name.familyName и phoneNumbers;
+// остальные атрибуты игнорируются - клиенты каталога присылают их при каждой синхронизации
+func (s *SCIMService) PatchUser(ctx context.Context, id string, req *SCIMPatchRequest) (*SCIMUser, error) {
+	user, err := s.directoryUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var active *bool
+	for _, op := range req.Operations {
+		attrs, err := patchAttributes(op)
+		if err != nil {
+			return nil, err
+		}
+		remove := strings.EqualFold(op.Op, "remove")
+		for path, raw := range attrs {
+			if err := s.patchUserAttribute(ctx, user, path, raw, remove, &active); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	if active != nil {
+		if err := s.setActive(ctx, user, *active); err != nil {
+			return nil, err
+		}
+	}
+	return toSCIMUser(user), nil
+}
+
+// DeleteUser deactivates the user; the record and booking history are kept
+func (s *SCIMService) DeleteUser(ctx context.Context, id string) error {
+	user, err := s.directoryUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.setActive(ctx, user, false)
+}
+
+// ListGroups returns a page of teams; startIndex is 1-based
+func (s *SCIMService) ListGroups(ctx context.Context, filter string, startIndex, count int) (*SCIMListResponse, error) {
+	var teamFilter repository.TeamFilter
+	if filter != "" {
+		attr, value, err := parseSCIMFilter(filter)
+		if err != nil {
+			return nil, err
+		}
+		switch attr {
+		case "displayname":
+			teamFilter.Name = value
+		case "externalid":
+			teamFilter.ExternalID = value
+		default:
+			return nil, ErrSCIMInvalidFilter
+		}
+	}
+
+	limit, offset := scimPage(startIndex, count)
+	teams, total, err := s.teamRepo.List(ctx, teamFilter, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]SCIMGroup, len(teams))
+	for i := range teams {
+		resources[i] = *toSCIMGroup(&teams[i])
+	}
+	return newSCIMListResponse(total, offset, resources, len(resources)), nil
+}
+
+// GetGroup returns a team with its members
+func (s *SCIMService) GetGroup(ctx context.Context, id string) (*SCIMGroup, error) {
+	team, err := s.team(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toSCIMGroup(team), nil
+}
+
+// CreateGroup provisions a team with its members
+func (s *SCIMService) CreateGroup(ctx context.Context, in *SCIMGroup) (*SCIMGroup, error) {
+	team := &models.Team{}
+	if err := s.applyGroup(ctx, team, in); err != nil {
+		return nil, err
+	}
+	memberIDs, err := s.memberIDs(ctx, in.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.teamRepo.Create(ctx, team); err != nil {
+		return nil, err
+	}
+	if err := s.teamRepo.AddMembers(ctx, team.ID, memberIDs); err != nil {
+		return nil, err
+	}
+	s.logger.Info("SCIM team provisioned", "team_id", team.ID, "members", len(memberIDs))
+
+	return s.GetGroup(ctx, strconv.FormatUint(uint64(team.ID), 10))
+}
+
+// ReplaceGroup replaces the team name and members (PUT)
+func (s *SCIMService) ReplaceGroup(ctx context.Context, id string, in *SCIMGroup) (*SCIMGroup, error) {
+	team, err := s.team(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	team.ExternalID = nil
+	if err := s.applyGroup(ctx, team, in); err != nil {
+		return nil, err
+	}
+	memberIDs, err := s.memberIDs(ctx, in.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.teamRepo.Update(ctx, team); err != nil {
+		return nil, err
+	}
+	if err := s.teamRepo.ClearMembers(ctx, team.ID); err != nil {
+		return nil, err
+	}
+	if err := s.teamRepo.AddMembers(ctx, team.ID, memberIDs); err != nil {
+		return nil, err
+	}
+	return s.GetGroup(ctx, id)
+}
+
+// PatchGroup applies PATCH operations to a team
+// Поддерживаются displayName, externalId и members (add, remove, replace, members[value eq "id"])
+func (s *SCIMService) PatchGroup(ctx context.Context, id string, req *SCIMPatchRequest) (*SCIMGroup, error) {
+	team, err := s.team(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, op := range req.Operations {
+		opName := strings.ToLower(op.Op)
+		if opName == "remove" {
+			if userID, ok := memberPathValue(op.Path); ok {
+				if err := s.teamRepo.RemoveMembers(ctx, team.ID, []uint{userID}); err != nil {
+					return nil, err
+				}
+				continue
+			}
+		}
+
+		attrs, err := patchAttributes(op)
+		if err != nil {
+			return nil, err
+		}
+		for path, raw := range attrs {
+			switch path {
+			case "displayname":
+				name, err := scimString(raw)
+				if err != nil || strings.TrimSpace(name) == "" {
+					return nil, fmt.Errorf("%w: displayName", ErrSCIMInvalidValue)
+				}
+				team.Name = strings.TrimSpace(name)
+			case "externalid":
+				externalID, err := scimString(raw)
+				if err != nil {
+					return nil, fmt.Errorf("%w: externalId", ErrSCIMInvalidValue)
+				}
+				if opName == "remove" {
+					externalID = ""
+				}
+				if err := s.checkTeamExternalID(ctx, team.ID, externalID); err != nil {
+					return nil, err
+				}
+				team.ExternalID = optionalString(externalID)
+			case "members":
+				if err := s.patchMembers(ctx, team.ID, opName, raw); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	if err := s.teamRepo.Update(ctx, team); err != nil {
+		return nil, err
+	}
+	return s.GetGroup(ctx, id)
+}
+
+// DeleteGroup deletes a team; its members stay
+func (s *SCIMService) DeleteGroup(ctx context.Context, id string) error {
+	teamID, err := parseSCIMID(id)
+	if err != nil {
+		return err
+	}
+	if err := s.teamRepo.Delete(ctx, teamID); err != nil {
+		return err
+	}
+	s.logger.Info("SCIM team deleted", "team_id", teamID)
+	return nil
+}
+
+// setActive включает или отключает пользователя
+// При отключении будущие бронирования отменяются каждый раз: повтор запроса каталогом
+// доводит до конца отмену, прерванную ошибкой
+func (s *SCIMService) setActive(ctx context.Context, user *models.User, active bool) error {
+	if active {
+		if user.DeactivatedAt == nil {
+			return nil
+		}
+		user.DeactivatedAt = nil
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		s.logger.Info("SCIM user reactivated", "user_id", user.ID)
+		return nil
+	}
+
+	if user.DeactivatedAt == nil {
+		now := time.Now()
+		user.DeactivatedAt = &now
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		s.logger.Info("SCIM user deactivated", "user_id", user.ID)
+	}
+
+	cancelled, err := s.bookings.CancelFutureBookingsOf(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to cancel future bookings: %w", err)
+	}
+	if cancelled > 0 {
+		s.logger.Info("future bookings of deactivated user cancelled", "user_id", user.ID, "count", cancelled)
+	}
+	return nil
+}
+
+// applyUser переносит атрибуты SCIM в пользователя (без active)
+func (s *SCIMService) applyUser(ctx context.Context, user *models.User, in *SCIMUser) error {
+	email := scimEmail(in)
+	if email == "" {
+		return fmt.Errorf("%w: userName must be an email", ErrSCIMInvalidValue)
+	}
+	if err := s.setEmail(ctx, user, email); err != nil {
+		return err
+	}
+	if err := s.setExternalID(ctx, user, in.ExternalID); err != nil {
+		return err
+	}
+	if in.Name != nil {
+		user.FirstName, user.LastName = strings.TrimSpace(in.Name.GivenName), strings.TrimSpace(in.Name.FamilyName)
+	}
+	if phone := primaryValue(in.PhoneNumbers); phone != "" {
+		user.PhoneNumber = phone
+	}
+	return nil
+}
+
+// patchUserAttribute применяет одну операцию PATCH к атрибуту пользователя
+func (s *SCIMService) patchUserAttribute(ctx context.Context, user *models.User, path string, raw json.RawMessage, remove bool, active **bool) error {
+	switch {
+	case path == "active":
+		if remove {
+			return nil
+		}
+		value, err := scimBool(raw)
+		if err != nil {
+			return fmt.Errorf("%w: active", ErrSCIMInvalidValue)
+		}
+		*active = &value
+	case path == "username":
+		value, err := scimString(raw)
+		email := strings.ToLower(strings.TrimSpace(value))
+		if remove || err != nil || !strings.Contains(email, "@") {
+			return fmt.Errorf("%w: userName must be an email", ErrSCIMInvalidValue)
+		}
+		return s.setEmail(ctx, user, email)
+	case path == "externalid":
+		value, err := scimString(raw)
+		if remove {
+			value, err = "", nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: externalId", ErrSCIMInvalidValue)
+		}
+		return s.setExternalID(ctx, user, value)
+	case path == "name.givenname" || path == "name.familyname":
+		value, err := scimString(raw)
+		if remove {
+			value, err = "", nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrSCIMInvalidValue, path)
+		}
+		if path == "name.givenname" {
+			user.FirstName = strings.TrimSpace(value)
+		} else {
+			user.LastName = strings.TrimSpace(value)
+		}
+	case strings.HasPrefix(path, "phonenumbers"):
+		if remove {
+			user.PhoneNumber = ""
+			return nil
+		}
+		phone, err := scimString(raw)
+		if err != nil {
+			var values []SCIMMultiValue
+			if json.Unmarshal(raw, &values) != nil {
+				return fmt.Errorf("%w: phoneNumbers", ErrSCIMInvalidValue)
+			}
+			phone = primaryValue(values)
+		}
+		user.PhoneNumber = strings.TrimSpace(phone)
+	}
+	return nil
+}
+
+// setEmail меняет email, если он не занят другим пользователем
+func (s *SCIMService) setEmail(ctx context.Context, user *models.User, email string) error {
+	other, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if other != nil && other.ID != user.ID {
+		return ErrSCIMUniqueness
+	}
+	user.Email = &email
+	return nil
+}
+
+// setExternalID меняет externalId, если он не занят другим пользователем
+func (s *SCIMService) setExternalID(ctx context.Context, user *models.User, externalID string) error {
+	if externalID != "" {
+		others, _, err := s.userRepo.ListDirectory(ctx, repository.DirectoryFilter{ExternalID: externalID}, 1, 0)
+		if err != nil {
+			return err
+		}
+		if len(others) > 0 && others[0].ID != user.ID {
+			return ErrSCIMUniqueness
+		}
+	}
+	user.ExternalID = optionalString(externalID)
+	return nil
+}
+
+// applyGroup переносит атрибуты SCIM в команду (без участников)
+func (s *SCIMService) applyGroup(ctx context.Context, team *models.Team, in *SCIMGroup) error {
+	name := strings.TrimSpace(in.DisplayName)
+	if name == "" {
+		return fmt.Errorf("%w: displayName is required", ErrSCIMInvalidValue)
+	}
+	if err := s.checkTeamExternalID(ctx, team.ID, in.ExternalID); err != nil {
+		return err
+	}
+	team.Name = name
+	team.ExternalID = optionalString(in.ExternalID)
+	return nil
+}
+
+// checkTeamExternalID проверяет, что externalId не занят другой командой
+func (s *SCIMService) checkTeamExternalID(ctx context.Context, teamID uint, externalID string) error {
+	if externalID == "" {
+		return nil
+	}
+	others, _, err := s.teamRepo.List(ctx, repository.TeamFilter{ExternalID: externalID}, 1, 0)
+	if err != nil {
+		return err
+	}
+	if len(others) > 0 && others[0].ID != teamID {
+		return ErrSCIMUniqueness
+	}
+	return nil
+}
+
+// patchMembers применяет операцию PATCH к участникам команды
+func (s *SCIMService) patchMembers(ctx context.Context, teamID uint, op string, raw json.RawMessage) error {
+	var members []SCIMMultiValue
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &members); err != nil {
+			return fmt.Errorf("%w: members", ErrSCIMInvalidValue)
+		}
+	}
+
+	switch op {
+	case "remove":
+		if len(members) == 0 {
+			return s.teamRepo.ClearMembers(ctx, teamID)
+		}
+		ids := make([]uint, 0, len(members))
+		for _, m := range members {
+			id, err := strconv.ParseUint(m.Value, 10, 32)
+			if err != nil {
+				return fmt.Errorf("%w: member %q", ErrSCIMInvalidValue, m.Value)
+			}
+			ids = append(ids, uint(id))
+		}
+		return s.teamRepo.RemoveMembers(ctx, teamID, ids)
+	case "replace":
+		ids, err := s.memberIDs(ctx, members)
+		if err != nil {
+			return err
+		}
+		if err := s.teamRepo.ClearMembers(ctx, teamID); err != nil {
+			return err
+		}
+		return s.teamRepo.AddMembers(ctx, teamID, ids)
+	default:
+		ids, err := s.memberIDs(ctx, members)
+		if err != nil {
+			return err
+		}
+		return s.teamRepo.AddMembers(ctx, teamID, ids)
+	}
+}
+
+// memberIDs разбирает участников и проверяет, что все пользователи существуют
+func (s *SCIMService) memberIDs(ctx context.Context, members []SCIMMultiValue) ([]uint, error) {
+	if len(members) == 0 {
+		return nil, nil
+	}
+	ids := make([]uint, 0, len(members))
+	for _, m := range members {
+		id, err := strconv.ParseUint(m.Value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w: member %q", ErrSCIMInvalidValue, m.Value)
+		}
+		ids = append(ids, uint(id))
+	}
+
+	users, err := s.userRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[uint]bool, len(users))
+	for _, u := range users {
+		found[u.ID] = true
+	}
+	for _, id := range ids {
+		if !found[id] {
+			return nil, fmt.Errorf("%w: unknown member %d", ErrSCIMInvalidValue, id)
+		}
+	}
+	return ids, nil
+}
+
+// directoryUser возвращает пользователя каталога; пользователи без email каталогу не видны
+func (s *SCIMService) directoryUser(ctx context.Context, id string) (*models.User, error) {
+	userID, err := parseSCIMID(id)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Email == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return user, nil
+}
+
+func (s *SCIMService) team(ctx context.Context, id string) (*models.Team, error) {
+	teamID, err := parseSCIMID(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.teamRepo.GetByID(ctx, teamID)
+}
+
+// parseSCIMID разбирает id ресурса; некорректный id - ресурс не найден
+func parseSCIMID(id string) (uint, error) {
+	value, err := strconv.ParseUint(id, 10, 32)
+	if err != nil || value == 0 {
+		return 0, gorm.ErrRecordNotFound
+	}
+	return uint(value), nil
+}
+
+// parseSCIMFilter разбирает фильтр attribute eq "value"; имя атрибута приводится к нижнему регистру
+func parseSCIMFilter(filter string) (string, string, error) {
+	m := scimFilterPattern.FindStringSubmatch(filter)
+	if m == nil {
+		return "", "", ErrSCIMInvalidFilter
+	}
+	value, err := strconv.Unquote(`"` + m[2] + `"`)
+	if err != nil {
+		return "", "", ErrSCIMInvalidFilter
+	}
+	return strings.ToLower(m[1]), value, nil
+}
+
+// memberPathValue разбирает путь members[value eq "id"]
+func memberPathValue(path string) (uint, bool) {
+	if !strings.HasPrefix(strings.ToLower(path), "members[") || !strings.HasSuffix(path, "]") {
+		return 0, false
+	}
+	attr, value, err := parseSCIMFilter(path[len("members[") : len(path)-1])
+	if err != nil || attr != "value" {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// patchAttributes возвращает атрибуты операции PATCH по путям в нижнем регистре
+// Операция без path содержит объект атрибутов (так присылает Okta); вложенный name раскрывается в name.givenName
+func patchAttributes(op SCIMPatchOperation) (map[string]json.RawMessage, error) {
+	switch strings.ToLower(op.Op) {
+	case "add", "replace", "remove":
+	default:
+		return nil, fmt.Errorf("%w: unsupported op %q", ErrSCIMInvalidValue, op.Op)
+	}
+	if op.Path != "" {
+		return map[string]json.RawMessage{strings.ToLower(op.Path): op.Value}, nil
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(op.Value, &object); err != nil {
+		return nil, fmt.Errorf("%w: value must be an object without path", ErrSCIMInvalidValue)
+	}
+	attrs := make(map[string]json.RawMessage, len(object))
+	for key, raw := range object {
+		key = strings.ToLower(key)
+		if key != "name" {
+			attrs[key] = raw
+			continue
+		}
+		var name map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &name); err != nil {
+			return nil, fmt.Errorf("%w: name", ErrSCIMInvalidValue)
+		}
+		for sub, subRaw := range name {
+			attrs["name."+strings.ToLower(sub)] = subRaw
+		}
+	}
+	return attrs, nil
+}
+
+// scimString разбирает строковое значение
+func scimString(raw json.RawMessage) (string, error) {
+	var value string
+	err := json.Unmarshal(raw, &value)
+	return value, err
+}
+
+// scimBool разбирает булево значение; Entra ID присылает active строкой "True"/"False"
+func scimBool(raw json.RawMessage) (bool, error) {
+	var value bool
+	if err := json.Unmarshal(raw, &value); err == nil {
+		return value, nil
+	}
+	s, err := scimString(raw)
+	if err != nil {
+		return false, err
+	}
+	return strconv.ParseBool(strings.ToLower(s))
+}
+
+// scimEmail возвращает email пользователя: userName, а если это не email - основной адрес из emails
+func scimEmail(in *SCIMUser) string {
+	for _, candidate := range []string{in.UserName, primaryValue(in.Emails)} {
+		email := strings.ToLower(strings.TrimSpace(candidate))
+		if strings.Contains(email, "@") {
+			return email
+		}
+	}
+	return ""
+}
+
+// primaryValue возвращает основное значение многозначного атрибута, иначе первое
+func primaryValue(values []SCIMMultiValue) string {
+	for _, v := range values {
+		if v.Primary {
+			return v.Value
+		}
+	}
+	if len(values) > 0 {
+		return values[0].Value
+	}
+	return ""
+}
+
+func optionalString(value string) *string {
+	if value = strings.TrimSpace(value); value == "" {
+		return nil
+	}
+	return &value
+}
+
+// scimPage переводит startIndex/count SCIM в limit/offset
+func scimPage(startIndex, count int) (int, int) {
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	return pageBounds(count, startIndex-1, DefaultSCIMPageSize, MaxSCIMPageSize)
+}
+
+func newSCIMListResponse(total int64, offset int, resources interface{}, items int) *SCIMListResponse {
+	return &SCIMListResponse{
+		Schemas:      []string{SCIMSchemaListResponse},
+		TotalResults: total,
+		StartIndex:   offset + 1,
+		ItemsPerPage: items,
+		Resources:    resources,
+	}
+}
+
+func toSCIMUser(u *models.User) *SCIMUser {
+	active := u.DeactivatedAt == nil
+	out := &SCIMUser{
+		Schemas:     []string{SCIMSchemaUser},
+		ID:          strconv.FormatUint(uint64(u.ID), 10),
+		Name:        &SCIMName{GivenName: u.FirstName, FamilyName: u.LastName},
+		DisplayName: strings.TrimSpace(u.FirstName + " " + u.LastName),
+		Active:      &active,
+		Meta:        &SCIMMeta{ResourceType: "User", Created: u.CreatedAt, LastModified: u.UpdatedAt},
+	}
+	if u.Email != nil {
+		out.UserName = *u.Email
+		out.Emails = []SCIMMultiValue{{Value: *u.Email, Type: "work", Primary: true}}
+	}
+	if u.ExternalID != nil {
+		out.ExternalID = *u.ExternalID
+	}
+	if u.PhoneNumber != "" {
+		out.PhoneNumbers = []SCIMMultiValue{{Value: u.PhoneNumber, Type: "work", Primary: true}}
+	}
+	return out
+}
+
+func toSCIMGroup(t *models.Team) *SCIMGroup {
+	out := &SCIMGroup{
+		Schemas:     []string{SCIMSchemaGroup},
+		ID:          strconv.FormatUint(uint64(t.ID), 10),
+		DisplayName: t.Name,
+		Members:     make([]SCIMMultiValue, len(t.Members)),
+		Meta:        &SCIMMeta{ResourceType: "Group", Created: t.CreatedAt, LastModified: t.UpdatedAt},
+	}
+	if t.ExternalID != nil {
+		out.ExternalID = *t.ExternalID
+	}
+	for i, m := range t.Members {
+		display := strings.TrimSpace(m.FirstName + " " + m.LastName)
+		if display == "" && m.Email != nil {
+			display = *m.Email
+		}
+		out.Members[i] = SCIMMultiValue{Value: strconv.FormatUint(uint64(m.ID), 10), Display: display}
+	}
+	return out
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/internal/repository"
+	"gorm.io/gorm"
+)
+
+// fakeDirectoryUserStore добавляет выборку каталога к fakeOIDCUserStore
+type fakeDirectoryUserStore struct {
+	*fakeOIDCUserStore
+}
+
+func (f *fakeDirectoryUserStore) ListDirectory(ctx context.Context, filter repository.DirectoryFilter, limit, offset int) ([]models.User, int64, error) {
+	var users []models.User
+	for id := uint(1); id <= uint(len(f.users)+100); id++ {
+		u, ok := f.users[id]
+		if !ok || u.Email == nil {
+			continue
+		}
+		if filter.Email != "" && *u.Email != filter.Email {
+			continue
+		}
+		if filter.ExternalID != "" && (u.ExternalID == nil || *u.ExternalID != filter.ExternalID) {
+			continue
+		}
+		users = append(users, *u)
+	}
+	return users, int64(len(users)), nil
+}
+
+type fakeTeamStore struct {
+	TeamStore
+	teams   map[uint]*models.Team
+	members map[uint][]uint
+}
+
+func (f *fakeTeamStore) Create(ctx context.Context, team *models.Team) error {
+	team.ID = uint(len(f.teams) + 1)
+	f.teams[team.ID] = team
+	return nil
+}
+
+func (f *fakeTeamStore) GetByID(ctx context.Context, id uint) (*models.Team, error) {
+	team, ok := f.teams[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	out := *team
+	out.Members = nil
+	for _, userID := range f.members[id] {
+		out.Members = append(out.Members, models.User{ID: userID})
+	}
+	return &out, nil
+}
+
+func (f *fakeTeamStore) List(ctx context.Context, filter repository.TeamFilter, limit, offset int) ([]models.Team, int64, error) {
+	return nil, 0, nil
+}
+
+func (f *fakeTeamStore) Update(ctx context.Context, team *models.Team) error {
+	f.teams[team.ID] = team
+	return nil
+}
+
+func (f *fakeTeamStore) AddMembers(ctx context.Context, teamID uint, userIDs []uint) error {
+	f.members[teamID] = append(f.members[teamID], userIDs...)
+	return nil
+}
+
+func (f *fakeTeamStore) RemoveMembers(ctx context.Context, teamID uint, userIDs []uint) error {
+	var kept []uint
+	for _, id := range f.members[teamID] {
+		removed := false
+		for _, r := range userIDs {
+			removed = removed || id == r
+		}
+		if !removed {
+			kept = append(kept, id)
+		}
+	}
+	f.members[teamID] = kept
+	return nil
+}
+
+func (f *fakeTeamStore) ClearMembers(ctx context.Context, teamID uint) error {
+	f.members[teamID] = nil
+	return nil
+}
+
+type fakeCanceller struct {
+	cancelledFor []uint
+}
+
+func (f *fakeCanceller) CancelFutureBookingsOf(ctx context.Context, userID uint) (int, error) {
+	f.cancelledFor = append(f.cancelledFor, userID)
+	return 2, nil
+}
+
+func newTestSCIMService() (*SCIMService, *fakeDirectoryUserStore, *fakeTeamStore, *fakeCanceller) {
+	users := &fakeDirectoryUserStore{&fakeOIDCUserStore{&fakeUserStore{users: map[uint]*models.User{}}}}
+	teams := &fakeTeamStore{teams: map[uint]*models.Team{}, members: map[uint][]uint{}}
+	canceller := &fakeCanceller{}
+	return NewSCIMService(users, teams, canceller, slog.Default()), users, teams, canceller
+}
+
+func patchOp(op, path, value string) SCIMPatchRequest {
+	req := SCIMPatchRequest{Schemas: []string{SCIMSchemaPatchOp}}
+	req.Operations = append(req.Operations, SCIMPatchOperation{Op: op, Path: path, Value: json.RawMessage(value)})
+	return req
+}
+
+func TestSCIMUserLifecycle(t *testing.T) {
+	svc, users, _, canceller := newTestSCIMService()
+	ctx := context.Background()
+
+	created, err := svc.CreateUser(ctx, &SCIMUser{
+		UserName:   "Anna@Example.com",
+		ExternalID: "hr-1",
+		Name:       &SCIMName{GivenName: "Anna", FamilyName: "Ivanova"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if created.UserName != "anna@example.com" || !*created.Active || created.ExternalID != "hr-1" {
+		t.Errorf("Unexpected user: %+v", created)
+	}
+	if _, err := svc.CreateUser(ctx, &SCIMUser{UserName: "anna@example.com"}); !errors.Is(err, ErrSCIMUniqueness) {
+		t.Errorf("Expected ErrSCIMUniqueness, got: %v", err)
+	}
+	if _, err := svc.CreateUser(ctx, &SCIMUser{UserName: "not-an-email"}); !errors.Is(err, ErrSCIMInvalidValue) {
+		t.Errorf("Expected ErrSCIMInvalidValue, got: %v", err)
+	}
+
+	list, err := svc.ListUsers(ctx, `userName eq "ANNA@example.com"`, 1, 10)
+	if err != nil || list.TotalResults != 1 {
+		t.Errorf("Expected the user to be found by filter, got: %+v, %v", list, err)
+	}
+	if _, err := svc.ListUsers(ctx, `name.givenName co "An"`, 1, 10); !errors.Is(err, ErrSCIMInvalidFilter) {
+		t.Errorf("Expected ErrSCIMInvalidFilter, got: %v", err)
+	}
+
+	// Entra ID присылает active строкой
+	req := patchOp("Replace", "active", `"False"`)
+	patched, err := svc.PatchUser(ctx, created.ID, &req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	user := users.users[100]
+	if *patched.Active || !user.IsDeactivated() {
+		t.Errorf("Expected user to be deactivated, got: %+v", patched)
+	}
+	if len(canceller.cancelledFor) != 1 || canceller.cancelledFor[0] != user.ID {
+		t.Errorf("Expected future bookings of user %d to be cancelled, got: %v", user.ID, canceller.cancelledFor)
+	}
+
+	// Okta присылает объект атрибутов без path
+	req = patchOp("replace", "", `{"active":true,"name":{"familyName":"Petrova"}}`)
+	patched, err = svc.PatchUser(ctx, created.ID, &req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !*patched.Active || user.IsDeactivated() || user.LastName != "Petrova" || user.FirstName != "Anna" {
+		t.Errorf("Expected reactivated user with new family name, got: %+v", user)
+	}
+
+	if err := svc.DeleteUser(ctx, created.ID); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if !user.IsDeactivated() || len(canceller.cancelledFor) != 2 {
+		t.Errorf("Expected DELETE to deactivate the user, got: %+v", user)
+	}
+}
+
+func TestSCIMUser_TelegramOnlyUsersAreHidden(t *testing.T) {
+	svc, users, _, _ := newTestSCIMService()
+	users.users[1] = &models.User{ID: 1, TelegramID: 111}
+
+	if _, err := svc.GetUser(context.Background(), "1"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected ErrRecordNotFound, got: %v", err)
+	}
+	if _, err := svc.GetUser(context.Background(), "abc"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected ErrRecordNotFound for a malformed id, got: %v", err)
+	}
+}
+
+func TestSCIMGroupMembers(t *testing.T) {
+	svc, users, teams, _ := newTestSCIMService()
+	ctx := context.Background()
+	for id := uint(1); id <= 3; id++ {
+		users.users[id] = &models.User{ID: id}
+	}
+
+	group, err := svc.CreateGroup(ctx, &SCIMGroup{DisplayName: "Design", Members: []SCIMMultiValue{{Value: "1"}, {Value: "2"}}})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(group.Members) != 2 {
+		t.Errorf("Expected 2 members, got: %+v", group.Members)
+	}
+	if _, err := svc.CreateGroup(ctx, &SCIMGroup{DisplayName: "Sales", Members: []SCIMMultiValue{{Value: "42"}}}); !errors.Is(err, ErrSCIMInvalidValue) {
+		t.Errorf("Expected ErrSCIMInvalidValue for an unknown member, got: %v", err)
+	}
+
+	req := SCIMPatchRequest{Operations: []SCIMPatchOperation{
+		{Op: "add", Path: "members", Value: json.RawMessage(`[{"value":"3"}]`)},
+		{Op: "remove", Path: `members[value eq "1"]`},
+		{Op: "replace", Path: "displayName", Value: json.RawMessage(`"Product Design"`)},
+	}}
+	group, err = svc.PatchGroup(ctx, group.ID, &req)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if group.DisplayName != "Product Design" || len(group.Members) != 2 || group.Members[0].Value != "2" || group.Members[1].Value != "3" {
+		t.Errorf("Unexpected group after patch: %+v", group)
+	}
+
+	req = patchOp("remove", "members", "")
+	if group, err = svc.PatchGroup(ctx, group.ID, &req); err != nil || len(group.Members) != 0 {
+		t.Errorf("Expected all members removed, got: %+v, %v", group, err)
+	}
+	if len(teams.teams) != 1 {
+		t.Errorf("Expected one team, got: %d", len(teams.teams))
+	}
+}
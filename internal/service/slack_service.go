@@ -0,0 +1,320 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/pkg/apperror"
+)
+
+const (
+	// slackAPIURL - базовый адрес Slack Web API для доставки через bot token
+	slackAPIURL = "https://slack.com/api"
+	// slackWebhookHost - incoming webhooks принимаются только на этот хост (иначе это был бы SSRF)
+	slackWebhookHost = "hooks.slack.com"
+	slackTimeout     = 10 * time.Second
+)
+
+var (
+	ErrInvalidSlackTarget = apperror.BadRequest("invalid_slack_target", "invalid Slack target")
+	ErrInvalidEvent       = apperror.BadRequest("invalid_event", "invalid event")
+)
+
+// SlackService delivers booking events to Slack channels configured by admins
+type SlackService struct {
+	targetRepo SlackTargetStore
+	roomRepo   RoomReader
+	httpClient *http.Client
+	apiURL     string
+	logger     *slog.Logger
+}
+
+// NewSlackService creates a new Slack service
+func NewSlackService(targetRepo SlackTargetStore, roomRepo RoomReader, logger *slog.Logger) *SlackService {
+	return &SlackService{
+		targetRepo: targetRepo,
+		roomRepo:   roomRepo,
+		httpClient: &http.Client{Timeout: slackTimeout},
+		apiURL:     slackAPIURL,
+		logger:     logger,
+	}
+}
+
+// CreateSlackTargetRequest represents a request to add a Slack target
+// Нужен либо webhook_url, либо bot_token вместе с channel
+type CreateSlackTargetRequest struct {
+	Name       string   `json:"name" binding:"required"`
+	RoomID     *uint    `json:"room_id"` // Пусто - все комнаты
+	WebhookURL string   `json:"webhook_url"`
+	BotToken   string   `json:"bot_token"`
+	Channel    string   `json:"channel"`
+	Events     []string `json:"events"` // Пусто - все события
+}
+
+// CreateTarget adds a Slack target (admin only)
+func (s *SlackService) CreateTarget(ctx context.Context, createdByID uint, req CreateSlackTargetRequest) (*models.SlackTarget, error) {
+	events, err := normalizeEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	target := &models.SlackTarget{
+		Name:        strings.TrimSpace(req.Name),
+		RoomID:      req.RoomID,
+		Events:      strings.Join(events, ","),
+		CreatedByID: createdByID,
+	}
+
+	webhookURL := strings.TrimSpace(req.WebhookURL)
+	botToken := strings.TrimSpace(req.BotToken)
+	switch {
+	case webhookURL != "" && botToken != "":
+		return nil, fmt.Errorf("%w: set either webhook_url or bot_token, not both", ErrInvalidSlackTarget)
+	case webhookURL != "":
+		if err := validateSlackWebhookURL(webhookURL); err != nil {
+			return nil, err
+		}
+		target.Kind = models.SlackTargetWebhook
+		target.WebhookURL = webhookURL
+	case botToken != "":
+		if !strings.HasPrefix(botToken, "xoxb-") {
+			return nil, fmt.Errorf("%w: bot_token must be a bot token (xoxb-...)", ErrInvalidSlackTarget)
+		}
+		if strings.TrimSpace(req.Channel) == "" {
+			return nil, fmt.Errorf("%w: channel is required with bot_token", ErrInvalidSlackTarget)
+		}
+		target.Kind = models.SlackTargetBot
+		target.BotToken = botToken
+		target.Channel = strings.TrimSpace(req.Channel)
+	default:
+		return nil, fmt.Errorf("%w: webhook_url or bot_token is required", ErrInvalidSlackTarget)
+	}
+
+	if req.RoomID != nil {
+		if _, err := s.roomRepo.GetByID(ctx, *req.RoomID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.targetRepo.Create(ctx, target); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Slack target created", "slack_target_id", target.ID, "kind", target.Kind, "events", target.Events, "created_by", createdByID)
+	return target, nil
+}
+
+// ListTargets returns all Slack targets (admin only)
+func (s *SlackService) ListTargets(ctx context.Context) ([]models.SlackTarget, error) {
+	return s.targetRepo.GetAll(ctx)
+}
+
+// DeleteTarget removes a Slack target (admin only)
+func (s *SlackService) DeleteTarget(ctx context.Context, id uint) error {
+	if err := s.targetRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.logger.Info("Slack target deleted", "slack_target_id", id)
+	return nil
+}
+
+// HandleBookingEvent posts the event to every Slack target of the booking's room
+// Ошибка одного канала не мешает доставке в остальные
+func (s *SlackService) HandleBookingEvent(ctx context.Context, event BookingEvent) error {
+	targets, err := s.targetRepo.GetForRoom(ctx, event.Booking.RoomID)
+	if err != nil {
+		return err
+	}
+
+	text := formatSlackMessage(event)
+	var errs []error
+	for i := range targets {
+		if !targets[i].WantsEvent(string(event.Type)) {
+			continue
+		}
+		if err := s.send(ctx, &targets[i], text); err != nil {
+			errs = append(errs, fmt.Errorf("slack target %d: %w", targets[i].ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// HandleRoomBroadcast posts an admin announcement to every Slack target of the room
+// Объявление - не событие бронирования: фильтр событий цели к нему не применяется
+func (s *SlackService) HandleRoomBroadcast(ctx context.Context, broadcast RoomBroadcast) error {
+	targets, err := s.targetRepo.GetForRoom(ctx, broadcast.Room.ID)
+	if err != nil {
+		return err
+	}
+
+	text := fmt.Sprintf(":loudspeaker: Announcement for *%s*\n%s", slackEscape(broadcast.Room.Name), slackEscape(broadcast.Message))
+	var errs []error
+	for i := range targets {
+		if err := s.send(ctx, &targets[i], text); err != nil {
+			errs = append(errs, fmt.Errorf("slack target %d: %w", targets[i].ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// send отправляет одно сообщение в канал
+func (s *SlackService) send(ctx context.Context, target *models.SlackTarget, text string) error {
+	if target.Kind == models.SlackTargetWebhook {
+		return s.post(ctx, target.WebhookURL, "", map[string]string{"text": text}, nil)
+	}
+
+	// Web API отвечает 200 и на ошибки - результат в поле ok
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	payload := map[string]string{"channel": target.Channel, "text": text}
+	if err := s.post(ctx, s.apiURL+"/chat.postMessage", target.BotToken, payload, &result); err != nil {
+		return err
+	}
+	if !result.OK {
+		return fmt.Errorf("chat.postMessage failed: %s", result.Error)
+	}
+	return nil
+}
+
+func (s *SlackService) post(ctx context.Context, endpoint, token string, payload interface{}, result interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return fmt.Errorf("slack returned status %d", resp.StatusCode)
+	}
+	if result == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// formatSlackMessage формирует текст уведомления в разметке Slack mrkdwn
+// Время передаётся как <!date^...>: Slack показывает его в часовом поясе читателя
+func formatSlackMessage(event BookingEvent) string {
+	b := event.Booking
+
+	var prefix string
+	switch event.Type {
+	case EventBookingCreated:
+		prefix = ":calendar: New booking"
+	case EventBookingCancelled:
+		prefix = ":x: Booking cancelled"
+	case EventBookingReminder:
+		prefix = ":alarm_clock: Starting soon"
+	case EventBookingReleased:
+		prefix = ":door: Room released early"
+	case EventBookingUpdated:
+		prefix = ":pencil2: Booking updated"
+	default:
+		prefix = string(event.Type)
+	}
+
+	when := fmt.Sprintf("<!date^%d^{date_short_pretty} {time}|%s> – <!date^%d^{time}|%s>",
+		b.StartTime.Unix(), b.StartTime.UTC().Format("2006-01-02 15:04 UTC"),
+		b.EndTime.Unix(), b.EndTime.UTC().Format("15:04 UTC"))
+
+	text := fmt.Sprintf("%s in *%s*: *%s*\n%s", prefix, slackEscape(b.Room.Name), slackEscape(b.Title), when)
+	if creator := displayUserName(&b.Creator); creator != "" {
+		text += " · " + slackEscape(creator)
+	}
+	for _, change := range event.Changes {
+		text += fmt.Sprintf("\n• %s: %s → %s", change.Field, slackValue(change.Old), slackValue(change.New))
+	}
+	return text
+}
+
+// slackValue форматирует значение изменённого поля; время - в часовом поясе читателя
+func slackValue(v any) string {
+	switch v := v.(type) {
+	case time.Time:
+		return fmt.Sprintf("<!date^%d^{date_short_pretty} {time}|%s>", v.Unix(), v.UTC().Format("2006-01-02 15:04 UTC"))
+	case string:
+		if v == "" {
+			return "—"
+		}
+		return slackEscape(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// slackEscape экранирует управляющие символы разметки Slack
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// displayUserName возвращает имя и фамилию пользователя, а если их нет - @username
+func displayUserName(u *models.User) string {
+	if name := strings.TrimSpace(u.FirstName + " " + u.LastName); name != "" {
+		return name
+	}
+	if u.Username != "" {
+		return "@" + u.Username
+	}
+	return ""
+}
+
+// validateSlackWebhookURL допускает только incoming webhooks Slack по HTTPS
+func validateSlackWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || u.Host != slackWebhookHost {
+		return fmt.Errorf("%w: webhook_url must be an https://%s/ URL", ErrInvalidSlackTarget, slackWebhookHost)
+	}
+	return nil
+}
+
+// normalizeEvents проверяет список событий; пустой список означает все события
+func normalizeEvents(events []string) ([]string, error) {
+	if len(events) == 0 {
+		events = make([]string, len(ValidBookingEvents))
+		for i, e := range ValidBookingEvents {
+			events[i] = string(e)
+		}
+		return events, nil
+	}
+
+	normalized := make([]string, 0, len(events))
+	for _, e := range events {
+		e = strings.TrimSpace(e)
+		if !slices.Contains(ValidBookingEvents, BookingEventType(e)) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidEvent, e)
+		}
+		if !slices.Contains(normalized, e) {
+			normalized = append(normalized, e)
+		}
+	}
+	return normalized, nil
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/models"
+)
+
+type fakeSlackTargetStore struct {
+	SlackTargetStore
+	targets []models.SlackTarget
+}
+
+func (f *fakeSlackTargetStore) Create(ctx context.Context, target *models.SlackTarget) error {
+	target.ID = uint(len(f.targets) + 1)
+	f.targets = append(f.targets, *target)
+	return nil
+}
+
+func (f *fakeSlackTargetStore) GetForRoom(ctx context.Context, roomID uint) ([]models.SlackTarget, error) {
+	var targets []models.SlackTarget
+	for _, t := range f.targets {
+		if t.RoomID == nil || *t.RoomID == roomID {
+			targets = append(targets, t)
+		}
+	}
+	return targets, nil
+}
+
+func newTestSlackService(targets ...models.SlackTarget) *SlackService {
+	rooms := &fakeRoomStore{rooms: map[uint]*models.Room{1: {ID: 1, Name: "Room 1", IsActive: true}}}
+	return NewSlackService(&fakeSlackTargetStore{targets: targets}, rooms, slog.Default())
+}
+
+func TestCreateSlackTargetValidation(t *testing.T) {
+	svc := newTestSlackService()
+	ctx := context.Background()
+
+	invalid := []CreateSlackTargetRequest{
+		{Name: "none"},
+		{Name: "both", WebhookURL: "https://hooks.slack.com/services/T/B/x", BotToken: "xoxb-1", Channel: "#general"},
+		{Name: "foreign host", WebhookURL: "https://example.com/services/T/B/x"},
+		{Name: "plain http", WebhookURL: "http://hooks.slack.com/services/T/B/x"},
+		{Name: "user token", BotToken: "xoxp-1", Channel: "#general"},
+		{Name: "no channel", BotToken: "xoxb-1"},
+		{Name: "bad event", WebhookURL: "https://hooks.slack.com/services/T/B/x", Events: []string{"booking.deleted"}},
+	}
+	for _, req := range invalid {
+		if _, err := svc.CreateTarget(ctx, 1, req); !errors.Is(err, ErrInvalidSlackTarget) && !errors.Is(err, ErrInvalidEvent) {
+			t.Errorf("%s: expected a validation error, got: %v", req.Name, err)
+		}
+	}
+
+	target, err := svc.CreateTarget(ctx, 1, CreateSlackTargetRequest{Name: "office", BotToken: "xoxb-1", Channel: "#office"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if target.Kind != models.SlackTargetBot || target.Events != "booking.created,booking.cancelled,booking.reminder,booking.released,booking.updated" {
+		t.Errorf("Expected bot target with all events, got: %s %q", target.Kind, target.Events)
+	}
+}
+
+func TestSlackHandleBookingEvent(t *testing.T) {
+	var mu sync.Mutex
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		mu.Lock()
+		received = append(received, r.URL.Path+" "+payload["channel"])
+		mu.Unlock()
+
+		if r.URL.Path == "/chat.postMessage" {
+			if r.Header.Get("Authorization") != "Bearer xoxb-1" {
+				t.Errorf("Expected bot token, got: %q", r.Header.Get("Authorization"))
+			}
+			_, _ = w.Write([]byte(`{"ok": true}`))
+		}
+	}))
+	defer server.Close()
+
+	otherRoom := uint(2)
+	svc := newTestSlackService(
+		models.SlackTarget{ID: 1, Kind: models.SlackTargetWebhook, WebhookURL: server.URL + "/hook", Events: "booking.created"},
+		models.SlackTarget{ID: 2, Kind: models.SlackTargetBot, BotToken: "xoxb-1", Channel: "#office", Events: "booking.created,booking.cancelled"},
+		models.SlackTarget{ID: 3, Kind: models.SlackTargetWebhook, WebhookURL: server.URL + "/other", RoomID: &otherRoom, Events: "booking.created"},
+	)
+	svc.apiURL = server.URL
+
+	booking := &models.Booking{ID: 5, RoomID: 1, Title: "Standup", StartTime: time.Now(), EndTime: time.Now().Add(time.Hour)}
+	if err := svc.HandleBookingEvent(context.Background(), BookingEvent{Type: EventBookingCancelled, Booking: booking}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(received) != 1 || received[0] != "/chat.postMessage #office" {
+		t.Errorf("Expected only the bot target to be notified, got: %v", received)
+	}
+
+	received = nil
+	if err := svc.HandleBookingEvent(context.Background(), BookingEvent{Type: EventBookingCreated, Booking: booking}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(received) != 2 {
+		t.Errorf("Expected both targets of room 1, got: %v", received)
+	}
+}
+
+func TestFormatSlackMessageEscapes(t *testing.T) {
+	booking := &models.Booking{Title: "<!channel> & co", Room: models.Room{Name: "A"}}
+	text := formatSlackMessage(BookingEvent{Type: EventBookingReminder, Booking: booking})
+	if strings.Contains(text, "<!channel>") || !strings.Contains(text, "&lt;!channel&gt; &amp; co") {
+		t.Errorf("Expected title to be escaped, got: %s", text)
+	}
+}
@@ -0,0 +1,56 @@
+package service
+
+// Белые списки сортировки списков (?sort=): имя поля в API -> колонка в БД
+// Обработчики передают их в response.ParseSort; сортировать по остальным полям нельзя
+var (
+	BookingSortColumns = map[string]string{
+		"id":                     "id",
+		"start_time":             "start_time",
+		"end_time":               "end_time",
+		"title":                  "title",
+		"status":                 "status",
+		"room_id":                "room_id",
+		"estimated_participants": "estimated_participants",
+		"created_at":             "created_at",
+	}
+
+	RoomSortColumns = map[string]string{
+		"id":         "id",
+		"name":       "name",
+		"capacity":   "capacity",
+		"created_at": "created_at",
+	}
+
+	PhonebookSortColumns = map[string]string{
+		"first_name": "first_name",
+		"last_name":  "last_name",
+		"username":   "username",
+	}
+
+	UserSortColumns = map[string]string{
+		"id":          "id",
+		"telegram_id": "telegram_id",
+		"username":    "username",
+		"first_name":  "first_name",
+		"last_name":   "last_name",
+		"role":        "role",
+		"created_at":  "created_at",
+	}
+
+	AuditSortColumns = map[string]string{
+		"id":         "id",
+		"created_at": "created_at",
+		"actor_id":   "actor_id",
+		"method":     "method",
+		"route":      "route",
+		"status":     "status",
+	}
+
+	APIKeySortColumns = map[string]string{
+		"id":           "id",
+		"name":         "name",
+		"created_at":   "created_at",
+		"expires_at":   "expires_at",
+		"last_used_at": "last_used_at",
+	}
+)
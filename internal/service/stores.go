@@ -0,0 +1,323 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/internal/repository"
+	"gorm.io/datatypes"
+)
+
+// Интерфейсы хранилищ, от которых зависят сервисы
+// Реализации - репозитории из internal/repository; в unit-тестах подставляются fakes в памяти
+
+// TxRunner runs a function in a database transaction
+type TxRunner interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// BookingStore persists bookings and their participants
+type BookingStore interface {
+	Create(ctx context.Context, booking *models.Booking) error
+	GetByID(ctx context.Context, id uint) (*models.Booking, error)
+	GetByUserID(ctx context.Context, userID uint, limit, offset int, order string) ([]models.Booking, int64, error)
+	GetByUserInRange(ctx context.Context, userID uint, start, end time.Time) ([]models.Booking, error)
+	GetByRoomAndTimeRange(ctx context.Context, roomID uint, start, end time.Time) ([]models.Booking, error)
+	CheckConflict(ctx context.Context, roomID uint, start, end time.Time, excludeBookingID *uint) (bool, error)
+	GetConflictingBookings(ctx context.Context, roomID uint, start, end time.Time, excludeBookingID *uint) ([]models.Booking, error)
+	GetUpcoming(ctx context.Context, limit int) ([]models.Booking, error)
+	GetForCalendar(ctx context.Context, start, end time.Time) ([]models.Booking, error)
+	GetCalendarSummaries(ctx context.Context, start, end time.Time) ([]models.BookingSummary, error)
+	ListByRoomKind(ctx context.Context, kind models.RoomKind, creatorID uint, start, end time.Time) ([]models.Booking, error)
+	ExportBatches(ctx context.Context, start, end time.Time, roomID uint, batchSize int, fn func([]models.Booking) error) error
+	GetDueForReminder(ctx context.Context, from, to time.Time) ([]models.Booking, error)
+	MarkReminderSent(ctx context.Context, id uint, at time.Time) (bool, error)
+	GetFutureByCreator(ctx context.Context, userID uint, now time.Time) ([]models.Booking, error)
+	CountUpcomingByCreator(ctx context.Context, userID uint, now time.Time) (int64, error)
+	GetCurrentByRoom(ctx context.Context, roomID uint, now time.Time) (*models.Booking, error)
+	Release(ctx context.Context, id uint, now time.Time) (bool, error)
+	GetEndedConfirmed(ctx context.Context, now time.Time, limit int) ([]models.Booking, error)
+	Complete(ctx context.Context, id uint, now time.Time) (bool, error)
+	RemoveFromFutureBookings(ctx context.Context, userID uint, now time.Time) ([]uint, error)
+	Update(ctx context.Context, booking *models.Booking) error
+	Cancel(ctx context.Context, id uint) error
+	AddParticipant(ctx context.Context, bookingID, userID uint) (bool, error)
+	RemoveParticipant(ctx context.Context, bookingID, userID uint) (bool, error)
+	CountParticipants(ctx context.Context, bookingID uint) (int64, error)
+	GetWaitlist(ctx context.Context, bookingID uint) ([]models.BookingWaitlistEntry, error)
+	AddToWaitlist(ctx context.Context, bookingID, userID uint) (bool, error)
+	RemoveFromWaitlist(ctx context.Context, bookingID, userID uint) (bool, error)
+}
+
+// BookingHistoryStore persists the append-only stream of booking lifecycle events
+type BookingHistoryStore interface {
+	Append(ctx context.Context, event *models.BookingHistoryEvent) error
+	ListByBooking(ctx context.Context, bookingID uint) ([]models.BookingHistoryEvent, error)
+	ListAfter(ctx context.Context, afterID uint, limit int) ([]models.BookingHistoryEvent, error)
+}
+
+// RoomStore persists rooms
+type RoomStore interface {
+	Create(ctx context.Context, room *models.Room) error
+	GetByID(ctx context.Context, id uint) (*models.Room, error)
+	LockByID(ctx context.Context, id uint) (*models.Room, error)
+	GetByName(ctx context.Context, name string) (*models.Room, error)
+	GetAll(ctx context.Context, order string) ([]models.Room, error)
+	GetAllWithEquipment(ctx context.Context, order string) ([]models.Room, error)
+	GetParkingSpots(ctx context.Context) ([]models.Room, error)
+	Update(ctx context.Context, room *models.Room) error
+	ClaimBroadcast(ctx context.Context, roomID uint, now, notBefore time.Time) (bool, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+// RoomReader is the read-only subset of RoomStore
+type RoomReader interface {
+	GetByID(ctx context.Context, id uint) (*models.Room, error)
+}
+
+// EquipmentStore reads room equipment
+type EquipmentStore interface {
+	GetByID(ctx context.Context, id uint) (*models.Equipment, error)
+	GetByRoomID(ctx context.Context, roomID uint) ([]models.Equipment, error)
+}
+
+// UserStore persists users
+type UserStore interface {
+	GetByID(ctx context.Context, id uint) (*models.User, error)
+	GetByIDs(ctx context.Context, ids []uint) ([]models.User, error)
+	GetByTelegramID(ctx context.Context, telegramID int64) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	GetByOIDCSubject(ctx context.Context, subject string) (*models.User, error)
+	Create(ctx context.Context, user *models.User) error
+	GetOrCreate(ctx context.Context, telegramID int64, username, firstName, lastName, languageCode string) (*models.User, error)
+	SyncFromTelegram(ctx context.Context, telegramID int64, username, firstName, lastName, languageCode string) (*models.User, error)
+	SyncUserpic(ctx context.Context, telegramID int64, userpicURL string) error
+	ClaimUserpicSync(ctx context.Context, userID uint, now, notBefore time.Time) (bool, error)
+	Update(ctx context.Context, user *models.User) error
+	UpdateColumns(ctx context.Context, user *models.User, columns ...string) error
+	SetBookingLimit(ctx context.Context, userID uint, limit *int) error
+	TightenBookingLimit(ctx context.Context, userID uint, limit int) (bool, error)
+	UpdateRole(ctx context.Context, userID uint, role models.UserRole) error
+	List(ctx context.Context, limit, offset int, order string) ([]models.User, int64, error)
+	GetPhonebook(ctx context.Context, limit, offset int, order string) ([]models.User, int64, error)
+	ExportPhonebookBatches(ctx context.Context, batchSize int, fn func([]models.User) error) error
+	Search(ctx context.Context, search string, limit, offset int, order string) ([]models.User, int64, error)
+	ListDirectory(ctx context.Context, filter repository.DirectoryFilter, limit, offset int) ([]models.User, int64, error)
+}
+
+// NotificationStore persists room notification subscriptions
+type NotificationStore interface {
+	Subscribe(ctx context.Context, userID uint, roomID uint) error
+	Unsubscribe(ctx context.Context, userID uint, roomID uint) error
+	GetUserSubscriptions(ctx context.Context, userID uint) ([]models.NotificationSubscription, error)
+	GetRoomSubscribers(ctx context.Context, roomID uint) ([]models.NotificationSubscription, error)
+	GetRoomSubscriberContacts(ctx context.Context, roomID uint) ([]models.RoomSubscriber, error)
+	IsSubscribed(ctx context.Context, userID uint, roomID uint) (bool, error)
+}
+
+// APIKeyStore persists third-party API keys
+type APIKeyStore interface {
+	Create(ctx context.Context, key *models.APIKey) error
+	GetBySecretHash(ctx context.Context, hash string) (*models.APIKey, error)
+	GetAll(ctx context.Context, order string) ([]models.APIKey, error)
+	TouchLastUsed(ctx context.Context, id uint, at time.Time) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// SlackTargetStore persists Slack notification targets
+type SlackTargetStore interface {
+	Create(ctx context.Context, target *models.SlackTarget) error
+	GetAll(ctx context.Context) ([]models.SlackTarget, error)
+	GetForRoom(ctx context.Context, roomID uint) ([]models.SlackTarget, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+// RESTHookStore persists REST hook subscriptions
+type RESTHookStore interface {
+	Create(ctx context.Context, hook *models.RESTHook) error
+	GetByAPIKey(ctx context.Context, apiKeyID uint) ([]models.RESTHook, error)
+	CountByAPIKey(ctx context.Context, apiKeyID uint) (int64, error)
+	GetByEvent(ctx context.Context, event string, now time.Time) ([]models.RESTHook, error)
+	Delete(ctx context.Context, id, apiKeyID uint) error
+}
+
+// DoorAccessStore persists door access grants
+type DoorAccessStore interface {
+	GetBookingsToGrant(ctx context.Context, from, to time.Time) ([]models.Booking, error)
+	Claim(ctx context.Context, grant *models.DoorAccessGrant) (bool, error)
+	MarkGranted(ctx context.Context, grant *models.DoorAccessGrant) error
+	Delete(ctx context.Context, id uint) error
+	GetByBookingID(ctx context.Context, bookingID uint) (*models.DoorAccessGrant, error)
+	GetExpired(ctx context.Context, now time.Time) ([]models.DoorAccessGrant, error)
+	MarkRevoked(ctx context.Context, id uint, at time.Time) (bool, error)
+	ClearRevoked(ctx context.Context, id uint) error
+}
+
+// TeamStore persists teams and their members
+type TeamStore interface {
+	Create(ctx context.Context, team *models.Team) error
+	GetByID(ctx context.Context, id uint) (*models.Team, error)
+	List(ctx context.Context, filter repository.TeamFilter, limit, offset int) ([]models.Team, int64, error)
+	Update(ctx context.Context, team *models.Team) error
+	AddMembers(ctx context.Context, teamID uint, userIDs []uint) error
+	RemoveMembers(ctx context.Context, teamID uint, userIDs []uint) error
+	ClearMembers(ctx context.Context, teamID uint) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// AuditStore persists audit log entries
+type AuditStore interface {
+	Create(ctx context.Context, entry *models.AuditLog) error
+	List(ctx context.Context, filter repository.AuditFilter) ([]models.AuditLog, int64, error)
+	DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// PurgeStore hard-deletes expired soft-deleted rows
+type PurgeStore interface {
+	PurgeBookings(ctx context.Context, cutoff time.Time) (int64, error)
+	PurgeRooms(ctx context.Context, cutoff time.Time) (int64, error)
+	PurgeUsers(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// RetentionStore stores retention rules and deletes the rows they expire
+type RetentionStore interface {
+	ListRules(ctx context.Context) ([]models.RetentionRule, error)
+	UpsertRule(ctx context.Context, rule *models.RetentionRule) error
+	DeleteRule(ctx context.Context, entity string) error
+	CountExpiredBookings(ctx context.Context, cutoff time.Time) (matched, exempt int64, err error)
+	DeleteExpiredBookings(ctx context.Context, cutoff time.Time) (int64, error)
+	CountExpiredAuditLogs(ctx context.Context, cutoff time.Time) (int64, error)
+	DeleteExpiredAuditLogs(ctx context.Context, cutoff time.Time) (int64, error)
+	SetBookingExempt(ctx context.Context, bookingID uint, exempt bool) error
+}
+
+// AbuseStore queries booking patterns for the abuse detector and keeps its review queue
+type AbuseStore interface {
+	CountCancellationsSince(ctx context.Context, since time.Time, min int) ([]repository.UserCount, error)
+	GetActiveBookingSpans(ctx context.Context, start, end time.Time) ([]models.Booking, error)
+	CreateFlag(ctx context.Context, flag *models.AbuseFlag) (bool, error)
+	ListFlags(ctx context.Context, status models.AbuseFlagStatus, limit, offset int) ([]models.AbuseFlag, int64, error)
+	GetFlag(ctx context.Context, id uint) (*models.AbuseFlag, error)
+	ReviewFlag(ctx context.Context, id uint, status models.AbuseFlagStatus, reviewerID uint, now time.Time) (bool, error)
+}
+
+// BillingStore reads teams and ended bookings for usage billing
+type BillingStore interface {
+	ListTeams(ctx context.Context) ([]models.Team, error)
+	GetTeam(ctx context.Context, id uint) (*models.Team, error)
+	GetMemberships(ctx context.Context) ([]repository.TeamMembership, error)
+	GetEndedBookingSpans(ctx context.Context, start, end, now time.Time) ([]models.Booking, error)
+	GetUsers(ctx context.Context, ids []uint) ([]models.User, error)
+	SetIncludedHours(ctx context.Context, teamID uint, hours *int) error
+}
+
+// HolidayStore persists the closure calendar
+type HolidayStore interface {
+	ListBetween(ctx context.Context, from, to string) ([]models.Holiday, error)
+	GetByID(ctx context.Context, id uint) (*models.Holiday, error)
+	Create(ctx context.Context, holiday *models.Holiday) (bool, error)
+	Update(ctx context.Context, holiday *models.Holiday) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// FeedbackStore persists booking feedback and aggregates room ratings
+type FeedbackStore interface {
+	Upsert(ctx context.Context, feedback *models.BookingFeedback) error
+	RoomRatingStats(ctx context.Context, since time.Time) ([]repository.RoomRatingStats, error)
+}
+
+// CheckInStore finds bookings to check in to and records check-ins
+type CheckInStore interface {
+	FindCheckInBooking(ctx context.Context, roomID, userID uint, now, opensAt time.Time) (*models.Booking, error)
+	CheckIn(ctx context.Context, checkIn *models.BookingCheckIn) (bool, error)
+}
+
+// CleaningStore persists cleaning tasks and the cleaning progress of rooms
+type CleaningStore interface {
+	GetResetRoomIDs(ctx context.Context) ([]uint, error)
+	GetEndedBookingSpans(ctx context.Context, roomID uint, after, until time.Time) ([]models.Booking, error)
+	SaveRoomProgress(ctx context.Context, room *models.Room) error
+	CreateTask(ctx context.Context, task *models.CleaningTask) error
+	ListTasks(ctx context.Context, status models.CleaningTaskStatus, roomID uint, limit, offset int) ([]models.CleaningTask, int64, error)
+	GetTask(ctx context.Context, id uint) (*models.CleaningTask, error)
+	CompleteTask(ctx context.Context, id, userID uint, now time.Time) (bool, error)
+	CountOpenTasks(ctx context.Context, roomID uint) (int64, error)
+}
+
+// AttendanceStore reads the planned and observed attendance of finished bookings
+type AttendanceStore interface {
+	GetAttendance(ctx context.Context, from, to time.Time) ([]repository.BookingAttendance, error)
+}
+
+// OwnershipStore persists rooms dedicated to teams and delegated slots
+type OwnershipStore interface {
+	List(ctx context.Context) ([]models.RoomOwnership, error)
+	GetActive(ctx context.Context, since time.Time) ([]models.RoomOwnership, error)
+	GetByID(ctx context.Context, id uint) (*models.RoomOwnership, error)
+	GetByRoomID(ctx context.Context, roomID uint) (*models.RoomOwnership, error)
+	Create(ctx context.Context, ownership *models.RoomOwnership) error
+	Update(ctx context.Context, ownership *models.RoomOwnership) error
+	Delete(ctx context.Context, id uint) error
+	IsTeamMember(ctx context.Context, teamID, userID uint) (bool, error)
+	HasDelegation(ctx context.Context, ownershipID, userID uint, start, end time.Time) (bool, error)
+	ListDelegations(ctx context.Context, ownershipID uint, after time.Time) ([]models.RoomDelegation, error)
+	CreateDelegation(ctx context.Context, delegation *models.RoomDelegation) error
+	DeleteDelegation(ctx context.Context, ownershipID, id uint) error
+}
+
+// RentalStore persists external rental requests and their moderation
+type RentalStore interface {
+	List(ctx context.Context, status models.RentalRequestStatus, limit, offset int) ([]models.RentalRequest, int64, error)
+	GetByID(ctx context.Context, id uint) (*models.RentalRequest, error)
+	Create(ctx context.Context, request *models.RentalRequest) error
+	Review(ctx context.Context, id uint, status models.RentalRequestStatus, reason string, reviewerID uint, now time.Time) (bool, error)
+	SetBooking(ctx context.Context, id, bookingID uint) error
+}
+
+// QuarantineStore persists booking events held back by the content filter and their review
+type QuarantineStore interface {
+	List(ctx context.Context, status models.QuarantineStatus, limit, offset int) ([]models.BookingQuarantine, int64, error)
+	GetByID(ctx context.Context, id uint) (*models.BookingQuarantine, error)
+	GetPendingByBooking(ctx context.Context, bookingID uint) (*models.BookingQuarantine, error)
+	Create(ctx context.Context, entry *models.BookingQuarantine) (bool, error)
+	UpdateHeld(ctx context.Context, id uint, changes, violations datatypes.JSON) error
+	Review(ctx context.Context, id uint, status models.QuarantineStatus, reviewerID uint, now time.Time) (bool, error)
+}
+
+// FloorStore persists floors and the placement of rooms on floor plans
+type FloorStore interface {
+	List(ctx context.Context) ([]models.Floor, error)
+	GetByID(ctx context.Context, id uint) (*models.Floor, error)
+	Create(ctx context.Context, floor *models.Floor) error
+	Update(ctx context.Context, floor *models.Floor) error
+	Delete(ctx context.Context, id uint) error
+	GetRooms(ctx context.Context, floorID uint) ([]models.Room, error)
+	SetRoomPlacement(ctx context.Context, roomID uint, floorID *uint, x, y *float64) error
+}
+
+// Репозитории должны удовлетворять интерфейсам - проверка на этапе компиляции
+var (
+	_ TxRunner            = (*repository.TxManager)(nil)
+	_ BookingStore        = (*repository.BookingRepository)(nil)
+	_ BookingHistoryStore = (*repository.BookingHistoryRepository)(nil)
+	_ RoomStore           = (*repository.RoomRepository)(nil)
+	_ EquipmentStore      = (*repository.EquipmentRepository)(nil)
+	_ UserStore           = (*repository.UserRepository)(nil)
+	_ NotificationStore   = (*repository.NotificationRepository)(nil)
+	_ APIKeyStore         = (*repository.APIKeyRepository)(nil)
+	_ AuditStore          = (*repository.AuditRepository)(nil)
+	_ PurgeStore          = (*repository.PurgeRepository)(nil)
+	_ RetentionStore      = (*repository.RetentionRepository)(nil)
+	_ AbuseStore          = (*repository.AbuseRepository)(nil)
+	_ BillingStore        = (*repository.BillingRepository)(nil)
+	_ HolidayStore        = (*repository.HolidayRepository)(nil)
+	_ FeedbackStore       = (*repository.FeedbackRepository)(nil)
+	_ CheckInStore        = (*repository.CheckInRepository)(nil)
+	_ AttendanceStore     = (*repository.CheckInRepository)(nil)
+	_ CleaningStore       = (*repository.CleaningRepository)(nil)
+	_ FloorStore          = (*repository.FloorRepository)(nil)
+	_ OwnershipStore      = (*repository.OwnershipRepository)(nil)
+	_ RentalStore         = (*repository.RentalRepository)(nil)
+	_ QuarantineStore     = (*repository.QuarantineRepository)(nil)
+)
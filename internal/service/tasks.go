@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+
+	"github.com/space/backend/internal/workerpool"
+)
+
+// TaskQueue runs background tasks with bounded concurrency
+// Исходящие вызовы (webhook бота, Telegram API) идут через очередь, а не через go func():
+// при заполненной очереди Submit возвращает ошибку, и задача отбрасывается
+type TaskQueue interface {
+	Submit(name string, fn func(ctx context.Context)) error
+}
+
+var _ TaskQueue = (*workerpool.Pool)(nil)
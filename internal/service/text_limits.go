@@ -0,0 +1,43 @@
+package service
+
+// Длины текстовых полей по умолчанию (в символах)
+const (
+	DefaultMaxTitleLength       = 200
+	DefaultMaxDescriptionLength = 2000
+	DefaultMaxNameLength        = 50
+	// maxAboutLength - колонка users.about объявлена как varchar(500)
+	maxAboutLength = 500
+	// maxPhoneNumberLength - номер в международном формате с пробелами и скобками
+	maxPhoneNumberLength = 32
+)
+
+// TextLimits are the maximum lengths of free-text fields in characters
+type TextLimits struct {
+	Title       int // Название бронирования
+	Description int // Описание бронирования
+	Name        int // Имя и фамилия в профиле
+}
+
+// DefaultTextLimits returns the built-in length limits
+func DefaultTextLimits() TextLimits {
+	return TextLimits{
+		Title:       DefaultMaxTitleLength,
+		Description: DefaultMaxDescriptionLength,
+		Name:        DefaultMaxNameLength,
+	}
+}
+
+// withDefaults заменяет незаданные (<= 0) лимиты значениями по умолчанию
+func (l TextLimits) withDefaults() TextLimits {
+	defaults := DefaultTextLimits()
+	if l.Title <= 0 {
+		l.Title = defaults.Title
+	}
+	if l.Description <= 0 {
+		l.Description = defaults.Description
+	}
+	if l.Name <= 0 {
+		l.Name = defaults.Name
+	}
+	return l
+}
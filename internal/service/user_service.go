@@ -1,26 +1,50 @@
 package service
 
 import (
-	"log"
+	"context"
+	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/space/backend/internal/models"
-	"github.com/space/backend/internal/repository"
+	"github.com/space/backend/pkg/apperror"
 	"github.com/space/backend/pkg/telegram"
+	"github.com/space/backend/pkg/validator"
+)
+
+var (
+	// ErrInvalidRole is returned when an unknown role is requested
+	ErrInvalidRole = apperror.BadRequest("invalid_role", "invalid role")
+	// ErrInvalidTelegramID - telegram_id = 0 зарезервирован за пользователями OIDC без Telegram
+	ErrInvalidTelegramID = apperror.BadRequest("invalid_telegram_id", "invalid Telegram ID")
 )
 
 // UserService handles user business logic
 type UserService struct {
-	userRepo *repository.UserRepository
-	botToken string // Нужен для получения фото профиля из Telegram
+	userRepo            UserStore
+	tasks               TaskQueue
+	botToken            string        // Нужен для получения фото профиля из Telegram
+	userpicSyncInterval time.Duration // Userpic пользователя запрашивается не чаще (0 - при каждом входе)
+	textLimits          TextLimits
+	logger              *slog.Logger
 }
 
 // NewUserService creates a new user service
-func NewUserService(userRepo *repository.UserRepository) *UserService {
+func NewUserService(userRepo UserStore, tasks TaskQueue, userpicSyncInterval time.Duration, logger *slog.Logger) *UserService {
 	return &UserService{
-		userRepo: userRepo,
+		userRepo:            userRepo,
+		tasks:               tasks,
+		userpicSyncInterval: userpicSyncInterval,
+		textLimits:          DefaultTextLimits(),
+		logger:              logger,
 	}
 }
 
+// SetTextLimits sets the maximum length of profile names
+func (s *UserService) SetTextLimits(limits TextLimits) {
+	s.textLimits = limits.withDefaults()
+}
+
 // SetBotToken sets the bot token for Telegram API calls
 func (s *UserService) SetBotToken(botToken string) {
 	s.botToken = botToken
@@ -28,50 +52,95 @@ func (s *UserService) SetBotToken(botToken string) {
 
 // SyncTelegramUser syncs a user from Telegram (get or create)
 // NOTE: This does NOT update existing users automatically
-func (s *UserService) SyncTelegramUser(telegramID int64, username, firstName, lastName, languageCode string) (*models.User, error) {
-	user, err := s.userRepo.GetOrCreate(telegramID, username, firstName, lastName, languageCode)
+func (s *UserService) SyncTelegramUser(ctx context.Context, telegramID int64, username, firstName, lastName, languageCode string) (*models.User, error) {
+	if telegramID <= 0 {
+		return nil, ErrInvalidTelegramID
+	}
+	user, err := s.userRepo.GetOrCreate(ctx, telegramID, username, firstName, lastName, languageCode)
 	if err != nil {
 		return nil, err
 	}
 
 	// Асинхронно обновляем userpic из Telegram (не блокируем запрос)
-	if s.botToken != "" {
-		go s.syncUserpicAsync(telegramID)
+	if s.botToken != "" && s.userpicSyncDue(ctx, user) {
+		err := s.tasks.Submit("userpic_sync", func(ctx context.Context) {
+			s.syncUserpic(ctx, telegramID)
+		})
+		if err != nil {
+			s.logger.Warn("userpic sync dropped", "telegram_id", telegramID, "error", err)
+		}
 	}
 
 	return user, nil
 }
 
-// syncUserpicAsync асинхронно обновляет userpic пользователя из Telegram
-func (s *UserService) syncUserpicAsync(telegramID int64) {
-	userpicURL, err := telegram.GetUserProfilePhotoURL(telegramID, s.botToken)
+// userpicSyncDue отмечает синхронизацию userpic, если с прошлой прошло userpicSyncInterval
+// Отметка ставится до вызова Telegram: неудачная попытка повторяется только через интервал
+func (s *UserService) userpicSyncDue(ctx context.Context, user *models.User) bool {
+	now := time.Now()
+	if user.UserpicSyncedAt != nil && now.Sub(*user.UserpicSyncedAt) < s.userpicSyncInterval {
+		return false
+	}
+	claimed, err := s.userRepo.ClaimUserpicSync(ctx, user.ID, now, now.Add(-s.userpicSyncInterval))
+	if err != nil {
+		s.logger.Warn("failed to mark userpic sync", "user_id", user.ID, "error", err)
+		return false
+	}
+	return claimed
+}
+
+// syncUserpic обновляет userpic пользователя из Telegram (выполняется в пуле исходящих вызовов)
+func (s *UserService) syncUserpic(ctx context.Context, telegramID int64) {
+	// Фоновая задача не связана с запросом - используем собственный таймаут
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	userpicURL, err := telegram.GetUserProfilePhotoURL(ctx, telegramID, s.botToken)
 	if err != nil {
-		log.Printf("WARNING: Failed to get userpic for user %d: %v", telegramID, err)
+		s.logger.Warn("failed to get userpic", "telegram_id", telegramID, "error", err)
 		return
 	}
 
 	// Обновляем только если фото есть
 	if userpicURL != "" {
-		if err := s.userRepo.SyncUserpic(telegramID, userpicURL); err != nil {
-			log.Printf("WARNING: Failed to sync userpic for user %d: %v", telegramID, err)
+		if err := s.userRepo.SyncUserpic(ctx, telegramID, userpicURL); err != nil {
+			s.logger.Warn("failed to sync userpic", "telegram_id", telegramID, "error", err)
 		}
 	}
 }
 
 // SyncUserFromTelegram explicitly updates user data from Telegram
 // Use this when user wants to sync their Telegram profile changes
-func (s *UserService) SyncUserFromTelegram(telegramID int64, username, firstName, lastName, languageCode string) (*models.User, error) {
-	return s.userRepo.SyncFromTelegram(telegramID, username, firstName, lastName, languageCode)
+func (s *UserService) SyncUserFromTelegram(ctx context.Context, telegramID int64, username, firstName, lastName, languageCode string) (*models.User, error) {
+	return s.userRepo.SyncFromTelegram(ctx, telegramID, username, firstName, lastName, languageCode)
 }
 
 // GetUser gets a user by ID
-func (s *UserService) GetUser(id uint) (*models.User, error) {
-	return s.userRepo.GetByID(id)
+func (s *UserService) GetUser(ctx context.Context, id uint) (*models.User, error) {
+	return s.userRepo.GetByID(ctx, id)
 }
 
 // GetUserByTelegramID gets a user by Telegram ID
-func (s *UserService) GetUserByTelegramID(telegramID int64) (*models.User, error) {
-	return s.userRepo.GetByTelegramID(telegramID)
+func (s *UserService) GetUserByTelegramID(ctx context.Context, telegramID int64) (*models.User, error) {
+	return s.userRepo.GetByTelegramID(ctx, telegramID)
+}
+
+// UserCard is a compact view of a user for the bot: who it is and how to address them
+type UserCard struct {
+	ID          uint   `json:"id"`
+	TelegramID  int64  `json:"telegram_id"`
+	Username    string `json:"username,omitempty"`
+	DisplayName string `json:"display_name"`
+}
+
+// NewUserCard builds the card of a user
+// Имя для отображения - имя и фамилия, без них - @username
+func NewUserCard(user *models.User) UserCard {
+	name := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	if name == "" && user.Username != "" {
+		name = "@" + user.Username
+	}
+	return UserCard{ID: user.ID, TelegramID: user.TelegramID, Username: user.Username, DisplayName: name}
 }
 
 // UpdateProfileRequest represents a request to update user profile
@@ -83,27 +152,32 @@ type UpdateProfileRequest struct {
 }
 
 // UpdateProfile updates user profile
-func (s *UserService) UpdateProfile(userID uint, req UpdateProfileRequest) (*models.User, error) {
-	user, err := s.userRepo.GetByID(userID)
+func (s *UserService) UpdateProfile(ctx context.Context, userID uint, req UpdateProfileRequest) (*models.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Обновляем поля
+	// Обновляем поля; все ошибки полей возвращаются разом
+	var fieldErrs validator.Errors
 	if req.FirstName != nil {
-		user.FirstName = *req.FirstName
+		user.FirstName = fieldErrs.Name("first_name", *req.FirstName, s.textLimits.Name)
 	}
 	if req.LastName != nil {
-		user.LastName = *req.LastName
+		user.LastName = fieldErrs.Name("last_name", *req.LastName, s.textLimits.Name)
 	}
 	if req.PhoneNumber != nil {
-		user.PhoneNumber = *req.PhoneNumber
+		user.PhoneNumber = fieldErrs.Text("phone_number", *req.PhoneNumber, maxPhoneNumberLength, false)
 	}
 	if req.About != nil {
-		user.About = *req.About
+		user.About = fieldErrs.Text("about", *req.About, maxAboutLength, false)
+	}
+	if err := fieldErrs.Err(); err != nil {
+		return nil, err
 	}
 
-	err = s.userRepo.Update(user)
+	// is_in_phone_book пересчитывается хуком BeforeSave по ФИО и телефону
+	err = s.userRepo.UpdateColumns(ctx, user, "first_name", "last_name", "phone_number", "about", "is_in_phone_book")
 	if err != nil {
 		return nil, err
 	}
@@ -121,15 +195,37 @@ func (s *UserService) CanEditUser(currentUser *models.User, targetUserID uint) b
 	return currentUser.ID == targetUserID
 }
 
-// GetPhonebook gets all users in the phonebook
-func (s *UserService) GetPhonebook() ([]models.User, error) {
-	return s.userRepo.GetPhonebook()
+// ListUsers gets a page of users and their total count (admin only)
+func (s *UserService) ListUsers(ctx context.Context, limit, offset int, order string) ([]models.User, int64, error) {
+	limit, offset = pageBounds(limit, offset, DefaultUserPageSize, MaxUserPageSize)
+	return s.userRepo.List(ctx, limit, offset, order)
+}
+
+// SetUserRole changes a user's role (admin only)
+func (s *UserService) SetUserRole(ctx context.Context, userID uint, role models.UserRole) (*models.User, error) {
+	if role != models.RoleUser && role != models.RoleAdmin {
+		return nil, ErrInvalidRole
+	}
+
+	if err := s.userRepo.UpdateRole(ctx, userID, role); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("user role changed", "user_id", userID, "role", role)
+	return s.userRepo.GetByID(ctx, userID)
 }
 
-// SearchPhonebook searches users in the phonebook
-func (s *UserService) SearchPhonebook(query string) ([]models.User, error) {
+// SearchPhonebook gets a page of phonebook users matching the query and their total count
+// Пустой запрос возвращает всю телефонную книгу
+func (s *UserService) SearchPhonebook(ctx context.Context, query string, limit, offset int, order string) ([]models.User, int64, error) {
+	limit, offset = pageBounds(limit, offset, DefaultPhonebookPageSize, MaxPhonebookPageSize)
 	if query == "" {
-		return s.GetPhonebook()
+		return s.userRepo.GetPhonebook(ctx, limit, offset, order)
 	}
-	return s.userRepo.Search(query)
+	return s.userRepo.Search(ctx, query, limit, offset, order)
+}
+
+// ExportPhonebook passes the whole phonebook to fn in batches of ExportBatchSize
+func (s *UserService) ExportPhonebook(ctx context.Context, fn func([]models.User) error) error {
+	return s.userRepo.ExportPhonebookBatches(ctx, ExportBatchSize, fn)
 }
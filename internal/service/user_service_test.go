@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/pkg/validator"
+)
+
+// countingTaskQueue считает задачи, не выполняя их
+type countingTaskQueue struct {
+	submitted map[string]int
+}
+
+func (q *countingTaskQueue) Submit(name string, fn func(ctx context.Context)) error {
+	q.submitted[name]++
+	return nil
+}
+
+// fakeUserpicUserStore хранит отметки синхронизации userpic, как UserRepository.ClaimUserpicSync
+type fakeUserpicUserStore struct {
+	*fakeUserStore
+}
+
+func (f *fakeUserpicUserStore) GetOrCreate(ctx context.Context, telegramID int64, username, firstName, lastName, languageCode string) (*models.User, error) {
+	for _, user := range f.users {
+		if user.TelegramID == telegramID {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	user := &models.User{ID: uint(len(f.users) + 1), TelegramID: telegramID, Username: username}
+	f.users[user.ID] = user
+	copied := *user
+	return &copied, nil
+}
+
+func (f *fakeUserpicUserStore) ClaimUserpicSync(ctx context.Context, userID uint, now, notBefore time.Time) (bool, error) {
+	user := f.users[userID]
+	if user.UserpicSyncedAt != nil && !user.UserpicSyncedAt.Before(notBefore) {
+		return false, nil
+	}
+	user.UserpicSyncedAt = &now
+	return true, nil
+}
+
+func TestUserService_UserpicSyncDebounced(t *testing.T) {
+	users := &fakeUserpicUserStore{&fakeUserStore{users: map[uint]*models.User{}}}
+	tasks := &countingTaskQueue{submitted: map[string]int{}}
+	svc := NewUserService(users, tasks, 24*time.Hour, slog.Default())
+	svc.SetBotToken("token")
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := svc.SyncTelegramUser(ctx, 42, "anna", "Anna", "", "ru"); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+	if tasks.submitted["userpic_sync"] != 1 {
+		t.Errorf("Expected one userpic sync per interval, got: %d", tasks.submitted["userpic_sync"])
+	}
+
+	// Интервал прошёл - userpic запрашивается снова
+	expired := time.Now().Add(-25 * time.Hour)
+	users.users[1].UserpicSyncedAt = &expired
+	_, _ = svc.SyncTelegramUser(ctx, 42, "anna", "Anna", "", "ru")
+	if tasks.submitted["userpic_sync"] != 2 {
+		t.Errorf("Expected a sync after the interval, got: %d", tasks.submitted["userpic_sync"])
+	}
+}
+
+func TestUserService_UpdateProfileValidation(t *testing.T) {
+	users := &fakeUserStore{users: map[uint]*models.User{1: {ID: 1, FirstName: "Anna"}}}
+	svc := NewUserService(users, nil, 0, slog.Default())
+	svc.SetTextLimits(TextLimits{Name: 10})
+	ctx := context.Background()
+
+	firstName, lastName, about := "A<b>", strings.Repeat("к", 11), strings.Repeat("a", maxAboutLength+1)
+	_, err := svc.UpdateProfile(ctx, 1, UpdateProfileRequest{FirstName: &firstName, LastName: &lastName, About: &about})
+	var fieldErrs validator.Errors
+	if !errors.As(err, &fieldErrs) || len(fieldErrs) != 3 {
+		t.Fatalf("Expected errors for three fields, got: %v", err)
+	}
+	if fieldErrs[0].Rule != validator.RuleInvalid || fieldErrs[1].Rule != validator.RuleMaxLength || fieldErrs[2].Field != "about" {
+		t.Errorf("Expected invalid first_name, too long last_name and about, got: %+v", fieldErrs)
+	}
+
+	firstName, lastName = " Анна-Мария ", ""
+	user, err := svc.UpdateProfile(ctx, 1, UpdateProfileRequest{FirstName: &firstName, LastName: &lastName})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if user.FirstName != "Анна-Мария" || user.LastName != "" {
+		t.Errorf("Expected trimmed name and cleared last name, got: %q %q", user.FirstName, user.LastName)
+	}
+}
+
+func TestNewUserCard(t *testing.T) {
+	if card := NewUserCard(&models.User{ID: 1, TelegramID: 42, FirstName: "Anna", LastName: "Ivanova"}); card.DisplayName != "Anna Ivanova" {
+		t.Errorf("Expected full name, got: %q", card.DisplayName)
+	}
+	if card := NewUserCard(&models.User{ID: 2, TelegramID: 43, Username: "anna"}); card.DisplayName != "@anna" {
+		t.Errorf("Expected username without a name, got: %q", card.DisplayName)
+	}
+}
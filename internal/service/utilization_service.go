@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/internal/repository"
+	"github.com/space/backend/pkg/apperror"
+)
+
+// Источник фактического числа участников бронирования
+const (
+	AttendanceAuto         = "auto"         // Отметки по QR-коду, а без них - создатель и присоединившиеся
+	AttendanceParticipants = "participants" // Создатель и присоединившиеся участники
+	AttendanceCheckIns     = "checkins"     // Только отметки; бронирования без отметок не учитываются
+)
+
+// ErrInvalidParticipants is returned when a room suggestion is asked for less than one participant
+var ErrInvalidParticipants = apperror.BadRequest("invalid_participants", "participants must be at least 1")
+
+// suggestionHistory - период прошлых бронирований, по которому оценивается фактическое присутствие
+const suggestionHistory = 90 * 24 * time.Hour
+
+// RoomAvailabilityChecker tells whether a room is free for a period
+// Реализуется BookingService: те же проверки пересечений и нерабочих дней, что при создании бронирования
+type RoomAvailabilityChecker interface {
+	CheckAvailability(ctx context.Context, roomID uint, start, end time.Time) (*AvailabilityCheck, error)
+}
+
+var _ RoomAvailabilityChecker = (*BookingService)(nil)
+
+// UtilizationSettings configures attendance tracking and right-sizing recommendations
+type UtilizationSettings struct {
+	Source         string // auto, participants или checkins
+	MaxFillPercent int    // Комната заполнена в среднем не больше чем на столько процентов - предлагается меньшая (0 - без рекомендаций)
+	MinBookings    int    // Бронирований за период, без которых рекомендация не даётся
+}
+
+// RoomRef is a short reference to a room
+type RoomRef struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	Capacity int    `json:"capacity"`
+}
+
+// RoomUtilization is the planned and actual attendance of a room's bookings over a period
+type RoomUtilization struct {
+	RoomID       uint     `json:"room_id"`
+	RoomName     string   `json:"room_name"`
+	Capacity     int      `json:"capacity"`
+	Bookings     int      `json:"bookings"`      // Закончившихся бронирований с данными о присутствии
+	AvgEstimated float64  `json:"avg_estimated"` // Среднее заявленное число участников
+	AvgActual    float64  `json:"avg_actual"`    // Среднее фактическое число участников
+	PeakActual   int      `json:"peak_actual"`
+	FillPercent  int      `json:"fill_percent"`           // Средняя фактическая заполненность вместимости
+	SmallerRoom  *RoomRef `json:"smaller_room,omitempty"` // Меньшая комната, в которую помещаются все встречи периода
+}
+
+// RoomSuggestion is the smallest free room for a meeting, sized by the attendance of past meetings
+type RoomSuggestion struct {
+	Participants         int          `json:"participants"`          // Заявленное число участников
+	ExpectedParticipants int          `json:"expected_participants"` // Ожидаемое с учётом фактического присутствия на прошлых встречах
+	Room                 *models.Room `json:"room"`                  // nil - свободной комнаты нужного размера нет
+}
+
+// UtilizationService compares estimated and actual attendance of rooms
+type UtilizationService struct {
+	attendanceRepo AttendanceStore
+	roomRepo       RoomStore
+	availability   RoomAvailabilityChecker
+	settings       UtilizationSettings
+}
+
+// NewUtilizationService creates a new utilization service
+func NewUtilizationService(attendanceRepo AttendanceStore, roomRepo RoomStore, availability RoomAvailabilityChecker, settings UtilizationSettings) *UtilizationService {
+	if settings.Source == "" {
+		settings.Source = AttendanceAuto
+	}
+	return &UtilizationService{attendanceRepo: attendanceRepo, roomRepo: roomRepo, availability: availability, settings: settings}
+}
+
+// RoomReport gets the utilization of active rooms for bookings that started since the given time, least filled first
+// Комнаты без бронирований за период в отчёт не попадают
+func (s *UtilizationService) RoomReport(ctx context.Context, since time.Time) ([]RoomUtilization, error) {
+	rooms, err := s.roomRepo.GetAll(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	attendance, err := s.attendanceRepo.GetAttendance(ctx, since, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	type totals struct{ bookings, estimated, actual, peak int }
+	byRoom := make(map[uint]*totals, len(rooms))
+	for _, a := range attendance {
+		actual, ok := s.actualAttendance(a)
+		if !ok {
+			continue
+		}
+		t := byRoom[a.RoomID]
+		if t == nil {
+			t = &totals{}
+			byRoom[a.RoomID] = t
+		}
+		t.bookings++
+		t.estimated += a.EstimatedParticipants
+		t.actual += actual
+		t.peak = max(t.peak, actual)
+	}
+
+	report := make([]RoomUtilization, 0, len(byRoom))
+	for _, room := range rooms {
+		t := byRoom[room.ID]
+		if t == nil {
+			continue
+		}
+		u := RoomUtilization{
+			RoomID:       room.ID,
+			RoomName:     room.Name,
+			Capacity:     room.Capacity,
+			Bookings:     t.bookings,
+			AvgEstimated: roundRating(float64(t.estimated) / float64(t.bookings)),
+			AvgActual:    roundRating(float64(t.actual) / float64(t.bookings)),
+			PeakActual:   t.peak,
+		}
+		if room.Capacity > 0 {
+			u.FillPercent = int(math.Round(float64(t.actual) * 100 / float64(t.bookings*room.Capacity)))
+		}
+		if s.settings.MaxFillPercent > 0 && t.bookings >= s.settings.MinBookings && u.FillPercent <= s.settings.MaxFillPercent {
+			u.SmallerRoom = smallerRoom(rooms, &room, t.peak)
+		}
+		report = append(report, u)
+	}
+
+	sort.SliceStable(report, func(i, j int) bool { return report[i].FillPercent < report[j].FillPercent })
+	return report, nil
+}
+
+// SuggestRoom finds the smallest room that is free for the period and fits the expected number of participants
+// Ожидаемое число - заявленное, уменьшенное в той же доле, в какой на прошлых встречах приходило меньше
+// заявленного; доля учитывается не меньше чем по RIGHTSIZE_MIN_BOOKINGS бронированиям
+func (s *UtilizationService) SuggestRoom(ctx context.Context, start, end time.Time, participants int) (*RoomSuggestion, error) {
+	if !end.After(start) {
+		return nil, ErrInvalidTime
+	}
+	if participants < 1 {
+		return nil, ErrInvalidParticipants
+	}
+
+	ratio, err := s.attendanceRatio(ctx)
+	if err != nil {
+		return nil, err
+	}
+	expected := int(math.Ceil(float64(participants) * ratio))
+	suggestion := &RoomSuggestion{Participants: participants, ExpectedParticipants: min(max(expected, 1), participants)}
+
+	rooms, err := s.roomRepo.GetAll(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(rooms, func(i, j int) bool { return rooms[i].Capacity < rooms[j].Capacity })
+	for i := range rooms {
+		if rooms[i].Capacity < suggestion.ExpectedParticipants {
+			continue
+		}
+		check, err := s.availability.CheckAvailability(ctx, rooms[i].ID, start, end)
+		if err != nil {
+			return nil, err
+		}
+		if check.Available {
+			suggestion.Room = &rooms[i]
+			break
+		}
+	}
+	return suggestion, nil
+}
+
+// attendanceRatio возвращает долю фактически пришедших от заявленного числа участников на прошлых встречах
+// 1 - истории недостаточно или на встречи приходят все заявленные
+func (s *UtilizationService) attendanceRatio(ctx context.Context) (float64, error) {
+	now := time.Now()
+	attendance, err := s.attendanceRepo.GetAttendance(ctx, now.Add(-suggestionHistory), now)
+	if err != nil {
+		return 0, err
+	}
+	var bookings, estimated, actual int
+	for _, a := range attendance {
+		if a.EstimatedParticipants <= 0 {
+			continue
+		}
+		n, ok := s.actualAttendance(a)
+		if !ok {
+			continue
+		}
+		bookings++
+		estimated += a.EstimatedParticipants
+		actual += n
+	}
+	if bookings == 0 || bookings < s.settings.MinBookings || actual >= estimated {
+		return 1, nil
+	}
+	return float64(actual) / float64(estimated), nil
+}
+
+// actualAttendance возвращает фактическое число участников бронирования по настроенному источнику
+// false - данных о присутствии нет (источник checkins без отметок)
+func (s *UtilizationService) actualAttendance(a repository.BookingAttendance) (int, bool) {
+	joined := a.ParticipantCount + 1 // Создатель и присоединившиеся
+	switch s.settings.Source {
+	case AttendanceParticipants:
+		return joined, true
+	case AttendanceCheckIns:
+		return a.CheckInCount, a.CheckInCount > 0
+	default:
+		if a.CheckInCount > 0 {
+			return a.CheckInCount, true
+		}
+		return joined, true
+	}
+}
+
+// smallerRoom находит самую маленькую комнату меньше room, вмещающую peak участников
+func smallerRoom(rooms []models.Room, room *models.Room, peak int) *RoomRef {
+	var best *models.Room
+	for i := range rooms {
+		candidate := &rooms[i]
+		if candidate.ID == room.ID || candidate.Capacity < peak || candidate.Capacity >= room.Capacity {
+			continue
+		}
+		if best == nil || candidate.Capacity < best.Capacity {
+			best = candidate
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	return &RoomRef{ID: best.ID, Name: best.Name, Capacity: best.Capacity}
+}
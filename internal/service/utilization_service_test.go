@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/internal/repository"
+)
+
+type fakeAttendanceStore struct {
+	attendance []repository.BookingAttendance
+}
+
+func (f *fakeAttendanceStore) GetAttendance(ctx context.Context, from, to time.Time) ([]repository.BookingAttendance, error) {
+	return f.attendance, nil
+}
+
+func TestUtilizationService_RoomReport(t *testing.T) {
+	rooms := &fakeRoomStateRoomStore{&fakeRoomStore{rooms: map[uint]*models.Room{
+		1: {ID: 1, Name: "Hall", Capacity: 12, IsActive: true},
+		2: {ID: 2, Name: "Booth", Capacity: 2, IsActive: true},
+		3: {ID: 3, Name: "Small", Capacity: 4, IsActive: true},
+	}}}
+	// В зале бронируют на 8, а приходят 2-3; в "Small" одна встреча только с отметками
+	store := &fakeAttendanceStore{attendance: []repository.BookingAttendance{
+		{BookingID: 1, RoomID: 1, EstimatedParticipants: 8, ParticipantCount: 1},
+		{BookingID: 2, RoomID: 1, EstimatedParticipants: 8, ParticipantCount: 5, CheckInCount: 3},
+		{BookingID: 3, RoomID: 3, EstimatedParticipants: 4, ParticipantCount: 3},
+	}}
+	ctx := context.Background()
+
+	svc := NewUtilizationService(store, rooms, nil, UtilizationSettings{MaxFillPercent: 50, MinBookings: 2})
+	report, err := svc.RoomReport(ctx, time.Time{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(report) != 2 || report[0].RoomID != 1 {
+		t.Fatalf("Expected the hall first and rooms without bookings skipped, got: %+v", report)
+	}
+	hall := report[0]
+	if hall.AvgEstimated != 8 || hall.AvgActual != 2.5 || hall.PeakActual != 3 || hall.FillPercent != 21 {
+		t.Errorf("Unexpected hall utilization: %+v", hall)
+	}
+	if hall.SmallerRoom == nil || hall.SmallerRoom.ID != 3 {
+		t.Errorf("Expected the smallest room that fits 3 people, got: %+v", hall.SmallerRoom)
+	}
+	if report[1].SmallerRoom != nil {
+		t.Errorf("Expected no recommendation below RIGHTSIZE_MIN_BOOKINGS, got: %+v", report[1])
+	}
+
+	// Только отметки: бронирования без отметок не учитываются
+	checkIns := NewUtilizationService(store, rooms, nil, UtilizationSettings{Source: AttendanceCheckIns, MaxFillPercent: 50, MinBookings: 2})
+	report, _ = checkIns.RoomReport(ctx, time.Time{})
+	if len(report) != 1 || report[0].Bookings != 1 || report[0].SmallerRoom != nil {
+		t.Errorf("Expected one booking with check-ins and no recommendation, got: %+v", report)
+	}
+}
+
+func TestUtilizationService_SuggestRoom(t *testing.T) {
+	rooms := &fakeRoomStateRoomStore{&fakeRoomStore{rooms: map[uint]*models.Room{
+		1: {ID: 1, Name: "Hall", Capacity: 12, IsActive: true},
+		2: {ID: 2, Name: "Booth", Capacity: 2, IsActive: true},
+		3: {ID: 3, Name: "Small", Capacity: 4, IsActive: true},
+		4: {ID: 4, Name: "Medium", Capacity: 6, IsActive: true},
+	}}}
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	end := start.Add(time.Hour)
+	bookings := &fakeRoomStateBookingStore{newFakeBookingStore(
+		models.Booking{ID: 1, RoomID: 3, Title: "Busy", StartTime: start, EndTime: end, Status: models.BookingStatusConfirmed},
+	)}
+	availability := NewBookingService(fakeTx{}, bookings, rooms, &fakeUserStore{}, nil, nil, nil, slog.Default())
+	// На встречи приходит половина заявленных
+	store := &fakeAttendanceStore{attendance: []repository.BookingAttendance{
+		{BookingID: 1, RoomID: 1, EstimatedParticipants: 8, ParticipantCount: 3},
+		{BookingID: 2, RoomID: 1, EstimatedParticipants: 4, ParticipantCount: 1},
+	}}
+	ctx := context.Background()
+
+	svc := NewUtilizationService(store, rooms, availability, UtilizationSettings{MinBookings: 2})
+	suggestion, err := svc.SuggestRoom(ctx, start, end, 7)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if suggestion.ExpectedParticipants != 4 {
+		t.Errorf("Expected 4 of 7 participants by attendance history, got %d", suggestion.ExpectedParticipants)
+	}
+	if suggestion.Room == nil || suggestion.Room.ID != 4 {
+		t.Errorf("Expected the medium room since the small one is busy, got: %+v", suggestion.Room)
+	}
+
+	// Истории меньше RIGHTSIZE_MIN_BOOKINGS - комната подбирается на всех заявленных
+	strict := NewUtilizationService(store, rooms, availability, UtilizationSettings{MinBookings: 5})
+	suggestion, _ = strict.SuggestRoom(ctx, start, end, 7)
+	if suggestion.ExpectedParticipants != 7 || suggestion.Room == nil || suggestion.Room.ID != 1 {
+		t.Errorf("Expected the hall for 7 participants, got: %+v", suggestion)
+	}
+
+	suggestion, _ = strict.SuggestRoom(ctx, start, end, 20)
+	if suggestion.Room != nil {
+		t.Errorf("Expected no room for 20 participants, got: %+v", suggestion.Room)
+	}
+	if _, err := svc.SuggestRoom(ctx, start, end, 0); err != ErrInvalidParticipants {
+		t.Errorf("Expected ErrInvalidParticipants, got: %v", err)
+	}
+}
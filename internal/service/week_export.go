@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/space/backend/internal/models"
+	"github.com/space/backend/pkg/i18n"
+	"github.com/space/backend/pkg/ical"
+)
+
+// icalProdID - идентификатор приложения в файлах iCalendar
+const icalProdID = "-//Space//Bookings//EN"
+
+// WeekExport is the week of a user rendered on the server, ready to be forwarded by the bot as is
+// Форматирование одно для всех клиентов бота, поэтому сообщения не расходятся между версиями
+type WeekExport struct {
+	From        string `json:"from"`                   // Понедельник, YYYY-MM-DD
+	To          string `json:"to"`                     // Воскресенье, YYYY-MM-DD
+	UTCOffset   string `json:"utc_offset"`             // Смещение, в котором указано время в тексте, например +03:00
+	Total       int    `json:"total"`                  // Бронирований за неделю
+	Text        string `json:"text"`                   // Сообщение целиком (не длиннее 4096 символов)
+	ParseMode   string `json:"parse_mode"`             // parse_mode для sendMessage: MarkdownV2
+	ICS         string `json:"ics,omitempty"`          // Файл iCalendar с бронированиями недели, если запрошен
+	ICSFileName string `json:"ics_filename,omitempty"` // Имя файла для sendDocument
+}
+
+// weekExportText - тексты сообщения на одном языке
+type weekExportText struct {
+	title    string // Первый и последний день недели
+	empty    string
+	weekdays [7]string // С понедельника
+}
+
+var weekExportTexts = map[string]weekExportText{
+	i18n.RU: {
+		title:    "Бронирования на неделю %s – %s",
+		empty:    "Бронирований нет",
+		weekdays: [7]string{"Пн", "Вт", "Ср", "Чт", "Пт", "Сб", "Вс"},
+	},
+	i18n.EN: {
+		title:    "Bookings for the week %s – %s",
+		empty:    "No bookings",
+		weekdays: [7]string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"},
+	},
+}
+
+// ExportUserWeek renders the bookings of a user (created or participating) for the week of date
+// as a Telegram message and, if withICS is set, as an iCalendar file
+// Неделя - с понедельника по воскресенье в часовом поясе date; язык - из профиля пользователя.
+// Чужая неделя доступна только администратору; actor - пользователь, от имени которого действует бот
+func (s *BookingService) ExportUserWeek(ctx context.Context, actor *models.User, telegramID int64, date time.Time, withICS bool) (*WeekExport, error) {
+	if actor.TelegramID != telegramID && !actor.IsAdmin() {
+		return nil, ErrNotAuthorized
+	}
+	user, err := s.userRepo.GetByTelegramID(ctx, telegramID)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := weekBounds(date, date.Location())
+	bookings, err := s.bookingRepo.GetByUserInRange(ctx, user.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	lang := i18n.Normalize(user.LanguageCode)
+	if lang == "" {
+		lang = i18n.Default
+	}
+	export := &WeekExport{
+		From:      start.Format("2006-01-02"),
+		To:        end.AddDate(0, 0, -1).Format("2006-01-02"),
+		UTCOffset: start.Format("-07:00"),
+		Total:     len(bookings),
+		Text:      formatWeekMarkdown(bookings, start, weekExportTexts[lang]),
+		ParseMode: "MarkdownV2",
+	}
+	if withICS {
+		export.ICS = bookingsCalendar(bookings, time.Now())
+		export.ICSFileName = "bookings-" + export.From + ".ics"
+	}
+	return export, nil
+}
+
+// weekBounds возвращает начало понедельника недели t в часовом поясе loc и начало следующего понедельника
+func weekBounds(t time.Time, loc *time.Location) (time.Time, time.Time) {
+	day, _ := dayBounds(t, loc)
+	start := day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	return start, start.AddDate(0, 0, 7)
+}
+
+// formatWeekMarkdown формирует сообщение в разметке MarkdownV2: заголовок и абзац на каждый занятый день
+// Бронирование на несколько дней показывается в каждом из них, как в ежедневном расписании (00:00 и 24:00 на границах)
+func formatWeekMarkdown(bookings []models.Booking, start time.Time, texts weekExportText) string {
+	const dayLayout = "02.01"
+	last := start.AddDate(0, 0, 6)
+	parts := []string{"*" + escapeMarkdown(fmt.Sprintf(texts.title, start.Format(dayLayout), last.Format(dayLayout))) + "*"}
+
+	for i := 0; i < 7; i++ {
+		dayStart := start.AddDate(0, 0, i)
+		dayEnd := dayStart.AddDate(0, 0, 1)
+		lines := []string{"*" + escapeMarkdown(texts.weekdays[i]+" "+dayStart.Format(dayLayout)) + "*"}
+		for _, booking := range bookings {
+			if !booking.StartTime.Before(dayEnd) || !booking.EndTime.After(dayStart) {
+				continue
+			}
+			from, to := "00:00", "24:00"
+			if !booking.StartTime.Before(dayStart) {
+				from = booking.StartTime.In(start.Location()).Format("15:04")
+			}
+			if booking.EndTime.Before(dayEnd) {
+				to = booking.EndTime.In(start.Location()).Format("15:04")
+			}
+			lines = append(lines, escapeMarkdown(from+"–"+to+" · "+booking.Title)+" · _"+escapeMarkdown(booking.Room.Name)+"_")
+		}
+		if len(lines) > 1 {
+			parts = append(parts, strings.Join(lines, "\n"))
+		}
+	}
+	if len(parts) == 1 {
+		parts = append(parts, escapeMarkdown(texts.empty))
+	}
+	return truncateMessage(strings.Join(parts, "\n\n"), telegramMessageLimit)
+}
+
+// markdownEscaper экранирует символы, зарезервированные в MarkdownV2 Telegram
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`, "~", `\~`, "`", "\\`",
+	">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`, "=", `\=`, "|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+)
+
+// escapeMarkdown экранирует текст для MarkdownV2
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// bookingsCalendar формирует файл iCalendar; UID постоянный, поэтому повторный импорт обновляет события
+func bookingsCalendar(bookings []models.Booking, now time.Time) string {
+	events := make([]ical.Event, len(bookings))
+	for i, booking := range bookings {
+		events[i] = ical.Event{
+			UID:         fmt.Sprintf("booking-%d@space", booking.ID),
+			Start:       booking.StartTime,
+			End:         booking.EndTime,
+			Summary:     booking.Title,
+			Location:    booking.Room.Name,
+			Description: booking.Description,
+		}
+	}
+	return ical.Calendar(icalProdID, events, now)
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/models"
+)
+
+func TestExportUserWeek(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	monday := time.Date(2025, 5, 12, 0, 0, 0, 0, moscow)
+	room := models.Room{ID: 1, Name: "Room_1"}
+	bookings := newFakeBookingStore(
+		models.Booking{ID: 1, RoomID: 1, Room: room, CreatorID: 10, Title: "Sync (weekly)", Description: "Agenda", StartTime: monday.Add(10 * time.Hour), EndTime: monday.Add(11*time.Hour + 30*time.Minute), Status: models.BookingStatusConfirmed},
+		models.Booking{ID: 2, RoomID: 1, Room: room, CreatorID: 20, Participants: []models.User{{ID: 10}}, Title: "Hackathon", StartTime: monday.AddDate(0, 0, 3).Add(18 * time.Hour), EndTime: monday.AddDate(0, 0, 4).Add(2 * time.Hour), Status: models.BookingStatusConfirmed},
+		models.Booking{ID: 3, RoomID: 1, Room: room, CreatorID: 10, Title: "Cancelled", StartTime: monday.Add(14 * time.Hour), EndTime: monday.Add(15 * time.Hour), Status: models.BookingStatusCancelled},
+		models.Booking{ID: 4, RoomID: 1, Room: room, CreatorID: 10, Title: "Next week", StartTime: monday.AddDate(0, 0, 7), EndTime: monday.AddDate(0, 0, 7).Add(time.Hour), Status: models.BookingStatusConfirmed},
+		models.Booking{ID: 5, RoomID: 1, Room: room, CreatorID: 20, Title: "Someone else's", StartTime: monday.Add(16 * time.Hour), EndTime: monday.Add(17 * time.Hour), Status: models.BookingStatusConfirmed},
+	)
+	users := &fakeUserStore{users: map[uint]*models.User{
+		10: {ID: 10, TelegramID: 100, Role: models.RoleUser, LanguageCode: "ru"},
+		20: {ID: 20, TelegramID: 200, Role: models.RoleUser},
+		30: {ID: 30, TelegramID: 300, Role: models.RoleAdmin, LanguageCode: "en"},
+	}}
+	svc := NewBookingService(fakeTx{}, bookings, &fakeRoomStore{}, users, nil, nil, nil, slog.Default())
+	ctx := context.Background()
+
+	// Любой день недели - та же неделя
+	export, err := svc.ExportUserWeek(ctx, users.users[10], 100, monday.AddDate(0, 0, 5).Add(20*time.Hour), true)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if export.From != "2025-05-12" || export.To != "2025-05-18" || export.UTCOffset != "+03:00" || export.Total != 2 || export.ParseMode != "MarkdownV2" {
+		t.Errorf("Expected the week of May 12 with two bookings, got: %+v", export)
+	}
+	want := "*Бронирования на неделю 12\\.05 – 18\\.05*\n\n" +
+		"*Пн 12\\.05*\n10:00–11:30 · Sync \\(weekly\\) · _Room\\_1_\n\n" +
+		"*Чт 15\\.05*\n18:00–24:00 · Hackathon · _Room\\_1_\n\n" +
+		"*Пт 16\\.05*\n00:00–02:00 · Hackathon · _Room\\_1_"
+	if export.Text != want {
+		t.Errorf("Expected text:\n%s\ngot:\n%s", want, export.Text)
+	}
+	if export.ICSFileName != "bookings-2025-05-12.ics" || strings.Count(export.ICS, "BEGIN:VEVENT") != 2 ||
+		!strings.Contains(export.ICS, "UID:booking-2@space\r\n") || !strings.Contains(export.ICS, "DTSTART:20250512T070000Z\r\n") {
+		t.Errorf("Expected an iCalendar file with two events, got: %s %q", export.ICSFileName, export.ICS)
+	}
+
+	// Администратор получает чужую неделю на языке её владельца; без ics файла нет
+	empty, err := svc.ExportUserWeek(ctx, users.users[30], 200, monday.AddDate(0, 0, 14), false)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if empty.Total != 0 || empty.Text != "*Bookings for the week 26\\.05 – 01\\.06*\n\nNo bookings" || empty.ICS != "" {
+		t.Errorf("Expected an empty week in English without a file, got: %+v", empty)
+	}
+
+	if _, err := svc.ExportUserWeek(ctx, users.users[10], 200, monday, false); !errors.Is(err, ErrNotAuthorized) {
+		t.Errorf("Expected ErrNotAuthorized, got: %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/space/backend/pkg/apperror"
+)
+
+const (
+	// DefaultWidgetRange - период по умолчанию, если сайт не передал start/end
+	DefaultWidgetRange = 7 * 24 * time.Hour
+	// MaxWidgetRange ограничивает выборку одного запроса виджета
+	MaxWidgetRange = 31 * 24 * time.Hour
+)
+
+var ErrWidgetRangeTooLong = apperror.BadRequest("widget_range_too_long", "time range must not exceed 31 days")
+
+// WidgetRoom is a room as shown on the public website
+type WidgetRoom struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	Capacity int    `json:"capacity"`
+}
+
+// WidgetSlot is a busy interval of a room; titles, creators and participants are never exposed
+type WidgetSlot struct {
+	RoomID    uint      `json:"room_id"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
+
+// WidgetCalendar is the availability of all active rooms in a time range
+type WidgetCalendar struct {
+	Start time.Time    `json:"start"`
+	End   time.Time    `json:"end"`
+	Rooms []WidgetRoom `json:"rooms"`
+	Busy  []WidgetSlot `json:"busy"`
+}
+
+// WidgetService serves the embeddable availability widget
+// Отдаёт только то, что можно показать на публичном сайте: комнаты и занятые интервалы
+type WidgetService struct {
+	roomRepo    RoomStore
+	bookingRepo BookingStore
+}
+
+// NewWidgetService creates a new widget service
+func NewWidgetService(roomRepo RoomStore, bookingRepo BookingStore) *WidgetService {
+	return &WidgetService{
+		roomRepo:    roomRepo,
+		bookingRepo: bookingRepo,
+	}
+}
+
+// GetCalendar returns active rooms and their busy intervals overlapping [start, end)
+// Пересекающиеся бронирования одной комнаты объединяются, чтобы по интервалам нельзя было судить о встречах
+func (s *WidgetService) GetCalendar(ctx context.Context, start, end time.Time) (*WidgetCalendar, error) {
+	if !end.After(start) {
+		return nil, ErrInvalidTime
+	}
+	if end.Sub(start) > MaxWidgetRange {
+		return nil, ErrWidgetRangeTooLong
+	}
+
+	rooms, err := s.roomRepo.GetAll(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	bookings, err := s.bookingRepo.GetCalendarSummaries(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &WidgetCalendar{
+		Start: start.UTC(),
+		End:   end.UTC(),
+		Rooms: make([]WidgetRoom, 0, len(rooms)),
+		Busy:  []WidgetSlot{},
+	}
+	active := make(map[uint]bool, len(rooms))
+	for _, room := range rooms {
+		active[room.ID] = true
+		calendar.Rooms = append(calendar.Rooms, WidgetRoom{ID: room.ID, Name: room.Name, Capacity: room.Capacity})
+	}
+
+	// Бронирования упорядочены по началу; последний интервал каждой комнаты расширяется пересекающимися
+	last := make(map[uint]int)
+	for _, booking := range bookings {
+		if !active[booking.RoomID] {
+			continue
+		}
+		if i, ok := last[booking.RoomID]; ok && !booking.StartTime.After(calendar.Busy[i].EndTime) {
+			if booking.EndTime.After(calendar.Busy[i].EndTime) {
+				calendar.Busy[i].EndTime = booking.EndTime.UTC()
+			}
+			continue
+		}
+		last[booking.RoomID] = len(calendar.Busy)
+		calendar.Busy = append(calendar.Busy, WidgetSlot{
+			RoomID:    booking.RoomID,
+			StartTime: booking.StartTime.UTC(),
+			EndTime:   booking.EndTime.UTC(),
+		})
+	}
+	return calendar, nil
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/space/backend/internal/models"
+)
+
+func TestWidgetService_GetCalendar(t *testing.T) {
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	at := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+
+	rooms := &fakeRoomStateRoomStore{&fakeRoomStore{rooms: map[uint]*models.Room{
+		1: {ID: 1, Name: "Big Room", Capacity: 10, IsActive: true},
+		2: {ID: 2, Name: "Archive", IsActive: false},
+	}}}
+	bookings := &fakeRoomStateBookingStore{newFakeBookingStore(
+		models.Booking{ID: 1, RoomID: 1, Title: "Anna's interview", StartTime: at(9, 0), EndTime: at(10, 0), Status: models.BookingStatusConfirmed},
+		models.Booking{ID: 2, RoomID: 1, Title: "Sync", StartTime: at(10, 0), EndTime: at(10, 30), Status: models.BookingStatusConfirmed},
+		models.Booking{ID: 3, RoomID: 1, Title: "Retro", StartTime: at(14, 0), EndTime: at(15, 0), Status: models.BookingStatusConfirmed},
+		models.Booking{ID: 4, RoomID: 2, Title: "Hidden", StartTime: at(9, 0), EndTime: at(10, 0), Status: models.BookingStatusConfirmed},
+	)}
+	svc := NewWidgetService(rooms, bookings)
+
+	calendar, err := svc.GetCalendar(context.Background(), day, day.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(calendar.Rooms) != 1 || calendar.Rooms[0].Name != "Big Room" {
+		t.Errorf("Expected only the active room, got: %+v", calendar.Rooms)
+	}
+	want := []WidgetSlot{
+		{RoomID: 1, StartTime: at(9, 0), EndTime: at(10, 30)},
+		{RoomID: 1, StartTime: at(14, 0), EndTime: at(15, 0)},
+	}
+	if len(calendar.Busy) != len(want) || calendar.Busy[0] != want[0] || calendar.Busy[1] != want[1] {
+		t.Errorf("Expected adjacent bookings to be merged, got: %+v", calendar.Busy)
+	}
+
+	body, _ := json.Marshal(calendar)
+	if strings.Contains(string(body), "Anna") || strings.Contains(string(body), "title") {
+		t.Errorf("Expected no booking details in widget data, got: %s", body)
+	}
+
+	if _, err := svc.GetCalendar(context.Background(), day, day.Add(MaxWidgetRange+time.Hour)); !errors.Is(err, ErrWidgetRangeTooLong) {
+		t.Errorf("Expected ErrWidgetRangeTooLong, got: %v", err)
+	}
+	if _, err := svc.GetCalendar(context.Background(), day, day); !errors.Is(err, ErrInvalidTime) {
+		t.Errorf("Expected ErrInvalidTime, got: %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+
+	"github.com/space/backend/internal/metrics"
+)
+
+var (
+	// ErrQueueFull возвращается, когда очередь заполнена: задача отбрасывается, а не ждёт
+	ErrQueueFull = errors.New("worker pool queue is full")
+	// ErrClosed возвращается после начала остановки пула
+	ErrClosed = errors.New("worker pool is shut down")
+)
+
+// task - задача в очереди пула
+type task struct {
+	name string
+	fn   func(ctx context.Context)
+}
+
+// Pool выполняет фоновые задачи фиксированным числом воркеров через ограниченную очередь
+// Используется для исходящих вызовов (webhook бота, Telegram API), чтобы всплеск
+// бронирований не порождал тысячи горутин
+type Pool struct {
+	name   string
+	logger *slog.Logger
+	tasks  chan task
+
+	// ctx передаётся задачам и отменяется, если Shutdown не дождался их завершения
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// New creates a pool and starts its workers
+func New(name string, workers, queueSize int, logger *slog.Logger) *Pool {
+	if workers <= 0 {
+		workers = 1
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Pool{
+		name:   name,
+		logger: logger,
+		tasks:  make(chan task, queueSize),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.worker()
+	}
+	return p
+}
+
+// Submit enqueues a task without blocking
+// Если очередь заполнена, задача отбрасывается и возвращается ErrQueueFull
+func (p *Pool) Submit(name string, fn func(ctx context.Context)) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return ErrClosed
+	}
+
+	select {
+	case p.tasks <- task{name: name, fn: fn}:
+		return nil
+	default:
+		metrics.WorkerPoolDropped.Add(p.name, 1)
+		return ErrQueueFull
+	}
+}
+
+// Shutdown stops accepting tasks and waits until queued tasks are done
+// Если ctx истекает раньше, контекст задач отменяется и возвращается ошибка ctx
+func (p *Pool) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.tasks)
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		return fmt.Errorf("worker pool %s shutdown: %w", p.name, ctx.Err())
+	}
+}
+
+func (p *Pool) worker() {
+	defer p.wg.Done()
+	for t := range p.tasks {
+		p.run(t)
+	}
+}
+
+// run выполняет задачу; паника логируется и не роняет воркер
+func (p *Pool) run(t task) {
+	defer func() {
+		if r := recover(); r != nil {
+			p.logger.Error("worker pool task panicked", "pool", p.name, "task", t.name, "panic", r, "stack", string(debug.Stack()))
+		}
+	}()
+	t.fn(p.ctx)
+}
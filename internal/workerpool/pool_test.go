@@ -0,0 +1,111 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestPool(workers, queueSize int) *Pool {
+	return New("test", workers, queueSize, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestPoolBoundsConcurrencyAndDrains(t *testing.T) {
+	p := newTestPool(2, 10)
+
+	var running, maxRunning, done atomic.Int32
+	for i := 0; i < 10; i++ {
+		err := p.Submit("task", func(ctx context.Context) {
+			n := running.Add(1)
+			for {
+				m := maxRunning.Load()
+				if n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+			done.Add(1)
+		})
+		if err != nil {
+			t.Fatalf("Expected task to be queued, got: %v", err)
+		}
+	}
+
+	if err := p.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Expected clean shutdown, got: %v", err)
+	}
+	if done.Load() != 10 {
+		t.Errorf("Expected all 10 queued tasks to run, got: %d", done.Load())
+	}
+	if maxRunning.Load() > 2 {
+		t.Errorf("Expected at most 2 concurrent tasks, got: %d", maxRunning.Load())
+	}
+	if err := p.Submit("late", func(ctx context.Context) {}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed after shutdown, got: %v", err)
+	}
+}
+
+func TestPoolRejectsWhenQueueFull(t *testing.T) {
+	p := newTestPool(1, 1)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	p.Submit("blocking", func(ctx context.Context) {
+		close(started)
+		<-release
+	})
+	<-started
+
+	if err := p.Submit("queued", func(ctx context.Context) {}); err != nil {
+		t.Fatalf("Expected task to fit in the queue, got: %v", err)
+	}
+	if err := p.Submit("overflow", func(ctx context.Context) {}); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Expected ErrQueueFull, got: %v", err)
+	}
+
+	close(release)
+	p.Shutdown(context.Background())
+}
+
+func TestPoolShutdownTimeoutCancelsTasks(t *testing.T) {
+	p := newTestPool(1, 1)
+
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	p.Submit("slow", func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		close(cancelled)
+	})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := p.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got: %v", err)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("Expected task context to be cancelled")
+	}
+}
+
+func TestPoolRecoversFromPanic(t *testing.T) {
+	p := newTestPool(1, 2)
+
+	var ran atomic.Bool
+	p.Submit("panicking", func(ctx context.Context) { panic("boom") })
+	p.Submit("next", func(ctx context.Context) { ran.Store(true) })
+
+	p.Shutdown(context.Background())
+	if !ran.Load() {
+		t.Error("Expected worker to survive a panicking task")
+	}
+}
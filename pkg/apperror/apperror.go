@@ -0,0 +1,86 @@
+// Package apperror describes domain errors with the HTTP status and code they are reported with
+package apperror
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Error is a domain error: a stable code for clients, the HTTP status and an English message
+// Message - ключ перевода i18n, поэтому меняется только вместе с переводами
+type Error struct {
+	Status  int
+	Code    string // snake_case, не меняется вместе с текстом сообщения
+	Message string
+
+	// Дополнительные данные ошибки (например, конфликтующие бронирования):
+	// в problem+json - член DetailsName, в прежнем формате ответа - поле data
+	DetailsName string
+	Details     interface{}
+
+	err error // Исходная ошибка, только для логов и errors.Is/As
+}
+
+// New creates an error; domain errors are declared once as package-level variables
+func New(status int, code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+// BadRequest creates a 400 error
+func BadRequest(code, message string) *Error {
+	return New(http.StatusBadRequest, code, message)
+}
+
+// Forbidden creates a 403 error
+func Forbidden(code, message string) *Error {
+	return New(http.StatusForbidden, code, message)
+}
+
+// NotFound creates a 404 error
+func NotFound(code, message string) *Error {
+	return New(http.StatusNotFound, code, message)
+}
+
+// Conflict creates a 409 error
+func Conflict(code, message string) *Error {
+	return New(http.StatusConflict, code, message)
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the wrapped cause
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Is matches errors by code, so copies made by WithDetails and Wrap still match their variable
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WithDetails returns a copy of the error carrying data for the client
+func (e *Error) WithDetails(name string, data interface{}) *Error {
+	c := *e
+	c.DetailsName = name
+	c.Details = data
+	return &c
+}
+
+// Wrap returns a copy of the error with the cause it was reported for
+func (e *Error) Wrap(err error) *Error {
+	c := *e
+	c.err = err
+	return &c
+}
+
+// As finds a domain error in the chain of err
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
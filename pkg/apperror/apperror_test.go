@@ -0,0 +1,43 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+var errConflict = Conflict("booking_conflict", "room is already booked")
+
+func TestIs_CopiesMatchVariable(t *testing.T) {
+	withDetails := errConflict.WithDetails("conflicting_bookings", []int{7})
+	if !errors.Is(withDetails, errConflict) {
+		t.Error("Expected a copy with details to match its variable")
+	}
+	wrapped := fmt.Errorf("create booking: %w", errConflict.Wrap(errors.New("db")))
+	if !errors.Is(wrapped, errConflict) {
+		t.Error("Expected a wrapped copy to match its variable")
+	}
+	if errors.Is(withDetails, NotFound("room_not_found", "room not found")) {
+		t.Error("Expected errors with different codes not to match")
+	}
+	if errConflict.DetailsName != "" || errConflict.Details != nil {
+		t.Errorf("Expected WithDetails to leave the variable unchanged, got: %+v", errConflict)
+	}
+}
+
+func TestAs_FindsErrorInChain(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := fmt.Errorf("import: %w", New(http.StatusBadGateway, "provider_failed", "provider failed").Wrap(cause))
+
+	appErr, ok := As(err)
+	if !ok || appErr.Status != http.StatusBadGateway || appErr.Code != "provider_failed" {
+		t.Fatalf("Expected provider_failed 502, got: %+v %v", appErr, ok)
+	}
+	if !errors.Is(err, cause) {
+		t.Error("Expected the cause to be reachable through Unwrap")
+	}
+	if _, ok := As(cause); ok {
+		t.Error("Expected no domain error in a plain error")
+	}
+}
@@ -0,0 +1,93 @@
+// Package captcha verifies captcha tokens with a siteverify API
+// (Cloudflare Turnstile, hCaptcha or Google reCAPTCHA - протокол у них общий)
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrInvalidToken is returned when the provider rejects a token (expired, reused or solved by a bot)
+var ErrInvalidToken = errors.New("captcha verification failed")
+
+// verifyURLs - адреса проверки токенов у поддерживаемых провайдеров
+var verifyURLs = map[string]string{
+	"turnstile": "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+	"hcaptcha":  "https://api.hcaptcha.com/siteverify",
+	"recaptcha": "https://www.google.com/recaptcha/api/siteverify",
+}
+
+// VerifyURL returns the siteverify URL of a provider: turnstile, hcaptcha or recaptcha
+func VerifyURL(provider string) (string, bool) {
+	u, ok := verifyURLs[provider]
+	return u, ok
+}
+
+// Verifier checks tokens solved by the client against the provider
+type Verifier struct {
+	verifyURL  string
+	secret     string
+	httpClient *http.Client
+}
+
+// NewVerifier creates a verifier; verifyURL is the provider's siteverify endpoint
+func NewVerifier(verifyURL, secret string, timeout time.Duration) *Verifier {
+	return &Verifier{
+		verifyURL:  verifyURL,
+		secret:     secret,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Verify checks a token; remoteIP is optional and lets the provider match it to the client that solved the captcha
+func (v *Verifier) Verify(ctx context.Context, token, remoteIP string) error {
+	if strings.TrimSpace(token) == "" {
+		return ErrInvalidToken
+	}
+
+	form := url.Values{"secret": {v.secret}, "response": {token}}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("captcha verify: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return fmt.Errorf("captcha verify returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Success    bool     `json:"success"`
+		ErrorCodes []string `json:"error-codes"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&result); err != nil {
+		return fmt.Errorf("captcha verify: %w", err)
+	}
+	if !result.Success {
+		// Неверный секрет - ошибка настройки сервера, а не клиента
+		for _, code := range result.ErrorCodes {
+			if code == "invalid-input-secret" || code == "missing-input-secret" {
+				return fmt.Errorf("captcha verify: %s", code)
+			}
+		}
+		return ErrInvalidToken
+	}
+	return nil
+}
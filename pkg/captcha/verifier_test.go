@@ -0,0 +1,57 @@
+package captcha
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestVerifier_Verify(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.PostForm.Get("secret") != "secret":
+			_, _ = w.Write([]byte(`{"success":false,"error-codes":["invalid-input-secret"]}`))
+		case r.PostForm.Get("response") == "solved" && r.PostForm.Get("remoteip") == "203.0.113.7":
+			_, _ = w.Write([]byte(`{"success":true}`))
+		default:
+			_, _ = w.Write([]byte(`{"success":false,"error-codes":["invalid-input-response"]}`))
+		}
+	}))
+	defer server.Close()
+
+	verifier := NewVerifier(server.URL, "secret", time.Second)
+	ctx := context.Background()
+
+	if err := verifier.Verify(ctx, "solved", "203.0.113.7"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := verifier.Verify(ctx, "forged", "203.0.113.7"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken, got: %v", err)
+	}
+	if err := verifier.Verify(ctx, "", ""); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for an empty token, got: %v", err)
+	}
+
+	// Неверный секрет - ошибка сервера, а не отклонённый токен
+	misconfigured := NewVerifier(server.URL, "wrong", time.Second)
+	if err := misconfigured.Verify(ctx, "solved", ""); err == nil || errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected a configuration error, got: %v", err)
+	}
+}
+
+func TestVerifyURL(t *testing.T) {
+	if u, ok := VerifyURL("turnstile"); !ok || u == "" {
+		t.Errorf("Expected the Turnstile URL, got: %q", u)
+	}
+	if _, ok := VerifyURL("unknown"); ok {
+		t.Error("Expected an unknown provider to be rejected")
+	}
+}
@@ -0,0 +1,167 @@
+// Package contentfilter flags profanity and spam in user-written texts
+package contentfilter
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// Rule определяет правило, по которому текст отмечен
+type Rule string
+
+const (
+	RuleWordlist Rule = "wordlist"       // Слово из списка запрещённых
+	RuleEmoji    Rule = "emoji"          // Эмодзи больше MaxEmoji
+	RuleRepeat   Rule = "repeated_chars" // Один символ подряд больше MaxRepeat раз ("!!!!!!!!!!!!")
+	RuleLongWord Rule = "long_word"      // Слово без пробелов длиннее MaxWordLength (ссылки, мусор)
+)
+
+// maxMatchLength ограничивает фрагмент текста, сохраняемый в нарушении
+const maxMatchLength = 40
+
+// Config configures the filter; a zero limit disables its heuristic
+type Config struct {
+	Words         []string // Без учёта регистра, ё = е; "слово*" - все слова с этим началом
+	MaxEmoji      int
+	MaxRepeat     int
+	MaxWordLength int
+}
+
+// Violation is a rule a text broke and the offending fragment
+type Violation struct {
+	Rule  Rule   `json:"rule"`
+	Match string `json:"match,omitempty"`
+}
+
+// Filter checks texts against a wordlist and spam heuristics; safe for concurrent use
+type Filter struct {
+	words    map[string]bool
+	prefixes []string
+	cfg      Config
+}
+
+// New creates a filter
+func New(cfg Config) *Filter {
+	f := &Filter{words: make(map[string]bool), cfg: cfg}
+	for _, word := range cfg.Words {
+		word = normalize(strings.TrimSpace(word))
+		if prefix, ok := strings.CutSuffix(word, "*"); ok {
+			if prefix != "" {
+				f.prefixes = append(f.prefixes, prefix)
+			}
+		} else if word != "" {
+			f.words[word] = true
+		}
+	}
+	return f
+}
+
+// LoadWords reads a wordlist file: one word per line, empty lines and lines starting with # are skipped
+func LoadWords(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	return words, scanner.Err()
+}
+
+// Check returns the rules the text breaks, at most one violation per rule
+func (f *Filter) Check(text string) []Violation {
+	var violations []Violation
+	if match := f.matchWord(text); match != "" {
+		violations = append(violations, Violation{Rule: RuleWordlist, Match: match})
+	}
+	if f.cfg.MaxEmoji > 0 {
+		if count := countEmoji(text); count > f.cfg.MaxEmoji {
+			violations = append(violations, Violation{Rule: RuleEmoji})
+		}
+	}
+	if f.cfg.MaxRepeat > 0 {
+		if run := longestRun(text); len([]rune(run)) > f.cfg.MaxRepeat {
+			violations = append(violations, Violation{Rule: RuleRepeat, Match: truncate(run)})
+		}
+	}
+	if f.cfg.MaxWordLength > 0 {
+		for _, word := range strings.Fields(text) {
+			if len([]rune(word)) > f.cfg.MaxWordLength {
+				violations = append(violations, Violation{Rule: RuleLongWord, Match: truncate(word)})
+				break
+			}
+		}
+	}
+	return violations
+}
+
+// matchWord возвращает первое слово текста из списка или с запрещённым началом
+func (f *Filter) matchWord(text string) string {
+	if len(f.words) == 0 && len(f.prefixes) == 0 {
+		return ""
+	}
+	words := strings.FieldsFunc(normalize(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if f.words[word] {
+			return word
+		}
+		for _, prefix := range f.prefixes {
+			if strings.HasPrefix(word, prefix) {
+				return word
+			}
+		}
+	}
+	return ""
+}
+
+// normalize приводит текст к нижнему регистру и заменяет ё на е
+func normalize(s string) string {
+	return strings.ReplaceAll(strings.ToLower(s), "ё", "е")
+}
+
+// countEmoji считает пиктограммы; модификаторы, селекторы вариантов и ZWJ не входят в категорию So
+func countEmoji(text string) int {
+	count := 0
+	for _, r := range text {
+		if unicode.Is(unicode.So, r) {
+			count++
+		}
+	}
+	return count
+}
+
+// longestRun находит самую длинную серию одного символа без учёта регистра; пробелы не считаются
+func longestRun(text string) string {
+	runes := []rune(text)
+	best, bestLen := "", 0
+	for i := 0; i < len(runes); {
+		j := i + 1
+		for j < len(runes) && unicode.ToLower(runes[j]) == unicode.ToLower(runes[i]) {
+			j++
+		}
+		if j-i > bestLen && !unicode.IsSpace(runes[i]) {
+			best, bestLen = string(runes[i:j]), j-i
+		}
+		i = j
+	}
+	return best
+}
+
+func truncate(s string) string {
+	if runes := []rune(s); len(runes) > maxMatchLength {
+		return string(runes[:maxMatchLength]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,50 @@
+package contentfilter
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFilter_Check(t *testing.T) {
+	filter := New(Config{Words: []string{"Spam", "ёлк*", " "}, MaxEmoji: 3, MaxRepeat: 5, MaxWordLength: 30})
+
+	tests := []struct {
+		name string
+		text string
+		want []Violation
+	}{
+		{name: "clean", text: "Планёрка команды 🚀", want: nil},
+		{name: "word in another case", text: "Free SPAM here", want: []Violation{{Rule: RuleWordlist, Match: "spam"}}},
+		{name: "prefix with ё", text: "Ёлки-палки", want: []Violation{{Rule: RuleWordlist, Match: "елки"}}},
+		{name: "word inside another word", text: "Spammer sync", want: nil},
+		{name: "emoji", text: "🔥🔥 Party 🎉🎉", want: []Violation{{Rule: RuleEmoji}}},
+		{name: "repeated chars", text: "Sale!!!!!!", want: []Violation{{Rule: RuleRepeat, Match: "!!!!!!"}}},
+		{name: "spaces are not a run", text: "a      b", want: nil},
+		{name: "long word", text: "see https://example.com/very/long/link/to/nowhere", want: []Violation{{Rule: RuleLongWord, Match: "https://example.com/very/long/link/to/no…"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filter.Check(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Check(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+
+	// Нулевые пороги выключают эвристики
+	if got := New(Config{}).Check("🔥🔥🔥🔥 !!!!!!!!!!!!"); got != nil {
+		t.Errorf("Expected an empty filter to pass everything, got: %+v", got)
+	}
+}
+
+func TestLoadWords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(path, []byte("# Список\nspam\n\n  scam*  \n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	words, err := LoadWords(path)
+	if err != nil || !reflect.DeepEqual(words, []string{"spam", "scam*"}) {
+		t.Errorf("Expected two words, got: %q (%v)", words, err)
+	}
+}
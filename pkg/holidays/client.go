@@ -0,0 +1,72 @@
+// Package holidays fetches public holidays from the Nager.Date API (https://date.nager.at)
+package holidays
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnknownCountry is returned for a country code the API does not know
+var ErrUnknownCountry = errors.New("unknown country code")
+
+// Holiday is a public holiday as returned by the API
+type Holiday struct {
+	Date      string `json:"date"` // YYYY-MM-DD
+	LocalName string `json:"localName"`
+	Name      string `json:"name"`
+	Global    bool   `json:"global"` // false - праздник только в части регионов страны
+}
+
+// Client is a Nager.Date API client
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a client; baseURL is the API root, e.g. https://date.nager.at
+func NewClient(baseURL string, timeout time.Duration) *Client {
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// PublicHolidays returns public holidays of a country (ISO 3166-1 alpha-2 code) in a year
+func (c *Client) PublicHolidays(ctx context.Context, year int, countryCode string) ([]Holiday, error) {
+	endpoint := c.baseURL + "/api/v3/PublicHolidays/" + strconv.Itoa(year) + "/" + url.PathEscape(strings.ToUpper(countryCode))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, ErrUnknownCountry
+	case http.StatusNoContent:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("holiday API returned status %d", resp.StatusCode)
+	}
+
+	var holidays []Holiday
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&holidays); err != nil {
+		return nil, fmt.Errorf("holiday API: %w", err)
+	}
+	return holidays, nil
+}
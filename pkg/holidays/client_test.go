@@ -0,0 +1,46 @@
+package holidays
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_PublicHolidays(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/PublicHolidays/2025/RU":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[
+				{"date":"2025-01-01","localName":"Новый год","name":"New Year's Day","countryCode":"RU","global":true},
+				{"date":"2025-03-08","localName":"Международный женский день","name":"International Women's Day","countryCode":"RU","global":true}
+			]`))
+		case "/api/v3/PublicHolidays/2025/XX":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL+"/", time.Second)
+	ctx := context.Background()
+
+	holidays, err := client.PublicHolidays(ctx, 2025, "ru")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(holidays) != 2 || holidays[0].Date != "2025-01-01" || holidays[0].LocalName != "Новый год" || !holidays[0].Global {
+		t.Errorf("Unexpected holidays: %+v", holidays)
+	}
+
+	if _, err := client.PublicHolidays(ctx, 2025, "XX"); !errors.Is(err, ErrUnknownCountry) {
+		t.Errorf("Expected ErrUnknownCountry, got: %v", err)
+	}
+	if _, err := client.PublicHolidays(ctx, 2024, "RU"); err == nil {
+		t.Error("Expected an error for a failed request")
+	}
+}
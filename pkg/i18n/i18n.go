@@ -0,0 +1,115 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Поддерживаемые языки
+const (
+	EN = "en"
+	RU = "ru"
+
+	// Default - язык по умолчанию, если клиент не передал поддерживаемый
+	Default = EN
+)
+
+// ContextKey - ключ gin-контекста, под которым хранится язык запроса
+const ContextKey = "locale"
+
+// catalogs - переводы сообщений по языкам
+// Ключом служит исходное (английское) сообщение ошибки или идентификатор шаблона
+var catalogs = map[string]map[string]string{
+	EN: messagesEN,
+	RU: messagesRU,
+}
+
+// IsSupported проверяет, есть ли каталог для языка
+func IsSupported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Normalize приводит языковой тег к базовому коду: "ru-RU" -> "ru"
+// Возвращает пустую строку, если язык не поддерживается
+func Normalize(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	if !IsSupported(tag) {
+		return ""
+	}
+	return tag
+}
+
+// ParseAcceptLanguage выбирает наиболее предпочтительный поддерживаемый язык
+// из заголовка Accept-Language с учётом q-весов
+// Возвращает пустую строку, если ни один язык не поддерживается
+func ParseAcceptLanguage(header string) string {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		lang := Normalize(fields[0])
+		if lang == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if v, ok := strings.CutPrefix(param, "q="); ok {
+				parsed, err := strconv.ParseFloat(v, 64)
+				if err != nil {
+					parsed = 0
+				}
+				q = parsed
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		candidates = append(candidates, candidate{lang: lang, q: q})
+	}
+
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	// Стабильная сортировка сохраняет порядок языков с одинаковым весом
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+	return candidates[0].lang
+}
+
+// T переводит сообщение на указанный язык
+// Если перевода нет, возвращается исходное сообщение
+func T(lang, message string) string {
+	if catalog, ok := catalogs[lang]; ok {
+		if translated, ok := catalog[message]; ok {
+			return translated
+		}
+	}
+	return message
+}
+
+// Tf переводит шаблон и подставляет аргументы
+// Шаблон ищется в каталоге языка, затем в каталоге по умолчанию
+func Tf(lang, key string, args ...interface{}) string {
+	format, ok := catalogs[lang][key]
+	if !ok {
+		format, ok = catalogs[Default][key]
+	}
+	if !ok {
+		format = key
+	}
+	return fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,47 @@
+package i18n
+
+import "testing"
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"ru-RU,ru;q=0.9,en-US;q=0.8,en;q=0.7", RU},
+		{"en-US,en;q=0.9", EN},
+		{"de-DE,de;q=0.9,ru;q=0.5", RU},
+		{"en;q=0.5,ru;q=0.8", RU},
+		{"ru;q=0", ""},
+		{"fr", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := ParseAcceptLanguage(tt.header); got != tt.expected {
+			t.Errorf("ParseAcceptLanguage(%q): expected %q, got: %q", tt.header, tt.expected, got)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	if got := T(RU, "room not found"); got != "комната не найдена" {
+		t.Errorf("Expected russian translation, got: %s", got)
+	}
+
+	// Без перевода возвращается исходное сообщение
+	if got := T(RU, "some unknown error"); got != "some unknown error" {
+		t.Errorf("Expected original message, got: %s", got)
+	}
+	if got := T(EN, "room not found"); got != "room not found" {
+		t.Errorf("Expected original message, got: %s", got)
+	}
+}
+
+func TestTf(t *testing.T) {
+	if got := Tf(RU, "validation.required", "title"); got != "поле 'title' обязательно" {
+		t.Errorf("Expected localized template, got: %s", got)
+	}
+	if got := Tf("fr", "validation.required", "title"); got != "field 'title' is required" {
+		t.Errorf("Expected default template, got: %s", got)
+	}
+}
@@ -0,0 +1,14 @@
+package i18n
+
+// messagesEN содержит только шаблоны: английские сообщения ошибок
+// являются ключами каталога и отдаются как есть
+var messagesEN = map[string]string{
+	"validation.required":  "field '%s' is required",
+	"validation.min":       "field '%s' must be at least %s",
+	"validation.max":       "field '%s' must be at most %s",
+	"validation.maxlength": "field '%s' must be at most %s characters",
+	"validation.oneof":     "field '%s' must be one of: %s",
+	"validation.email":     "field '%s' must be a valid email",
+	"validation.type":      "field '%s' must be a %s",
+	"validation.invalid":   "field '%s' is invalid",
+}
@@ -0,0 +1,96 @@
+package i18n
+
+var messagesRU = map[string]string{
+	// Шаблоны валидации
+	"validation.required":  "поле '%s' обязательно",
+	"validation.min":       "поле '%s' должно быть не меньше %s",
+	"validation.max":       "поле '%s' должно быть не больше %s",
+	"validation.maxlength": "поле '%s' должно быть не длиннее %s символов",
+	"validation.oneof":     "поле '%s' должно быть одним из: %s",
+	"validation.email":     "поле '%s' должно содержать корректный email",
+	"validation.type":      "поле '%s' должно иметь тип %s",
+	"validation.invalid":   "поле '%s' заполнено некорректно",
+
+	// Аутентификация и доступ
+	"missing authorization header":                                "отсутствует заголовок авторизации",
+	"invalid authorization header":                                "некорректный заголовок авторизации",
+	"admin privileges required":                                   "требуются права администратора",
+	"invalid bot API token":                                       "неверный токен бота",
+	"missing X-Bot-Token header":                                  "отсутствует заголовок X-Bot-Token",
+	"missing X-Telegram-User-ID header":                           "отсутствует заголовок X-Telegram-User-ID",
+	"invalid X-Telegram-User-ID format":                           "некорректный формат X-Telegram-User-ID",
+	"missing X-API-Key header":                                    "отсутствует заголовок X-API-Key",
+	"API key does not have the required scope":                    "у API-ключа нет необходимых прав",
+	"invalid API key":                                             "неверный API-ключ",
+	"API key expired":                                             "срок действия API-ключа истёк",
+	"invalid API key scope":                                       "недопустимая область действия API-ключа",
+	"expires_at must be in the future":                            "expires_at должен быть в будущем",
+	"unknown auth type":                                           "неизвестный тип авторизации",
+	"user not authenticated":                                      "пользователь не аутентифицирован",
+	"access denied. You must be a member of the authorized group": "доступ запрещён. Вы должны быть участником авторизованной группы",
+	"user is not a member of the authorized group":                "пользователь не состоит в авторизованной группе",
+	"failed to verify membership":                                 "не удалось проверить членство в группе",
+	"invalid hash":                                                "неверная подпись",
+	"missing hash parameter":                                      "отсутствует параметр hash",
+	"missing user data":                                           "отсутствуют данные пользователя",
+	"auth_date expired":                                           "срок действия авторизации истёк",
+	"invalid auth_date":                                           "некорректный auth_date",
+	"initData is empty":                                           "initData не передан",
+	"too many requests":                                           "слишком много запросов",
+	"Rate limit exceeded. Please try again later.":                "Превышен лимит запросов. Повторите попытку позже.",
+	"request timed out":                                           "превышено время обработки запроса",
+
+	// Пользователи
+	"telegram user data not found in request context": "данные пользователя Telegram не найдены",
+	"cannot sync different user's data":               "нельзя синхронизировать данные другого пользователя",
+	"invalid user ID":                                 "некорректный ID пользователя",
+	"you don't have permission to edit this user":     "у вас нет прав на редактирование этого пользователя",
+	"cannot revoke your own admin role":               "нельзя снять роль администратора с самого себя",
+	"invalid role":                                    "недопустимая роль",
+
+	// Комнаты и бронирования
+	"booking conflict: room is already booked for this time":                  "конфликт бронирования: комната уже занята на это время",
+	"invalid time: end time must be after start time":                         "некорректное время: окончание должно быть позже начала",
+	"cannot create booking in the past":                                       "нельзя создать бронирование в прошлом",
+	"room not found":                                                          "комната не найдена",
+	"room is not active":                                                      "комната неактивна",
+	"record not found":                                                        "запись не найдена",
+	"not authorized to perform this action":                                   "недостаточно прав для выполнения действия",
+	"this booking is not joinable":                                            "к этому бронированию нельзя присоединиться",
+	"cannot join cancelled or completed booking":                              "нельзя присоединиться к отменённому или завершённому бронированию",
+	"creator cannot leave booking, use cancel instead":                        "создатель не может покинуть бронирование, используйте отмену",
+	"import file contains invalid rows, nothing was imported":                 "файл импорта содержит ошибки, ничего не импортировано",
+	"active booking limit reached":                                            "достигнут лимит активных бронирований",
+	"room has no booking in progress":                                         "в комнате сейчас нет бронирования",
+	"the space is closed on this day":                                         "в этот день пространство не работает",
+	"a closure day with this date already exists":                             "нерабочий день с этой датой уже есть",
+	"rating must be between 1 and 5":                                          "оценка должна быть от 1 до 5",
+	"feedback can be left after the booking ends":                             "отзыв можно оставить после окончания бронирования",
+	"cannot leave feedback for a cancelled booking":                           "нельзя оставить отзыв об отменённом бронировании",
+	"only participants of the booking can leave feedback":                     "оставить отзыв могут только участники бронирования",
+	"you have no booking in this room right now":                              "у вас сейчас нет бронирования в этой комнате",
+	"cleaning task is already done":                                           "задача уборки уже выполнена",
+	"reset_after_hours must not be negative":                                  "reset_after_hours не может быть отрицательным",
+	"timezone must be an IANA timezone such as Europe/Moscow":                 "часовой пояс должен быть в формате IANA, например Europe/Moscow",
+	"kind must be room or parking":                                            "вид должен быть room или parking",
+	"not a parking spot":                                                      "это не парковочное место",
+	"license plate is required for a parking booking":                         "для бронирования парковки нужен номер автомобиля",
+	"license plate must contain only letters and digits, up to 20 characters": "номер автомобиля может содержать только буквы и цифры, до 20 символов",
+	"parking booking must start and end on the same day":                      "бронирование парковки должно начинаться и заканчиваться в один день",
+	"booking must not cross midnight or exceed the maximum duration":          "бронирование не может переходить через полночь или превышать максимальную длительность",
+	"multi-day bookings require an administrator with allow_multi_day":        "многодневное бронирование может создать только администратор с allow_multi_day",
+	"booking crosses midnight or exceeds the maximum duration":                "бронирование переходит через полночь или превышает максимальную длительность",
+	"only one parking booking per day is allowed":                             "парковку можно бронировать один раз в день",
+	"floor not found":                                                         "этаж не найден",
+	"floor plan image is not uploaded":                                        "план этажа не загружен",
+	"floor plan must be a PNG, JPEG or WebP image":                            "план этажа должен быть изображением PNG, JPEG или WebP",
+	"map_x and map_y must be between 0 and 1":                                 "map_x и map_y должны быть от 0 до 1",
+	"duration must be between 15 and 720 minutes":                             "длительность должна быть от 15 до 720 минут",
+	"equipment not found":                                                     "оборудование не найдено",
+
+	// Валидация полей
+	"request body is not valid JSON":             "тело запроса не является корректным JSON",
+	"search query must be at least 2 characters": "поисковый запрос должен содержать минимум 2 символа",
+	"username is too long (max 32 characters)":   "username слишком длинный (максимум 32 символа)",
+	"username contains invalid characters":       "username содержит недопустимые символы",
+}
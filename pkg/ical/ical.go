@@ -0,0 +1,84 @@
+// Package ical renders events as iCalendar (RFC 5545) files that calendar apps import
+package ical
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxLineOctets - длина строки iCalendar без CRLF, после которой строка переносится
+const maxLineOctets = 75
+
+const utcLayout = "20060102T150405Z"
+
+// Event is a calendar event
+type Event struct {
+	UID         string // Постоянный идентификатор: повторный импорт обновляет событие, а не дублирует
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Location    string
+	Description string
+}
+
+// Calendar renders events as a VCALENDAR; times are written in UTC, stamped with now
+func Calendar(prodID string, events []Event, now time.Time) string {
+	var b strings.Builder
+	write := func(line string) {
+		b.WriteString(fold(line))
+		b.WriteString("\r\n")
+	}
+
+	write("BEGIN:VCALENDAR")
+	write("VERSION:2.0")
+	write("PRODID:" + prodID)
+	write("CALSCALE:GREGORIAN")
+	write("METHOD:PUBLISH")
+	stamp := now.UTC().Format(utcLayout)
+	for _, event := range events {
+		write("BEGIN:VEVENT")
+		write("UID:" + event.UID)
+		write("DTSTAMP:" + stamp)
+		write("DTSTART:" + event.Start.UTC().Format(utcLayout))
+		write("DTEND:" + event.End.UTC().Format(utcLayout))
+		write("SUMMARY:" + escapeText(event.Summary))
+		if event.Location != "" {
+			write("LOCATION:" + escapeText(event.Location))
+		}
+		if event.Description != "" {
+			write("DESCRIPTION:" + escapeText(event.Description))
+		}
+		write("END:VEVENT")
+	}
+	write("END:VCALENDAR")
+	return b.String()
+}
+
+// escapeText экранирует значение типа TEXT: обратную косую черту, запятую, точку с запятой и переводы строк
+func escapeText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`, "\r", `\n`).Replace(s)
+}
+
+// fold переносит строку длиннее 75 октетов: продолжение начинается с пробела,
+// многобайтовые символы UTF-8 не разрываются
+func fold(line string) string {
+	if len(line) <= maxLineOctets {
+		return line
+	}
+	var b strings.Builder
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = maxLineOctets - 1 // Пробел в начале продолжения входит в длину строки
+	}
+	b.WriteString(line)
+	return b.String()
+}
@@ -0,0 +1,60 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalendar(t *testing.T) {
+	msk := time.FixedZone("MSK", 3*3600)
+	start := time.Date(2026, 10, 12, 10, 0, 0, 0, msk)
+	events := []Event{{
+		UID:         "booking-7@space",
+		Start:       start,
+		End:         start.Add(90 * time.Minute),
+		Summary:     "Sync; planning, Q4",
+		Location:    "Room 1",
+		Description: "Line one\nC:\\share",
+	}}
+
+	got := Calendar("-//Space//Bookings//EN", events, time.Date(2026, 10, 10, 8, 0, 0, 0, time.UTC))
+	for _, line := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"UID:booking-7@space\r\n",
+		"DTSTAMP:20261010T080000Z\r\n",
+		"DTSTART:20261012T070000Z\r\nDTEND:20261012T083000Z\r\n",
+		`SUMMARY:Sync\; planning\, Q4` + "\r\n",
+		`DESCRIPTION:Line one\nC:\\share` + "\r\n",
+	} {
+		if !strings.Contains(got, line) {
+			t.Errorf("Expected %q in:\n%s", line, got)
+		}
+	}
+	if !strings.HasSuffix(got, "END:VEVENT\r\nEND:VCALENDAR\r\n") {
+		t.Errorf("Expected the calendar to be closed, got:\n%s", got)
+	}
+}
+
+func TestFold(t *testing.T) {
+	line := "SUMMARY:" + strings.Repeat("ж", 80)
+	folded := fold(line)
+	parts := strings.Split(folded, "\r\n")
+	if len(parts) != 3 {
+		t.Fatalf("Expected 3 lines, got: %q", parts)
+	}
+	for i, part := range parts {
+		if len(part) > maxLineOctets {
+			t.Errorf("Line %d is %d octets long", i, len(part))
+		}
+		if i > 0 && !strings.HasPrefix(part, " ") {
+			t.Errorf("Expected line %d to start with a space, got: %q", i, part)
+		}
+	}
+	if unfolded := strings.ReplaceAll(folded, "\r\n ", ""); unfolded != line {
+		t.Errorf("Expected unfolding to restore the line, got: %q", unfolded)
+	}
+	if short := "UID:1"; fold(short) != short {
+		t.Errorf("Expected a short line to stay as is, got: %q", fold(short))
+	}
+}
@@ -0,0 +1,59 @@
+package mail
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildMessage(t *testing.T) {
+	date := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	body := strings.Repeat("Заявка на аренду одобрена. ", 5)
+	msg, err := buildMessage("Space <noreply@space.example.com>", "guest@example.com", "Заявка одобрена", body, date)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	headers, encoded, ok := strings.Cut(string(msg), "\r\n\r\n")
+	if !ok {
+		t.Fatalf("Expected headers and body, got: %q", msg)
+	}
+	for _, want := range []string{
+		"From: Space <noreply@space.example.com>",
+		"To: guest@example.com",
+		"Subject: =?utf-8?q?",
+		"Date: Sat, 01 Mar 2025 09:00:00 +0000",
+		"Content-Type: text/plain; charset=utf-8",
+	} {
+		if !strings.Contains(headers, want) {
+			t.Errorf("Expected header %q in %q", want, headers)
+		}
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(encoded), "\r\n") {
+		if len(line) > 76 {
+			t.Errorf("Expected body lines of at most 76 characters, got %d", len(line))
+		}
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(strings.TrimSpace(encoded), "\r\n", ""))
+	if err != nil || string(decoded) != body {
+		t.Errorf("Expected the body to round-trip, got: %q (%v)", decoded, err)
+	}
+
+	if _, err := buildMessage("noreply@space.example.com", "guest@example.com\r\nBcc: victim@example.com", "Hi", "", date); !errors.Is(err, ErrInvalidHeader) {
+		t.Errorf("Expected ErrInvalidHeader, got: %v", err)
+	}
+}
+
+func TestEnvelopeAddress(t *testing.T) {
+	for input, want := range map[string]string{
+		"Space <noreply@space.example.com>": "noreply@space.example.com",
+		" guest@example.com ":               "guest@example.com",
+	} {
+		if got, err := envelopeAddress(input); err != nil || got != want {
+			t.Errorf("envelopeAddress(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,321 @@
+// Package mqtt is a minimal MQTT 3.1.1 client that only publishes (QoS 0)
+// Серверу нужны только retained-сообщения о состоянии комнат, поэтому подписки и QoS 1/2 не реализованы
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// keepAlive - интервал keep alive, о котором клиент сообщает брокеру; PINGREQ отправляется вдвое чаще
+const keepAlive = 60 * time.Second
+
+// Типы пакетов MQTT (старшие 4 бита первого байта)
+const (
+	packetConnect    byte = 0x10
+	packetConnack    byte = 0x20
+	packetPublish    byte = 0x30
+	packetPingreq    byte = 0xC0
+	packetDisconnect byte = 0xE0
+)
+
+var (
+	ErrConnectionRefused = errors.New("mqtt: connection refused by broker")
+	ErrInvalidTopic      = errors.New("mqtt: invalid topic")
+)
+
+// Config describes the broker connection
+type Config struct {
+	URL      string // mqtt://host:1883 или mqtts://host:8883 (TLS)
+	ClientID string
+	Username string
+	Password string
+	Timeout  time.Duration // Таймаут подключения и записи
+}
+
+// Client publishes messages to an MQTT broker
+// Подключается при первой публикации и переподключается после обрыва; безопасен для конкурентного использования
+type Client struct {
+	address string
+	useTLS  bool
+	host    string
+	cfg     Config
+
+	mu   sync.Mutex
+	conn *connection
+}
+
+// connection - одно подключение к брокеру; done закрывается при его закрытии
+type connection struct {
+	net.Conn
+	done chan struct{}
+	once sync.Once
+}
+
+func (c *connection) close() {
+	c.once.Do(func() {
+		close(c.done)
+		_ = c.Conn.Close()
+	})
+}
+
+// NewClient creates a client; the connection is established on the first Publish
+func NewClient(cfg Config) (*Client, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("mqtt: invalid URL: %w", err)
+	}
+
+	var useTLS bool
+	var defaultPort string
+	switch u.Scheme {
+	case "mqtt", "tcp":
+		defaultPort = "1883"
+	case "mqtts", "ssl", "tls":
+		useTLS, defaultPort = true, "8883"
+	default:
+		return nil, fmt.Errorf("mqtt: unsupported scheme %q", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return nil, errors.New("mqtt: URL must include a host")
+	}
+	port := u.Port()
+	if port == "" {
+		port = defaultPort
+	}
+
+	return &Client{
+		address: net.JoinHostPort(u.Hostname(), port),
+		useTLS:  useTLS,
+		host:    u.Hostname(),
+		cfg:     cfg,
+	}, nil
+}
+
+// Publish sends a QoS 0 message; retained messages are delivered to new subscribers
+// При ошибке записи клиент переподключается и повторяет отправку один раз
+func (c *Client) Publish(ctx context.Context, topic string, payload []byte, retain bool) error {
+	if topic == "" || len(topic) > 0xFFFF {
+		return ErrInvalidTopic
+	}
+
+	header := packetPublish
+	if retain {
+		header |= 0x01
+	}
+	var body []byte
+	body = appendString(body, topic)
+	body = append(body, payload...)
+	packet := encodePacket(header, body)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if c.conn == nil {
+			if c.conn, err = c.connect(ctx); err != nil {
+				return err
+			}
+		}
+		if err = c.write(c.conn, packet); err == nil {
+			return nil
+		}
+		c.conn.close()
+		c.conn = nil
+	}
+	return err
+}
+
+// Close disconnects from the broker
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+	_ = c.write(c.conn, []byte{packetDisconnect, 0})
+	c.conn.close()
+	c.conn = nil
+	return nil
+}
+
+// connect подключается к брокеру и ждёт CONNACK; вызывается под c.mu
+func (c *Client) connect(ctx context.Context) (*connection, error) {
+	deadline := time.Now().Add(c.cfg.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	dialer := &net.Dialer{Deadline: deadline}
+	var conn net.Conn
+	var err error
+	if c.useTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: c.host, MinVersion: tls.VersionTLS12}}).DialContext(ctx, "tcp", c.address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", c.address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("mqtt: connect: %w", err)
+	}
+	_ = conn.SetDeadline(deadline)
+
+	if _, err := conn.Write(c.connectPacket()); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("mqtt: connect: %w", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	packetType, body, err := readPacket(reader)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("mqtt: connack: %w", err)
+	}
+	if packetType != packetConnack || len(body) != 2 {
+		_ = conn.Close()
+		return nil, fmt.Errorf("mqtt: unexpected packet 0x%x instead of CONNACK", packetType)
+	}
+	if body[1] != 0 {
+		_ = conn.Close()
+		return nil, fmt.Errorf("%w (code %d)", ErrConnectionRefused, body[1])
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	cn := &connection{Conn: conn, done: make(chan struct{})}
+	go c.readLoop(cn, reader)
+	go c.pingLoop(cn)
+	return cn, nil
+}
+
+// connectPacket - CONNECT с clean session; пустой пароль не отправляется
+func (c *Client) connectPacket() []byte {
+	flags := byte(0x02) // Clean session
+	if c.cfg.Username != "" {
+		flags |= 0x80
+		if c.cfg.Password != "" {
+			flags |= 0x40
+		}
+	}
+
+	seconds := uint16(keepAlive / time.Second)
+	var body []byte
+	body = appendString(body, "MQTT")
+	body = append(body, 4, flags, byte(seconds>>8), byte(seconds)) // Уровень протокола 4 - MQTT 3.1.1
+	body = appendString(body, c.cfg.ClientID)
+	if c.cfg.Username != "" {
+		body = appendString(body, c.cfg.Username)
+		if c.cfg.Password != "" {
+			body = appendString(body, c.cfg.Password)
+		}
+	}
+	return encodePacket(packetConnect, body)
+}
+
+// readLoop читает ответы брокера (PINGRESP) и закрывает подключение при обрыве,
+// чтобы следующая публикация переподключилась
+func (c *Client) readLoop(cn *connection, reader *bufio.Reader) {
+	for {
+		if _, _, err := readPacket(reader); err != nil {
+			c.drop(cn)
+			return
+		}
+	}
+}
+
+// pingLoop отправляет PINGREQ, чтобы брокер не закрыл простаивающее подключение
+func (c *Client) pingLoop(cn *connection) {
+	ticker := time.NewTicker(keepAlive / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-cn.done:
+			return
+		case <-ticker.C:
+			c.mu.Lock()
+			err := c.write(cn, []byte{packetPingreq, 0})
+			c.mu.Unlock()
+			if err != nil {
+				c.drop(cn)
+				return
+			}
+		}
+	}
+}
+
+// drop закрывает подключение и забывает его, если оно ещё текущее
+func (c *Client) drop(cn *connection) {
+	cn.close()
+	c.mu.Lock()
+	if c.conn == cn {
+		c.conn = nil
+	}
+	c.mu.Unlock()
+}
+
+func (c *Client) write(cn *connection, packet []byte) error {
+	_ = cn.SetWriteDeadline(time.Now().Add(c.cfg.Timeout))
+	_, err := cn.Write(packet)
+	return err
+}
+
+// encodePacket добавляет фиксированный заголовок: тип и длину остатка (variable byte integer)
+func encodePacket(header byte, body []byte) []byte {
+	packet := []byte{header}
+	length := len(body)
+	for {
+		b := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			b |= 0x80
+		}
+		packet = append(packet, b)
+		if length == 0 {
+			break
+		}
+	}
+	return append(packet, body...)
+}
+
+// readPacket читает пакет и возвращает его тип (без флагов) и тело
+func readPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	length, multiplier := 0, 1
+	for i := 0; ; i++ {
+		if i == 4 {
+			return 0, nil, errors.New("malformed remaining length")
+		}
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(b&0x7F) * multiplier
+		multiplier *= 128
+		if b&0x80 == 0 {
+			break
+		}
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return header & 0xF0, body, nil
+}
+
+// appendString добавляет строку в формате MQTT: длина (2 байта) и UTF-8
+func appendString(b []byte, s string) []byte {
+	return append(append(b, byte(len(s)>>8), byte(len(s))), s...)
+}
@@ -0,0 +1,116 @@
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// testBroker принимает подключения, отвечает CONNACK с кодом code и пересылает пакеты в packets
+type testBroker struct {
+	net.Listener
+	code    byte
+	packets chan []byte
+}
+
+func newTestBroker(t *testing.T, code byte) *testBroker {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	b := &testBroker{Listener: ln, code: code, packets: make(chan []byte, 10)}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go b.serve(conn)
+		}
+	}()
+	return b
+}
+
+func (b *testBroker) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		header, _ := reader.Peek(1)
+		packetType, body, err := readPacket(reader)
+		if err != nil {
+			return
+		}
+		b.packets <- append([]byte{header[0]}, body...)
+		if packetType == packetConnect {
+			_, _ = conn.Write([]byte{packetConnack, 2, 0, b.code})
+		}
+	}
+}
+
+func (b *testBroker) next(t *testing.T) []byte {
+	t.Helper()
+	select {
+	case p := <-b.packets:
+		return p
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for a packet")
+		return nil
+	}
+}
+
+func TestClientPublish(t *testing.T) {
+	broker := newTestBroker(t, 0)
+	client, err := NewClient(Config{URL: "mqtt://" + broker.Addr().String(), ClientID: "space", Username: "user", Password: "secret", Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.Publish(context.Background(), "space/rooms/1/state", []byte("booked"), true); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	connect := broker.next(t)
+	want := append([]byte{packetConnect, 0, 4, 'M', 'Q', 'T', 'T', 4, 0xC2, 0, 60}, appendString(appendString(appendString(nil, "space"), "user"), "secret")...)
+	if string(connect) != string(want) {
+		t.Errorf("Unexpected CONNECT: %v", connect)
+	}
+
+	publish := broker.next(t)
+	want = append(append([]byte{packetPublish | 0x01}, appendString(nil, "space/rooms/1/state")...), "booked"...)
+	if string(publish) != string(want) {
+		t.Errorf("Unexpected PUBLISH: %q", publish)
+	}
+}
+
+func TestClientPublish_Refused(t *testing.T) {
+	broker := newTestBroker(t, 5) // Not authorized
+	client, err := NewClient(Config{URL: "mqtt://" + broker.Addr().String(), Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if err := client.Publish(context.Background(), "space/rooms/1", []byte("{}"), true); !errors.Is(err, ErrConnectionRefused) {
+		t.Errorf("Expected ErrConnectionRefused, got: %v", err)
+	}
+}
+
+func TestEncodePacket_RemainingLength(t *testing.T) {
+	packet := encodePacket(packetPublish, make([]byte, 321))
+	if packet[1] != 0xC1 || packet[2] != 0x02 || len(packet) != 3+321 {
+		t.Errorf("Expected two-byte remaining length 321, got: %x %x", packet[1], packet[2])
+	}
+}
+
+func TestNewClient_InvalidURL(t *testing.T) {
+	for _, raw := range []string{"http://broker", "mqtt://", "::"} {
+		if _, err := NewClient(Config{URL: raw}); err == nil {
+			t.Errorf("Expected %q to be rejected", raw)
+		}
+	}
+}
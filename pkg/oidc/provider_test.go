@@ -0,0 +1,130 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testProvider - минимальный провайдер OIDC: discovery, JWKS и token endpoint
+type testProvider struct {
+	*httptest.Server
+	key     *rsa.PrivateKey
+	idToken string
+}
+
+func newTestProvider(t *testing.T) *testProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	p := &testProvider{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.URL,
+			"authorization_endpoint": p.URL + "/authorize",
+			"token_endpoint":         p.URL + "/token",
+			"jwks_uri":               p.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA", "kid": "k1", "use": "sig",
+			"n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("code") != "good-code" || r.FormValue("code_verifier") != "verifier" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"id_token": p.idToken})
+	})
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+	return p
+}
+
+func (p *testProvider) sign(t *testing.T, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "k1"})
+	payload, _ := json.Marshal(claims)
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, p.key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestProviderLoginFlow(t *testing.T) {
+	tp := newTestProvider(t)
+	ctx := context.Background()
+	provider := NewProvider(tp.URL, "client", "secret", "https://api.example.com/api/auth/oidc/callback", time.Second)
+
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", "challenge")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	u, _ := url.Parse(authURL)
+	if q := u.Query(); u.Path != "/authorize" || q.Get("state") != "state-1" || q.Get("code_challenge_method") != "S256" || q.Get("client_id") != "client" {
+		t.Errorf("Unexpected auth URL: %s", authURL)
+	}
+
+	now := time.Now()
+	claims := map[string]interface{}{
+		"iss": tp.URL, "sub": "user-1", "aud": "client", "exp": now.Add(time.Hour).Unix(), "iat": now.Unix(),
+		"nonce": "nonce-1", "email": "Anna@Example.com", "email_verified": "true",
+	}
+	tp.idToken = tp.sign(t, claims)
+
+	if _, err := provider.Exchange(ctx, "bad-code", "verifier"); err == nil {
+		t.Error("Expected exchange with a bad code to fail")
+	}
+	raw, err := provider.Exchange(ctx, "good-code", "verifier")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	token, err := provider.VerifyIDToken(ctx, raw, "nonce-1", now)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if token.Subject != "user-1" || token.Email != "Anna@Example.com" || !bool(token.EmailVerified) {
+		t.Errorf("Unexpected token: %+v", token)
+	}
+
+	if _, err := provider.VerifyIDToken(ctx, raw, "other-nonce", now); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected nonce mismatch, got: %v", err)
+	}
+	if _, err := provider.VerifyIDToken(ctx, raw, "nonce-1", now.Add(2*time.Hour)); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("Expected expired token, got: %v", err)
+	}
+
+	claims["aud"] = []string{"other-client"}
+	if _, err := provider.VerifyIDToken(ctx, tp.sign(t, claims), "nonce-1", now); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected foreign audience to be rejected, got: %v", err)
+	}
+
+	parts := strings.Split(raw, ".")
+	forged := parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"admin"}`)) + "." + parts[2]
+	if _, err := provider.VerifyIDToken(ctx, forged, "nonce-1", now); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected forged token to be rejected, got: %v", err)
+	}
+}
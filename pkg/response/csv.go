@@ -0,0 +1,101 @@
+package response
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// utf8BOM позволяет Excel определить кодировку файла (иначе кириллица отображается неверно)
+const utf8BOM = "\xef\xbb\xbf"
+
+// CSV sends rows as a CSV attachment with a UTF-8 BOM
+// Кавычки, запятые и переводы строк экранирует encoding/csv; значения, которые
+// Excel принял бы за формулу, экранируются апострофом
+func CSV(c *gin.Context, filename string, header []string, rows [][]string) {
+	stream := NewCSVStream(c, filename, header)
+	for _, row := range rows {
+		if err := stream.WriteRow(row); err != nil {
+			return
+		}
+	}
+	_ = stream.Close()
+}
+
+// CSVStream writes a CSV attachment in chunks without holding all rows in memory
+// Заголовки ответа отправляются при первой записи: до неё ошибку ещё можно вернуть обычным ответом
+type CSVStream struct {
+	c        *gin.Context
+	filename string
+	header   []string
+	w        *csv.Writer
+}
+
+// NewCSVStream prepares a streamed CSV attachment; nothing is sent until the first row or Close
+func NewCSVStream(c *gin.Context, filename string, header []string) *CSVStream {
+	return &CSVStream{c: c, filename: filename, header: header}
+}
+
+// Started reports whether the response headers were already sent
+func (s *CSVStream) Started() bool {
+	return s.w != nil
+}
+
+// WriteRow buffers a row; call Flush to send buffered rows to the client
+func (s *CSVStream) WriteRow(row []string) error {
+	if err := s.start(); err != nil {
+		return err
+	}
+	safe := make([]string, len(row))
+	for i, value := range row {
+		safe[i] = escapeFormula(value)
+	}
+	return s.w.Write(safe)
+}
+
+// Flush sends buffered rows to the client as a chunk
+func (s *CSVStream) Flush() error {
+	if err := s.start(); err != nil {
+		return err
+	}
+	s.w.Flush()
+	if err := s.w.Error(); err != nil {
+		return err
+	}
+	s.c.Writer.Flush()
+	return nil
+}
+
+// Close finishes the file; an export without rows still gets the header row
+func (s *CSVStream) Close() error {
+	return s.Flush()
+}
+
+func (s *CSVStream) start() error {
+	if s.w != nil {
+		return nil
+	}
+	s.c.Header("Content-Type", "text/csv; charset=utf-8")
+	s.c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, s.filename))
+	s.c.Status(http.StatusOK)
+
+	s.w = csv.NewWriter(s.c.Writer)
+	if _, err := s.c.Writer.WriteString(utf8BOM); err != nil {
+		return err
+	}
+	return s.w.Write(s.header)
+}
+
+// escapeFormula защищает от CSV injection: =, +, -, @ в начале ячейки Excel выполняет как формулу
+func escapeFormula(value string) string {
+	if value == "" {
+		return value
+	}
+	if strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
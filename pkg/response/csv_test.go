@@ -0,0 +1,38 @@
+package response
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCSV(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	// SecurityHeaders заранее выставляет JSON - CSV должен его перекрыть
+	c.Header("Content-Type", "application/json")
+
+	CSV(c, "report.csv", []string{"Name", "Note"}, [][]string{
+		{"Иван, Петров", `say "hi"`},
+		{"=HYPERLINK(\"x\")", "-1"},
+	})
+
+	if ct := w.Header().Get("Content-Type"); ct != "text/csv; charset=utf-8" {
+		t.Errorf("Expected text/csv content type, got: %s", ct)
+	}
+	if cd := w.Header().Get("Content-Disposition"); cd != `attachment; filename="report.csv"` {
+		t.Errorf("Expected attachment disposition, got: %s", cd)
+	}
+
+	body := w.Body.String()
+	if !strings.HasPrefix(body, "\xef\xbb\xbf") {
+		t.Error("Expected UTF-8 BOM")
+	}
+	want := "Name,Note\n\"Иван, Петров\",\"say \"\"hi\"\"\"\n\"'=HYPERLINK(\"\"x\"\")\",'-1\n"
+	if got := strings.TrimPrefix(body, "\xef\xbb\xbf"); got != want {
+		t.Errorf("Expected %q, got: %q", want, got)
+	}
+}
@@ -0,0 +1,81 @@
+package response
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JSONStream writes a JSON array attachment item by item without holding all items in memory
+// Если выгрузка прервалась, массив остаётся незакрытым - клиент получит невалидный JSON, а не обрезанный список
+type JSONStream struct {
+	c        *gin.Context
+	filename string
+	started  bool
+	count    int
+}
+
+// NewJSONStream prepares a streamed JSON attachment; nothing is sent until the first item or Close
+func NewJSONStream(c *gin.Context, filename string) *JSONStream {
+	return &JSONStream{c: c, filename: filename}
+}
+
+// Started reports whether the response headers were already sent
+func (s *JSONStream) Started() bool {
+	return s.started
+}
+
+// Write appends an item to the array; call Flush to send written items to the client
+func (s *JSONStream) Write(item any) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	s.start()
+
+	separator := ","
+	if s.count == 0 {
+		separator = "["
+	}
+	if _, err := s.c.Writer.WriteString(separator); err != nil {
+		return err
+	}
+	if _, err := s.c.Writer.Write(data); err != nil {
+		return err
+	}
+	s.count++
+	return nil
+}
+
+// Flush sends written items to the client as a chunk
+func (s *JSONStream) Flush() error {
+	s.start()
+	s.c.Writer.Flush()
+	return nil
+}
+
+// Close closes the array; an export without items is sent as []
+func (s *JSONStream) Close() error {
+	s.start()
+	closing := "]"
+	if s.count == 0 {
+		closing = "[]"
+	}
+	if _, err := s.c.Writer.WriteString(closing + "\n"); err != nil {
+		return err
+	}
+	s.c.Writer.Flush()
+	return nil
+}
+
+func (s *JSONStream) start() {
+	if s.started {
+		return
+	}
+	s.started = true
+	s.c.Header("Content-Type", "application/json; charset=utf-8")
+	s.c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, s.filename))
+	s.c.Status(http.StatusOK)
+}
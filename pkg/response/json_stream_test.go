@@ -0,0 +1,47 @@
+package response
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestJSONStream(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	stream := NewJSONStream(c, "items.json")
+	if stream.Started() {
+		t.Fatal("Expected nothing to be sent before the first item")
+	}
+	for _, batch := range [][]int{{1, 2}, {3}} {
+		for _, item := range batch {
+			if err := stream.Write(map[string]int{"id": item}); err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+		}
+		_ = stream.Flush()
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if !w.Flushed {
+		t.Error("Expected batches to be flushed")
+	}
+	if cd := w.Header().Get("Content-Disposition"); cd != `attachment; filename="items.json"` {
+		t.Errorf("Expected attachment disposition, got: %s", cd)
+	}
+	if want := `[{"id":1},{"id":2},{"id":3}]` + "\n"; w.Body.String() != want {
+		t.Errorf("Expected %q, got: %q", want, w.Body.String())
+	}
+
+	empty := httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(empty)
+	_ = NewJSONStream(c, "items.json").Close()
+	if empty.Body.String() != "[]\n" {
+		t.Errorf("Expected empty array, got: %q", empty.Body.String())
+	}
+}
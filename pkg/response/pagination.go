@@ -0,0 +1,130 @@
+package response
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrInvalidCursor возвращается, если cursor не был выдан сервером
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// cursorPrefix отличает курсор от произвольной строки
+const cursorPrefix = "o:"
+
+// Page описывает запрошенную страницу списка
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// PageMeta describes the position of a page within a list
+type PageMeta struct {
+	Total      int64  `json:"total"`
+	Page       int    `json:"page"`
+	PerPage    int    `json:"per_page"`
+	NextCursor string `json:"next_cursor,omitempty"` // Пусто на последней странице
+}
+
+// PaginatedResponse represents a page of a list
+type PaginatedResponse struct {
+	Data interface{} `json:"data"`
+	Meta PageMeta    `json:"meta"`
+}
+
+// ParsePage reads pagination query parameters
+// Поддерживаются cursor (из meta.next_cursor), page/per_page и прежние limit/offset;
+// per_page ограничивается maxPerPage, по умолчанию используется defaultPerPage
+func ParsePage(c *gin.Context, defaultPerPage, maxPerPage int) (Page, error) {
+	page := Page{Limit: defaultPerPage}
+
+	perPage := c.Query("per_page")
+	if perPage == "" {
+		perPage = c.Query("limit")
+	}
+	if perPage != "" {
+		n, err := strconv.Atoi(perPage)
+		if err != nil || n <= 0 {
+			return page, errors.New("invalid per_page")
+		}
+		page.Limit = min(n, maxPerPage)
+	}
+
+	switch {
+	case c.Query("cursor") != "":
+		offset, err := decodeCursor(c.Query("cursor"))
+		if err != nil {
+			return page, err
+		}
+		page.Offset = offset
+	case c.Query("page") != "":
+		n, err := strconv.Atoi(c.Query("page"))
+		if err != nil || n <= 0 {
+			return page, errors.New("invalid page")
+		}
+		page.Offset = (n - 1) * page.Limit
+	case c.Query("offset") != "":
+		n, err := strconv.Atoi(c.Query("offset"))
+		if err != nil || n < 0 {
+			return page, errors.New("invalid offset")
+		}
+		page.Offset = n
+	}
+
+	return page, nil
+}
+
+// Meta builds page metadata for a list with total items
+func (p Page) Meta(total int64) PageMeta {
+	meta := PageMeta{
+		Total:   total,
+		Page:    p.Offset/p.Limit + 1,
+		PerPage: p.Limit,
+	}
+	if next := p.Offset + p.Limit; int64(next) < total {
+		meta.NextCursor = encodeCursor(next)
+	}
+	return meta
+}
+
+// Slice returns the requested page of an in-memory list
+// Используется для небольших списков, которые целиком читаются из БД (комнаты, API-ключи)
+func Slice[T any](items []T, p Page) []T {
+	if p.Offset >= len(items) {
+		return []T{}
+	}
+	return items[p.Offset:min(p.Offset+p.Limit, len(items))]
+}
+
+// Paginated sends a page of a list with its metadata
+func Paginated(c *gin.Context, data interface{}, meta PageMeta) {
+	c.JSON(http.StatusOK, PaginatedResponse{
+		Data: data,
+		Meta: meta,
+	})
+}
+
+// encodeCursor кодирует смещение следующей страницы; для клиента курсор непрозрачен
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(offset)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	value, ok := strings.CutPrefix(string(raw), cursorPrefix)
+	if !ok {
+		return 0, ErrInvalidCursor
+	}
+	offset, err := strconv.Atoi(value)
+	if err != nil || offset < 0 {
+		return 0, ErrInvalidCursor
+	}
+	return offset, nil
+}
@@ -0,0 +1,74 @@
+package response
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func parsePage(t *testing.T, query string) (Page, error) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/items?"+query, nil)
+	return ParsePage(c, 20, 100)
+}
+
+func TestParsePage(t *testing.T) {
+	tests := []struct {
+		query string
+		want  Page
+	}{
+		{"", Page{Limit: 20, Offset: 0}},
+		{"page=3&per_page=10", Page{Limit: 10, Offset: 20}},
+		{"per_page=1000", Page{Limit: 100, Offset: 0}},
+		{"limit=5&offset=7", Page{Limit: 5, Offset: 7}}, // прежние параметры
+	}
+
+	for _, tt := range tests {
+		got, err := parsePage(t, tt.query)
+		if err != nil {
+			t.Errorf("%q: expected no error, got: %v", tt.query, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%q: expected %+v, got: %+v", tt.query, tt.want, got)
+		}
+	}
+
+	for _, query := range []string{"page=0", "per_page=abc", "offset=-1", "cursor=bogus"} {
+		if _, err := parsePage(t, query); err == nil {
+			t.Errorf("%q: expected an error", query)
+		}
+	}
+}
+
+func TestPageMeta_NextCursor(t *testing.T) {
+	meta := Page{Limit: 10, Offset: 10}.Meta(25)
+	if meta.Page != 2 || meta.PerPage != 10 || meta.Total != 25 {
+		t.Errorf("Expected page 2 of 10 items out of 25, got: %+v", meta)
+	}
+
+	next, err := parsePage(t, "per_page=10&cursor="+meta.NextCursor)
+	if err != nil {
+		t.Fatalf("Expected cursor to be accepted, got: %v", err)
+	}
+	if next.Offset != 20 {
+		t.Errorf("Expected next offset 20, got: %d", next.Offset)
+	}
+
+	if last := next.Meta(25); last.NextCursor != "" {
+		t.Errorf("Expected no cursor on the last page, got: %q", last.NextCursor)
+	}
+}
+
+func TestSlice(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	if got := Slice(items, Page{Limit: 2, Offset: 4}); len(got) != 1 || got[0] != 5 {
+		t.Errorf("Expected [5], got: %v", got)
+	}
+	if got := Slice(items, Page{Limit: 2, Offset: 10}); got == nil || len(got) != 0 {
+		t.Errorf("Expected empty non-nil page, got: %v", got)
+	}
+}
@@ -0,0 +1,95 @@
+package response
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemContentType - тип ответа об ошибке по RFC 7807
+const ProblemContentType = "application/problem+json"
+
+// Problem represents an RFC 7807 error response
+// Extensions добавляются в объект верхнего уровня (например, conflicting_bookings)
+type Problem struct {
+	Type       string                 `json:"type"`
+	Title      string                 `json:"title"`
+	Status     int                    `json:"status"`
+	Detail     string                 `json:"detail,omitempty"`
+	Instance   string                 `json:"instance,omitempty"`
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON flattens extension members into the problem object
+func (p Problem) MarshalJSON() ([]byte, error) {
+	fields := make(map[string]interface{}, len(p.Extensions)+5)
+	for name, value := range p.Extensions {
+		fields[name] = value
+	}
+	// Стандартные члены нельзя переопределить расширениями
+	fields["type"] = p.Type
+	fields["title"] = p.Title
+	fields["status"] = p.Status
+	if p.Detail != "" {
+		fields["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		fields["instance"] = p.Instance
+	}
+	return json.Marshal(fields)
+}
+
+// wantsProblem сообщает, запросил ли клиент application/problem+json в Accept
+// Без явного запроса ошибки отдаются в прежнем формате {"error": ...}
+func wantsProblem(c *gin.Context) bool {
+	for _, part := range strings.Split(c.GetHeader("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || mediaType != ProblemContentType {
+			continue
+		}
+		return params["q"] != "0"
+	}
+	return false
+}
+
+// writeError отправляет ошибку в формате, согласованном по Accept
+// extensions попадают в problem+json как члены верхнего уровня; в прежнем формате
+// они передаются через legacy (nil - ErrorResponse без дополнительных полей)
+func writeError(c *gin.Context, status int, body ErrorResponse, extensions map[string]interface{}, legacy gin.H) {
+	if !wantsProblem(c) {
+		if legacy != nil {
+			c.JSON(status, legacy)
+			return
+		}
+		c.JSON(status, body)
+		return
+	}
+
+	problem := Problem{
+		Type:       "about:blank",
+		Title:      http.StatusText(status),
+		Status:     status,
+		Detail:     body.Error,
+		Instance:   c.Request.URL.Path,
+		Extensions: map[string]interface{}{},
+	}
+	if body.Message != "" {
+		problem.Extensions["message"] = body.Message
+	}
+	if body.Code != "" {
+		problem.Extensions["code"] = body.Code
+	}
+	if requestID := c.GetString("requestID"); requestID != "" {
+		problem.Extensions["request_id"] = requestID
+	}
+	for name, value := range extensions {
+		problem.Extensions[name] = value
+	}
+
+	// SecurityHeaders заранее выставляет application/json; gin не перезаписывает заданный тип
+	c.Header("Content-Type", ProblemContentType)
+	c.JSON(status, problem)
+}
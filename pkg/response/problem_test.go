@@ -0,0 +1,150 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/space/backend/pkg/apperror"
+	"github.com/space/backend/pkg/i18n"
+	textvalidator "github.com/space/backend/pkg/validator"
+)
+
+func conflictRecorder(accept string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/bookings", nil)
+	c.Request.Header.Set("Accept", accept)
+	c.Set("requestID", "req-1")
+
+	conflict := apperror.Conflict("booking_conflict", "room is already booked")
+	AppError(c, conflict.WithDetails("conflicting_bookings", []int{7}))
+	return w
+}
+
+func TestAppError_Problem(t *testing.T) {
+	w := conflictRecorder("application/problem+json, application/json;q=0.5")
+
+	if got := w.Header().Get("Content-Type"); got != ProblemContentType {
+		t.Errorf("Expected %s, got: %s", ProblemContentType, got)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected JSON body, got: %v", err)
+	}
+	if body["status"] != float64(http.StatusConflict) || body["title"] != "Conflict" || body["type"] != "about:blank" {
+		t.Errorf("Expected 409 Conflict problem, got: %v", body)
+	}
+	if body["detail"] != "room is already booked" || body["instance"] != "/api/bookings" || body["request_id"] != "req-1" {
+		t.Errorf("Expected detail, instance and request_id, got: %v", body)
+	}
+	if body["code"] != "booking_conflict" {
+		t.Errorf("Expected booking_conflict code, got: %v", body)
+	}
+	if _, ok := body["conflicting_bookings"]; !ok {
+		t.Errorf("Expected conflicting_bookings extension, got: %v", body)
+	}
+}
+
+func TestAppError_Legacy(t *testing.T) {
+	w := conflictRecorder("application/json")
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected JSON body, got: %v", err)
+	}
+	if body["error"] != "room is already booked" || body["code"] != "booking_conflict" || body["data"] == nil {
+		t.Errorf("Expected legacy {error, code, data}, got: %v", body)
+	}
+}
+
+func TestAppError_WrappedMessage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/rooms", nil)
+
+	AppError(c, fmt.Errorf("room 7: %w", apperror.BadRequest("room_inactive", "room is not active")))
+
+	var body ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected JSON body, got: %v", err)
+	}
+	if w.Code != http.StatusBadRequest || body.Code != "room_inactive" || body.Error != "room 7: room is not active" {
+		t.Errorf("Expected 400 room_inactive with the wrapped message, got: %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestWantsProblem_QZero(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Request.Header.Set("Accept", "application/problem+json;q=0")
+
+	BadRequest(c, errors.New("bad"))
+	if got := w.Header().Get("Content-Type"); got == ProblemContentType {
+		t.Errorf("Expected legacy format for q=0, got: %s", got)
+	}
+}
+
+func TestBadRequest_FieldErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/bookings", nil)
+	c.Set(i18n.ContextKey, "ru")
+
+	err := fmt.Errorf("create booking: %w", textvalidator.Errors{
+		{Field: "title", Rule: textvalidator.RuleRequired},
+		{Field: "description", Rule: textvalidator.RuleMaxLength, Param: "2000"},
+	})
+	BadRequest(c, err)
+
+	var body struct {
+		Error  string         `json:"error"`
+		Fields []FieldMessage `json:"fields"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected JSON body, got: %v", err)
+	}
+	if w.Code != http.StatusBadRequest || len(body.Fields) != 2 {
+		t.Fatalf("Expected 400 with two field errors, got: %d %s", w.Code, w.Body.String())
+	}
+	if body.Fields[1].Field != "description" || body.Fields[1].Param != "2000" || body.Fields[1].Message != "поле 'description' должно быть не длиннее 2000 символов" {
+		t.Errorf("Expected a localized maxlength error, got: %+v", body.Fields[1])
+	}
+	if !strings.Contains(body.Error, "поле 'title' обязательно") {
+		t.Errorf("Expected localized messages in error, got: %s", body.Error)
+	}
+}
+
+func TestBadRequest_BusinessFieldErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/bookings", nil)
+	c.Set(i18n.ContextKey, "ru")
+
+	var fieldErrs textvalidator.Errors
+	fieldErrs.Add("end_time", apperror.BadRequest("invalid_time", "invalid time: end time must be after start time"))
+	BadRequest(c, fieldErrs.Err())
+
+	var body struct {
+		Fields []FieldMessage `json:"fields"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected JSON body, got: %v", err)
+	}
+	if len(body.Fields) != 1 || body.Fields[0].Field != "end_time" || body.Fields[0].Rule != "invalid_time" ||
+		body.Fields[0].Message != "некорректное время: окончание должно быть позже начала" {
+		t.Errorf("Expected a localized end_time error, got: %s", w.Body.String())
+	}
+}
@@ -1,9 +1,16 @@
 package response
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/space/backend/pkg/apperror"
+	"github.com/space/backend/pkg/i18n"
+	textvalidator "github.com/space/backend/pkg/validator"
 )
 
 // ErrorResponse represents an error response
@@ -13,6 +20,12 @@ type ErrorResponse struct {
 	Code    string `json:"code,omitempty"` // Код ошибки для клиента
 }
 
+// FieldMessage is a field validation error with its message in the language of the request
+type FieldMessage struct {
+	textvalidator.FieldError
+	Message string `json:"message"`
+}
+
 // SuccessResponse represents a success response
 type SuccessResponse struct {
 	Data    interface{} `json:"data,omitempty"`
@@ -41,24 +54,49 @@ func Created(c *gin.Context, data interface{}) {
 	})
 }
 
+// CreatedWithMessage sends a 201 Created response with a message in the language of the request
+// Для предупреждений: запрос выполнен, но клиенту стоит показать сообщение
+func CreatedWithMessage(c *gin.Context, data interface{}, message string) {
+	c.JSON(http.StatusCreated, SuccessResponse{
+		Data:    data,
+		Message: i18n.T(locale(c), message),
+	})
+}
+
 // NoContent sends a 204 No Content response
 func NoContent(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
 // Error sends an error JSON response
+// Клиенты с Accept: application/problem+json получают ответ по RFC 7807
+// Ошибки полей, найденные сервисом, дополнительно отдаются списком fields
 func Error(c *gin.Context, statusCode int, err error) {
-	c.JSON(statusCode, ErrorResponse{
-		Error: err.Error(),
-	})
+	body := ErrorResponse{Error: localize(c, err)}
+
+	var fieldErrs textvalidator.Errors
+	if errors.As(err, &fieldErrs) {
+		lang := locale(c)
+		fields := make([]FieldMessage, len(fieldErrs))
+		for i, fe := range fieldErrs {
+			fields[i] = FieldMessage{FieldError: fe, Message: textFieldMessage(lang, fe)}
+		}
+		writeError(c, statusCode, body,
+			map[string]interface{}{"fields": fields},
+			gin.H{"error": body.Error, "fields": fields},
+		)
+		return
+	}
+
+	writeError(c, statusCode, body, nil, nil)
 }
 
 // ErrorWithMessage sends an error JSON response with a custom message
 func ErrorWithMessage(c *gin.Context, statusCode int, err error, message string) {
-	c.JSON(statusCode, ErrorResponse{
-		Error:   err.Error(),
-		Message: message,
-	})
+	writeError(c, statusCode, ErrorResponse{
+		Error:   localize(c, err),
+		Message: i18n.T(locale(c), message),
+	}, nil, nil)
 }
 
 // BadRequest sends a 400 Bad Request response
@@ -73,10 +111,10 @@ func Unauthorized(c *gin.Context, err error) {
 
 // UnauthorizedWithCode sends a 401 Unauthorized response with error code
 func UnauthorizedWithCode(c *gin.Context, err error, code string) {
-	c.JSON(http.StatusUnauthorized, ErrorResponse{
-		Error: err.Error(),
+	writeError(c, http.StatusUnauthorized, ErrorResponse{
+		Error: localize(c, err),
 		Code:  code,
-	})
+	}, nil, nil)
 }
 
 // Forbidden sends a 403 Forbidden response
@@ -94,15 +132,96 @@ func Conflict(c *gin.Context, err error) {
 	Error(c, http.StatusConflict, err)
 }
 
-// ConflictWithData sends a 409 Conflict response with additional data
-func ConflictWithData(c *gin.Context, message string, data interface{}) {
-	c.JSON(http.StatusConflict, gin.H{
-		"error": message,
-		"data":  data,
-	})
+// AppError sends a domain error (pkg/apperror) found in the chain of err with its status and code
+// Текст - всей цепочки: обёртки fmt.Errorf уточняют сообщение. Данные ошибки в прежнем формате
+// передаются в поле data, в problem+json - в члене DetailsName; err без доменной ошибки - InternalServerError
+func AppError(c *gin.Context, err error) {
+	appErr, ok := apperror.As(err)
+	if !ok {
+		InternalServerError(c, err)
+		return
+	}
+
+	body := ErrorResponse{Error: localize(c, err), Code: appErr.Code}
+	if appErr.DetailsName == "" {
+		writeError(c, appErr.Status, body, nil, nil)
+		return
+	}
+	writeError(c, appErr.Status, body,
+		map[string]interface{}{appErr.DetailsName: appErr.Details},
+		gin.H{"error": body.Error, "code": body.Code, "data": appErr.Details},
+	)
 }
 
 // InternalServerError sends a 500 Internal Server Error response
+// Ошибки истечения дедлайна запроса отдаются как 504 Gateway Timeout
 func InternalServerError(c *gin.Context, err error) {
+	if errors.Is(err, context.DeadlineExceeded) {
+		GatewayTimeout(c, err)
+		return
+	}
 	Error(c, http.StatusInternalServerError, err)
 }
+
+// GatewayTimeout sends a 504 Gateway Timeout response
+func GatewayTimeout(c *gin.Context, err error) {
+	Error(c, http.StatusGatewayTimeout, err)
+}
+
+// locale возвращает язык запроса, определённый i18n middleware
+func locale(c *gin.Context) string {
+	if lang := c.GetString(i18n.ContextKey); lang != "" {
+		return lang
+	}
+	return i18n.Default
+}
+
+// localize переводит текст ошибки на язык запроса
+// Ошибки валидации тела запроса разворачиваются в сообщения по каждому полю
+func localize(c *gin.Context, err error) string {
+	lang := locale(c)
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		messages := make([]string, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			messages = append(messages, fieldErrorMessage(lang, fe))
+		}
+		return strings.Join(messages, "; ")
+	}
+
+	var fieldErrs textvalidator.Errors
+	if errors.As(err, &fieldErrs) {
+		messages := make([]string, 0, len(fieldErrs))
+		for _, fe := range fieldErrs {
+			messages = append(messages, textFieldMessage(lang, fe))
+		}
+		return strings.Join(messages, "; ")
+	}
+
+	return i18n.T(lang, err.Error())
+}
+
+// fieldErrorMessage формирует сообщение об ошибке валидации одного поля
+func fieldErrorMessage(lang string, fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required", "email":
+		return i18n.Tf(lang, "validation."+fe.Tag(), fe.Field())
+	case "min", "max", "oneof":
+		return i18n.Tf(lang, "validation."+fe.Tag(), fe.Field(), fe.Param())
+	default:
+		return i18n.Tf(lang, "validation.invalid", fe.Field())
+	}
+}
+
+// textFieldMessage формирует сообщение об ошибке поля, найденной сервисом (pkg/validator)
+// Бизнес-проверки поля переводятся по тексту своей доменной ошибки
+func textFieldMessage(lang string, fe textvalidator.FieldError) string {
+	if errors.Unwrap(fe) != nil {
+		return i18n.T(lang, fe.Error())
+	}
+	if fe.Param != "" {
+		return i18n.Tf(lang, "validation."+fe.Rule, fe.Field, fe.Param)
+	}
+	return i18n.Tf(lang, "validation."+fe.Rule, fe.Field)
+}
@@ -0,0 +1,44 @@
+package response
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrInvalidSort возвращается для поля, по которому нельзя сортировать
+var ErrInvalidSort = errors.New("invalid sort field")
+
+// ParseSort parses ?sort=-start_time,title into an ORDER BY clause
+// columns - белый список эндпоинта: имя поля в API -> колонка в БД; "-" перед полем - по убыванию
+// Пустая строка означает порядок по умолчанию
+func ParseSort(c *gin.Context, columns map[string]string) (string, error) {
+	v := c.Query("sort")
+	if v == "" {
+		return "", nil
+	}
+
+	var clauses []string
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(v, ",") {
+		// Неэкранированный "+" в query декодируется в пробел, поэтому убираем и его, и пробелы
+		field = strings.TrimPrefix(strings.TrimSpace(field), "+")
+		if field == "" {
+			continue
+		}
+		direction := "ASC"
+		if name, desc := strings.CutPrefix(field, "-"); desc {
+			field, direction = name, "DESC"
+		}
+
+		column, ok := columns[field]
+		if !ok || seen[field] {
+			return "", fmt.Errorf("%w: %q", ErrInvalidSort, field)
+		}
+		seen[field] = true
+		clauses = append(clauses, column+" "+direction)
+	}
+	return strings.Join(clauses, ", "), nil
+}
@@ -0,0 +1,47 @@
+package response
+
+import (
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+var testSortColumns = map[string]string{"start_time": "start_time", "title": "title"}
+
+func parseSortQuery(t *testing.T, sort string) (string, error) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/bookings?sort="+url.QueryEscape(sort), nil)
+	return ParseSort(c, testSortColumns)
+}
+
+func TestParseSort(t *testing.T) {
+	tests := map[string]string{
+		"":                  "",
+		"-start_time,title": "start_time DESC, title ASC",
+		" title , ":         "title ASC",
+		"+title":            "title ASC",
+	}
+	for sort, want := range tests {
+		got, err := parseSortQuery(t, sort)
+		if err != nil {
+			t.Errorf("%q: expected no error, got: %v", sort, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("%q: expected %q, got: %q", sort, want, got)
+		}
+	}
+}
+
+func TestParseSort_Invalid(t *testing.T) {
+	for _, sort := range []string{"password", "title;DROP TABLE users", "title,-title", "--title"} {
+		if _, err := parseSortQuery(t, sort); !errors.Is(err, ErrInvalidSort) {
+			t.Errorf("%q: expected ErrInvalidSort, got: %v", sort, err)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Package session issues and verifies compact HS256 tokens signed with JWT_SECRET
+package session
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid session token")
+	ErrTokenExpired = errors.New("session token expired")
+)
+
+// header - единственный поддерживаемый заголовок, другие alg не принимаются
+const header = `{"alg":"HS256","typ":"JWT"}`
+
+// Claims are the payload of a token
+// Audience отделяет назначения токенов друг от друга: токен входа не годится как state и наоборот
+type Claims struct {
+	Subject   string            `json:"sub"`
+	Audience  string            `json:"aud"`
+	IssuedAt  int64             `json:"iat"`
+	ExpiresAt int64             `json:"exp"`
+	Data      map[string]string `json:"data,omitempty"`
+}
+
+// Sign issues a token for subject valid for ttl
+func Sign(secret []byte, audience, subject string, data map[string]string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	payload, err := json.Marshal(Claims{
+		Subject:   subject,
+		Audience:  audience,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+		Data:      data,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + sign(secret, unsigned), nil
+}
+
+// Verify checks the signature, audience and expiry of a token
+func Verify(secret []byte, audience, token string, now time.Time) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+	if parts[0] != base64.RawURLEncoding.EncodeToString([]byte(header)) {
+		return nil, ErrInvalidToken
+	}
+	if !hmac.Equal([]byte(parts[2]), []byte(sign(secret, parts[0]+"."+parts[1]))) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if claims.Audience != audience {
+		return nil, ErrInvalidToken
+	}
+	if now.Unix() >= claims.ExpiresAt {
+		return nil, ErrTokenExpired
+	}
+	return &claims, nil
+}
+
+func sign(secret []byte, unsigned string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
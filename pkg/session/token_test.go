@@ -0,0 +1,40 @@
+package session
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	secret := []byte(strings.Repeat("s", 32))
+	token, err := Sign(secret, "session", "42", map[string]string{"k": "v"}, time.Hour)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	claims, err := Verify(secret, "session", token, time.Now())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if claims.Subject != "42" || claims.Data["k"] != "v" {
+		t.Errorf("Unexpected claims: %+v", claims)
+	}
+
+	if _, err := Verify(secret, "oidc_flow", token, time.Now()); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected token of another audience to be rejected, got: %v", err)
+	}
+	if _, err := Verify([]byte(strings.Repeat("x", 32)), "session", token, time.Now()); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected token with another secret to be rejected, got: %v", err)
+	}
+	if _, err := Verify(secret, "session", token, time.Now().Add(2*time.Hour)); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("Expected expired token to be rejected, got: %v", err)
+	}
+
+	parts := strings.Split(token, ".")
+	tampered := parts[0] + "." + parts[1] + "x." + parts[2]
+	if _, err := Verify(secret, "session", tampered, time.Now()); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected tampered token to be rejected, got: %v", err)
+	}
+}
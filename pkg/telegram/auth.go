@@ -18,7 +18,7 @@ var (
 	ErrInvalidHash     = errors.New("invalid hash")
 	ErrMissingHash     = errors.New("missing hash parameter")
 	ErrMissingUserData = errors.New("missing user data")
-	ErrAuthDateExpired = errors.New("auth_date expired (older than 1 hour)")
+	ErrAuthDateExpired = errors.New("auth_date expired")
 	ErrInvalidAuthDate = errors.New("invalid auth_date")
 )
 
@@ -36,6 +36,17 @@ type TelegramUser struct {
 // See: https://core.telegram.org/bots/webapps#validating-data-received-via-the-mini-app
 // ttl - time to live for auth_date in seconds (e.g., 3600 for 1 hour)
 func ValidateInitData(initData string, botToken string, ttl int64) error {
+	// Для Mini App секретный ключ - HMAC_SHA256("WebAppData", botToken)
+	secretKey := hmac.New(sha256.New, []byte("WebAppData"))
+	secretKey.Write([]byte(botToken))
+
+	return validateSignedData(initData, secretKey.Sum(nil), ttl)
+}
+
+// validateSignedData - общий путь валидации для Mini App и Login Widget
+// Отличается только секретный ключ, которым подписан data-check-string
+// ttl - time to live for auth_date in seconds
+func validateSignedData(initData string, secretKey []byte, ttl int64) error {
 	if initData == "" {
 		return errors.New("initData is empty")
 	}
@@ -53,24 +64,12 @@ func ValidateInitData(initData string, botToken string, ttl int64) error {
 	}
 	values.Del("hash")
 
-	// Проверяем auth_date (срок действия 1 час)
-	authDateStr := values.Get("auth_date")
-	if authDateStr == "" {
-		return ErrInvalidAuthDate
-	}
-
-	authDate, err := strconv.ParseInt(authDateStr, 10, 64)
-	if err != nil {
-		return ErrInvalidAuthDate
-	}
-
-	// Проверяем, что auth_date не старше установленного TTL
-	now := time.Now().Unix()
-	if now-authDate > ttl {
-		return ErrAuthDateExpired
+	if err := checkAuthDate(values.Get("auth_date"), ttl); err != nil {
+		return err
 	}
 
 	// Create data-check-string
+	// Формат: ключ=значение, отсортированные по ключу, соединённые \n
 	var keys []string
 	for key := range values {
 		keys = append(keys, key)
@@ -83,23 +82,37 @@ func ValidateInitData(initData string, botToken string, ttl int64) error {
 	}
 	dataCheckString := strings.Join(dataCheckArr, "\n")
 
-	// Create secret key
-	secretKey := hmac.New(sha256.New, []byte("WebAppData"))
-	secretKey.Write([]byte(botToken))
-
 	// Calculate hash
-	calculatedHash := hmac.New(sha256.New, secretKey.Sum(nil))
+	calculatedHash := hmac.New(sha256.New, secretKey)
 	calculatedHash.Write([]byte(dataCheckString))
 	calculatedHashStr := hex.EncodeToString(calculatedHash.Sum(nil))
 
-	// Compare hashes
-	if calculatedHashStr != hash {
+	// Compare hashes (constant time)
+	if !hmac.Equal([]byte(calculatedHashStr), []byte(hash)) {
 		return ErrInvalidHash
 	}
 
 	return nil
 }
 
+// checkAuthDate проверяет, что auth_date не старше установленного TTL
+func checkAuthDate(authDateStr string, ttl int64) error {
+	if authDateStr == "" {
+		return ErrInvalidAuthDate
+	}
+
+	authDate, err := strconv.ParseInt(authDateStr, 10, 64)
+	if err != nil {
+		return ErrInvalidAuthDate
+	}
+
+	if time.Now().Unix()-authDate > ttl {
+		return ErrAuthDateExpired
+	}
+
+	return nil
+}
+
 // ParseUserFromInitData parses user data from initData query string
 func ParseUserFromInitData(initData string) (*TelegramUser, error) {
 	values, err := url.ParseQuery(initData)
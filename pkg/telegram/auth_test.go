@@ -193,3 +193,73 @@ func TestValidateAndParseInitData_InvalidHash(t *testing.T) {
 		t.Errorf("Expected ErrInvalidHash, got: %v", err)
 	}
 }
+
+// Helper function to create valid Login Widget data for testing
+func createValidLoginWidgetData(botToken string, authDate int64) string {
+	data := map[string]string{
+		"auth_date":  fmt.Sprintf("%d", authDate),
+		"first_name": "Test",
+		"id":         "12345",
+		"username":   "testuser",
+	}
+
+	var keys []string
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", key, data[key]))
+	}
+	dataCheckString := strings.Join(pairs, "\n")
+
+	secretKey := sha256.Sum256([]byte(botToken))
+	calculatedHash := hmac.New(sha256.New, secretKey[:])
+	calculatedHash.Write([]byte(dataCheckString))
+
+	values := url.Values{}
+	for key, value := range data {
+		values.Set(key, value)
+	}
+	values.Set("hash", hex.EncodeToString(calculatedHash.Sum(nil)))
+
+	return values.Encode()
+}
+
+func TestValidateLoginWidget_Success(t *testing.T) {
+	botToken := "test_bot_token_123456"
+	initData := createValidLoginWidgetData(botToken, time.Now().Unix())
+
+	user, err := ValidateAndParseLoginWidget(initData, botToken, 3600)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if user.ID != 12345 {
+		t.Errorf("Expected ID 12345, got: %d", user.ID)
+	}
+}
+
+func TestValidateLoginWidget_RespectsTTL(t *testing.T) {
+	botToken := "test_bot_token_123456"
+	// 2 дня назад: валидно при TTL 7 дней, истекло при TTL 1 час
+	initData := createValidLoginWidgetData(botToken, time.Now().Unix()-2*86400)
+
+	if err := ValidateLoginWidget(initData, botToken, 7*86400); err != nil {
+		t.Errorf("Expected no error with 7 day TTL, got: %v", err)
+	}
+	if err := ValidateLoginWidget(initData, botToken, 3600); err != ErrAuthDateExpired {
+		t.Errorf("Expected ErrAuthDateExpired with 1 hour TTL, got: %v", err)
+	}
+}
+
+func TestValidateLoginWidget_MiniAppSignatureRejected(t *testing.T) {
+	// Mini App подпись не должна проходить проверку Login Widget и наоборот
+	botToken := "test_bot_token_123456"
+	initData := createValidInitData(botToken)
+
+	if err := ValidateLoginWidget(initData, botToken, 3600); err != ErrInvalidHash {
+		t.Errorf("Expected ErrInvalidHash, got: %v", err)
+	}
+}
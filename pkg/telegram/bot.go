@@ -0,0 +1,40 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// GetMeResponse represents the Telegram API response for getMe
+type GetMeResponse struct {
+	OK          bool   `json:"ok"`
+	Description string `json:"description,omitempty"`
+	Result      struct {
+		ID       int64  `json:"id"`
+		Username string `json:"username"`
+	} `json:"result"`
+}
+
+// CheckBot проверяет доступность Telegram Bot API и валидность токена через getMe
+// Используется в readiness-проверке
+func CheckBot(ctx context.Context, botToken string) error {
+	apiURL := fmt.Sprintf("https://api.telegram.org/bot%s/getMe", botToken)
+
+	resp, err := httpGet(ctx, apiURL)
+	if err != nil {
+		return fmt.Errorf("telegram API unreachable: %w", err)
+	}
+	defer closeBody(resp)
+
+	var result GetMeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if !result.OK {
+		return fmt.Errorf("telegram API error: %s", result.Description)
+	}
+
+	return nil
+}
@@ -58,12 +58,10 @@ func (c *MembershipCache) Clear() {
 	}
 }
 
-// StartCleanupRoutine запускает фоновую очистку кэша
-func (c *MembershipCache) StartCleanupRoutine(interval time.Duration) {
-	ticker := time.NewTicker(interval)
-	go func() {
-		for range ticker.C {
-			c.Clear()
-		}
-	}()
+// Reset удаляет все записи, включая действующие (например, при смене группы)
+func (c *MembershipCache) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.data = make(map[int64]CacheEntry)
 }
@@ -0,0 +1,47 @@
+package telegram
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// apiTimeout ограничивает один вызов Bot API, даже если дедлайн контекста больше
+const apiTimeout = 10 * time.Second
+
+// httpClient - общий клиент всех вызовов Telegram API
+// Все запросы идут на api.telegram.org, поэтому keep-alive соединения переиспользуются
+// вместо TCP+TLS рукопожатия на каждый вызов
+var httpClient = &http.Client{
+	Timeout: apiTimeout,
+	Transport: &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          32,
+		MaxIdleConnsPerHost:   32, // По умолчанию 2 - при параллельных проверках членства соединения бы закрывались
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ResponseHeaderTimeout: apiTimeout,
+	},
+}
+
+// httpGet выполняет GET запрос с контекстом через общий клиент
+func httpGet(ctx context.Context, apiURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	return httpClient.Do(req)
+}
+
+// closeBody дочитывает и закрывает тело ответа: недочитанное соединение не возвращается в пул
+func closeBody(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	_ = resp.Body.Close()
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"time"
 )
 
@@ -22,12 +21,8 @@ type ChatMemberResponse struct {
 }
 
 // CheckUserInChat проверяет, является ли пользователь участником чата
-func CheckUserInChat(userID int64, chatID int64, botToken string) (bool, error) {
-	// Создаем HTTP клиент с таймаутом
-	client := &http.Client{
-		Timeout: 5 * time.Second,
-	}
-
+// ctx - контекст запроса; вызов отменяется при его отмене или истечении дедлайна
+func CheckUserInChat(ctx context.Context, userID int64, chatID int64, botToken string) (bool, error) {
 	url := fmt.Sprintf(
 		"https://api.telegram.org/bot%s/getChatMember?chat_id=%d&user_id=%d",
 		botToken,
@@ -35,20 +30,15 @@ func CheckUserInChat(userID int64, chatID int64, botToken string) (bool, error)
 		userID,
 	)
 
-	// Создаем запрос с контекстом
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	// Создаем запрос с контекстом (не дольше 5 секунд даже при большем дедлайне запроса)
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return false, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	resp, err := client.Do(req)
+	resp, err := httpGet(ctx, url)
 	if err != nil {
 		return false, fmt.Errorf("failed to check membership: %w", err)
 	}
-	defer resp.Body.Close()
+	defer closeBody(resp)
 
 	var result ChatMemberResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
@@ -1,11 +1,11 @@
 package telegram
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
-	"net/http"
+	"log/slog"
 )
 
 // UserProfilePhotos represents user profile photos response from Telegram API
@@ -39,15 +39,15 @@ type GetFileResponse struct {
 
 // GetUserProfilePhotoURL получает URL последней фотографии профиля пользователя из Telegram
 // Возвращает URL или пустую строку если фото нет
-func GetUserProfilePhotoURL(telegramUserID int64, botToken string) (string, error) {
+func GetUserProfilePhotoURL(ctx context.Context, telegramUserID int64, botToken string) (string, error) {
 	// Получаем список фотографий профиля (limit=1 для получения только последней)
 	apiURL := fmt.Sprintf("https://api.telegram.org/bot%s/getUserProfilePhotos?user_id=%d&limit=1", botToken, telegramUserID)
 
-	resp, err := http.Get(apiURL)
+	resp, err := httpGet(ctx, apiURL)
 	if err != nil {
 		return "", fmt.Errorf("failed to get user profile photos: %w", err)
 	}
-	defer resp.Body.Close()
+	defer closeBody(resp)
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -65,7 +65,7 @@ func GetUserProfilePhotoURL(telegramUserID int64, botToken string) (string, erro
 
 	// Если фотографий нет, возвращаем пустую строку
 	if photos.Result.TotalCount == 0 || len(photos.Result.Photos) == 0 {
-		log.Printf("DEBUG: User %d has no profile photos", telegramUserID)
+		slog.Debug("user has no profile photos", "telegram_id", telegramUserID)
 		return "", nil
 	}
 
@@ -80,7 +80,7 @@ func GetUserProfilePhotoURL(telegramUserID int64, botToken string) (string, erro
 	largestPhoto := photoSizes[len(photoSizes)-1]
 
 	// Получаем file_path для построения URL
-	fileInfo, err := getFileInfo(largestPhoto.FileID, botToken)
+	fileInfo, err := getFileInfo(ctx, largestPhoto.FileID, botToken)
 	if err != nil {
 		return "", fmt.Errorf("failed to get file info: %w", err)
 	}
@@ -88,19 +88,19 @@ func GetUserProfilePhotoURL(telegramUserID int64, botToken string) (string, erro
 	// Строим публичный URL для фотографии
 	photoURL := fmt.Sprintf("https://api.telegram.org/file/bot%s/%s", botToken, fileInfo.Result.FilePath)
 
-	log.Printf("DEBUG: Got profile photo URL for user %d: %s", telegramUserID, photoURL)
+	slog.Debug("got profile photo URL", "telegram_id", telegramUserID)
 	return photoURL, nil
 }
 
 // getFileInfo получает информацию о файле по file_id
-func getFileInfo(fileID, botToken string) (*GetFileResponse, error) {
+func getFileInfo(ctx context.Context, fileID, botToken string) (*GetFileResponse, error) {
 	apiURL := fmt.Sprintf("https://api.telegram.org/bot%s/getFile?file_id=%s", botToken, fileID)
 
-	resp, err := http.Get(apiURL)
+	resp, err := httpGet(ctx, apiURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get file info: %w", err)
 	}
-	defer resp.Body.Close()
+	defer closeBody(resp)
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -0,0 +1,83 @@
+package validator
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ErrInvalidBody - тело запроса не разбирается как JSON, и поле с ошибкой указать нельзя
+var ErrInvalidBody = errors.New("request body is not valid JSON")
+
+// Правила тегов binding, для которых есть шаблоны сообщений; прочие теги отдаются как invalid
+var bindingRules = map[string]bool{
+	RuleRequired: true,
+	RuleMin:      true,
+	RuleMax:      true,
+	RuleOneOf:    true,
+	RuleEmail:    true,
+}
+
+// FromBinding translates an error of gin binding into field errors
+// Ошибки тегов binding и типов значений становятся Errors с именами полей из json-тегов,
+// синтаксические ошибки JSON - ErrInvalidBody; прочие ошибки (например, превышение размера тела)
+// возвращаются без изменений
+func FromBinding(err error) error {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fieldErrs := make(Errors, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			fieldErrs = append(fieldErrs, bindingFieldError(fe))
+		}
+		return fieldErrs
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return Errors{{Field: typeErr.Field, Rule: RuleType, Param: jsonType(typeErr.Type)}}
+	}
+
+	var syntaxErr *json.SyntaxError
+	var timeErr *time.ParseError
+	switch {
+	case errors.As(err, &syntaxErr), errors.As(err, &timeErr), typeErr != nil,
+		errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return ErrInvalidBody
+	}
+	return err
+}
+
+func bindingFieldError(fe validator.FieldError) FieldError {
+	if !bindingRules[fe.Tag()] {
+		return FieldError{Field: fe.Field(), Rule: RuleInvalid}
+	}
+	return FieldError{Field: fe.Field(), Rule: fe.Tag(), Param: fe.Param()}
+}
+
+// jsonType называет тип JSON, которого ожидало поле
+func jsonType(t reflect.Type) string {
+	if t == nil {
+		return ""
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
@@ -0,0 +1,89 @@
+package validator
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/space/backend/pkg/apperror"
+)
+
+type bindingRequest struct {
+	RoomID uint   `json:"room_id" binding:"required"`
+	Rating int    `json:"rating" binding:"required,min=1,max=5"`
+	Title  string `json:"title"`
+}
+
+func newBindingValidator() *validator.Validate {
+	v := validator.New()
+	v.SetTagName("binding")
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	})
+	return v
+}
+
+func TestFromBinding_ValidationErrors(t *testing.T) {
+	err := newBindingValidator().Struct(bindingRequest{Rating: 7})
+
+	var fieldErrs Errors
+	if !errors.As(FromBinding(err), &fieldErrs) || len(fieldErrs) != 2 {
+		t.Fatalf("Expected two field errors, got: %v", FromBinding(err))
+	}
+	if fieldErrs[0].Field != "room_id" || fieldErrs[0].Rule != RuleRequired {
+		t.Errorf("Expected required room_id, got: %+v", fieldErrs[0])
+	}
+	if fieldErrs[1].Field != "rating" || fieldErrs[1].Rule != RuleMax || fieldErrs[1].Param != "5" {
+		t.Errorf("Expected rating at most 5, got: %+v", fieldErrs[1])
+	}
+}
+
+func TestFromBinding_DecodeErrors(t *testing.T) {
+	var req bindingRequest
+	err := json.Unmarshal([]byte(`{"room_id": "seven"}`), &req)
+
+	var fieldErrs Errors
+	if !errors.As(FromBinding(err), &fieldErrs) || fieldErrs[0].Field != "room_id" ||
+		fieldErrs[0].Rule != RuleType || fieldErrs[0].Param != "number" {
+		t.Errorf("Expected room_id to be a number, got: %v", FromBinding(err))
+	}
+
+	for _, body := range []string{`{"room_id": 1`, `{"room_id": }`, ``} {
+		err := json.NewDecoder(strings.NewReader(body)).Decode(&req)
+		if !errors.Is(FromBinding(err), ErrInvalidBody) {
+			t.Errorf("Expected ErrInvalidBody for %q, got: %v", body, FromBinding(err))
+		}
+	}
+
+	tooLarge := &http.MaxBytesError{Limit: 10}
+	if got := FromBinding(tooLarge); got != tooLarge {
+		t.Errorf("Expected other errors unchanged, got: %v", got)
+	}
+	if !errors.Is(FromBinding(io.ErrUnexpectedEOF), ErrInvalidBody) {
+		t.Error("Expected a truncated body to be ErrInvalidBody")
+	}
+}
+
+func TestErrors_Add(t *testing.T) {
+	errPast := apperror.BadRequest("past_booking", "cannot create booking in the past")
+
+	var fieldErrs Errors
+	fieldErrs.Add("start_time", errPast)
+	fieldErrs.Add("end_time", errors.New("end is too late"))
+	err := fieldErrs.Err()
+
+	if !errors.Is(err, errPast) {
+		t.Error("Expected the domain error to match through field errors")
+	}
+	if fieldErrs[0].Rule != "past_booking" || fieldErrs[0].Error() != "cannot create booking in the past" {
+		t.Errorf("Expected the rule and message of the domain error, got: %+v", fieldErrs[0])
+	}
+	if fieldErrs[1].Rule != RuleInvalid {
+		t.Errorf("Expected invalid rule for a plain error, got: %+v", fieldErrs[1])
+	}
+}
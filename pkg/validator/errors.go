@@ -0,0 +1,134 @@
+package validator
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/space/backend/pkg/apperror"
+)
+
+// Правила полей - совпадают с тегами binding, чтобы сообщения переводились теми же шаблонами
+const (
+	RuleRequired  = "required"
+	RuleMin       = "min"
+	RuleMax       = "max"
+	RuleMaxLength = "maxlength"
+	RuleOneOf     = "oneof"
+	RuleEmail     = "email"
+	RuleType      = "type"
+	RuleInvalid   = "invalid"
+)
+
+// FieldError is a validation error of one request field
+type FieldError struct {
+	Field string `json:"field"`           // Имя поля в JSON запроса
+	Rule  string `json:"rule"`            // Правило из списка выше или код доменной ошибки
+	Param string `json:"param,omitempty"` // Граница правила: длина, минимум, допустимые значения, тип JSON
+
+	err error // Доменная ошибка бизнес-проверки; её текст - сообщение поля
+}
+
+func (e FieldError) Error() string {
+	if e.err != nil {
+		return e.err.Error()
+	}
+	switch e.Rule {
+	case RuleRequired:
+		return "field '" + e.Field + "' is required"
+	case RuleMin:
+		return "field '" + e.Field + "' must be at least " + e.Param
+	case RuleMax:
+		return "field '" + e.Field + "' must be at most " + e.Param
+	case RuleMaxLength:
+		return "field '" + e.Field + "' must be at most " + e.Param + " characters"
+	case RuleOneOf:
+		return "field '" + e.Field + "' must be one of: " + e.Param
+	case RuleEmail:
+		return "field '" + e.Field + "' must be a valid email"
+	case RuleType:
+		return "field '" + e.Field + "' must be a " + e.Param
+	default:
+		return "field '" + e.Field + "' is invalid"
+	}
+}
+
+// Unwrap returns the domain error of a business check, so errors.Is still matches it
+func (e FieldError) Unwrap() error {
+	return e.err
+}
+
+// Errors collects field-level validation errors of a request
+// Сервис проверяет все поля и возвращает ошибки разом, а не первую найденную
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, fe := range e {
+		messages[i] = fe.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the errors of all fields
+func (e Errors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, fe := range e {
+		errs[i] = fe
+	}
+	return errs
+}
+
+// Err returns the collected errors, or nil if all fields are valid
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Text sanitizes a text field with SanitizeText; a required field must not be empty after sanitizing
+func (e *Errors) Text(field, value string, maxLength int, required bool) string {
+	text, err := SanitizeText(value, maxLength)
+	if err != nil {
+		e.add(field, err, maxLength)
+		return value
+	}
+	if required && text == "" {
+		e.add(field, ErrEmpty, maxLength)
+	}
+	return text
+}
+
+// Name validates a name field with ValidateName; an empty name is allowed
+func (e *Errors) Name(field, value string, maxLength int) string {
+	name := strings.TrimSpace(value)
+	if name == "" {
+		return name
+	}
+	if err := ValidateName(name, maxLength); err != nil {
+		e.add(field, err, maxLength)
+	}
+	return name
+}
+
+// Add records a failed business check of a field; the rule is the code of a domain error (pkg/apperror)
+func (e *Errors) Add(field string, err error) {
+	fe := FieldError{Field: field, Rule: RuleInvalid, err: err}
+	if appErr, ok := apperror.As(err); ok {
+		fe.Rule = appErr.Code
+	}
+	*e = append(*e, fe)
+}
+
+func (e *Errors) add(field string, err error, maxLength int) {
+	fe := FieldError{Field: field, Rule: RuleInvalid}
+	switch {
+	case errors.Is(err, ErrEmpty):
+		fe.Rule = RuleRequired
+	case errors.Is(err, ErrTooLong):
+		fe.Rule = RuleMaxLength
+		fe.Param = strconv.Itoa(maxLength)
+	}
+	*e = append(*e, fe)
+}
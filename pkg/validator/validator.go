@@ -4,6 +4,14 @@ import (
 	"errors"
 	"regexp"
 	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	ErrEmpty             = errors.New("value cannot be empty")
+	ErrTooLong           = errors.New("value is too long")
+	ErrInvalidCharacters = errors.New("value contains invalid characters")
 )
 
 var (
@@ -43,18 +51,18 @@ func EscapeLike(s string) string {
 	return s
 }
 
-// ValidateName проверяет, что имя содержит только допустимые символы
-func ValidateName(name string) error {
+// ValidateName проверяет, что имя содержит только допустимые символы и не длиннее maxLength символов
+func ValidateName(name string, maxLength int) error {
 	if name == "" {
-		return errors.New("name cannot be empty")
+		return ErrEmpty
 	}
 
-	if len(name) > 50 {
-		return errors.New("name is too long (max 50 characters)")
+	if utf8.RuneCountInString(name) > maxLength {
+		return ErrTooLong
 	}
 
 	if !nameRegex.MatchString(name) {
-		return errors.New("name contains invalid characters (only letters, spaces, hyphens allowed)")
+		return ErrInvalidCharacters
 	}
 
 	return nil
@@ -78,16 +86,20 @@ func ValidateUsername(username string) error {
 }
 
 // SanitizeText общая санитизация текстовых полей
+// Убирает пробелы по краям и управляющие символы, кроме переводов строк и табуляции.
+// Длина считается в символах; слишком длинный текст не обрезается, а отклоняется (ErrTooLong)
 func SanitizeText(text string, maxLength int) (string, error) {
-	// Убираем пробелы по краям
+	text = strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' || !unicode.IsControl(r) {
+			return r
+		}
+		return -1
+	}, text)
 	text = strings.TrimSpace(text)
 
-	// Ограничиваем длину
-	if len(text) > maxLength {
-		text = text[:maxLength]
+	if utf8.RuneCountInString(text) > maxLength {
+		return "", ErrTooLong
 	}
 
-	// Можно добавить дополнительную очистку здесь
-
 	return text, nil
-}
\ No newline at end of file
+}
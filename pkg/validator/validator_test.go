@@ -0,0 +1,39 @@
+package validator
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSanitizeText(t *testing.T) {
+	text, err := SanitizeText("  Планёрка\x00\x1b\n\tкоманды  ", 20)
+	if err != nil || text != "Планёрка\n\tкоманды" {
+		t.Errorf("Expected control characters and spaces removed, got: %q, %v", text, err)
+	}
+
+	// Длина - в символах: 8 кириллических символов занимают 16 байт
+	if _, err := SanitizeText("Планёрка", 8); err != nil {
+		t.Errorf("Expected 8 characters to fit, got: %v", err)
+	}
+	if _, err := SanitizeText("Планёрка", 7); !errors.Is(err, ErrTooLong) {
+		t.Errorf("Expected ErrTooLong, got: %v", err)
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name string
+		want error
+	}{
+		{"Анна-Мария", nil},
+		{"O'Brien", nil},
+		{"", ErrEmpty},
+		{"Anna1", ErrInvalidCharacters},
+		{"Ааааааааааа", ErrTooLong},
+	}
+	for _, tt := range tests {
+		if err := ValidateName(tt.name, 10); !errors.Is(err, tt.want) {
+			t.Errorf("ValidateName(%q): expected %v, got: %v", tt.name, tt.want, err)
+		}
+	}
+}